@@ -0,0 +1,149 @@
+/*
+Package client calls the menu planner API from Go, e.g. from tools and
+scripts. The API's operations, in operations.go, are generated from its
+OpenAPI description: run go generate after changing openapi.json.
+*/
+package client
+
+//go:generate go run ./gen operations.go
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strings"
+
+	"github.com/kieron-pivotal/menu-planner-app/problem"
+)
+
+// Client calls the API as one user. It keeps the session and CSRF
+// cookies in its HTTP client's cookie jar, and fetches a CSRF token
+// before its first request other than GET.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	csrfToken  string
+}
+
+// Error is a failed call, with the problem details the API answered with
+type Error struct {
+	problem.Problem
+}
+
+func (e *Error) Error() string {
+	if e.Detail != "" {
+		return fmt.Sprintf("%d %s: %s", e.Status, e.Code, e.Detail)
+	}
+	return fmt.Sprintf("%d %s", e.Status, e.Title)
+}
+
+// New returns a client of the API at baseURL, e.g.
+// "http://localhost:8080". httpClient must have a cookie jar; if it is
+// nil a client with one is made.
+func New(baseURL string, httpClient *http.Client) (*Client, error) {
+	if httpClient == nil {
+		jar, err := cookiejar.New(nil)
+		if err != nil {
+			return nil, fmt.Errorf("client: %w", err)
+		}
+		httpClient = &http.Client{Jar: jar}
+	}
+	if httpClient.Jar == nil {
+		return nil, fmt.Errorf("client: the HTTP client needs a cookie jar to keep the session")
+	}
+
+	return &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: httpClient,
+	}, nil
+}
+
+// do makes a request, sending body as JSON unless it is nil. If out is a
+// *[]byte it gets the raw response body, otherwise the body is decoded
+// into it as JSON unless it is nil. accept is the media types asked for.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, accept string, body, out interface{}) error {
+	u := c.baseURL + basePath + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	var reqBody io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("client: %s %s: %w", method, path, err)
+		}
+		reqBody = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, reqBody)
+	if err != nil {
+		return fmt.Errorf("client: %s %s: %w", method, path, err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+
+	if method != http.MethodGet {
+		if c.csrfToken == "" {
+			token, err := c.GetCSRFToken(ctx)
+			if err != nil {
+				return err
+			}
+			c.csrfToken = token.Token
+		}
+		req.Header.Set("X-CSRF-Token", c.csrfToken)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("client: %s %s: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return c.failure(resp)
+	}
+
+	switch out := out.(type) {
+	case nil:
+		return nil
+	case *[]byte:
+		if *out, err = io.ReadAll(resp.Body); err != nil {
+			return fmt.Errorf("client: %s %s: %w", method, path, err)
+		}
+	default:
+		if err = json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fmt.Errorf("client: %s %s: %w", method, path, err)
+		}
+	}
+	return nil
+}
+
+// failure returns the Error for a failed response. A rejected CSRF token
+// is forgotten, so the next call fetches a new one.
+func (c *Client) failure(resp *http.Response) error {
+	e := &Error{}
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), problem.ContentType) {
+		if err := json.NewDecoder(resp.Body).Decode(&e.Problem); err != nil {
+			return fmt.Errorf("client: %s: %w", resp.Status, err)
+		}
+	}
+	e.Status = resp.StatusCode
+	if e.Title == "" {
+		e.Title = http.StatusText(resp.StatusCode)
+	}
+
+	if e.Code == problem.InvalidCSRFToken {
+		c.csrfToken = ""
+	}
+	return e
+}
@@ -0,0 +1,165 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/kieron-pivotal/menu-planner-app/client"
+	"github.com/kieron-pivotal/menu-planner-app/models"
+	"github.com/kieron-pivotal/menu-planner-app/openapi"
+	"github.com/kieron-pivotal/menu-planner-app/problem"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Client", func() {
+	var (
+		server   *httptest.Server
+		mux      *http.ServeMux
+		requests []*http.Request
+		c        *client.Client
+		ctx      context.Context
+	)
+
+	BeforeEach(func() {
+		requests = nil
+		mux = http.NewServeMux()
+		mux.HandleFunc("/api/v1/csrf", func(w http.ResponseWriter, r *http.Request) {
+			http.SetCookie(w, &http.Cookie{Name: "_csrf", Value: "csrf-token", Path: "/"})
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"token": "csrf-token"}`))
+		})
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests = append(requests, r)
+			mux.ServeHTTP(w, r)
+		}))
+
+		var err error
+		c, err = client.New(server.URL+"/", nil)
+		Expect(err).NotTo(HaveOccurred())
+		ctx = context.Background()
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("is generated from the current OpenAPI description", func() {
+		doc, err := openapi.Load()
+		Expect(err).NotTo(HaveOccurred())
+		src, err := doc.GoClient("client")
+		Expect(err).NotTo(HaveOccurred())
+
+		generated, err := ioutil.ReadFile("operations.go")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(generated)).To(Equal(string(src)), "run go generate ./client")
+	})
+
+	It("needs a cookie jar to keep the session", func() {
+		_, err := client.New(server.URL, &http.Client{})
+		Expect(err).To(MatchError(ContainSubstring("cookie jar")))
+	})
+
+	It("sends path and query parameters and decodes JSON", func() {
+		mux.HandleFunc("/api/v1/shopping-list", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"categories": [{"name": "Dairy", "items": [{"name": "milk", "quantity": 2, "unit": "l"}]}]}`))
+		})
+
+		list, err := c.GetShoppingList(ctx, client.GetShoppingListParams{Recipes: []int{3, 4, 3}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(list).To(Equal(models.ShoppingList{Categories: []models.ShoppingCategory{
+			{Name: "Dairy", Items: []models.ShoppingItem{{Name: "milk", Quantity: 2, Unit: "l"}}},
+		}}))
+		Expect(requests[0].URL.Query().Get("recipes")).To(Equal("3,4,3"))
+		Expect(requests[0].Header.Get("Accept")).To(Equal("application/json"))
+	})
+
+	It("returns other media types raw", func() {
+		mux.HandleFunc("/api/v1/shopping-list", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", r.Header.Get("Accept"))
+			w.Write([]byte("- [ ] milk\n"))
+		})
+
+		list, err := c.GetShoppingListAs(ctx, client.GetShoppingListParams{Recipes: []int{3}}, "text/markdown")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(list)).To(Equal("- [ ] milk\n"))
+		Expect(requests[0].Header.Get("Accept")).To(Equal("text/markdown"))
+	})
+
+	It("fetches a CSRF token before its first request other than GET", func() {
+		mux.HandleFunc("/api/v1/recipes/12/rating", func(w http.ResponseWriter, r *http.Request) {
+			cookie, err := r.Cookie("_csrf")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cookie.Value).To(Equal("csrf-token"))
+			Expect(r.Header.Get("X-CSRF-Token")).To(Equal("csrf-token"))
+			Expect(r.Header.Get("Content-Type")).To(Equal("application/json"))
+
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"rating": 4, "favourite": true}`))
+		})
+
+		for i := 0; i < 2; i++ {
+			rating, err := c.RateRecipe(ctx, 12, models.Rating{Rating: 4, Favourite: true})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rating).To(Equal(models.Rating{Rating: 4, Favourite: true}))
+		}
+
+		paths := []string{}
+		for _, r := range requests {
+			paths = append(paths, r.Method+" "+r.URL.Path)
+		}
+		Expect(paths).To(Equal([]string{"GET /api/v1/csrf", "PUT /api/v1/recipes/12/rating", "PUT /api/v1/recipes/12/rating"}))
+	})
+
+	It("returns problem details as errors", func() {
+		mux.HandleFunc("/api/v1/recipes", func(w http.ResponseWriter, r *http.Request) {
+			problem.WriteConflict(w, problem.DuplicateRecipe, "you already have it", []problem.Conflict{{ID: 3, Name: "Soup"}})
+		})
+
+		_, err := c.NewRecipe(ctx, client.NewRecipeParams{}, models.Recipe{Name: "soup"})
+		var clientErr *client.Error
+		Expect(errors.As(err, &clientErr)).To(BeTrue())
+		Expect(clientErr.Status).To(Equal(http.StatusConflict))
+		Expect(clientErr.Code).To(Equal(problem.DuplicateRecipe))
+		Expect(clientErr.Conflicts).To(Equal([]problem.Conflict{{ID: 3, Name: "Soup"}}))
+		Expect(err).To(MatchError("409 duplicate-recipe: you already have it"))
+	})
+
+	It("says what failed without problem details", func() {
+		_, err := c.WhoAmI(ctx)
+		var clientErr *client.Error
+		Expect(errors.As(err, &clientErr)).To(BeTrue())
+		Expect(clientErr.Status).To(Equal(http.StatusNotFound))
+		Expect(err).To(MatchError("404 Not Found"))
+	})
+
+	It("fetches a new CSRF token after one is rejected", func() {
+		rejected := false
+		mux.HandleFunc("/api/v1/logout", func(w http.ResponseWriter, r *http.Request) {
+			if !rejected {
+				rejected = true
+				problem.Write(w, http.StatusForbidden, problem.InvalidCSRFToken, "")
+				return
+			}
+			w.Write([]byte("logged out"))
+		})
+
+		_, err := c.Logout(ctx)
+		Expect(err).To(HaveOccurred())
+		out, err := c.Logout(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(out)).To(Equal("logged out"))
+
+		csrfFetches := 0
+		for _, r := range requests {
+			if r.URL.Path == "/api/v1/csrf" {
+				csrfFetches++
+			}
+		}
+		Expect(csrfFetches).To(Equal(2))
+	})
+})
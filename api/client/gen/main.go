@@ -0,0 +1,29 @@
+// Command gen writes the client's operations from the OpenAPI description
+package main
+
+import (
+	"log"
+	"os"
+
+	"github.com/kieron-pivotal/menu-planner-app/openapi"
+)
+
+func main() {
+	if len(os.Args) != 2 {
+		log.Fatal("usage: gen <output file>")
+	}
+
+	doc, err := openapi.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	src, err := doc.GoClient("client")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err = os.WriteFile(os.Args[1], src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
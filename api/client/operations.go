@@ -0,0 +1,430 @@
+// Code generated from the OpenAPI description by openapi.GoClient; DO NOT EDIT.
+
+package client
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/kieron-pivotal/menu-planner-app/models"
+)
+
+// basePath is the prefix of every operation's path
+const basePath = "/api/v1"
+
+// ApplyTemplate is the ApplyTemplate schema
+type ApplyTemplate struct {
+	TemplateID int `json:"templateId"`
+}
+
+// CSRFToken is the CSRFToken schema
+type CSRFToken struct {
+	Token string `json:"token"`
+}
+
+// CalendarToken is the CalendarToken schema
+type CalendarToken struct {
+	// Where the feed is served, e.g. /calendar/<token>.ics
+	Path  string `json:"path"`
+	Token string `json:"token"`
+}
+
+// Eaten is the Eaten schema
+type Eaten struct {
+	Portions int `json:"portions,omitempty"`
+}
+
+// GoogleLogin is the GoogleLogin schema
+type GoogleLogin struct {
+	IDToken string `json:"idToken"`
+}
+
+// Identity is the Identity schema: who is logged in
+type Identity struct {
+	Name string `json:"name"`
+}
+
+// Merge is the Merge schema
+type Merge struct {
+	// The ids of the recipes merged in
+	Duplicates []int `json:"duplicates"`
+}
+
+// AuthGoogle calls POST /authGoogle: log in with a Google ID token
+func (c *Client) AuthGoogle(ctx context.Context, body GoogleLogin) (Identity, error) {
+	var out Identity
+	err := c.do(ctx, "POST", "/authGoogle", nil, "application/json", body, &out)
+	return out, err
+}
+
+// GetCalendarFeed calls GET /calendar/{token}.ics: the user's meal plans for this week and the next three as an iCalendar feed
+func (c *Client) GetCalendarFeed(ctx context.Context, token string) ([]byte, error) {
+	var out []byte
+	err := c.do(ctx, "GET", "/calendar/"+url.PathEscape(token)+".ics", nil, "text/calendar", nil, &out)
+	return out, err
+}
+
+// GetCSRFToken calls GET /csrf: get a token for the X-CSRF-Token header
+func (c *Client) GetCSRFToken(ctx context.Context) (CSRFToken, error) {
+	var out CSRFToken
+	err := c.do(ctx, "GET", "/csrf", nil, "application/json", nil, &out)
+	return out, err
+}
+
+// GetLeftovers calls GET /leftovers: cooks with portions left
+func (c *Client) GetLeftovers(ctx context.Context) ([]models.Cooked, error) {
+	var out []models.Cooked
+	err := c.do(ctx, "GET", "/leftovers", nil, "application/json", nil, &out)
+	return out, err
+}
+
+// EatLeftovers calls POST /leftovers/{id}/eaten: eat portions of leftovers
+func (c *Client) EatLeftovers(ctx context.Context, id int, body Eaten) (models.Cooked, error) {
+	var out models.Cooked
+	err := c.do(ctx, "POST", "/leftovers/"+strconv.Itoa(id)+"/eaten", nil, "application/json", body, &out)
+	return out, err
+}
+
+// Logout calls POST /logout: log out of this session
+func (c *Client) Logout(ctx context.Context) ([]byte, error) {
+	var out []byte
+	err := c.do(ctx, "POST", "/logout", nil, "text/plain", nil, &out)
+	return out, err
+}
+
+// GetSpec calls GET /openapi.json: this OpenAPI description
+func (c *Client) GetSpec(ctx context.Context) (map[string]interface{}, error) {
+	var out map[string]interface{}
+	err := c.do(ctx, "GET", "/openapi.json", nil, "application/json", nil, &out)
+	return out, err
+}
+
+// ListPlanRules calls GET /plan-rules: the user's recurring plan rules
+func (c *Client) ListPlanRules(ctx context.Context) ([]models.PlanRule, error) {
+	var out []models.PlanRule
+	err := c.do(ctx, "GET", "/plan-rules", nil, "application/json", nil, &out)
+	return out, err
+}
+
+// SavePlanRule calls PUT /plan-rules: plan a recipe for a meal every week, replacing any rule for the meal
+func (c *Client) SavePlanRule(ctx context.Context, body models.PlanRule) (models.PlanRule, error) {
+	var out models.PlanRule
+	err := c.do(ctx, "PUT", "/plan-rules", nil, "application/json", body, &out)
+	return out, err
+}
+
+// DeletePlanRule calls DELETE /plan-rules/{id}: delete a plan rule
+func (c *Client) DeletePlanRule(ctx context.Context, id int) error {
+	return c.do(ctx, "DELETE", "/plan-rules/"+strconv.Itoa(id), nil, "", nil, nil)
+}
+
+// ListPlanTemplates calls GET /plan-templates: the user's plan templates
+func (c *Client) ListPlanTemplates(ctx context.Context) ([]models.PlanTemplate, error) {
+	var out []models.PlanTemplate
+	err := c.do(ctx, "GET", "/plan-templates", nil, "application/json", nil, &out)
+	return out, err
+}
+
+// NewPlanTemplate calls POST /plan-templates: save a named week layout
+func (c *Client) NewPlanTemplate(ctx context.Context, body models.PlanTemplate) (models.PlanTemplate, error) {
+	var out models.PlanTemplate
+	err := c.do(ctx, "POST", "/plan-templates", nil, "application/json", body, &out)
+	return out, err
+}
+
+// DeletePlanTemplate calls DELETE /plan-templates/{id}: delete a plan template
+func (c *Client) DeletePlanTemplate(ctx context.Context, id int) error {
+	return c.do(ctx, "DELETE", "/plan-templates/"+strconv.Itoa(id), nil, "", nil, nil)
+}
+
+// GetRecipesPrintoutParams are the query parameters of GetRecipesPrintout. Zero values are left out.
+type GetRecipesPrintoutParams struct {
+	// Comma separated recipe ids. A recipe listed twice is used twice.
+	Recipes []int
+	// The printout's title; "Meal plan" if not given
+	Title string
+}
+
+func (p GetRecipesPrintoutParams) values() url.Values {
+	q := url.Values{}
+	if len(p.Recipes) > 0 {
+		values := []string{}
+		for _, v := range p.Recipes {
+			values = append(values, strconv.Itoa(v))
+		}
+		q.Set("recipes", strings.Join(values, ","))
+	}
+	if p.Title != "" {
+		q.Set("title", p.Title)
+	}
+	return q
+}
+
+// GetRecipesPrintout calls GET /plan.pdf: print a list of recipes
+func (c *Client) GetRecipesPrintout(ctx context.Context, params GetRecipesPrintoutParams) ([]byte, error) {
+	var out []byte
+	err := c.do(ctx, "GET", "/plan.pdf", params.values(), "application/pdf", nil, &out)
+	return out, err
+}
+
+// GetPlan calls GET /plans/{week}: the user's plan for a week
+func (c *Client) GetPlan(ctx context.Context, week string) (models.Plan, error) {
+	var out models.Plan
+	err := c.do(ctx, "GET", "/plans/"+url.PathEscape(week), nil, "application/json", nil, &out)
+	return out, err
+}
+
+// SavePlan calls PUT /plans/{week}: replace the user's plan for a week
+func (c *Client) SavePlan(ctx context.Context, week string, body models.Plan) (models.Plan, error) {
+	var out models.Plan
+	err := c.do(ctx, "PUT", "/plans/"+url.PathEscape(week), nil, "application/json", body, &out)
+	return out, err
+}
+
+// GetPlanPrintout calls GET /plans/{week}/print.pdf: print a week's plan
+func (c *Client) GetPlanPrintout(ctx context.Context, week string) ([]byte, error) {
+	var out []byte
+	err := c.do(ctx, "GET", "/plans/"+url.PathEscape(week)+"/print.pdf", nil, "application/pdf", nil, &out)
+	return out, err
+}
+
+// ApplyPlanRules calls POST /plans/{week}/rules: fill a week's empty meals from the user's recurring rules
+func (c *Client) ApplyPlanRules(ctx context.Context, week string) (models.PlanChange, error) {
+	var out models.PlanChange
+	err := c.do(ctx, "POST", "/plans/"+url.PathEscape(week)+"/rules", nil, "application/json", nil, &out)
+	return out, err
+}
+
+// GetPlanShoppingList calls GET /plans/{week}/shopping-list: what to buy for a week's plan
+func (c *Client) GetPlanShoppingList(ctx context.Context, week string) (models.ShoppingList, error) {
+	var out models.ShoppingList
+	err := c.do(ctx, "GET", "/plans/"+url.PathEscape(week)+"/shopping-list", nil, "application/json", nil, &out)
+	return out, err
+}
+
+// GetPlanShoppingListAs is GetPlanShoppingList returning the body as accept, one of text/csv, text/html, text/markdown, text/plain
+func (c *Client) GetPlanShoppingListAs(ctx context.Context, week string, accept string) ([]byte, error) {
+	var out []byte
+	err := c.do(ctx, "GET", "/plans/"+url.PathEscape(week)+"/shopping-list", nil, accept, nil, &out)
+	return out, err
+}
+
+// ApplyPlanTemplate calls POST /plans/{week}/template: fill a week's empty meals from a template
+func (c *Client) ApplyPlanTemplate(ctx context.Context, week string, body ApplyTemplate) (models.PlanChange, error) {
+	var out models.PlanChange
+	err := c.do(ctx, "POST", "/plans/"+url.PathEscape(week)+"/template", nil, "application/json", body, &out)
+	return out, err
+}
+
+// ListPrices calls GET /prices: the user's ingredient prices
+func (c *Client) ListPrices(ctx context.Context) ([]models.Price, error) {
+	var out []models.Price
+	err := c.do(ctx, "GET", "/prices", nil, "application/json", nil, &out)
+	return out, err
+}
+
+// SavePrice calls PUT /prices: save what an ingredient costs at a store, replacing any earlier price
+func (c *Client) SavePrice(ctx context.Context, body models.Price) (models.Price, error) {
+	var out models.Price
+	err := c.do(ctx, "PUT", "/prices", nil, "application/json", body, &out)
+	return out, err
+}
+
+// DeletePrice calls DELETE /prices/{id}: delete a price
+func (c *Client) DeletePrice(ctx context.Context, id int) error {
+	return c.do(ctx, "DELETE", "/prices/"+strconv.Itoa(id), nil, "", nil, nil)
+}
+
+// GetBudget calls GET /profile/budget: what the user means to spend on food each week
+func (c *Client) GetBudget(ctx context.Context) (models.Budget, error) {
+	var out models.Budget
+	err := c.do(ctx, "GET", "/profile/budget", nil, "application/json", nil, &out)
+	return out, err
+}
+
+// SaveBudget calls PUT /profile/budget: set the user's weekly budget. A budget of 0 clears it
+func (c *Client) SaveBudget(ctx context.Context, body models.Budget) (models.Budget, error) {
+	var out models.Budget
+	err := c.do(ctx, "PUT", "/profile/budget", nil, "application/json", body, &out)
+	return out, err
+}
+
+// IssueCalendarToken calls POST /profile/calendar-token: issue a calendar feed token, revoking any earlier one
+func (c *Client) IssueCalendarToken(ctx context.Context) (CalendarToken, error) {
+	var out CalendarToken
+	err := c.do(ctx, "POST", "/profile/calendar-token", nil, "application/json", nil, &out)
+	return out, err
+}
+
+// RevokeCalendarToken calls DELETE /profile/calendar-token: revoke the calendar feed token
+func (c *Client) RevokeCalendarToken(ctx context.Context) error {
+	return c.do(ctx, "DELETE", "/profile/calendar-token", nil, "", nil, nil)
+}
+
+// GetRecipesParams are the query parameters of GetRecipes. Zero values are left out.
+type GetRecipesParams struct {
+	// lastCooked puts the most recently cooked first and never cooked last; favourites puts the user's favourites first, then the best rated
+	Sort string
+	// Leave out recipes anyone has rated lower
+	MinRating int
+}
+
+func (p GetRecipesParams) values() url.Values {
+	q := url.Values{}
+	if p.Sort != "" {
+		q.Set("sort", p.Sort)
+	}
+	if p.MinRating != 0 {
+		q.Set("minRating", strconv.Itoa(p.MinRating))
+	}
+	return q
+}
+
+// GetRecipes calls GET /recipes: list the user's recipes
+func (c *Client) GetRecipes(ctx context.Context, params GetRecipesParams) ([]models.Recipe, error) {
+	var out []models.Recipe
+	err := c.do(ctx, "GET", "/recipes", params.values(), "application/json", nil, &out)
+	return out, err
+}
+
+// NewRecipeParams are the query parameters of NewRecipe. Zero values are left out.
+type NewRecipeParams struct {
+	// Save the recipe even if the user has recipes with names like it
+	AllowSimilar bool
+}
+
+func (p NewRecipeParams) values() url.Values {
+	q := url.Values{}
+	if p.AllowSimilar {
+		q.Set("allowSimilar", "true")
+	}
+	return q
+}
+
+// NewRecipe calls POST /recipes: save a recipe
+func (c *Client) NewRecipe(ctx context.Context, params NewRecipeParams, body models.Recipe) (models.Recipe, error) {
+	var out models.Recipe
+	err := c.do(ctx, "POST", "/recipes", params.values(), "application/json", body, &out)
+	return out, err
+}
+
+// GetSuggestionsParams are the query parameters of GetSuggestions. Zero values are left out.
+type GetSuggestionsParams struct {
+	// How many days counts as a while; 28 if not given
+	Days int
+}
+
+func (p GetSuggestionsParams) values() url.Values {
+	q := url.Values{}
+	if p.Days != 0 {
+		q.Set("days", strconv.Itoa(p.Days))
+	}
+	return q
+}
+
+// GetSuggestions calls GET /recipes/suggestions: recipes the user hasn't cooked in a while
+func (c *Client) GetSuggestions(ctx context.Context, params GetSuggestionsParams) ([]models.Recipe, error) {
+	var out []models.Recipe
+	err := c.do(ctx, "GET", "/recipes/suggestions", params.values(), "application/json", nil, &out)
+	return out, err
+}
+
+// GetRecipe calls GET /recipes/{id}: A recipe with its method and estimates of its nutrition and cost
+func (c *Client) GetRecipe(ctx context.Context, id int) (models.Recipe, error) {
+	var out models.Recipe
+	err := c.do(ctx, "GET", "/recipes/"+strconv.Itoa(id), nil, "application/json", nil, &out)
+	return out, err
+}
+
+// GetRecipeCard calls GET /recipes/{id}/card.pdf: print a recipe
+func (c *Client) GetRecipeCard(ctx context.Context, id int) ([]byte, error) {
+	var out []byte
+	err := c.do(ctx, "GET", "/recipes/"+strconv.Itoa(id)+"/card.pdf", nil, "application/pdf", nil, &out)
+	return out, err
+}
+
+// RecordCooked calls POST /recipes/{id}/cooked: log cooking a recipe
+func (c *Client) RecordCooked(ctx context.Context, id int, body models.Cooked) (models.Cooked, error) {
+	var out models.Cooked
+	err := c.do(ctx, "POST", "/recipes/"+strconv.Itoa(id)+"/cooked", nil, "application/json", body, &out)
+	return out, err
+}
+
+// GetCookHistory calls GET /recipes/{id}/history: when a recipe was cooked
+func (c *Client) GetCookHistory(ctx context.Context, id int) ([]models.Cooked, error) {
+	var out []models.Cooked
+	err := c.do(ctx, "GET", "/recipes/"+strconv.Itoa(id)+"/history", nil, "application/json", nil, &out)
+	return out, err
+}
+
+// MergeRecipes calls POST /recipes/{id}/merge: merge duplicates into a recipe
+func (c *Client) MergeRecipes(ctx context.Context, id int, body Merge) (models.Recipe, error) {
+	var out models.Recipe
+	err := c.do(ctx, "POST", "/recipes/"+strconv.Itoa(id)+"/merge", nil, "application/json", body, &out)
+	return out, err
+}
+
+// RateRecipe calls PUT /recipes/{id}/rating: rate a recipe and mark it as a favourite
+func (c *Client) RateRecipe(ctx context.Context, id int, body models.Rating) (models.Rating, error) {
+	var out models.Rating
+	err := c.do(ctx, "PUT", "/recipes/"+strconv.Itoa(id)+"/rating", nil, "application/json", body, &out)
+	return out, err
+}
+
+// ListSessions calls GET /sessions: the user's logged in sessions
+func (c *Client) ListSessions(ctx context.Context) ([]models.Session, error) {
+	var out []models.Session
+	err := c.do(ctx, "GET", "/sessions", nil, "application/json", nil, &out)
+	return out, err
+}
+
+// RevokeAllSessions calls DELETE /sessions: log out everywhere else
+func (c *Client) RevokeAllSessions(ctx context.Context) error {
+	return c.do(ctx, "DELETE", "/sessions", nil, "", nil, nil)
+}
+
+// RevokeSession calls DELETE /sessions/{id}: log a session out
+func (c *Client) RevokeSession(ctx context.Context, id string) error {
+	return c.do(ctx, "DELETE", "/sessions/"+url.PathEscape(id), nil, "", nil, nil)
+}
+
+// GetShoppingListParams are the query parameters of GetShoppingList. Zero values are left out.
+type GetShoppingListParams struct {
+	// Comma separated recipe ids. A recipe listed twice is used twice.
+	Recipes []int
+}
+
+func (p GetShoppingListParams) values() url.Values {
+	q := url.Values{}
+	if len(p.Recipes) > 0 {
+		values := []string{}
+		for _, v := range p.Recipes {
+			values = append(values, strconv.Itoa(v))
+		}
+		q.Set("recipes", strings.Join(values, ","))
+	}
+	return q
+}
+
+// GetShoppingList calls GET /shopping-list: what to buy to cook some recipes
+func (c *Client) GetShoppingList(ctx context.Context, params GetShoppingListParams) (models.ShoppingList, error) {
+	var out models.ShoppingList
+	err := c.do(ctx, "GET", "/shopping-list", params.values(), "application/json", nil, &out)
+	return out, err
+}
+
+// GetShoppingListAs is GetShoppingList returning the body as accept, one of text/csv, text/html, text/markdown, text/plain
+func (c *Client) GetShoppingListAs(ctx context.Context, params GetShoppingListParams, accept string) ([]byte, error) {
+	var out []byte
+	err := c.do(ctx, "GET", "/shopping-list", params.values(), accept, nil, &out)
+	return out, err
+}
+
+// WhoAmI calls GET /whoami: the logged in user
+func (c *Client) WhoAmI(ctx context.Context) (Identity, error) {
+	var out Identity
+	err := c.do(ctx, "GET", "/whoami", nil, "application/json", nil, &out)
+	return out, err
+}
@@ -0,0 +1,322 @@
+/* Package config loads the server's settings from a file, the environment and flags */
+package config
+
+import (
+	"encoding/base64"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/securecookie"
+	"gopkg.in/yaml.v2"
+)
+
+const redacted = "<redacted>"
+
+type Config struct {
+	Host           string `yaml:"host"`
+	Port           int    `yaml:"port"`
+	WebURI         string `yaml:"webURI"`
+	GoogleAudience string `yaml:"googleAudience"`
+	// DBDriver is postgres or sqlite. For sqlite, DBConnStr is the path of
+	// the database file.
+	DBDriver  string `yaml:"dbDriver"`
+	DBConnStr string `yaml:"dbConnStr"`
+
+	// Session keys are base64 encoded. Random keys are used if they are not
+	// set, so sessions won't survive a restart.
+	SessionSignKey    string `yaml:"sessionSignKey"`
+	SessionEncryptKey string `yaml:"sessionEncryptKey"`
+
+	CORSAllowedOrigins []string `yaml:"corsAllowedOrigins"`
+	CORSPublicOrigins  []string `yaml:"corsPublicOrigins"`
+	// CORSAllowedMethods are advertised on preflight requests instead of
+	// each route's own methods if set
+	CORSAllowedMethods []string `yaml:"corsAllowedMethods"`
+	// CORSAllowedHeaders replace the request headers the web app needs, so
+	// should keep Content-Type and X-CSRF-Token
+	CORSAllowedHeaders []string      `yaml:"corsAllowedHeaders"`
+	CORSMaxAge         time.Duration `yaml:"corsMaxAge"`
+
+	MigrateOnStart bool `yaml:"migrateOnStart"`
+
+	// NutrientData is a CSV file of nutrients per 100 g of food, used
+	// instead of the small built-in table
+	NutrientData string `yaml:"nutrientData"`
+
+	// Demo keeps all data in memory, seeded with sample recipes, instead of
+	// using a database
+	Demo bool `yaml:"demo"`
+
+	ConfigFile  string `yaml:"-"`
+	PrintConfig bool   `yaml:"-"`
+	// Args are the arguments left after the flags, e.g. a subcommand
+	Args []string `yaml:"-"`
+}
+
+// setting describes one config value and where it can come from
+type setting struct {
+	flag     string
+	env      string
+	usage    string
+	required bool
+	// demoOptional settings are not required in demo mode
+	demoOptional bool
+	secret       bool
+	value        func(c *Config) interface{}
+}
+
+var settings = []setting{
+	{flag: "host", env: "HOST", usage: "interface to listen on",
+		value: func(c *Config) interface{} { return &c.Host }},
+	{flag: "port", env: "PORT", usage: "port to listen on",
+		value: func(c *Config) interface{} { return &c.Port }},
+	{flag: "web-uri", env: "WEB_URI", usage: "origin of the web app",
+		value: func(c *Config) interface{} { return &c.WebURI }},
+	{flag: "google-audience", env: "GOOGLE_AUDIENCE", usage: "google oauth client id of the web app", required: true,
+		value: func(c *Config) interface{} { return &c.GoogleAudience }},
+	{flag: "db-driver", env: "DB_DRIVER", usage: "database to use, postgres or sqlite",
+		value: func(c *Config) interface{} { return &c.DBDriver }},
+	{flag: "db-conn-str", env: "DB_CONN_STR", usage: "postgres connection string or sqlite file path", required: true, demoOptional: true, secret: true,
+		value: func(c *Config) interface{} { return &c.DBConnStr }},
+	{flag: "session-sign-key", env: "SESSION_SIGN_KEY", usage: "base64 session signing key", secret: true,
+		value: func(c *Config) interface{} { return &c.SessionSignKey }},
+	{flag: "session-encrypt-key", env: "SESSION_ENCRYPT_KEY", usage: "base64 session encryption key", secret: true,
+		value: func(c *Config) interface{} { return &c.SessionEncryptKey }},
+	{flag: "cors-allowed-origins", env: "CORS_ALLOWED_ORIGINS", usage: "comma separated origins allowed credentialed requests",
+		value: func(c *Config) interface{} { return &c.CORSAllowedOrigins }},
+	{flag: "cors-public-origins", env: "CORS_PUBLIC_ORIGINS", usage: "comma separated origins allowed credential-less requests",
+		value: func(c *Config) interface{} { return &c.CORSPublicOrigins }},
+	{flag: "cors-allowed-methods", env: "CORS_ALLOWED_METHODS", usage: "comma separated methods allowed cross-origin, instead of each route's own",
+		value: func(c *Config) interface{} { return &c.CORSAllowedMethods }},
+	{flag: "cors-allowed-headers", env: "CORS_ALLOWED_HEADERS", usage: "comma separated request headers allowed cross-origin",
+		value: func(c *Config) interface{} { return &c.CORSAllowedHeaders }},
+	{flag: "cors-max-age", env: "CORS_MAX_AGE", usage: "how long browsers may cache preflight responses",
+		value: func(c *Config) interface{} { return &c.CORSMaxAge }},
+	{flag: "migrate-on-start", env: "MIGRATE_ON_START", usage: "apply pending database migrations when the server starts",
+		value: func(c *Config) interface{} { return &c.MigrateOnStart }},
+	{flag: "nutrient-data", env: "NUTRIENT_DATA", usage: "CSV file of nutrients per 100 g of food, instead of the built-in table",
+		value: func(c *Config) interface{} { return &c.NutrientData }},
+	{flag: "demo", env: "DEMO", usage: "keep data in memory, seeded with sample recipes, instead of using a database",
+		value: func(c *Config) interface{} { return &c.Demo }},
+}
+
+// flagValue captures a flag's raw value so it can be applied after the file
+// and environment
+type flagValue struct {
+	value  string
+	isBool bool
+}
+
+func (f *flagValue) String() string     { return f.value }
+func (f *flagValue) Set(v string) error { f.value = v; return nil }
+func (f *flagValue) IsBoolFlag() bool   { return f.isBool }
+
+func defaults() Config {
+	return Config{
+		Host:               "localhost",
+		Port:               8080,
+		WebURI:             "http://localhost:3000",
+		DBDriver:           "postgres",
+		CORSAllowedHeaders: []string{"Content-Type", "X-CSRF-Token"},
+		CORSMaxAge:         10 * time.Minute,
+		MigrateOnStart:     true,
+	}
+}
+
+// Load builds the config from, in increasing order of precedence: defaults,
+// the YAML file named by --config or CONFIG_FILE, environment variables and
+// flags. The config is returned even when it fails validation, so that it
+// can still be printed.
+func Load(args []string, getenv func(string) string) (Config, error) {
+	c := defaults()
+
+	fs := flag.NewFlagSet("menu-planner", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	fs.StringVar(&c.ConfigFile, "config", getenv("CONFIG_FILE"), "path to a YAML config file")
+	fs.BoolVar(&c.PrintConfig, "print-config", false, "print the config with secrets redacted and exit")
+	flagValues := map[string]*flagValue{}
+	for _, s := range settings {
+		_, isBool := s.value(&c).(*bool)
+		flagValues[s.flag] = &flagValue{isBool: isBool}
+		fs.Var(flagValues[s.flag], s.flag, s.usage)
+	}
+
+	if err := fs.Parse(args); err != nil {
+		var usage strings.Builder
+		fs.SetOutput(&usage)
+		fs.PrintDefaults()
+		return c, fmt.Errorf("config: %w\n%s", err, usage.String())
+	}
+	c.Args = fs.Args()
+
+	if c.ConfigFile != "" {
+		if err := c.loadFile(c.ConfigFile); err != nil {
+			return c, err
+		}
+	}
+
+	for _, s := range settings {
+		if v := getenv(s.env); v != "" {
+			if err := set(s.value(&c), v); err != nil {
+				return c, fmt.Errorf("config: invalid %s: %w", s.env, err)
+			}
+		}
+	}
+
+	visited := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) { visited[f.Name] = true })
+	for _, s := range settings {
+		if visited[s.flag] {
+			if err := set(s.value(&c), flagValues[s.flag].value); err != nil {
+				return c, fmt.Errorf("config: invalid --%s: %w", s.flag, err)
+			}
+		}
+	}
+
+	return c, c.Validate()
+}
+
+// Validate reports every missing or invalid value at once
+func (c Config) Validate() error {
+	var missing, problems []string
+
+	for _, s := range settings {
+		if s.required && !(c.Demo && s.demoOptional) && isZero(s.value(&c)) {
+			missing = append(missing, fmt.Sprintf("%s (--%s)", s.env, s.flag))
+		}
+	}
+	if len(missing) > 0 {
+		problems = append(problems, "missing "+strings.Join(missing, ", "))
+	}
+
+	if c.Port <= 0 || c.Port > 65535 {
+		problems = append(problems, fmt.Sprintf("port %d out of range", c.Port))
+	}
+
+	if c.DBDriver != "postgres" && c.DBDriver != "sqlite" {
+		problems = append(problems, fmt.Sprintf("db driver %q must be postgres or sqlite", c.DBDriver))
+	}
+
+	if (c.SessionSignKey == "") != (c.SessionEncryptKey == "") {
+		problems = append(problems, "session sign and encrypt keys must be set together")
+	}
+	for name, key := range map[string]string{"sign": c.SessionSignKey, "encrypt": c.SessionEncryptKey} {
+		if key == "" {
+			continue
+		}
+		b, err := base64.StdEncoding.DecodeString(key)
+		if err != nil || len(b) != 32 {
+			problems = append(problems, fmt.Sprintf("session %s key must be 32 base64 encoded bytes", name))
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("config: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// Addr is the address to listen on
+func (c Config) Addr() string {
+	return net.JoinHostPort(c.Host, strconv.Itoa(c.Port))
+}
+
+// SessionKeys returns the configured session keys, or random ones if there
+// are none
+func (c Config) SessionKeys() [][]byte {
+	if c.SessionSignKey == "" {
+		return [][]byte{securecookie.GenerateRandomKey(32), securecookie.GenerateRandomKey(32)}
+	}
+
+	sign, _ := base64.StdEncoding.DecodeString(c.SessionSignKey)
+	encrypt, _ := base64.StdEncoding.DecodeString(c.SessionEncryptKey)
+	return [][]byte{sign, encrypt}
+}
+
+// Print writes the config as YAML, redacting secrets
+func (c Config) Print(w io.Writer) error {
+	for _, s := range settings {
+		if s.secret && !isZero(s.value(&c)) {
+			set(s.value(&c), redacted)
+		}
+	}
+
+	out, err := yaml.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+
+	_, err = w.Write(out)
+	return err
+}
+
+func (c *Config) loadFile(path string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("config: failed to read file %w", err)
+	}
+
+	if err = yaml.UnmarshalStrict(data, c); err != nil {
+		return fmt.Errorf("config: failed to parse %s: %w", path, err)
+	}
+
+	return nil
+}
+
+func set(ptr interface{}, v string) error {
+	switch p := ptr.(type) {
+	case *string:
+		*p = v
+	case *bool:
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return err
+		}
+		*p = b
+	case *int:
+		i, err := strconv.Atoi(v)
+		if err != nil {
+			return err
+		}
+		*p = i
+	case *time.Duration:
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return err
+		}
+		*p = d
+	case *[]string:
+		*p = nil
+		for _, s := range strings.Split(v, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				*p = append(*p, s)
+			}
+		}
+	default:
+		return errors.New("unsupported type")
+	}
+	return nil
+}
+
+func isZero(ptr interface{}) bool {
+	switch p := ptr.(type) {
+	case *string:
+		return *p == ""
+	case *bool:
+		return !*p
+	case *int:
+		return *p == 0
+	case *time.Duration:
+		return *p == 0
+	case *[]string:
+		return len(*p) == 0
+	}
+	return false
+}
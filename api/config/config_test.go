@@ -0,0 +1,243 @@
+package config_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gorilla/securecookie"
+	"github.com/kieron-pivotal/menu-planner-app/config"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Config", func() {
+	var (
+		args    []string
+		env     map[string]string
+		cfg     config.Config
+		loadErr error
+	)
+
+	BeforeEach(func() {
+		args = []string{}
+		env = map[string]string{"DB_CONN_STR": "postgres://env", "GOOGLE_AUDIENCE": "web-app-id"}
+	})
+
+	JustBeforeEach(func() {
+		cfg, loadErr = config.Load(args, func(v string) string { return env[v] })
+	})
+
+	It("has sensible defaults", func() {
+		Expect(loadErr).NotTo(HaveOccurred())
+		Expect(cfg.Addr()).To(Equal("localhost:8080"))
+		Expect(cfg.WebURI).To(Equal("http://localhost:3000"))
+		Expect(cfg.GoogleAudience).To(Equal("web-app-id"))
+		Expect(cfg.CORSMaxAge).To(Equal(10 * time.Minute))
+		Expect(cfg.CORSAllowedMethods).To(BeEmpty())
+		Expect(cfg.CORSAllowedHeaders).To(Equal([]string{"Content-Type", "X-CSRF-Token"}))
+	})
+
+	It("reads the environment", func() {
+		env["HOST"] = "0.0.0.0"
+		env["PORT"] = "9090"
+		env["CORS_ALLOWED_ORIGINS"] = "https://a.com, https://*.b.com"
+		env["CORS_ALLOWED_METHODS"] = "GET,POST"
+		env["CORS_ALLOWED_HEADERS"] = "Content-Type, X-CSRF-Token, X-Request-Id"
+		env["CORS_MAX_AGE"] = "1h"
+
+		cfg, loadErr = config.Load(args, func(v string) string { return env[v] })
+		Expect(loadErr).NotTo(HaveOccurred())
+		Expect(cfg.Addr()).To(Equal("0.0.0.0:9090"))
+		Expect(cfg.DBConnStr).To(Equal("postgres://env"))
+		Expect(cfg.CORSAllowedOrigins).To(Equal([]string{"https://a.com", "https://*.b.com"}))
+		Expect(cfg.CORSAllowedMethods).To(Equal([]string{"GET", "POST"}))
+		Expect(cfg.CORSAllowedHeaders).To(Equal([]string{"Content-Type", "X-CSRF-Token", "X-Request-Id"}))
+		Expect(cfg.CORSMaxAge).To(Equal(time.Hour))
+	})
+
+	When("flags are passed", func() {
+		BeforeEach(func() {
+			args = []string{"--db-conn-str", "postgres://flag", "--port=9999", "--cors-allowed-methods", "GET", "--cors-allowed-headers=Content-Type"}
+			env["CORS_ALLOWED_HEADERS"] = "X-Request-Id"
+		})
+
+		It("prefers them to the environment", func() {
+			Expect(loadErr).NotTo(HaveOccurred())
+			Expect(cfg.DBConnStr).To(Equal("postgres://flag"))
+			Expect(cfg.Port).To(Equal(9999))
+			Expect(cfg.CORSAllowedMethods).To(Equal([]string{"GET"}))
+			Expect(cfg.CORSAllowedHeaders).To(Equal([]string{"Content-Type"}))
+		})
+	})
+
+	When("a config file is given", func() {
+		var dir string
+
+		BeforeEach(func() {
+			var err error
+			dir, err = ioutil.TempDir("", "config")
+			Expect(err).NotTo(HaveOccurred())
+
+			file := filepath.Join(dir, "config.yml")
+			Expect(ioutil.WriteFile(file, []byte(`
+host: 127.0.0.1
+port: 7000
+dbConnStr: postgres://file
+corsPublicOrigins:
+- https://public.com
+corsMaxAge: 5m
+`), 0600)).To(Succeed())
+
+			args = []string{"--config", file, "--port", "7001"}
+		})
+
+		AfterEach(func() {
+			Expect(os.RemoveAll(dir)).To(Succeed())
+		})
+
+		It("reads it with lower precedence than env and flags", func() {
+			Expect(loadErr).NotTo(HaveOccurred())
+			Expect(cfg.Host).To(Equal("127.0.0.1"))
+			Expect(cfg.Port).To(Equal(7001))
+			Expect(cfg.DBConnStr).To(Equal("postgres://env"))
+			Expect(cfg.CORSPublicOrigins).To(ConsistOf("https://public.com"))
+			Expect(cfg.CORSMaxAge).To(Equal(5 * time.Minute))
+		})
+
+		When("the file has unknown keys", func() {
+			BeforeEach(func() {
+				Expect(ioutil.WriteFile(args[1], []byte("prot: 7000\n"), 0600)).To(Succeed())
+			})
+
+			It("fails", func() {
+				Expect(loadErr).To(MatchError(ContainSubstring("failed to parse")))
+			})
+		})
+	})
+
+	When("required values are missing", func() {
+		BeforeEach(func() {
+			env = map[string]string{"GOOGLE_AUDIENCE": ""}
+			args = []string{"--google-audience", "", "--port", "0"}
+		})
+
+		It("lists every problem", func() {
+			Expect(loadErr).To(MatchError(SatisfyAll(
+				ContainSubstring("DB_CONN_STR (--db-conn-str)"),
+				ContainSubstring("GOOGLE_AUDIENCE (--google-audience)"),
+				ContainSubstring("port 0 out of range"),
+			)))
+		})
+	})
+
+	When("the google audience isn't given", func() {
+		BeforeEach(func() {
+			delete(env, "GOOGLE_AUDIENCE")
+		})
+
+		It("fails, as it has no default", func() {
+			Expect(loadErr).To(MatchError("config: missing GOOGLE_AUDIENCE (--google-audience)"))
+		})
+	})
+
+	When("running a demo", func() {
+		BeforeEach(func() {
+			env = map[string]string{"GOOGLE_AUDIENCE": "web-app-id"}
+			args = []string{"--demo"}
+		})
+
+		It("doesn't need a database", func() {
+			Expect(loadErr).NotTo(HaveOccurred())
+			Expect(cfg.Demo).To(BeTrue())
+		})
+	})
+
+	When("the db driver is unknown", func() {
+		BeforeEach(func() {
+			env["DB_DRIVER"] = "mysql"
+		})
+
+		It("fails", func() {
+			Expect(loadErr).To(MatchError(ContainSubstring(`db driver "mysql" must be postgres or sqlite`)))
+		})
+	})
+
+	When("a value can't be parsed", func() {
+		BeforeEach(func() {
+			env["PORT"] = "eighty"
+		})
+
+		It("fails naming the variable", func() {
+			Expect(loadErr).To(MatchError(ContainSubstring("invalid PORT")))
+		})
+	})
+
+	Context("session keys", func() {
+		var sign, encrypt []byte
+
+		BeforeEach(func() {
+			sign = securecookie.GenerateRandomKey(32)
+			encrypt = securecookie.GenerateRandomKey(32)
+			env["SESSION_SIGN_KEY"] = base64.StdEncoding.EncodeToString(sign)
+			env["SESSION_ENCRYPT_KEY"] = base64.StdEncoding.EncodeToString(encrypt)
+		})
+
+		It("decodes them", func() {
+			Expect(loadErr).NotTo(HaveOccurred())
+			Expect(cfg.SessionKeys()).To(Equal([][]byte{sign, encrypt}))
+		})
+
+		When("only one is set", func() {
+			BeforeEach(func() {
+				delete(env, "SESSION_ENCRYPT_KEY")
+			})
+
+			It("fails", func() {
+				Expect(loadErr).To(MatchError(ContainSubstring("must be set together")))
+			})
+		})
+
+		When("they aren't set", func() {
+			BeforeEach(func() {
+				delete(env, "SESSION_SIGN_KEY")
+				delete(env, "SESSION_ENCRYPT_KEY")
+			})
+
+			It("generates random ones", func() {
+				keys := cfg.SessionKeys()
+				Expect(keys).To(HaveLen(2))
+				Expect(keys[0]).To(HaveLen(32))
+			})
+		})
+	})
+
+	Context("printing", func() {
+		BeforeEach(func() {
+			args = []string{"--print-config", "--session-sign-key", base64.StdEncoding.EncodeToString(securecookie.GenerateRandomKey(32))}
+		})
+
+		It("redacts secrets", func() {
+			Expect(cfg.PrintConfig).To(BeTrue())
+
+			var out bytes.Buffer
+			Expect(cfg.Print(&out)).To(Succeed())
+			Expect(out.String()).To(SatisfyAll(
+				ContainSubstring("dbConnStr: <redacted>"),
+				ContainSubstring("sessionSignKey: <redacted>"),
+				ContainSubstring(`sessionEncryptKey: ""`),
+				ContainSubstring("port: 8080"),
+				ContainSubstring("corsAllowedMethods: []"),
+				ContainSubstring("corsAllowedHeaders:\n- Content-Type\n- X-CSRF-Token\n"),
+				Not(ContainSubstring("postgres://env")),
+			))
+		})
+
+		It("still returns validation errors", func() {
+			Expect(loadErr).To(MatchError(ContainSubstring("must be set together")))
+		})
+	})
+})
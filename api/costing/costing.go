@@ -0,0 +1,132 @@
+/* Package costing estimates what recipes cost from the prices users record */
+package costing
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/kieron-pivotal/menu-planner-app/models"
+	"github.com/kieron-pivotal/menu-planner-app/units"
+)
+
+type PriceLister interface {
+	List(ctx context.Context, userID int) ([]models.Price, error)
+}
+
+type Estimator struct {
+	priceLister PriceLister
+}
+
+func NewEstimator(priceLister PriceLister) *Estimator {
+	return &Estimator{
+		priceLister: priceLister,
+	}
+}
+
+// ForRecipe estimates a recipe's cost from its owner's prices
+func (e *Estimator) ForRecipe(ctx context.Context, recipe models.Recipe) (*models.Cost, error) {
+	prices, err := e.priceLister.List(ctx, recipe.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("estimate-cost failed %w", err)
+	}
+
+	return Estimate(recipe, prices), nil
+}
+
+// ForRecipes estimates the cost of each of a user's recipes, by recipe id,
+// listing their prices once
+func (e *Estimator) ForRecipes(ctx context.Context, userID int, recipes []models.Recipe) (map[int]*models.Cost, error) {
+	prices, err := e.priceLister.List(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("estimate-costs failed %w", err)
+	}
+
+	res := map[int]*models.Cost{}
+	for _, recipe := range recipes {
+		res[recipe.ID] = Estimate(recipe, prices)
+	}
+
+	return res, nil
+}
+
+// Estimate works out a recipe's cost, using the cheapest store for each
+// ingredient. Recipes without a number of servings count as one serving.
+// Ingredients without a quantity are assumed to be negligible. Ingredients
+// without a price, or whose quantity can't be compared with their prices,
+// are left out and listed as missing.
+func Estimate(recipe models.Recipe, prices []models.Price) *models.Cost {
+	res := &models.Cost{}
+	total := 0.0
+
+	for _, ing := range recipe.Ingredients {
+		if ing.Quantity == 0 {
+			continue
+		}
+
+		cheapest, found := 0.0, false
+		for _, p := range prices {
+			if !sameIngredient(ing.Name, p.Ingredient) {
+				continue
+			}
+			if cost, ok := cost(ing, p); ok && (!found || cost < cheapest) {
+				cheapest, found = cost, true
+			}
+		}
+
+		if !found {
+			res.Missing = append(res.Missing, ing.Name)
+			continue
+		}
+		total += cheapest
+	}
+
+	servings := recipe.Servings
+	if servings < 1 {
+		servings = 1
+	}
+	res.Total = int(math.Round(total))
+	res.PerServing = int(math.Round(total / float64(servings)))
+
+	return res
+}
+
+// cost of an ingredient at a price, if their quantities can be compared
+func cost(ing models.Ingredient, p models.Price) (float64, bool) {
+	if p.Quantity <= 0 {
+		return 0, false
+	}
+
+	if units.Normalize(ing.Unit) == units.Normalize(p.Unit) {
+		return ing.Quantity / p.Quantity * float64(p.Price), true
+	}
+
+	ingGrams, ok := units.Grams(ing.Quantity, ing.Unit, 0)
+	if !ok {
+		return 0, false
+	}
+	priceGrams, ok := units.Grams(p.Quantity, p.Unit, 0)
+	if !ok || priceGrams == 0 {
+		return 0, false
+	}
+
+	return ingGrams / priceGrams * float64(p.Price), true
+}
+
+// sameIngredient compares names ignoring case, spacing and plurals
+func sameIngredient(a, b string) bool {
+	forms := func(name string) []string {
+		name = strings.Join(strings.Fields(strings.ToLower(name)), " ")
+		return []string{name, strings.TrimSuffix(name, "s"), strings.TrimSuffix(name, "es")}
+	}
+
+	for _, x := range forms(a) {
+		for _, y := range forms(b) {
+			if x == y {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,13 @@
+package costing_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestCosting(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Costing Suite")
+}
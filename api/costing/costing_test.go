@@ -0,0 +1,109 @@
+package costing_test
+
+import (
+	"context"
+	"errors"
+
+	"github.com/kieron-pivotal/menu-planner-app/costing"
+	"github.com/kieron-pivotal/menu-planner-app/memstore"
+	"github.com/kieron-pivotal/menu-planner-app/models"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Costing", func() {
+	var prices []models.Price
+
+	BeforeEach(func() {
+		prices = []models.Price{
+			{Ingredient: "spaghetti", Store: "corner shop", Price: 150, Quantity: 500, Unit: "g"},
+			{Ingredient: "spaghetti", Store: "supermarket", Price: 100, Quantity: 1, Unit: "kg"},
+			{Ingredient: "onion", Store: "supermarket", Price: 20, Quantity: 1},
+			{Ingredient: "olive oil", Store: "supermarket", Price: 500, Quantity: 1, Unit: "l"},
+		}
+	})
+
+	It("uses the cheapest store for each ingredient", func() {
+		cost := costing.Estimate(models.Recipe{
+			Servings: 4,
+			Ingredients: []models.Ingredient{
+				{Name: "spaghetti", Quantity: 400, Unit: "g"},
+				{Name: "Onions", Quantity: 2},
+				{Name: "olive oil", Quantity: 2, Unit: "tbsp"},
+			},
+		}, prices)
+
+		Expect(cost.Total).To(Equal(95))
+		Expect(cost.PerServing).To(Equal(24))
+		Expect(cost.Missing).To(BeEmpty())
+	})
+
+	It("counts recipes without servings as one serving", func() {
+		cost := costing.Estimate(models.Recipe{Ingredients: []models.Ingredient{{Name: "onion", Quantity: 3}}}, prices)
+		Expect(cost.Total).To(Equal(60))
+		Expect(cost.PerServing).To(Equal(60))
+	})
+
+	It("lists ingredients it can't price", func() {
+		cost := costing.Estimate(models.Recipe{Ingredients: []models.Ingredient{
+			{Name: "saffron", Quantity: 1, Unit: "g"},
+			{Name: "onion", Quantity: 100, Unit: "g"},
+			{Name: "salt"},
+		}}, prices)
+
+		Expect(cost.Missing).To(Equal([]string{"saffron", "onion"}))
+		Expect(cost.Total).To(BeZero())
+	})
+
+	Describe("Estimator", func() {
+		It("estimates with the recipe owner's prices", func() {
+			memDB := memstore.New()
+			user, err := memstore.NewUserStore(memDB).Create(context.Background(), "cook@example.com", "Cook")
+			Expect(err).NotTo(HaveOccurred())
+			priceStore := memstore.NewPriceStore(memDB)
+			_, err = priceStore.Save(context.Background(), models.Price{UserID: user.ID(), Ingredient: "egg", Price: 30, Quantity: 1})
+			Expect(err).NotTo(HaveOccurred())
+
+			cost, err := costing.NewEstimator(priceStore).ForRecipe(context.Background(), models.Recipe{
+				UserID:      user.ID(),
+				Ingredients: []models.Ingredient{{Name: "eggs", Quantity: 2}},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cost.Total).To(Equal(60))
+		})
+
+		It("fails if the prices can't be listed", func() {
+			_, err := costing.NewEstimator(failingLister{}).ForRecipe(context.Background(), models.Recipe{})
+			Expect(err).To(MatchError("estimate-cost failed boom"))
+		})
+
+		It("estimates several recipes from one list of prices", func() {
+			lister := &countingLister{prices: prices}
+			costs, err := costing.NewEstimator(lister).ForRecipes(context.Background(), 1, []models.Recipe{
+				{ID: 3, Ingredients: []models.Ingredient{{Name: "onion", Quantity: 3}}},
+				{ID: 5, Ingredients: []models.Ingredient{{Name: "saffron", Quantity: 1, Unit: "g"}}},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(lister.calls).To(Equal(1))
+			Expect(costs).To(HaveLen(2))
+			Expect(costs[3].Total).To(Equal(60))
+			Expect(costs[5].Missing).To(ConsistOf("saffron"))
+		})
+	})
+})
+
+type failingLister struct{}
+
+func (failingLister) List(context.Context, int) ([]models.Price, error) {
+	return nil, errors.New("boom")
+}
+
+type countingLister struct {
+	prices []models.Price
+	calls  int
+}
+
+func (l *countingLister) List(context.Context, int) ([]models.Price, error) {
+	l.calls++
+	return l.prices, nil
+}
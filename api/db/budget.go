@@ -0,0 +1,49 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/kieron-pivotal/menu-planner-app/models"
+)
+
+type BudgetStore struct {
+	sqlDB DB
+}
+
+func NewBudgetStore(sqlDB DB) *BudgetStore {
+	return &BudgetStore{
+		sqlDB: sqlDB,
+	}
+}
+
+// Get returns the user's weekly budget, which is 0 if they haven't set one
+func (s *BudgetStore) Get(ctx context.Context, userID int) (models.Budget, error) {
+	budget := models.Budget{UserID: userID}
+
+	err := conn(ctx, s.sqlDB).QueryRowContext(ctx, `
+SELECT amount
+FROM weekly_budget
+WHERE user_id = $1`, userID).Scan(&budget.Weekly)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return models.Budget{}, fmt.Errorf("get-budget failed %w", err)
+	}
+
+	return budget, nil
+}
+
+// Save sets the user's weekly budget, replacing any they had
+func (s *BudgetStore) Save(ctx context.Context, budget models.Budget) error {
+	_, err := conn(ctx, s.sqlDB).ExecContext(ctx, `
+INSERT INTO weekly_budget (user_id, amount)
+VALUES ($1, $2)
+ON CONFLICT (user_id)
+DO UPDATE SET amount = excluded.amount`, budget.UserID, budget.Weekly)
+	if err != nil {
+		return fmt.Errorf("save-budget failed %w", err)
+	}
+
+	return nil
+}
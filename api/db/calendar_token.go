@@ -0,0 +1,75 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+type CalendarTokenStore struct {
+	sqlDB DB
+}
+
+func NewCalendarTokenStore(sqlDB DB) *CalendarTokenStore {
+	return &CalendarTokenStore{
+		sqlDB: sqlDB,
+	}
+}
+
+func (s *CalendarTokenStore) IsNotFoundErr(err error) bool {
+	return err == errNotFound
+}
+
+// Save gives the user a calendar token, replacing any they had. Only a
+// hash of the token is stored.
+func (s *CalendarTokenStore) Save(ctx context.Context, userID int, tokenHash string) error {
+	_, err := conn(ctx, s.sqlDB).ExecContext(ctx, `
+INSERT INTO calendar_token (user_id, token_hash, created_at)
+VALUES ($1, $2, $3)
+ON CONFLICT (user_id)
+DO UPDATE SET token_hash = excluded.token_hash, created_at = excluded.created_at`,
+		userID, tokenHash, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("save-calendar-token failed %w", err)
+	}
+
+	return nil
+}
+
+func (s *CalendarTokenStore) Delete(ctx context.Context, userID int) error {
+	res, err := conn(ctx, s.sqlDB).ExecContext(ctx, `
+DELETE FROM calendar_token
+WHERE user_id = $1`, userID)
+	if err != nil {
+		return fmt.Errorf("delete-calendar-token failed %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("delete-calendar-token failed %w", err)
+	}
+
+	if n == 0 {
+		return errNotFound
+	}
+
+	return nil
+}
+
+// FindUser returns the id of the user whose token has the given hash
+func (s *CalendarTokenStore) FindUser(ctx context.Context, tokenHash string) (int, error) {
+	var userID int
+	err := conn(ctx, s.sqlDB).QueryRowContext(ctx, `
+SELECT user_id
+FROM calendar_token
+WHERE token_hash = $1`, tokenHash).Scan(&userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, errNotFound
+		}
+		return 0, fmt.Errorf("find-calendar-token failed %w", err)
+	}
+
+	return userID, nil
+}
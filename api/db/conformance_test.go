@@ -0,0 +1,21 @@
+package db_test
+
+import (
+	"github.com/kieron-pivotal/menu-planner-app/db"
+	"github.com/kieron-pivotal/menu-planner-app/storetest"
+)
+
+var _ = storetest.DescribeStores("SQL stores", func() storetest.Stores {
+	return storetest.Stores{
+		Users:     db.NewUserStore(tx),
+		Recipes:   db.NewRecipeStore(tx),
+		Sessions:  db.NewSessionStore(tx),
+		Prices:    db.NewPriceStore(tx),
+		Budgets:   db.NewBudgetStore(tx),
+		CookLog:   db.NewCookLogStore(tx),
+		Ratings:   db.NewRatingStore(tx),
+		Calendar:  db.NewCalendarTokenStore(tx),
+		Plans:     db.NewPlanStore(tx),
+		Templates: db.NewPlanTemplateStore(tx),
+	}
+})
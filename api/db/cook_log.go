@@ -0,0 +1,139 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/kieron-pivotal/menu-planner-app/models"
+)
+
+type CookLogStore struct {
+	sqlDB DB
+}
+
+func NewCookLogStore(sqlDB DB) *CookLogStore {
+	return &CookLogStore{
+		sqlDB: sqlDB,
+	}
+}
+
+func (s *CookLogStore) IsNotFoundErr(err error) bool {
+	return err == errNotFound
+}
+
+// Add records that a recipe was cooked and updates when it was last
+// cooked. Run it in a unit of work so the two stay in step.
+func (s *CookLogStore) Add(ctx context.Context, cooked models.Cooked) (models.Cooked, error) {
+	cookedAt := cooked.CookedAt.UTC()
+
+	err := conn(ctx, s.sqlDB).QueryRowContext(ctx, `
+INSERT INTO cook_log (recipe_id, cooked_at, rating, notes, leftovers)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id`, cooked.RecipeID, cookedAt, cooked.Rating, cooked.Notes, cooked.Leftovers).Scan(&cooked.ID)
+	if err != nil {
+		return models.Cooked{}, fmt.Errorf("add-cooked failed %w", err)
+	}
+
+	_, err = conn(ctx, s.sqlDB).ExecContext(ctx, `
+UPDATE recipe
+SET last_cooked_at = $2
+WHERE id = $1
+AND (last_cooked_at IS NULL OR last_cooked_at < $2)`, cooked.RecipeID, cookedAt)
+	if err != nil {
+		return models.Cooked{}, fmt.Errorf("add-cooked failed %w", err)
+	}
+
+	return cooked, nil
+}
+
+// History lists the times a recipe was cooked, most recent first
+func (s *CookLogStore) History(ctx context.Context, recipeID int) ([]models.Cooked, error) {
+	return s.Histories(ctx, []int{recipeID})
+}
+
+// Histories lists the times any of the recipes were cooked, most recent
+// first, in one query
+func (s *CookLogStore) Histories(ctx context.Context, recipeIDs []int) ([]models.Cooked, error) {
+	res := []models.Cooked{}
+	if len(recipeIDs) == 0 {
+		return res, nil
+	}
+
+	args := []interface{}{}
+	for _, id := range recipeIDs {
+		args = append(args, id)
+	}
+	rows, err := conn(ctx, s.sqlDB).QueryContext(ctx, `
+SELECT id, recipe_id, cooked_at, rating, notes, leftovers
+FROM cook_log
+WHERE recipe_id IN (`+placeholders(1, len(recipeIDs))+`)
+ORDER BY cooked_at DESC, id DESC
+`, args...)
+	if err != nil {
+		return res, fmt.Errorf("cook-history failed %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		cooked := models.Cooked{}
+		if err = rows.Scan(&cooked.ID, &cooked.RecipeID, &cooked.CookedAt, &cooked.Rating, &cooked.Notes, &cooked.Leftovers); err != nil {
+			return res, fmt.Errorf("cook-history failed %w", err)
+		}
+
+		res = append(res, cooked)
+	}
+
+	return res, rows.Err()
+}
+
+// Leftovers lists the user's cooks which still have portions left, oldest
+// first so they are eaten before they go off
+func (s *CookLogStore) Leftovers(ctx context.Context, userID int) ([]models.Cooked, error) {
+	res := []models.Cooked{}
+
+	rows, err := conn(ctx, s.sqlDB).QueryContext(ctx, `
+SELECT c.id, c.recipe_id, c.cooked_at, c.rating, c.notes, c.leftovers
+FROM cook_log c
+JOIN recipe r ON r.id = c.recipe_id
+WHERE r.user_id = $1
+AND c.leftovers > 0
+ORDER BY c.cooked_at, c.id
+`, userID)
+	if err != nil {
+		return res, fmt.Errorf("list-leftovers failed %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		cooked := models.Cooked{}
+		if err = rows.Scan(&cooked.ID, &cooked.RecipeID, &cooked.CookedAt, &cooked.Rating, &cooked.Notes, &cooked.Leftovers); err != nil {
+			return res, fmt.Errorf("list-leftovers failed %w", err)
+		}
+
+		res = append(res, cooked)
+	}
+
+	return res, rows.Err()
+}
+
+// EatLeftovers takes portions from a cook's leftovers. Eating more than
+// is left uses them all up.
+func (s *CookLogStore) EatLeftovers(ctx context.Context, userID, id, portions int) (models.Cooked, error) {
+	cooked := models.Cooked{}
+	err := conn(ctx, s.sqlDB).QueryRowContext(ctx, `
+UPDATE cook_log
+SET leftovers = CASE WHEN leftovers > $3 THEN leftovers - $3 ELSE 0 END
+WHERE id = $1
+AND recipe_id IN (SELECT id FROM recipe WHERE user_id = $2)
+RETURNING id, recipe_id, cooked_at, rating, notes, leftovers`, id, userID, portions).
+		Scan(&cooked.ID, &cooked.RecipeID, &cooked.CookedAt, &cooked.Rating, &cooked.Notes, &cooked.Leftovers)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return models.Cooked{}, errNotFound
+		}
+		return models.Cooked{}, fmt.Errorf("eat-leftovers failed %w", err)
+	}
+
+	return cooked, nil
+}
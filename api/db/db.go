@@ -0,0 +1,72 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type DB interface {
+	QueryContext(context.Context, string, ...interface{}) (*sql.Rows, error)
+	QueryRowContext(context.Context, string, ...interface{}) *sql.Row
+	ExecContext(context.Context, string, ...interface{}) (sql.Result, error)
+}
+
+type txKey struct{}
+
+// Transactor runs units of work in a transaction. Stores called with the
+// context passed to the unit of work take part in its transaction.
+type Transactor struct {
+	sqlDB *sql.DB
+}
+
+func NewTransactor(sqlDB *sql.DB) *Transactor {
+	return &Transactor{
+		sqlDB: sqlDB,
+	}
+}
+
+// InTx commits if fn succeeds and rolls back if it returns an error. Calls
+// nested inside another unit of work join the outer transaction.
+func (t *Transactor) InTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := t.sqlDB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin-tx failed %w", err)
+	}
+	defer tx.Rollback()
+
+	if err = fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("commit-tx failed %w", err)
+	}
+
+	return nil
+}
+
+// conn returns the transaction of the unit of work running in ctx, if any,
+// or the store's own DB otherwise
+func conn(ctx context.Context, sqlDB DB) DB {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx
+	}
+	return sqlDB
+}
+
+// placeholders returns n comma separated query parameters numbered from
+// first, e.g. "$2, $3, $4", for an IN list
+func placeholders(first, n int) string {
+	params := make([]string, n)
+	for i := range params {
+		params[i] = "$" + strconv.Itoa(first+i)
+	}
+	return strings.Join(params, ", ")
+}
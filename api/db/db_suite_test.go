@@ -2,14 +2,12 @@ package db_test
 
 import (
 	"database/sql"
-	"fmt"
-	"os"
 	"testing"
 
+	"github.com/kieron-pivotal/menu-planner-app/db"
+	"github.com/kieron-pivotal/menu-planner-app/db/dbtest"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
-
-	_ "github.com/lib/pq"
 )
 
 func TestDb(t *testing.T) {
@@ -18,20 +16,25 @@ func TestDb(t *testing.T) {
 }
 
 var (
-	pg *sql.DB
-	tx *sql.Tx
+	sqlDB   *sql.DB
+	dialect db.Dialect
+	closeDB func()
+	tx      *sql.Tx
 )
 
 var _ = BeforeSuite(func() {
-	connStr := mustGetEnv("DB_CONN_STR")
 	var err error
-	pg, err = sql.Open("postgres", connStr)
+	sqlDB, dialect, closeDB, err = dbtest.Open()
 	Expect(err).NotTo(HaveOccurred())
 })
 
+var _ = AfterSuite(func() {
+	closeDB()
+})
+
 var _ = BeforeEach(func() {
 	var err error
-	tx, err = pg.Begin()
+	tx, err = sqlDB.Begin()
 	Expect(err).NotTo(HaveOccurred())
 })
 
@@ -39,11 +42,13 @@ var _ = AfterEach(func() {
 	Expect(tx.Rollback()).To(Succeed())
 })
 
-func mustGetEnv(v string) string {
-	s := os.Getenv(v)
-	if s != "" {
-		return s
+func tableExists(name string) bool {
+	query := `SELECT to_regclass($1) IS NOT NULL`
+	if dialect == db.SQLite {
+		query = `SELECT count(*) > 0 FROM sqlite_master WHERE type = 'table' AND name = $1`
 	}
-	Fail(fmt.Sprintf("expected env var %q", v))
-	return ""
+
+	var exists bool
+	Expect(sqlDB.QueryRow(query, name).Scan(&exists)).To(Succeed())
+	return exists
 }
@@ -0,0 +1,91 @@
+package db_test
+
+import (
+	"context"
+	"errors"
+
+	"github.com/kieron-pivotal/menu-planner-app/db"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Transactor", func() {
+	var (
+		transactor *db.Transactor
+		userStore  *db.UserStore
+		ctx        context.Context
+	)
+
+	BeforeEach(func() {
+		transactor = db.NewTransactor(sqlDB)
+		userStore = db.NewUserStore(sqlDB)
+		ctx = context.Background()
+	})
+
+	AfterEach(func() {
+		_, err := sqlDB.Exec(`DELETE FROM local_user WHERE email LIKE '%@transactor.test'`)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("commits when the unit of work succeeds", func() {
+		err := transactor.InTx(ctx, func(ctx context.Context) error {
+			_, err := userStore.Create(ctx, "a@transactor.test", "a")
+			return err
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = userStore.FindByEmail(ctx, "a@transactor.test")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("rolls back every store call when the unit of work fails", func() {
+		err := transactor.InTx(ctx, func(ctx context.Context) error {
+			if _, err := userStore.Create(ctx, "a@transactor.test", "a"); err != nil {
+				return err
+			}
+			if _, err := userStore.Create(ctx, "b@transactor.test", "b"); err != nil {
+				return err
+			}
+			return errors.New("oops")
+		})
+		Expect(err).To(MatchError("oops"))
+
+		_, err = userStore.FindByEmail(ctx, "a@transactor.test")
+		Expect(userStore.IsNotFoundErr(err)).To(BeTrue())
+	})
+
+	It("sees its own uncommitted writes", func() {
+		err := transactor.InTx(ctx, func(ctx context.Context) error {
+			if _, err := userStore.Create(ctx, "a@transactor.test", "a"); err != nil {
+				return err
+			}
+			_, err := userStore.FindByEmail(ctx, "a@transactor.test")
+			return err
+		})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("joins an outer unit of work", func() {
+		err := transactor.InTx(ctx, func(ctx context.Context) error {
+			if err := transactor.InTx(ctx, func(ctx context.Context) error {
+				_, err := userStore.Create(ctx, "a@transactor.test", "a")
+				return err
+			}); err != nil {
+				return err
+			}
+			return errors.New("oops")
+		})
+		Expect(err).To(MatchError("oops"))
+
+		_, err = userStore.FindByEmail(ctx, "a@transactor.test")
+		Expect(userStore.IsNotFoundErr(err)).To(BeTrue())
+	})
+
+	It("stops when the context is cancelled", func() {
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		_, err := userStore.FindByEmail(cancelled, "a@transactor.test")
+		Expect(err).To(MatchError(ContainSubstring("context canceled")))
+	})
+})
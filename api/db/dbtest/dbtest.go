@@ -0,0 +1,48 @@
+/* Package dbtest provides the database for the db and integration test suites */
+package dbtest
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/kieron-pivotal/menu-planner-app/db"
+	"github.com/kieron-pivotal/menu-planner-app/db/migrations"
+)
+
+// Open connects to the postgres database in DB_CONN_STR, which is expected
+// to be migrated already. When DB_CONN_STR isn't set, it creates and
+// migrates a SQLite database in a temporary directory instead. The returned
+// cleanup func closes the database and removes any temporary files.
+func Open() (*sql.DB, db.Dialect, func(), error) {
+	if connStr := os.Getenv("DB_CONN_STR"); connStr != "" {
+		sqlDB, err := db.Open(db.Postgres, connStr)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		return sqlDB, db.Postgres, func() { sqlDB.Close() }, nil
+	}
+
+	dir, err := ioutil.TempDir("", "menu-planner-test")
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("dbtest: failed to create temp dir %w", err)
+	}
+	cleanup := func() { os.RemoveAll(dir) }
+
+	sqlDB, err := db.Open(db.SQLite, filepath.Join(dir, "test.db"))
+	if err != nil {
+		cleanup()
+		return nil, "", nil, err
+	}
+
+	if _, err = db.NewMigrator(sqlDB, db.SQLite, migrations.SQLite).Migrate(context.Background(), false); err != nil {
+		sqlDB.Close()
+		cleanup()
+		return nil, "", nil, err
+	}
+
+	return sqlDB, db.SQLite, func() { sqlDB.Close(); cleanup() }, nil
+}
@@ -0,0 +1,52 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// Dialect is the flavour of SQL database the stores run against. Store
+// queries are written to work with both.
+type Dialect string
+
+const (
+	Postgres Dialect = "postgres"
+	SQLite   Dialect = "sqlite"
+)
+
+// Open connects to the database. For SQLite, connStr is the path of the
+// database file, which is created if it doesn't exist.
+func Open(dialect Dialect, connStr string) (*sql.DB, error) {
+	switch dialect {
+	case Postgres:
+		return sql.Open("postgres", connStr)
+	case SQLite:
+		// times are written in SQLite's own format so that they compare
+		// correctly as text
+		return sql.Open("sqlite", "file:"+connStr+
+			"?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_time_format=sqlite")
+	}
+
+	return nil, fmt.Errorf("unknown database dialect %q", dialect)
+}
+
+// isUniqueViolation tells whether err is either database refusing a row
+// which a unique index already has
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code == "23505"
+	}
+
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE
+	}
+
+	return false
+}
@@ -0,0 +1,96 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/kieron-pivotal/menu-planner-app/events"
+	"github.com/kieron-pivotal/menu-planner-app/models"
+	"github.com/lib/pq"
+)
+
+// eventChannel is the Postgres channel events are sent on
+const eventChannel = "menu_planner_events"
+
+// EventBus publishes events with Postgres NOTIFY, so that every replica
+// hears them, and delivers those it hears to its hub's subscribers
+type EventBus struct {
+	sqlDB    DB
+	hub      *events.Hub
+	listener *pq.Listener
+}
+
+// notification is the payload of an event's NOTIFY
+type notification struct {
+	UserID int          `json:"userId"`
+	Event  models.Event `json:"event"`
+}
+
+// NewEventBus listens for events on its own connection to the Postgres
+// database in connStr, reconnecting if it is lost
+func NewEventBus(sqlDB DB, connStr string, hub *events.Hub) (*EventBus, error) {
+	listener := pq.NewListener(connStr, time.Second, time.Minute, func(_ pq.ListenerEventType, err error) {
+		if err != nil {
+			log.Printf("event-listen: %v\n", err)
+		}
+	})
+	if err := listener.Listen(eventChannel); err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("listen-events failed %w", err)
+	}
+
+	b := &EventBus{
+		sqlDB:    sqlDB,
+		hub:      hub,
+		listener: listener,
+	}
+	go b.listen()
+
+	return b, nil
+}
+
+// Publish sends an event to the user's subscribers on every replica. In a
+// unit of work, it is sent when the transaction commits.
+func (b *EventBus) Publish(ctx context.Context, userID int, event models.Event) error {
+	payload, err := json.Marshal(notification{UserID: userID, Event: event})
+	if err != nil {
+		return fmt.Errorf("publish-event failed %w", err)
+	}
+
+	if _, err = conn(ctx, b.sqlDB).ExecContext(ctx, `SELECT pg_notify($1, $2)`, eventChannel, string(payload)); err != nil {
+		return fmt.Errorf("publish-event failed %w", err)
+	}
+
+	return nil
+}
+
+func (b *EventBus) Subscribe(userID int) (<-chan models.Event, func()) {
+	return b.hub.Subscribe(userID)
+}
+
+// Close stops listening for events
+func (b *EventBus) Close() error {
+	return b.listener.Close()
+}
+
+// listen delivers the events it is notified of until the bus is closed.
+// Those sent while the listener was reconnecting are lost, so subscribers
+// are told to resync.
+func (b *EventBus) listen() {
+	for n := range b.listener.Notify {
+		if n == nil {
+			b.hub.Broadcast(models.Event{Type: models.Resync})
+			continue
+		}
+
+		var note notification
+		if err := json.Unmarshal([]byte(n.Extra), &note); err != nil {
+			log.Printf("event-decode: %v\n", err)
+			continue
+		}
+		b.hub.Publish(context.Background(), note.UserID, note.Event)
+	}
+}
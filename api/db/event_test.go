@@ -0,0 +1,70 @@
+package db_test
+
+import (
+	"context"
+	"os"
+
+	"github.com/kieron-pivotal/menu-planner-app/db"
+	"github.com/kieron-pivotal/menu-planner-app/events"
+	"github.com/kieron-pivotal/menu-planner-app/models"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("EventBus", func() {
+	var (
+		replicaA *db.EventBus
+		replicaB *db.EventBus
+		cooked   models.Event
+	)
+
+	BeforeEach(func() {
+		if dialect != db.Postgres {
+			Skip("LISTEN/NOTIFY needs postgres")
+		}
+
+		var err error
+		replicaA, err = db.NewEventBus(sqlDB, os.Getenv("DB_CONN_STR"), events.NewHub())
+		Expect(err).NotTo(HaveOccurred())
+		replicaB, err = db.NewEventBus(sqlDB, os.Getenv("DB_CONN_STR"), events.NewHub())
+		Expect(err).NotTo(HaveOccurred())
+
+		cooked = models.Event{Type: models.RecipeCooked, RecipeID: 3}
+	})
+
+	AfterEach(func() {
+		if replicaA != nil {
+			Expect(replicaA.Close()).To(Succeed())
+			Expect(replicaB.Close()).To(Succeed())
+		}
+	})
+
+	It("sends events to the user's subscribers on every replica", func() {
+		onA, stopA := replicaA.Subscribe(123)
+		defer stopA()
+		onB, stopB := replicaB.Subscribe(123)
+		defer stopB()
+		other, stopOther := replicaB.Subscribe(234)
+		defer stopOther()
+
+		Expect(replicaA.Publish(context.Background(), 123, cooked)).To(Succeed())
+
+		Eventually(onA).Should(Receive(Equal(cooked)))
+		Eventually(onB).Should(Receive(Equal(cooked)))
+		Consistently(other).ShouldNot(Receive())
+	})
+
+	It("sends events published in a unit of work when it commits", func() {
+		onB, stop := replicaB.Subscribe(123)
+		defer stop()
+
+		err := db.NewTransactor(sqlDB).InTx(context.Background(), func(ctx context.Context) error {
+			Expect(replicaA.Publish(ctx, 123, cooked)).To(Succeed())
+			Consistently(onB).ShouldNot(Receive())
+			return nil
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		Eventually(onB).Should(Receive(Equal(cooked)))
+	})
+})
@@ -0,0 +1,6 @@
+package db
+
+// SetTable lets tests record migrations somewhere other than the real table
+func (m *Migrator) SetTable(table string) {
+	m.table = table
+}
@@ -0,0 +1,325 @@
+package db
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	migrationTable = "schema_migration"
+	// migrationLockID is an arbitrary key for the postgres advisory lock
+	// which stops two servers migrating at once. SQLite databases belong to
+	// a single server so need no lock.
+	migrationLockID = 7346231
+)
+
+var migrationFileName = regexp.MustCompile(`^V(\d+)__(\w+)\.sql$`)
+
+type Migration struct {
+	Version     int
+	Description string
+	Checksum    string
+	SQL         string
+}
+
+type MigrationStatus struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+	// Modified is set when the file has changed since it was applied
+	Modified bool
+	// Missing is set when an applied migration has no file
+	Missing bool
+}
+
+type Migrator struct {
+	sqlDB      *sql.DB
+	dialect    Dialect
+	migrations fs.FS
+	table      string
+}
+
+func NewMigrator(sqlDB *sql.DB, dialect Dialect, migrations fs.FS) *Migrator {
+	return &Migrator{
+		sqlDB:      sqlDB,
+		dialect:    dialect,
+		migrations: migrations,
+		table:      migrationTable,
+	}
+}
+
+// Status lists every migration, whether applied or pending
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	conn, err := m.lock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer m.unlock(ctx, conn)
+
+	return m.status(ctx, conn)
+}
+
+// Migrate applies pending migrations in version order, each in its own
+// transaction, returning those applied. With dryRun it only returns the
+// migrations which would be applied.
+func (m *Migrator) Migrate(ctx context.Context, dryRun bool) ([]Migration, error) {
+	conn, err := m.lock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer m.unlock(ctx, conn)
+
+	statuses, err := m.status(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	pending := []Migration{}
+	for _, s := range statuses {
+		switch {
+		case s.Modified:
+			return nil, fmt.Errorf("migrate: V%d has changed since it was applied", s.Version)
+		case s.Missing:
+			return nil, fmt.Errorf("migrate: V%d was applied but its file is missing", s.Version)
+		case !s.Applied:
+			pending = append(pending, s.Migration)
+		}
+	}
+
+	if dryRun {
+		return pending, nil
+	}
+
+	for i, mig := range pending {
+		if err = m.apply(ctx, conn, mig); err != nil {
+			return pending[:i], err
+		}
+	}
+
+	return pending, nil
+}
+
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, mig Migration) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("migrate: V%d begin failed %w", mig.Version, err)
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.ExecContext(ctx, mig.SQL); err != nil {
+		return fmt.Errorf("migrate: V%d failed %w", mig.Version, err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+INSERT INTO `+m.table+` (version, description, checksum, applied_at)
+VALUES ($1, $2, $3, $4)`, mig.Version, mig.Description, mig.Checksum, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("migrate: V%d record failed %w", mig.Version, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("migrate: V%d commit failed %w", mig.Version, err)
+	}
+
+	return nil
+}
+
+func (m *Migrator) status(ctx context.Context, conn *sql.Conn) ([]MigrationStatus, error) {
+	migrations, err := ParseMigrations(m.migrations)
+	if err != nil {
+		return nil, err
+	}
+
+	applied, err := m.applied(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(applied) == 0 && m.dialect == Postgres {
+		if applied, err = m.adoptFlywayHistory(ctx, conn, migrations); err != nil {
+			return nil, err
+		}
+	}
+
+	res := []MigrationStatus{}
+	for _, mig := range migrations {
+		s := MigrationStatus{Migration: mig}
+		if a, ok := applied[mig.Version]; ok {
+			s.Applied = true
+			s.AppliedAt = a.AppliedAt
+			s.Modified = a.Checksum != mig.Checksum
+			delete(applied, mig.Version)
+		}
+		res = append(res, s)
+	}
+
+	for _, a := range applied {
+		a.Missing = true
+		res = append(res, a)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Version < res[j].Version })
+
+	return res, nil
+}
+
+func (m *Migrator) applied(ctx context.Context, conn *sql.Conn) (map[int]MigrationStatus, error) {
+	timestamp := "TIMESTAMP WITH TIME ZONE"
+	if m.dialect == SQLite {
+		timestamp = "TIMESTAMP"
+	}
+
+	_, err := conn.ExecContext(ctx, `
+CREATE TABLE IF NOT EXISTS `+m.table+` (
+    version INT PRIMARY KEY,
+    description VARCHAR(200) NOT NULL,
+    checksum CHAR(64) NOT NULL,
+    applied_at `+timestamp+` NOT NULL
+)`)
+	if err != nil {
+		return nil, fmt.Errorf("migrate: failed to create %s %w", m.table, err)
+	}
+
+	rows, err := conn.QueryContext(ctx, `
+SELECT version, description, checksum, applied_at
+FROM `+m.table)
+	if err != nil {
+		return nil, fmt.Errorf("migrate: failed to read %s %w", m.table, err)
+	}
+	defer rows.Close()
+
+	res := map[int]MigrationStatus{}
+	for rows.Next() {
+		s := MigrationStatus{Applied: true}
+		if err = rows.Scan(&s.Version, &s.Description, &s.Checksum, &s.AppliedAt); err != nil {
+			return nil, fmt.Errorf("migrate: failed to read %s %w", m.table, err)
+		}
+		res[s.Version] = s
+	}
+
+	return res, rows.Err()
+}
+
+// adoptFlywayHistory records migrations already applied by Flyway, which
+// managed the schema before this runner existed
+func (m *Migrator) adoptFlywayHistory(ctx context.Context, conn *sql.Conn, migrations []Migration) (map[int]MigrationStatus, error) {
+	res := map[int]MigrationStatus{}
+
+	var exists bool
+	err := conn.QueryRowContext(ctx, `SELECT to_regclass('flyway_schema_history') IS NOT NULL`).Scan(&exists)
+	if err != nil || !exists {
+		return res, err
+	}
+
+	rows, err := conn.QueryContext(ctx, `
+SELECT version, installed_on
+FROM flyway_schema_history
+WHERE success AND version IS NOT NULL`)
+	if err != nil {
+		return nil, fmt.Errorf("migrate: failed to read flyway history %w", err)
+	}
+	defer rows.Close()
+
+	installed := map[string]time.Time{}
+	for rows.Next() {
+		var version string
+		var on time.Time
+		if err = rows.Scan(&version, &on); err != nil {
+			return nil, fmt.Errorf("migrate: failed to read flyway history %w", err)
+		}
+		installed[version] = on
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("migrate: failed to read flyway history %w", err)
+	}
+
+	for _, mig := range migrations {
+		on, ok := installed[strconv.Itoa(mig.Version)]
+		if !ok {
+			continue
+		}
+
+		_, err = conn.ExecContext(ctx, `
+INSERT INTO `+m.table+` (version, description, checksum, applied_at)
+VALUES ($1, $2, $3, $4)`, mig.Version, mig.Description, mig.Checksum, on)
+		if err != nil {
+			return nil, fmt.Errorf("migrate: failed to adopt flyway history %w", err)
+		}
+		res[mig.Version] = MigrationStatus{Migration: mig, Applied: true, AppliedAt: on}
+	}
+
+	return res, nil
+}
+
+func (m *Migrator) lock(ctx context.Context) (*sql.Conn, error) {
+	conn, err := m.sqlDB.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("migrate: failed to connect %w", err)
+	}
+
+	if m.dialect != Postgres {
+		return conn, nil
+	}
+
+	if _, err = conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, migrationLockID); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("migrate: failed to lock %w", err)
+	}
+
+	return conn, nil
+}
+
+func (m *Migrator) unlock(ctx context.Context, conn *sql.Conn) {
+	if m.dialect == Postgres {
+		conn.ExecContext(ctx, `SELECT pg_advisory_unlock($1)`, migrationLockID)
+	}
+	conn.Close()
+}
+
+// ParseMigrations reads the V<version>__<description>.sql files in fsys,
+// ordered by version
+func ParseMigrations(fsys fs.FS) ([]Migration, error) {
+	paths, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return nil, fmt.Errorf("migrate: %w", err)
+	}
+
+	res := []Migration{}
+	seen := map[int]string{}
+	for _, path := range paths {
+		match := migrationFileName.FindStringSubmatch(path)
+		if match == nil {
+			return nil, fmt.Errorf("migrate: %q is not named V<version>__<description>.sql", path)
+		}
+
+		version, _ := strconv.Atoi(match[1])
+		if other, ok := seen[version]; ok {
+			return nil, fmt.Errorf("migrate: %q and %q have the same version", other, path)
+		}
+		seen[version] = path
+
+		content, err := fs.ReadFile(fsys, path)
+		if err != nil {
+			return nil, fmt.Errorf("migrate: %w", err)
+		}
+
+		sum := sha256.Sum256(content)
+		res = append(res, Migration{
+			Version:     version,
+			Description: strings.ReplaceAll(match[2], "_", " "),
+			Checksum:    hex.EncodeToString(sum[:]),
+			SQL:         string(content),
+		})
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Version < res[j].Version })
+
+	return res, nil
+}
@@ -0,0 +1,259 @@
+package db_test
+
+import (
+	"context"
+	"io/fs"
+	"testing/fstest"
+	"time"
+
+	"github.com/kieron-pivotal/menu-planner-app/db"
+	"github.com/kieron-pivotal/menu-planner-app/db/migrations"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Migrations", func() {
+	Describe("parsing", func() {
+		It("orders the embedded migrations by version", func() {
+			migs, err := db.ParseMigrations(migrations.Postgres)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(len(migs)).To(BeNumerically(">=", 3))
+			Expect(migs[0].Version).To(Equal(1))
+			Expect(migs[0].Description).To(Equal("CreateLocalUserTable"))
+			Expect(migs[0].Checksum).To(HaveLen(64))
+			for i := 1; i < len(migs); i++ {
+				Expect(migs[i].Version).To(BeNumerically(">", migs[i-1].Version))
+			}
+		})
+
+		It("has the same migrations for every database", func() {
+			pgMigs, err := db.ParseMigrations(migrations.Postgres)
+			Expect(err).NotTo(HaveOccurred())
+			sqliteMigs, err := db.ParseMigrations(migrations.SQLite)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(sqliteMigs).To(HaveLen(len(pgMigs)))
+			for i := range pgMigs {
+				Expect(sqliteMigs[i].Version).To(Equal(pgMigs[i].Version))
+				Expect(sqliteMigs[i].Description).To(Equal(pgMigs[i].Description))
+			}
+		})
+
+		It("rejects badly named files", func() {
+			_, err := db.ParseMigrations(fstest.MapFS{"create_things.sql": {}})
+			Expect(err).To(MatchError(ContainSubstring("is not named")))
+		})
+
+		It("rejects duplicate versions", func() {
+			_, err := db.ParseMigrations(fstest.MapFS{
+				"V1__a.sql":  {},
+				"V01__b.sql": {},
+			})
+			Expect(err).To(MatchError(ContainSubstring("have the same version")))
+		})
+	})
+
+	Describe("running", func() {
+		var (
+			migrator *db.Migrator
+			fsys     fstest.MapFS
+			ctx      context.Context
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			fsys = fstest.MapFS{
+				"V1__Create_Thing.sql": {Data: []byte(`CREATE TABLE migrate_test_thing (id INT)`)},
+				"V2__Insert_Thing.sql": {Data: []byte(`INSERT INTO migrate_test_thing VALUES (1)`)},
+				"V10__Insert_More.sql": {Data: []byte(`INSERT INTO migrate_test_thing VALUES (10)`)},
+			}
+			migrator = db.NewMigrator(sqlDB, dialect, fsys)
+			migrator.SetTable("migrate_test_schema_migration")
+		})
+
+		AfterEach(func() {
+			for _, table := range []string{"migrate_test_thing", "migrate_test_schema_migration"} {
+				_, err := sqlDB.Exec(`DROP TABLE IF EXISTS ` + table)
+				Expect(err).NotTo(HaveOccurred())
+			}
+		})
+
+		It("lists pending migrations on a dry run without applying them", func() {
+			pending, err := migrator.Migrate(ctx, true)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pending).To(HaveLen(3))
+			Expect(pending[0].Description).To(Equal("Create Thing"))
+			Expect(pending[2].Version).To(Equal(10))
+
+			Expect(tableExists("migrate_test_thing")).To(BeFalse())
+		})
+
+		It("applies pending migrations in version order, once", func() {
+			applied, err := migrator.Migrate(ctx, false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(applied).To(HaveLen(3))
+
+			var count int
+			Expect(sqlDB.QueryRow(`SELECT count(*) FROM migrate_test_thing`).Scan(&count)).To(Succeed())
+			Expect(count).To(Equal(2))
+
+			applied, err = migrator.Migrate(ctx, false)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(applied).To(BeEmpty())
+		})
+
+		It("reports the status of each migration", func() {
+			delete(fsys, "V10__Insert_More.sql")
+			_, err := migrator.Migrate(ctx, false)
+			Expect(err).NotTo(HaveOccurred())
+
+			fsys["V10__Insert_More.sql"] = &fstest.MapFile{Data: []byte(`SELECT 1`)}
+			statuses, err := migrator.Status(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(statuses).To(HaveLen(3))
+			Expect(statuses[0].Applied).To(BeTrue())
+			Expect(statuses[0].AppliedAt).NotTo(BeZero())
+			Expect(statuses[2].Applied).To(BeFalse())
+		})
+
+		It("refuses to run when an applied migration has changed", func() {
+			_, err := migrator.Migrate(ctx, false)
+			Expect(err).NotTo(HaveOccurred())
+
+			fsys["V2__Insert_Thing.sql"] = &fstest.MapFile{Data: []byte(`INSERT INTO migrate_test_thing VALUES (2)`)}
+			_, err = migrator.Migrate(ctx, false)
+			Expect(err).To(MatchError(ContainSubstring("V2 has changed")))
+
+			statuses, err := migrator.Status(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(statuses[1].Modified).To(BeTrue())
+		})
+
+		It("rolls back a failing migration", func() {
+			fsys["V3__Broken.sql"] = &fstest.MapFile{Data: []byte(`INSERT INTO migrate_test_thing VALUES (3); SELECT nonsense`)}
+			applied, err := migrator.Migrate(ctx, false)
+			Expect(err).To(MatchError(ContainSubstring("V3 failed")))
+			Expect(applied).To(HaveLen(2))
+
+			var count int
+			Expect(sqlDB.QueryRow(`SELECT count(*) FROM migrate_test_thing`).Scan(&count)).To(Succeed())
+			Expect(count).To(Equal(1))
+		})
+	})
+
+	Describe("making recipe names unique", func() {
+		BeforeEach(func() {
+			_, err := tx.Exec(`DROP INDEX recipe__user_id_lower_name`)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = tx.Exec(`INSERT INTO local_user (id, name, email)
+                VALUES (123, 'bob', 'bob@example.com'), (234, 'jim', 'jim@example.com')`)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = tx.Exec(`INSERT INTO recipe (id, name, user_id)
+                VALUES (1, 'Soup', 123), (2, 'soup', 123), (3, 'SOUP', 123), (4, 'Stew', 123), (5, 'soup', 234)`)
+			Expect(err).NotTo(HaveOccurred())
+
+			runMigration("V11__AddRecipeNameUniqueIndex.sql")
+		})
+
+		It("tells apart the user's recipes differing only in case", func() {
+			Expect(recipeNames()).To(Equal(map[int]string{1: "Soup", 2: "soup (2)", 3: "SOUP (3)", 4: "Stew", 5: "soup"}))
+		})
+
+		It("adds the index", func() {
+			_, err := tx.Exec(`INSERT INTO recipe (name, user_id) VALUES ('sOUP', 123)`)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("merging the recipes told apart by name", func() {
+		var cookedAt time.Time
+
+		BeforeEach(func() {
+			_, err := tx.Exec(`INSERT INTO local_user (id, name, email)
+                VALUES (123, 'bob', 'bob@example.com'), (234, 'jim', 'jim@example.com')`)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = tx.Exec(`INSERT INTO recipe (id, name, user_id)
+                VALUES (1, 'Soup', 123), (2, 'soup (2)', 123), (3, 'SOUP (3)', 123), (4, 'Stew', 123),
+                    (5, 'soup', 234), (6, 'Stew (4)', 123), (7, 'Soup (5)', 234)`)
+			Expect(err).NotTo(HaveOccurred())
+
+			cookedAt = time.Date(2020, 3, 4, 18, 0, 0, 0, time.UTC)
+			_, err = tx.Exec(`INSERT INTO cook_log (recipe_id, cooked_at)
+                VALUES (1, $1), (2, $2), (3, $1)`, cookedAt.AddDate(0, 0, -7), cookedAt)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = tx.Exec(`INSERT INTO recipe_rating (recipe_id, user_id, rating, favourite)
+                VALUES (1, 123, 0, FALSE), (2, 123, 4, FALSE), (3, 123, 2, TRUE)`)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = tx.Exec(`INSERT INTO plan_slot (user_id, week, day, meal, recipe_id)
+                VALUES (123, '2026-10-19', 0, 'dinner', 3)`)
+			Expect(err).NotTo(HaveOccurred())
+
+			runMigration("V15__MergeRecipeNameDuplicates.sql")
+		})
+
+		It("merges them into the first saved, leaving names that only look alike", func() {
+			Expect(recipeNames()).To(Equal(map[int]string{1: "Soup", 4: "Stew", 5: "soup", 6: "Stew (4)", 7: "Soup (5)"}))
+		})
+
+		It("keeps the merged recipes' cook log, ratings and places in plans", func() {
+			var count int
+			Expect(tx.QueryRow(`SELECT count(*) FROM cook_log WHERE recipe_id = 1`).Scan(&count)).To(Succeed())
+			Expect(count).To(Equal(3))
+
+			var lastCooked time.Time
+			Expect(tx.QueryRow(`SELECT last_cooked_at FROM recipe WHERE id = 1`).Scan(&lastCooked)).To(Succeed())
+			Expect(lastCooked).To(BeTemporally("==", cookedAt))
+
+			var (
+				rating    int
+				favourite bool
+			)
+			Expect(tx.QueryRow(`SELECT rating, favourite FROM recipe_rating WHERE recipe_id = 1 AND user_id = 123`).
+				Scan(&rating, &favourite)).To(Succeed())
+			Expect(rating).To(Equal(4))
+			Expect(favourite).To(BeTrue())
+
+			var recipeID int
+			Expect(tx.QueryRow(`SELECT recipe_id FROM plan_slot WHERE user_id = 123`).Scan(&recipeID)).To(Succeed())
+			Expect(recipeID).To(Equal(1))
+		})
+	})
+})
+
+// runMigration runs one of the embedded migrations for the test database in
+// the test's transaction
+func runMigration(name string) {
+	fsys := migrations.Postgres
+	if dialect == db.SQLite {
+		fsys = migrations.SQLite
+	}
+	migration, err := fs.ReadFile(fsys, name)
+	Expect(err).NotTo(HaveOccurred())
+
+	_, err = tx.Exec(string(migration))
+	Expect(err).NotTo(HaveOccurred())
+}
+
+// recipeNames are the names of every recipe by id
+func recipeNames() map[int]string {
+	rows, err := tx.Query(`SELECT id, name FROM recipe ORDER BY id`)
+	Expect(err).NotTo(HaveOccurred())
+	defer rows.Close()
+
+	names := map[int]string{}
+	for rows.Next() {
+		var (
+			id   int
+			name string
+		)
+		Expect(rows.Scan(&id, &name)).To(Succeed())
+		names[id] = name
+	}
+	Expect(rows.Err()).NotTo(HaveOccurred())
+	return names
+}
@@ -0,0 +1,9 @@
+CREATE TABLE calendar_token (
+    user_id INT PRIMARY KEY,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+
+    CONSTRAINT fk_user
+        FOREIGN KEY(user_id)
+            REFERENCES local_user(id)
+);
@@ -0,0 +1,13 @@
+-- names differing only in case are the same recipe, so tell apart any
+-- already saved before adding the index
+UPDATE recipe
+SET name = SUBSTR(name, 1, 180) || ' (' || id || ')'
+WHERE EXISTS (
+    SELECT 1 FROM recipe earlier
+    WHERE earlier.user_id = recipe.user_id
+    AND LOWER(earlier.name) = LOWER(recipe.name)
+    AND earlier.id < recipe.id
+);
+
+CREATE UNIQUE INDEX recipe__user_id_lower_name
+    ON recipe (user_id, LOWER(name));
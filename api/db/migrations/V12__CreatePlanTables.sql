@@ -0,0 +1,61 @@
+CREATE TABLE plan_slot (
+    user_id INT NOT NULL,
+    week VARCHAR(10) NOT NULL,
+    day INT NOT NULL,
+    meal VARCHAR(20) NOT NULL,
+    recipe_id INT NOT NULL,
+
+    PRIMARY KEY (user_id, week, day, meal),
+    CONSTRAINT fk_user
+        FOREIGN KEY(user_id)
+            REFERENCES local_user(id),
+    CONSTRAINT fk_recipe
+        FOREIGN KEY(recipe_id)
+            REFERENCES recipe(id)
+            ON DELETE CASCADE
+);
+
+CREATE TABLE plan_template (
+    id serial PRIMARY KEY,
+    user_id INT NOT NULL,
+    name VARCHAR(200) NOT NULL,
+
+    CONSTRAINT fk_user
+        FOREIGN KEY(user_id)
+            REFERENCES local_user(id),
+    UNIQUE (user_id, name)
+);
+
+CREATE TABLE plan_template_slot (
+    template_id INT NOT NULL,
+    day INT NOT NULL,
+    meal VARCHAR(20) NOT NULL,
+    recipe_id INT NOT NULL,
+
+    PRIMARY KEY (template_id, day, meal),
+    CONSTRAINT fk_template
+        FOREIGN KEY(template_id)
+            REFERENCES plan_template(id)
+            ON DELETE CASCADE,
+    CONSTRAINT fk_recipe
+        FOREIGN KEY(recipe_id)
+            REFERENCES recipe(id)
+            ON DELETE CASCADE
+);
+
+CREATE TABLE plan_rule (
+    id serial PRIMARY KEY,
+    user_id INT NOT NULL,
+    day INT NOT NULL,
+    meal VARCHAR(20) NOT NULL,
+    recipe_id INT NOT NULL,
+
+    CONSTRAINT fk_user
+        FOREIGN KEY(user_id)
+            REFERENCES local_user(id),
+    CONSTRAINT fk_recipe
+        FOREIGN KEY(recipe_id)
+            REFERENCES recipe(id)
+            ON DELETE CASCADE,
+    UNIQUE (user_id, day, meal)
+);
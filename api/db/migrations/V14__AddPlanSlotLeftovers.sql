@@ -0,0 +1,5 @@
+ALTER TABLE plan_slot ADD COLUMN portions INT NOT NULL DEFAULT 0;
+ALTER TABLE plan_slot ADD COLUMN leftover_day INT;
+ALTER TABLE plan_slot ADD COLUMN leftover_meal VARCHAR(20);
+ALTER TABLE plan_template_slot ADD COLUMN portions INT NOT NULL DEFAULT 0;
+ALTER TABLE plan_rule ADD COLUMN portions INT NOT NULL DEFAULT 0;
@@ -0,0 +1,45 @@
+-- V11 told apart recipe names differing only in case by adding the
+-- duplicate's id, e.g. "soup (2)". They are the same recipe, so merge each
+-- into the first saved, as merging recipes does: it keeps its name,
+-- ingredients and method and gains their cook log, ratings and places in
+-- plans
+CREATE TEMPORARY TABLE recipe_duplicate AS
+SELECT dup.id AS duplicate_id, MIN(survivor.id) AS survivor_id
+FROM recipe dup
+JOIN recipe survivor ON survivor.user_id = dup.user_id
+    AND survivor.id < dup.id
+    AND LOWER(dup.name) = LOWER(SUBSTR(survivor.name, 1, 180)) || ' (' || dup.id || ')'
+GROUP BY dup.id;
+
+UPDATE cook_log
+SET recipe_id = (SELECT survivor_id FROM recipe_duplicate WHERE duplicate_id = cook_log.recipe_id)
+WHERE recipe_id IN (SELECT duplicate_id FROM recipe_duplicate);
+
+INSERT INTO recipe_rating (recipe_id, user_id, rating, favourite)
+SELECT d.survivor_id, rr.user_id, MAX(rr.rating), MAX(CASE WHEN rr.favourite THEN 1 ELSE 0 END) = 1
+FROM recipe_rating rr
+JOIN recipe_duplicate d ON d.duplicate_id = rr.recipe_id
+GROUP BY d.survivor_id, rr.user_id
+ON CONFLICT (recipe_id, user_id) DO UPDATE SET
+    rating = CASE WHEN recipe_rating.rating = 0 THEN excluded.rating ELSE recipe_rating.rating END,
+    favourite = recipe_rating.favourite OR excluded.favourite;
+
+UPDATE plan_slot
+SET recipe_id = (SELECT survivor_id FROM recipe_duplicate WHERE duplicate_id = plan_slot.recipe_id)
+WHERE recipe_id IN (SELECT duplicate_id FROM recipe_duplicate);
+
+UPDATE plan_template_slot
+SET recipe_id = (SELECT survivor_id FROM recipe_duplicate WHERE duplicate_id = plan_template_slot.recipe_id)
+WHERE recipe_id IN (SELECT duplicate_id FROM recipe_duplicate);
+
+UPDATE plan_rule
+SET recipe_id = (SELECT survivor_id FROM recipe_duplicate WHERE duplicate_id = plan_rule.recipe_id)
+WHERE recipe_id IN (SELECT duplicate_id FROM recipe_duplicate);
+
+UPDATE recipe
+SET last_cooked_at = (SELECT MAX(cooked_at) FROM cook_log WHERE recipe_id = recipe.id)
+WHERE id IN (SELECT survivor_id FROM recipe_duplicate);
+
+DELETE FROM recipe WHERE id IN (SELECT duplicate_id FROM recipe_duplicate);
+
+DROP TABLE recipe_duplicate;
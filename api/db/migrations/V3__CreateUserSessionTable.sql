@@ -0,0 +1,15 @@
+CREATE TABLE user_session (
+    id VARCHAR(64) PRIMARY KEY,
+    user_id INT NOT NULL,
+    user_agent VARCHAR(500) NOT NULL,
+    ip VARCHAR(45) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    last_seen_at TIMESTAMP WITH TIME ZONE NOT NULL,
+
+    CONSTRAINT fk_user
+        FOREIGN KEY(user_id)
+            REFERENCES local_user(id)
+);
+
+CREATE INDEX user_session__user_id
+    ON user_session (user_id);
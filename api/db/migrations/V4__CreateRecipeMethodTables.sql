@@ -0,0 +1,43 @@
+CREATE TABLE recipe_ingredient (
+    id serial PRIMARY KEY,
+    recipe_id INT NOT NULL,
+    position INT NOT NULL,
+    name VARCHAR(200) NOT NULL,
+    quantity DOUBLE PRECISION NOT NULL DEFAULT 0,
+    unit VARCHAR(50) NOT NULL DEFAULT '',
+
+    CONSTRAINT fk_recipe
+        FOREIGN KEY(recipe_id)
+            REFERENCES recipe(id)
+            ON DELETE CASCADE,
+    UNIQUE (recipe_id, position)
+);
+
+CREATE TABLE recipe_step (
+    id serial PRIMARY KEY,
+    recipe_id INT NOT NULL,
+    position INT NOT NULL,
+    instruction TEXT NOT NULL,
+    duration_seconds INT NOT NULL DEFAULT 0,
+
+    CONSTRAINT fk_recipe
+        FOREIGN KEY(recipe_id)
+            REFERENCES recipe(id)
+            ON DELETE CASCADE,
+    UNIQUE (recipe_id, position)
+);
+
+CREATE TABLE recipe_step_ingredient (
+    recipe_step_id INT NOT NULL,
+    recipe_ingredient_id INT NOT NULL,
+
+    CONSTRAINT fk_recipe_step
+        FOREIGN KEY(recipe_step_id)
+            REFERENCES recipe_step(id)
+            ON DELETE CASCADE,
+    CONSTRAINT fk_recipe_ingredient
+        FOREIGN KEY(recipe_ingredient_id)
+            REFERENCES recipe_ingredient(id)
+            ON DELETE CASCADE,
+    PRIMARY KEY (recipe_step_id, recipe_ingredient_id)
+);
@@ -0,0 +1 @@
+ALTER TABLE recipe ADD COLUMN servings INT NOT NULL DEFAULT 0;
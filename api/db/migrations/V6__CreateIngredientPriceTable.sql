@@ -0,0 +1,14 @@
+CREATE TABLE ingredient_price (
+    id serial PRIMARY KEY,
+    user_id INT NOT NULL,
+    ingredient VARCHAR(200) NOT NULL,
+    store VARCHAR(200) NOT NULL DEFAULT '',
+    price INT NOT NULL,
+    quantity DOUBLE PRECISION NOT NULL,
+    unit VARCHAR(50) NOT NULL DEFAULT '',
+
+    CONSTRAINT fk_user
+        FOREIGN KEY(user_id)
+            REFERENCES local_user(id),
+    UNIQUE (user_id, ingredient, store)
+);
@@ -0,0 +1,17 @@
+CREATE TABLE cook_log (
+    id serial PRIMARY KEY,
+    recipe_id INT NOT NULL,
+    cooked_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    rating INT NOT NULL DEFAULT 0,
+    notes TEXT NOT NULL DEFAULT '',
+
+    CONSTRAINT fk_recipe
+        FOREIGN KEY(recipe_id)
+            REFERENCES recipe(id)
+            ON DELETE CASCADE
+);
+
+CREATE INDEX cook_log__recipe_id
+    ON cook_log (recipe_id, cooked_at);
+
+ALTER TABLE recipe ADD COLUMN last_cooked_at TIMESTAMP WITH TIME ZONE;
@@ -0,0 +1,30 @@
+/* Package migrations embeds the database schema migrations */
+package migrations
+
+import (
+	"embed"
+	"io/fs"
+)
+
+var (
+	//go:embed *.sql
+	postgres embed.FS
+
+	//go:embed sqlite/*.sql
+	sqlite embed.FS
+)
+
+// Postgres and SQLite hold the Flyway-style V<version>__<description>.sql
+// migration files for each database. Every migration must be added to both.
+var (
+	Postgres fs.FS = postgres
+	SQLite   fs.FS = mustSub(sqlite, "sqlite")
+)
+
+func mustSub(fsys fs.FS, dir string) fs.FS {
+	sub, err := fs.Sub(fsys, dir)
+	if err != nil {
+		panic(err)
+	}
+	return sub
+}
@@ -0,0 +1,8 @@
+CREATE TABLE weekly_budget (
+    user_id INT PRIMARY KEY,
+    amount INT NOT NULL CHECK (amount >= 0),
+
+    CONSTRAINT fk_user
+        FOREIGN KEY(user_id)
+            REFERENCES local_user(id)
+);
@@ -0,0 +1,8 @@
+CREATE TABLE local_user (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    email VARCHAR(200) UNIQUE NOT NULL CHECK (length(email) <= 200),
+    name VARCHAR(200) NOT NULL CHECK (length(name) <= 200)
+);
+
+CREATE UNIQUE INDEX local_user__email
+    ON local_user (email);
@@ -0,0 +1,12 @@
+CREATE TABLE recipe (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name VARCHAR(200) NOT NULL CHECK (length(name) <= 200),
+    user_id INT,
+
+    CONSTRAINT fk_user
+        FOREIGN KEY(user_id)
+            REFERENCES local_user(id)
+);
+
+CREATE INDEX recipe__user_id
+    ON recipe (user_id);
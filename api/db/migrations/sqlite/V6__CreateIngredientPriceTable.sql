@@ -0,0 +1,14 @@
+CREATE TABLE ingredient_price (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INT NOT NULL,
+    ingredient VARCHAR(200) NOT NULL CHECK (length(ingredient) <= 200),
+    store VARCHAR(200) NOT NULL DEFAULT '' CHECK (length(store) <= 200),
+    price INT NOT NULL,
+    quantity REAL NOT NULL,
+    unit VARCHAR(50) NOT NULL DEFAULT '' CHECK (length(unit) <= 50),
+
+    CONSTRAINT fk_user
+        FOREIGN KEY(user_id)
+            REFERENCES local_user(id),
+    UNIQUE (user_id, ingredient, store)
+);
@@ -0,0 +1,15 @@
+CREATE TABLE recipe_rating (
+    recipe_id INT NOT NULL,
+    user_id INT NOT NULL,
+    rating INT NOT NULL DEFAULT 0,
+    favourite BOOLEAN NOT NULL DEFAULT FALSE,
+
+    PRIMARY KEY (recipe_id, user_id),
+    CONSTRAINT fk_recipe
+        FOREIGN KEY(recipe_id)
+            REFERENCES recipe(id)
+            ON DELETE CASCADE,
+    CONSTRAINT fk_user
+        FOREIGN KEY(user_id)
+            REFERENCES local_user(id)
+);
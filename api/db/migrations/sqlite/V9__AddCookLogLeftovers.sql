@@ -0,0 +1 @@
+ALTER TABLE cook_log ADD COLUMN leftovers INT NOT NULL DEFAULT 0;
@@ -0,0 +1,132 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/kieron-pivotal/menu-planner-app/models"
+)
+
+type PlanStore struct {
+	sqlDB DB
+}
+
+func NewPlanStore(sqlDB DB) *PlanStore {
+	return &PlanStore{
+		sqlDB: sqlDB,
+	}
+}
+
+// Get returns the user's plan for the week, which has no slots if nothing
+// is planned
+func (s *PlanStore) Get(ctx context.Context, userID int, week string) (models.Plan, error) {
+	plan := models.Plan{UserID: userID, Week: week, Slots: []models.Slot{}}
+
+	rows, err := conn(ctx, s.sqlDB).QueryContext(ctx, `
+SELECT day, meal, recipe_id, portions, leftover_day, leftover_meal
+FROM plan_slot
+WHERE user_id = $1 AND week = $2`, userID, week)
+	if err != nil {
+		return models.Plan{}, fmt.Errorf("get-plan failed %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			slot         models.Slot
+			leftoverDay  sql.NullInt64
+			leftoverMeal sql.NullString
+		)
+		if err = rows.Scan(&slot.Day, &slot.Meal, &slot.RecipeID, &slot.Portions, &leftoverDay, &leftoverMeal); err != nil {
+			return models.Plan{}, fmt.Errorf("get-plan failed %w", err)
+		}
+		if leftoverDay.Valid {
+			slot.LeftoverOf = &models.SlotRef{Day: int(leftoverDay.Int64), Meal: models.Meal(leftoverMeal.String)}
+		}
+		plan.Slots = append(plan.Slots, slot)
+	}
+	if err = rows.Err(); err != nil {
+		return models.Plan{}, fmt.Errorf("get-plan failed %w", err)
+	}
+
+	models.SortSlots(plan.Slots)
+
+	return plan, nil
+}
+
+// Weeks returns the user's plans for the weeks from one Monday to
+// another, inclusive, in order. Weeks with nothing planned are left out.
+func (s *PlanStore) Weeks(ctx context.Context, userID int, from, to string) ([]models.Plan, error) {
+	plans := []models.Plan{}
+
+	rows, err := conn(ctx, s.sqlDB).QueryContext(ctx, `
+SELECT week, day, meal, recipe_id, portions, leftover_day, leftover_meal
+FROM plan_slot
+WHERE user_id = $1 AND week >= $2 AND week <= $3
+ORDER BY week`, userID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("get-plan-weeks failed %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			week         string
+			slot         models.Slot
+			leftoverDay  sql.NullInt64
+			leftoverMeal sql.NullString
+		)
+		if err = rows.Scan(&week, &slot.Day, &slot.Meal, &slot.RecipeID, &slot.Portions, &leftoverDay, &leftoverMeal); err != nil {
+			return nil, fmt.Errorf("get-plan-weeks failed %w", err)
+		}
+		if leftoverDay.Valid {
+			slot.LeftoverOf = &models.SlotRef{Day: int(leftoverDay.Int64), Meal: models.Meal(leftoverMeal.String)}
+		}
+		if len(plans) == 0 || plans[len(plans)-1].Week != week {
+			plans = append(plans, models.Plan{UserID: userID, Week: week, Slots: []models.Slot{}})
+		}
+		plans[len(plans)-1].Slots = append(plans[len(plans)-1].Slots, slot)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("get-plan-weeks failed %w", err)
+	}
+
+	for _, plan := range plans {
+		models.SortSlots(plan.Slots)
+	}
+
+	return plans, nil
+}
+
+// Save replaces the user's plan for the week. Run it in a unit of work so
+// that a plan isn't left half written if it fails.
+func (s *PlanStore) Save(ctx context.Context, plan models.Plan) error {
+	_, err := conn(ctx, s.sqlDB).ExecContext(ctx, `
+DELETE FROM plan_slot
+WHERE user_id = $1 AND week = $2`, plan.UserID, plan.Week)
+	if err != nil {
+		return fmt.Errorf("save-plan failed %w", err)
+	}
+
+	for _, slot := range plan.Slots {
+		var (
+			leftoverDay  sql.NullInt64
+			leftoverMeal sql.NullString
+		)
+		if slot.LeftoverOf != nil {
+			leftoverDay = sql.NullInt64{Int64: int64(slot.LeftoverOf.Day), Valid: true}
+			leftoverMeal = sql.NullString{String: string(slot.LeftoverOf.Meal), Valid: true}
+		}
+
+		_, err = conn(ctx, s.sqlDB).ExecContext(ctx, `
+INSERT INTO plan_slot (user_id, week, day, meal, recipe_id, portions, leftover_day, leftover_meal)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+			plan.UserID, plan.Week, slot.Day, slot.Meal, slot.RecipeID, slot.Portions, leftoverDay, leftoverMeal)
+		if err != nil {
+			return fmt.Errorf("save-plan failed %w", err)
+		}
+	}
+
+	return nil
+}
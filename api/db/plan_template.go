@@ -0,0 +1,231 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/kieron-pivotal/menu-planner-app/models"
+)
+
+// errTemplateNameTaken is returned when a user already has a plan
+// template with the name
+var errTemplateNameTaken = errors.New("plan template name already used")
+
+// PlanTemplateStore keeps the week layouts a user reuses: named templates
+// and the rules which plan a recipe for a slot every week
+type PlanTemplateStore struct {
+	sqlDB DB
+}
+
+func NewPlanTemplateStore(sqlDB DB) *PlanTemplateStore {
+	return &PlanTemplateStore{
+		sqlDB: sqlDB,
+	}
+}
+
+func (s *PlanTemplateStore) IsNotFoundErr(err error) bool {
+	return err == errNotFound
+}
+
+func (s *PlanTemplateStore) IsDuplicateErr(err error) bool {
+	return errors.Is(err, errTemplateNameTaken)
+}
+
+// ListTemplates returns the user's templates with their slots, by name
+func (s *PlanTemplateStore) ListTemplates(ctx context.Context, userID int) ([]models.PlanTemplate, error) {
+	res := []models.PlanTemplate{}
+
+	rows, err := conn(ctx, s.sqlDB).QueryContext(ctx, `
+SELECT id, name
+FROM plan_template
+WHERE user_id = $1
+ORDER BY name`, userID)
+	if err != nil {
+		return res, fmt.Errorf("list-plan-templates failed %w", err)
+	}
+	defer rows.Close()
+
+	byID := map[int]*models.PlanTemplate{}
+	for rows.Next() {
+		template := models.PlanTemplate{UserID: userID, Slots: []models.Slot{}}
+		if err = rows.Scan(&template.ID, &template.Name); err != nil {
+			return res, fmt.Errorf("list-plan-templates failed %w", err)
+		}
+		res = append(res, template)
+	}
+	if err = rows.Err(); err != nil {
+		return res, fmt.Errorf("list-plan-templates failed %w", err)
+	}
+	for i := range res {
+		byID[res[i].ID] = &res[i]
+	}
+
+	slots, err := conn(ctx, s.sqlDB).QueryContext(ctx, `
+SELECT t.id, ts.day, ts.meal, ts.recipe_id, ts.portions
+FROM plan_template_slot ts
+JOIN plan_template t ON t.id = ts.template_id
+WHERE t.user_id = $1`, userID)
+	if err != nil {
+		return res, fmt.Errorf("list-plan-templates failed %w", err)
+	}
+	defer slots.Close()
+
+	for slots.Next() {
+		var (
+			id   int
+			slot models.Slot
+		)
+		if err = slots.Scan(&id, &slot.Day, &slot.Meal, &slot.RecipeID, &slot.Portions); err != nil {
+			return res, fmt.Errorf("list-plan-templates failed %w", err)
+		}
+		byID[id].Slots = append(byID[id].Slots, slot)
+	}
+	if err = slots.Err(); err != nil {
+		return res, fmt.Errorf("list-plan-templates failed %w", err)
+	}
+
+	for _, template := range res {
+		models.SortSlots(template.Slots)
+	}
+
+	return res, nil
+}
+
+// GetTemplate returns one of the user's templates with its slots
+func (s *PlanTemplateStore) GetTemplate(ctx context.Context, userID, id int) (models.PlanTemplate, error) {
+	templates, err := s.ListTemplates(ctx, userID)
+	if err != nil {
+		return models.PlanTemplate{}, err
+	}
+
+	for _, template := range templates {
+		if template.ID == id {
+			return template, nil
+		}
+	}
+
+	return models.PlanTemplate{}, errNotFound
+}
+
+// InsertTemplate adds a template with its slots. Run it in a unit of work
+// so that a template isn't left half written if it fails.
+func (s *PlanTemplateStore) InsertTemplate(ctx context.Context, template models.PlanTemplate) (models.PlanTemplate, error) {
+	err := conn(ctx, s.sqlDB).QueryRowContext(ctx, `
+INSERT INTO plan_template (user_id, name)
+VALUES ($1, $2)
+RETURNING id`, template.UserID, template.Name).Scan(&template.ID)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return models.PlanTemplate{}, fmt.Errorf("insert-plan-template failed %w", errTemplateNameTaken)
+		}
+		return models.PlanTemplate{}, fmt.Errorf("insert-plan-template failed %w", err)
+	}
+
+	for _, slot := range template.Slots {
+		_, err = conn(ctx, s.sqlDB).ExecContext(ctx, `
+INSERT INTO plan_template_slot (template_id, day, meal, recipe_id, portions)
+VALUES ($1, $2, $3, $4, $5)`, template.ID, slot.Day, slot.Meal, slot.RecipeID, slot.Portions)
+		if err != nil {
+			return models.PlanTemplate{}, fmt.Errorf("insert-plan-template failed %w", err)
+		}
+	}
+
+	return template, nil
+}
+
+func (s *PlanTemplateStore) DeleteTemplate(ctx context.Context, userID, id int) error {
+	res, err := conn(ctx, s.sqlDB).ExecContext(ctx, `
+DELETE FROM plan_template
+WHERE user_id = $1
+AND id = $2`, userID, id)
+	if err != nil {
+		return fmt.Errorf("delete-plan-template failed %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("delete-plan-template failed %w", err)
+	}
+
+	if n == 0 {
+		return errNotFound
+	}
+
+	return nil
+}
+
+// ListRules returns the user's rules in slot order
+func (s *PlanTemplateStore) ListRules(ctx context.Context, userID int) ([]models.PlanRule, error) {
+	res := []models.PlanRule{}
+
+	rows, err := conn(ctx, s.sqlDB).QueryContext(ctx, `
+SELECT id, day, meal, recipe_id, portions
+FROM plan_rule
+WHERE user_id = $1`, userID)
+	if err != nil {
+		return res, fmt.Errorf("list-plan-rules failed %w", err)
+	}
+	defer rows.Close()
+
+	slots := []models.Slot{}
+	ids := map[models.Slot]int{}
+	for rows.Next() {
+		var (
+			id   int
+			slot models.Slot
+		)
+		if err = rows.Scan(&id, &slot.Day, &slot.Meal, &slot.RecipeID, &slot.Portions); err != nil {
+			return res, fmt.Errorf("list-plan-rules failed %w", err)
+		}
+		slots = append(slots, slot)
+		ids[slot] = id
+	}
+	if err = rows.Err(); err != nil {
+		return res, fmt.Errorf("list-plan-rules failed %w", err)
+	}
+
+	models.SortSlots(slots)
+	for _, slot := range slots {
+		res = append(res, models.PlanRule{ID: ids[slot], UserID: userID, Slot: slot})
+	}
+
+	return res, nil
+}
+
+// SaveRule plans a recipe for a slot every week, replacing any rule the
+// user has for the slot
+func (s *PlanTemplateStore) SaveRule(ctx context.Context, rule models.PlanRule) (models.PlanRule, error) {
+	err := conn(ctx, s.sqlDB).QueryRowContext(ctx, `
+INSERT INTO plan_rule (user_id, day, meal, recipe_id, portions)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (user_id, day, meal) DO UPDATE
+SET recipe_id = excluded.recipe_id, portions = excluded.portions
+RETURNING id`, rule.UserID, rule.Day, rule.Meal, rule.RecipeID, rule.Portions).Scan(&rule.ID)
+	if err != nil {
+		return models.PlanRule{}, fmt.Errorf("save-plan-rule failed %w", err)
+	}
+
+	return rule, nil
+}
+
+func (s *PlanTemplateStore) DeleteRule(ctx context.Context, userID, id int) error {
+	res, err := conn(ctx, s.sqlDB).ExecContext(ctx, `
+DELETE FROM plan_rule
+WHERE user_id = $1
+AND id = $2`, userID, id)
+	if err != nil {
+		return fmt.Errorf("delete-plan-rule failed %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("delete-plan-rule failed %w", err)
+	}
+
+	if n == 0 {
+		return errNotFound
+	}
+
+	return nil
+}
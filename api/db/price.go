@@ -0,0 +1,85 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kieron-pivotal/menu-planner-app/models"
+)
+
+type PriceStore struct {
+	sqlDB DB
+}
+
+func NewPriceStore(sqlDB DB) *PriceStore {
+	return &PriceStore{
+		sqlDB: sqlDB,
+	}
+}
+
+func (s *PriceStore) IsNotFoundErr(err error) bool {
+	return err == errNotFound
+}
+
+func (s *PriceStore) List(ctx context.Context, userID int) ([]models.Price, error) {
+	res := []models.Price{}
+
+	rows, err := conn(ctx, s.sqlDB).QueryContext(ctx, `
+SELECT id, ingredient, store, price, quantity, unit
+FROM ingredient_price
+WHERE user_id = $1
+ORDER BY ingredient, store
+`, userID)
+	if err != nil {
+		return res, fmt.Errorf("list-prices failed %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		price := models.Price{UserID: userID}
+		if err = rows.Scan(&price.ID, &price.Ingredient, &price.Store, &price.Price, &price.Quantity, &price.Unit); err != nil {
+			return res, fmt.Errorf("list-prices failed %w", err)
+		}
+
+		res = append(res, price)
+	}
+
+	return res, rows.Err()
+}
+
+// Save records a price, replacing any the user has for the same ingredient
+// at the same store
+func (s *PriceStore) Save(ctx context.Context, price models.Price) (models.Price, error) {
+	err := conn(ctx, s.sqlDB).QueryRowContext(ctx, `
+INSERT INTO ingredient_price (user_id, ingredient, store, price, quantity, unit)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (user_id, ingredient, store) DO UPDATE
+SET price = excluded.price, quantity = excluded.quantity, unit = excluded.unit
+RETURNING id`, price.UserID, price.Ingredient, price.Store, price.Price, price.Quantity, price.Unit).Scan(&price.ID)
+	if err != nil {
+		return models.Price{}, fmt.Errorf("save-price failed %w", err)
+	}
+
+	return price, nil
+}
+
+func (s *PriceStore) Delete(ctx context.Context, userID, id int) error {
+	res, err := conn(ctx, s.sqlDB).ExecContext(ctx, `
+DELETE FROM ingredient_price
+WHERE user_id = $1
+AND id = $2`, userID, id)
+	if err != nil {
+		return fmt.Errorf("delete-price failed %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("delete-price failed %w", err)
+	}
+
+	if n == 0 {
+		return errNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,34 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kieron-pivotal/menu-planner-app/models"
+)
+
+type RatingStore struct {
+	sqlDB DB
+}
+
+func NewRatingStore(sqlDB DB) *RatingStore {
+	return &RatingStore{
+		sqlDB: sqlDB,
+	}
+}
+
+// Rate records what a user thinks of a recipe, replacing their previous
+// rating
+func (s *RatingStore) Rate(ctx context.Context, rating models.Rating) (models.Rating, error) {
+	_, err := conn(ctx, s.sqlDB).ExecContext(ctx, `
+INSERT INTO recipe_rating (recipe_id, user_id, rating, favourite)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (recipe_id, user_id)
+DO UPDATE SET rating = excluded.rating, favourite = excluded.favourite`,
+		rating.RecipeID, rating.UserID, rating.Rating, rating.Favourite)
+	if err != nil {
+		return models.Rating{}, fmt.Errorf("rate-recipe failed %w", err)
+	}
+
+	return rating, nil
+}
@@ -1,17 +1,14 @@
 package db
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 
 	"github.com/kieron-pivotal/menu-planner-app/models"
 )
 
-type DB interface {
-	Query(string, ...interface{}) (*sql.Rows, error)
-	QueryRow(string, ...interface{}) *sql.Row
-}
-
 type RecipeStore struct {
 	sqlDB DB
 }
@@ -22,17 +19,30 @@ func NewRecipeStore(sqlDB DB) *RecipeStore {
 	}
 }
 
+// errNameTaken is returned when a user already has a recipe with the
+// name, ignoring case
+var errNameTaken = errors.New("recipe name already used")
+
 func (s *RecipeStore) IsNotFoundErr(err error) bool {
 	return err == errNotFound
 }
 
-func (s *RecipeStore) List(userID int) ([]models.Recipe, error) {
+func (s *RecipeStore) IsDuplicateErr(err error) bool {
+	return errors.Is(err, errNameTaken)
+}
+
+func (s *RecipeStore) List(ctx context.Context, userID int) ([]models.Recipe, error) {
 	res := []models.Recipe{}
 
-	rows, err := s.sqlDB.Query(`
-SELECT id, name
-FROM recipe
-WHERE user_id = $1
+	rows, err := conn(ctx, s.sqlDB).QueryContext(ctx, `
+SELECT r.id, r.name, r.last_cooked_at,
+    AVG(NULLIF(rr.rating, 0)), MIN(NULLIF(rr.rating, 0)),
+    MAX(CASE WHEN rr.user_id = $1 AND rr.favourite THEN 1 ELSE 0 END)
+FROM recipe r
+LEFT JOIN recipe_rating rr ON rr.recipe_id = r.id
+WHERE r.user_id = $1
+GROUP BY r.id, r.name, r.last_cooked_at
+ORDER BY r.id
 `, userID)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -40,26 +50,340 @@ WHERE user_id = $1
 		}
 		return res, fmt.Errorf("list-recipes failed %w", err)
 	}
+	defer rows.Close()
+
+	for rows.Next() {
+		recipe := models.Recipe{UserID: userID}
+		var (
+			lastCooked sql.NullTime
+			score      sql.NullFloat64
+			lowest     sql.NullInt64
+			favourite  int
+		)
+		if err = rows.Scan(&recipe.ID, &recipe.Name, &lastCooked, &score, &lowest, &favourite); err != nil {
+			return res, fmt.Errorf("list-recipes failed %w", err)
+		}
+		if lastCooked.Valid {
+			recipe.LastCookedAt = &lastCooked.Time
+		}
+		recipe.Score = score.Float64
+		recipe.LowestRating = int(lowest.Int64)
+		recipe.Favourite = favourite == 1
+
+		res = append(res, recipe)
+	}
+
+	return res, nil
+}
+
+// Get returns a user's recipe with its ingredients and steps
+func (s *RecipeStore) Get(ctx context.Context, userID, id int) (models.Recipe, error) {
+	recipes, err := s.GetMany(ctx, userID, []int{id})
+	if err != nil {
+		return models.Recipe{}, fmt.Errorf("get-recipe failed %w", err)
+	}
+	if len(recipes) == 0 {
+		return models.Recipe{}, errNotFound
+	}
+
+	return recipes[0], nil
+}
+
+// GetMany returns the user's recipes with the given ids, with their
+// ingredients and steps, in id order. Ids which aren't the user's recipes
+// are left out. It makes the same few queries however many ids there are.
+func (s *RecipeStore) GetMany(ctx context.Context, userID int, ids []int) ([]models.Recipe, error) {
+	res := []models.Recipe{}
+	if len(ids) == 0 {
+		return res, nil
+	}
+
+	args := []interface{}{userID}
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	rows, err := conn(ctx, s.sqlDB).QueryContext(ctx, `
+SELECT id, name, servings, last_cooked_at
+FROM recipe
+WHERE user_id = $1
+AND id IN (`+placeholders(2, len(ids))+`)
+ORDER BY id
+`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("get-recipes failed %w", err)
+	}
+	defer rows.Close()
 
 	for rows.Next() {
-		recipe := models.Recipe{}
-		rows.Scan(&recipe.ID, &recipe.Name)
+		recipe := models.Recipe{UserID: userID, Ingredients: []models.Ingredient{}, Steps: []models.Step{}}
+		var lastCooked sql.NullTime
+		if err = rows.Scan(&recipe.ID, &recipe.Name, &recipe.Servings, &lastCooked); err != nil {
+			return nil, fmt.Errorf("get-recipes failed %w", err)
+		}
+		if lastCooked.Valid {
+			recipe.LastCookedAt = &lastCooked.Time
+		}
 
 		res = append(res, recipe)
 	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("get-recipes failed %w", err)
+	}
+	if len(res) == 0 {
+		return res, nil
+	}
+
+	byID := map[int]*models.Recipe{}
+	found := []interface{}{}
+	for i := range res {
+		byID[res[i].ID] = &res[i]
+		found = append(found, res[i].ID)
+	}
+
+	if err = s.ingredients(ctx, byID, found); err != nil {
+		return nil, fmt.Errorf("get-recipes failed %w", err)
+	}
+	if err = s.steps(ctx, byID, found); err != nil {
+		return nil, fmt.Errorf("get-recipes failed %w", err)
+	}
+	if err = s.ratings(ctx, byID, userID, found); err != nil {
+		return nil, fmt.Errorf("get-recipes failed %w", err)
+	}
 
 	return res, nil
 }
 
-func (s *RecipeStore) Insert(recipe models.Recipe) (models.Recipe, error) {
-	row := s.sqlDB.QueryRow(`INSERT INTO recipe
-    (name, user_id)
-    VALUES ($1, $2)
-    RETURNING (id)`, recipe.Name, recipe.UserID)
+// ratings sets the recipes' scores, lowest ratings and whether the user
+// has marked them as favourites
+func (s *RecipeStore) ratings(ctx context.Context, byID map[int]*models.Recipe, userID int, ids []interface{}) error {
+	rows, err := conn(ctx, s.sqlDB).QueryContext(ctx, `
+SELECT recipe_id, AVG(NULLIF(rating, 0)), MIN(NULLIF(rating, 0)),
+    MAX(CASE WHEN user_id = $1 AND favourite THEN 1 ELSE 0 END)
+FROM recipe_rating
+WHERE recipe_id IN (`+placeholders(2, len(ids))+`)
+GROUP BY recipe_id
+`, append([]interface{}{userID}, ids...)...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			recipeID  int
+			score     sql.NullFloat64
+			lowest    sql.NullInt64
+			favourite int
+		)
+		if err = rows.Scan(&recipeID, &score, &lowest, &favourite); err != nil {
+			return err
+		}
+
+		recipe := byID[recipeID]
+		recipe.Score = score.Float64
+		recipe.LowestRating = int(lowest.Int64)
+		recipe.Favourite = favourite == 1
+	}
+
+	return rows.Err()
+}
+
+func (s *RecipeStore) ingredients(ctx context.Context, byID map[int]*models.Recipe, ids []interface{}) error {
+	rows, err := conn(ctx, s.sqlDB).QueryContext(ctx, `
+SELECT recipe_id, name, quantity, unit
+FROM recipe_ingredient
+WHERE recipe_id IN (`+placeholders(1, len(ids))+`)
+ORDER BY recipe_id, position
+`, ids...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var recipeID int
+		ing := models.Ingredient{}
+		if err = rows.Scan(&recipeID, &ing.Name, &ing.Quantity, &ing.Unit); err != nil {
+			return err
+		}
+
+		recipe := byID[recipeID]
+		recipe.Ingredients = append(recipe.Ingredients, ing)
+	}
+
+	return rows.Err()
+}
+
+func (s *RecipeStore) steps(ctx context.Context, byID map[int]*models.Recipe, ids []interface{}) error {
+	rows, err := conn(ctx, s.sqlDB).QueryContext(ctx, `
+SELECT recipe_id, instruction, duration_seconds
+FROM recipe_step
+WHERE recipe_id IN (`+placeholders(1, len(ids))+`)
+ORDER BY recipe_id, position
+`, ids...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var recipeID int
+		step := models.Step{}
+		if err = rows.Scan(&recipeID, &step.Instruction, &step.DurationSeconds); err != nil {
+			return err
+		}
+
+		recipe := byID[recipeID]
+		recipe.Steps = append(recipe.Steps, step)
+	}
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	refs, err := conn(ctx, s.sqlDB).QueryContext(ctx, `
+SELECT s.recipe_id, s.position, i.position
+FROM recipe_step_ingredient si
+JOIN recipe_step s ON s.id = si.recipe_step_id
+JOIN recipe_ingredient i ON i.id = si.recipe_ingredient_id
+WHERE s.recipe_id IN (`+placeholders(1, len(ids))+`)
+ORDER BY s.recipe_id, s.position, i.position
+`, ids...)
+	if err != nil {
+		return err
+	}
+	defer refs.Close()
 
+	for refs.Next() {
+		var recipeID, step, ingredient int
+		if err = refs.Scan(&recipeID, &step, &ingredient); err != nil {
+			return err
+		}
+
+		steps := byID[recipeID].Steps
+		steps[step].Ingredients = append(steps[step].Ingredients, ingredient)
+	}
+
+	return refs.Err()
+}
+
+// Insert adds a recipe with its ingredients and steps. Run it in a unit of
+// work so that a recipe isn't left half written if it fails.
+func (s *RecipeStore) Insert(ctx context.Context, recipe models.Recipe) (models.Recipe, error) {
+	var existing int
+	err := conn(ctx, s.sqlDB).QueryRowContext(ctx, `
+SELECT id FROM recipe
+WHERE user_id = $1 AND LOWER(name) = LOWER($2)`, recipe.UserID, recipe.Name).Scan(&existing)
+	if err == nil {
+		return models.Recipe{}, fmt.Errorf("insert failed: %w", errNameTaken)
+	}
+	if err != sql.ErrNoRows {
+		return models.Recipe{}, fmt.Errorf("insert failed: %w", err)
+	}
+
+	row := conn(ctx, s.sqlDB).QueryRowContext(ctx, `INSERT INTO recipe
+    (name, user_id, servings)
+    VALUES ($1, $2, $3)
+    RETURNING (id)`, recipe.Name, recipe.UserID, recipe.Servings)
+
+	// a concurrent insert of the same name gets past the check above, but
+	// not the unique index
 	if err := row.Scan(&recipe.ID); err != nil {
+		if isUniqueViolation(err) {
+			return models.Recipe{}, fmt.Errorf("insert failed: %w", errNameTaken)
+		}
 		return models.Recipe{}, fmt.Errorf("insert failed: %w", err)
 	}
 
+	ingredientIDs := make([]int, len(recipe.Ingredients))
+	for i, ing := range recipe.Ingredients {
+		err := conn(ctx, s.sqlDB).QueryRowContext(ctx, `
+INSERT INTO recipe_ingredient (recipe_id, position, name, quantity, unit)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id`, recipe.ID, i, ing.Name, ing.Quantity, ing.Unit).Scan(&ingredientIDs[i])
+		if err != nil {
+			return models.Recipe{}, fmt.Errorf("insert failed: %w", err)
+		}
+	}
+
+	for i, step := range recipe.Steps {
+		var stepID int
+		err := conn(ctx, s.sqlDB).QueryRowContext(ctx, `
+INSERT INTO recipe_step (recipe_id, position, instruction, duration_seconds)
+VALUES ($1, $2, $3, $4)
+RETURNING id`, recipe.ID, i, step.Instruction, step.DurationSeconds).Scan(&stepID)
+		if err != nil {
+			return models.Recipe{}, fmt.Errorf("insert failed: %w", err)
+		}
+
+		for _, ref := range step.Ingredients {
+			if ref < 0 || ref >= len(ingredientIDs) {
+				return models.Recipe{}, fmt.Errorf("insert failed: step %d uses unknown ingredient %d", i, ref)
+			}
+
+			_, err = conn(ctx, s.sqlDB).ExecContext(ctx, `
+INSERT INTO recipe_step_ingredient (recipe_step_id, recipe_ingredient_id)
+VALUES ($1, $2)`, stepID, ingredientIDs[ref])
+			if err != nil {
+				return models.Recipe{}, fmt.Errorf("insert failed: %w", err)
+			}
+		}
+	}
+
 	return recipe, nil
 }
+
+// Merge folds a duplicate recipe into the survivor. The duplicate's cook
+// log, ratings and places in plans move to the survivor, which keeps its
+// own rating where a user rated both, and then the duplicate is deleted. Run it in a unit
+// of work so that a merge isn't left half done if it fails.
+func (s *RecipeStore) Merge(ctx context.Context, survivorID, duplicateID int) error {
+	_, err := conn(ctx, s.sqlDB).ExecContext(ctx, `
+UPDATE cook_log SET recipe_id = $1 WHERE recipe_id = $2`, survivorID, duplicateID)
+	if err != nil {
+		return fmt.Errorf("merge-recipes failed %w", err)
+	}
+
+	_, err = conn(ctx, s.sqlDB).ExecContext(ctx, `
+INSERT INTO recipe_rating (recipe_id, user_id, rating, favourite)
+SELECT CAST($1 AS INT), user_id, rating, favourite
+FROM recipe_rating
+WHERE recipe_id = $2
+ON CONFLICT (recipe_id, user_id) DO UPDATE SET
+    rating = CASE WHEN recipe_rating.rating = 0 THEN excluded.rating ELSE recipe_rating.rating END,
+    favourite = recipe_rating.favourite OR excluded.favourite`, survivorID, duplicateID)
+	if err != nil {
+		return fmt.Errorf("merge-recipes failed %w", err)
+	}
+
+	for _, table := range []string{"plan_slot", "plan_template_slot", "plan_rule"} {
+		_, err = conn(ctx, s.sqlDB).ExecContext(ctx, `
+UPDATE `+table+` SET recipe_id = $1 WHERE recipe_id = $2`, survivorID, duplicateID)
+		if err != nil {
+			return fmt.Errorf("merge-recipes failed %w", err)
+		}
+	}
+
+	_, err = conn(ctx, s.sqlDB).ExecContext(ctx, `
+UPDATE recipe
+SET last_cooked_at = (SELECT MAX(cooked_at) FROM cook_log WHERE recipe_id = $1)
+WHERE id = $1`, survivorID)
+	if err != nil {
+		return fmt.Errorf("merge-recipes failed %w", err)
+	}
+
+	res, err := conn(ctx, s.sqlDB).ExecContext(ctx, `DELETE FROM recipe WHERE id = $1`, duplicateID)
+	if err != nil {
+		return fmt.Errorf("merge-recipes failed %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("merge-recipes failed %w", err)
+	}
+
+	if n == 0 {
+		return errNotFound
+	}
+
+	return nil
+}
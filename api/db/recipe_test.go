@@ -1,6 +1,10 @@
 package db_test
 
 import (
+	"context"
+	"database/sql"
+	"strings"
+
 	"github.com/kieron-pivotal/menu-planner-app/db"
 	"github.com/kieron-pivotal/menu-planner-app/models"
 	. "github.com/onsi/ginkgo"
@@ -38,7 +42,7 @@ var _ = Describe("Recipe", func() {
 		})
 
 		JustBeforeEach(func() {
-			recipes, err = recipeStore.List(userID)
+			recipes, err = recipeStore.List(context.Background(), userID)
 		})
 
 		When("there are no recipes for the user", func() {
@@ -83,7 +87,7 @@ var _ = Describe("Recipe", func() {
 		})
 
 		JustBeforeEach(func() {
-			returnedRecipe, insertErr = recipeStore.Insert(recipe)
+			returnedRecipe, insertErr = recipeStore.Insert(context.Background(), recipe)
 		})
 
 		It("writes it to the database", func() {
@@ -101,5 +105,32 @@ var _ = Describe("Recipe", func() {
 			Expect(id).To(BeNumerically(">", 0))
 			Expect(userID).To(Equal(recipe.UserID))
 		})
+
+		When("another request saved the name, in another case, since it was checked", func() {
+			BeforeEach(func() {
+				_, err := tx.Exec(`INSERT INTO recipe (name, user_id) VALUES ('Jim Bob', 123)`)
+				Expect(err).NotTo(HaveOccurred())
+
+				recipeStore = db.NewRecipeStore(uncheckedNames{tx})
+			})
+
+			It("reports the name as taken", func() {
+				Expect(recipeStore.IsDuplicateErr(insertErr)).To(BeTrue())
+			})
+		})
 	})
 })
+
+// uncheckedNames hides a user's recipes from the check for a name before
+// an insert, as if the recipe with the name were saved just after it
+type uncheckedNames struct {
+	*sql.Tx
+}
+
+func (u uncheckedNames) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	if strings.Contains(query, "LOWER(name) = LOWER($2)") {
+		query = `SELECT id FROM recipe WHERE user_id = $1 AND name = $2 AND 1 = 0`
+	}
+
+	return u.Tx.QueryRowContext(ctx, query, args...)
+}
@@ -0,0 +1,142 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kieron-pivotal/menu-planner-app/models"
+)
+
+type SessionStore struct {
+	sqlDB DB
+}
+
+func NewSessionStore(sqlDB DB) *SessionStore {
+	return &SessionStore{
+		sqlDB: sqlDB,
+	}
+}
+
+func (s *SessionStore) IsNotFoundErr(err error) bool {
+	return err == errNotFound
+}
+
+func (s *SessionStore) Create(ctx context.Context, sess models.Session) error {
+	_, err := conn(ctx, s.sqlDB).ExecContext(ctx, `
+INSERT INTO user_session (id, user_id, user_agent, ip, created_at, last_seen_at)
+VALUES ($1, $2, $3, $4, $5, $6)`,
+		sess.ID, sess.UserID, sess.UserAgent, sess.IP, sess.CreatedAt.UTC(), sess.LastSeenAt.UTC())
+	if err != nil {
+		return fmt.Errorf("create-session failed %w", err)
+	}
+
+	return nil
+}
+
+// Touch records activity on a session at lastSeen, reporting false if the
+// session no longer exists or hasn't been seen since seenSince. Activity is
+// only written if the session was last seen before writeBefore, so a busy
+// session isn't written on every request.
+func (s *SessionStore) Touch(ctx context.Context, id string, lastSeen, seenSince, writeBefore time.Time) (bool, error) {
+	res, err := conn(ctx, s.sqlDB).ExecContext(ctx, `
+UPDATE user_session
+SET last_seen_at = $2
+WHERE id = $1
+AND last_seen_at > $3
+AND last_seen_at < $4`, id, lastSeen.UTC(), seenSince.UTC(), writeBefore.UTC())
+	if err != nil {
+		return false, fmt.Errorf("touch-session failed %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("touch-session failed %w", err)
+	}
+	if n > 0 {
+		return true, nil
+	}
+
+	var active bool
+	err = conn(ctx, s.sqlDB).QueryRowContext(ctx, `
+SELECT count(*) > 0
+FROM user_session
+WHERE id = $1
+AND last_seen_at > $2`, id, seenSince.UTC()).Scan(&active)
+	if err != nil {
+		return false, fmt.Errorf("touch-session failed %w", err)
+	}
+
+	return active, nil
+}
+
+func (s *SessionStore) List(ctx context.Context, userID int, seenSince time.Time) ([]models.Session, error) {
+	res := []models.Session{}
+
+	rows, err := conn(ctx, s.sqlDB).QueryContext(ctx, `
+SELECT id, user_id, user_agent, ip, created_at, last_seen_at
+FROM user_session
+WHERE user_id = $1
+AND last_seen_at > $2
+ORDER BY last_seen_at DESC
+`, userID, seenSince.UTC())
+	if err != nil {
+		return res, fmt.Errorf("list-sessions failed %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		sess := models.Session{}
+		if err = rows.Scan(&sess.ID, &sess.UserID, &sess.UserAgent, &sess.IP, &sess.CreatedAt, &sess.LastSeenAt); err != nil {
+			return res, fmt.Errorf("list-sessions failed %w", err)
+		}
+
+		res = append(res, sess)
+	}
+
+	return res, rows.Err()
+}
+
+func (s *SessionStore) Delete(ctx context.Context, userID int, id string) error {
+	res, err := conn(ctx, s.sqlDB).ExecContext(ctx, `
+DELETE FROM user_session
+WHERE user_id = $1
+AND id = $2`, userID, id)
+	if err != nil {
+		return fmt.Errorf("delete-session failed %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("delete-session failed %w", err)
+	}
+
+	if n == 0 {
+		return errNotFound
+	}
+
+	return nil
+}
+
+func (s *SessionStore) DeleteIdle(ctx context.Context, userID int, lastSeenBefore time.Time) error {
+	_, err := conn(ctx, s.sqlDB).ExecContext(ctx, `
+DELETE FROM user_session
+WHERE user_id = $1
+AND last_seen_at <= $2`, userID, lastSeenBefore.UTC())
+	if err != nil {
+		return fmt.Errorf("delete-idle-sessions failed %w", err)
+	}
+
+	return nil
+}
+
+func (s *SessionStore) DeleteAll(ctx context.Context, userID int) error {
+	_, err := conn(ctx, s.sqlDB).ExecContext(ctx, `
+DELETE FROM user_session
+WHERE user_id = $1`, userID)
+	if err != nil {
+		return fmt.Errorf("delete-all-sessions failed %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,134 @@
+package db_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/kieron-pivotal/menu-planner-app/db"
+	"github.com/kieron-pivotal/menu-planner-app/models"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Session", func() {
+	var (
+		sessionStore *db.SessionStore
+		now          time.Time
+		ctx          context.Context
+	)
+
+	BeforeEach(func() {
+		sessionStore = db.NewSessionStore(tx)
+		ctx = context.Background()
+		now = time.Now().Truncate(time.Second)
+
+		_, err := tx.Exec(`insert into local_user(id, name, email)
+                    VALUES (123, 'bob', 'bob@example.com'),
+                    (234, 'jim', 'jim@example.com')`)
+		Expect(err).NotTo(HaveOccurred())
+
+		for _, s := range []models.Session{
+			{ID: "a", UserID: 123, UserAgent: "firefox", IP: "10.0.0.1", CreatedAt: now.Add(-time.Hour), LastSeenAt: now.Add(-time.Minute)},
+			{ID: "b", UserID: 123, UserAgent: "chrome", IP: "10.0.0.2", CreatedAt: now.Add(-time.Hour), LastSeenAt: now.Add(-time.Hour)},
+			{ID: "c", UserID: 234, UserAgent: "safari", IP: "10.0.0.3", CreatedAt: now, LastSeenAt: now},
+		} {
+			Expect(sessionStore.Create(ctx, s)).To(Succeed())
+		}
+	})
+
+	Describe("Listing sessions", func() {
+		It("returns the user's sessions seen since the given time", func() {
+			sessions, err := sessionStore.List(ctx, 123, now.Add(-30*time.Minute))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sessions).To(HaveLen(1))
+			Expect(sessions[0].ID).To(Equal("a"))
+			Expect(sessions[0].UserAgent).To(Equal("firefox"))
+			Expect(sessions[0].IP).To(Equal("10.0.0.1"))
+			Expect(sessions[0].CreatedAt).To(BeTemporally("==", now.Add(-time.Hour)))
+			Expect(sessions[0].LastSeenAt).To(BeTemporally("==", now.Add(-time.Minute)))
+		})
+
+		It("returns the most recently seen first", func() {
+			sessions, err := sessionStore.List(ctx, 123, now.Add(-2*time.Hour))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sessions).To(HaveLen(2))
+			Expect(sessions[0].ID).To(Equal("a"))
+			Expect(sessions[1].ID).To(Equal("b"))
+		})
+	})
+
+	Describe("Touching a session", func() {
+		It("updates last seen", func() {
+			active, err := sessionStore.Touch(ctx, "a", now, now.Add(-15*time.Minute), now)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(active).To(BeTrue())
+
+			sessions, err := sessionStore.List(ctx, 123, now.Add(-time.Second))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sessions).To(HaveLen(1))
+			Expect(sessions[0].ID).To(Equal("a"))
+		})
+
+		It("doesn't write activity seen recently", func() {
+			active, err := sessionStore.Touch(ctx, "a", now, now.Add(-15*time.Minute), now.Add(-2*time.Minute))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(active).To(BeTrue())
+
+			sessions, err := sessionStore.List(ctx, 123, now.Add(-time.Second))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sessions).To(BeEmpty())
+		})
+
+		It("reports sessions which have expired", func() {
+			active, err := sessionStore.Touch(ctx, "b", now, now.Add(-15*time.Minute), now)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(active).To(BeFalse())
+
+			sessions, err := sessionStore.List(ctx, 123, now.Add(-time.Second))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sessions).To(BeEmpty())
+		})
+
+		It("reports sessions which don't exist", func() {
+			active, err := sessionStore.Touch(ctx, "nope", now, now.Add(-15*time.Minute), now)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(active).To(BeFalse())
+		})
+	})
+
+	Describe("Deleting sessions", func() {
+		It("deletes a single session", func() {
+			Expect(sessionStore.Delete(ctx, 123, "a")).To(Succeed())
+
+			active, err := sessionStore.Touch(ctx, "a", now, now.Add(-time.Hour), now)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(active).To(BeFalse())
+		})
+
+		It("won't delete another user's session", func() {
+			err := sessionStore.Delete(ctx, 123, "c")
+			Expect(sessionStore.IsNotFoundErr(err)).To(BeTrue())
+		})
+
+		It("deletes idle sessions", func() {
+			Expect(sessionStore.DeleteIdle(ctx, 123, now.Add(-30*time.Minute))).To(Succeed())
+
+			sessions, err := sessionStore.List(ctx, 123, now.Add(-2*time.Hour))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sessions).To(HaveLen(1))
+			Expect(sessions[0].ID).To(Equal("a"))
+		})
+
+		It("deletes all of a user's sessions", func() {
+			Expect(sessionStore.DeleteAll(ctx, 123)).To(Succeed())
+
+			sessions, err := sessionStore.List(ctx, 123, now.Add(-2*time.Hour))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sessions).To(BeEmpty())
+
+			sessions, err = sessionStore.List(ctx, 234, now.Add(-2*time.Hour))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sessions).To(HaveLen(1))
+		})
+	})
+})
@@ -1,6 +1,7 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
@@ -47,10 +48,10 @@ func (s *UserStore) IsNotFoundErr(err error) bool {
 	return err == errNotFound
 }
 
-func (s *UserStore) FindByEmail(email string) (models.User, error) {
+func (s *UserStore) FindByEmail(ctx context.Context, email string) (models.User, error) {
 	var e, name string
 	var id int
-	err := s.sqlDB.QueryRow(`
+	err := conn(ctx, s.sqlDB).QueryRowContext(ctx, `
 SELECT id, email, name
 FROM local_user
 WHERE email = $1
@@ -68,9 +69,9 @@ WHERE email = $1
 	}, nil
 }
 
-func (s *UserStore) Create(email, name string) (models.User, error) {
+func (s *UserStore) Create(ctx context.Context, email, name string) (models.User, error) {
 	var id int
-	err := s.sqlDB.QueryRow(`
+	err := conn(ctx, s.sqlDB).QueryRowContext(ctx, `
 INSERT INTO local_user (email, name)
 VALUES ($1, $2)
 RETURNING id`, email, name).Scan(&id)
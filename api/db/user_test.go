@@ -1,6 +1,7 @@
 package db_test
 
 import (
+	"context"
 	"strings"
 
 	. "github.com/onsi/ginkgo"
@@ -28,7 +29,7 @@ var _ = Describe("User", func() {
 
 	Context("FindByEmail", func() {
 		JustBeforeEach(func() {
-			user, err = store.FindByEmail(email)
+			user, err = store.FindByEmail(context.Background(), email)
 		})
 
 		When("a user with the email exists in the DB", func() {
@@ -56,7 +57,7 @@ RETURNING id`, email, name).Scan(&id)
 
 	Context("Create", func() {
 		JustBeforeEach(func() {
-			user, err = store.Create(email, name)
+			user, err = store.Create(context.Background(), email, name)
 		})
 
 		When("all goes well", func() {
@@ -0,0 +1,87 @@
+/* Package demo seeds sample data for demo mode */
+package demo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kieron-pivotal/menu-planner-app/handlers"
+	"github.com/kieron-pivotal/menu-planner-app/models"
+)
+
+// Recipes are the sample recipes every demo user starts with
+var Recipes = []models.Recipe{
+	{
+		Name:     "Spaghetti bolognese",
+		Servings: 4,
+		Ingredients: []models.Ingredient{
+			{Name: "spaghetti", Quantity: 400, Unit: "g"},
+			{Name: "beef mince", Quantity: 500, Unit: "g"},
+			{Name: "onion", Quantity: 1},
+			{Name: "chopped tomatoes", Quantity: 400, Unit: "g"},
+		},
+		Steps: []models.Step{
+			{Instruction: "Fry the onion until soft", DurationSeconds: 5 * 60, Ingredients: []int{2}},
+			{Instruction: "Add the mince and brown it", DurationSeconds: 5 * 60, Ingredients: []int{1}},
+			{Instruction: "Add the tomatoes and simmer", DurationSeconds: 30 * 60, Ingredients: []int{3}},
+			{Instruction: "Cook the spaghetti and serve with the sauce", DurationSeconds: 10 * 60, Ingredients: []int{0}},
+		},
+	},
+	{Name: "Chicken curry"},
+	{Name: "Vegetable stir fry"},
+	{Name: "Fish pie"},
+	{Name: "Chilli con carne"},
+	{
+		Name:     "Mushroom risotto",
+		Servings: 4,
+		Ingredients: []models.Ingredient{
+			{Name: "risotto rice", Quantity: 300, Unit: "g"},
+			{Name: "mushrooms", Quantity: 250, Unit: "g"},
+			{Name: "vegetable stock", Quantity: 1, Unit: "l"},
+			{Name: "parmesan", Quantity: 50, Unit: "g"},
+		},
+		Steps: []models.Step{
+			{Instruction: "Fry the mushrooms", DurationSeconds: 5 * 60, Ingredients: []int{1}},
+			{Instruction: "Stir in the rice", Ingredients: []int{0}},
+			{Instruction: "Add the stock a ladle at a time, stirring", DurationSeconds: 20 * 60, Ingredients: []int{2}},
+			{Instruction: "Stir in the parmesan", Ingredients: []int{3}},
+		},
+	},
+	{Name: "Roast chicken"},
+	{Name: "Lentil soup"},
+}
+
+// UserStore gives each user it creates the sample recipes, so that anyone
+// logging in to a demo has something to look at
+type UserStore struct {
+	handlers.UserStore
+	recipeStore handlers.RecipeStore
+}
+
+func NewUserStore(userStore handlers.UserStore, recipeStore handlers.RecipeStore) *UserStore {
+	return &UserStore{
+		UserStore:   userStore,
+		recipeStore: recipeStore,
+	}
+}
+
+func (s *UserStore) Create(ctx context.Context, email, name string) (models.User, error) {
+	user, err := s.UserStore.Create(ctx, email, name)
+	if err != nil {
+		return user, err
+	}
+
+	return user, Seed(ctx, s.recipeStore, user.ID())
+}
+
+// Seed adds the sample recipes for a user
+func Seed(ctx context.Context, recipeStore handlers.RecipeStore, userID int) error {
+	for _, recipe := range Recipes {
+		recipe.UserID = userID
+		if _, err := recipeStore.Insert(ctx, recipe); err != nil {
+			return fmt.Errorf("seed failed %w", err)
+		}
+	}
+
+	return nil
+}
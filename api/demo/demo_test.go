@@ -0,0 +1,61 @@
+package demo_test
+
+import (
+	"context"
+	"errors"
+
+	"github.com/kieron-pivotal/menu-planner-app/demo"
+	"github.com/kieron-pivotal/menu-planner-app/handlers/handlersfakes"
+	"github.com/kieron-pivotal/menu-planner-app/memstore"
+	"github.com/kieron-pivotal/menu-planner-app/models"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Demo", func() {
+	var (
+		memDB       *memstore.DB
+		recipeStore *memstore.RecipeStore
+		userStore   *demo.UserStore
+		ctx         context.Context
+	)
+
+	BeforeEach(func() {
+		memDB = memstore.New()
+		recipeStore = memstore.NewRecipeStore(memDB)
+		userStore = demo.NewUserStore(memstore.NewUserStore(memDB), recipeStore)
+		ctx = context.Background()
+	})
+
+	It("gives new users the sample recipes", func() {
+		user, err := userStore.Create(ctx, "demo@example.com", "Demo")
+		Expect(err).NotTo(HaveOccurred())
+
+		recipes, err := recipeStore.List(ctx, user.ID())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(recipes).To(HaveLen(len(demo.Recipes)))
+		Expect(recipes[0].Name).To(Equal(demo.Recipes[0].Name))
+
+		recipe, err := recipeStore.Get(ctx, user.ID(), recipes[0].ID)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(recipe.Steps).To(Equal(demo.Recipes[0].Steps))
+	})
+
+	It("finds users with the underlying store", func() {
+		created, err := userStore.Create(ctx, "demo@example.com", "Demo")
+		Expect(err).NotTo(HaveOccurred())
+
+		found, err := userStore.FindByEmail(ctx, "demo@example.com")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(found.ID()).To(Equal(created.ID()))
+	})
+
+	It("fails if the recipes can't be added", func() {
+		failingRecipes := new(handlersfakes.FakeRecipeStore)
+		failingRecipes.InsertReturns(models.Recipe{}, errors.New("boom"))
+		userStore = demo.NewUserStore(memstore.NewUserStore(memDB), failingRecipes)
+
+		_, err := userStore.Create(ctx, "demo@example.com", "Demo")
+		Expect(err).To(MatchError(ContainSubstring("seed failed boom")))
+	})
+})
@@ -0,0 +1,99 @@
+/*
+Package events passes changes to a user's data to each of their connected
+clients. A Hub does so within one server; db.EventBus shares events
+between replicas with Postgres LISTEN/NOTIFY.
+*/
+package events
+
+import (
+	"context"
+	"sync"
+
+	"github.com/kieron-pivotal/menu-planner-app/models"
+)
+
+// bufferSize is how many events a subscriber may fall behind by before it
+// is dropped
+const bufferSize = 16
+
+type subscriber chan models.Event
+
+// Hub delivers events to the subscribers in this process
+type Hub struct {
+	mu          sync.Mutex
+	subscribers map[int]map[subscriber]struct{}
+}
+
+func NewHub() *Hub {
+	return &Hub{
+		subscribers: map[int]map[subscriber]struct{}{},
+	}
+}
+
+// Subscribe returns a channel of the user's events and a func to stop
+// them. The channel is closed when the subscriber stops, or if it falls
+// too far behind, in which case it should fetch what it shows again and
+// subscribe again.
+func (h *Hub) Subscribe(userID int) (<-chan models.Event, func()) {
+	s := make(subscriber, bufferSize)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.subscribers[userID] == nil {
+		h.subscribers[userID] = map[subscriber]struct{}{}
+	}
+	h.subscribers[userID][s] = struct{}{}
+
+	return s, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+
+		h.remove(userID, s)
+	}
+}
+
+// Publish sends an event to the user's subscribers without waiting for
+// them to read it
+func (h *Hub) Publish(_ context.Context, userID int, event models.Event) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for s := range h.subscribers[userID] {
+		h.send(userID, s, event)
+	}
+
+	return nil
+}
+
+// Broadcast sends an event to every subscriber
+func (h *Hub) Broadcast(event models.Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for userID, subscribers := range h.subscribers {
+		for s := range subscribers {
+			h.send(userID, s, event)
+		}
+	}
+}
+
+func (h *Hub) send(userID int, s subscriber, event models.Event) {
+	select {
+	case s <- event:
+	default:
+		h.remove(userID, s)
+	}
+}
+
+func (h *Hub) remove(userID int, s subscriber) {
+	if _, ok := h.subscribers[userID][s]; !ok {
+		return
+	}
+
+	delete(h.subscribers[userID], s)
+	if len(h.subscribers[userID]) == 0 {
+		delete(h.subscribers, userID)
+	}
+	close(s)
+}
@@ -0,0 +1,88 @@
+package events_test
+
+import (
+	"context"
+
+	"github.com/kieron-pivotal/menu-planner-app/events"
+	"github.com/kieron-pivotal/menu-planner-app/models"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Hub", func() {
+	var (
+		hub     *events.Hub
+		cooked  models.Event
+		created models.Event
+	)
+
+	BeforeEach(func() {
+		hub = events.NewHub()
+		cooked = models.Event{Type: models.RecipeCooked, RecipeID: 3}
+		created = models.Event{Type: models.RecipeCreated, RecipeID: 4}
+	})
+
+	It("sends events to each of the user's subscribers", func() {
+		phone, stopPhone := hub.Subscribe(1)
+		defer stopPhone()
+		laptop, stopLaptop := hub.Subscribe(1)
+		defer stopLaptop()
+
+		Expect(hub.Publish(context.Background(), 1, cooked)).To(Succeed())
+		Expect(hub.Publish(context.Background(), 1, created)).To(Succeed())
+
+		Expect(phone).To(Receive(Equal(cooked)))
+		Expect(phone).To(Receive(Equal(created)))
+		Expect(laptop).To(Receive(Equal(cooked)))
+		Expect(laptop).To(Receive(Equal(created)))
+	})
+
+	It("doesn't send events to other users", func() {
+		other, stop := hub.Subscribe(2)
+		defer stop()
+
+		Expect(hub.Publish(context.Background(), 1, cooked)).To(Succeed())
+
+		Expect(other).NotTo(Receive())
+	})
+
+	It("closes the channel when the subscriber stops", func() {
+		events, stop := hub.Subscribe(1)
+		stop()
+		stop()
+
+		Expect(events).To(BeClosed())
+		Expect(hub.Publish(context.Background(), 1, cooked)).To(Succeed())
+	})
+
+	It("drops subscribers which fall too far behind", func() {
+		slow, stopSlow := hub.Subscribe(1)
+		defer stopSlow()
+		fast, stopFast := hub.Subscribe(1)
+		defer stopFast()
+
+		for i := 0; i < 20; i++ {
+			Expect(hub.Publish(context.Background(), 1, cooked)).To(Succeed())
+			Eventually(fast).Should(Receive())
+		}
+
+		received := 0
+		for range slow {
+			received++
+		}
+		Expect(received).To(Equal(16))
+		Expect(fast).NotTo(BeClosed())
+	})
+
+	It("broadcasts to every user", func() {
+		one, stopOne := hub.Subscribe(1)
+		defer stopOne()
+		two, stopTwo := hub.Subscribe(2)
+		defer stopTwo()
+
+		hub.Broadcast(models.Event{Type: models.Resync})
+
+		Expect(one).To(Receive(Equal(models.Event{Type: models.Resync})))
+		Expect(two).To(Receive(Equal(models.Event{Type: models.Resync})))
+	})
+})
@@ -0,0 +1,267 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// execution runs a validated operation breadth first: every field on a
+// level of the query is resolved before any Thunk is called, so that a
+// Loader fetches all of a level's keys together
+type execution struct {
+	ctx  context.Context
+	doc  *document
+	errs []*Error
+}
+
+// job is an object whose fields are still to be resolved into out
+type job struct {
+	object     *Object
+	source     interface{}
+	selections []selection
+	out        *result
+	path       []interface{}
+}
+
+// resolved is a field resolved on a level, perhaps to a Thunk
+type resolved struct {
+	job    job
+	key    string
+	def    *Field
+	fields []*field
+	value  interface{}
+	err    error
+}
+
+func (e *execution) run(query *Object, selections []selection) *result {
+	data := &result{}
+	level := []job{{object: query, selections: selections, out: data}}
+
+	for len(level) > 0 {
+		values := []resolved{}
+		for _, j := range level {
+			for _, c := range collect(e.doc, j.object, j.selections) {
+				// the key is set now so the response keeps the query's order
+				j.out.set(c.key, nil)
+
+				f := c.fields[0]
+				if f.name == "__typename" {
+					j.out.set(c.key, j.object.Name)
+					continue
+				}
+
+				def := j.object.Fields[f.name]
+				value, err := e.resolve(def, j.source, f)
+				values = append(values, resolved{job: j, key: c.key, def: def, fields: c.fields, value: value, err: err})
+			}
+		}
+
+		next := []job{}
+		for _, r := range values {
+			value, err := r.value, r.err
+			if thunk, ok := value.(Thunk); ok && err == nil {
+				value, err = thunk()
+			}
+
+			path := appendPath(r.job.path, r.key)
+			if err == nil {
+				value, err = e.complete(r.def.Type, value, subselections(r.fields), path, &next)
+			}
+			if err != nil {
+				e.errs = append(e.errs, &Error{Message: err.Error(), Locations: []Location{r.fields[0].loc}, Path: path})
+				value = nil
+			}
+			r.job.out.set(r.key, value)
+		}
+		level = next
+	}
+
+	return data
+}
+
+func (e *execution) resolve(def *Field, source interface{}, f *field) (interface{}, error) {
+	if def.Resolve == nil {
+		return structField(source, f.name), nil
+	}
+	return def.Resolve(e.ctx, source, f.values)
+}
+
+// complete turns a resolved value into JSON for its type, queueing
+// objects as jobs for the next level
+func (e *execution) complete(t Type, value interface{}, selections []selection, path []interface{}, next *[]job) (interface{}, error) {
+	if n, ok := t.(*NonNull); ok {
+		res, err := e.complete(n.Of, value, selections, path, next)
+		if err == nil && res == nil {
+			err = fmt.Errorf("%s must not be null", t)
+		}
+		return res, err
+	}
+	if isNil(value) {
+		return nil, nil
+	}
+
+	switch t := t.(type) {
+	case *Scalar:
+		res, ok := t.Serialize(value)
+		if !ok {
+			return nil, fmt.Errorf("%v isn't a valid %s", value, t)
+		}
+		return res, nil
+	case *Object:
+		out := &result{}
+		*next = append(*next, job{object: t, source: value, selections: selections, out: out, path: path})
+		return out, nil
+	case *List:
+		items := reflect.ValueOf(value)
+		if items.Kind() != reflect.Slice {
+			return nil, fmt.Errorf("%T isn't a list", value)
+		}
+		res := make([]interface{}, items.Len())
+		for i := range res {
+			var err error
+			if res[i], err = e.complete(t.Of, items.Index(i).Interface(), selections, appendPath(path, i), next); err != nil {
+				return nil, err
+			}
+		}
+		return res, nil
+	}
+	return nil, fmt.Errorf("unknown type %s", t)
+}
+
+// collected is the fields asked for with a response key
+type collected struct {
+	key    string
+	fields []*field
+}
+
+// collect lists the fields selected on an object, merging those with the
+// same key and expanding fragments
+func collect(doc *document, obj *Object, selections []selection) []*collected {
+	res := []*collected{}
+	byKey := map[string]*collected{}
+
+	var walk func(selections []selection, spread map[string]bool)
+	walk = func(selections []selection, spread map[string]bool) {
+		for _, s := range selections {
+			switch s := s.(type) {
+			case *field:
+				c, ok := byKey[s.key()]
+				if !ok {
+					c = &collected{key: s.key()}
+					byKey[c.key] = c
+					res = append(res, c)
+				}
+				c.fields = append(c.fields, s)
+			case *inlineFragment:
+				walk(s.selections, spread)
+			case *fragmentSpread:
+				if !spread[s.name] {
+					spread[s.name] = true
+					walk(doc.fragments[s.name].selections, spread)
+				}
+			}
+		}
+	}
+	walk(selections, map[string]bool{})
+
+	return res
+}
+
+// subselections merges the selections of fields with the same key
+func subselections(fields []*field) []selection {
+	if len(fields) == 1 {
+		return fields[0].selections
+	}
+	res := []selection{}
+	for _, f := range fields {
+		res = append(res, f.selections...)
+	}
+	return res
+}
+
+// structField is the value of the field of a struct, or a struct pointed
+// to, whose JSON name is name, or nil if there isn't one
+func structField(source interface{}, name string) interface{} {
+	v := reflect.ValueOf(source)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		jsonName := strings.Split(sf.Tag.Get("json"), ",")[0]
+		if jsonName == name || jsonName == "" && sf.Name == name {
+			return v.Field(i).Interface()
+		}
+	}
+	return nil
+}
+
+func isNil(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface, reflect.Func:
+		return v.IsNil()
+	}
+	return false
+}
+
+func appendPath(path []interface{}, key interface{}) []interface{} {
+	res := make([]interface{}, len(path), len(path)+1)
+	copy(res, path)
+	return append(res, key)
+}
+
+// result is an object in the response, whose keys keep the order they
+// were asked for in
+type result struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func (r *result) set(key string, value interface{}) {
+	if r.values == nil {
+		r.values = map[string]interface{}{}
+	}
+	if _, ok := r.values[key]; !ok {
+		r.keys = append(r.keys, key)
+	}
+	r.values[key] = value
+}
+
+func (r *result) MarshalJSON() ([]byte, error) {
+	buf := &bytes.Buffer{}
+	buf.WriteByte('{')
+	for i, key := range r.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		k, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		v, err := json.Marshal(r.values[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,329 @@
+/*
+Package graphql answers GraphQL queries against a schema defined in Go. It
+supports what the web app needs: queries with variables, aliases and
+fragments. Mutations, subscriptions, directives, input objects and
+introspection other than __typename are not supported.
+
+Fields are resolved a level of the query at a time, so resolvers on one
+level can queue loads with a Loader and have them fetched together when the
+first of their Thunks is called.
+
+It is written here rather than taken from a GraphQL library because the API
+needs only this small, read-only subset, the module keeps its dependencies
+to the few it has, and the batching is built into the executor rather than
+added as a separate dataloader. A handler only defines Objects, Fields and
+resolvers, so moving to a library later would touch the schema in
+handlers/graphql_handler.go and not the stores behind it.
+*/
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Type is a Scalar, an Object, or a List or NonNull of another type
+type Type interface {
+	String() string
+}
+
+// Scalar is a leaf type. Coerce turns an argument's value, decoded from
+// JSON variables or written in the query, into its Go value, and
+// Serialize turns a resolved value into JSON; both report false for
+// values of the wrong type.
+type Scalar struct {
+	Name      string
+	Coerce    func(v interface{}) (interface{}, bool)
+	Serialize func(v interface{}) (interface{}, bool)
+}
+
+func (s *Scalar) String() string {
+	return s.Name
+}
+
+// Object is a type with fields
+type Object struct {
+	Name   string
+	Fields Fields
+}
+
+func (o *Object) String() string {
+	return o.Name
+}
+
+// List is a list of another type
+type List struct {
+	Of Type
+}
+
+func (l *List) String() string {
+	return "[" + l.Of.String() + "]"
+}
+
+// NonNull is a type which can't be null. A non-null field resolving to
+// null is an error, and the field is null in the response.
+type NonNull struct {
+	Of Type
+}
+
+func (n *NonNull) String() string {
+	return n.Of.String() + "!"
+}
+
+// ListOf returns a list of t
+func ListOf(t Type) *List {
+	return &List{Of: t}
+}
+
+// NonNullOf returns t, not null
+func NonNullOf(t Type) *NonNull {
+	return &NonNull{Of: t}
+}
+
+type Fields map[string]*Field
+
+// Field is a field of an object
+type Field struct {
+	Type Type
+	Args Args
+	// Resolve returns the field's value from its parent's. When nil, the
+	// value is the parent struct's field with the field's name as its
+	// JSON name.
+	Resolve Resolver
+}
+
+type Args map[string]*Arg
+
+// Arg is an argument of a field. It is required if its type is NonNull
+// and it has no Default.
+type Arg struct {
+	Type    Type
+	Default interface{}
+}
+
+// Resolver returns a field's value from its parent's value, the source,
+// and the field's arguments. The value may be a Thunk, which is called
+// once every field on the same level of the query has been resolved.
+type Resolver func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error)
+
+// Thunk returns a value which is loaded later, see Loader
+type Thunk func() (interface{}, error)
+
+// Schema is the types a query can ask for, starting from the Query type
+type Schema struct {
+	Query *Object
+}
+
+// Request is a query as sent by GraphQL clients
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// Response is the result of a query. Data is left out if the query
+// couldn't be run at all.
+type Response struct {
+	Data   interface{} `json:"data,omitempty"`
+	Errors []*Error    `json:"errors,omitempty"`
+}
+
+// Error is a problem with the query, or with resolving one of its fields,
+// whose path is then given
+type Error struct {
+	Message   string        `json:"message"`
+	Locations []Location    `json:"locations,omitempty"`
+	Path      []interface{} `json:"path,omitempty"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Location is where in a query an error is
+type Location struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// Execute runs the query in the request. Errors in the query are reported
+// in the response rather than returned.
+func Execute(ctx context.Context, schema *Schema, req Request) *Response {
+	doc, err := parse(req.Query)
+	if err != nil {
+		return &Response{Errors: []*Error{err}}
+	}
+
+	op, err := doc.operation(req.OperationName)
+	if err != nil {
+		return &Response{Errors: []*Error{err}}
+	}
+
+	if errs := validate(schema, doc, op, req.Variables); len(errs) > 0 {
+		return &Response{Errors: errs}
+	}
+
+	e := &execution{ctx: ctx, doc: doc}
+	data := e.run(schema.Query, op.selections)
+
+	return &Response{Data: data, Errors: e.errs}
+}
+
+// Built in scalars. IDs are strings in Go, whether written as strings or
+// numbers, and integers and strings serialize as IDs.
+var (
+	Int = &Scalar{
+		Name: "Int",
+		Coerce: func(v interface{}) (interface{}, bool) {
+			switch n := v.(type) {
+			case int:
+				return n, true
+			case float64:
+				if n == float64(int32(n)) {
+					return int(n), true
+				}
+			}
+			return nil, false
+		},
+		Serialize: func(v interface{}) (interface{}, bool) {
+			switch n := v.(type) {
+			case int:
+				return n, true
+			case int64:
+				return n, true
+			}
+			return nil, false
+		},
+	}
+	Float = &Scalar{
+		Name: "Float",
+		Coerce: func(v interface{}) (interface{}, bool) {
+			switch n := v.(type) {
+			case int:
+				return float64(n), true
+			case float64:
+				return n, true
+			}
+			return nil, false
+		},
+		Serialize: func(v interface{}) (interface{}, bool) {
+			switch n := v.(type) {
+			case int:
+				return float64(n), true
+			case float64:
+				return n, true
+			}
+			return nil, false
+		},
+	}
+	String = &Scalar{
+		Name: "String",
+		Coerce: func(v interface{}) (interface{}, bool) {
+			s, ok := v.(string)
+			return s, ok
+		},
+		Serialize: func(v interface{}) (interface{}, bool) {
+			s, ok := v.(string)
+			return s, ok
+		},
+	}
+	Boolean = &Scalar{
+		Name: "Boolean",
+		Coerce: func(v interface{}) (interface{}, bool) {
+			b, ok := v.(bool)
+			return b, ok
+		},
+		Serialize: func(v interface{}) (interface{}, bool) {
+			b, ok := v.(bool)
+			return b, ok
+		},
+	}
+	ID = &Scalar{
+		Name: "ID",
+		Coerce: func(v interface{}) (interface{}, bool) {
+			switch id := v.(type) {
+			case string:
+				return id, true
+			case int:
+				return strconv.Itoa(id), true
+			case float64:
+				if id == float64(int64(id)) {
+					return strconv.FormatInt(int64(id), 10), true
+				}
+			}
+			return nil, false
+		},
+		Serialize: func(v interface{}) (interface{}, bool) {
+			switch id := v.(type) {
+			case string:
+				return id, true
+			case int:
+				return strconv.Itoa(id), true
+			}
+			return nil, false
+		},
+	}
+)
+
+// coerce turns an argument's value into Go for its type, after variables
+// have been replaced
+func coerce(t Type, v interface{}) (interface{}, error) {
+	if n, ok := t.(*NonNull); ok {
+		if v == nil {
+			return nil, fmt.Errorf("expected %s, got null", t)
+		}
+		return coerce(n.Of, v)
+	}
+	if v == nil {
+		return nil, nil
+	}
+
+	switch t := t.(type) {
+	case *List:
+		items, ok := v.([]interface{})
+		if !ok {
+			// a single value is a list of one
+			item, err := coerce(t.Of, v)
+			if err != nil {
+				return nil, err
+			}
+			return []interface{}{item}, nil
+		}
+		res := make([]interface{}, len(items))
+		for i, item := range items {
+			var err error
+			if res[i], err = coerce(t.Of, item); err != nil {
+				return nil, err
+			}
+		}
+		return res, nil
+	case *Scalar:
+		if res, ok := t.Coerce(v); ok {
+			return res, nil
+		}
+		return nil, fmt.Errorf("expected %s, got %s", t, describe(v))
+	}
+	return nil, fmt.Errorf("%s can't be an argument", t)
+}
+
+// describe says what an argument's value is, for errors
+func describe(v interface{}) string {
+	switch v := v.(type) {
+	case enumValue:
+		return string(v)
+	case string:
+		return strconv.Quote(v)
+	case []interface{}:
+		return "a list"
+	case map[string]interface{}:
+		return "an object"
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return strings.TrimSpace(string(b))
+}
@@ -0,0 +1,203 @@
+package graphql_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+
+	"github.com/kieron-pivotal/menu-planner-app/graphql"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type book struct {
+	ID       int    `json:"id"`
+	Title    string `json:"title"`
+	AuthorID int    `json:"-"`
+}
+
+type author struct {
+	Name string
+}
+
+var _ = Describe("GraphQL", func() {
+	var (
+		schema  *graphql.Schema
+		fetches [][]int
+		authors *graphql.Loader
+		req     graphql.Request
+	)
+
+	BeforeEach(func() {
+		fetches = nil
+		authors = graphql.NewLoader(func(keys []int) (map[int]interface{}, error) {
+			fetches = append(fetches, keys)
+			res := map[int]interface{}{}
+			for _, key := range keys {
+				if key != 99 {
+					res[key] = author{Name: "author " + strconv.Itoa(key)}
+				}
+			}
+			return res, nil
+		})
+
+		authorType := &graphql.Object{Name: "Author", Fields: graphql.Fields{
+			"Name": {Type: graphql.String},
+		}}
+		bookType := &graphql.Object{Name: "Book", Fields: graphql.Fields{
+			"id":    {Type: graphql.ID},
+			"title": {Type: graphql.String},
+			"author": {Type: authorType, Resolve: func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+				if b, ok := source.(*book); ok {
+					return authors.Load(b.AuthorID), nil
+				}
+				return authors.Load(source.(book).AuthorID), nil
+			}},
+			"broken": {Type: graphql.String, Resolve: func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+				return nil, errors.New("it broke")
+			}},
+		}}
+		schema = &graphql.Schema{Query: &graphql.Object{Name: "Query", Fields: graphql.Fields{
+			"books": {
+				Type: graphql.ListOf(bookType),
+				Args: graphql.Args{
+					"ids":   {Type: graphql.ListOf(graphql.NonNullOf(graphql.ID))},
+					"limit": {Type: graphql.Int, Default: 10},
+				},
+				Resolve: func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+					books := []book{}
+					ids, _ := args["ids"].([]interface{})
+					for i, id := range ids {
+						if i == args["limit"].(int) {
+							break
+						}
+						n, _ := strconv.Atoi(id.(string))
+						books = append(books, book{ID: n, Title: "book " + id.(string), AuthorID: n % 3})
+					}
+					return books, nil
+				},
+			},
+			"book": {
+				Type: bookType,
+				Args: graphql.Args{"id": {Type: graphql.NonNullOf(graphql.ID)}},
+				Resolve: func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+					n, _ := strconv.Atoi(args["id"].(string))
+					return &book{ID: n, Title: "book " + args["id"].(string), AuthorID: n}, nil
+				},
+			},
+		}}}
+		req = graphql.Request{}
+	})
+
+	execute := func() string {
+		b, err := json.Marshal(graphql.Execute(context.Background(), schema, req))
+		Expect(err).NotTo(HaveOccurred())
+		return string(b)
+	}
+
+	It("answers in the order fields were asked for, with aliases", func() {
+		req.Query = `{ second: book(id: 2) { title id } first: book(id: "1") { __typename id } }`
+		Expect(execute()).To(Equal(`{"data":{"second":{"title":"book 2","id":"2"},"first":{"__typename":"Book","id":"1"}}}`))
+	})
+
+	It("fills in variables, defaults and fragments", func() {
+		req.Query = `
+			# a comment
+			query Books($ids: [ID!]!, $limit: Int = 2) {
+				books(ids: $ids, limit: $limit) { ...bookFields ... on Book { author { Name } } }
+			}
+			fragment bookFields on Book { id, title }`
+		req.Variables = map[string]interface{}{"ids": []interface{}{"4", 5.0, "6"}}
+		Expect(execute()).To(MatchJSON(`{"data": {"books": [
+			{"id": "4", "title": "book 4", "author": {"Name": "author 1"}},
+			{"id": "5", "title": "book 5", "author": {"Name": "author 2"}}
+		]}}`))
+	})
+
+	It("coerces a single value into a list", func() {
+		req.Query = `{ books(ids: 7) { id } }`
+		Expect(execute()).To(MatchJSON(`{"data": {"books": [{"id": "7"}]}}`))
+	})
+
+	It("loads each level of the query in one batch", func() {
+		req.Query = `{
+			books(ids: ["1", "2", "3", "4", "5"]) { author { Name } }
+			book(id: 99) { author { Name } }
+			again: book(id: 1) { author { Name } }
+		}`
+		Expect(execute()).To(MatchJSON(`{"data": {
+			"books": [
+				{"author": {"Name": "author 1"}}, {"author": {"Name": "author 2"}}, {"author": {"Name": "author 0"}},
+				{"author": {"Name": "author 1"}}, {"author": {"Name": "author 2"}}
+			],
+			"book": {"author": null},
+			"again": {"author": {"Name": "author 1"}}
+		}}`))
+		Expect(fetches).To(Equal([][]int{{1, 2, 0, 99}}))
+	})
+
+	It("reports field errors with their path, leaving the field null", func() {
+		req.Query = `{ books(ids: ["1", "2"]) { id broken } }`
+		Expect(execute()).To(MatchJSON(`{
+			"data": {"books": [{"id": "1", "broken": null}, {"id": "2", "broken": null}]},
+			"errors": [
+				{"message": "it broke", "locations": [{"line": 1, "column": 31}], "path": ["books", 0, "broken"]},
+				{"message": "it broke", "locations": [{"line": 1, "column": 31}], "path": ["books", 1, "broken"]}
+			]
+		}`))
+	})
+
+	It("runs the named operation", func() {
+		req.Query = `query A { book(id: 1) { id } } query B { book(id: 2) { id } }`
+		req.OperationName = "B"
+		Expect(execute()).To(MatchJSON(`{"data": {"book": {"id": "2"}}}`))
+
+		req.OperationName = ""
+		Expect(execute()).To(MatchJSON(`{"errors": [{"message": "operationName is required when the query has several operations"}]}`))
+	})
+
+	It("reports syntax errors with where they are", func() {
+		for query, message := range map[string]string{
+			"{ book(id: 1) { id }":         "syntax error: unexpected end of query",
+			"{\n  book(id: 01) { id } }":   "syntax error: numbers can't start with 0",
+			`{ book(id: "1) { id } }`:      "syntax error: unterminated string",
+			"mutation { book }":            "only queries are supported",
+			"{ book(id: 1) @skip { id } }": "directives aren't supported",
+		} {
+			req.Query = query
+			res := graphql.Execute(context.Background(), schema, req)
+			Expect(res.Data).To(BeNil(), query)
+			Expect(res.Errors).To(HaveLen(1), query)
+			Expect(res.Errors[0].Message).To(Equal(message), query)
+			Expect(res.Errors[0].Locations).To(HaveLen(1), query)
+		}
+
+		req.Query = "{\n  book(id: 01) { id } }"
+		Expect(graphql.Execute(context.Background(), schema, req).Errors[0].Locations).To(Equal([]graphql.Location{{Line: 2, Column: 12}}))
+	})
+
+	It("checks the query against the schema before running it", func() {
+		for query, message := range map[string]string{
+			"{ nope }":                                               `Query has no field "nope"`,
+			"{ book { id } }":                                        `field "book" needs argument "id"`,
+			"{ book(id: 1, id: 2) { id } }":                          `argument "id" is given twice`,
+			"{ book(id: 1, size: 2) { id } }":                        `field "book" has no argument "size"`,
+			"{ book(id: true) { id } }":                              `argument "id": expected ID, got true`,
+			"{ book(id: null) { id } }":                              `argument "id": expected ID!, got null`,
+			"{ book(id: 1) }":                                        `field "book" is Book, so fields of it must be asked for`,
+			"{ book(id: 1) { id { x } } }":                           `field "id" is ID, which has no fields`,
+			"{ book(id: $id) { id } }":                               `argument "id": variable $id isn't defined`,
+			"query ($id: ID!) { book(id: $id) { id } }":              `variable $id is required`,
+			"{ book(id: 1) { ...f } }":                               `there is no fragment named "f"`,
+			"{ book(id: 1) { ...f } } fragment f on Author { Name }": `fragment "f" on Author can't be used on Book`,
+			"{ book(id: 1) { ...f } } fragment f on Book { ...f }":   `fragment "f" uses itself`,
+		} {
+			req.Query = query
+			res := graphql.Execute(context.Background(), schema, req)
+			Expect(res.Data).To(BeNil(), query)
+			Expect(res.Errors).NotTo(BeEmpty(), query)
+			Expect(res.Errors[0].Message).To(Equal(message), query)
+		}
+	})
+})
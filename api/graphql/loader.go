@@ -0,0 +1,91 @@
+package graphql
+
+// BatchFunc fetches the values of several keys at once. Keys missing from
+// the map it returns have null values.
+type BatchFunc func(keys []int) (map[int]interface{}, error)
+
+// Loader batches loads of values by key, e.g. recipes by id, so that
+// resolving a field of every item of a list makes one fetch rather than
+// one per item. Load queues a key and returns a Thunk; the first of the
+// Thunks called fetches every key queued since the last fetch. Values are
+// kept for the Loader's life, so make one per query. A Loader isn't safe
+// for concurrent use, which the breadth first execution doesn't need.
+type Loader struct {
+	fetch  BatchFunc
+	queued []int
+	loaded map[int]bool
+	values map[int]interface{}
+	errs   map[int]error
+}
+
+func NewLoader(fetch BatchFunc) *Loader {
+	return &Loader{
+		fetch:  fetch,
+		loaded: map[int]bool{},
+		values: map[int]interface{}{},
+		errs:   map[int]error{},
+	}
+}
+
+// Load queues a key, unless it is loaded already, and returns a Thunk for
+// its value
+func (l *Loader) Load(key int) Thunk {
+	l.queue(key)
+
+	return func() (interface{}, error) {
+		l.dispatch()
+		return l.values[key], l.errs[key]
+	}
+}
+
+// LoadMany queues several keys and returns a Thunk for a []interface{} of
+// their values, in the order of the keys
+func (l *Loader) LoadMany(keys []int) Thunk {
+	for _, key := range keys {
+		l.queue(key)
+	}
+
+	return func() (interface{}, error) {
+		l.dispatch()
+
+		res := make([]interface{}, len(keys))
+		for i, key := range keys {
+			if err := l.errs[key]; err != nil {
+				return nil, err
+			}
+			res[i] = l.values[key]
+		}
+		return res, nil
+	}
+}
+
+func (l *Loader) queue(key int) {
+	if l.loaded[key] {
+		return
+	}
+	for _, queued := range l.queued {
+		if queued == key {
+			return
+		}
+	}
+	l.queued = append(l.queued, key)
+}
+
+// dispatch fetches the queued keys
+func (l *Loader) dispatch() {
+	if len(l.queued) == 0 {
+		return
+	}
+	keys := l.queued
+	l.queued = nil
+
+	values, err := l.fetch(keys)
+	for _, key := range keys {
+		l.loaded[key] = true
+		if err != nil {
+			l.errs[key] = err
+			continue
+		}
+		l.values[key] = values[key]
+	}
+}
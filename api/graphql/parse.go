@@ -0,0 +1,674 @@
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// document is a parsed query
+type document struct {
+	operations []*operation
+	fragments  map[string]*fragment
+}
+
+type operation struct {
+	name       string
+	variables  []*variableDef
+	selections []selection
+	loc        Location
+}
+
+type variableDef struct {
+	name string
+	// required is whether the variable's type is non-null
+	required   bool
+	def        interface{}
+	hasDefault bool
+	loc        Location
+}
+
+// selection is a *field, *fragmentSpread or *inlineFragment
+type selection interface{}
+
+type field struct {
+	alias, name string
+	args        []*argument
+	selections  []selection
+	loc         Location
+	// values are the arguments coerced for the field's type, set by
+	// validate
+	values map[string]interface{}
+}
+
+// key is the name of the field in the response
+func (f *field) key() string {
+	if f.alias != "" {
+		return f.alias
+	}
+	return f.name
+}
+
+type argument struct {
+	name  string
+	value interface{}
+	loc   Location
+}
+
+type fragmentSpread struct {
+	name string
+	loc  Location
+}
+
+type inlineFragment struct {
+	on         string
+	selections []selection
+	loc        Location
+}
+
+type fragment struct {
+	name, on   string
+	selections []selection
+	loc        Location
+}
+
+// Values written in a query are ints, float64s, strings, bools, nil,
+// []interface{}, or these
+type (
+	variable  string
+	enumValue string
+)
+
+// operation returns the operation to run: the one named, or the only one
+func (d *document) operation(name string) (*operation, *Error) {
+	if name == "" {
+		if len(d.operations) > 1 {
+			return nil, &Error{Message: "operationName is required when the query has several operations"}
+		}
+		return d.operations[0], nil
+	}
+
+	for _, op := range d.operations {
+		if op.name == name {
+			return op, nil
+		}
+	}
+	return nil, &Error{Message: fmt.Sprintf("no operation named %q", name)}
+}
+
+type tokenKind int
+
+const (
+	eof tokenKind = iota
+	punctuator
+	name
+	intValue
+	floatValue
+	stringValue
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	loc  Location
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case eof:
+		return "end of query"
+	case stringValue:
+		return strconv.Quote(t.text)
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// lexer splits a query into tokens, skipping white space, commas and
+// comments
+type lexer struct {
+	src  string
+	pos  int
+	line int
+	// lineStart is the position the line starts at
+	lineStart int
+}
+
+func (l *lexer) loc() Location {
+	return Location{Line: l.line, Column: utf8.RuneCountInString(l.src[l.lineStart:l.pos]) + 1}
+}
+
+func (l *lexer) errorf(loc Location, format string, args ...interface{}) *Error {
+	return &Error{Message: "syntax error: " + fmt.Sprintf(format, args...), Locations: []Location{loc}}
+}
+
+func (l *lexer) next() (token, *Error) {
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		switch {
+		case c == '\n':
+			l.pos++
+			l.line++
+			l.lineStart = l.pos
+		case c == ' ' || c == '\t' || c == '\r' || c == ',':
+			l.pos++
+		case c == '#':
+			for l.pos < len(l.src) && l.src[l.pos] != '\n' {
+				l.pos++
+			}
+		case strings.HasPrefix(l.src[l.pos:], "\uFEFF"):
+			l.pos += len("\uFEFF")
+		default:
+			return l.token()
+		}
+	}
+	return token{kind: eof, loc: l.loc()}, nil
+}
+
+func (l *lexer) token() (token, *Error) {
+	loc := l.loc()
+	start := l.pos
+	c := l.src[l.pos]
+
+	switch {
+	case strings.HasPrefix(l.src[l.pos:], "..."):
+		l.pos += 3
+		return token{kind: punctuator, text: "...", loc: loc}, nil
+	case strings.ContainsRune("!$():=@[]{|}", rune(c)):
+		l.pos++
+		return token{kind: punctuator, text: string(c), loc: loc}, nil
+	case c == '_' || isLetter(c):
+		for l.pos < len(l.src) && (l.src[l.pos] == '_' || isLetter(l.src[l.pos]) || isDigit(l.src[l.pos])) {
+			l.pos++
+		}
+		return token{kind: name, text: l.src[start:l.pos], loc: loc}, nil
+	case c == '-' || isDigit(c):
+		return l.number(loc)
+	case c == '"':
+		return l.string(loc)
+	}
+
+	r, _ := utf8.DecodeRuneInString(l.src[l.pos:])
+	return token{}, l.errorf(loc, "unexpected character %q", r)
+}
+
+func (l *lexer) number(loc Location) (token, *Error) {
+	start := l.pos
+	kind := intValue
+	if l.src[l.pos] == '-' {
+		l.pos++
+	}
+	if !l.digits() {
+		return token{}, l.errorf(loc, "expected a digit")
+	}
+	if strings.HasPrefix(l.src[start:l.pos], "0") && l.pos-start > 1 ||
+		strings.HasPrefix(l.src[start:l.pos], "-0") && l.pos-start > 2 {
+		return token{}, l.errorf(loc, "numbers can't start with 0")
+	}
+	if l.pos < len(l.src) && l.src[l.pos] == '.' {
+		kind = floatValue
+		l.pos++
+		if !l.digits() {
+			return token{}, l.errorf(loc, "expected a digit after the decimal point")
+		}
+	}
+	if l.pos < len(l.src) && (l.src[l.pos] == 'e' || l.src[l.pos] == 'E') {
+		kind = floatValue
+		l.pos++
+		if l.pos < len(l.src) && (l.src[l.pos] == '+' || l.src[l.pos] == '-') {
+			l.pos++
+		}
+		if !l.digits() {
+			return token{}, l.errorf(loc, "expected a digit in the exponent")
+		}
+	}
+	if l.pos < len(l.src) && (l.src[l.pos] == '_' || l.src[l.pos] == '.' || isLetter(l.src[l.pos])) {
+		return token{}, l.errorf(loc, "invalid number")
+	}
+	return token{kind: kind, text: l.src[start:l.pos], loc: loc}, nil
+}
+
+// digits skips digits, reporting whether there were any
+func (l *lexer) digits() bool {
+	start := l.pos
+	for l.pos < len(l.src) && isDigit(l.src[l.pos]) {
+		l.pos++
+	}
+	return l.pos > start
+}
+
+func (l *lexer) string(loc Location) (token, *Error) {
+	if strings.HasPrefix(l.src[l.pos:], `"""`) {
+		return token{}, l.errorf(loc, "block strings aren't supported")
+	}
+	l.pos++
+
+	b := &strings.Builder{}
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		switch c {
+		case '"':
+			l.pos++
+			return token{kind: stringValue, text: b.String(), loc: loc}, nil
+		case '\n', '\r':
+			return token{}, l.errorf(loc, "unterminated string")
+		case '\\':
+			if l.pos+1 >= len(l.src) {
+				return token{}, l.errorf(loc, "unterminated string")
+			}
+			esc := l.src[l.pos+1]
+			l.pos += 2
+			switch esc {
+			case '"', '\\', '/':
+				b.WriteByte(esc)
+			case 'b':
+				b.WriteByte('\b')
+			case 'f':
+				b.WriteByte('\f')
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 't':
+				b.WriteByte('\t')
+			case 'u':
+				if l.pos+4 > len(l.src) {
+					return token{}, l.errorf(loc, "invalid unicode escape")
+				}
+				r, err := strconv.ParseUint(l.src[l.pos:l.pos+4], 16, 32)
+				if err != nil {
+					return token{}, l.errorf(loc, "invalid unicode escape")
+				}
+				b.WriteRune(rune(r))
+				l.pos += 4
+			default:
+				return token{}, l.errorf(loc, "invalid escape \\%c", esc)
+			}
+		default:
+			b.WriteByte(c)
+			l.pos++
+		}
+	}
+	return token{}, l.errorf(loc, "unterminated string")
+}
+
+func isLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// parser builds a document from a query's tokens, looking one token ahead
+type parser struct {
+	lex *lexer
+	tok token
+}
+
+func parse(query string) (*document, *Error) {
+	p := &parser{lex: &lexer{src: query, line: 1}}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	doc := &document{fragments: map[string]*fragment{}}
+	for p.tok.kind != eof {
+		switch {
+		case p.peek(punctuator, "{"):
+			op := &operation{loc: p.tok.loc}
+			var err *Error
+			if op.selections, err = p.selectionSet(); err != nil {
+				return nil, err
+			}
+			doc.operations = append(doc.operations, op)
+		case p.peek(name, "query"):
+			op, err := p.operation()
+			if err != nil {
+				return nil, err
+			}
+			doc.operations = append(doc.operations, op)
+		case p.peek(name, "mutation"), p.peek(name, "subscription"):
+			return nil, &Error{Message: "only queries are supported", Locations: []Location{p.tok.loc}}
+		case p.peek(name, "fragment"):
+			f, err := p.fragment()
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := doc.fragments[f.name]; ok {
+				return nil, &Error{Message: fmt.Sprintf("there are several fragments named %q", f.name), Locations: []Location{f.loc}}
+			}
+			doc.fragments[f.name] = f
+		default:
+			return nil, p.unexpected()
+		}
+	}
+
+	if len(doc.operations) == 0 {
+		return nil, &Error{Message: "the query has no operations"}
+	}
+	return doc, nil
+}
+
+func (p *parser) advance() *Error {
+	var err *Error
+	p.tok, err = p.lex.next()
+	return err
+}
+
+// peek reports whether the next token is the one given
+func (p *parser) peek(kind tokenKind, text string) bool {
+	return p.tok.kind == kind && p.tok.text == text
+}
+
+// skip advances past the next token if it is the one given, reporting
+// whether it was
+func (p *parser) skip(kind tokenKind, text string) (bool, *Error) {
+	if !p.peek(kind, text) {
+		return false, nil
+	}
+	return true, p.advance()
+}
+
+func (p *parser) expect(kind tokenKind, text string) *Error {
+	if !p.peek(kind, text) {
+		return p.unexpected()
+	}
+	return p.advance()
+}
+
+func (p *parser) name() (string, *Error) {
+	if p.tok.kind != name {
+		return "", p.unexpected()
+	}
+	n := p.tok.text
+	return n, p.advance()
+}
+
+func (p *parser) unexpected() *Error {
+	return p.lex.errorf(p.tok.loc, "unexpected %s", p.tok)
+}
+
+func (p *parser) operation() (*operation, *Error) {
+	op := &operation{loc: p.tok.loc}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	var err *Error
+	if p.tok.kind == name {
+		if op.name, err = p.name(); err != nil {
+			return nil, err
+		}
+	}
+	if op.variables, err = p.variableDefs(); err != nil {
+		return nil, err
+	}
+	if err = p.noDirectives(); err != nil {
+		return nil, err
+	}
+	if op.selections, err = p.selectionSet(); err != nil {
+		return nil, err
+	}
+	return op, nil
+}
+
+func (p *parser) variableDefs() ([]*variableDef, *Error) {
+	if ok, err := p.skip(punctuator, "("); !ok || err != nil {
+		return nil, err
+	}
+
+	defs := []*variableDef{}
+	for {
+		def := &variableDef{loc: p.tok.loc}
+		if err := p.expect(punctuator, "$"); err != nil {
+			return nil, err
+		}
+		var err *Error
+		if def.name, err = p.name(); err != nil {
+			return nil, err
+		}
+		if err = p.expect(punctuator, ":"); err != nil {
+			return nil, err
+		}
+		if def.required, err = p.typeRef(); err != nil {
+			return nil, err
+		}
+		if def.hasDefault, err = p.skip(punctuator, "="); err != nil {
+			return nil, err
+		}
+		if def.hasDefault {
+			if def.def, err = p.value(true); err != nil {
+				return nil, err
+			}
+		}
+		defs = append(defs, def)
+
+		if ok, err := p.skip(punctuator, ")"); ok || err != nil {
+			return defs, err
+		}
+	}
+}
+
+// typeRef skips a variable's type, reporting whether it is non-null.
+// Variables are coerced for the arguments they are used in, so the rest
+// of the type isn't needed.
+func (p *parser) typeRef() (bool, *Error) {
+	if ok, err := p.skip(punctuator, "["); err != nil {
+		return false, err
+	} else if ok {
+		if _, err = p.typeRef(); err != nil {
+			return false, err
+		}
+		if err = p.expect(punctuator, "]"); err != nil {
+			return false, err
+		}
+	} else if _, err = p.name(); err != nil {
+		return false, err
+	}
+
+	return p.skip(punctuator, "!")
+}
+
+func (p *parser) fragment() (*fragment, *Error) {
+	f := &fragment{loc: p.tok.loc}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	var err *Error
+	if f.name, err = p.name(); err != nil {
+		return nil, err
+	}
+	if f.name == "on" {
+		return nil, p.lex.errorf(f.loc, "a fragment can't be named \"on\"")
+	}
+	if err = p.expect(name, "on"); err != nil {
+		return nil, err
+	}
+	if f.on, err = p.name(); err != nil {
+		return nil, err
+	}
+	if err = p.noDirectives(); err != nil {
+		return nil, err
+	}
+	if f.selections, err = p.selectionSet(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (p *parser) selectionSet() ([]selection, *Error) {
+	if err := p.expect(punctuator, "{"); err != nil {
+		return nil, err
+	}
+
+	selections := []selection{}
+	for {
+		s, err := p.selection()
+		if err != nil {
+			return nil, err
+		}
+		selections = append(selections, s)
+
+		if ok, err := p.skip(punctuator, "}"); ok || err != nil {
+			return selections, err
+		}
+	}
+}
+
+func (p *parser) selection() (selection, *Error) {
+	loc := p.tok.loc
+	if ok, err := p.skip(punctuator, "..."); err != nil {
+		return nil, err
+	} else if ok {
+		if p.tok.kind == name && p.tok.text != "on" {
+			spread := &fragmentSpread{loc: loc}
+			if spread.name, err = p.name(); err != nil {
+				return nil, err
+			}
+			return spread, p.noDirectives()
+		}
+
+		inline := &inlineFragment{loc: loc}
+		if ok, err = p.skip(name, "on"); err != nil {
+			return nil, err
+		} else if ok {
+			if inline.on, err = p.name(); err != nil {
+				return nil, err
+			}
+		}
+		if err = p.noDirectives(); err != nil {
+			return nil, err
+		}
+		if inline.selections, err = p.selectionSet(); err != nil {
+			return nil, err
+		}
+		return inline, nil
+	}
+
+	return p.field()
+}
+
+func (p *parser) field() (*field, *Error) {
+	f := &field{loc: p.tok.loc}
+
+	var err *Error
+	if f.name, err = p.name(); err != nil {
+		return nil, err
+	}
+	if ok, err := p.skip(punctuator, ":"); err != nil {
+		return nil, err
+	} else if ok {
+		f.alias = f.name
+		if f.name, err = p.name(); err != nil {
+			return nil, err
+		}
+	}
+	if f.args, err = p.arguments(); err != nil {
+		return nil, err
+	}
+	if err = p.noDirectives(); err != nil {
+		return nil, err
+	}
+	if p.peek(punctuator, "{") {
+		if f.selections, err = p.selectionSet(); err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+func (p *parser) arguments() ([]*argument, *Error) {
+	if ok, err := p.skip(punctuator, "("); !ok || err != nil {
+		return nil, err
+	}
+
+	args := []*argument{}
+	for {
+		arg := &argument{loc: p.tok.loc}
+		var err *Error
+		if arg.name, err = p.name(); err != nil {
+			return nil, err
+		}
+		if err = p.expect(punctuator, ":"); err != nil {
+			return nil, err
+		}
+		if arg.value, err = p.value(false); err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+
+		if ok, err := p.skip(punctuator, ")"); ok || err != nil {
+			return args, err
+		}
+	}
+}
+
+// value parses a value, which must be constant in variable defaults
+func (p *parser) value(constant bool) (interface{}, *Error) {
+	tok := p.tok
+	switch tok.kind {
+	case intValue:
+		n, err := strconv.Atoi(tok.text)
+		if err != nil {
+			return nil, p.lex.errorf(tok.loc, "%s is too big", tok.text)
+		}
+		return n, p.advance()
+	case floatValue:
+		f, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, p.lex.errorf(tok.loc, "%s is too big", tok.text)
+		}
+		return f, p.advance()
+	case stringValue:
+		return tok.text, p.advance()
+	case name:
+		var v interface{}
+		switch tok.text {
+		case "true":
+			v = true
+		case "false":
+			v = false
+		case "null":
+			v = nil
+		default:
+			v = enumValue(tok.text)
+		}
+		return v, p.advance()
+	}
+
+	switch {
+	case p.peek(punctuator, "$") && !constant:
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		n, err := p.name()
+		return variable(n), err
+	case p.peek(punctuator, "["):
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		items := []interface{}{}
+		for {
+			if ok, err := p.skip(punctuator, "]"); ok || err != nil {
+				return items, err
+			}
+			item, err := p.value(constant)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+	case p.peek(punctuator, "{"):
+		return nil, &Error{Message: "input objects aren't supported", Locations: []Location{tok.loc}}
+	}
+	return nil, p.unexpected()
+}
+
+func (p *parser) noDirectives() *Error {
+	if p.peek(punctuator, "@") {
+		return &Error{Message: "directives aren't supported", Locations: []Location{p.tok.loc}}
+	}
+	return nil
+}
@@ -0,0 +1,203 @@
+package graphql
+
+import (
+	"fmt"
+	"sort"
+)
+
+// validator checks an operation against the schema before it runs, and
+// coerces each field's arguments
+type validator struct {
+	schema    *Schema
+	doc       *document
+	variables map[string]interface{}
+	defs      map[string]*variableDef
+	// fragments holds whether each fragment spread is being validated, or
+	// has been, to find fragments spreading themselves
+	fragments map[string]fragmentState
+	errs      []*Error
+}
+
+type fragmentState int
+
+const (
+	unvisited fragmentState = iota
+	visiting
+	visited
+)
+
+func validate(schema *Schema, doc *document, op *operation, variables map[string]interface{}) []*Error {
+	v := &validator{
+		schema:    schema,
+		doc:       doc,
+		variables: variables,
+		defs:      map[string]*variableDef{},
+		fragments: map[string]fragmentState{},
+	}
+
+	for _, def := range op.variables {
+		if _, ok := v.defs[def.name]; ok {
+			v.errorf(def.loc, "variable $%s is defined twice", def.name)
+			continue
+		}
+		v.defs[def.name] = def
+
+		if def.required && !def.hasDefault && variables[def.name] == nil {
+			v.errorf(def.loc, "variable $%s is required", def.name)
+		}
+	}
+
+	v.selections(schema.Query, op.selections)
+
+	return v.errs
+}
+
+func (v *validator) errorf(loc Location, format string, args ...interface{}) {
+	v.errs = append(v.errs, &Error{Message: fmt.Sprintf(format, args...), Locations: []Location{loc}})
+}
+
+func (v *validator) selections(obj *Object, selections []selection) {
+	for _, s := range selections {
+		switch s := s.(type) {
+		case *field:
+			v.field(obj, s)
+		case *inlineFragment:
+			if s.on != "" && s.on != obj.Name {
+				v.errorf(s.loc, "a fragment on %s can't be used on %s", s.on, obj.Name)
+				continue
+			}
+			v.selections(obj, s.selections)
+		case *fragmentSpread:
+			f, ok := v.doc.fragments[s.name]
+			if !ok {
+				v.errorf(s.loc, "there is no fragment named %q", s.name)
+				continue
+			}
+			if f.on != obj.Name {
+				v.errorf(s.loc, "fragment %q on %s can't be used on %s", s.name, f.on, obj.Name)
+				continue
+			}
+
+			switch v.fragments[s.name] {
+			case visiting:
+				v.errorf(s.loc, "fragment %q uses itself", s.name)
+			case unvisited:
+				v.fragments[s.name] = visiting
+				v.selections(obj, f.selections)
+				v.fragments[s.name] = visited
+			}
+		}
+	}
+}
+
+func (v *validator) field(obj *Object, f *field) {
+	if f.name == "__typename" {
+		if len(f.args) > 0 || f.selections != nil {
+			v.errorf(f.loc, "__typename has no arguments or fields")
+		}
+		return
+	}
+
+	def, ok := obj.Fields[f.name]
+	if !ok {
+		v.errorf(f.loc, "%s has no field %q", obj.Name, f.name)
+		return
+	}
+
+	f.values = map[string]interface{}{}
+	invalid := map[string]bool{}
+	for _, arg := range f.args {
+		argDef, ok := def.Args[arg.name]
+		if !ok {
+			v.errorf(arg.loc, "field %q has no argument %q", f.name, arg.name)
+			continue
+		}
+		if _, ok = f.values[arg.name]; ok || invalid[arg.name] {
+			v.errorf(arg.loc, "argument %q is given twice", arg.name)
+			continue
+		}
+
+		value, present, err := v.value(arg.value)
+		if err == nil && present {
+			value, err = coerce(argDef.Type, value)
+		}
+		if err != nil {
+			v.errorf(arg.loc, "argument %q: %v", arg.name, err)
+			invalid[arg.name] = true
+			continue
+		}
+		if present {
+			f.values[arg.name] = value
+		}
+	}
+
+	names := make([]string, 0, len(def.Args))
+	for name := range def.Args {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if _, ok := f.values[name]; ok || invalid[name] {
+			continue
+		}
+		argDef := def.Args[name]
+		if argDef.Default != nil {
+			f.values[name] = argDef.Default
+		} else if _, required := argDef.Type.(*NonNull); required {
+			v.errorf(f.loc, "field %q needs argument %q", f.name, name)
+		}
+	}
+
+	switch t := named(def.Type).(type) {
+	case *Scalar:
+		if f.selections != nil {
+			v.errorf(f.loc, "field %q is %s, which has no fields", f.name, t)
+		}
+	case *Object:
+		if f.selections == nil {
+			v.errorf(f.loc, "field %q is %s, so fields of it must be asked for", f.name, t)
+			return
+		}
+		v.selections(t, f.selections)
+	}
+}
+
+// value replaces the variables in a value, reporting whether it is
+// present: a variable which wasn't given and has no default isn't
+func (v *validator) value(value interface{}) (interface{}, bool, error) {
+	switch value := value.(type) {
+	case variable:
+		def, ok := v.defs[string(value)]
+		if !ok {
+			return nil, false, fmt.Errorf("variable $%s isn't defined", value)
+		}
+		if given, ok := v.variables[def.name]; ok {
+			return given, true, nil
+		}
+		return def.def, def.hasDefault, nil
+	case []interface{}:
+		items := make([]interface{}, len(value))
+		for i, item := range value {
+			var err error
+			if items[i], _, err = v.value(item); err != nil {
+				return nil, false, err
+			}
+		}
+		return items, true, nil
+	}
+	return value, true, nil
+}
+
+// named returns the type a list or non-null type wraps
+func named(t Type) Type {
+	for {
+		switch wrapper := t.(type) {
+		case *List:
+			t = wrapper.Of
+		case *NonNull:
+			t = wrapper.Of
+		default:
+			return t
+		}
+	}
+}
@@ -2,13 +2,12 @@ package handlers
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"io/ioutil"
 	"log"
 	"net/http"
 
 	"github.com/kieron-pivotal/menu-planner-app/models"
+	"github.com/kieron-pivotal/menu-planner-app/problem"
 	"github.com/kieron-pivotal/menu-planner-app/session"
 )
 
@@ -30,8 +29,16 @@ type JWTDecoder interface {
 
 type UserStore interface {
 	IsNotFoundErr(error) bool
-	FindByEmail(email string) (models.User, error)
-	Create(email, name string) (models.User, error)
+	FindByEmail(ctx context.Context, email string) (models.User, error)
+	Create(ctx context.Context, email, name string) (models.User, error)
+}
+
+//counterfeiter:generate . Transactor
+
+// Transactor runs several store calls as one unit of work. Stores must be
+// passed the context given to fn to take part in the transaction.
+type Transactor interface {
+	InTx(ctx context.Context, fn func(ctx context.Context) error) error
 }
 
 //counterfeiter:generate . SessionManager
@@ -39,6 +46,7 @@ type UserStore interface {
 type SessionManager interface {
 	Get(ctx context.Context) (*session.AuthInfo, error)
 	Set(r *http.Request, w http.ResponseWriter, s *session.AuthInfo) error
+	IsActive(ctx context.Context, s *session.AuthInfo) (bool, error)
 }
 
 type AuthHandler struct {
@@ -46,6 +54,7 @@ type AuthHandler struct {
 	tokenVerifier  TokenVerifier
 	jwtDecoder     JWTDecoder
 	userStore      UserStore
+	transactor     Transactor
 	sessionManager SessionManager
 }
 
@@ -54,6 +63,7 @@ func NewAuthHandler(
 	tokenVerifier TokenVerifier,
 	jwtDecoder JWTDecoder,
 	userStore UserStore,
+	transactor Transactor,
 	sessionSetter SessionManager,
 ) *AuthHandler {
 	return &AuthHandler{
@@ -61,6 +71,7 @@ func NewAuthHandler(
 		tokenVerifier:  tokenVerifier,
 		jwtDecoder:     jwtDecoder,
 		userStore:      userStore,
+		transactor:     transactor,
 		sessionManager: sessionSetter,
 	}
 }
@@ -68,64 +79,80 @@ func NewAuthHandler(
 func (h *AuthHandler) AuthGoogle(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 
-	body, err := ioutil.ReadAll(r.Body)
-	if err != nil {
-		log.Printf("read-body: %v\n", err)
-		http.Error(w, "", http.StatusInternalServerError)
-		return
-	}
-
 	var authReq struct {
 		IDToken string `json:"idToken"`
 	}
 
-	err = json.Unmarshal(body, &authReq)
-	if err != nil {
-		log.Printf("json unmarshal: %v\n", err)
-		http.Error(w, "", http.StatusBadRequest)
+	if !decodeJSON(w, r, &authReq, maxBodyBytes) {
+		return
+	}
+
+	if authReq.IDToken == "" {
+		problem.WriteInvalid(w, []problem.FieldError{{Field: "idToken", Message: "is required"}})
 		return
 	}
 
-	if err = h.tokenVerifier.VerifyIDToken(authReq.IDToken, []string{h.audience}); err != nil {
+	if err := h.tokenVerifier.VerifyIDToken(authReq.IDToken, []string{h.audience}); err != nil {
 		log.Printf("token-verifier: %v\n", err)
-		http.Error(w, "", http.StatusBadRequest)
+		problem.Write(w, http.StatusBadRequest, problem.InvalidIDToken, "")
 		return
 	}
 
 	claimSet, err := h.jwtDecoder.ClaimSet(authReq.IDToken)
 	if err != nil {
 		log.Printf("jwt-decoder: %v\n", err)
-		http.Error(w, "", http.StatusBadRequest)
+		problem.Write(w, http.StatusBadRequest, problem.InvalidIDToken, "")
 		return
 	}
 
 	var email, name string
 	if email, err = extractString(claimSet, "email"); err != nil {
 		log.Printf("extract-string: %v", err)
-		http.Error(w, "", http.StatusBadRequest)
+		problem.Write(w, http.StatusBadRequest, problem.InvalidIDToken, "ID token has no email")
 		return
 	}
 
-	user, err := h.userStore.FindByEmail(email)
-	if err != nil {
+	var user models.User
+	status := http.StatusOK
+	err = h.transactor.InTx(r.Context(), func(ctx context.Context) error {
+		var err error
+		user, err = h.userStore.FindByEmail(ctx, email)
+		if err == nil {
+			return nil
+		}
+
 		if !h.userStore.IsNotFoundErr(err) {
 			log.Printf("user-store-find-by-email: %v\n", err)
-			http.Error(w, "", http.StatusInternalServerError)
-			return
+			status = http.StatusInternalServerError
+			return err
 		}
 
 		if name, err = extractString(claimSet, "name"); err != nil {
 			log.Printf("extract-string: %v\n", err)
-			w.WriteHeader(http.StatusBadRequest)
-			return
+			status = http.StatusBadRequest
+			return err
 		}
 
-		user, err = h.userStore.Create(email, name)
+		user, err = h.userStore.Create(ctx, email, name)
 		if err != nil {
 			log.Printf("user-store-create: %v\n", err)
-			http.Error(w, "", http.StatusInternalServerError)
-			return
+			status = http.StatusInternalServerError
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		if status == http.StatusOK {
+			log.Printf("find-or-create-user: %v\n", err)
+			status = http.StatusInternalServerError
+		}
+		code := problem.Internal
+		if status == http.StatusBadRequest {
+			code = problem.InvalidIDToken
 		}
+		problem.Write(w, status, code, "")
+		return
 	}
 
 	sess := session.AuthInfo{
@@ -136,7 +163,7 @@ func (h *AuthHandler) AuthGoogle(w http.ResponseWriter, r *http.Request) {
 
 	if err := h.sessionManager.Set(r, w, &sess); err != nil {
 		log.Printf("failed-to-set-session: %v\n", err)
-		http.Error(w, "", http.StatusInternalServerError)
+		problem.Write(w, http.StatusInternalServerError, problem.Internal, "")
 		return
 	}
 
@@ -147,7 +174,7 @@ func (h *AuthHandler) AuthGoogle(w http.ResponseWriter, r *http.Request) {
 func (h *AuthHandler) WhoAmI(w http.ResponseWriter, r *http.Request) {
 	sess, err := h.sessionManager.Get(r.Context())
 	if err != nil || sess == nil || !sess.IsLoggedIn {
-		http.Error(w, `{"error": "unauthorized"}`, http.StatusUnauthorized)
+		problem.Write(w, http.StatusUnauthorized, problem.Unauthorized, "")
 
 		return
 	}
@@ -164,7 +191,9 @@ func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
 	sess.IsLoggedIn = false
 	if err = h.sessionManager.Set(r, w, sess); err != nil {
 		log.Printf("failed-to-set-session: %v\n", err)
-		http.Error(w, "", http.StatusInternalServerError)
+		problem.Write(w, http.StatusInternalServerError, problem.Internal, "")
+
+		return
 	}
 	fmt.Fprint(w, "logged out")
 }
@@ -2,6 +2,7 @@ package handlers_test
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"io/ioutil"
 	"log"
@@ -17,6 +18,8 @@ import (
 	. "github.com/onsi/gomega"
 )
 
+type inTx struct{}
+
 var _ = Describe("Auth", func() {
 	var (
 		httpHandlers   *handlers.AuthHandler
@@ -25,6 +28,7 @@ var _ = Describe("Auth", func() {
 		jwtDecoder     *handlersfakes.FakeJWTDecoder
 		sessionManager *handlersfakes.FakeSessionManager
 		userStore      *handlersfakes.FakeUserStore
+		transactor     *handlersfakes.FakeTransactor
 		user           *modelsfakes.FakeUser
 		recorder       *httptest.ResponseRecorder
 		req            *http.Request
@@ -46,12 +50,17 @@ var _ = Describe("Auth", func() {
 		userStore = new(handlersfakes.FakeUserStore)
 		userStore.FindByEmailReturns(user, nil)
 
+		transactor = new(handlersfakes.FakeTransactor)
+		transactor.InTxStub = func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(context.WithValue(ctx, inTx{}, true))
+		}
+
 		audience = "my.audience"
 		sessionManager = new(handlersfakes.FakeSessionManager)
-		httpHandlers = handlers.NewAuthHandler(audience, tokenVerifier, jwtDecoder, userStore, sessionManager)
+		httpHandlers = handlers.NewAuthHandler(audience, tokenVerifier, jwtDecoder, userStore, transactor, sessionManager)
 		hf = http.HandlerFunc(httpHandlers.AuthGoogle)
 		recorder = httptest.NewRecorder()
-		bodyBytes = []byte("{}")
+		bodyBytes = []byte(`{"idToken":"my.google.token"}`)
 	})
 
 	JustBeforeEach(func() {
@@ -86,7 +95,8 @@ var _ = Describe("Auth", func() {
 
 			It("tries to find user by email", func() {
 				Expect(userStore.FindByEmailCallCount()).To(Equal(1))
-				Expect(userStore.FindByEmailArgsForCall(0)).To(Equal("bob@bits.com"))
+				_, email := userStore.FindByEmailArgsForCall(0)
+				Expect(email).To(Equal("bob@bits.com"))
 			})
 
 			When("the user doesn't exist", func() {
@@ -98,7 +108,7 @@ var _ = Describe("Auth", func() {
 
 				It("creates the user", func() {
 					Expect(userStore.CreateCallCount()).To(Equal(1))
-					actualEmail, actualName := userStore.CreateArgsForCall(0)
+					_, actualEmail, actualName := userStore.CreateArgsForCall(0)
 					Expect(actualEmail).To(Equal("bob@bits.com"))
 					Expect(actualName).To(Equal("bob"))
 				})
@@ -115,6 +125,12 @@ var _ = Describe("Auth", func() {
 			It("returns an ok success status", func() {
 				Expect(recorder.Code).To(Equal(http.StatusOK))
 			})
+
+			It("finds or creates the user in a single transaction", func() {
+				Expect(transactor.InTxCallCount()).To(Equal(1))
+				txCtx, _ := userStore.FindByEmailArgsForCall(0)
+				Expect(txCtx.Value(inTx{})).To(BeTrue())
+			})
 		})
 
 		When("the body is not valid json", func() {
@@ -190,6 +206,20 @@ var _ = Describe("Auth", func() {
 			})
 		})
 
+		When("the transaction fails to commit", func() {
+			BeforeEach(func() {
+				transactor.InTxStub = func(ctx context.Context, fn func(context.Context) error) error {
+					Expect(fn(ctx)).To(Succeed())
+					return errors.New("commit failed")
+				}
+			})
+
+			It("fails with internal server error", func() {
+				Expect(recorder.Code).To(Equal(http.StatusInternalServerError))
+				Expect(sessionManager.SetCallCount()).To(BeZero())
+			})
+		})
+
 		When("setting the session fails", func() {
 			BeforeEach(func() {
 				sessionManager.SetReturns(errors.New("oops"))
@@ -214,7 +244,7 @@ var _ = Describe("Who Am I?", func() {
 	BeforeEach(func() {
 		log.SetOutput(GinkgoWriter)
 		sessionManager = new(handlersfakes.FakeSessionManager)
-		httpHandlers = handlers.NewAuthHandler("", nil, nil, nil, sessionManager)
+		httpHandlers = handlers.NewAuthHandler("", nil, nil, nil, nil, sessionManager)
 		hf = http.HandlerFunc(httpHandlers.WhoAmI)
 		recorder = httptest.NewRecorder()
 	})
@@ -260,3 +290,57 @@ var _ = Describe("Who Am I?", func() {
 		})
 	})
 })
+
+var _ = Describe("Logout", func() {
+	var (
+		httpHandlers   *handlers.AuthHandler
+		recorder       *httptest.ResponseRecorder
+		sessionManager *handlersfakes.FakeSessionManager
+	)
+
+	BeforeEach(func() {
+		log.SetOutput(GinkgoWriter)
+		sessionManager = new(handlersfakes.FakeSessionManager)
+		sessionManager.GetReturns(&session.AuthInfo{IsLoggedIn: true, Name: "forest"}, nil)
+		httpHandlers = handlers.NewAuthHandler("", nil, nil, nil, nil, sessionManager)
+		recorder = httptest.NewRecorder()
+	})
+
+	JustBeforeEach(func() {
+		req, err := http.NewRequest(http.MethodPost, "/logout", nil)
+		Expect(err).NotTo(HaveOccurred())
+		httpHandlers.Logout(recorder, req)
+	})
+
+	When("I'm logged in", func() {
+		It("logs me out", func() {
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+			Expect(recorder.Body.String()).To(Equal("logged out"))
+			_, _, sess := sessionManager.SetArgsForCall(0)
+			Expect(sess.IsLoggedIn).To(BeFalse())
+		})
+	})
+
+	When("I'm logged out", func() {
+		BeforeEach(func() {
+			sessionManager.GetReturns(&session.AuthInfo{IsLoggedIn: false}, nil)
+		})
+
+		It("does nothing", func() {
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+			Expect(sessionManager.SetCallCount()).To(BeZero())
+		})
+	})
+
+	When("setting the session fails", func() {
+		BeforeEach(func() {
+			sessionManager.SetReturns(errors.New("oops"))
+		})
+
+		It("fails with internal server error and nothing else", func() {
+			Expect(recorder.Code).To(Equal(http.StatusInternalServerError))
+			Expect(recorder.Header().Get("Content-Type")).To(Equal("application/problem+json"))
+			Expect(recorder.Body.String()).NotTo(ContainSubstring("logged out"))
+		})
+	})
+})
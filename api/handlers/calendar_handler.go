@@ -0,0 +1,242 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/kieron-pivotal/menu-planner-app/ical"
+	"github.com/kieron-pivotal/menu-planner-app/models"
+	"github.com/kieron-pivotal/menu-planner-app/problem"
+)
+
+const (
+	// calendarWeeks is how many weeks of plans the calendar feed has,
+	// starting with this one
+	calendarWeeks = 4
+	// defaultPrepTime is used for recipes without timed steps
+	defaultPrepTime = 30 * time.Minute
+	// reheatTime is how long a leftover slot's event takes, since nothing
+	// is cooked
+	reheatTime = 15 * time.Minute
+)
+
+// mealTimes are when each meal is eaten, after a day's midnight
+var mealTimes = map[models.Meal]time.Duration{
+	models.Breakfast: 8 * time.Hour,
+	models.Lunch:     12*time.Hour + 30*time.Minute,
+	models.Dinner:    18*time.Hour + 30*time.Minute,
+}
+
+//counterfeiter:generate . CalendarTokenStore
+
+type CalendarTokenStore interface {
+	IsNotFoundErr(error) bool
+	Save(ctx context.Context, userID int, tokenHash string) error
+	Delete(ctx context.Context, userID int) error
+	FindUser(ctx context.Context, tokenHash string) (int, error)
+}
+
+type CalendarHandler struct {
+	sessionManager SessionManager
+	tokenStore     CalendarTokenStore
+	recipeStore    RecipeStore
+	planStore      PlanStore
+	webURI         string
+}
+
+func NewCalendarHandler(
+	sessionManager SessionManager, tokenStore CalendarTokenStore,
+	recipeStore RecipeStore, planStore PlanStore, webURI string) *CalendarHandler {
+	return &CalendarHandler{
+		sessionManager: sessionManager,
+		tokenStore:     tokenStore,
+		recipeStore:    recipeStore,
+		planStore:      planStore,
+		webURI:         strings.TrimSuffix(webURI, "/"),
+	}
+}
+
+// IssueCalendarToken gives the user a new secret calendar feed URL. Any
+// URL issued before stops working.
+func (h *CalendarHandler) IssueCalendarToken(w http.ResponseWriter, r *http.Request) {
+	sess, err := h.sessionManager.Get(r.Context())
+	if err != nil || sess == nil || !sess.IsLoggedIn {
+		problem.Write(w, http.StatusUnauthorized, problem.Unauthorized, "")
+
+		return
+	}
+
+	b := make([]byte, 32)
+	if _, err = rand.Read(b); err != nil {
+		log.Printf("calendar-token-issue: %v\n", err)
+		problem.Write(w, http.StatusInternalServerError, problem.Internal, "")
+
+		return
+	}
+	token := hex.EncodeToString(b)
+
+	if err = h.tokenStore.Save(r.Context(), sess.ID, hashToken(token)); err != nil {
+		log.Printf("calendar-token-issue: %v\n", err)
+		problem.Write(w, http.StatusInternalServerError, problem.Internal, "")
+
+		return
+	}
+
+	w.Header().Add("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(struct {
+		Token string `json:"token"`
+		Path  string `json:"path"`
+	}{
+		Token: token,
+		Path:  "/api/v1/calendar/" + token + ".ics",
+	})
+}
+
+func (h *CalendarHandler) RevokeCalendarToken(w http.ResponseWriter, r *http.Request) {
+	sess, err := h.sessionManager.Get(r.Context())
+	if err != nil || sess == nil || !sess.IsLoggedIn {
+		problem.Write(w, http.StatusUnauthorized, problem.Unauthorized, "")
+
+		return
+	}
+
+	if err = h.tokenStore.Delete(r.Context(), sess.ID); err != nil {
+		if h.tokenStore.IsNotFoundErr(err) {
+			problem.Write(w, http.StatusNotFound, problem.NotFound, "")
+
+			return
+		}
+
+		log.Printf("calendar-token-revoke: %v\n", err)
+		problem.Write(w, http.StatusInternalServerError, problem.Internal, "")
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// CalendarFeed serves the token's user's meal plans for this week and the
+// next few as an iCalendar feed. Calendar apps can't log in, so the secret
+// token in the URL is the only authentication. Each slot is an event which
+// ends when the meal is eaten and starts when preparing it should begin.
+func (h *CalendarHandler) CalendarFeed(w http.ResponseWriter, r *http.Request) {
+	userID, err := h.tokenStore.FindUser(r.Context(), hashToken(mux.Vars(r)["token"]))
+	if err != nil {
+		if h.tokenStore.IsNotFoundErr(err) {
+			problem.Write(w, http.StatusNotFound, problem.NotFound, "")
+
+			return
+		}
+
+		log.Printf("calendar-feed: %v\n", err)
+		problem.Write(w, http.StatusInternalServerError, problem.Internal, "")
+
+		return
+	}
+
+	now := time.Now()
+	monday := now.AddDate(0, 0, -(int(now.Weekday())+6)%7)
+	plans, err := h.planStore.Weeks(r.Context(), userID,
+		monday.Format(weekFormat), monday.AddDate(0, 0, 7*(calendarWeeks-1)).Format(weekFormat))
+	if err != nil {
+		log.Printf("calendar-feed: %v\n", err)
+		problem.Write(w, http.StatusInternalServerError, problem.Internal, "")
+
+		return
+	}
+
+	ids := []int{}
+	for _, plan := range plans {
+		ids = append(ids, slotRecipes(plan.Slots)...)
+	}
+
+	recipes := map[int]models.Recipe{}
+	if len(ids) > 0 {
+		found, err := h.recipeStore.GetMany(r.Context(), userID, ids)
+		if err != nil {
+			log.Printf("calendar-feed: %v\n", err)
+			problem.Write(w, http.StatusInternalServerError, problem.Internal, "")
+
+			return
+		}
+		for _, recipe := range found {
+			recipes[recipe.ID] = recipe
+		}
+	}
+
+	cal := ical.Calendar{Name: "Meal plan", Stamp: now, Events: []ical.Event{}}
+	for _, plan := range plans {
+		start, err := time.Parse(weekFormat, plan.Week)
+		if err != nil {
+			log.Printf("calendar-feed: %v\n", err)
+			continue
+		}
+
+		for _, slot := range plan.Slots {
+			recipe, ok := recipes[slot.RecipeID]
+			if !ok {
+				continue
+			}
+			cal.Events = append(cal.Events, h.slotEvent(start, slot, recipe))
+		}
+	}
+
+	w.Header().Add("Content-Type", "text/calendar; charset=utf-8")
+
+	if err = ical.Write(w, cal); err != nil {
+		log.Printf("calendar-feed: %v\n", err)
+	}
+}
+
+// slotEvent is the event for a slot of the plan for the week starting on
+// monday. Its UID names the week and slot rather than the recipe, so a
+// calendar app moves the event on when the slot's recipe changes.
+func (h *CalendarHandler) slotEvent(monday time.Time, slot models.Slot, recipe models.Recipe) ical.Event {
+	eaten := monday.AddDate(0, 0, slot.Day).Add(mealTimes[slot.Meal])
+
+	event := ical.Event{
+		UID:      fmt.Sprintf("plan-%s-%d-%s@menu-planner", monday.Format(weekFormat), slot.Day, slot.Meal),
+		Summary:  recipe.Name,
+		URL:      fmt.Sprintf("%s/recipes/%d", h.webURI, recipe.ID),
+		Start:    eaten.Add(-prepTime(recipe)),
+		End:      eaten,
+		Floating: true,
+	}
+	if slot.LeftoverOf != nil {
+		event.Summary += " (leftovers)"
+		event.Description = fmt.Sprintf("Leftovers of %s %s", time.Weekday((slot.LeftoverOf.Day+1)%7), slot.LeftoverOf.Meal)
+		event.Start = eaten.Add(-reheatTime)
+	}
+
+	return event
+}
+
+// hashToken is what is stored in place of a calendar token, so a leaked
+// database doesn't leak feed URLs
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// prepTime is how long the recipe's timed steps take
+func prepTime(recipe models.Recipe) time.Duration {
+	total := 0
+	for _, step := range recipe.Steps {
+		total += step.DurationSeconds
+	}
+	if total == 0 {
+		return defaultPrepTime
+	}
+	return time.Duration(total) * time.Second
+}
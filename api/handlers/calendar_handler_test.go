@@ -0,0 +1,245 @@
+package handlers_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/kieron-pivotal/menu-planner-app/db"
+	"github.com/kieron-pivotal/menu-planner-app/handlers"
+	"github.com/kieron-pivotal/menu-planner-app/handlers/handlersfakes"
+	"github.com/kieron-pivotal/menu-planner-app/models"
+	"github.com/kieron-pivotal/menu-planner-app/session"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CalendarHandler", func() {
+	var (
+		sessionManager *handlersfakes.FakeSessionManager
+		tokenStore     *handlersfakes.FakeCalendarTokenStore
+		recipeStore    *handlersfakes.FakeRecipeStore
+		planStore      *handlersfakes.FakePlanStore
+		recorder       *httptest.ResponseRecorder
+		req            *http.Request
+		httpHandlers   *handlers.CalendarHandler
+	)
+
+	BeforeEach(func() {
+		sessionManager = new(handlersfakes.FakeSessionManager)
+		sessionManager.GetReturns(&session.AuthInfo{IsLoggedIn: true, Name: "forest", ID: 234}, nil)
+		tokenStore = new(handlersfakes.FakeCalendarTokenStore)
+		tokenStore.IsNotFoundErrStub = func(err error) bool { return err == db.NotFoundErr() }
+		recipeStore = new(handlersfakes.FakeRecipeStore)
+		planStore = new(handlersfakes.FakePlanStore)
+		httpHandlers = handlers.NewCalendarHandler(sessionManager, tokenStore, recipeStore, planStore, "https://menus.example.com/")
+		recorder = httptest.NewRecorder()
+	})
+
+	Describe("IssueCalendarToken", func() {
+		JustBeforeEach(func() {
+			var err error
+			req, err = http.NewRequest(http.MethodPost, "/profile/calendar-token", nil)
+			Expect(err).NotTo(HaveOccurred())
+			httpHandlers.IssueCalendarToken(recorder, req)
+		})
+
+		When("I'm logged out", func() {
+			BeforeEach(func() {
+				sessionManager.GetReturns(&session.AuthInfo{IsLoggedIn: false}, nil)
+			})
+
+			It("returns a status not auth'ed", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusUnauthorized))
+				Expect(tokenStore.SaveCallCount()).To(BeZero())
+			})
+		})
+
+		It("stores a hash of a new random token", func() {
+			Expect(recorder.Result().StatusCode).To(Equal(http.StatusCreated))
+
+			var issued struct {
+				Token string
+				Path  string
+			}
+			Expect(json.Unmarshal(recorder.Body.Bytes(), &issued)).To(Succeed())
+			Expect(issued.Token).To(MatchRegexp("^[0-9a-f]{64}$"))
+			Expect(issued.Path).To(Equal("/api/v1/calendar/" + issued.Token + ".ics"))
+
+			_, userID, hash := tokenStore.SaveArgsForCall(0)
+			Expect(userID).To(Equal(234))
+			sum := sha256.Sum256([]byte(issued.Token))
+			Expect(hash).To(Equal(hex.EncodeToString(sum[:])))
+		})
+
+		When("the store fails", func() {
+			BeforeEach(func() {
+				tokenStore.SaveReturns(errors.New("boom"))
+			})
+
+			It("returns an internal server error", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusInternalServerError))
+			})
+		})
+	})
+
+	Describe("RevokeCalendarToken", func() {
+		JustBeforeEach(func() {
+			var err error
+			req, err = http.NewRequest(http.MethodDelete, "/profile/calendar-token", nil)
+			Expect(err).NotTo(HaveOccurred())
+			httpHandlers.RevokeCalendarToken(recorder, req)
+		})
+
+		When("I'm logged out", func() {
+			BeforeEach(func() {
+				sessionManager.GetReturns(&session.AuthInfo{IsLoggedIn: false}, nil)
+			})
+
+			It("returns a status not auth'ed", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusUnauthorized))
+			})
+		})
+
+		It("deletes the user's token", func() {
+			Expect(recorder.Result().StatusCode).To(Equal(http.StatusNoContent))
+			_, userID := tokenStore.DeleteArgsForCall(0)
+			Expect(userID).To(Equal(234))
+		})
+
+		When("there is no token", func() {
+			BeforeEach(func() {
+				tokenStore.DeleteReturns(db.NotFoundErr())
+			})
+
+			It("returns a not found status", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusNotFound))
+			})
+		})
+	})
+
+	Describe("CalendarFeed", func() {
+		var monday time.Time
+
+		BeforeEach(func() {
+			now := time.Now()
+			monday = now.AddDate(0, 0, -(int(now.Weekday())+6)%7)
+
+			tokenStore.FindUserReturns(234, nil)
+			planStore.WeeksReturns([]models.Plan{{UserID: 234, Week: monday.Format("2006-01-02"), Slots: []models.Slot{
+				{Day: 0, Meal: models.Dinner, RecipeID: 3, Portions: 2},
+				{Day: 1, Meal: models.Breakfast, RecipeID: 5},
+				{Day: 1, Meal: models.Lunch, RecipeID: 3, LeftoverOf: &models.SlotRef{Day: 0, Meal: models.Dinner}},
+			}}}, nil)
+			recipeStore.GetManyStub = func(_ context.Context, _ int, ids []int) ([]models.Recipe, error) {
+				return []models.Recipe{
+					{ID: 3, Name: "Fish, chips", Steps: []models.Step{
+						{Instruction: "Fry chips", DurationSeconds: 1200},
+						{Instruction: "Fry fish", DurationSeconds: 600},
+						{Instruction: "Serve"},
+					}},
+					{ID: 5, Name: "Toast"},
+				}, nil
+			}
+		})
+
+		JustBeforeEach(func() {
+			var err error
+			req, err = http.NewRequest(http.MethodGet, "/calendar/s3cret.ics", nil)
+			Expect(err).NotTo(HaveOccurred())
+			req = mux.SetURLVars(req, map[string]string{"token": "s3cret"})
+			httpHandlers.CalendarFeed(recorder, req)
+		})
+
+		It("finds the user by the token's hash, without a session", func() {
+			Expect(sessionManager.GetCallCount()).To(BeZero())
+			_, hash := tokenStore.FindUserArgsForCall(0)
+			sum := sha256.Sum256([]byte("s3cret"))
+			Expect(hash).To(Equal(hex.EncodeToString(sum[:])))
+		})
+
+		It("reads the user's plans for this week and the next three", func() {
+			_, userID, from, to := planStore.WeeksArgsForCall(0)
+			Expect(userID).To(Equal(234))
+			Expect(from).To(Equal(monday.Format("2006-01-02")))
+			Expect(to).To(Equal(monday.AddDate(0, 0, 21).Format("2006-01-02")))
+		})
+
+		It("serves an event per slot, starting when preparation should begin", func() {
+			Expect(recorder.Result().StatusCode).To(Equal(http.StatusOK))
+			Expect(recorder.Header().Get("Content-Type")).To(Equal("text/calendar; charset=utf-8"))
+
+			week := monday.Format("2006-01-02")
+			day := func(d int) string { return monday.AddDate(0, 0, d).Format("20060102") }
+			body := recorder.Body.String()
+			Expect(body).To(ContainSubstring("BEGIN:VEVENT\r\nUID:plan-" + week + "-0-dinner@menu-planner\r\n"))
+			Expect(body).To(ContainSubstring("DTSTART:" + day(0) + "T180000\r\nDTEND:" + day(0) + "T183000\r\n" +
+				"SUMMARY:Fish\\, chips\r\nURL:https://menus.example.com/recipes/3\r\n"))
+			Expect(body).To(ContainSubstring("DTSTART:" + day(1) + "T073000\r\nDTEND:" + day(1) + "T080000\r\nSUMMARY:Toast\r\n"))
+		})
+
+		It("serves leftover slots as a reheat of the earlier meal", func() {
+			day := monday.AddDate(0, 0, 1).Format("20060102")
+			Expect(recorder.Body.String()).To(ContainSubstring("DTSTART:" + day + "T121500\r\nDTEND:" + day + "T123000\r\n" +
+				"SUMMARY:Fish\\, chips (leftovers)\r\nDESCRIPTION:Leftovers of Monday dinner\r\n"))
+		})
+
+		It("loads the recipes in one batch", func() {
+			Expect(recipeStore.GetCallCount()).To(BeZero())
+			Expect(recipeStore.GetManyCallCount()).To(Equal(1))
+			_, userID, ids := recipeStore.GetManyArgsForCall(0)
+			Expect(userID).To(Equal(234))
+			Expect(ids).To(ConsistOf(3, 5, 3))
+		})
+
+		When("nothing is planned", func() {
+			BeforeEach(func() {
+				planStore.WeeksReturns([]models.Plan{}, nil)
+			})
+
+			It("serves an empty calendar", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusOK))
+				Expect(recorder.Body.String()).NotTo(ContainSubstring("VEVENT"))
+				Expect(recipeStore.GetManyCallCount()).To(BeZero())
+			})
+		})
+
+		When("the token is unknown", func() {
+			BeforeEach(func() {
+				tokenStore.FindUserReturns(0, db.NotFoundErr())
+			})
+
+			It("returns a not found status", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusNotFound))
+				Expect(planStore.WeeksCallCount()).To(BeZero())
+			})
+		})
+
+		When("the plans can't be read", func() {
+			BeforeEach(func() {
+				planStore.WeeksReturns(nil, errors.New("boom"))
+			})
+
+			It("returns an internal server error", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusInternalServerError))
+			})
+		})
+
+		When("the recipes can't be loaded", func() {
+			BeforeEach(func() {
+				recipeStore.GetManyStub = nil
+				recipeStore.GetManyReturns(nil, errors.New("boom"))
+			})
+
+			It("returns an internal server error", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusInternalServerError))
+			})
+		})
+	})
+})
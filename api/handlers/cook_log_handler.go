@@ -0,0 +1,289 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/kieron-pivotal/menu-planner-app/models"
+	"github.com/kieron-pivotal/menu-planner-app/problem"
+)
+
+// defaultSuggestionDays is how long a recipe must not have been cooked
+// for before it is suggested
+const defaultSuggestionDays = 28
+
+//counterfeiter:generate . CookLogStore
+
+type CookLogStore interface {
+	IsNotFoundErr(error) bool
+	Add(ctx context.Context, cooked models.Cooked) (models.Cooked, error)
+	History(ctx context.Context, recipeID int) ([]models.Cooked, error)
+	Histories(ctx context.Context, recipeIDs []int) ([]models.Cooked, error)
+	Leftovers(ctx context.Context, userID int) ([]models.Cooked, error)
+	EatLeftovers(ctx context.Context, userID, id, portions int) (models.Cooked, error)
+}
+
+type CookLogHandler struct {
+	sessionManager SessionManager
+	recipeStore    RecipeStore
+	cookLogStore   CookLogStore
+	transactor     Transactor
+	publisher      EventPublisher
+}
+
+func NewCookLogHandler(
+	sessionManager SessionManager, recipeStore RecipeStore,
+	cookLogStore CookLogStore, transactor Transactor, publisher EventPublisher) *CookLogHandler {
+	return &CookLogHandler{
+		sessionManager: sessionManager,
+		recipeStore:    recipeStore,
+		cookLogStore:   cookLogStore,
+		transactor:     transactor,
+		publisher:      publisher,
+	}
+}
+
+// RecordCooked logs that the user made a recipe, with an optional rating,
+// notes and how many portions are left over. It was cooked now unless the
+// body says otherwise.
+func (h *CookLogHandler) RecordCooked(w http.ResponseWriter, r *http.Request) {
+	sess, err := h.sessionManager.Get(r.Context())
+	if err != nil || sess == nil || !sess.IsLoggedIn {
+		problem.Write(w, http.StatusUnauthorized, problem.Unauthorized, "")
+
+		return
+	}
+
+	recipeID, ok := h.ownRecipeID(w, r, sess.ID)
+	if !ok {
+		return
+	}
+
+	cooked := models.Cooked{}
+	if !decodeJSON(w, r, &cooked, maxBodyBytes) {
+		return
+	}
+
+	if errs := validateCooked(cooked); len(errs) > 0 {
+		problem.WriteInvalid(w, errs)
+
+		return
+	}
+
+	cooked.ID = 0
+	cooked.RecipeID = recipeID
+	cooked.Notes = strings.TrimSpace(cooked.Notes)
+	if cooked.CookedAt.IsZero() {
+		cooked.CookedAt = time.Now()
+	}
+
+	err = h.transactor.InTx(r.Context(), func(ctx context.Context) error {
+		cooked, err = h.cookLogStore.Add(ctx, cooked)
+		return err
+	})
+	if err != nil {
+		log.Printf("cooked-add: %v\n", err)
+		problem.Write(w, http.StatusInternalServerError, problem.Internal, "")
+
+		return
+	}
+
+	publish(r.Context(), h.publisher, sess.ID, models.Event{Type: models.RecipeCooked, RecipeID: recipeID})
+
+	w.Header().Add("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(cooked)
+}
+
+// CookHistory lists when the user made a recipe, most recent first
+func (h *CookLogHandler) CookHistory(w http.ResponseWriter, r *http.Request) {
+	sess, err := h.sessionManager.Get(r.Context())
+	if err != nil || sess == nil || !sess.IsLoggedIn {
+		problem.Write(w, http.StatusUnauthorized, problem.Unauthorized, "")
+
+		return
+	}
+
+	recipeID, ok := h.ownRecipeID(w, r, sess.ID)
+	if !ok {
+		return
+	}
+
+	history, err := h.cookLogStore.History(r.Context(), recipeID)
+	if err != nil {
+		log.Printf("cooked-history: %v\n", err)
+		problem.Write(w, http.StatusInternalServerError, problem.Internal, "")
+
+		return
+	}
+
+	w.Header().Add("Content-Type", "application/json")
+
+	if err = json.NewEncoder(w).Encode(history); err != nil {
+		problem.Write(w, http.StatusInternalServerError, problem.Internal, "json encoding failure")
+
+		return
+	}
+}
+
+// ListLeftovers lists the user's cooks which still have portions left,
+// oldest first
+func (h *CookLogHandler) ListLeftovers(w http.ResponseWriter, r *http.Request) {
+	sess, err := h.sessionManager.Get(r.Context())
+	if err != nil || sess == nil || !sess.IsLoggedIn {
+		problem.Write(w, http.StatusUnauthorized, problem.Unauthorized, "")
+
+		return
+	}
+
+	leftovers, err := h.cookLogStore.Leftovers(r.Context(), sess.ID)
+	if err != nil {
+		log.Printf("leftovers-list: %v\n", err)
+		problem.Write(w, http.StatusInternalServerError, problem.Internal, "")
+
+		return
+	}
+
+	w.Header().Add("Content-Type", "application/json")
+
+	if err = json.NewEncoder(w).Encode(leftovers); err != nil {
+		problem.Write(w, http.StatusInternalServerError, problem.Internal, "json encoding failure")
+
+		return
+	}
+}
+
+// EatLeftovers takes portions from a cook's leftovers, one unless the body
+// says how many
+func (h *CookLogHandler) EatLeftovers(w http.ResponseWriter, r *http.Request) {
+	sess, err := h.sessionManager.Get(r.Context())
+	if err != nil || sess == nil || !sess.IsLoggedIn {
+		problem.Write(w, http.StatusUnauthorized, problem.Unauthorized, "")
+
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		problem.Write(w, http.StatusNotFound, problem.NotFound, "")
+
+		return
+	}
+
+	eaten := struct {
+		Portions int `json:"portions"`
+	}{Portions: 1}
+	if !decodeJSON(w, r, &eaten, maxBodyBytes) {
+		return
+	}
+
+	if eaten.Portions < 1 {
+		problem.WriteInvalid(w, []problem.FieldError{{Field: "portions", Message: "must be at least 1"}})
+
+		return
+	}
+
+	cooked, err := h.cookLogStore.EatLeftovers(r.Context(), sess.ID, id, eaten.Portions)
+	if err != nil {
+		if h.cookLogStore.IsNotFoundErr(err) {
+			problem.Write(w, http.StatusNotFound, problem.NotFound, "")
+
+			return
+		}
+
+		log.Printf("leftovers-eat: %v\n", err)
+		problem.Write(w, http.StatusInternalServerError, problem.Internal, "")
+
+		return
+	}
+
+	publish(r.Context(), h.publisher, sess.ID, models.Event{Type: models.LeftoversChanged, RecipeID: cooked.RecipeID})
+
+	w.Header().Add("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(cooked)
+}
+
+// Suggestions lists recipes the user hasn't made in a while: those never
+// cooked come first, then the longest since they were last cooked. The
+// days query parameter sets how long "a while" is.
+func (h *CookLogHandler) Suggestions(w http.ResponseWriter, r *http.Request) {
+	sess, err := h.sessionManager.Get(r.Context())
+	if err != nil || sess == nil || !sess.IsLoggedIn {
+		problem.Write(w, http.StatusUnauthorized, problem.Unauthorized, "")
+
+		return
+	}
+
+	days := defaultSuggestionDays
+	if param := r.URL.Query().Get("days"); param != "" {
+		days, err = strconv.Atoi(param)
+		if err != nil || days < 0 {
+			problem.Write(w, http.StatusBadRequest, problem.InvalidParameter, "days must be a whole number of days, not negative")
+
+			return
+		}
+	}
+
+	recipes, err := h.recipeStore.List(r.Context(), sess.ID)
+	if err != nil {
+		log.Printf("recipe-suggestions: %v\n", err)
+		problem.Write(w, http.StatusInternalServerError, problem.Internal, "")
+
+		return
+	}
+
+	cutoff := time.Now().AddDate(0, 0, -days)
+	list := []models.Recipe{}
+	for _, recipe := range recipes {
+		if recipe.LastCookedAt == nil || recipe.LastCookedAt.Before(cutoff) {
+			list = append(list, models.Recipe{Name: recipe.Name, ID: recipe.ID, LastCookedAt: recipe.LastCookedAt})
+		}
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		if list[j].LastCookedAt == nil {
+			return false
+		}
+		return list[i].LastCookedAt == nil || list[i].LastCookedAt.Before(*list[j].LastCookedAt)
+	})
+
+	w.Header().Add("Content-Type", "application/json")
+
+	if err = json.NewEncoder(w).Encode(list); err != nil {
+		problem.Write(w, http.StatusInternalServerError, problem.Internal, "json encoding failure")
+
+		return
+	}
+}
+
+// ownRecipeID returns the recipe id from the path, writing a 404 unless
+// it is one of the user's recipes
+func (h *CookLogHandler) ownRecipeID(w http.ResponseWriter, r *http.Request, userID int) (int, bool) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		problem.Write(w, http.StatusNotFound, problem.NotFound, "")
+
+		return 0, false
+	}
+
+	if _, err = h.recipeStore.Get(r.Context(), userID, id); err != nil {
+		if h.recipeStore.IsNotFoundErr(err) {
+			problem.Write(w, http.StatusNotFound, problem.NotFound, "")
+
+			return 0, false
+		}
+
+		log.Printf("recipe-get: %v\n", err)
+		problem.Write(w, http.StatusInternalServerError, problem.Internal, "")
+
+		return 0, false
+	}
+
+	return id, true
+}
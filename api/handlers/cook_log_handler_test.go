@@ -0,0 +1,409 @@
+package handlers_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/kieron-pivotal/menu-planner-app/db"
+	"github.com/kieron-pivotal/menu-planner-app/handlers"
+	"github.com/kieron-pivotal/menu-planner-app/handlers/handlersfakes"
+	"github.com/kieron-pivotal/menu-planner-app/models"
+	"github.com/kieron-pivotal/menu-planner-app/session"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CookLogHandler", func() {
+	var (
+		sessionManager *handlersfakes.FakeSessionManager
+		recipeStore    *handlersfakes.FakeRecipeStore
+		cookLogStore   *handlersfakes.FakeCookLogStore
+		transactor     *handlersfakes.FakeTransactor
+		publisher      *handlersfakes.FakeEventPublisher
+		recorder       *httptest.ResponseRecorder
+		req            *http.Request
+		httpHandlers   *handlers.CookLogHandler
+		id             string
+	)
+
+	BeforeEach(func() {
+		sessionManager = new(handlersfakes.FakeSessionManager)
+		sessionManager.GetReturns(&session.AuthInfo{IsLoggedIn: true, Name: "forest", ID: 234}, nil)
+		recipeStore = new(handlersfakes.FakeRecipeStore)
+		recipeStore.IsNotFoundErrStub = func(err error) bool { return err == db.NotFoundErr() }
+		recipeStore.GetReturns(models.Recipe{Name: "Bob", ID: 345}, nil)
+		cookLogStore = new(handlersfakes.FakeCookLogStore)
+		cookLogStore.IsNotFoundErrStub = func(err error) bool { return err == db.NotFoundErr() }
+		transactor = new(handlersfakes.FakeTransactor)
+		transactor.InTxStub = func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(context.WithValue(ctx, inTx{}, true))
+		}
+		publisher = new(handlersfakes.FakeEventPublisher)
+		httpHandlers = handlers.NewCookLogHandler(sessionManager, recipeStore, cookLogStore, transactor, publisher)
+		recorder = httptest.NewRecorder()
+		id = "345"
+	})
+
+	Describe("RecordCooked", func() {
+		var body string
+
+		BeforeEach(func() {
+			body = `{"cookedAt": "2020-05-07T18:30:00Z", "rating": 4, "notes": " more garlic "}`
+			cookLogStore.AddStub = func(_ context.Context, c models.Cooked) (models.Cooked, error) {
+				c.ID = 9
+				return c, nil
+			}
+		})
+
+		JustBeforeEach(func() {
+			var err error
+			req, err = http.NewRequest(http.MethodPost, "/recipes/"+id+"/cooked", strings.NewReader(body))
+			Expect(err).NotTo(HaveOccurred())
+			req = mux.SetURLVars(req, map[string]string{"id": id})
+			httpHandlers.RecordCooked(recorder, req)
+		})
+
+		When("I'm logged out", func() {
+			BeforeEach(func() {
+				sessionManager.GetReturns(&session.AuthInfo{IsLoggedIn: false}, nil)
+			})
+
+			It("returns a status not auth'ed", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusUnauthorized))
+			})
+		})
+
+		It("logs the recipe as cooked in a transaction", func() {
+			Expect(recorder.Result().StatusCode).To(Equal(http.StatusCreated))
+
+			_, userID, recipeID := recipeStore.GetArgsForCall(0)
+			Expect(userID).To(Equal(234))
+			Expect(recipeID).To(Equal(345))
+
+			Expect(cookLogStore.AddCallCount()).To(Equal(1))
+			ctx, cooked := cookLogStore.AddArgsForCall(0)
+			Expect(ctx.Value(inTx{})).To(BeTrue())
+			Expect(cooked).To(Equal(models.Cooked{
+				RecipeID: 345, CookedAt: time.Date(2020, 5, 7, 18, 30, 0, 0, time.UTC), Rating: 4, Notes: "more garlic",
+			}))
+
+			Expect(recorder.Body.String()).To(MatchJSON(`{
+				"id": 9, "recipeId": 345, "cookedAt": "2020-05-07T18:30:00Z", "rating": 4, "notes": "more garlic"
+			}`))
+
+			Expect(publisher.PublishCallCount()).To(Equal(1))
+			_, userID, event := publisher.PublishArgsForCall(0)
+			Expect(userID).To(Equal(234))
+			Expect(event).To(Equal(models.Event{Type: models.RecipeCooked, RecipeID: 345}))
+		})
+
+		When("there is no body", func() {
+			BeforeEach(func() {
+				body = ""
+			})
+
+			It("logs the recipe as cooked now", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusCreated))
+				_, cooked := cookLogStore.AddArgsForCall(0)
+				Expect(cooked.CookedAt).To(BeTemporally("~", time.Now(), time.Minute))
+				Expect(cooked.Rating).To(BeZero())
+			})
+		})
+
+		When("the rating is out of range", func() {
+			BeforeEach(func() {
+				body = `{"rating": 6}`
+			})
+
+			It("returns an unprocessable entity status", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusUnprocessableEntity))
+				Expect(cookLogStore.AddCallCount()).To(BeZero())
+			})
+		})
+
+		When("there are leftovers", func() {
+			BeforeEach(func() {
+				body = `{"leftovers": 2}`
+			})
+
+			It("logs them", func() {
+				_, cooked := cookLogStore.AddArgsForCall(0)
+				Expect(cooked.Leftovers).To(Equal(2))
+			})
+		})
+
+		When("the leftovers are negative", func() {
+			BeforeEach(func() {
+				body = `{"leftovers": -2}`
+			})
+
+			It("returns an unprocessable entity status", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusUnprocessableEntity))
+				Expect(cookLogStore.AddCallCount()).To(BeZero())
+			})
+		})
+
+		When("the recipe isn't mine", func() {
+			BeforeEach(func() {
+				recipeStore.GetReturns(models.Recipe{}, db.NotFoundErr())
+			})
+
+			It("returns a not found status", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusNotFound))
+				Expect(cookLogStore.AddCallCount()).To(BeZero())
+			})
+		})
+
+		When("the id isn't a number", func() {
+			BeforeEach(func() {
+				id = "soup"
+			})
+
+			It("returns a not found status", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusNotFound))
+			})
+		})
+
+		When("the store fails", func() {
+			BeforeEach(func() {
+				cookLogStore.AddReturns(models.Cooked{}, errors.New("boom"))
+			})
+
+			It("returns an internal server error", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusInternalServerError))
+				Expect(publisher.PublishCallCount()).To(BeZero())
+			})
+		})
+	})
+
+	Describe("CookHistory", func() {
+		BeforeEach(func() {
+			cookLogStore.HistoryReturns([]models.Cooked{
+				{ID: 2, RecipeID: 345, CookedAt: time.Date(2020, 5, 7, 18, 30, 0, 0, time.UTC), Rating: 5},
+				{ID: 1, RecipeID: 345, CookedAt: time.Date(2020, 4, 2, 19, 0, 0, 0, time.UTC), Notes: "too salty"},
+			}, nil)
+		})
+
+		JustBeforeEach(func() {
+			var err error
+			req, err = http.NewRequest(http.MethodGet, "/recipes/"+id+"/history", nil)
+			Expect(err).NotTo(HaveOccurred())
+			req = mux.SetURLVars(req, map[string]string{"id": id})
+			httpHandlers.CookHistory(recorder, req)
+		})
+
+		When("I'm logged out", func() {
+			BeforeEach(func() {
+				sessionManager.GetReturns(&session.AuthInfo{IsLoggedIn: false}, nil)
+			})
+
+			It("returns a status not auth'ed", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusUnauthorized))
+			})
+		})
+
+		It("returns the recipe's history as JSON", func() {
+			Expect(cookLogStore.HistoryCallCount()).To(Equal(1))
+			_, recipeID := cookLogStore.HistoryArgsForCall(0)
+			Expect(recipeID).To(Equal(345))
+			Expect(recorder.Header().Get("Content-Type")).To(Equal("application/json"))
+			Expect(recorder.Body.String()).To(MatchJSON(`[
+				{"id": 2, "recipeId": 345, "cookedAt": "2020-05-07T18:30:00Z", "rating": 5},
+				{"id": 1, "recipeId": 345, "cookedAt": "2020-04-02T19:00:00Z", "notes": "too salty"}
+			]`))
+		})
+
+		When("the recipe isn't mine", func() {
+			BeforeEach(func() {
+				recipeStore.GetReturns(models.Recipe{}, db.NotFoundErr())
+			})
+
+			It("returns a not found status", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusNotFound))
+				Expect(cookLogStore.HistoryCallCount()).To(BeZero())
+			})
+		})
+	})
+
+	Describe("Suggestions", func() {
+		var url string
+
+		BeforeEach(func() {
+			url = "/recipes/suggestions"
+			recent := time.Now().Add(-3 * 24 * time.Hour)
+			longAgo := time.Now().Add(-60 * 24 * time.Hour)
+			aWhileAgo := time.Now().Add(-40 * 24 * time.Hour)
+			recipeStore.ListReturns([]models.Recipe{
+				{Name: "recent", ID: 1, LastCookedAt: &recent},
+				{Name: "a while ago", ID: 2, LastCookedAt: &aWhileAgo},
+				{Name: "never", ID: 3},
+				{Name: "long ago", ID: 4, LastCookedAt: &longAgo},
+			}, nil)
+		})
+
+		JustBeforeEach(func() {
+			var err error
+			req, err = http.NewRequest(http.MethodGet, url, nil)
+			Expect(err).NotTo(HaveOccurred())
+			httpHandlers.Suggestions(recorder, req)
+		})
+
+		When("I'm logged out", func() {
+			BeforeEach(func() {
+				sessionManager.GetReturns(&session.AuthInfo{IsLoggedIn: false}, nil)
+			})
+
+			It("returns a status not auth'ed", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusUnauthorized))
+			})
+		})
+
+		It("suggests recipes not cooked for four weeks, never cooked first", func() {
+			Expect(recorder.Result().StatusCode).To(Equal(http.StatusOK))
+			_, userID := recipeStore.ListArgsForCall(0)
+			Expect(userID).To(Equal(234))
+			Expect(names(recorder)).To(Equal([]string{"never", "long ago", "a while ago"}))
+		})
+
+		When("the number of days is given", func() {
+			BeforeEach(func() {
+				url = "/recipes/suggestions?days=50"
+			})
+
+			It("uses it", func() {
+				Expect(names(recorder)).To(Equal([]string{"never", "long ago"}))
+			})
+		})
+
+		When("the number of days isn't a number", func() {
+			BeforeEach(func() {
+				url = "/recipes/suggestions?days=lots"
+			})
+
+			It("returns a bad request status", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusBadRequest))
+			})
+		})
+	})
+
+	Describe("ListLeftovers", func() {
+		BeforeEach(func() {
+			cookLogStore.LeftoversReturns([]models.Cooked{
+				{ID: 1, RecipeID: 345, CookedAt: time.Date(2020, 5, 3, 18, 0, 0, 0, time.UTC), Leftovers: 2},
+			}, nil)
+		})
+
+		JustBeforeEach(func() {
+			var err error
+			req, err = http.NewRequest(http.MethodGet, "/leftovers", nil)
+			Expect(err).NotTo(HaveOccurred())
+			httpHandlers.ListLeftovers(recorder, req)
+		})
+
+		When("I'm logged out", func() {
+			BeforeEach(func() {
+				sessionManager.GetReturns(&session.AuthInfo{IsLoggedIn: false}, nil)
+			})
+
+			It("returns a status not auth'ed", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusUnauthorized))
+			})
+		})
+
+		It("returns the user's leftovers as JSON", func() {
+			_, userID := cookLogStore.LeftoversArgsForCall(0)
+			Expect(userID).To(Equal(234))
+			Expect(recorder.Header().Get("Content-Type")).To(Equal("application/json"))
+			Expect(recorder.Body.String()).To(MatchJSON(`[
+				{"id": 1, "recipeId": 345, "cookedAt": "2020-05-03T18:00:00Z", "leftovers": 2}
+			]`))
+		})
+
+		When("the store fails", func() {
+			BeforeEach(func() {
+				cookLogStore.LeftoversReturns(nil, errors.New("boom"))
+			})
+
+			It("returns an internal server error", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusInternalServerError))
+			})
+		})
+	})
+
+	Describe("EatLeftovers", func() {
+		var body string
+
+		BeforeEach(func() {
+			id = "7"
+			body = `{"portions": 2}`
+			cookLogStore.EatLeftoversReturns(models.Cooked{ID: 7, RecipeID: 345, CookedAt: time.Date(2020, 5, 3, 18, 0, 0, 0, time.UTC), Leftovers: 1}, nil)
+		})
+
+		JustBeforeEach(func() {
+			var err error
+			req, err = http.NewRequest(http.MethodPost, "/leftovers/"+id+"/eaten", strings.NewReader(body))
+			Expect(err).NotTo(HaveOccurred())
+			req = mux.SetURLVars(req, map[string]string{"id": id})
+			httpHandlers.EatLeftovers(recorder, req)
+		})
+
+		When("I'm logged out", func() {
+			BeforeEach(func() {
+				sessionManager.GetReturns(&session.AuthInfo{IsLoggedIn: false}, nil)
+			})
+
+			It("returns a status not auth'ed", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusUnauthorized))
+			})
+		})
+
+		It("eats the portions and returns what is left", func() {
+			Expect(recorder.Result().StatusCode).To(Equal(http.StatusOK))
+			_, userID, cookedID, portions := cookLogStore.EatLeftoversArgsForCall(0)
+			Expect(userID).To(Equal(234))
+			Expect(cookedID).To(Equal(7))
+			Expect(portions).To(Equal(2))
+			Expect(recorder.Body.String()).To(ContainSubstring(`"leftovers":1`))
+
+			_, _, event := publisher.PublishArgsForCall(0)
+			Expect(event).To(Equal(models.Event{Type: models.LeftoversChanged, RecipeID: 345}))
+		})
+
+		When("there is no body", func() {
+			BeforeEach(func() {
+				body = ""
+			})
+
+			It("eats one portion", func() {
+				_, _, _, portions := cookLogStore.EatLeftoversArgsForCall(0)
+				Expect(portions).To(Equal(1))
+			})
+		})
+
+		When("no portions are eaten", func() {
+			BeforeEach(func() {
+				body = `{"portions": 0}`
+			})
+
+			It("returns an unprocessable entity status", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusUnprocessableEntity))
+				Expect(cookLogStore.EatLeftoversCallCount()).To(BeZero())
+			})
+		})
+
+		When("the leftovers aren't mine", func() {
+			BeforeEach(func() {
+				cookLogStore.EatLeftoversReturns(models.Cooked{}, db.NotFoundErr())
+			})
+
+			It("returns a not found status", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusNotFound))
+			})
+		})
+	})
+})
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/kieron-pivotal/menu-planner-app/models"
+	"github.com/kieron-pivotal/menu-planner-app/problem"
+)
+
+// keepAlive is how often an idle event stream sends a comment, so proxies
+// don't close it, after checking the session is still active
+const keepAlive = 30 * time.Second
+
+//counterfeiter:generate . EventPublisher
+
+type EventPublisher interface {
+	Publish(ctx context.Context, userID int, event models.Event) error
+}
+
+//counterfeiter:generate . EventSubscriber
+
+type EventSubscriber interface {
+	Subscribe(userID int) (<-chan models.Event, func())
+}
+
+type EventsHandler struct {
+	sessionManager SessionManager
+	subscriber     EventSubscriber
+	keepAlive      time.Duration
+}
+
+func NewEventsHandler(sessionManager SessionManager, subscriber EventSubscriber) *EventsHandler {
+	return &EventsHandler{
+		sessionManager: sessionManager,
+		subscriber:     subscriber,
+		keepAlive:      keepAlive,
+	}
+}
+
+// StreamEvents sends the user's change events as server-sent events until
+// the client goes away. The stream ends if the client falls too far
+// behind; EventSource reconnects, and clients should then fetch what they
+// show again, as they should on a resync event. It also ends at the first
+// keep-alive after the session is logged out, revoked or expires.
+func (h *EventsHandler) StreamEvents(w http.ResponseWriter, r *http.Request) {
+	sess, err := h.sessionManager.Get(r.Context())
+	if err != nil || sess == nil || !sess.IsLoggedIn {
+		problem.Write(w, http.StatusUnauthorized, problem.Unauthorized, "")
+
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		problem.Write(w, http.StatusInternalServerError, problem.Internal, "streaming unsupported")
+
+		return
+	}
+
+	events, stop := h.subscriber.Subscribe(sess.ID)
+	defer stop()
+
+	w.Header().Add("Content-Type", "text/event-stream")
+	w.Header().Add("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	ticker := time.NewTicker(h.keepAlive)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+			active, err := h.sessionManager.IsActive(r.Context(), sess)
+			if err != nil {
+				log.Printf("events-session: %v\n", err)
+			}
+			if !active {
+				return
+			}
+			fmt.Fprint(w, ": keep-alive\n\n")
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+
+			data, err := json.Marshal(event)
+			if err != nil {
+				log.Printf("event-encode: %v\n", err)
+				continue
+			}
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data)
+		}
+		flusher.Flush()
+	}
+}
+
+// publish tells the user's other clients about a change. The change has
+// already been made, so failing to tell them is only logged.
+func publish(ctx context.Context, publisher EventPublisher, userID int, events ...models.Event) {
+	for _, event := range events {
+		if err := publisher.Publish(ctx, userID, event); err != nil {
+			log.Printf("event-publish: %v\n", err)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package handlers_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/kieron-pivotal/menu-planner-app/handlers"
+	"github.com/kieron-pivotal/menu-planner-app/handlers/handlersfakes"
+	"github.com/kieron-pivotal/menu-planner-app/models"
+	"github.com/kieron-pivotal/menu-planner-app/session"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("EventsHandler", func() {
+	var (
+		sessionManager *handlersfakes.FakeSessionManager
+		subscriber     *handlersfakes.FakeEventSubscriber
+		recorder       *httptest.ResponseRecorder
+		httpHandlers   *handlers.EventsHandler
+		events         chan models.Event
+		stopped        bool
+		ctx            context.Context
+		cancel         context.CancelFunc
+	)
+
+	BeforeEach(func() {
+		sessionManager = new(handlersfakes.FakeSessionManager)
+		sessionManager.GetReturns(&session.AuthInfo{IsLoggedIn: true, Name: "forest", ID: 234}, nil)
+		events = make(chan models.Event, 2)
+		stopped = false
+		subscriber = new(handlersfakes.FakeEventSubscriber)
+		subscriber.SubscribeReturns(events, func() { stopped = true })
+		httpHandlers = handlers.NewEventsHandler(sessionManager, subscriber)
+		recorder = httptest.NewRecorder()
+		ctx, cancel = context.WithCancel(context.Background())
+	})
+
+	JustBeforeEach(func() {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, "/events", nil)
+		Expect(err).NotTo(HaveOccurred())
+		httpHandlers.StreamEvents(recorder, req)
+	})
+
+	When("I'm logged out", func() {
+		BeforeEach(func() {
+			sessionManager.GetReturns(&session.AuthInfo{IsLoggedIn: false}, nil)
+		})
+
+		It("returns a status not auth'ed", func() {
+			Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+			Expect(subscriber.SubscribeCallCount()).To(BeZero())
+		})
+	})
+
+	When("my data changes", func() {
+		BeforeEach(func() {
+			events <- models.Event{Type: models.RecipeCooked, RecipeID: 345}
+			events <- models.Event{Type: models.Resync}
+			close(events)
+		})
+
+		It("streams my events until the subscription ends", func() {
+			Expect(subscriber.SubscribeArgsForCall(0)).To(Equal(234))
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+			Expect(recorder.Header().Get("Content-Type")).To(Equal("text/event-stream"))
+			Expect(recorder.Header().Get("Cache-Control")).To(Equal("no-cache"))
+			Expect(recorder.Flushed).To(BeTrue())
+			Expect(recorder.Body.String()).To(Equal(
+				"event: recipe-cooked\ndata: {\"type\":\"recipe-cooked\",\"recipeId\":345}\n\n" +
+					"event: resync\ndata: {\"type\":\"resync\"}\n\n"))
+			Expect(stopped).To(BeTrue())
+		})
+	})
+
+	When("my session stays active", func() {
+		BeforeEach(func() {
+			httpHandlers.SetKeepAlive(time.Millisecond)
+			sessionManager.IsActiveReturnsOnCall(0, true, nil)
+			sessionManager.IsActiveReturnsOnCall(1, true, nil)
+			sessionManager.IsActiveReturnsOnCall(2, false, nil)
+		})
+
+		It("keeps the stream alive, checking the session each time, until it ends", func() {
+			Expect(sessionManager.IsActiveCallCount()).To(Equal(3))
+			_, sess := sessionManager.IsActiveArgsForCall(0)
+			Expect(sess.ID).To(Equal(234))
+			Expect(recorder.Body.String()).To(Equal(": keep-alive\n\n: keep-alive\n\n"))
+			Expect(stopped).To(BeTrue())
+		})
+	})
+
+	When("my session can't be checked", func() {
+		BeforeEach(func() {
+			httpHandlers.SetKeepAlive(time.Millisecond)
+			sessionManager.IsActiveReturns(false, errors.New("boom"))
+		})
+
+		It("ends the stream", func() {
+			Expect(recorder.Body.String()).To(BeEmpty())
+			Expect(stopped).To(BeTrue())
+		})
+	})
+
+	When("I go away", func() {
+		BeforeEach(func() {
+			cancel()
+		})
+
+		It("stops my subscription", func() {
+			Expect(recorder.Body.String()).To(BeEmpty())
+			Expect(stopped).To(BeTrue())
+		})
+	})
+})
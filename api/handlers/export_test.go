@@ -0,0 +1,9 @@
+package handlers
+
+import "time"
+
+// SetKeepAlive lets tests send keep-alives without waiting for the real
+// interval
+func (h *EventsHandler) SetKeepAlive(d time.Duration) {
+	h.keepAlive = d
+}
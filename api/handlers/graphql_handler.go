@@ -0,0 +1,400 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/kieron-pivotal/menu-planner-app/graphql"
+	"github.com/kieron-pivotal/menu-planner-app/models"
+	"github.com/kieron-pivotal/menu-planner-app/problem"
+	"github.com/kieron-pivotal/menu-planner-app/session"
+)
+
+// errGraphQLInternal is what a query is told when a store fails, which is
+// logged instead
+var errGraphQLInternal = errors.New("internal error")
+
+type GraphQLHandler struct {
+	sessionManager SessionManager
+	planStore      PlanStore
+	recipeStore    RecipeStore
+	cookLogStore   CookLogStore
+	builder        ShoppingListBuilder
+	schema         *graphql.Schema
+}
+
+func NewGraphQLHandler(
+	sessionManager SessionManager, planStore PlanStore, recipeStore RecipeStore,
+	cookLogStore CookLogStore, builder ShoppingListBuilder) *GraphQLHandler {
+	h := &GraphQLHandler{
+		sessionManager: sessionManager,
+		planStore:      planStore,
+		recipeStore:    recipeStore,
+		cookLogStore:   cookLogStore,
+		builder:        builder,
+	}
+	h.schema = h.newSchema()
+	return h
+}
+
+type graphQLKey struct{}
+
+// graphQLQuery is the state of one query: who is asking, and the loaders
+// batching its store calls
+type graphQLQuery struct {
+	user    *session.AuthInfo
+	recipes *graphql.Loader
+	history *graphql.Loader
+}
+
+func queryState(ctx context.Context) *graphQLQuery {
+	return ctx.Value(graphQLKey{}).(*graphQLQuery)
+}
+
+// Query answers a GraphQL query about the logged in user's recipes and
+// plans, so a week view with its recipes and shopping list is one request.
+// Problems with the query itself are reported in the response's errors, as
+// GraphQL clients expect.
+func (h *GraphQLHandler) Query(w http.ResponseWriter, r *http.Request) {
+	sess, err := h.sessionManager.Get(r.Context())
+	if err != nil || sess == nil || !sess.IsLoggedIn {
+		problem.Write(w, http.StatusUnauthorized, problem.Unauthorized, "")
+
+		return
+	}
+
+	req := struct {
+		Query         string                 `json:"query"`
+		OperationName string                 `json:"operationName"`
+		Variables     map[string]interface{} `json:"variables"`
+		Extensions    json.RawMessage        `json:"extensions"`
+	}{}
+	if !decodeJSON(w, r, &req, maxBodyBytes) {
+		return
+	}
+
+	if errs := validateGraphQL(req.Query); len(errs) > 0 {
+		problem.WriteInvalid(w, errs)
+
+		return
+	}
+
+	ctx := context.WithValue(r.Context(), graphQLKey{}, h.newQuery(r.Context(), sess))
+	res := graphql.Execute(ctx, h.schema, graphql.Request{
+		Query:         req.Query,
+		OperationName: req.OperationName,
+		Variables:     req.Variables,
+	})
+
+	w.Header().Add("Content-Type", "application/json")
+
+	if err = json.NewEncoder(w).Encode(res); err != nil {
+		problem.Write(w, http.StatusInternalServerError, problem.Internal, "json encoding failure")
+
+		return
+	}
+}
+
+func (h *GraphQLHandler) newQuery(ctx context.Context, sess *session.AuthInfo) *graphQLQuery {
+	return &graphQLQuery{
+		user: sess,
+		recipes: graphql.NewLoader(func(ids []int) (map[int]interface{}, error) {
+			recipes, err := h.recipeStore.GetMany(ctx, sess.ID, ids)
+			if err != nil {
+				log.Printf("graphql-recipes: %v\n", err)
+				return nil, errGraphQLInternal
+			}
+
+			res := map[int]interface{}{}
+			for _, recipe := range recipes {
+				res[recipe.ID] = recipe
+			}
+			return res, nil
+		}),
+		// only recipes loaded for the user are asked for, so their
+		// histories are the user's
+		history: graphql.NewLoader(func(recipeIDs []int) (map[int]interface{}, error) {
+			history, err := h.cookLogStore.Histories(ctx, recipeIDs)
+			if err != nil {
+				log.Printf("graphql-history: %v\n", err)
+				return nil, errGraphQLInternal
+			}
+
+			byRecipe := map[int][]models.Cooked{}
+			for _, id := range recipeIDs {
+				byRecipe[id] = []models.Cooked{}
+			}
+			for _, cooked := range history {
+				byRecipe[cooked.RecipeID] = append(byRecipe[cooked.RecipeID], cooked)
+			}
+
+			res := map[int]interface{}{}
+			for id, cooked := range byRecipe {
+				res[id] = cooked
+			}
+			return res, nil
+		}),
+	}
+}
+
+func (h *GraphQLHandler) newSchema() *graphql.Schema {
+	ingredient := &graphql.Object{Name: "Ingredient", Fields: graphql.Fields{
+		"name":     {Type: graphql.String},
+		"quantity": {Type: graphql.Float},
+		"unit":     {Type: graphql.String},
+	}}
+
+	step := &graphql.Object{Name: "Step", Fields: graphql.Fields{
+		"instruction":     {Type: graphql.String},
+		"durationSeconds": {Type: graphql.Int},
+		"ingredients":     {Type: graphql.ListOf(graphql.Int)},
+	}}
+
+	recipe := &graphql.Object{Name: "Recipe"}
+	cooked := &graphql.Object{Name: "Cooked"}
+
+	recipe.Fields = graphql.Fields{
+		"id":   {Type: graphql.ID},
+		"name": {Type: graphql.String},
+		"servings": {Type: graphql.Int, Resolve: recipeDetail(func(r models.Recipe) interface{} {
+			return r.Servings
+		})},
+		"lastCookedAt": {Type: graphql.String, Resolve: func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+			return timestamp(source.(models.Recipe).LastCookedAt), nil
+		}},
+		"score":        {Type: graphql.Float},
+		"lowestRating": {Type: graphql.Int},
+		"favourite":    {Type: graphql.Boolean},
+		"ingredients": {Type: graphql.ListOf(ingredient), Resolve: recipeDetail(func(r models.Recipe) interface{} {
+			return r.Ingredients
+		})},
+		"steps": {Type: graphql.ListOf(step), Resolve: recipeDetail(func(r models.Recipe) interface{} {
+			return r.Steps
+		})},
+		"history": {Type: graphql.ListOf(cooked), Resolve: func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+			return queryState(ctx).history.Load(source.(models.Recipe).ID), nil
+		}},
+	}
+
+	cooked.Fields = graphql.Fields{
+		"id": {Type: graphql.ID},
+		"cookedAt": {Type: graphql.String, Resolve: func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+			cookedAt := source.(models.Cooked).CookedAt
+			return timestamp(&cookedAt), nil
+		}},
+		"rating":    {Type: graphql.Int},
+		"notes":     {Type: graphql.String},
+		"leftovers": {Type: graphql.Int},
+		"recipe": {Type: recipe, Resolve: func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+			return queryState(ctx).recipes.Load(source.(models.Cooked).RecipeID), nil
+		}},
+	}
+
+	shoppingItem := &graphql.Object{Name: "ShoppingItem", Fields: graphql.Fields{
+		"name":     {Type: graphql.String},
+		"quantity": {Type: graphql.Float},
+		"unit":     {Type: graphql.String},
+	}}
+	shoppingCategory := &graphql.Object{Name: "ShoppingCategory", Fields: graphql.Fields{
+		"name":  {Type: graphql.String},
+		"items": {Type: graphql.ListOf(shoppingItem)},
+	}}
+	shoppingList := &graphql.Object{Name: "ShoppingList", Fields: graphql.Fields{
+		"categories": {Type: graphql.ListOf(shoppingCategory)},
+	}}
+
+	slotRef := &graphql.Object{Name: "SlotRef", Fields: graphql.Fields{
+		"day": {Type: graphql.Int},
+		"meal": {Type: graphql.String, Resolve: func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+			return string(source.(*models.SlotRef).Meal), nil
+		}},
+	}}
+
+	slot := &graphql.Object{Name: "Slot", Fields: graphql.Fields{
+		"day": {Type: graphql.Int},
+		"meal": {Type: graphql.String, Resolve: func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+			return string(source.(models.Slot).Meal), nil
+		}},
+		"portions": {Type: graphql.Int, Resolve: func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+			return source.(models.Slot).Eats(), nil
+		}},
+		"leftoverOf": {Type: slotRef},
+		"recipe": {Type: recipe, Resolve: func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+			return queryState(ctx).recipes.Load(source.(models.Slot).RecipeID), nil
+		}},
+	}}
+
+	plan := &graphql.Object{Name: "Plan", Fields: graphql.Fields{
+		"week":  {Type: graphql.String},
+		"slots": {Type: graphql.ListOf(slot)},
+		// like the plan's REST shopping list, leftover slots add nothing
+		"shoppingList": {Type: shoppingList, Resolve: func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+			ids := []int{}
+			for _, slot := range source.(models.Plan).Slots {
+				if slot.LeftoverOf == nil {
+					ids = append(ids, slot.RecipeID)
+				}
+			}
+			return h.shoppingList(ctx, ids), nil
+		}},
+	}}
+
+	user := &graphql.Object{Name: "User", Fields: graphql.Fields{
+		"id": {Type: graphql.ID, Resolve: func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+			return source.(*session.AuthInfo).ID, nil
+		}},
+		"name": {Type: graphql.String, Resolve: func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+			return source.(*session.AuthInfo).Name, nil
+		}},
+		"recipes": {
+			Type: graphql.ListOf(recipe),
+			Args: graphql.Args{"minRating": {Type: graphql.Int, Default: 0}},
+			Resolve: func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+				recipes, err := h.recipeStore.List(ctx, source.(*session.AuthInfo).ID)
+				if err != nil {
+					log.Printf("graphql-recipe-list: %v\n", err)
+					return nil, errGraphQLInternal
+				}
+
+				// an explicit null is given as nil, and means no minimum
+				minRating, _ := args["minRating"].(int)
+
+				list := []models.Recipe{}
+				for _, r := range recipes {
+					if r.LowestRating > 0 && r.LowestRating < minRating {
+						continue
+					}
+					list = append(list, r)
+				}
+				return list, nil
+			},
+		},
+		"leftovers": {Type: graphql.ListOf(cooked), Resolve: func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+			leftovers, err := h.cookLogStore.Leftovers(ctx, source.(*session.AuthInfo).ID)
+			if err != nil {
+				log.Printf("graphql-leftovers: %v\n", err)
+				return nil, errGraphQLInternal
+			}
+			return leftovers, nil
+		}},
+	}}
+
+	query := &graphql.Object{Name: "Query", Fields: graphql.Fields{
+		"viewer": {Type: user, Resolve: func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+			return queryState(ctx).user, nil
+		}},
+		"recipe": {
+			Type: recipe,
+			Args: graphql.Args{"id": {Type: graphql.NonNullOf(graphql.ID)}},
+			Resolve: func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+				id, err := graphQLID(args["id"])
+				if err != nil {
+					return nil, err
+				}
+				return queryState(ctx).recipes.Load(id), nil
+			},
+		},
+		"plan": {
+			Type: plan,
+			Args: graphql.Args{"week": {Type: graphql.NonNullOf(graphql.String)}},
+			Resolve: func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+				week := args["week"].(string)
+				if date, err := time.Parse(weekFormat, week); err != nil || date.Weekday() != time.Monday {
+					return nil, fmt.Errorf("week must be the date of a Monday, e.g. 2026-10-19")
+				}
+
+				plan, err := h.planStore.Get(ctx, queryState(ctx).user.ID, week)
+				if err != nil {
+					log.Printf("graphql-plan: %v\n", err)
+					return nil, errGraphQLInternal
+				}
+				return plan, nil
+			},
+		},
+		"shoppingList": {
+			Type: shoppingList,
+			Args: graphql.Args{"recipes": {Type: graphql.NonNullOf(graphql.ListOf(graphql.NonNullOf(graphql.ID)))}},
+			Resolve: func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+				ids, err := graphQLIDs(args["recipes"].([]interface{}))
+				if err != nil {
+					return nil, err
+				}
+				return h.shoppingList(ctx, ids), nil
+			},
+		},
+	}}
+
+	return &graphql.Schema{Query: query}
+}
+
+// shoppingList loads the recipes and lists what to buy for them. An id
+// listed twice gives the recipe twice.
+func (h *GraphQLHandler) shoppingList(ctx context.Context, ids []int) graphql.Thunk {
+	load := queryState(ctx).recipes.LoadMany(ids)
+
+	return func() (interface{}, error) {
+		loaded, err := load()
+		if err != nil {
+			return nil, err
+		}
+
+		recipes := []models.Recipe{}
+		for i, recipe := range loaded.([]interface{}) {
+			if recipe == nil {
+				return nil, fmt.Errorf("recipe %d not found", ids[i])
+			}
+			recipes = append(recipes, recipe.(models.Recipe))
+		}
+		return h.builder.Build(recipes), nil
+	}
+}
+
+// recipeDetail resolves a field only loaded for single recipes, as
+// recipes listed come without them
+func recipeDetail(detail func(models.Recipe) interface{}) graphql.Resolver {
+	return func(ctx context.Context, source interface{}, args map[string]interface{}) (interface{}, error) {
+		load := queryState(ctx).recipes.Load(source.(models.Recipe).ID)
+
+		return graphql.Thunk(func() (interface{}, error) {
+			recipe, err := load()
+			if err != nil || recipe == nil {
+				return nil, err
+			}
+			return detail(recipe.(models.Recipe)), nil
+		}), nil
+	}
+}
+
+// timestamp formats a time as RFC 3339, or returns nil if there isn't one
+func timestamp(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func graphQLID(id interface{}) (int, error) {
+	n, err := strconv.Atoi(id.(string))
+	if err != nil {
+		return 0, fmt.Errorf("%q isn't a recipe id", id)
+	}
+	return n, nil
+}
+
+func graphQLIDs(ids []interface{}) ([]int, error) {
+	res := []int{}
+	for _, id := range ids {
+		n, err := graphQLID(id)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, n)
+	}
+	return res, nil
+}
@@ -0,0 +1,260 @@
+package handlers_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kieron-pivotal/menu-planner-app/handlers"
+	"github.com/kieron-pivotal/menu-planner-app/handlers/handlersfakes"
+	"github.com/kieron-pivotal/menu-planner-app/models"
+	"github.com/kieron-pivotal/menu-planner-app/problem"
+	"github.com/kieron-pivotal/menu-planner-app/session"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GraphQLHandler", func() {
+	var (
+		sessionManager *handlersfakes.FakeSessionManager
+		planStore      *handlersfakes.FakePlanStore
+		recipeStore    *handlersfakes.FakeRecipeStore
+		cookLogStore   *handlersfakes.FakeCookLogStore
+		builder        *handlersfakes.FakeShoppingListBuilder
+		recorder       *httptest.ResponseRecorder
+		httpHandlers   *handlers.GraphQLHandler
+		body           string
+		cookedAt       time.Time
+	)
+
+	BeforeEach(func() {
+		sessionManager = new(handlersfakes.FakeSessionManager)
+		sessionManager.GetReturns(&session.AuthInfo{IsLoggedIn: true, Name: "forest", ID: 234}, nil)
+		planStore = new(handlersfakes.FakePlanStore)
+		recipeStore = new(handlersfakes.FakeRecipeStore)
+		recipeStore.GetManyStub = func(_ context.Context, _ int, ids []int) ([]models.Recipe, error) {
+			recipes := []models.Recipe{}
+			for _, id := range ids {
+				if id != 9 {
+					recipes = append(recipes, models.Recipe{
+						ID: id, Name: "recipe " + strconv.Itoa(id), Servings: 2,
+						Ingredients: []models.Ingredient{{Name: "egg", Quantity: float64(id)}},
+					})
+				}
+			}
+			return recipes, nil
+		}
+		cookLogStore = new(handlersfakes.FakeCookLogStore)
+		cookedAt = time.Date(2026, time.October, 1, 18, 30, 0, 0, time.UTC)
+		cookLogStore.HistoriesReturns([]models.Cooked{{ID: 7, RecipeID: 2, CookedAt: cookedAt, Rating: 4}}, nil)
+		builder = new(handlersfakes.FakeShoppingListBuilder)
+		builder.BuildReturns(models.ShoppingList{Categories: []models.ShoppingCategory{
+			{Name: "Dairy & eggs", Items: []models.ShoppingItem{{Name: "egg", Quantity: 6}}},
+		}})
+		httpHandlers = handlers.NewGraphQLHandler(sessionManager, planStore, recipeStore, cookLogStore, builder)
+		recorder = httptest.NewRecorder()
+	})
+
+	JustBeforeEach(func() {
+		req, err := http.NewRequest(http.MethodPost, "/graphql", strings.NewReader(body))
+		Expect(err).NotTo(HaveOccurred())
+		httpHandlers.Query(recorder, req)
+	})
+
+	When("I'm logged out", func() {
+		BeforeEach(func() {
+			sessionManager.GetReturns(&session.AuthInfo{IsLoggedIn: false}, nil)
+			body = `{"query": "{ viewer { name } }"}`
+		})
+
+		It("returns a status not auth'ed", func() {
+			Expect(recorder.Code).To(Equal(http.StatusUnauthorized))
+		})
+	})
+
+	When("there is no query", func() {
+		BeforeEach(func() {
+			body = `{"variables": {}}`
+		})
+
+		It("says the query is required", func() {
+			Expect(recorder.Code).To(Equal(http.StatusUnprocessableEntity))
+			Expect(fieldErrors(recorder)).To(Equal([]problem.FieldError{{Field: "query", Message: "is required"}}))
+		})
+	})
+
+	When("asking for a week's plan", func() {
+		BeforeEach(func() {
+			planStore.GetReturns(models.Plan{Week: "2026-10-19", Slots: []models.Slot{
+				{Day: 0, Meal: models.Dinner, RecipeID: 1},
+				{Day: 0, Meal: models.Dinner, RecipeID: 2, Portions: 4},
+				{Day: 1, Meal: models.Lunch, RecipeID: 2, Portions: 2, LeftoverOf: &models.SlotRef{Day: 0, Meal: models.Dinner}},
+				{Day: 2, Meal: models.Dinner, RecipeID: 3},
+			}}, nil)
+			body = `{
+				"query": "query Week($week: String!) { plan(week: $week) { week slots { day meal portions leftoverOf { day meal } recipe { name ingredients { quantity } history { cookedAt rating } } } shoppingList { categories { name items { name quantity } } } } }",
+				"variables": {"week": "2026-10-19"}
+			}`
+		})
+
+		It("answers from the saved plan, loading the recipes and their histories in one batch each", func() {
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+			Expect(recorder.Header().Get("Content-Type")).To(Equal("application/json"))
+			Expect(recorder.Body.String()).To(MatchJSON(`{"data": {"plan": {
+				"week": "2026-10-19",
+				"slots": [
+					{"day": 0, "meal": "dinner", "portions": 1, "leftoverOf": null,
+						"recipe": {"name": "recipe 1", "ingredients": [{"quantity": 1}], "history": []}},
+					{"day": 0, "meal": "dinner", "portions": 4, "leftoverOf": null,
+						"recipe": {"name": "recipe 2", "ingredients": [{"quantity": 2}], "history": [{"cookedAt": "2026-10-01T18:30:00Z", "rating": 4}]}},
+					{"day": 1, "meal": "lunch", "portions": 2, "leftoverOf": {"day": 0, "meal": "dinner"},
+						"recipe": {"name": "recipe 2", "ingredients": [{"quantity": 2}], "history": [{"cookedAt": "2026-10-01T18:30:00Z", "rating": 4}]}},
+					{"day": 2, "meal": "dinner", "portions": 1, "leftoverOf": null,
+						"recipe": {"name": "recipe 3", "ingredients": [{"quantity": 3}], "history": []}}
+				],
+				"shoppingList": {"categories": [{"name": "Dairy & eggs", "items": [{"name": "egg", "quantity": 6}]}]}
+			}}}`))
+
+			Expect(planStore.GetCallCount()).To(Equal(1))
+			_, userID, week := planStore.GetArgsForCall(0)
+			Expect(userID).To(Equal(234))
+			Expect(week).To(Equal("2026-10-19"))
+
+			Expect(recipeStore.GetManyCallCount()).To(Equal(1))
+			_, userID, ids := recipeStore.GetManyArgsForCall(0)
+			Expect(userID).To(Equal(234))
+			Expect(ids).To(Equal([]int{1, 2, 3}))
+
+			Expect(cookLogStore.HistoriesCallCount()).To(Equal(1))
+			_, ids = cookLogStore.HistoriesArgsForCall(0)
+			Expect(ids).To(ConsistOf(1, 2, 3))
+
+			By("leaving the leftovers out of the shopping list")
+			Expect(builder.BuildCallCount()).To(Equal(1))
+			names := []string{}
+			for _, r := range builder.BuildArgsForCall(0) {
+				names = append(names, r.Name)
+			}
+			Expect(names).To(Equal([]string{"recipe 1", "recipe 2", "recipe 3"}))
+		})
+
+		When("the week isn't a Monday", func() {
+			BeforeEach(func() {
+				body = `{"query": "{ plan(week: \"2026-10-20\") { week } }"}`
+			})
+
+			It("reports the error without asking for the plan", func() {
+				Expect(recorder.Body.String()).To(MatchJSON(`{
+					"data": {"plan": null},
+					"errors": [{"message": "week must be the date of a Monday, e.g. 2026-10-19", "locations": [{"line": 1, "column": 3}], "path": ["plan"]}]
+				}`))
+				Expect(planStore.GetCallCount()).To(BeZero())
+			})
+		})
+
+		When("getting the plan fails", func() {
+			BeforeEach(func() {
+				planStore.GetReturns(models.Plan{}, errors.New("db down"))
+				body = `{"query": "{ plan(week: \"2026-10-19\") { week } }"}`
+			})
+
+			It("reports an internal error without the detail", func() {
+				Expect(recorder.Body.String()).To(MatchJSON(`{
+					"data": {"plan": null},
+					"errors": [{"message": "internal error", "locations": [{"line": 1, "column": 3}], "path": ["plan"]}]
+				}`))
+			})
+		})
+	})
+
+	When("listing the user's recipes", func() {
+		BeforeEach(func() {
+			recipeStore.ListReturns([]models.Recipe{
+				{ID: 1, Name: "recipe 1", LowestRating: 2},
+				{ID: 4, Name: "recipe 4", LowestRating: 5, Favourite: true},
+			}, nil)
+			body = `{"query": "{ viewer { id name recipes(minRating: 3) { id favourite servings } } }"}`
+		})
+
+		It("filters like the REST API and loads details in one batch", func() {
+			Expect(recorder.Body.String()).To(MatchJSON(`{"data": {"viewer": {
+				"id": "234", "name": "forest",
+				"recipes": [{"id": "4", "favourite": true, "servings": 2}]
+			}}}`))
+			_, userID := recipeStore.ListArgsForCall(0)
+			Expect(userID).To(Equal(234))
+			Expect(recipeStore.GetManyCallCount()).To(Equal(1))
+		})
+
+		When("the minimum rating is null", func() {
+			BeforeEach(func() {
+				body = `{"query": "{ viewer { recipes(minRating: null) { id } } }"}`
+			})
+
+			It("lists every recipe", func() {
+				Expect(recorder.Body.String()).To(MatchJSON(`{"data": {"viewer": {"recipes": [{"id": "1"}, {"id": "4"}]}}}`))
+			})
+		})
+
+		When("the minimum rating is a null variable", func() {
+			BeforeEach(func() {
+				body = `{
+					"query": "query Recipes($min: Int) { viewer { recipes(minRating: $min) { id } } }",
+					"variables": {"min": null}
+				}`
+			})
+
+			It("lists every recipe", func() {
+				Expect(recorder.Body.String()).To(MatchJSON(`{"data": {"viewer": {"recipes": [{"id": "1"}, {"id": "4"}]}}}`))
+			})
+		})
+	})
+
+	When("a recipe isn't the user's", func() {
+		BeforeEach(func() {
+			body = `{"query": "{ recipe(id: 9) { name } shoppingList(recipes: [1, 9]) { categories { name } } }"}`
+		})
+
+		It("is null, and the shopping list fails", func() {
+			Expect(recorder.Body.String()).To(MatchJSON(`{
+				"data": {"recipe": null, "shoppingList": null},
+				"errors": [{"message": "recipe 9 not found", "locations": [{"line": 1, "column": 26}], "path": ["shoppingList"]}]
+			}`))
+			Expect(recipeStore.GetManyCallCount()).To(Equal(1))
+		})
+	})
+
+	When("the store fails", func() {
+		BeforeEach(func() {
+			recipeStore.GetManyReturns(nil, errors.New("db down"))
+			recipeStore.GetManyStub = nil
+			body = `{"query": "{ recipe(id: 1) { name } }"}`
+		})
+
+		It("reports an internal error without the detail", func() {
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+			Expect(recorder.Body.String()).To(MatchJSON(`{
+				"data": {"recipe": null},
+				"errors": [{"message": "internal error", "locations": [{"line": 1, "column": 3}], "path": ["recipe"]}]
+			}`))
+		})
+	})
+
+	When("the query is invalid", func() {
+		BeforeEach(func() {
+			body = `{"query": "{ recipe(id: 1) { colour } }"}`
+		})
+
+		It("reports it as GraphQL errors, without data", func() {
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+			Expect(recorder.Body.String()).To(MatchJSON(`{
+				"errors": [{"message": "Recipe has no field \"colour\"", "locations": [{"line": 1, "column": 19}]}]
+			}`))
+			Expect(recipeStore.GetManyCallCount()).To(Equal(0))
+		})
+	})
+})
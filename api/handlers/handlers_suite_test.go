@@ -1,8 +1,12 @@
 package handlers_test
 
 import (
+	"encoding/json"
+	"net/http/httptest"
 	"testing"
 
+	"github.com/kieron-pivotal/menu-planner-app/models"
+	"github.com/kieron-pivotal/menu-planner-app/problem"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 )
@@ -11,3 +15,37 @@ func TestHandlers(t *testing.T) {
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "Handlers Suite")
 }
+
+// names returns the names of the recipes in a JSON response
+func names(recorder *httptest.ResponseRecorder) []string {
+	recipes := []models.Recipe{}
+	Expect(json.Unmarshal(recorder.Body.Bytes(), &recipes)).To(Succeed())
+	res := []string{}
+	for _, r := range recipes {
+		res = append(res, r.Name)
+	}
+	return res
+}
+
+// problemCode returns the code of a problem+json response
+func problemCode(recorder *httptest.ResponseRecorder) problem.Code {
+	ExpectWithOffset(1, recorder.Result().Header.Get("Content-Type")).To(Equal(problem.ContentType))
+	p := problem.Problem{}
+	ExpectWithOffset(1, json.Unmarshal(recorder.Body.Bytes(), &p)).To(Succeed())
+	ExpectWithOffset(1, p.Status).To(Equal(recorder.Result().StatusCode))
+	return p.Code
+}
+
+// fieldErrors returns the invalid fields listed in a problem+json response
+func fieldErrors(recorder *httptest.ResponseRecorder) []problem.FieldError {
+	p := problem.Problem{}
+	ExpectWithOffset(1, json.Unmarshal(recorder.Body.Bytes(), &p)).To(Succeed())
+	return p.Errors
+}
+
+// conflicts returns the resources listed in a 409 problem+json response
+func conflicts(recorder *httptest.ResponseRecorder) []problem.Conflict {
+	p := problem.Problem{}
+	ExpectWithOffset(1, json.Unmarshal(recorder.Body.Bytes(), &p)).To(Succeed())
+	return p.Conflicts
+}
@@ -0,0 +1,343 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package handlersfakes
+
+import (
+	"context"
+	"sync"
+
+	"github.com/kieron-pivotal/menu-planner-app/handlers"
+)
+
+type FakeCalendarTokenStore struct {
+	DeleteStub        func(context.Context, int) error
+	deleteMutex       sync.RWMutex
+	deleteArgsForCall []struct {
+		arg1 context.Context
+		arg2 int
+	}
+	deleteReturns struct {
+		result1 error
+	}
+	deleteReturnsOnCall map[int]struct {
+		result1 error
+	}
+	FindUserStub        func(context.Context, string) (int, error)
+	findUserMutex       sync.RWMutex
+	findUserArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	findUserReturns struct {
+		result1 int
+		result2 error
+	}
+	findUserReturnsOnCall map[int]struct {
+		result1 int
+		result2 error
+	}
+	IsNotFoundErrStub        func(error) bool
+	isNotFoundErrMutex       sync.RWMutex
+	isNotFoundErrArgsForCall []struct {
+		arg1 error
+	}
+	isNotFoundErrReturns struct {
+		result1 bool
+	}
+	isNotFoundErrReturnsOnCall map[int]struct {
+		result1 bool
+	}
+	SaveStub        func(context.Context, int, string) error
+	saveMutex       sync.RWMutex
+	saveArgsForCall []struct {
+		arg1 context.Context
+		arg2 int
+		arg3 string
+	}
+	saveReturns struct {
+		result1 error
+	}
+	saveReturnsOnCall map[int]struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeCalendarTokenStore) Delete(arg1 context.Context, arg2 int) error {
+	fake.deleteMutex.Lock()
+	ret, specificReturn := fake.deleteReturnsOnCall[len(fake.deleteArgsForCall)]
+	fake.deleteArgsForCall = append(fake.deleteArgsForCall, struct {
+		arg1 context.Context
+		arg2 int
+	}{arg1, arg2})
+	fake.recordInvocation("Delete", []interface{}{arg1, arg2})
+	fake.deleteMutex.Unlock()
+	if fake.DeleteStub != nil {
+		return fake.DeleteStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.deleteReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeCalendarTokenStore) DeleteCallCount() int {
+	fake.deleteMutex.RLock()
+	defer fake.deleteMutex.RUnlock()
+	return len(fake.deleteArgsForCall)
+}
+
+func (fake *FakeCalendarTokenStore) DeleteCalls(stub func(context.Context, int) error) {
+	fake.deleteMutex.Lock()
+	defer fake.deleteMutex.Unlock()
+	fake.DeleteStub = stub
+}
+
+func (fake *FakeCalendarTokenStore) DeleteArgsForCall(i int) (context.Context, int) {
+	fake.deleteMutex.RLock()
+	defer fake.deleteMutex.RUnlock()
+	argsForCall := fake.deleteArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeCalendarTokenStore) DeleteReturns(result1 error) {
+	fake.deleteMutex.Lock()
+	defer fake.deleteMutex.Unlock()
+	fake.DeleteStub = nil
+	fake.deleteReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeCalendarTokenStore) DeleteReturnsOnCall(i int, result1 error) {
+	fake.deleteMutex.Lock()
+	defer fake.deleteMutex.Unlock()
+	fake.DeleteStub = nil
+	if fake.deleteReturnsOnCall == nil {
+		fake.deleteReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeCalendarTokenStore) FindUser(arg1 context.Context, arg2 string) (int, error) {
+	fake.findUserMutex.Lock()
+	ret, specificReturn := fake.findUserReturnsOnCall[len(fake.findUserArgsForCall)]
+	fake.findUserArgsForCall = append(fake.findUserArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	fake.recordInvocation("FindUser", []interface{}{arg1, arg2})
+	fake.findUserMutex.Unlock()
+	if fake.FindUserStub != nil {
+		return fake.FindUserStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.findUserReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeCalendarTokenStore) FindUserCallCount() int {
+	fake.findUserMutex.RLock()
+	defer fake.findUserMutex.RUnlock()
+	return len(fake.findUserArgsForCall)
+}
+
+func (fake *FakeCalendarTokenStore) FindUserCalls(stub func(context.Context, string) (int, error)) {
+	fake.findUserMutex.Lock()
+	defer fake.findUserMutex.Unlock()
+	fake.FindUserStub = stub
+}
+
+func (fake *FakeCalendarTokenStore) FindUserArgsForCall(i int) (context.Context, string) {
+	fake.findUserMutex.RLock()
+	defer fake.findUserMutex.RUnlock()
+	argsForCall := fake.findUserArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeCalendarTokenStore) FindUserReturns(result1 int, result2 error) {
+	fake.findUserMutex.Lock()
+	defer fake.findUserMutex.Unlock()
+	fake.FindUserStub = nil
+	fake.findUserReturns = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCalendarTokenStore) FindUserReturnsOnCall(i int, result1 int, result2 error) {
+	fake.findUserMutex.Lock()
+	defer fake.findUserMutex.Unlock()
+	fake.FindUserStub = nil
+	if fake.findUserReturnsOnCall == nil {
+		fake.findUserReturnsOnCall = make(map[int]struct {
+			result1 int
+			result2 error
+		})
+	}
+	fake.findUserReturnsOnCall[i] = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCalendarTokenStore) IsNotFoundErr(arg1 error) bool {
+	fake.isNotFoundErrMutex.Lock()
+	ret, specificReturn := fake.isNotFoundErrReturnsOnCall[len(fake.isNotFoundErrArgsForCall)]
+	fake.isNotFoundErrArgsForCall = append(fake.isNotFoundErrArgsForCall, struct {
+		arg1 error
+	}{arg1})
+	fake.recordInvocation("IsNotFoundErr", []interface{}{arg1})
+	fake.isNotFoundErrMutex.Unlock()
+	if fake.IsNotFoundErrStub != nil {
+		return fake.IsNotFoundErrStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.isNotFoundErrReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeCalendarTokenStore) IsNotFoundErrCallCount() int {
+	fake.isNotFoundErrMutex.RLock()
+	defer fake.isNotFoundErrMutex.RUnlock()
+	return len(fake.isNotFoundErrArgsForCall)
+}
+
+func (fake *FakeCalendarTokenStore) IsNotFoundErrCalls(stub func(error) bool) {
+	fake.isNotFoundErrMutex.Lock()
+	defer fake.isNotFoundErrMutex.Unlock()
+	fake.IsNotFoundErrStub = stub
+}
+
+func (fake *FakeCalendarTokenStore) IsNotFoundErrArgsForCall(i int) error {
+	fake.isNotFoundErrMutex.RLock()
+	defer fake.isNotFoundErrMutex.RUnlock()
+	argsForCall := fake.isNotFoundErrArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeCalendarTokenStore) IsNotFoundErrReturns(result1 bool) {
+	fake.isNotFoundErrMutex.Lock()
+	defer fake.isNotFoundErrMutex.Unlock()
+	fake.IsNotFoundErrStub = nil
+	fake.isNotFoundErrReturns = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *FakeCalendarTokenStore) IsNotFoundErrReturnsOnCall(i int, result1 bool) {
+	fake.isNotFoundErrMutex.Lock()
+	defer fake.isNotFoundErrMutex.Unlock()
+	fake.IsNotFoundErrStub = nil
+	if fake.isNotFoundErrReturnsOnCall == nil {
+		fake.isNotFoundErrReturnsOnCall = make(map[int]struct {
+			result1 bool
+		})
+	}
+	fake.isNotFoundErrReturnsOnCall[i] = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *FakeCalendarTokenStore) Save(arg1 context.Context, arg2 int, arg3 string) error {
+	fake.saveMutex.Lock()
+	ret, specificReturn := fake.saveReturnsOnCall[len(fake.saveArgsForCall)]
+	fake.saveArgsForCall = append(fake.saveArgsForCall, struct {
+		arg1 context.Context
+		arg2 int
+		arg3 string
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("Save", []interface{}{arg1, arg2, arg3})
+	fake.saveMutex.Unlock()
+	if fake.SaveStub != nil {
+		return fake.SaveStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.saveReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeCalendarTokenStore) SaveCallCount() int {
+	fake.saveMutex.RLock()
+	defer fake.saveMutex.RUnlock()
+	return len(fake.saveArgsForCall)
+}
+
+func (fake *FakeCalendarTokenStore) SaveCalls(stub func(context.Context, int, string) error) {
+	fake.saveMutex.Lock()
+	defer fake.saveMutex.Unlock()
+	fake.SaveStub = stub
+}
+
+func (fake *FakeCalendarTokenStore) SaveArgsForCall(i int) (context.Context, int, string) {
+	fake.saveMutex.RLock()
+	defer fake.saveMutex.RUnlock()
+	argsForCall := fake.saveArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeCalendarTokenStore) SaveReturns(result1 error) {
+	fake.saveMutex.Lock()
+	defer fake.saveMutex.Unlock()
+	fake.SaveStub = nil
+	fake.saveReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeCalendarTokenStore) SaveReturnsOnCall(i int, result1 error) {
+	fake.saveMutex.Lock()
+	defer fake.saveMutex.Unlock()
+	fake.SaveStub = nil
+	if fake.saveReturnsOnCall == nil {
+		fake.saveReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.saveReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeCalendarTokenStore) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.deleteMutex.RLock()
+	defer fake.deleteMutex.RUnlock()
+	fake.findUserMutex.RLock()
+	defer fake.findUserMutex.RUnlock()
+	fake.isNotFoundErrMutex.RLock()
+	defer fake.isNotFoundErrMutex.RUnlock()
+	fake.saveMutex.RLock()
+	defer fake.saveMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeCalendarTokenStore) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ handlers.CalendarTokenStore = new(FakeCalendarTokenStore)
@@ -0,0 +1,521 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package handlersfakes
+
+import (
+	"context"
+	"sync"
+
+	"github.com/kieron-pivotal/menu-planner-app/handlers"
+	"github.com/kieron-pivotal/menu-planner-app/models"
+)
+
+type FakeCookLogStore struct {
+	AddStub        func(context.Context, models.Cooked) (models.Cooked, error)
+	addMutex       sync.RWMutex
+	addArgsForCall []struct {
+		arg1 context.Context
+		arg2 models.Cooked
+	}
+	addReturns struct {
+		result1 models.Cooked
+		result2 error
+	}
+	addReturnsOnCall map[int]struct {
+		result1 models.Cooked
+		result2 error
+	}
+	EatLeftoversStub        func(context.Context, int, int, int) (models.Cooked, error)
+	eatLeftoversMutex       sync.RWMutex
+	eatLeftoversArgsForCall []struct {
+		arg1 context.Context
+		arg2 int
+		arg3 int
+		arg4 int
+	}
+	eatLeftoversReturns struct {
+		result1 models.Cooked
+		result2 error
+	}
+	eatLeftoversReturnsOnCall map[int]struct {
+		result1 models.Cooked
+		result2 error
+	}
+	HistoriesStub        func(context.Context, []int) ([]models.Cooked, error)
+	historiesMutex       sync.RWMutex
+	historiesArgsForCall []struct {
+		arg1 context.Context
+		arg2 []int
+	}
+	historiesReturns struct {
+		result1 []models.Cooked
+		result2 error
+	}
+	historiesReturnsOnCall map[int]struct {
+		result1 []models.Cooked
+		result2 error
+	}
+	HistoryStub        func(context.Context, int) ([]models.Cooked, error)
+	historyMutex       sync.RWMutex
+	historyArgsForCall []struct {
+		arg1 context.Context
+		arg2 int
+	}
+	historyReturns struct {
+		result1 []models.Cooked
+		result2 error
+	}
+	historyReturnsOnCall map[int]struct {
+		result1 []models.Cooked
+		result2 error
+	}
+	IsNotFoundErrStub        func(error) bool
+	isNotFoundErrMutex       sync.RWMutex
+	isNotFoundErrArgsForCall []struct {
+		arg1 error
+	}
+	isNotFoundErrReturns struct {
+		result1 bool
+	}
+	isNotFoundErrReturnsOnCall map[int]struct {
+		result1 bool
+	}
+	LeftoversStub        func(context.Context, int) ([]models.Cooked, error)
+	leftoversMutex       sync.RWMutex
+	leftoversArgsForCall []struct {
+		arg1 context.Context
+		arg2 int
+	}
+	leftoversReturns struct {
+		result1 []models.Cooked
+		result2 error
+	}
+	leftoversReturnsOnCall map[int]struct {
+		result1 []models.Cooked
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeCookLogStore) Add(arg1 context.Context, arg2 models.Cooked) (models.Cooked, error) {
+	fake.addMutex.Lock()
+	ret, specificReturn := fake.addReturnsOnCall[len(fake.addArgsForCall)]
+	fake.addArgsForCall = append(fake.addArgsForCall, struct {
+		arg1 context.Context
+		arg2 models.Cooked
+	}{arg1, arg2})
+	fake.recordInvocation("Add", []interface{}{arg1, arg2})
+	fake.addMutex.Unlock()
+	if fake.AddStub != nil {
+		return fake.AddStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.addReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeCookLogStore) AddCallCount() int {
+	fake.addMutex.RLock()
+	defer fake.addMutex.RUnlock()
+	return len(fake.addArgsForCall)
+}
+
+func (fake *FakeCookLogStore) AddCalls(stub func(context.Context, models.Cooked) (models.Cooked, error)) {
+	fake.addMutex.Lock()
+	defer fake.addMutex.Unlock()
+	fake.AddStub = stub
+}
+
+func (fake *FakeCookLogStore) AddArgsForCall(i int) (context.Context, models.Cooked) {
+	fake.addMutex.RLock()
+	defer fake.addMutex.RUnlock()
+	argsForCall := fake.addArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeCookLogStore) AddReturns(result1 models.Cooked, result2 error) {
+	fake.addMutex.Lock()
+	defer fake.addMutex.Unlock()
+	fake.AddStub = nil
+	fake.addReturns = struct {
+		result1 models.Cooked
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCookLogStore) AddReturnsOnCall(i int, result1 models.Cooked, result2 error) {
+	fake.addMutex.Lock()
+	defer fake.addMutex.Unlock()
+	fake.AddStub = nil
+	if fake.addReturnsOnCall == nil {
+		fake.addReturnsOnCall = make(map[int]struct {
+			result1 models.Cooked
+			result2 error
+		})
+	}
+	fake.addReturnsOnCall[i] = struct {
+		result1 models.Cooked
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCookLogStore) EatLeftovers(arg1 context.Context, arg2 int, arg3 int, arg4 int) (models.Cooked, error) {
+	fake.eatLeftoversMutex.Lock()
+	ret, specificReturn := fake.eatLeftoversReturnsOnCall[len(fake.eatLeftoversArgsForCall)]
+	fake.eatLeftoversArgsForCall = append(fake.eatLeftoversArgsForCall, struct {
+		arg1 context.Context
+		arg2 int
+		arg3 int
+		arg4 int
+	}{arg1, arg2, arg3, arg4})
+	fake.recordInvocation("EatLeftovers", []interface{}{arg1, arg2, arg3, arg4})
+	fake.eatLeftoversMutex.Unlock()
+	if fake.EatLeftoversStub != nil {
+		return fake.EatLeftoversStub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.eatLeftoversReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeCookLogStore) EatLeftoversCallCount() int {
+	fake.eatLeftoversMutex.RLock()
+	defer fake.eatLeftoversMutex.RUnlock()
+	return len(fake.eatLeftoversArgsForCall)
+}
+
+func (fake *FakeCookLogStore) EatLeftoversCalls(stub func(context.Context, int, int, int) (models.Cooked, error)) {
+	fake.eatLeftoversMutex.Lock()
+	defer fake.eatLeftoversMutex.Unlock()
+	fake.EatLeftoversStub = stub
+}
+
+func (fake *FakeCookLogStore) EatLeftoversArgsForCall(i int) (context.Context, int, int, int) {
+	fake.eatLeftoversMutex.RLock()
+	defer fake.eatLeftoversMutex.RUnlock()
+	argsForCall := fake.eatLeftoversArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeCookLogStore) EatLeftoversReturns(result1 models.Cooked, result2 error) {
+	fake.eatLeftoversMutex.Lock()
+	defer fake.eatLeftoversMutex.Unlock()
+	fake.EatLeftoversStub = nil
+	fake.eatLeftoversReturns = struct {
+		result1 models.Cooked
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCookLogStore) EatLeftoversReturnsOnCall(i int, result1 models.Cooked, result2 error) {
+	fake.eatLeftoversMutex.Lock()
+	defer fake.eatLeftoversMutex.Unlock()
+	fake.EatLeftoversStub = nil
+	if fake.eatLeftoversReturnsOnCall == nil {
+		fake.eatLeftoversReturnsOnCall = make(map[int]struct {
+			result1 models.Cooked
+			result2 error
+		})
+	}
+	fake.eatLeftoversReturnsOnCall[i] = struct {
+		result1 models.Cooked
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCookLogStore) Histories(arg1 context.Context, arg2 []int) ([]models.Cooked, error) {
+	var arg2Copy []int
+	if arg2 != nil {
+		arg2Copy = make([]int, len(arg2))
+		copy(arg2Copy, arg2)
+	}
+	fake.historiesMutex.Lock()
+	ret, specificReturn := fake.historiesReturnsOnCall[len(fake.historiesArgsForCall)]
+	fake.historiesArgsForCall = append(fake.historiesArgsForCall, struct {
+		arg1 context.Context
+		arg2 []int
+	}{arg1, arg2Copy})
+	fake.recordInvocation("Histories", []interface{}{arg1, arg2Copy})
+	fake.historiesMutex.Unlock()
+	if fake.HistoriesStub != nil {
+		return fake.HistoriesStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.historiesReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeCookLogStore) HistoriesCallCount() int {
+	fake.historiesMutex.RLock()
+	defer fake.historiesMutex.RUnlock()
+	return len(fake.historiesArgsForCall)
+}
+
+func (fake *FakeCookLogStore) HistoriesCalls(stub func(context.Context, []int) ([]models.Cooked, error)) {
+	fake.historiesMutex.Lock()
+	defer fake.historiesMutex.Unlock()
+	fake.HistoriesStub = stub
+}
+
+func (fake *FakeCookLogStore) HistoriesArgsForCall(i int) (context.Context, []int) {
+	fake.historiesMutex.RLock()
+	defer fake.historiesMutex.RUnlock()
+	argsForCall := fake.historiesArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeCookLogStore) HistoriesReturns(result1 []models.Cooked, result2 error) {
+	fake.historiesMutex.Lock()
+	defer fake.historiesMutex.Unlock()
+	fake.HistoriesStub = nil
+	fake.historiesReturns = struct {
+		result1 []models.Cooked
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCookLogStore) HistoriesReturnsOnCall(i int, result1 []models.Cooked, result2 error) {
+	fake.historiesMutex.Lock()
+	defer fake.historiesMutex.Unlock()
+	fake.HistoriesStub = nil
+	if fake.historiesReturnsOnCall == nil {
+		fake.historiesReturnsOnCall = make(map[int]struct {
+			result1 []models.Cooked
+			result2 error
+		})
+	}
+	fake.historiesReturnsOnCall[i] = struct {
+		result1 []models.Cooked
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCookLogStore) History(arg1 context.Context, arg2 int) ([]models.Cooked, error) {
+	fake.historyMutex.Lock()
+	ret, specificReturn := fake.historyReturnsOnCall[len(fake.historyArgsForCall)]
+	fake.historyArgsForCall = append(fake.historyArgsForCall, struct {
+		arg1 context.Context
+		arg2 int
+	}{arg1, arg2})
+	fake.recordInvocation("History", []interface{}{arg1, arg2})
+	fake.historyMutex.Unlock()
+	if fake.HistoryStub != nil {
+		return fake.HistoryStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.historyReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeCookLogStore) HistoryCallCount() int {
+	fake.historyMutex.RLock()
+	defer fake.historyMutex.RUnlock()
+	return len(fake.historyArgsForCall)
+}
+
+func (fake *FakeCookLogStore) HistoryCalls(stub func(context.Context, int) ([]models.Cooked, error)) {
+	fake.historyMutex.Lock()
+	defer fake.historyMutex.Unlock()
+	fake.HistoryStub = stub
+}
+
+func (fake *FakeCookLogStore) HistoryArgsForCall(i int) (context.Context, int) {
+	fake.historyMutex.RLock()
+	defer fake.historyMutex.RUnlock()
+	argsForCall := fake.historyArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeCookLogStore) HistoryReturns(result1 []models.Cooked, result2 error) {
+	fake.historyMutex.Lock()
+	defer fake.historyMutex.Unlock()
+	fake.HistoryStub = nil
+	fake.historyReturns = struct {
+		result1 []models.Cooked
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCookLogStore) HistoryReturnsOnCall(i int, result1 []models.Cooked, result2 error) {
+	fake.historyMutex.Lock()
+	defer fake.historyMutex.Unlock()
+	fake.HistoryStub = nil
+	if fake.historyReturnsOnCall == nil {
+		fake.historyReturnsOnCall = make(map[int]struct {
+			result1 []models.Cooked
+			result2 error
+		})
+	}
+	fake.historyReturnsOnCall[i] = struct {
+		result1 []models.Cooked
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCookLogStore) IsNotFoundErr(arg1 error) bool {
+	fake.isNotFoundErrMutex.Lock()
+	ret, specificReturn := fake.isNotFoundErrReturnsOnCall[len(fake.isNotFoundErrArgsForCall)]
+	fake.isNotFoundErrArgsForCall = append(fake.isNotFoundErrArgsForCall, struct {
+		arg1 error
+	}{arg1})
+	fake.recordInvocation("IsNotFoundErr", []interface{}{arg1})
+	fake.isNotFoundErrMutex.Unlock()
+	if fake.IsNotFoundErrStub != nil {
+		return fake.IsNotFoundErrStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.isNotFoundErrReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeCookLogStore) IsNotFoundErrCallCount() int {
+	fake.isNotFoundErrMutex.RLock()
+	defer fake.isNotFoundErrMutex.RUnlock()
+	return len(fake.isNotFoundErrArgsForCall)
+}
+
+func (fake *FakeCookLogStore) IsNotFoundErrCalls(stub func(error) bool) {
+	fake.isNotFoundErrMutex.Lock()
+	defer fake.isNotFoundErrMutex.Unlock()
+	fake.IsNotFoundErrStub = stub
+}
+
+func (fake *FakeCookLogStore) IsNotFoundErrArgsForCall(i int) error {
+	fake.isNotFoundErrMutex.RLock()
+	defer fake.isNotFoundErrMutex.RUnlock()
+	argsForCall := fake.isNotFoundErrArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeCookLogStore) IsNotFoundErrReturns(result1 bool) {
+	fake.isNotFoundErrMutex.Lock()
+	defer fake.isNotFoundErrMutex.Unlock()
+	fake.IsNotFoundErrStub = nil
+	fake.isNotFoundErrReturns = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *FakeCookLogStore) IsNotFoundErrReturnsOnCall(i int, result1 bool) {
+	fake.isNotFoundErrMutex.Lock()
+	defer fake.isNotFoundErrMutex.Unlock()
+	fake.IsNotFoundErrStub = nil
+	if fake.isNotFoundErrReturnsOnCall == nil {
+		fake.isNotFoundErrReturnsOnCall = make(map[int]struct {
+			result1 bool
+		})
+	}
+	fake.isNotFoundErrReturnsOnCall[i] = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *FakeCookLogStore) Leftovers(arg1 context.Context, arg2 int) ([]models.Cooked, error) {
+	fake.leftoversMutex.Lock()
+	ret, specificReturn := fake.leftoversReturnsOnCall[len(fake.leftoversArgsForCall)]
+	fake.leftoversArgsForCall = append(fake.leftoversArgsForCall, struct {
+		arg1 context.Context
+		arg2 int
+	}{arg1, arg2})
+	fake.recordInvocation("Leftovers", []interface{}{arg1, arg2})
+	fake.leftoversMutex.Unlock()
+	if fake.LeftoversStub != nil {
+		return fake.LeftoversStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.leftoversReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeCookLogStore) LeftoversCallCount() int {
+	fake.leftoversMutex.RLock()
+	defer fake.leftoversMutex.RUnlock()
+	return len(fake.leftoversArgsForCall)
+}
+
+func (fake *FakeCookLogStore) LeftoversCalls(stub func(context.Context, int) ([]models.Cooked, error)) {
+	fake.leftoversMutex.Lock()
+	defer fake.leftoversMutex.Unlock()
+	fake.LeftoversStub = stub
+}
+
+func (fake *FakeCookLogStore) LeftoversArgsForCall(i int) (context.Context, int) {
+	fake.leftoversMutex.RLock()
+	defer fake.leftoversMutex.RUnlock()
+	argsForCall := fake.leftoversArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeCookLogStore) LeftoversReturns(result1 []models.Cooked, result2 error) {
+	fake.leftoversMutex.Lock()
+	defer fake.leftoversMutex.Unlock()
+	fake.LeftoversStub = nil
+	fake.leftoversReturns = struct {
+		result1 []models.Cooked
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCookLogStore) LeftoversReturnsOnCall(i int, result1 []models.Cooked, result2 error) {
+	fake.leftoversMutex.Lock()
+	defer fake.leftoversMutex.Unlock()
+	fake.LeftoversStub = nil
+	if fake.leftoversReturnsOnCall == nil {
+		fake.leftoversReturnsOnCall = make(map[int]struct {
+			result1 []models.Cooked
+			result2 error
+		})
+	}
+	fake.leftoversReturnsOnCall[i] = struct {
+		result1 []models.Cooked
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCookLogStore) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.addMutex.RLock()
+	defer fake.addMutex.RUnlock()
+	fake.eatLeftoversMutex.RLock()
+	defer fake.eatLeftoversMutex.RUnlock()
+	fake.historiesMutex.RLock()
+	defer fake.historiesMutex.RUnlock()
+	fake.historyMutex.RLock()
+	defer fake.historyMutex.RUnlock()
+	fake.isNotFoundErrMutex.RLock()
+	defer fake.isNotFoundErrMutex.RUnlock()
+	fake.leftoversMutex.RLock()
+	defer fake.leftoversMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeCookLogStore) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ handlers.CookLogStore = new(FakeCookLogStore)
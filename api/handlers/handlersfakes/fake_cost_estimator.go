@@ -0,0 +1,206 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package handlersfakes
+
+import (
+	"context"
+	"sync"
+
+	"github.com/kieron-pivotal/menu-planner-app/handlers"
+	"github.com/kieron-pivotal/menu-planner-app/models"
+)
+
+type FakeCostEstimator struct {
+	ForRecipeStub        func(context.Context, models.Recipe) (*models.Cost, error)
+	forRecipeMutex       sync.RWMutex
+	forRecipeArgsForCall []struct {
+		arg1 context.Context
+		arg2 models.Recipe
+	}
+	forRecipeReturns struct {
+		result1 *models.Cost
+		result2 error
+	}
+	forRecipeReturnsOnCall map[int]struct {
+		result1 *models.Cost
+		result2 error
+	}
+	ForRecipesStub        func(context.Context, int, []models.Recipe) (map[int]*models.Cost, error)
+	forRecipesMutex       sync.RWMutex
+	forRecipesArgsForCall []struct {
+		arg1 context.Context
+		arg2 int
+		arg3 []models.Recipe
+	}
+	forRecipesReturns struct {
+		result1 map[int]*models.Cost
+		result2 error
+	}
+	forRecipesReturnsOnCall map[int]struct {
+		result1 map[int]*models.Cost
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeCostEstimator) ForRecipe(arg1 context.Context, arg2 models.Recipe) (*models.Cost, error) {
+	fake.forRecipeMutex.Lock()
+	ret, specificReturn := fake.forRecipeReturnsOnCall[len(fake.forRecipeArgsForCall)]
+	fake.forRecipeArgsForCall = append(fake.forRecipeArgsForCall, struct {
+		arg1 context.Context
+		arg2 models.Recipe
+	}{arg1, arg2})
+	fake.recordInvocation("ForRecipe", []interface{}{arg1, arg2})
+	fake.forRecipeMutex.Unlock()
+	if fake.ForRecipeStub != nil {
+		return fake.ForRecipeStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.forRecipeReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeCostEstimator) ForRecipeCallCount() int {
+	fake.forRecipeMutex.RLock()
+	defer fake.forRecipeMutex.RUnlock()
+	return len(fake.forRecipeArgsForCall)
+}
+
+func (fake *FakeCostEstimator) ForRecipeCalls(stub func(context.Context, models.Recipe) (*models.Cost, error)) {
+	fake.forRecipeMutex.Lock()
+	defer fake.forRecipeMutex.Unlock()
+	fake.ForRecipeStub = stub
+}
+
+func (fake *FakeCostEstimator) ForRecipeArgsForCall(i int) (context.Context, models.Recipe) {
+	fake.forRecipeMutex.RLock()
+	defer fake.forRecipeMutex.RUnlock()
+	argsForCall := fake.forRecipeArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeCostEstimator) ForRecipeReturns(result1 *models.Cost, result2 error) {
+	fake.forRecipeMutex.Lock()
+	defer fake.forRecipeMutex.Unlock()
+	fake.ForRecipeStub = nil
+	fake.forRecipeReturns = struct {
+		result1 *models.Cost
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCostEstimator) ForRecipeReturnsOnCall(i int, result1 *models.Cost, result2 error) {
+	fake.forRecipeMutex.Lock()
+	defer fake.forRecipeMutex.Unlock()
+	fake.ForRecipeStub = nil
+	if fake.forRecipeReturnsOnCall == nil {
+		fake.forRecipeReturnsOnCall = make(map[int]struct {
+			result1 *models.Cost
+			result2 error
+		})
+	}
+	fake.forRecipeReturnsOnCall[i] = struct {
+		result1 *models.Cost
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCostEstimator) ForRecipes(arg1 context.Context, arg2 int, arg3 []models.Recipe) (map[int]*models.Cost, error) {
+	var arg3Copy []models.Recipe
+	if arg3 != nil {
+		arg3Copy = make([]models.Recipe, len(arg3))
+		copy(arg3Copy, arg3)
+	}
+	fake.forRecipesMutex.Lock()
+	ret, specificReturn := fake.forRecipesReturnsOnCall[len(fake.forRecipesArgsForCall)]
+	fake.forRecipesArgsForCall = append(fake.forRecipesArgsForCall, struct {
+		arg1 context.Context
+		arg2 int
+		arg3 []models.Recipe
+	}{arg1, arg2, arg3Copy})
+	fake.recordInvocation("ForRecipes", []interface{}{arg1, arg2, arg3Copy})
+	fake.forRecipesMutex.Unlock()
+	if fake.ForRecipesStub != nil {
+		return fake.ForRecipesStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.forRecipesReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeCostEstimator) ForRecipesCallCount() int {
+	fake.forRecipesMutex.RLock()
+	defer fake.forRecipesMutex.RUnlock()
+	return len(fake.forRecipesArgsForCall)
+}
+
+func (fake *FakeCostEstimator) ForRecipesCalls(stub func(context.Context, int, []models.Recipe) (map[int]*models.Cost, error)) {
+	fake.forRecipesMutex.Lock()
+	defer fake.forRecipesMutex.Unlock()
+	fake.ForRecipesStub = stub
+}
+
+func (fake *FakeCostEstimator) ForRecipesArgsForCall(i int) (context.Context, int, []models.Recipe) {
+	fake.forRecipesMutex.RLock()
+	defer fake.forRecipesMutex.RUnlock()
+	argsForCall := fake.forRecipesArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeCostEstimator) ForRecipesReturns(result1 map[int]*models.Cost, result2 error) {
+	fake.forRecipesMutex.Lock()
+	defer fake.forRecipesMutex.Unlock()
+	fake.ForRecipesStub = nil
+	fake.forRecipesReturns = struct {
+		result1 map[int]*models.Cost
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCostEstimator) ForRecipesReturnsOnCall(i int, result1 map[int]*models.Cost, result2 error) {
+	fake.forRecipesMutex.Lock()
+	defer fake.forRecipesMutex.Unlock()
+	fake.ForRecipesStub = nil
+	if fake.forRecipesReturnsOnCall == nil {
+		fake.forRecipesReturnsOnCall = make(map[int]struct {
+			result1 map[int]*models.Cost
+			result2 error
+		})
+	}
+	fake.forRecipesReturnsOnCall[i] = struct {
+		result1 map[int]*models.Cost
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCostEstimator) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.forRecipeMutex.RLock()
+	defer fake.forRecipeMutex.RUnlock()
+	fake.forRecipesMutex.RLock()
+	defer fake.forRecipesMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeCostEstimator) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ handlers.CostEstimator = new(FakeCostEstimator)
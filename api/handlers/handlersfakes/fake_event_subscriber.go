@@ -0,0 +1,116 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package handlersfakes
+
+import (
+	"sync"
+
+	"github.com/kieron-pivotal/menu-planner-app/handlers"
+	"github.com/kieron-pivotal/menu-planner-app/models"
+)
+
+type FakeEventSubscriber struct {
+	SubscribeStub        func(int) (<-chan models.Event, func())
+	subscribeMutex       sync.RWMutex
+	subscribeArgsForCall []struct {
+		arg1 int
+	}
+	subscribeReturns struct {
+		result1 <-chan models.Event
+		result2 func()
+	}
+	subscribeReturnsOnCall map[int]struct {
+		result1 <-chan models.Event
+		result2 func()
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeEventSubscriber) Subscribe(arg1 int) (<-chan models.Event, func()) {
+	fake.subscribeMutex.Lock()
+	ret, specificReturn := fake.subscribeReturnsOnCall[len(fake.subscribeArgsForCall)]
+	fake.subscribeArgsForCall = append(fake.subscribeArgsForCall, struct {
+		arg1 int
+	}{arg1})
+	fake.recordInvocation("Subscribe", []interface{}{arg1})
+	fake.subscribeMutex.Unlock()
+	if fake.SubscribeStub != nil {
+		return fake.SubscribeStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.subscribeReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeEventSubscriber) SubscribeCallCount() int {
+	fake.subscribeMutex.RLock()
+	defer fake.subscribeMutex.RUnlock()
+	return len(fake.subscribeArgsForCall)
+}
+
+func (fake *FakeEventSubscriber) SubscribeCalls(stub func(int) (<-chan models.Event, func())) {
+	fake.subscribeMutex.Lock()
+	defer fake.subscribeMutex.Unlock()
+	fake.SubscribeStub = stub
+}
+
+func (fake *FakeEventSubscriber) SubscribeArgsForCall(i int) int {
+	fake.subscribeMutex.RLock()
+	defer fake.subscribeMutex.RUnlock()
+	argsForCall := fake.subscribeArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeEventSubscriber) SubscribeReturns(result1 <-chan models.Event, result2 func()) {
+	fake.subscribeMutex.Lock()
+	defer fake.subscribeMutex.Unlock()
+	fake.SubscribeStub = nil
+	fake.subscribeReturns = struct {
+		result1 <-chan models.Event
+		result2 func()
+	}{result1, result2}
+}
+
+func (fake *FakeEventSubscriber) SubscribeReturnsOnCall(i int, result1 <-chan models.Event, result2 func()) {
+	fake.subscribeMutex.Lock()
+	defer fake.subscribeMutex.Unlock()
+	fake.SubscribeStub = nil
+	if fake.subscribeReturnsOnCall == nil {
+		fake.subscribeReturnsOnCall = make(map[int]struct {
+			result1 <-chan models.Event
+			result2 func()
+		})
+	}
+	fake.subscribeReturnsOnCall[i] = struct {
+		result1 <-chan models.Event
+		result2 func()
+	}{result1, result2}
+}
+
+func (fake *FakeEventSubscriber) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.subscribeMutex.RLock()
+	defer fake.subscribeMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeEventSubscriber) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ handlers.EventSubscriber = new(FakeEventSubscriber)
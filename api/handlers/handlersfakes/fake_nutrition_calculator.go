@@ -0,0 +1,111 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package handlersfakes
+
+import (
+	"sync"
+
+	"github.com/kieron-pivotal/menu-planner-app/handlers"
+	"github.com/kieron-pivotal/menu-planner-app/models"
+)
+
+type FakeNutritionCalculator struct {
+	ForRecipeStub        func(models.Recipe) *models.Nutrition
+	forRecipeMutex       sync.RWMutex
+	forRecipeArgsForCall []struct {
+		arg1 models.Recipe
+	}
+	forRecipeReturns struct {
+		result1 *models.Nutrition
+	}
+	forRecipeReturnsOnCall map[int]struct {
+		result1 *models.Nutrition
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeNutritionCalculator) ForRecipe(arg1 models.Recipe) *models.Nutrition {
+	fake.forRecipeMutex.Lock()
+	ret, specificReturn := fake.forRecipeReturnsOnCall[len(fake.forRecipeArgsForCall)]
+	fake.forRecipeArgsForCall = append(fake.forRecipeArgsForCall, struct {
+		arg1 models.Recipe
+	}{arg1})
+	fake.recordInvocation("ForRecipe", []interface{}{arg1})
+	fake.forRecipeMutex.Unlock()
+	if fake.ForRecipeStub != nil {
+		return fake.ForRecipeStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.forRecipeReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeNutritionCalculator) ForRecipeCallCount() int {
+	fake.forRecipeMutex.RLock()
+	defer fake.forRecipeMutex.RUnlock()
+	return len(fake.forRecipeArgsForCall)
+}
+
+func (fake *FakeNutritionCalculator) ForRecipeCalls(stub func(models.Recipe) *models.Nutrition) {
+	fake.forRecipeMutex.Lock()
+	defer fake.forRecipeMutex.Unlock()
+	fake.ForRecipeStub = stub
+}
+
+func (fake *FakeNutritionCalculator) ForRecipeArgsForCall(i int) models.Recipe {
+	fake.forRecipeMutex.RLock()
+	defer fake.forRecipeMutex.RUnlock()
+	argsForCall := fake.forRecipeArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeNutritionCalculator) ForRecipeReturns(result1 *models.Nutrition) {
+	fake.forRecipeMutex.Lock()
+	defer fake.forRecipeMutex.Unlock()
+	fake.ForRecipeStub = nil
+	fake.forRecipeReturns = struct {
+		result1 *models.Nutrition
+	}{result1}
+}
+
+func (fake *FakeNutritionCalculator) ForRecipeReturnsOnCall(i int, result1 *models.Nutrition) {
+	fake.forRecipeMutex.Lock()
+	defer fake.forRecipeMutex.Unlock()
+	fake.ForRecipeStub = nil
+	if fake.forRecipeReturnsOnCall == nil {
+		fake.forRecipeReturnsOnCall = make(map[int]struct {
+			result1 *models.Nutrition
+		})
+	}
+	fake.forRecipeReturnsOnCall[i] = struct {
+		result1 *models.Nutrition
+	}{result1}
+}
+
+func (fake *FakeNutritionCalculator) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.forRecipeMutex.RLock()
+	defer fake.forRecipeMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeNutritionCalculator) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ handlers.NutritionCalculator = new(FakeNutritionCalculator)
@@ -0,0 +1,280 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package handlersfakes
+
+import (
+	"context"
+	"sync"
+
+	"github.com/kieron-pivotal/menu-planner-app/handlers"
+	"github.com/kieron-pivotal/menu-planner-app/models"
+)
+
+type FakePlanStore struct {
+	GetStub        func(context.Context, int, string) (models.Plan, error)
+	getMutex       sync.RWMutex
+	getArgsForCall []struct {
+		arg1 context.Context
+		arg2 int
+		arg3 string
+	}
+	getReturns struct {
+		result1 models.Plan
+		result2 error
+	}
+	getReturnsOnCall map[int]struct {
+		result1 models.Plan
+		result2 error
+	}
+	SaveStub        func(context.Context, models.Plan) error
+	saveMutex       sync.RWMutex
+	saveArgsForCall []struct {
+		arg1 context.Context
+		arg2 models.Plan
+	}
+	saveReturns struct {
+		result1 error
+	}
+	saveReturnsOnCall map[int]struct {
+		result1 error
+	}
+	WeeksStub        func(context.Context, int, string, string) ([]models.Plan, error)
+	weeksMutex       sync.RWMutex
+	weeksArgsForCall []struct {
+		arg1 context.Context
+		arg2 int
+		arg3 string
+		arg4 string
+	}
+	weeksReturns struct {
+		result1 []models.Plan
+		result2 error
+	}
+	weeksReturnsOnCall map[int]struct {
+		result1 []models.Plan
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakePlanStore) Get(arg1 context.Context, arg2 int, arg3 string) (models.Plan, error) {
+	fake.getMutex.Lock()
+	ret, specificReturn := fake.getReturnsOnCall[len(fake.getArgsForCall)]
+	fake.getArgsForCall = append(fake.getArgsForCall, struct {
+		arg1 context.Context
+		arg2 int
+		arg3 string
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("Get", []interface{}{arg1, arg2, arg3})
+	fake.getMutex.Unlock()
+	if fake.GetStub != nil {
+		return fake.GetStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakePlanStore) GetCallCount() int {
+	fake.getMutex.RLock()
+	defer fake.getMutex.RUnlock()
+	return len(fake.getArgsForCall)
+}
+
+func (fake *FakePlanStore) GetCalls(stub func(context.Context, int, string) (models.Plan, error)) {
+	fake.getMutex.Lock()
+	defer fake.getMutex.Unlock()
+	fake.GetStub = stub
+}
+
+func (fake *FakePlanStore) GetArgsForCall(i int) (context.Context, int, string) {
+	fake.getMutex.RLock()
+	defer fake.getMutex.RUnlock()
+	argsForCall := fake.getArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakePlanStore) GetReturns(result1 models.Plan, result2 error) {
+	fake.getMutex.Lock()
+	defer fake.getMutex.Unlock()
+	fake.GetStub = nil
+	fake.getReturns = struct {
+		result1 models.Plan
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePlanStore) GetReturnsOnCall(i int, result1 models.Plan, result2 error) {
+	fake.getMutex.Lock()
+	defer fake.getMutex.Unlock()
+	fake.GetStub = nil
+	if fake.getReturnsOnCall == nil {
+		fake.getReturnsOnCall = make(map[int]struct {
+			result1 models.Plan
+			result2 error
+		})
+	}
+	fake.getReturnsOnCall[i] = struct {
+		result1 models.Plan
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePlanStore) Save(arg1 context.Context, arg2 models.Plan) error {
+	fake.saveMutex.Lock()
+	ret, specificReturn := fake.saveReturnsOnCall[len(fake.saveArgsForCall)]
+	fake.saveArgsForCall = append(fake.saveArgsForCall, struct {
+		arg1 context.Context
+		arg2 models.Plan
+	}{arg1, arg2})
+	fake.recordInvocation("Save", []interface{}{arg1, arg2})
+	fake.saveMutex.Unlock()
+	if fake.SaveStub != nil {
+		return fake.SaveStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.saveReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakePlanStore) SaveCallCount() int {
+	fake.saveMutex.RLock()
+	defer fake.saveMutex.RUnlock()
+	return len(fake.saveArgsForCall)
+}
+
+func (fake *FakePlanStore) SaveCalls(stub func(context.Context, models.Plan) error) {
+	fake.saveMutex.Lock()
+	defer fake.saveMutex.Unlock()
+	fake.SaveStub = stub
+}
+
+func (fake *FakePlanStore) SaveArgsForCall(i int) (context.Context, models.Plan) {
+	fake.saveMutex.RLock()
+	defer fake.saveMutex.RUnlock()
+	argsForCall := fake.saveArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakePlanStore) SaveReturns(result1 error) {
+	fake.saveMutex.Lock()
+	defer fake.saveMutex.Unlock()
+	fake.SaveStub = nil
+	fake.saveReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePlanStore) SaveReturnsOnCall(i int, result1 error) {
+	fake.saveMutex.Lock()
+	defer fake.saveMutex.Unlock()
+	fake.SaveStub = nil
+	if fake.saveReturnsOnCall == nil {
+		fake.saveReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.saveReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePlanStore) Weeks(arg1 context.Context, arg2 int, arg3 string, arg4 string) ([]models.Plan, error) {
+	fake.weeksMutex.Lock()
+	ret, specificReturn := fake.weeksReturnsOnCall[len(fake.weeksArgsForCall)]
+	fake.weeksArgsForCall = append(fake.weeksArgsForCall, struct {
+		arg1 context.Context
+		arg2 int
+		arg3 string
+		arg4 string
+	}{arg1, arg2, arg3, arg4})
+	fake.recordInvocation("Weeks", []interface{}{arg1, arg2, arg3, arg4})
+	fake.weeksMutex.Unlock()
+	if fake.WeeksStub != nil {
+		return fake.WeeksStub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.weeksReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakePlanStore) WeeksCallCount() int {
+	fake.weeksMutex.RLock()
+	defer fake.weeksMutex.RUnlock()
+	return len(fake.weeksArgsForCall)
+}
+
+func (fake *FakePlanStore) WeeksCalls(stub func(context.Context, int, string, string) ([]models.Plan, error)) {
+	fake.weeksMutex.Lock()
+	defer fake.weeksMutex.Unlock()
+	fake.WeeksStub = stub
+}
+
+func (fake *FakePlanStore) WeeksArgsForCall(i int) (context.Context, int, string, string) {
+	fake.weeksMutex.RLock()
+	defer fake.weeksMutex.RUnlock()
+	argsForCall := fake.weeksArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakePlanStore) WeeksReturns(result1 []models.Plan, result2 error) {
+	fake.weeksMutex.Lock()
+	defer fake.weeksMutex.Unlock()
+	fake.WeeksStub = nil
+	fake.weeksReturns = struct {
+		result1 []models.Plan
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePlanStore) WeeksReturnsOnCall(i int, result1 []models.Plan, result2 error) {
+	fake.weeksMutex.Lock()
+	defer fake.weeksMutex.Unlock()
+	fake.WeeksStub = nil
+	if fake.weeksReturnsOnCall == nil {
+		fake.weeksReturnsOnCall = make(map[int]struct {
+			result1 []models.Plan
+			result2 error
+		})
+	}
+	fake.weeksReturnsOnCall[i] = struct {
+		result1 []models.Plan
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePlanStore) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.getMutex.RLock()
+	defer fake.getMutex.RUnlock()
+	fake.saveMutex.RLock()
+	defer fake.saveMutex.RUnlock()
+	fake.weeksMutex.RLock()
+	defer fake.weeksMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakePlanStore) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ handlers.PlanStore = new(FakePlanStore)
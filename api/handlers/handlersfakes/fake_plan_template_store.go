@@ -0,0 +1,741 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package handlersfakes
+
+import (
+	"context"
+	"sync"
+
+	"github.com/kieron-pivotal/menu-planner-app/handlers"
+	"github.com/kieron-pivotal/menu-planner-app/models"
+)
+
+type FakePlanTemplateStore struct {
+	DeleteRuleStub        func(context.Context, int, int) error
+	deleteRuleMutex       sync.RWMutex
+	deleteRuleArgsForCall []struct {
+		arg1 context.Context
+		arg2 int
+		arg3 int
+	}
+	deleteRuleReturns struct {
+		result1 error
+	}
+	deleteRuleReturnsOnCall map[int]struct {
+		result1 error
+	}
+	DeleteTemplateStub        func(context.Context, int, int) error
+	deleteTemplateMutex       sync.RWMutex
+	deleteTemplateArgsForCall []struct {
+		arg1 context.Context
+		arg2 int
+		arg3 int
+	}
+	deleteTemplateReturns struct {
+		result1 error
+	}
+	deleteTemplateReturnsOnCall map[int]struct {
+		result1 error
+	}
+	GetTemplateStub        func(context.Context, int, int) (models.PlanTemplate, error)
+	getTemplateMutex       sync.RWMutex
+	getTemplateArgsForCall []struct {
+		arg1 context.Context
+		arg2 int
+		arg3 int
+	}
+	getTemplateReturns struct {
+		result1 models.PlanTemplate
+		result2 error
+	}
+	getTemplateReturnsOnCall map[int]struct {
+		result1 models.PlanTemplate
+		result2 error
+	}
+	InsertTemplateStub        func(context.Context, models.PlanTemplate) (models.PlanTemplate, error)
+	insertTemplateMutex       sync.RWMutex
+	insertTemplateArgsForCall []struct {
+		arg1 context.Context
+		arg2 models.PlanTemplate
+	}
+	insertTemplateReturns struct {
+		result1 models.PlanTemplate
+		result2 error
+	}
+	insertTemplateReturnsOnCall map[int]struct {
+		result1 models.PlanTemplate
+		result2 error
+	}
+	IsDuplicateErrStub        func(error) bool
+	isDuplicateErrMutex       sync.RWMutex
+	isDuplicateErrArgsForCall []struct {
+		arg1 error
+	}
+	isDuplicateErrReturns struct {
+		result1 bool
+	}
+	isDuplicateErrReturnsOnCall map[int]struct {
+		result1 bool
+	}
+	IsNotFoundErrStub        func(error) bool
+	isNotFoundErrMutex       sync.RWMutex
+	isNotFoundErrArgsForCall []struct {
+		arg1 error
+	}
+	isNotFoundErrReturns struct {
+		result1 bool
+	}
+	isNotFoundErrReturnsOnCall map[int]struct {
+		result1 bool
+	}
+	ListRulesStub        func(context.Context, int) ([]models.PlanRule, error)
+	listRulesMutex       sync.RWMutex
+	listRulesArgsForCall []struct {
+		arg1 context.Context
+		arg2 int
+	}
+	listRulesReturns struct {
+		result1 []models.PlanRule
+		result2 error
+	}
+	listRulesReturnsOnCall map[int]struct {
+		result1 []models.PlanRule
+		result2 error
+	}
+	ListTemplatesStub        func(context.Context, int) ([]models.PlanTemplate, error)
+	listTemplatesMutex       sync.RWMutex
+	listTemplatesArgsForCall []struct {
+		arg1 context.Context
+		arg2 int
+	}
+	listTemplatesReturns struct {
+		result1 []models.PlanTemplate
+		result2 error
+	}
+	listTemplatesReturnsOnCall map[int]struct {
+		result1 []models.PlanTemplate
+		result2 error
+	}
+	SaveRuleStub        func(context.Context, models.PlanRule) (models.PlanRule, error)
+	saveRuleMutex       sync.RWMutex
+	saveRuleArgsForCall []struct {
+		arg1 context.Context
+		arg2 models.PlanRule
+	}
+	saveRuleReturns struct {
+		result1 models.PlanRule
+		result2 error
+	}
+	saveRuleReturnsOnCall map[int]struct {
+		result1 models.PlanRule
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakePlanTemplateStore) DeleteRule(arg1 context.Context, arg2 int, arg3 int) error {
+	fake.deleteRuleMutex.Lock()
+	ret, specificReturn := fake.deleteRuleReturnsOnCall[len(fake.deleteRuleArgsForCall)]
+	fake.deleteRuleArgsForCall = append(fake.deleteRuleArgsForCall, struct {
+		arg1 context.Context
+		arg2 int
+		arg3 int
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("DeleteRule", []interface{}{arg1, arg2, arg3})
+	fake.deleteRuleMutex.Unlock()
+	if fake.DeleteRuleStub != nil {
+		return fake.DeleteRuleStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.deleteRuleReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakePlanTemplateStore) DeleteRuleCallCount() int {
+	fake.deleteRuleMutex.RLock()
+	defer fake.deleteRuleMutex.RUnlock()
+	return len(fake.deleteRuleArgsForCall)
+}
+
+func (fake *FakePlanTemplateStore) DeleteRuleCalls(stub func(context.Context, int, int) error) {
+	fake.deleteRuleMutex.Lock()
+	defer fake.deleteRuleMutex.Unlock()
+	fake.DeleteRuleStub = stub
+}
+
+func (fake *FakePlanTemplateStore) DeleteRuleArgsForCall(i int) (context.Context, int, int) {
+	fake.deleteRuleMutex.RLock()
+	defer fake.deleteRuleMutex.RUnlock()
+	argsForCall := fake.deleteRuleArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakePlanTemplateStore) DeleteRuleReturns(result1 error) {
+	fake.deleteRuleMutex.Lock()
+	defer fake.deleteRuleMutex.Unlock()
+	fake.DeleteRuleStub = nil
+	fake.deleteRuleReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePlanTemplateStore) DeleteRuleReturnsOnCall(i int, result1 error) {
+	fake.deleteRuleMutex.Lock()
+	defer fake.deleteRuleMutex.Unlock()
+	fake.DeleteRuleStub = nil
+	if fake.deleteRuleReturnsOnCall == nil {
+		fake.deleteRuleReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteRuleReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePlanTemplateStore) DeleteTemplate(arg1 context.Context, arg2 int, arg3 int) error {
+	fake.deleteTemplateMutex.Lock()
+	ret, specificReturn := fake.deleteTemplateReturnsOnCall[len(fake.deleteTemplateArgsForCall)]
+	fake.deleteTemplateArgsForCall = append(fake.deleteTemplateArgsForCall, struct {
+		arg1 context.Context
+		arg2 int
+		arg3 int
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("DeleteTemplate", []interface{}{arg1, arg2, arg3})
+	fake.deleteTemplateMutex.Unlock()
+	if fake.DeleteTemplateStub != nil {
+		return fake.DeleteTemplateStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.deleteTemplateReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakePlanTemplateStore) DeleteTemplateCallCount() int {
+	fake.deleteTemplateMutex.RLock()
+	defer fake.deleteTemplateMutex.RUnlock()
+	return len(fake.deleteTemplateArgsForCall)
+}
+
+func (fake *FakePlanTemplateStore) DeleteTemplateCalls(stub func(context.Context, int, int) error) {
+	fake.deleteTemplateMutex.Lock()
+	defer fake.deleteTemplateMutex.Unlock()
+	fake.DeleteTemplateStub = stub
+}
+
+func (fake *FakePlanTemplateStore) DeleteTemplateArgsForCall(i int) (context.Context, int, int) {
+	fake.deleteTemplateMutex.RLock()
+	defer fake.deleteTemplateMutex.RUnlock()
+	argsForCall := fake.deleteTemplateArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakePlanTemplateStore) DeleteTemplateReturns(result1 error) {
+	fake.deleteTemplateMutex.Lock()
+	defer fake.deleteTemplateMutex.Unlock()
+	fake.DeleteTemplateStub = nil
+	fake.deleteTemplateReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePlanTemplateStore) DeleteTemplateReturnsOnCall(i int, result1 error) {
+	fake.deleteTemplateMutex.Lock()
+	defer fake.deleteTemplateMutex.Unlock()
+	fake.DeleteTemplateStub = nil
+	if fake.deleteTemplateReturnsOnCall == nil {
+		fake.deleteTemplateReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteTemplateReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePlanTemplateStore) GetTemplate(arg1 context.Context, arg2 int, arg3 int) (models.PlanTemplate, error) {
+	fake.getTemplateMutex.Lock()
+	ret, specificReturn := fake.getTemplateReturnsOnCall[len(fake.getTemplateArgsForCall)]
+	fake.getTemplateArgsForCall = append(fake.getTemplateArgsForCall, struct {
+		arg1 context.Context
+		arg2 int
+		arg3 int
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("GetTemplate", []interface{}{arg1, arg2, arg3})
+	fake.getTemplateMutex.Unlock()
+	if fake.GetTemplateStub != nil {
+		return fake.GetTemplateStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getTemplateReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakePlanTemplateStore) GetTemplateCallCount() int {
+	fake.getTemplateMutex.RLock()
+	defer fake.getTemplateMutex.RUnlock()
+	return len(fake.getTemplateArgsForCall)
+}
+
+func (fake *FakePlanTemplateStore) GetTemplateCalls(stub func(context.Context, int, int) (models.PlanTemplate, error)) {
+	fake.getTemplateMutex.Lock()
+	defer fake.getTemplateMutex.Unlock()
+	fake.GetTemplateStub = stub
+}
+
+func (fake *FakePlanTemplateStore) GetTemplateArgsForCall(i int) (context.Context, int, int) {
+	fake.getTemplateMutex.RLock()
+	defer fake.getTemplateMutex.RUnlock()
+	argsForCall := fake.getTemplateArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakePlanTemplateStore) GetTemplateReturns(result1 models.PlanTemplate, result2 error) {
+	fake.getTemplateMutex.Lock()
+	defer fake.getTemplateMutex.Unlock()
+	fake.GetTemplateStub = nil
+	fake.getTemplateReturns = struct {
+		result1 models.PlanTemplate
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePlanTemplateStore) GetTemplateReturnsOnCall(i int, result1 models.PlanTemplate, result2 error) {
+	fake.getTemplateMutex.Lock()
+	defer fake.getTemplateMutex.Unlock()
+	fake.GetTemplateStub = nil
+	if fake.getTemplateReturnsOnCall == nil {
+		fake.getTemplateReturnsOnCall = make(map[int]struct {
+			result1 models.PlanTemplate
+			result2 error
+		})
+	}
+	fake.getTemplateReturnsOnCall[i] = struct {
+		result1 models.PlanTemplate
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePlanTemplateStore) InsertTemplate(arg1 context.Context, arg2 models.PlanTemplate) (models.PlanTemplate, error) {
+	fake.insertTemplateMutex.Lock()
+	ret, specificReturn := fake.insertTemplateReturnsOnCall[len(fake.insertTemplateArgsForCall)]
+	fake.insertTemplateArgsForCall = append(fake.insertTemplateArgsForCall, struct {
+		arg1 context.Context
+		arg2 models.PlanTemplate
+	}{arg1, arg2})
+	fake.recordInvocation("InsertTemplate", []interface{}{arg1, arg2})
+	fake.insertTemplateMutex.Unlock()
+	if fake.InsertTemplateStub != nil {
+		return fake.InsertTemplateStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.insertTemplateReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakePlanTemplateStore) InsertTemplateCallCount() int {
+	fake.insertTemplateMutex.RLock()
+	defer fake.insertTemplateMutex.RUnlock()
+	return len(fake.insertTemplateArgsForCall)
+}
+
+func (fake *FakePlanTemplateStore) InsertTemplateCalls(stub func(context.Context, models.PlanTemplate) (models.PlanTemplate, error)) {
+	fake.insertTemplateMutex.Lock()
+	defer fake.insertTemplateMutex.Unlock()
+	fake.InsertTemplateStub = stub
+}
+
+func (fake *FakePlanTemplateStore) InsertTemplateArgsForCall(i int) (context.Context, models.PlanTemplate) {
+	fake.insertTemplateMutex.RLock()
+	defer fake.insertTemplateMutex.RUnlock()
+	argsForCall := fake.insertTemplateArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakePlanTemplateStore) InsertTemplateReturns(result1 models.PlanTemplate, result2 error) {
+	fake.insertTemplateMutex.Lock()
+	defer fake.insertTemplateMutex.Unlock()
+	fake.InsertTemplateStub = nil
+	fake.insertTemplateReturns = struct {
+		result1 models.PlanTemplate
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePlanTemplateStore) InsertTemplateReturnsOnCall(i int, result1 models.PlanTemplate, result2 error) {
+	fake.insertTemplateMutex.Lock()
+	defer fake.insertTemplateMutex.Unlock()
+	fake.InsertTemplateStub = nil
+	if fake.insertTemplateReturnsOnCall == nil {
+		fake.insertTemplateReturnsOnCall = make(map[int]struct {
+			result1 models.PlanTemplate
+			result2 error
+		})
+	}
+	fake.insertTemplateReturnsOnCall[i] = struct {
+		result1 models.PlanTemplate
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePlanTemplateStore) IsDuplicateErr(arg1 error) bool {
+	fake.isDuplicateErrMutex.Lock()
+	ret, specificReturn := fake.isDuplicateErrReturnsOnCall[len(fake.isDuplicateErrArgsForCall)]
+	fake.isDuplicateErrArgsForCall = append(fake.isDuplicateErrArgsForCall, struct {
+		arg1 error
+	}{arg1})
+	fake.recordInvocation("IsDuplicateErr", []interface{}{arg1})
+	fake.isDuplicateErrMutex.Unlock()
+	if fake.IsDuplicateErrStub != nil {
+		return fake.IsDuplicateErrStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.isDuplicateErrReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakePlanTemplateStore) IsDuplicateErrCallCount() int {
+	fake.isDuplicateErrMutex.RLock()
+	defer fake.isDuplicateErrMutex.RUnlock()
+	return len(fake.isDuplicateErrArgsForCall)
+}
+
+func (fake *FakePlanTemplateStore) IsDuplicateErrCalls(stub func(error) bool) {
+	fake.isDuplicateErrMutex.Lock()
+	defer fake.isDuplicateErrMutex.Unlock()
+	fake.IsDuplicateErrStub = stub
+}
+
+func (fake *FakePlanTemplateStore) IsDuplicateErrArgsForCall(i int) error {
+	fake.isDuplicateErrMutex.RLock()
+	defer fake.isDuplicateErrMutex.RUnlock()
+	argsForCall := fake.isDuplicateErrArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakePlanTemplateStore) IsDuplicateErrReturns(result1 bool) {
+	fake.isDuplicateErrMutex.Lock()
+	defer fake.isDuplicateErrMutex.Unlock()
+	fake.IsDuplicateErrStub = nil
+	fake.isDuplicateErrReturns = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *FakePlanTemplateStore) IsDuplicateErrReturnsOnCall(i int, result1 bool) {
+	fake.isDuplicateErrMutex.Lock()
+	defer fake.isDuplicateErrMutex.Unlock()
+	fake.IsDuplicateErrStub = nil
+	if fake.isDuplicateErrReturnsOnCall == nil {
+		fake.isDuplicateErrReturnsOnCall = make(map[int]struct {
+			result1 bool
+		})
+	}
+	fake.isDuplicateErrReturnsOnCall[i] = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *FakePlanTemplateStore) IsNotFoundErr(arg1 error) bool {
+	fake.isNotFoundErrMutex.Lock()
+	ret, specificReturn := fake.isNotFoundErrReturnsOnCall[len(fake.isNotFoundErrArgsForCall)]
+	fake.isNotFoundErrArgsForCall = append(fake.isNotFoundErrArgsForCall, struct {
+		arg1 error
+	}{arg1})
+	fake.recordInvocation("IsNotFoundErr", []interface{}{arg1})
+	fake.isNotFoundErrMutex.Unlock()
+	if fake.IsNotFoundErrStub != nil {
+		return fake.IsNotFoundErrStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.isNotFoundErrReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakePlanTemplateStore) IsNotFoundErrCallCount() int {
+	fake.isNotFoundErrMutex.RLock()
+	defer fake.isNotFoundErrMutex.RUnlock()
+	return len(fake.isNotFoundErrArgsForCall)
+}
+
+func (fake *FakePlanTemplateStore) IsNotFoundErrCalls(stub func(error) bool) {
+	fake.isNotFoundErrMutex.Lock()
+	defer fake.isNotFoundErrMutex.Unlock()
+	fake.IsNotFoundErrStub = stub
+}
+
+func (fake *FakePlanTemplateStore) IsNotFoundErrArgsForCall(i int) error {
+	fake.isNotFoundErrMutex.RLock()
+	defer fake.isNotFoundErrMutex.RUnlock()
+	argsForCall := fake.isNotFoundErrArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakePlanTemplateStore) IsNotFoundErrReturns(result1 bool) {
+	fake.isNotFoundErrMutex.Lock()
+	defer fake.isNotFoundErrMutex.Unlock()
+	fake.IsNotFoundErrStub = nil
+	fake.isNotFoundErrReturns = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *FakePlanTemplateStore) IsNotFoundErrReturnsOnCall(i int, result1 bool) {
+	fake.isNotFoundErrMutex.Lock()
+	defer fake.isNotFoundErrMutex.Unlock()
+	fake.IsNotFoundErrStub = nil
+	if fake.isNotFoundErrReturnsOnCall == nil {
+		fake.isNotFoundErrReturnsOnCall = make(map[int]struct {
+			result1 bool
+		})
+	}
+	fake.isNotFoundErrReturnsOnCall[i] = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *FakePlanTemplateStore) ListRules(arg1 context.Context, arg2 int) ([]models.PlanRule, error) {
+	fake.listRulesMutex.Lock()
+	ret, specificReturn := fake.listRulesReturnsOnCall[len(fake.listRulesArgsForCall)]
+	fake.listRulesArgsForCall = append(fake.listRulesArgsForCall, struct {
+		arg1 context.Context
+		arg2 int
+	}{arg1, arg2})
+	fake.recordInvocation("ListRules", []interface{}{arg1, arg2})
+	fake.listRulesMutex.Unlock()
+	if fake.ListRulesStub != nil {
+		return fake.ListRulesStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.listRulesReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakePlanTemplateStore) ListRulesCallCount() int {
+	fake.listRulesMutex.RLock()
+	defer fake.listRulesMutex.RUnlock()
+	return len(fake.listRulesArgsForCall)
+}
+
+func (fake *FakePlanTemplateStore) ListRulesCalls(stub func(context.Context, int) ([]models.PlanRule, error)) {
+	fake.listRulesMutex.Lock()
+	defer fake.listRulesMutex.Unlock()
+	fake.ListRulesStub = stub
+}
+
+func (fake *FakePlanTemplateStore) ListRulesArgsForCall(i int) (context.Context, int) {
+	fake.listRulesMutex.RLock()
+	defer fake.listRulesMutex.RUnlock()
+	argsForCall := fake.listRulesArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakePlanTemplateStore) ListRulesReturns(result1 []models.PlanRule, result2 error) {
+	fake.listRulesMutex.Lock()
+	defer fake.listRulesMutex.Unlock()
+	fake.ListRulesStub = nil
+	fake.listRulesReturns = struct {
+		result1 []models.PlanRule
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePlanTemplateStore) ListRulesReturnsOnCall(i int, result1 []models.PlanRule, result2 error) {
+	fake.listRulesMutex.Lock()
+	defer fake.listRulesMutex.Unlock()
+	fake.ListRulesStub = nil
+	if fake.listRulesReturnsOnCall == nil {
+		fake.listRulesReturnsOnCall = make(map[int]struct {
+			result1 []models.PlanRule
+			result2 error
+		})
+	}
+	fake.listRulesReturnsOnCall[i] = struct {
+		result1 []models.PlanRule
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePlanTemplateStore) ListTemplates(arg1 context.Context, arg2 int) ([]models.PlanTemplate, error) {
+	fake.listTemplatesMutex.Lock()
+	ret, specificReturn := fake.listTemplatesReturnsOnCall[len(fake.listTemplatesArgsForCall)]
+	fake.listTemplatesArgsForCall = append(fake.listTemplatesArgsForCall, struct {
+		arg1 context.Context
+		arg2 int
+	}{arg1, arg2})
+	fake.recordInvocation("ListTemplates", []interface{}{arg1, arg2})
+	fake.listTemplatesMutex.Unlock()
+	if fake.ListTemplatesStub != nil {
+		return fake.ListTemplatesStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.listTemplatesReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakePlanTemplateStore) ListTemplatesCallCount() int {
+	fake.listTemplatesMutex.RLock()
+	defer fake.listTemplatesMutex.RUnlock()
+	return len(fake.listTemplatesArgsForCall)
+}
+
+func (fake *FakePlanTemplateStore) ListTemplatesCalls(stub func(context.Context, int) ([]models.PlanTemplate, error)) {
+	fake.listTemplatesMutex.Lock()
+	defer fake.listTemplatesMutex.Unlock()
+	fake.ListTemplatesStub = stub
+}
+
+func (fake *FakePlanTemplateStore) ListTemplatesArgsForCall(i int) (context.Context, int) {
+	fake.listTemplatesMutex.RLock()
+	defer fake.listTemplatesMutex.RUnlock()
+	argsForCall := fake.listTemplatesArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakePlanTemplateStore) ListTemplatesReturns(result1 []models.PlanTemplate, result2 error) {
+	fake.listTemplatesMutex.Lock()
+	defer fake.listTemplatesMutex.Unlock()
+	fake.ListTemplatesStub = nil
+	fake.listTemplatesReturns = struct {
+		result1 []models.PlanTemplate
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePlanTemplateStore) ListTemplatesReturnsOnCall(i int, result1 []models.PlanTemplate, result2 error) {
+	fake.listTemplatesMutex.Lock()
+	defer fake.listTemplatesMutex.Unlock()
+	fake.ListTemplatesStub = nil
+	if fake.listTemplatesReturnsOnCall == nil {
+		fake.listTemplatesReturnsOnCall = make(map[int]struct {
+			result1 []models.PlanTemplate
+			result2 error
+		})
+	}
+	fake.listTemplatesReturnsOnCall[i] = struct {
+		result1 []models.PlanTemplate
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePlanTemplateStore) SaveRule(arg1 context.Context, arg2 models.PlanRule) (models.PlanRule, error) {
+	fake.saveRuleMutex.Lock()
+	ret, specificReturn := fake.saveRuleReturnsOnCall[len(fake.saveRuleArgsForCall)]
+	fake.saveRuleArgsForCall = append(fake.saveRuleArgsForCall, struct {
+		arg1 context.Context
+		arg2 models.PlanRule
+	}{arg1, arg2})
+	fake.recordInvocation("SaveRule", []interface{}{arg1, arg2})
+	fake.saveRuleMutex.Unlock()
+	if fake.SaveRuleStub != nil {
+		return fake.SaveRuleStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.saveRuleReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakePlanTemplateStore) SaveRuleCallCount() int {
+	fake.saveRuleMutex.RLock()
+	defer fake.saveRuleMutex.RUnlock()
+	return len(fake.saveRuleArgsForCall)
+}
+
+func (fake *FakePlanTemplateStore) SaveRuleCalls(stub func(context.Context, models.PlanRule) (models.PlanRule, error)) {
+	fake.saveRuleMutex.Lock()
+	defer fake.saveRuleMutex.Unlock()
+	fake.SaveRuleStub = stub
+}
+
+func (fake *FakePlanTemplateStore) SaveRuleArgsForCall(i int) (context.Context, models.PlanRule) {
+	fake.saveRuleMutex.RLock()
+	defer fake.saveRuleMutex.RUnlock()
+	argsForCall := fake.saveRuleArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakePlanTemplateStore) SaveRuleReturns(result1 models.PlanRule, result2 error) {
+	fake.saveRuleMutex.Lock()
+	defer fake.saveRuleMutex.Unlock()
+	fake.SaveRuleStub = nil
+	fake.saveRuleReturns = struct {
+		result1 models.PlanRule
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePlanTemplateStore) SaveRuleReturnsOnCall(i int, result1 models.PlanRule, result2 error) {
+	fake.saveRuleMutex.Lock()
+	defer fake.saveRuleMutex.Unlock()
+	fake.SaveRuleStub = nil
+	if fake.saveRuleReturnsOnCall == nil {
+		fake.saveRuleReturnsOnCall = make(map[int]struct {
+			result1 models.PlanRule
+			result2 error
+		})
+	}
+	fake.saveRuleReturnsOnCall[i] = struct {
+		result1 models.PlanRule
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePlanTemplateStore) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.deleteRuleMutex.RLock()
+	defer fake.deleteRuleMutex.RUnlock()
+	fake.deleteTemplateMutex.RLock()
+	defer fake.deleteTemplateMutex.RUnlock()
+	fake.getTemplateMutex.RLock()
+	defer fake.getTemplateMutex.RUnlock()
+	fake.insertTemplateMutex.RLock()
+	defer fake.insertTemplateMutex.RUnlock()
+	fake.isDuplicateErrMutex.RLock()
+	defer fake.isDuplicateErrMutex.RUnlock()
+	fake.isNotFoundErrMutex.RLock()
+	defer fake.isNotFoundErrMutex.RUnlock()
+	fake.listRulesMutex.RLock()
+	defer fake.listRulesMutex.RUnlock()
+	fake.listTemplatesMutex.RLock()
+	defer fake.listTemplatesMutex.RUnlock()
+	fake.saveRuleMutex.RLock()
+	defer fake.saveRuleMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakePlanTemplateStore) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ handlers.PlanTemplateStore = new(FakePlanTemplateStore)
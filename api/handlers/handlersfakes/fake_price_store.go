@@ -0,0 +1,349 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package handlersfakes
+
+import (
+	"context"
+	"sync"
+
+	"github.com/kieron-pivotal/menu-planner-app/handlers"
+	"github.com/kieron-pivotal/menu-planner-app/models"
+)
+
+type FakePriceStore struct {
+	DeleteStub        func(context.Context, int, int) error
+	deleteMutex       sync.RWMutex
+	deleteArgsForCall []struct {
+		arg1 context.Context
+		arg2 int
+		arg3 int
+	}
+	deleteReturns struct {
+		result1 error
+	}
+	deleteReturnsOnCall map[int]struct {
+		result1 error
+	}
+	IsNotFoundErrStub        func(error) bool
+	isNotFoundErrMutex       sync.RWMutex
+	isNotFoundErrArgsForCall []struct {
+		arg1 error
+	}
+	isNotFoundErrReturns struct {
+		result1 bool
+	}
+	isNotFoundErrReturnsOnCall map[int]struct {
+		result1 bool
+	}
+	ListStub        func(context.Context, int) ([]models.Price, error)
+	listMutex       sync.RWMutex
+	listArgsForCall []struct {
+		arg1 context.Context
+		arg2 int
+	}
+	listReturns struct {
+		result1 []models.Price
+		result2 error
+	}
+	listReturnsOnCall map[int]struct {
+		result1 []models.Price
+		result2 error
+	}
+	SaveStub        func(context.Context, models.Price) (models.Price, error)
+	saveMutex       sync.RWMutex
+	saveArgsForCall []struct {
+		arg1 context.Context
+		arg2 models.Price
+	}
+	saveReturns struct {
+		result1 models.Price
+		result2 error
+	}
+	saveReturnsOnCall map[int]struct {
+		result1 models.Price
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakePriceStore) Delete(arg1 context.Context, arg2 int, arg3 int) error {
+	fake.deleteMutex.Lock()
+	ret, specificReturn := fake.deleteReturnsOnCall[len(fake.deleteArgsForCall)]
+	fake.deleteArgsForCall = append(fake.deleteArgsForCall, struct {
+		arg1 context.Context
+		arg2 int
+		arg3 int
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("Delete", []interface{}{arg1, arg2, arg3})
+	fake.deleteMutex.Unlock()
+	if fake.DeleteStub != nil {
+		return fake.DeleteStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.deleteReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakePriceStore) DeleteCallCount() int {
+	fake.deleteMutex.RLock()
+	defer fake.deleteMutex.RUnlock()
+	return len(fake.deleteArgsForCall)
+}
+
+func (fake *FakePriceStore) DeleteCalls(stub func(context.Context, int, int) error) {
+	fake.deleteMutex.Lock()
+	defer fake.deleteMutex.Unlock()
+	fake.DeleteStub = stub
+}
+
+func (fake *FakePriceStore) DeleteArgsForCall(i int) (context.Context, int, int) {
+	fake.deleteMutex.RLock()
+	defer fake.deleteMutex.RUnlock()
+	argsForCall := fake.deleteArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakePriceStore) DeleteReturns(result1 error) {
+	fake.deleteMutex.Lock()
+	defer fake.deleteMutex.Unlock()
+	fake.DeleteStub = nil
+	fake.deleteReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePriceStore) DeleteReturnsOnCall(i int, result1 error) {
+	fake.deleteMutex.Lock()
+	defer fake.deleteMutex.Unlock()
+	fake.DeleteStub = nil
+	if fake.deleteReturnsOnCall == nil {
+		fake.deleteReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePriceStore) IsNotFoundErr(arg1 error) bool {
+	fake.isNotFoundErrMutex.Lock()
+	ret, specificReturn := fake.isNotFoundErrReturnsOnCall[len(fake.isNotFoundErrArgsForCall)]
+	fake.isNotFoundErrArgsForCall = append(fake.isNotFoundErrArgsForCall, struct {
+		arg1 error
+	}{arg1})
+	fake.recordInvocation("IsNotFoundErr", []interface{}{arg1})
+	fake.isNotFoundErrMutex.Unlock()
+	if fake.IsNotFoundErrStub != nil {
+		return fake.IsNotFoundErrStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.isNotFoundErrReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakePriceStore) IsNotFoundErrCallCount() int {
+	fake.isNotFoundErrMutex.RLock()
+	defer fake.isNotFoundErrMutex.RUnlock()
+	return len(fake.isNotFoundErrArgsForCall)
+}
+
+func (fake *FakePriceStore) IsNotFoundErrCalls(stub func(error) bool) {
+	fake.isNotFoundErrMutex.Lock()
+	defer fake.isNotFoundErrMutex.Unlock()
+	fake.IsNotFoundErrStub = stub
+}
+
+func (fake *FakePriceStore) IsNotFoundErrArgsForCall(i int) error {
+	fake.isNotFoundErrMutex.RLock()
+	defer fake.isNotFoundErrMutex.RUnlock()
+	argsForCall := fake.isNotFoundErrArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakePriceStore) IsNotFoundErrReturns(result1 bool) {
+	fake.isNotFoundErrMutex.Lock()
+	defer fake.isNotFoundErrMutex.Unlock()
+	fake.IsNotFoundErrStub = nil
+	fake.isNotFoundErrReturns = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *FakePriceStore) IsNotFoundErrReturnsOnCall(i int, result1 bool) {
+	fake.isNotFoundErrMutex.Lock()
+	defer fake.isNotFoundErrMutex.Unlock()
+	fake.IsNotFoundErrStub = nil
+	if fake.isNotFoundErrReturnsOnCall == nil {
+		fake.isNotFoundErrReturnsOnCall = make(map[int]struct {
+			result1 bool
+		})
+	}
+	fake.isNotFoundErrReturnsOnCall[i] = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *FakePriceStore) List(arg1 context.Context, arg2 int) ([]models.Price, error) {
+	fake.listMutex.Lock()
+	ret, specificReturn := fake.listReturnsOnCall[len(fake.listArgsForCall)]
+	fake.listArgsForCall = append(fake.listArgsForCall, struct {
+		arg1 context.Context
+		arg2 int
+	}{arg1, arg2})
+	fake.recordInvocation("List", []interface{}{arg1, arg2})
+	fake.listMutex.Unlock()
+	if fake.ListStub != nil {
+		return fake.ListStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.listReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakePriceStore) ListCallCount() int {
+	fake.listMutex.RLock()
+	defer fake.listMutex.RUnlock()
+	return len(fake.listArgsForCall)
+}
+
+func (fake *FakePriceStore) ListCalls(stub func(context.Context, int) ([]models.Price, error)) {
+	fake.listMutex.Lock()
+	defer fake.listMutex.Unlock()
+	fake.ListStub = stub
+}
+
+func (fake *FakePriceStore) ListArgsForCall(i int) (context.Context, int) {
+	fake.listMutex.RLock()
+	defer fake.listMutex.RUnlock()
+	argsForCall := fake.listArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakePriceStore) ListReturns(result1 []models.Price, result2 error) {
+	fake.listMutex.Lock()
+	defer fake.listMutex.Unlock()
+	fake.ListStub = nil
+	fake.listReturns = struct {
+		result1 []models.Price
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePriceStore) ListReturnsOnCall(i int, result1 []models.Price, result2 error) {
+	fake.listMutex.Lock()
+	defer fake.listMutex.Unlock()
+	fake.ListStub = nil
+	if fake.listReturnsOnCall == nil {
+		fake.listReturnsOnCall = make(map[int]struct {
+			result1 []models.Price
+			result2 error
+		})
+	}
+	fake.listReturnsOnCall[i] = struct {
+		result1 []models.Price
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePriceStore) Save(arg1 context.Context, arg2 models.Price) (models.Price, error) {
+	fake.saveMutex.Lock()
+	ret, specificReturn := fake.saveReturnsOnCall[len(fake.saveArgsForCall)]
+	fake.saveArgsForCall = append(fake.saveArgsForCall, struct {
+		arg1 context.Context
+		arg2 models.Price
+	}{arg1, arg2})
+	fake.recordInvocation("Save", []interface{}{arg1, arg2})
+	fake.saveMutex.Unlock()
+	if fake.SaveStub != nil {
+		return fake.SaveStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.saveReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakePriceStore) SaveCallCount() int {
+	fake.saveMutex.RLock()
+	defer fake.saveMutex.RUnlock()
+	return len(fake.saveArgsForCall)
+}
+
+func (fake *FakePriceStore) SaveCalls(stub func(context.Context, models.Price) (models.Price, error)) {
+	fake.saveMutex.Lock()
+	defer fake.saveMutex.Unlock()
+	fake.SaveStub = stub
+}
+
+func (fake *FakePriceStore) SaveArgsForCall(i int) (context.Context, models.Price) {
+	fake.saveMutex.RLock()
+	defer fake.saveMutex.RUnlock()
+	argsForCall := fake.saveArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakePriceStore) SaveReturns(result1 models.Price, result2 error) {
+	fake.saveMutex.Lock()
+	defer fake.saveMutex.Unlock()
+	fake.SaveStub = nil
+	fake.saveReturns = struct {
+		result1 models.Price
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePriceStore) SaveReturnsOnCall(i int, result1 models.Price, result2 error) {
+	fake.saveMutex.Lock()
+	defer fake.saveMutex.Unlock()
+	fake.SaveStub = nil
+	if fake.saveReturnsOnCall == nil {
+		fake.saveReturnsOnCall = make(map[int]struct {
+			result1 models.Price
+			result2 error
+		})
+	}
+	fake.saveReturnsOnCall[i] = struct {
+		result1 models.Price
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePriceStore) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.deleteMutex.RLock()
+	defer fake.deleteMutex.RUnlock()
+	fake.isNotFoundErrMutex.RLock()
+	defer fake.isNotFoundErrMutex.RUnlock()
+	fake.listMutex.RLock()
+	defer fake.listMutex.RUnlock()
+	fake.saveMutex.RLock()
+	defer fake.saveMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakePriceStore) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ handlers.PriceStore = new(FakePriceStore)
@@ -0,0 +1,119 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package handlersfakes
+
+import (
+	"context"
+	"sync"
+
+	"github.com/kieron-pivotal/menu-planner-app/handlers"
+	"github.com/kieron-pivotal/menu-planner-app/models"
+)
+
+type FakeRatingStore struct {
+	RateStub        func(context.Context, models.Rating) (models.Rating, error)
+	rateMutex       sync.RWMutex
+	rateArgsForCall []struct {
+		arg1 context.Context
+		arg2 models.Rating
+	}
+	rateReturns struct {
+		result1 models.Rating
+		result2 error
+	}
+	rateReturnsOnCall map[int]struct {
+		result1 models.Rating
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeRatingStore) Rate(arg1 context.Context, arg2 models.Rating) (models.Rating, error) {
+	fake.rateMutex.Lock()
+	ret, specificReturn := fake.rateReturnsOnCall[len(fake.rateArgsForCall)]
+	fake.rateArgsForCall = append(fake.rateArgsForCall, struct {
+		arg1 context.Context
+		arg2 models.Rating
+	}{arg1, arg2})
+	fake.recordInvocation("Rate", []interface{}{arg1, arg2})
+	fake.rateMutex.Unlock()
+	if fake.RateStub != nil {
+		return fake.RateStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.rateReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeRatingStore) RateCallCount() int {
+	fake.rateMutex.RLock()
+	defer fake.rateMutex.RUnlock()
+	return len(fake.rateArgsForCall)
+}
+
+func (fake *FakeRatingStore) RateCalls(stub func(context.Context, models.Rating) (models.Rating, error)) {
+	fake.rateMutex.Lock()
+	defer fake.rateMutex.Unlock()
+	fake.RateStub = stub
+}
+
+func (fake *FakeRatingStore) RateArgsForCall(i int) (context.Context, models.Rating) {
+	fake.rateMutex.RLock()
+	defer fake.rateMutex.RUnlock()
+	argsForCall := fake.rateArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeRatingStore) RateReturns(result1 models.Rating, result2 error) {
+	fake.rateMutex.Lock()
+	defer fake.rateMutex.Unlock()
+	fake.RateStub = nil
+	fake.rateReturns = struct {
+		result1 models.Rating
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRatingStore) RateReturnsOnCall(i int, result1 models.Rating, result2 error) {
+	fake.rateMutex.Lock()
+	defer fake.rateMutex.Unlock()
+	fake.RateStub = nil
+	if fake.rateReturnsOnCall == nil {
+		fake.rateReturnsOnCall = make(map[int]struct {
+			result1 models.Rating
+			result2 error
+		})
+	}
+	fake.rateReturnsOnCall[i] = struct {
+		result1 models.Rating
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRatingStore) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.rateMutex.RLock()
+	defer fake.rateMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeRatingStore) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ handlers.RatingStore = new(FakeRatingStore)
@@ -2,6 +2,7 @@
 package handlersfakes
 
 import (
+	"context"
 	"sync"
 
 	"github.com/kieron-pivotal/menu-planner-app/handlers"
@@ -9,10 +10,41 @@ import (
 )
 
 type FakeRecipeStore struct {
-	InsertStub        func(models.Recipe) (models.Recipe, error)
+	GetStub        func(context.Context, int, int) (models.Recipe, error)
+	getMutex       sync.RWMutex
+	getArgsForCall []struct {
+		arg1 context.Context
+		arg2 int
+		arg3 int
+	}
+	getReturns struct {
+		result1 models.Recipe
+		result2 error
+	}
+	getReturnsOnCall map[int]struct {
+		result1 models.Recipe
+		result2 error
+	}
+	GetManyStub        func(context.Context, int, []int) ([]models.Recipe, error)
+	getManyMutex       sync.RWMutex
+	getManyArgsForCall []struct {
+		arg1 context.Context
+		arg2 int
+		arg3 []int
+	}
+	getManyReturns struct {
+		result1 []models.Recipe
+		result2 error
+	}
+	getManyReturnsOnCall map[int]struct {
+		result1 []models.Recipe
+		result2 error
+	}
+	InsertStub        func(context.Context, models.Recipe) (models.Recipe, error)
 	insertMutex       sync.RWMutex
 	insertArgsForCall []struct {
-		arg1 models.Recipe
+		arg1 context.Context
+		arg2 models.Recipe
 	}
 	insertReturns struct {
 		result1 models.Recipe
@@ -22,10 +54,33 @@ type FakeRecipeStore struct {
 		result1 models.Recipe
 		result2 error
 	}
-	ListStub        func(int) ([]models.Recipe, error)
+	IsDuplicateErrStub        func(error) bool
+	isDuplicateErrMutex       sync.RWMutex
+	isDuplicateErrArgsForCall []struct {
+		arg1 error
+	}
+	isDuplicateErrReturns struct {
+		result1 bool
+	}
+	isDuplicateErrReturnsOnCall map[int]struct {
+		result1 bool
+	}
+	IsNotFoundErrStub        func(error) bool
+	isNotFoundErrMutex       sync.RWMutex
+	isNotFoundErrArgsForCall []struct {
+		arg1 error
+	}
+	isNotFoundErrReturns struct {
+		result1 bool
+	}
+	isNotFoundErrReturnsOnCall map[int]struct {
+		result1 bool
+	}
+	ListStub        func(context.Context, int) ([]models.Recipe, error)
 	listMutex       sync.RWMutex
 	listArgsForCall []struct {
-		arg1 int
+		arg1 context.Context
+		arg2 int
 	}
 	listReturns struct {
 		result1 []models.Recipe
@@ -35,20 +90,169 @@ type FakeRecipeStore struct {
 		result1 []models.Recipe
 		result2 error
 	}
+	MergeStub        func(context.Context, int, int) error
+	mergeMutex       sync.RWMutex
+	mergeArgsForCall []struct {
+		arg1 context.Context
+		arg2 int
+		arg3 int
+	}
+	mergeReturns struct {
+		result1 error
+	}
+	mergeReturnsOnCall map[int]struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeRecipeStore) Insert(arg1 models.Recipe) (models.Recipe, error) {
+func (fake *FakeRecipeStore) Get(arg1 context.Context, arg2 int, arg3 int) (models.Recipe, error) {
+	fake.getMutex.Lock()
+	ret, specificReturn := fake.getReturnsOnCall[len(fake.getArgsForCall)]
+	fake.getArgsForCall = append(fake.getArgsForCall, struct {
+		arg1 context.Context
+		arg2 int
+		arg3 int
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("Get", []interface{}{arg1, arg2, arg3})
+	fake.getMutex.Unlock()
+	if fake.GetStub != nil {
+		return fake.GetStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeRecipeStore) GetCallCount() int {
+	fake.getMutex.RLock()
+	defer fake.getMutex.RUnlock()
+	return len(fake.getArgsForCall)
+}
+
+func (fake *FakeRecipeStore) GetCalls(stub func(context.Context, int, int) (models.Recipe, error)) {
+	fake.getMutex.Lock()
+	defer fake.getMutex.Unlock()
+	fake.GetStub = stub
+}
+
+func (fake *FakeRecipeStore) GetArgsForCall(i int) (context.Context, int, int) {
+	fake.getMutex.RLock()
+	defer fake.getMutex.RUnlock()
+	argsForCall := fake.getArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeRecipeStore) GetReturns(result1 models.Recipe, result2 error) {
+	fake.getMutex.Lock()
+	defer fake.getMutex.Unlock()
+	fake.GetStub = nil
+	fake.getReturns = struct {
+		result1 models.Recipe
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRecipeStore) GetReturnsOnCall(i int, result1 models.Recipe, result2 error) {
+	fake.getMutex.Lock()
+	defer fake.getMutex.Unlock()
+	fake.GetStub = nil
+	if fake.getReturnsOnCall == nil {
+		fake.getReturnsOnCall = make(map[int]struct {
+			result1 models.Recipe
+			result2 error
+		})
+	}
+	fake.getReturnsOnCall[i] = struct {
+		result1 models.Recipe
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRecipeStore) GetMany(arg1 context.Context, arg2 int, arg3 []int) ([]models.Recipe, error) {
+	var arg3Copy []int
+	if arg3 != nil {
+		arg3Copy = make([]int, len(arg3))
+		copy(arg3Copy, arg3)
+	}
+	fake.getManyMutex.Lock()
+	ret, specificReturn := fake.getManyReturnsOnCall[len(fake.getManyArgsForCall)]
+	fake.getManyArgsForCall = append(fake.getManyArgsForCall, struct {
+		arg1 context.Context
+		arg2 int
+		arg3 []int
+	}{arg1, arg2, arg3Copy})
+	fake.recordInvocation("GetMany", []interface{}{arg1, arg2, arg3Copy})
+	fake.getManyMutex.Unlock()
+	if fake.GetManyStub != nil {
+		return fake.GetManyStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getManyReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeRecipeStore) GetManyCallCount() int {
+	fake.getManyMutex.RLock()
+	defer fake.getManyMutex.RUnlock()
+	return len(fake.getManyArgsForCall)
+}
+
+func (fake *FakeRecipeStore) GetManyCalls(stub func(context.Context, int, []int) ([]models.Recipe, error)) {
+	fake.getManyMutex.Lock()
+	defer fake.getManyMutex.Unlock()
+	fake.GetManyStub = stub
+}
+
+func (fake *FakeRecipeStore) GetManyArgsForCall(i int) (context.Context, int, []int) {
+	fake.getManyMutex.RLock()
+	defer fake.getManyMutex.RUnlock()
+	argsForCall := fake.getManyArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeRecipeStore) GetManyReturns(result1 []models.Recipe, result2 error) {
+	fake.getManyMutex.Lock()
+	defer fake.getManyMutex.Unlock()
+	fake.GetManyStub = nil
+	fake.getManyReturns = struct {
+		result1 []models.Recipe
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRecipeStore) GetManyReturnsOnCall(i int, result1 []models.Recipe, result2 error) {
+	fake.getManyMutex.Lock()
+	defer fake.getManyMutex.Unlock()
+	fake.GetManyStub = nil
+	if fake.getManyReturnsOnCall == nil {
+		fake.getManyReturnsOnCall = make(map[int]struct {
+			result1 []models.Recipe
+			result2 error
+		})
+	}
+	fake.getManyReturnsOnCall[i] = struct {
+		result1 []models.Recipe
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRecipeStore) Insert(arg1 context.Context, arg2 models.Recipe) (models.Recipe, error) {
 	fake.insertMutex.Lock()
 	ret, specificReturn := fake.insertReturnsOnCall[len(fake.insertArgsForCall)]
 	fake.insertArgsForCall = append(fake.insertArgsForCall, struct {
-		arg1 models.Recipe
-	}{arg1})
-	fake.recordInvocation("Insert", []interface{}{arg1})
+		arg1 context.Context
+		arg2 models.Recipe
+	}{arg1, arg2})
+	fake.recordInvocation("Insert", []interface{}{arg1, arg2})
 	fake.insertMutex.Unlock()
 	if fake.InsertStub != nil {
-		return fake.InsertStub(arg1)
+		return fake.InsertStub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -63,17 +267,17 @@ func (fake *FakeRecipeStore) InsertCallCount() int {
 	return len(fake.insertArgsForCall)
 }
 
-func (fake *FakeRecipeStore) InsertCalls(stub func(models.Recipe) (models.Recipe, error)) {
+func (fake *FakeRecipeStore) InsertCalls(stub func(context.Context, models.Recipe) (models.Recipe, error)) {
 	fake.insertMutex.Lock()
 	defer fake.insertMutex.Unlock()
 	fake.InsertStub = stub
 }
 
-func (fake *FakeRecipeStore) InsertArgsForCall(i int) models.Recipe {
+func (fake *FakeRecipeStore) InsertArgsForCall(i int) (context.Context, models.Recipe) {
 	fake.insertMutex.RLock()
 	defer fake.insertMutex.RUnlock()
 	argsForCall := fake.insertArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakeRecipeStore) InsertReturns(result1 models.Recipe, result2 error) {
@@ -102,16 +306,137 @@ func (fake *FakeRecipeStore) InsertReturnsOnCall(i int, result1 models.Recipe, r
 	}{result1, result2}
 }
 
-func (fake *FakeRecipeStore) List(arg1 int) ([]models.Recipe, error) {
+func (fake *FakeRecipeStore) IsDuplicateErr(arg1 error) bool {
+	fake.isDuplicateErrMutex.Lock()
+	ret, specificReturn := fake.isDuplicateErrReturnsOnCall[len(fake.isDuplicateErrArgsForCall)]
+	fake.isDuplicateErrArgsForCall = append(fake.isDuplicateErrArgsForCall, struct {
+		arg1 error
+	}{arg1})
+	fake.recordInvocation("IsDuplicateErr", []interface{}{arg1})
+	fake.isDuplicateErrMutex.Unlock()
+	if fake.IsDuplicateErrStub != nil {
+		return fake.IsDuplicateErrStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.isDuplicateErrReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeRecipeStore) IsDuplicateErrCallCount() int {
+	fake.isDuplicateErrMutex.RLock()
+	defer fake.isDuplicateErrMutex.RUnlock()
+	return len(fake.isDuplicateErrArgsForCall)
+}
+
+func (fake *FakeRecipeStore) IsDuplicateErrCalls(stub func(error) bool) {
+	fake.isDuplicateErrMutex.Lock()
+	defer fake.isDuplicateErrMutex.Unlock()
+	fake.IsDuplicateErrStub = stub
+}
+
+func (fake *FakeRecipeStore) IsDuplicateErrArgsForCall(i int) error {
+	fake.isDuplicateErrMutex.RLock()
+	defer fake.isDuplicateErrMutex.RUnlock()
+	argsForCall := fake.isDuplicateErrArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeRecipeStore) IsDuplicateErrReturns(result1 bool) {
+	fake.isDuplicateErrMutex.Lock()
+	defer fake.isDuplicateErrMutex.Unlock()
+	fake.IsDuplicateErrStub = nil
+	fake.isDuplicateErrReturns = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *FakeRecipeStore) IsDuplicateErrReturnsOnCall(i int, result1 bool) {
+	fake.isDuplicateErrMutex.Lock()
+	defer fake.isDuplicateErrMutex.Unlock()
+	fake.IsDuplicateErrStub = nil
+	if fake.isDuplicateErrReturnsOnCall == nil {
+		fake.isDuplicateErrReturnsOnCall = make(map[int]struct {
+			result1 bool
+		})
+	}
+	fake.isDuplicateErrReturnsOnCall[i] = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *FakeRecipeStore) IsNotFoundErr(arg1 error) bool {
+	fake.isNotFoundErrMutex.Lock()
+	ret, specificReturn := fake.isNotFoundErrReturnsOnCall[len(fake.isNotFoundErrArgsForCall)]
+	fake.isNotFoundErrArgsForCall = append(fake.isNotFoundErrArgsForCall, struct {
+		arg1 error
+	}{arg1})
+	fake.recordInvocation("IsNotFoundErr", []interface{}{arg1})
+	fake.isNotFoundErrMutex.Unlock()
+	if fake.IsNotFoundErrStub != nil {
+		return fake.IsNotFoundErrStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.isNotFoundErrReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeRecipeStore) IsNotFoundErrCallCount() int {
+	fake.isNotFoundErrMutex.RLock()
+	defer fake.isNotFoundErrMutex.RUnlock()
+	return len(fake.isNotFoundErrArgsForCall)
+}
+
+func (fake *FakeRecipeStore) IsNotFoundErrCalls(stub func(error) bool) {
+	fake.isNotFoundErrMutex.Lock()
+	defer fake.isNotFoundErrMutex.Unlock()
+	fake.IsNotFoundErrStub = stub
+}
+
+func (fake *FakeRecipeStore) IsNotFoundErrArgsForCall(i int) error {
+	fake.isNotFoundErrMutex.RLock()
+	defer fake.isNotFoundErrMutex.RUnlock()
+	argsForCall := fake.isNotFoundErrArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeRecipeStore) IsNotFoundErrReturns(result1 bool) {
+	fake.isNotFoundErrMutex.Lock()
+	defer fake.isNotFoundErrMutex.Unlock()
+	fake.IsNotFoundErrStub = nil
+	fake.isNotFoundErrReturns = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *FakeRecipeStore) IsNotFoundErrReturnsOnCall(i int, result1 bool) {
+	fake.isNotFoundErrMutex.Lock()
+	defer fake.isNotFoundErrMutex.Unlock()
+	fake.IsNotFoundErrStub = nil
+	if fake.isNotFoundErrReturnsOnCall == nil {
+		fake.isNotFoundErrReturnsOnCall = make(map[int]struct {
+			result1 bool
+		})
+	}
+	fake.isNotFoundErrReturnsOnCall[i] = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *FakeRecipeStore) List(arg1 context.Context, arg2 int) ([]models.Recipe, error) {
 	fake.listMutex.Lock()
 	ret, specificReturn := fake.listReturnsOnCall[len(fake.listArgsForCall)]
 	fake.listArgsForCall = append(fake.listArgsForCall, struct {
-		arg1 int
-	}{arg1})
-	fake.recordInvocation("List", []interface{}{arg1})
+		arg1 context.Context
+		arg2 int
+	}{arg1, arg2})
+	fake.recordInvocation("List", []interface{}{arg1, arg2})
 	fake.listMutex.Unlock()
 	if fake.ListStub != nil {
-		return fake.ListStub(arg1)
+		return fake.ListStub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -126,17 +451,17 @@ func (fake *FakeRecipeStore) ListCallCount() int {
 	return len(fake.listArgsForCall)
 }
 
-func (fake *FakeRecipeStore) ListCalls(stub func(int) ([]models.Recipe, error)) {
+func (fake *FakeRecipeStore) ListCalls(stub func(context.Context, int) ([]models.Recipe, error)) {
 	fake.listMutex.Lock()
 	defer fake.listMutex.Unlock()
 	fake.ListStub = stub
 }
 
-func (fake *FakeRecipeStore) ListArgsForCall(i int) int {
+func (fake *FakeRecipeStore) ListArgsForCall(i int) (context.Context, int) {
 	fake.listMutex.RLock()
 	defer fake.listMutex.RUnlock()
 	argsForCall := fake.listArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakeRecipeStore) ListReturns(result1 []models.Recipe, result2 error) {
@@ -165,13 +490,85 @@ func (fake *FakeRecipeStore) ListReturnsOnCall(i int, result1 []models.Recipe, r
 	}{result1, result2}
 }
 
+func (fake *FakeRecipeStore) Merge(arg1 context.Context, arg2 int, arg3 int) error {
+	fake.mergeMutex.Lock()
+	ret, specificReturn := fake.mergeReturnsOnCall[len(fake.mergeArgsForCall)]
+	fake.mergeArgsForCall = append(fake.mergeArgsForCall, struct {
+		arg1 context.Context
+		arg2 int
+		arg3 int
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("Merge", []interface{}{arg1, arg2, arg3})
+	fake.mergeMutex.Unlock()
+	if fake.MergeStub != nil {
+		return fake.MergeStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.mergeReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeRecipeStore) MergeCallCount() int {
+	fake.mergeMutex.RLock()
+	defer fake.mergeMutex.RUnlock()
+	return len(fake.mergeArgsForCall)
+}
+
+func (fake *FakeRecipeStore) MergeCalls(stub func(context.Context, int, int) error) {
+	fake.mergeMutex.Lock()
+	defer fake.mergeMutex.Unlock()
+	fake.MergeStub = stub
+}
+
+func (fake *FakeRecipeStore) MergeArgsForCall(i int) (context.Context, int, int) {
+	fake.mergeMutex.RLock()
+	defer fake.mergeMutex.RUnlock()
+	argsForCall := fake.mergeArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeRecipeStore) MergeReturns(result1 error) {
+	fake.mergeMutex.Lock()
+	defer fake.mergeMutex.Unlock()
+	fake.MergeStub = nil
+	fake.mergeReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRecipeStore) MergeReturnsOnCall(i int, result1 error) {
+	fake.mergeMutex.Lock()
+	defer fake.mergeMutex.Unlock()
+	fake.MergeStub = nil
+	if fake.mergeReturnsOnCall == nil {
+		fake.mergeReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.mergeReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeRecipeStore) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.getMutex.RLock()
+	defer fake.getMutex.RUnlock()
+	fake.getManyMutex.RLock()
+	defer fake.getManyMutex.RUnlock()
 	fake.insertMutex.RLock()
 	defer fake.insertMutex.RUnlock()
+	fake.isDuplicateErrMutex.RLock()
+	defer fake.isDuplicateErrMutex.RUnlock()
+	fake.isNotFoundErrMutex.RLock()
+	defer fake.isNotFoundErrMutex.RUnlock()
 	fake.listMutex.RLock()
 	defer fake.listMutex.RUnlock()
+	fake.mergeMutex.RLock()
+	defer fake.mergeMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
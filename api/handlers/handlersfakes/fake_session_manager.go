@@ -24,6 +24,20 @@ type FakeSessionManager struct {
 		result1 *session.AuthInfo
 		result2 error
 	}
+	IsActiveStub        func(context.Context, *session.AuthInfo) (bool, error)
+	isActiveMutex       sync.RWMutex
+	isActiveArgsForCall []struct {
+		arg1 context.Context
+		arg2 *session.AuthInfo
+	}
+	isActiveReturns struct {
+		result1 bool
+		result2 error
+	}
+	isActiveReturnsOnCall map[int]struct {
+		result1 bool
+		result2 error
+	}
 	SetStub        func(*http.Request, http.ResponseWriter, *session.AuthInfo) error
 	setMutex       sync.RWMutex
 	setArgsForCall []struct {
@@ -104,6 +118,70 @@ func (fake *FakeSessionManager) GetReturnsOnCall(i int, result1 *session.AuthInf
 	}{result1, result2}
 }
 
+func (fake *FakeSessionManager) IsActive(arg1 context.Context, arg2 *session.AuthInfo) (bool, error) {
+	fake.isActiveMutex.Lock()
+	ret, specificReturn := fake.isActiveReturnsOnCall[len(fake.isActiveArgsForCall)]
+	fake.isActiveArgsForCall = append(fake.isActiveArgsForCall, struct {
+		arg1 context.Context
+		arg2 *session.AuthInfo
+	}{arg1, arg2})
+	fake.recordInvocation("IsActive", []interface{}{arg1, arg2})
+	fake.isActiveMutex.Unlock()
+	if fake.IsActiveStub != nil {
+		return fake.IsActiveStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.isActiveReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeSessionManager) IsActiveCallCount() int {
+	fake.isActiveMutex.RLock()
+	defer fake.isActiveMutex.RUnlock()
+	return len(fake.isActiveArgsForCall)
+}
+
+func (fake *FakeSessionManager) IsActiveCalls(stub func(context.Context, *session.AuthInfo) (bool, error)) {
+	fake.isActiveMutex.Lock()
+	defer fake.isActiveMutex.Unlock()
+	fake.IsActiveStub = stub
+}
+
+func (fake *FakeSessionManager) IsActiveArgsForCall(i int) (context.Context, *session.AuthInfo) {
+	fake.isActiveMutex.RLock()
+	defer fake.isActiveMutex.RUnlock()
+	argsForCall := fake.isActiveArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeSessionManager) IsActiveReturns(result1 bool, result2 error) {
+	fake.isActiveMutex.Lock()
+	defer fake.isActiveMutex.Unlock()
+	fake.IsActiveStub = nil
+	fake.isActiveReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeSessionManager) IsActiveReturnsOnCall(i int, result1 bool, result2 error) {
+	fake.isActiveMutex.Lock()
+	defer fake.isActiveMutex.Unlock()
+	fake.IsActiveStub = nil
+	if fake.isActiveReturnsOnCall == nil {
+		fake.isActiveReturnsOnCall = make(map[int]struct {
+			result1 bool
+			result2 error
+		})
+	}
+	fake.isActiveReturnsOnCall[i] = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeSessionManager) Set(arg1 *http.Request, arg2 http.ResponseWriter, arg3 *session.AuthInfo) error {
 	fake.setMutex.Lock()
 	ret, specificReturn := fake.setReturnsOnCall[len(fake.setArgsForCall)]
@@ -171,6 +249,8 @@ func (fake *FakeSessionManager) Invocations() map[string][][]interface{} {
 	defer fake.invocationsMutex.RUnlock()
 	fake.getMutex.RLock()
 	defer fake.getMutex.RUnlock()
+	fake.isActiveMutex.RLock()
+	defer fake.isActiveMutex.RUnlock()
 	fake.setMutex.RLock()
 	defer fake.setMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
@@ -0,0 +1,344 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package handlersfakes
+
+import (
+	"context"
+	"sync"
+
+	"github.com/kieron-pivotal/menu-planner-app/handlers"
+	"github.com/kieron-pivotal/menu-planner-app/models"
+)
+
+type FakeSessionRevoker struct {
+	IsNotFoundErrStub        func(error) bool
+	isNotFoundErrMutex       sync.RWMutex
+	isNotFoundErrArgsForCall []struct {
+		arg1 error
+	}
+	isNotFoundErrReturns struct {
+		result1 bool
+	}
+	isNotFoundErrReturnsOnCall map[int]struct {
+		result1 bool
+	}
+	ListStub        func(context.Context, int) ([]models.Session, error)
+	listMutex       sync.RWMutex
+	listArgsForCall []struct {
+		arg1 context.Context
+		arg2 int
+	}
+	listReturns struct {
+		result1 []models.Session
+		result2 error
+	}
+	listReturnsOnCall map[int]struct {
+		result1 []models.Session
+		result2 error
+	}
+	RevokeStub        func(context.Context, int, string) error
+	revokeMutex       sync.RWMutex
+	revokeArgsForCall []struct {
+		arg1 context.Context
+		arg2 int
+		arg3 string
+	}
+	revokeReturns struct {
+		result1 error
+	}
+	revokeReturnsOnCall map[int]struct {
+		result1 error
+	}
+	RevokeAllStub        func(context.Context, int) error
+	revokeAllMutex       sync.RWMutex
+	revokeAllArgsForCall []struct {
+		arg1 context.Context
+		arg2 int
+	}
+	revokeAllReturns struct {
+		result1 error
+	}
+	revokeAllReturnsOnCall map[int]struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeSessionRevoker) IsNotFoundErr(arg1 error) bool {
+	fake.isNotFoundErrMutex.Lock()
+	ret, specificReturn := fake.isNotFoundErrReturnsOnCall[len(fake.isNotFoundErrArgsForCall)]
+	fake.isNotFoundErrArgsForCall = append(fake.isNotFoundErrArgsForCall, struct {
+		arg1 error
+	}{arg1})
+	fake.recordInvocation("IsNotFoundErr", []interface{}{arg1})
+	fake.isNotFoundErrMutex.Unlock()
+	if fake.IsNotFoundErrStub != nil {
+		return fake.IsNotFoundErrStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.isNotFoundErrReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeSessionRevoker) IsNotFoundErrCallCount() int {
+	fake.isNotFoundErrMutex.RLock()
+	defer fake.isNotFoundErrMutex.RUnlock()
+	return len(fake.isNotFoundErrArgsForCall)
+}
+
+func (fake *FakeSessionRevoker) IsNotFoundErrCalls(stub func(error) bool) {
+	fake.isNotFoundErrMutex.Lock()
+	defer fake.isNotFoundErrMutex.Unlock()
+	fake.IsNotFoundErrStub = stub
+}
+
+func (fake *FakeSessionRevoker) IsNotFoundErrArgsForCall(i int) error {
+	fake.isNotFoundErrMutex.RLock()
+	defer fake.isNotFoundErrMutex.RUnlock()
+	argsForCall := fake.isNotFoundErrArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeSessionRevoker) IsNotFoundErrReturns(result1 bool) {
+	fake.isNotFoundErrMutex.Lock()
+	defer fake.isNotFoundErrMutex.Unlock()
+	fake.IsNotFoundErrStub = nil
+	fake.isNotFoundErrReturns = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *FakeSessionRevoker) IsNotFoundErrReturnsOnCall(i int, result1 bool) {
+	fake.isNotFoundErrMutex.Lock()
+	defer fake.isNotFoundErrMutex.Unlock()
+	fake.IsNotFoundErrStub = nil
+	if fake.isNotFoundErrReturnsOnCall == nil {
+		fake.isNotFoundErrReturnsOnCall = make(map[int]struct {
+			result1 bool
+		})
+	}
+	fake.isNotFoundErrReturnsOnCall[i] = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *FakeSessionRevoker) List(arg1 context.Context, arg2 int) ([]models.Session, error) {
+	fake.listMutex.Lock()
+	ret, specificReturn := fake.listReturnsOnCall[len(fake.listArgsForCall)]
+	fake.listArgsForCall = append(fake.listArgsForCall, struct {
+		arg1 context.Context
+		arg2 int
+	}{arg1, arg2})
+	fake.recordInvocation("List", []interface{}{arg1, arg2})
+	fake.listMutex.Unlock()
+	if fake.ListStub != nil {
+		return fake.ListStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.listReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeSessionRevoker) ListCallCount() int {
+	fake.listMutex.RLock()
+	defer fake.listMutex.RUnlock()
+	return len(fake.listArgsForCall)
+}
+
+func (fake *FakeSessionRevoker) ListCalls(stub func(context.Context, int) ([]models.Session, error)) {
+	fake.listMutex.Lock()
+	defer fake.listMutex.Unlock()
+	fake.ListStub = stub
+}
+
+func (fake *FakeSessionRevoker) ListArgsForCall(i int) (context.Context, int) {
+	fake.listMutex.RLock()
+	defer fake.listMutex.RUnlock()
+	argsForCall := fake.listArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeSessionRevoker) ListReturns(result1 []models.Session, result2 error) {
+	fake.listMutex.Lock()
+	defer fake.listMutex.Unlock()
+	fake.ListStub = nil
+	fake.listReturns = struct {
+		result1 []models.Session
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeSessionRevoker) ListReturnsOnCall(i int, result1 []models.Session, result2 error) {
+	fake.listMutex.Lock()
+	defer fake.listMutex.Unlock()
+	fake.ListStub = nil
+	if fake.listReturnsOnCall == nil {
+		fake.listReturnsOnCall = make(map[int]struct {
+			result1 []models.Session
+			result2 error
+		})
+	}
+	fake.listReturnsOnCall[i] = struct {
+		result1 []models.Session
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeSessionRevoker) Revoke(arg1 context.Context, arg2 int, arg3 string) error {
+	fake.revokeMutex.Lock()
+	ret, specificReturn := fake.revokeReturnsOnCall[len(fake.revokeArgsForCall)]
+	fake.revokeArgsForCall = append(fake.revokeArgsForCall, struct {
+		arg1 context.Context
+		arg2 int
+		arg3 string
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("Revoke", []interface{}{arg1, arg2, arg3})
+	fake.revokeMutex.Unlock()
+	if fake.RevokeStub != nil {
+		return fake.RevokeStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.revokeReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeSessionRevoker) RevokeCallCount() int {
+	fake.revokeMutex.RLock()
+	defer fake.revokeMutex.RUnlock()
+	return len(fake.revokeArgsForCall)
+}
+
+func (fake *FakeSessionRevoker) RevokeCalls(stub func(context.Context, int, string) error) {
+	fake.revokeMutex.Lock()
+	defer fake.revokeMutex.Unlock()
+	fake.RevokeStub = stub
+}
+
+func (fake *FakeSessionRevoker) RevokeArgsForCall(i int) (context.Context, int, string) {
+	fake.revokeMutex.RLock()
+	defer fake.revokeMutex.RUnlock()
+	argsForCall := fake.revokeArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeSessionRevoker) RevokeReturns(result1 error) {
+	fake.revokeMutex.Lock()
+	defer fake.revokeMutex.Unlock()
+	fake.RevokeStub = nil
+	fake.revokeReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeSessionRevoker) RevokeReturnsOnCall(i int, result1 error) {
+	fake.revokeMutex.Lock()
+	defer fake.revokeMutex.Unlock()
+	fake.RevokeStub = nil
+	if fake.revokeReturnsOnCall == nil {
+		fake.revokeReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.revokeReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeSessionRevoker) RevokeAll(arg1 context.Context, arg2 int) error {
+	fake.revokeAllMutex.Lock()
+	ret, specificReturn := fake.revokeAllReturnsOnCall[len(fake.revokeAllArgsForCall)]
+	fake.revokeAllArgsForCall = append(fake.revokeAllArgsForCall, struct {
+		arg1 context.Context
+		arg2 int
+	}{arg1, arg2})
+	fake.recordInvocation("RevokeAll", []interface{}{arg1, arg2})
+	fake.revokeAllMutex.Unlock()
+	if fake.RevokeAllStub != nil {
+		return fake.RevokeAllStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.revokeAllReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeSessionRevoker) RevokeAllCallCount() int {
+	fake.revokeAllMutex.RLock()
+	defer fake.revokeAllMutex.RUnlock()
+	return len(fake.revokeAllArgsForCall)
+}
+
+func (fake *FakeSessionRevoker) RevokeAllCalls(stub func(context.Context, int) error) {
+	fake.revokeAllMutex.Lock()
+	defer fake.revokeAllMutex.Unlock()
+	fake.RevokeAllStub = stub
+}
+
+func (fake *FakeSessionRevoker) RevokeAllArgsForCall(i int) (context.Context, int) {
+	fake.revokeAllMutex.RLock()
+	defer fake.revokeAllMutex.RUnlock()
+	argsForCall := fake.revokeAllArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeSessionRevoker) RevokeAllReturns(result1 error) {
+	fake.revokeAllMutex.Lock()
+	defer fake.revokeAllMutex.Unlock()
+	fake.RevokeAllStub = nil
+	fake.revokeAllReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeSessionRevoker) RevokeAllReturnsOnCall(i int, result1 error) {
+	fake.revokeAllMutex.Lock()
+	defer fake.revokeAllMutex.Unlock()
+	fake.RevokeAllStub = nil
+	if fake.revokeAllReturnsOnCall == nil {
+		fake.revokeAllReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.revokeAllReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeSessionRevoker) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.isNotFoundErrMutex.RLock()
+	defer fake.isNotFoundErrMutex.RUnlock()
+	fake.listMutex.RLock()
+	defer fake.listMutex.RUnlock()
+	fake.revokeMutex.RLock()
+	defer fake.revokeMutex.RUnlock()
+	fake.revokeAllMutex.RLock()
+	defer fake.revokeAllMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeSessionRevoker) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ handlers.SessionRevoker = new(FakeSessionRevoker)
@@ -0,0 +1,116 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package handlersfakes
+
+import (
+	"sync"
+
+	"github.com/kieron-pivotal/menu-planner-app/handlers"
+	"github.com/kieron-pivotal/menu-planner-app/models"
+)
+
+type FakeShoppingListBuilder struct {
+	BuildStub        func([]models.Recipe) models.ShoppingList
+	buildMutex       sync.RWMutex
+	buildArgsForCall []struct {
+		arg1 []models.Recipe
+	}
+	buildReturns struct {
+		result1 models.ShoppingList
+	}
+	buildReturnsOnCall map[int]struct {
+		result1 models.ShoppingList
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeShoppingListBuilder) Build(arg1 []models.Recipe) models.ShoppingList {
+	var arg1Copy []models.Recipe
+	if arg1 != nil {
+		arg1Copy = make([]models.Recipe, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.buildMutex.Lock()
+	ret, specificReturn := fake.buildReturnsOnCall[len(fake.buildArgsForCall)]
+	fake.buildArgsForCall = append(fake.buildArgsForCall, struct {
+		arg1 []models.Recipe
+	}{arg1Copy})
+	fake.recordInvocation("Build", []interface{}{arg1Copy})
+	fake.buildMutex.Unlock()
+	if fake.BuildStub != nil {
+		return fake.BuildStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.buildReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeShoppingListBuilder) BuildCallCount() int {
+	fake.buildMutex.RLock()
+	defer fake.buildMutex.RUnlock()
+	return len(fake.buildArgsForCall)
+}
+
+func (fake *FakeShoppingListBuilder) BuildCalls(stub func([]models.Recipe) models.ShoppingList) {
+	fake.buildMutex.Lock()
+	defer fake.buildMutex.Unlock()
+	fake.BuildStub = stub
+}
+
+func (fake *FakeShoppingListBuilder) BuildArgsForCall(i int) []models.Recipe {
+	fake.buildMutex.RLock()
+	defer fake.buildMutex.RUnlock()
+	argsForCall := fake.buildArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeShoppingListBuilder) BuildReturns(result1 models.ShoppingList) {
+	fake.buildMutex.Lock()
+	defer fake.buildMutex.Unlock()
+	fake.BuildStub = nil
+	fake.buildReturns = struct {
+		result1 models.ShoppingList
+	}{result1}
+}
+
+func (fake *FakeShoppingListBuilder) BuildReturnsOnCall(i int, result1 models.ShoppingList) {
+	fake.buildMutex.Lock()
+	defer fake.buildMutex.Unlock()
+	fake.BuildStub = nil
+	if fake.buildReturnsOnCall == nil {
+		fake.buildReturnsOnCall = make(map[int]struct {
+			result1 models.ShoppingList
+		})
+	}
+	fake.buildReturnsOnCall[i] = struct {
+		result1 models.ShoppingList
+	}{result1}
+}
+
+func (fake *FakeShoppingListBuilder) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.buildMutex.RLock()
+	defer fake.buildMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeShoppingListBuilder) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ handlers.ShoppingListBuilder = new(FakeShoppingListBuilder)
@@ -0,0 +1,113 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package handlersfakes
+
+import (
+	"context"
+	"sync"
+
+	"github.com/kieron-pivotal/menu-planner-app/handlers"
+)
+
+type FakeTransactor struct {
+	InTxStub        func(context.Context, func(ctx context.Context) error) error
+	inTxMutex       sync.RWMutex
+	inTxArgsForCall []struct {
+		arg1 context.Context
+		arg2 func(ctx context.Context) error
+	}
+	inTxReturns struct {
+		result1 error
+	}
+	inTxReturnsOnCall map[int]struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeTransactor) InTx(arg1 context.Context, arg2 func(ctx context.Context) error) error {
+	fake.inTxMutex.Lock()
+	ret, specificReturn := fake.inTxReturnsOnCall[len(fake.inTxArgsForCall)]
+	fake.inTxArgsForCall = append(fake.inTxArgsForCall, struct {
+		arg1 context.Context
+		arg2 func(ctx context.Context) error
+	}{arg1, arg2})
+	fake.recordInvocation("InTx", []interface{}{arg1, arg2})
+	fake.inTxMutex.Unlock()
+	if fake.InTxStub != nil {
+		return fake.InTxStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.inTxReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeTransactor) InTxCallCount() int {
+	fake.inTxMutex.RLock()
+	defer fake.inTxMutex.RUnlock()
+	return len(fake.inTxArgsForCall)
+}
+
+func (fake *FakeTransactor) InTxCalls(stub func(context.Context, func(ctx context.Context) error) error) {
+	fake.inTxMutex.Lock()
+	defer fake.inTxMutex.Unlock()
+	fake.InTxStub = stub
+}
+
+func (fake *FakeTransactor) InTxArgsForCall(i int) (context.Context, func(ctx context.Context) error) {
+	fake.inTxMutex.RLock()
+	defer fake.inTxMutex.RUnlock()
+	argsForCall := fake.inTxArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeTransactor) InTxReturns(result1 error) {
+	fake.inTxMutex.Lock()
+	defer fake.inTxMutex.Unlock()
+	fake.InTxStub = nil
+	fake.inTxReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeTransactor) InTxReturnsOnCall(i int, result1 error) {
+	fake.inTxMutex.Lock()
+	defer fake.inTxMutex.Unlock()
+	fake.InTxStub = nil
+	if fake.inTxReturnsOnCall == nil {
+		fake.inTxReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.inTxReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeTransactor) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.inTxMutex.RLock()
+	defer fake.inTxMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeTransactor) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ handlers.Transactor = new(FakeTransactor)
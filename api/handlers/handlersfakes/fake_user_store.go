@@ -2,6 +2,7 @@
 package handlersfakes
 
 import (
+	"context"
 	"sync"
 
 	"github.com/kieron-pivotal/menu-planner-app/handlers"
@@ -9,11 +10,12 @@ import (
 )
 
 type FakeUserStore struct {
-	CreateStub        func(string, string) (models.User, error)
+	CreateStub        func(context.Context, string, string) (models.User, error)
 	createMutex       sync.RWMutex
 	createArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
 		arg2 string
+		arg3 string
 	}
 	createReturns struct {
 		result1 models.User
@@ -23,10 +25,11 @@ type FakeUserStore struct {
 		result1 models.User
 		result2 error
 	}
-	FindByEmailStub        func(string) (models.User, error)
+	FindByEmailStub        func(context.Context, string) (models.User, error)
 	findByEmailMutex       sync.RWMutex
 	findByEmailArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
+		arg2 string
 	}
 	findByEmailReturns struct {
 		result1 models.User
@@ -51,17 +54,18 @@ type FakeUserStore struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeUserStore) Create(arg1 string, arg2 string) (models.User, error) {
+func (fake *FakeUserStore) Create(arg1 context.Context, arg2 string, arg3 string) (models.User, error) {
 	fake.createMutex.Lock()
 	ret, specificReturn := fake.createReturnsOnCall[len(fake.createArgsForCall)]
 	fake.createArgsForCall = append(fake.createArgsForCall, struct {
-		arg1 string
+		arg1 context.Context
 		arg2 string
-	}{arg1, arg2})
-	fake.recordInvocation("Create", []interface{}{arg1, arg2})
+		arg3 string
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("Create", []interface{}{arg1, arg2, arg3})
 	fake.createMutex.Unlock()
 	if fake.CreateStub != nil {
-		return fake.CreateStub(arg1, arg2)
+		return fake.CreateStub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -76,17 +80,17 @@ func (fake *FakeUserStore) CreateCallCount() int {
 	return len(fake.createArgsForCall)
 }
 
-func (fake *FakeUserStore) CreateCalls(stub func(string, string) (models.User, error)) {
+func (fake *FakeUserStore) CreateCalls(stub func(context.Context, string, string) (models.User, error)) {
 	fake.createMutex.Lock()
 	defer fake.createMutex.Unlock()
 	fake.CreateStub = stub
 }
 
-func (fake *FakeUserStore) CreateArgsForCall(i int) (string, string) {
+func (fake *FakeUserStore) CreateArgsForCall(i int) (context.Context, string, string) {
 	fake.createMutex.RLock()
 	defer fake.createMutex.RUnlock()
 	argsForCall := fake.createArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeUserStore) CreateReturns(result1 models.User, result2 error) {
@@ -115,16 +119,17 @@ func (fake *FakeUserStore) CreateReturnsOnCall(i int, result1 models.User, resul
 	}{result1, result2}
 }
 
-func (fake *FakeUserStore) FindByEmail(arg1 string) (models.User, error) {
+func (fake *FakeUserStore) FindByEmail(arg1 context.Context, arg2 string) (models.User, error) {
 	fake.findByEmailMutex.Lock()
 	ret, specificReturn := fake.findByEmailReturnsOnCall[len(fake.findByEmailArgsForCall)]
 	fake.findByEmailArgsForCall = append(fake.findByEmailArgsForCall, struct {
-		arg1 string
-	}{arg1})
-	fake.recordInvocation("FindByEmail", []interface{}{arg1})
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	fake.recordInvocation("FindByEmail", []interface{}{arg1, arg2})
 	fake.findByEmailMutex.Unlock()
 	if fake.FindByEmailStub != nil {
-		return fake.FindByEmailStub(arg1)
+		return fake.FindByEmailStub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -139,17 +144,17 @@ func (fake *FakeUserStore) FindByEmailCallCount() int {
 	return len(fake.findByEmailArgsForCall)
 }
 
-func (fake *FakeUserStore) FindByEmailCalls(stub func(string) (models.User, error)) {
+func (fake *FakeUserStore) FindByEmailCalls(stub func(context.Context, string) (models.User, error)) {
 	fake.findByEmailMutex.Lock()
 	defer fake.findByEmailMutex.Unlock()
 	fake.FindByEmailStub = stub
 }
 
-func (fake *FakeUserStore) FindByEmailArgsForCall(i int) string {
+func (fake *FakeUserStore) FindByEmailArgsForCall(i int) (context.Context, string) {
 	fake.findByEmailMutex.RLock()
 	defer fake.findByEmailMutex.RUnlock()
 	argsForCall := fake.findByEmailArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakeUserStore) FindByEmailReturns(result1 models.User, result2 error) {
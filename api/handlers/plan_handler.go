@@ -0,0 +1,795 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/kieron-pivotal/menu-planner-app/models"
+	"github.com/kieron-pivotal/menu-planner-app/problem"
+)
+
+// weekFormat is how the Monday starting a plan's week is written
+const weekFormat = "2006-01-02"
+
+//counterfeiter:generate . PlanStore
+
+type PlanStore interface {
+	Get(ctx context.Context, userID int, week string) (models.Plan, error)
+	Weeks(ctx context.Context, userID int, from, to string) ([]models.Plan, error)
+	Save(ctx context.Context, plan models.Plan) error
+}
+
+//counterfeiter:generate . PlanTemplateStore
+
+type PlanTemplateStore interface {
+	IsNotFoundErr(error) bool
+	IsDuplicateErr(error) bool
+	ListTemplates(ctx context.Context, userID int) ([]models.PlanTemplate, error)
+	GetTemplate(ctx context.Context, userID, id int) (models.PlanTemplate, error)
+	InsertTemplate(ctx context.Context, template models.PlanTemplate) (models.PlanTemplate, error)
+	DeleteTemplate(ctx context.Context, userID, id int) error
+	ListRules(ctx context.Context, userID int) ([]models.PlanRule, error)
+	SaveRule(ctx context.Context, rule models.PlanRule) (models.PlanRule, error)
+	DeleteRule(ctx context.Context, userID, id int) error
+}
+
+type PlanHandler struct {
+	sessionManager      SessionManager
+	planStore           PlanStore
+	templateStore       PlanTemplateStore
+	recipeStore         RecipeStore
+	transactor          Transactor
+	nutritionCalculator NutritionCalculator
+	costEstimator       CostEstimator
+	budgetStore         BudgetStore
+	publisher           EventPublisher
+}
+
+func NewPlanHandler(
+	sessionManager SessionManager, planStore PlanStore, templateStore PlanTemplateStore,
+	recipeStore RecipeStore, transactor Transactor, nutritionCalculator NutritionCalculator,
+	costEstimator CostEstimator, budgetStore BudgetStore, publisher EventPublisher) *PlanHandler {
+	return &PlanHandler{
+		sessionManager:      sessionManager,
+		planStore:           planStore,
+		templateStore:       templateStore,
+		recipeStore:         recipeStore,
+		transactor:          transactor,
+		nutritionCalculator: nutritionCalculator,
+		costEstimator:       costEstimator,
+		budgetStore:         budgetStore,
+		publisher:           publisher,
+	}
+}
+
+// GetPlan returns the user's plan for the week starting on the Monday in
+// the path, with its nutrition totals and cost, which is compared with the
+// user's weekly budget if they have one
+func (h *PlanHandler) GetPlan(w http.ResponseWriter, r *http.Request) {
+	sess, err := h.sessionManager.Get(r.Context())
+	if err != nil || sess == nil || !sess.IsLoggedIn {
+		problem.Write(w, http.StatusUnauthorized, problem.Unauthorized, "")
+
+		return
+	}
+
+	week, ok := planWeek(w, r)
+	if !ok {
+		return
+	}
+
+	plan, err := h.planStore.Get(r.Context(), sess.ID, week)
+	if err != nil {
+		log.Printf("plan-get: %v\n", err)
+		problem.Write(w, http.StatusInternalServerError, problem.Internal, "")
+
+		return
+	}
+
+	recipes, err := h.recipeStore.GetMany(r.Context(), sess.ID, slotRecipes(plan.Slots))
+	if err != nil {
+		log.Printf("recipe-get-many: %v\n", err)
+		problem.Write(w, http.StatusInternalServerError, problem.Internal, "")
+
+		return
+	}
+	if plan, err = h.withTotals(r.Context(), plan, recipes); err != nil {
+		log.Printf("plan-get: %v\n", err)
+		problem.Write(w, http.StatusInternalServerError, problem.Internal, "")
+
+		return
+	}
+
+	w.Header().Add("Content-Type", "application/json")
+
+	if err = json.NewEncoder(w).Encode(plan); err != nil {
+		problem.Write(w, http.StatusInternalServerError, problem.Internal, "json encoding failure")
+
+		return
+	}
+}
+
+// SavePlan replaces the user's plan for the week with the slots in the
+// body, each of which must be for one of their recipes. A leftover slot
+// eats portions cooked at an earlier slot, and can't eat more than are
+// left.
+func (h *PlanHandler) SavePlan(w http.ResponseWriter, r *http.Request) {
+	sess, err := h.sessionManager.Get(r.Context())
+	if err != nil || sess == nil || !sess.IsLoggedIn {
+		problem.Write(w, http.StatusUnauthorized, problem.Unauthorized, "")
+
+		return
+	}
+
+	week, ok := planWeek(w, r)
+	if !ok {
+		return
+	}
+
+	plan := models.Plan{Slots: []models.Slot{}}
+	if !decodeJSON(w, r, &plan, maxBodyBytes) {
+		return
+	}
+
+	if errs := validatePlan(plan); len(errs) > 0 {
+		problem.WriteInvalid(w, errs)
+
+		return
+	}
+
+	linkLeftovers(plan.Slots)
+
+	recipes, ok := h.checkRecipes(w, r, sess.ID, plan.Slots, slotRecipeField)
+	if !ok {
+		return
+	}
+
+	if _, errs := planLeftovers(plan.Slots, recipes); len(errs) > 0 {
+		problem.WriteInvalid(w, errs)
+
+		return
+	}
+
+	plan.UserID, plan.Week = sess.ID, week
+	models.SortSlots(plan.Slots)
+
+	err = h.transactor.InTx(r.Context(), func(ctx context.Context) error {
+		return h.planStore.Save(ctx, plan)
+	})
+	if err != nil {
+		log.Printf("plan-save: %v\n", err)
+		problem.Write(w, http.StatusInternalServerError, problem.Internal, "")
+
+		return
+	}
+
+	publish(r.Context(), h.publisher, sess.ID, planEvents(week)...)
+
+	if plan, err = h.withTotals(r.Context(), plan, recipes); err != nil {
+		log.Printf("plan-save: %v\n", err)
+		problem.Write(w, http.StatusInternalServerError, problem.Internal, "")
+
+		return
+	}
+
+	w.Header().Add("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(plan)
+}
+
+// ApplyTemplate fills the meals of the week which have nothing planned
+// from the template whose id is in the body. Meals with another recipe
+// planned are left alone and listed as conflicts.
+func (h *PlanHandler) ApplyTemplate(w http.ResponseWriter, r *http.Request) {
+	sess, err := h.sessionManager.Get(r.Context())
+	if err != nil || sess == nil || !sess.IsLoggedIn {
+		problem.Write(w, http.StatusUnauthorized, problem.Unauthorized, "")
+
+		return
+	}
+
+	week, ok := planWeek(w, r)
+	if !ok {
+		return
+	}
+
+	var apply struct {
+		TemplateID int `json:"templateId"`
+	}
+	if !decodeJSON(w, r, &apply, maxBodyBytes) {
+		return
+	}
+
+	if apply.TemplateID <= 0 {
+		problem.WriteInvalid(w, []problem.FieldError{{Field: "templateId", Message: "is required"}})
+
+		return
+	}
+
+	h.apply(w, r, sess.ID, week, func(ctx context.Context) ([]models.Slot, error) {
+		template, err := h.templateStore.GetTemplate(ctx, sess.ID, apply.TemplateID)
+		if err != nil {
+			return nil, err
+		}
+		return template.Slots, nil
+	})
+}
+
+// ApplyRules fills the meals of the week which have nothing planned from
+// the user's rules. Meals with another recipe planned are left alone and
+// listed as conflicts.
+func (h *PlanHandler) ApplyRules(w http.ResponseWriter, r *http.Request) {
+	sess, err := h.sessionManager.Get(r.Context())
+	if err != nil || sess == nil || !sess.IsLoggedIn {
+		problem.Write(w, http.StatusUnauthorized, problem.Unauthorized, "")
+
+		return
+	}
+
+	week, ok := planWeek(w, r)
+	if !ok {
+		return
+	}
+
+	h.apply(w, r, sess.ID, week, func(ctx context.Context) ([]models.Slot, error) {
+		rules, err := h.templateStore.ListRules(ctx, sess.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		slots := []models.Slot{}
+		for _, rule := range rules {
+			slots = append(slots, rule.Slot)
+		}
+		return slots, nil
+	})
+}
+
+// apply fills the week's plan with the slots from load in a unit of work,
+// and returns it with the totals GetPlan adds
+func (h *PlanHandler) apply(
+	w http.ResponseWriter, r *http.Request, userID int, week string,
+	load func(ctx context.Context) ([]models.Slot, error)) {
+	var change models.PlanChange
+	err := h.transactor.InTx(r.Context(), func(ctx context.Context) error {
+		slots, err := load(ctx)
+		if err != nil {
+			return err
+		}
+
+		plan, err := h.planStore.Get(ctx, userID, week)
+		if err != nil {
+			return err
+		}
+
+		change = fillSlots(plan, slots)
+		return h.planStore.Save(ctx, change.Plan)
+	})
+	if err != nil {
+		if h.templateStore.IsNotFoundErr(err) {
+			problem.Write(w, http.StatusNotFound, problem.NotFound, "")
+
+			return
+		}
+
+		log.Printf("plan-apply: %v\n", err)
+		problem.Write(w, http.StatusInternalServerError, problem.Internal, "")
+
+		return
+	}
+
+	publish(r.Context(), h.publisher, userID, planEvents(week)...)
+
+	recipes, err := h.recipeStore.GetMany(r.Context(), userID, slotRecipes(change.Plan.Slots))
+	if err != nil {
+		log.Printf("recipe-get-many: %v\n", err)
+		problem.Write(w, http.StatusInternalServerError, problem.Internal, "")
+
+		return
+	}
+	if change.Plan, err = h.withTotals(r.Context(), change.Plan, recipes); err != nil {
+		log.Printf("plan-apply: %v\n", err)
+		problem.Write(w, http.StatusInternalServerError, problem.Internal, "")
+
+		return
+	}
+
+	w.Header().Add("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(change)
+}
+
+func (h *PlanHandler) ListPlanTemplates(w http.ResponseWriter, r *http.Request) {
+	sess, err := h.sessionManager.Get(r.Context())
+	if err != nil || sess == nil || !sess.IsLoggedIn {
+		problem.Write(w, http.StatusUnauthorized, problem.Unauthorized, "")
+
+		return
+	}
+
+	templates, err := h.templateStore.ListTemplates(r.Context(), sess.ID)
+	if err != nil {
+		log.Printf("plan-template-list: %v\n", err)
+		problem.Write(w, http.StatusInternalServerError, problem.Internal, "")
+
+		return
+	}
+
+	w.Header().Add("Content-Type", "application/json")
+
+	if err = json.NewEncoder(w).Encode(templates); err != nil {
+		problem.Write(w, http.StatusInternalServerError, problem.Internal, "json encoding failure")
+
+		return
+	}
+}
+
+// NewPlanTemplate saves a named week layout. It is a 409 if the user has
+// a template with the same name.
+func (h *PlanHandler) NewPlanTemplate(w http.ResponseWriter, r *http.Request) {
+	sess, err := h.sessionManager.Get(r.Context())
+	if err != nil || sess == nil || !sess.IsLoggedIn {
+		problem.Write(w, http.StatusUnauthorized, problem.Unauthorized, "")
+
+		return
+	}
+
+	template := models.PlanTemplate{Slots: []models.Slot{}}
+	if !decodeJSON(w, r, &template, maxBodyBytes) {
+		return
+	}
+
+	template.Name = strings.TrimSpace(template.Name)
+	if errs := validatePlanTemplate(template); len(errs) > 0 {
+		problem.WriteInvalid(w, errs)
+
+		return
+	}
+
+	if _, ok := h.checkRecipes(w, r, sess.ID, template.Slots, slotRecipeField); !ok {
+		return
+	}
+
+	template.UserID = sess.ID
+	models.SortSlots(template.Slots)
+
+	err = h.transactor.InTx(r.Context(), func(ctx context.Context) error {
+		template, err = h.templateStore.InsertTemplate(ctx, template)
+		return err
+	})
+	if err != nil {
+		if h.templateStore.IsDuplicateErr(err) {
+			problem.Write(w, http.StatusConflict, problem.DuplicateName,
+				fmt.Sprintf("you already have a plan template called %q", template.Name))
+
+			return
+		}
+
+		log.Printf("plan-template-insert: %v\n", err)
+		problem.Write(w, http.StatusInternalServerError, problem.Internal, "")
+
+		return
+	}
+
+	publish(r.Context(), h.publisher, sess.ID, models.Event{Type: models.PlanTemplatesChanged})
+
+	w.Header().Add("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(template)
+}
+
+func (h *PlanHandler) DeletePlanTemplate(w http.ResponseWriter, r *http.Request) {
+	sess, err := h.sessionManager.Get(r.Context())
+	if err != nil || sess == nil || !sess.IsLoggedIn {
+		problem.Write(w, http.StatusUnauthorized, problem.Unauthorized, "")
+
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		problem.Write(w, http.StatusNotFound, problem.NotFound, "")
+
+		return
+	}
+
+	if err = h.templateStore.DeleteTemplate(r.Context(), sess.ID, id); err != nil {
+		if h.templateStore.IsNotFoundErr(err) {
+			problem.Write(w, http.StatusNotFound, problem.NotFound, "")
+
+			return
+		}
+
+		log.Printf("plan-template-delete: %v\n", err)
+		problem.Write(w, http.StatusInternalServerError, problem.Internal, "")
+
+		return
+	}
+
+	publish(r.Context(), h.publisher, sess.ID, models.Event{Type: models.PlanTemplatesChanged})
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *PlanHandler) ListPlanRules(w http.ResponseWriter, r *http.Request) {
+	sess, err := h.sessionManager.Get(r.Context())
+	if err != nil || sess == nil || !sess.IsLoggedIn {
+		problem.Write(w, http.StatusUnauthorized, problem.Unauthorized, "")
+
+		return
+	}
+
+	rules, err := h.templateStore.ListRules(r.Context(), sess.ID)
+	if err != nil {
+		log.Printf("plan-rule-list: %v\n", err)
+		problem.Write(w, http.StatusInternalServerError, problem.Internal, "")
+
+		return
+	}
+
+	w.Header().Add("Content-Type", "application/json")
+
+	if err = json.NewEncoder(w).Encode(rules); err != nil {
+		problem.Write(w, http.StatusInternalServerError, problem.Internal, "json encoding failure")
+
+		return
+	}
+}
+
+// SavePlanRule plans a recipe for a meal every week, replacing the rule
+// the user had for the meal. Rules are only put into a week's plan when
+// they are applied to it.
+func (h *PlanHandler) SavePlanRule(w http.ResponseWriter, r *http.Request) {
+	sess, err := h.sessionManager.Get(r.Context())
+	if err != nil || sess == nil || !sess.IsLoggedIn {
+		problem.Write(w, http.StatusUnauthorized, problem.Unauthorized, "")
+
+		return
+	}
+
+	rule := models.PlanRule{}
+	if !decodeJSON(w, r, &rule, maxBodyBytes) {
+		return
+	}
+
+	if errs := validatePlanRule(rule); len(errs) > 0 {
+		problem.WriteInvalid(w, errs)
+
+		return
+	}
+
+	if _, ok := h.checkRecipes(w, r, sess.ID, []models.Slot{rule.Slot}, func(int) string { return "recipeId" }); !ok {
+		return
+	}
+
+	rule.UserID = sess.ID
+
+	rule, err = h.templateStore.SaveRule(r.Context(), rule)
+	if err != nil {
+		log.Printf("plan-rule-save: %v\n", err)
+		problem.Write(w, http.StatusInternalServerError, problem.Internal, "")
+
+		return
+	}
+
+	publish(r.Context(), h.publisher, sess.ID, models.Event{Type: models.PlanTemplatesChanged})
+
+	w.Header().Add("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rule)
+}
+
+func (h *PlanHandler) DeletePlanRule(w http.ResponseWriter, r *http.Request) {
+	sess, err := h.sessionManager.Get(r.Context())
+	if err != nil || sess == nil || !sess.IsLoggedIn {
+		problem.Write(w, http.StatusUnauthorized, problem.Unauthorized, "")
+
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		problem.Write(w, http.StatusNotFound, problem.NotFound, "")
+
+		return
+	}
+
+	if err = h.templateStore.DeleteRule(r.Context(), sess.ID, id); err != nil {
+		if h.templateStore.IsNotFoundErr(err) {
+			problem.Write(w, http.StatusNotFound, problem.NotFound, "")
+
+			return
+		}
+
+		log.Printf("plan-rule-delete: %v\n", err)
+		problem.Write(w, http.StatusInternalServerError, problem.Internal, "")
+
+		return
+	}
+
+	publish(r.Context(), h.publisher, sess.ID, models.Event{Type: models.PlanTemplatesChanged})
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// planEvents tell the user's clients that the week's plan, and so its
+// shopping list, changed
+func planEvents(week string) []models.Event {
+	return []models.Event{
+		{Type: models.PlanChanged, Week: week},
+		{Type: models.ShoppingListChanged, Week: week},
+	}
+}
+
+// checkRecipes loads the slots' recipes in one batch, writing a 422 if any
+// aren't the user's, and returns them if they all are. field names a
+// slot's recipe id in the body from its index.
+func (h *PlanHandler) checkRecipes(
+	w http.ResponseWriter, r *http.Request, userID int, slots []models.Slot, field func(i int) string) ([]models.Recipe, bool) {
+	recipes, err := h.recipeStore.GetMany(r.Context(), userID, slotRecipes(slots))
+	if err != nil {
+		log.Printf("recipe-get-many: %v\n", err)
+		problem.Write(w, http.StatusInternalServerError, problem.Internal, "")
+
+		return nil, false
+	}
+
+	owned := map[int]bool{}
+	for _, recipe := range recipes {
+		owned[recipe.ID] = true
+	}
+
+	e := fieldErrors{}
+	for i, slot := range slots {
+		if !owned[slot.RecipeID] {
+			e.add(field(i), "must be one of your recipes")
+		}
+	}
+	if len(e) > 0 {
+		problem.WriteInvalid(w, e)
+
+		return nil, false
+	}
+
+	return recipes, true
+}
+
+// withTotals adds the plan's nutrition totals, cost and leftovers from its
+// recipes
+func (h *PlanHandler) withTotals(ctx context.Context, plan models.Plan, recipes []models.Recipe) (models.Plan, error) {
+	perRecipe := map[int]*models.Nutrition{}
+	for _, recipe := range recipes {
+		perRecipe[recipe.ID] = h.nutritionCalculator.ForRecipe(recipe)
+	}
+	plan.Nutrition = planNutrition(plan.Slots, perRecipe)
+	plan.Leftovers, _ = planLeftovers(plan.Slots, recipes)
+
+	costs, err := h.costEstimator.ForRecipes(ctx, plan.UserID, recipes)
+	if err != nil {
+		return models.Plan{}, err
+	}
+
+	budget, err := h.budgetStore.Get(ctx, plan.UserID)
+	if err != nil {
+		return models.Plan{}, err
+	}
+
+	plan.Cost = planCost(plan.Slots, recipes, costs, budget)
+
+	return plan, nil
+}
+
+// planNutrition adds up a serving of each slot's recipe by day and for the
+// week
+func planNutrition(slots []models.Slot, perRecipe map[int]*models.Nutrition) *models.PlanNutrition {
+	res := &models.PlanNutrition{Days: make([]models.Macros, 7)}
+	missing := map[string]bool{}
+
+	for _, slot := range slots {
+		n := perRecipe[slot.RecipeID]
+		if n == nil {
+			continue
+		}
+
+		res.Days[slot.Day] = res.Days[slot.Day].Add(n.PerServing)
+		for _, name := range n.Missing {
+			if !missing[name] {
+				missing[name] = true
+				res.Missing = append(res.Missing, name)
+			}
+		}
+	}
+
+	for i, day := range res.Days {
+		res.Days[i] = day.Round()
+		res.Week = res.Week.Add(day)
+	}
+	res.Week = res.Week.Round()
+
+	return res
+}
+
+// linkLeftovers gives each leftover slot the recipe of the slot it is a
+// leftover of
+func linkLeftovers(slots []models.Slot) {
+	cooked := map[models.SlotRef]int{}
+	for _, slot := range slots {
+		if slot.LeftoverOf == nil {
+			cooked[slot.Ref()] = slot.RecipeID
+		}
+	}
+
+	for i, slot := range slots {
+		if slot.LeftoverOf != nil {
+			slots[i].RecipeID = cooked[*slot.LeftoverOf]
+		}
+	}
+}
+
+// planLeftovers follows the portions cooked at each slot which makes more
+// than are eaten at it through the week's leftover slots. A recipe makes
+// its servings, or one portion if they aren't known; a cooked slot which
+// eats more is taken to have scaled the recipe up. It reports leftover
+// slots which eat more portions than are left.
+func planLeftovers(slots []models.Slot, recipes []models.Recipe) ([]models.Batch, []problem.FieldError) {
+	servings := map[int]int{}
+	for _, recipe := range recipes {
+		servings[recipe.ID] = recipe.Servings
+	}
+
+	batches := map[models.SlotRef]*models.Batch{}
+	order := []models.SlotRef{}
+	for _, slot := range slots {
+		if slot.LeftoverOf != nil {
+			continue
+		}
+
+		cooked := servings[slot.RecipeID]
+		if cooked < 1 {
+			cooked = 1
+		}
+		if cooked > slot.Eats() {
+			batches[slot.Ref()] = &models.Batch{
+				SlotRef: slot.Ref(), RecipeID: slot.RecipeID, Cooked: cooked, Remaining: cooked - slot.Eats(),
+			}
+			order = append(order, slot.Ref())
+		}
+	}
+
+	// eat the leftovers in the order of the week, whatever the order of
+	// the slots
+	leftovers := []int{}
+	for i, slot := range slots {
+		if slot.LeftoverOf != nil {
+			leftovers = append(leftovers, i)
+		}
+	}
+	sort.SliceStable(leftovers, func(i, j int) bool {
+		return slots[leftovers[i]].Ref().Before(slots[leftovers[j]].Ref())
+	})
+
+	e := fieldErrors{}
+	for _, i := range leftovers {
+		slot := slots[i]
+		batch, ok := batches[*slot.LeftoverOf]
+		remaining := 0
+		if ok {
+			remaining = batch.Remaining
+		}
+		if slot.Eats() > remaining {
+			e.add(fmt.Sprintf("slots[%d].portions", i), fmt.Sprintf("must be at most the %d portions left", remaining))
+		}
+		if ok {
+			batch.Remaining -= slot.Eats()
+		}
+	}
+
+	res := []models.Batch{}
+	for _, ref := range order {
+		res = append(res, *batches[ref])
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].SlotRef.Before(res[j].SlotRef) })
+
+	return res, e
+}
+
+// slotRecipes are the ids of the slots' recipes
+func slotRecipes(slots []models.Slot) []int {
+	ids := []int{}
+	for _, slot := range slots {
+		ids = append(ids, slot.RecipeID)
+	}
+	return ids
+}
+
+// planCost adds up the cost of the portions eaten at each slot, a recipe's
+// cost covering its servings, or one portion if they aren't known. The
+// portions a leftover slot eats are costed there rather than where they
+// were cooked. It compares the total with the budget if there is one.
+func planCost(slots []models.Slot, recipes []models.Recipe, costs map[int]*models.Cost, budget models.Budget) *models.PlanCost {
+	servings := map[int]int{}
+	for _, recipe := range recipes {
+		servings[recipe.ID] = recipe.Servings
+	}
+
+	res := &models.PlanCost{}
+	missing := map[string]bool{}
+	total := 0.0
+
+	for _, slot := range slots {
+		cost := costs[slot.RecipeID]
+		if cost == nil {
+			continue
+		}
+
+		portions := servings[slot.RecipeID]
+		if portions < 1 {
+			portions = 1
+		}
+		total += float64(cost.Total) * float64(slot.Eats()) / float64(portions)
+		for _, name := range cost.Missing {
+			if !missing[name] {
+				missing[name] = true
+				res.Missing = append(res.Missing, name)
+			}
+		}
+	}
+	res.Total = int(math.Round(total))
+
+	if budget.Weekly > 0 {
+		res.Budget = &models.BudgetReport{
+			Weekly:    budget.Weekly,
+			Remaining: budget.Weekly - res.Total,
+			Over:      res.Total > budget.Weekly,
+		}
+	}
+
+	return res
+}
+
+func slotRecipeField(i int) string {
+	return fmt.Sprintf("slots[%d].recipeId", i)
+}
+
+// fillSlots plans the slots' recipes for the meals the plan has nothing
+// for. Meals planned with another recipe are conflicts.
+func fillSlots(plan models.Plan, slots []models.Slot) models.PlanChange {
+	planned := map[models.Slot]int{}
+	for _, slot := range plan.Slots {
+		planned[models.Slot{Day: slot.Day, Meal: slot.Meal}] = slot.RecipeID
+	}
+
+	change := models.PlanChange{Plan: plan, Conflicts: []models.SlotConflict{}}
+	change.Plan.Slots = append([]models.Slot{}, plan.Slots...)
+	for _, slot := range slots {
+		recipeID, ok := planned[models.Slot{Day: slot.Day, Meal: slot.Meal}]
+		switch {
+		case !ok:
+			change.Plan.Slots = append(change.Plan.Slots, slot)
+		case recipeID != slot.RecipeID:
+			change.Conflicts = append(change.Conflicts, models.SlotConflict{Slot: slot, PlannedRecipeID: recipeID})
+		}
+	}
+	models.SortSlots(change.Plan.Slots)
+
+	return change
+}
+
+// planWeek reads the week from the path, writing a 400 unless it is the
+// date of a Monday
+func planWeek(w http.ResponseWriter, r *http.Request) (string, bool) {
+	week := mux.Vars(r)["week"]
+	if date, err := time.Parse(weekFormat, week); err != nil || date.Weekday() != time.Monday {
+		problem.Write(w, http.StatusBadRequest, problem.InvalidParameter, "week must be the date of a Monday, e.g. 2026-10-19")
+
+		return "", false
+	}
+
+	return week, true
+}
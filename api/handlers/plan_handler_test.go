@@ -0,0 +1,733 @@
+package handlers_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/kieron-pivotal/menu-planner-app/db"
+	"github.com/kieron-pivotal/menu-planner-app/handlers"
+	"github.com/kieron-pivotal/menu-planner-app/handlers/handlersfakes"
+	"github.com/kieron-pivotal/menu-planner-app/models"
+	"github.com/kieron-pivotal/menu-planner-app/session"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PlanHandler", func() {
+	var (
+		sessionManager *handlersfakes.FakeSessionManager
+		planStore      *handlersfakes.FakePlanStore
+		templateStore  *handlersfakes.FakePlanTemplateStore
+		recipeStore    *handlersfakes.FakeRecipeStore
+		transactor     *handlersfakes.FakeTransactor
+		nutrition      *handlersfakes.FakeNutritionCalculator
+		costEstimator  *handlersfakes.FakeCostEstimator
+		budgetStore    *handlersfakes.FakeBudgetStore
+		publisher      *handlersfakes.FakeEventPublisher
+		recorder       *httptest.ResponseRecorder
+		req            *http.Request
+		httpHandlers   *handlers.PlanHandler
+		week           string
+		body           string
+	)
+
+	published := func() []models.Event {
+		events := []models.Event{}
+		for i := 0; i < publisher.PublishCallCount(); i++ {
+			_, userID, event := publisher.PublishArgsForCall(i)
+			Expect(userID).To(Equal(234))
+			events = append(events, event)
+		}
+		return events
+	}
+
+	send := func(method, path string, vars map[string]string, handle http.HandlerFunc) {
+		var err error
+		req, err = http.NewRequest(method, path, strings.NewReader(body))
+		Expect(err).NotTo(HaveOccurred())
+		req = mux.SetURLVars(req, vars)
+		handle(recorder, req)
+	}
+
+	BeforeEach(func() {
+		sessionManager = new(handlersfakes.FakeSessionManager)
+		sessionManager.GetReturns(&session.AuthInfo{IsLoggedIn: true, Name: "forest", ID: 234}, nil)
+		planStore = new(handlersfakes.FakePlanStore)
+		planStore.GetStub = func(_ context.Context, userID int, week string) (models.Plan, error) {
+			return models.Plan{UserID: userID, Week: week, Slots: []models.Slot{}}, nil
+		}
+		templateStore = new(handlersfakes.FakePlanTemplateStore)
+		templateStore.IsNotFoundErrStub = func(err error) bool { return err == db.NotFoundErr() }
+		recipeStore = new(handlersfakes.FakeRecipeStore)
+		recipeStore.GetManyStub = func(_ context.Context, _ int, ids []int) ([]models.Recipe, error) {
+			res := []models.Recipe{}
+			for _, id := range ids {
+				if id < 100 {
+					res = append(res, models.Recipe{ID: id})
+				}
+			}
+			return res, nil
+		}
+		transactor = new(handlersfakes.FakeTransactor)
+		transactor.InTxStub = func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		}
+		nutrition = new(handlersfakes.FakeNutritionCalculator)
+		nutrition.ForRecipeStub = func(recipe models.Recipe) *models.Nutrition {
+			return &models.Nutrition{PerServing: models.Macros{Calories: float64(recipe.ID) * 100, Protein: 0.1}}
+		}
+		costEstimator = new(handlersfakes.FakeCostEstimator)
+		costEstimator.ForRecipesStub = func(_ context.Context, _ int, recipes []models.Recipe) (map[int]*models.Cost, error) {
+			res := map[int]*models.Cost{}
+			for _, recipe := range recipes {
+				res[recipe.ID] = &models.Cost{Total: recipe.ID * 100}
+			}
+			return res, nil
+		}
+		budgetStore = new(handlersfakes.FakeBudgetStore)
+		publisher = new(handlersfakes.FakeEventPublisher)
+		httpHandlers = handlers.NewPlanHandler(sessionManager, planStore, templateStore, recipeStore, transactor, nutrition, costEstimator, budgetStore, publisher)
+		recorder = httptest.NewRecorder()
+		week = "2026-10-19"
+		body = ""
+	})
+
+	Describe("GetPlan", func() {
+		BeforeEach(func() {
+			planStore.GetReturns(models.Plan{Week: week, Slots: []models.Slot{{Day: 4, Meal: models.Dinner, RecipeID: 3}}}, nil)
+		})
+
+		JustBeforeEach(func() {
+			send(http.MethodGet, "/plans/"+week, map[string]string{"week": week}, httpHandlers.GetPlan)
+		})
+
+		When("I'm logged out", func() {
+			BeforeEach(func() {
+				sessionManager.GetReturns(&session.AuthInfo{IsLoggedIn: false}, nil)
+			})
+
+			It("returns a status not auth'ed", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusUnauthorized))
+			})
+		})
+
+		It("returns the user's plan for the week", func() {
+			_, userID, w := planStore.GetArgsForCall(0)
+			Expect(userID).To(Equal(234))
+			Expect(w).To(Equal("2026-10-19"))
+			Expect(recorder.Header().Get("Content-Type")).To(Equal("application/json"))
+			Expect(recorder.Body.String()).To(ContainSubstring(`"slots":[{"day":4,"meal":"dinner","recipeId":3}]`))
+		})
+
+		When("several meals are planned", func() {
+			BeforeEach(func() {
+				planStore.GetReturns(models.Plan{UserID: 234, Week: week, Slots: []models.Slot{
+					{Day: 0, Meal: models.Lunch, RecipeID: 1},
+					{Day: 0, Meal: models.Dinner, RecipeID: 3},
+					{Day: 4, Meal: models.Dinner, RecipeID: 3},
+				}}, nil)
+				nutrition.ForRecipeStub = func(recipe models.Recipe) *models.Nutrition {
+					if recipe.ID == 1 {
+						return &models.Nutrition{PerServing: models.Macros{Calories: 250, Protein: 0.1}, Missing: []string{"sumac"}}
+					}
+					return &models.Nutrition{PerServing: models.Macros{Calories: 600, Protein: 0.2}, Missing: []string{"sumac", "yuzu"}}
+				}
+			})
+
+			It("totals a serving of each slot by day and for the week", func() {
+				Expect(recipeStore.GetManyCallCount()).To(Equal(1))
+				_, _, ids := recipeStore.GetManyArgsForCall(0)
+				Expect(ids).To(Equal([]int{1, 3, 3}))
+
+				Expect(recorder.Body.String()).To(MatchJSON(`{
+					"week": "2026-10-19",
+					"slots": [
+						{"day": 0, "meal": "lunch", "recipeId": 1},
+						{"day": 0, "meal": "dinner", "recipeId": 3},
+						{"day": 4, "meal": "dinner", "recipeId": 3}
+					],
+					"nutrition": {
+						"days": [
+							{"calories": 850, "protein": 0.3, "fat": 0, "carbohydrate": 0},
+							{"calories": 0, "protein": 0, "fat": 0, "carbohydrate": 0},
+							{"calories": 0, "protein": 0, "fat": 0, "carbohydrate": 0},
+							{"calories": 0, "protein": 0, "fat": 0, "carbohydrate": 0},
+							{"calories": 600, "protein": 0.2, "fat": 0, "carbohydrate": 0},
+							{"calories": 0, "protein": 0, "fat": 0, "carbohydrate": 0},
+							{"calories": 0, "protein": 0, "fat": 0, "carbohydrate": 0}
+						],
+						"week": {"calories": 1450, "protein": 0.5, "fat": 0, "carbohydrate": 0},
+						"missing": ["sumac", "yuzu"]
+					},
+					"cost": {"total": 700}
+				}`))
+			})
+
+			It("costs cooking each slot's recipe with one list of prices", func() {
+				Expect(costEstimator.ForRecipesCallCount()).To(Equal(1))
+				_, userID, recipes := costEstimator.ForRecipesArgsForCall(0)
+				Expect(userID).To(Equal(234))
+				Expect(recipes).To(HaveLen(3))
+			})
+
+			When("slots eat a different number of portions than their recipes serve", func() {
+				BeforeEach(func() {
+					planStore.GetReturns(models.Plan{UserID: 234, Week: week, Slots: []models.Slot{
+						{Day: 0, Meal: models.Lunch, RecipeID: 1, Portions: 6},
+						{Day: 0, Meal: models.Dinner, RecipeID: 3},
+						{Day: 4, Meal: models.Dinner, RecipeID: 3, Portions: 3},
+					}}, nil)
+					recipeStore.GetManyStub = func(_ context.Context, _ int, ids []int) ([]models.Recipe, error) {
+						res := []models.Recipe{}
+						for _, id := range ids {
+							res = append(res, models.Recipe{ID: id, Servings: 4})
+						}
+						return res, nil
+					}
+				})
+
+				It("costs each slot's share of its recipe", func() {
+					Expect(recorder.Body.String()).To(ContainSubstring(`"cost":{"total":450}`))
+				})
+			})
+
+			When("some ingredients have no price", func() {
+				BeforeEach(func() {
+					costEstimator.ForRecipesReturns(map[int]*models.Cost{
+						1: {Total: 120, Missing: []string{"sumac"}},
+						3: {Total: 400, Missing: []string{"yuzu", "sumac"}},
+					}, nil)
+					costEstimator.ForRecipesStub = nil
+				})
+
+				It("lists each of them once", func() {
+					Expect(recorder.Body.String()).To(ContainSubstring(`"cost":{"total":920,"missing":["sumac","yuzu"]}`))
+				})
+			})
+
+			When("the user has a weekly budget the plan is under", func() {
+				BeforeEach(func() {
+					budgetStore.GetReturns(models.Budget{UserID: 234, Weekly: 1000}, nil)
+				})
+
+				It("reports what is left", func() {
+					_, userID := budgetStore.GetArgsForCall(0)
+					Expect(userID).To(Equal(234))
+					Expect(recorder.Body.String()).To(ContainSubstring(`"cost":{"total":700,"budget":{"weekly":1000,"remaining":300,"over":false}}`))
+				})
+			})
+
+			When("the plan is over the user's weekly budget", func() {
+				BeforeEach(func() {
+					budgetStore.GetReturns(models.Budget{UserID: 234, Weekly: 500}, nil)
+				})
+
+				It("reports how far over it is", func() {
+					Expect(recorder.Body.String()).To(ContainSubstring(`"cost":{"total":700,"budget":{"weekly":500,"remaining":-200,"over":true}}`))
+				})
+			})
+		})
+
+		When("the costs can't be estimated", func() {
+			BeforeEach(func() {
+				costEstimator.ForRecipesStub = nil
+				costEstimator.ForRecipesReturns(nil, errors.New("boom"))
+			})
+
+			It("returns an internal server error", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusInternalServerError))
+			})
+		})
+
+		When("the budget can't be read", func() {
+			BeforeEach(func() {
+				budgetStore.GetReturns(models.Budget{}, errors.New("boom"))
+			})
+
+			It("returns an internal server error", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusInternalServerError))
+			})
+		})
+
+		When("loading the recipes fails", func() {
+			BeforeEach(func() {
+				recipeStore.GetManyStub = nil
+				recipeStore.GetManyReturns(nil, errors.New("boom"))
+			})
+
+			It("returns an internal server error", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusInternalServerError))
+			})
+		})
+
+		When("the week isn't a Monday", func() {
+			It("returns a bad request status", func() {
+				for _, w := range []string{"2026-10-20", "next-week", "19/10/2026"} {
+					recorder = httptest.NewRecorder()
+					send(http.MethodGet, "/plans/"+w, map[string]string{"week": w}, httpHandlers.GetPlan)
+					Expect(recorder.Result().StatusCode).To(Equal(http.StatusBadRequest), w)
+				}
+			})
+		})
+
+		When("the store fails", func() {
+			BeforeEach(func() {
+				planStore.GetReturns(models.Plan{}, errors.New("boom"))
+			})
+
+			It("returns an internal server error", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusInternalServerError))
+			})
+		})
+	})
+
+	Describe("SavePlan", func() {
+		BeforeEach(func() {
+			body = `{"slots": [{"day": 4, "meal": "dinner", "recipeId": 3}, {"day": 0, "meal": "lunch", "recipeId": 5}]}`
+		})
+
+		JustBeforeEach(func() {
+			send(http.MethodPut, "/plans/"+week, map[string]string{"week": week}, httpHandlers.SavePlan)
+		})
+
+		When("I'm logged out", func() {
+			BeforeEach(func() {
+				sessionManager.GetReturns(&session.AuthInfo{IsLoggedIn: false}, nil)
+			})
+
+			It("returns a status not auth'ed", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusUnauthorized))
+			})
+		})
+
+		It("saves the slots in order in a unit of work", func() {
+			Expect(recorder.Result().StatusCode).To(Equal(http.StatusOK))
+			Expect(transactor.InTxCallCount()).To(Equal(1))
+			_, plan := planStore.SaveArgsForCall(0)
+			Expect(plan).To(Equal(models.Plan{UserID: 234, Week: week, Slots: []models.Slot{
+				{Day: 0, Meal: models.Lunch, RecipeID: 5},
+				{Day: 4, Meal: models.Dinner, RecipeID: 3},
+			}}))
+		})
+
+		It("tells my other clients the plan and its shopping list changed", func() {
+			Expect(published()).To(Equal([]models.Event{
+				{Type: models.PlanChanged, Week: week},
+				{Type: models.ShoppingListChanged, Week: week},
+			}))
+		})
+
+		It("checks the recipes in one batch", func() {
+			Expect(recipeStore.GetManyCallCount()).To(Equal(1))
+			_, userID, ids := recipeStore.GetManyArgsForCall(0)
+			Expect(userID).To(Equal(234))
+			Expect(ids).To(Equal([]int{3, 5}))
+		})
+
+		It("returns the saved plan with its nutrition totals", func() {
+			Expect(recorder.Body.String()).To(ContainSubstring(`"week":{"calories":800,"protein":0.2,"fat":0,"carbohydrate":0}`))
+		})
+
+		When("a recipe isn't the user's", func() {
+			BeforeEach(func() {
+				body = `{"slots": [{"day": 4, "meal": "dinner", "recipeId": 3}, {"day": 0, "meal": "lunch", "recipeId": 500}]}`
+			})
+
+			It("returns an unprocessable entity status naming the slot", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusUnprocessableEntity))
+				Expect(recorder.Body.String()).To(ContainSubstring(`slots[1].recipeId`))
+				Expect(planStore.SaveCallCount()).To(Equal(0))
+			})
+		})
+
+		When("some slots eat the leftovers of another", func() {
+			BeforeEach(func() {
+				recipeStore.GetManyStub = func(_ context.Context, _ int, ids []int) ([]models.Recipe, error) {
+					res := []models.Recipe{}
+					for _, id := range ids {
+						res = append(res, models.Recipe{ID: id, Servings: 4})
+					}
+					return res, nil
+				}
+				body = `{"slots": [
+					{"day": 2, "meal": "lunch", "leftoverOf": {"day": 0, "meal": "dinner"}},
+					{"day": 0, "meal": "dinner", "recipeId": 3, "portions": 2},
+					{"day": 1, "meal": "lunch", "leftoverOf": {"day": 0, "meal": "dinner"}}
+				]}`
+			})
+
+			It("saves them with the recipe of the slot they are leftovers of", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusOK))
+				_, plan := planStore.SaveArgsForCall(0)
+				leftoverOf := &models.SlotRef{Day: 0, Meal: models.Dinner}
+				Expect(plan.Slots).To(Equal([]models.Slot{
+					{Day: 0, Meal: models.Dinner, RecipeID: 3, Portions: 2},
+					{Day: 1, Meal: models.Lunch, RecipeID: 3, LeftoverOf: leftoverOf},
+					{Day: 2, Meal: models.Lunch, RecipeID: 3, LeftoverOf: leftoverOf},
+				}))
+			})
+
+			It("returns the portions left and costs the recipe once across its slots", func() {
+				Expect(recorder.Body.String()).To(ContainSubstring(`"leftovers":[{"day":0,"meal":"dinner","recipeId":3,"cooked":4,"remaining":0}]`))
+				Expect(recorder.Body.String()).To(ContainSubstring(`"cost":{"total":300}`))
+			})
+
+			When("they eat more portions than are left", func() {
+				BeforeEach(func() {
+					body = `{"slots": [
+						{"day": 2, "meal": "lunch", "leftoverOf": {"day": 0, "meal": "dinner"}},
+						{"day": 0, "meal": "dinner", "recipeId": 3, "portions": 2},
+						{"day": 1, "meal": "lunch", "leftoverOf": {"day": 0, "meal": "dinner"}, "portions": 2}
+					]}`
+				})
+
+				It("returns an unprocessable entity status naming the slot that runs out", func() {
+					Expect(recorder.Result().StatusCode).To(Equal(http.StatusUnprocessableEntity))
+					Expect(recorder.Body.String()).To(ContainSubstring(`"field":"slots[0].portions","message":"must be at most the 0 portions left"`))
+					Expect(planStore.SaveCallCount()).To(Equal(0))
+				})
+			})
+		})
+
+		When("the slots are invalid", func() {
+			It("returns an unprocessable entity status", func() {
+				for _, b := range []string{
+					`{"slots": [{"day": 7, "meal": "dinner", "recipeId": 3}]}`,
+					`{"slots": [{"day": 1, "meal": "supper", "recipeId": 3}]}`,
+					`{"slots": [{"day": 1, "meal": "dinner"}]}`,
+					`{"slots": [{"day": 1, "meal": "dinner", "recipeId": 3}, {"day": 1, "meal": "dinner", "recipeId": 4}]}`,
+					`{"slots": [{"day": 1, "meal": "dinner", "recipeId": 3, "portions": -1}]}`,
+					`{"slots": [{"day": 1, "meal": "lunch", "leftoverOf": {"day": 0, "meal": "dinner"}}]}`,
+					`{"slots": [{"day": 1, "meal": "dinner", "recipeId": 3}, {"day": 1, "meal": "lunch", "leftoverOf": {"day": 1, "meal": "dinner"}}]}`,
+					`{"slots": [{"day": 0, "meal": "dinner", "recipeId": 3}, {"day": 1, "meal": "lunch", "recipeId": 4, "leftoverOf": {"day": 0, "meal": "dinner"}}]}`,
+				} {
+					recorder = httptest.NewRecorder()
+					body = b
+					send(http.MethodPut, "/plans/"+week, map[string]string{"week": week}, httpHandlers.SavePlan)
+					Expect(recorder.Result().StatusCode).To(Equal(http.StatusUnprocessableEntity), b)
+				}
+			})
+		})
+
+		When("the store fails", func() {
+			BeforeEach(func() {
+				planStore.SaveReturns(errors.New("boom"))
+			})
+
+			It("returns an internal server error and tells no one", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusInternalServerError))
+				Expect(publisher.PublishCallCount()).To(BeZero())
+			})
+		})
+	})
+
+	Describe("ApplyTemplate", func() {
+		BeforeEach(func() {
+			body = `{"templateId": 8}`
+			templateStore.GetTemplateReturns(models.PlanTemplate{ID: 8, Name: "Usual", Slots: []models.Slot{
+				{Day: 0, Meal: models.Dinner, RecipeID: 3},
+				{Day: 1, Meal: models.Dinner, RecipeID: 4},
+				{Day: 2, Meal: models.Dinner, RecipeID: 5},
+			}}, nil)
+			planStore.GetReturns(models.Plan{UserID: 234, Week: week, Slots: []models.Slot{
+				{Day: 1, Meal: models.Dinner, RecipeID: 4},
+				{Day: 2, Meal: models.Dinner, RecipeID: 6},
+			}}, nil)
+		})
+
+		JustBeforeEach(func() {
+			send(http.MethodPost, "/plans/"+week+"/template", map[string]string{"week": week}, httpHandlers.ApplyTemplate)
+		})
+
+		When("I'm logged out", func() {
+			BeforeEach(func() {
+				sessionManager.GetReturns(&session.AuthInfo{IsLoggedIn: false}, nil)
+			})
+
+			It("returns a status not auth'ed", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusUnauthorized))
+			})
+		})
+
+		It("fills the empty meals and reports the ones planned with something else", func() {
+			Expect(recorder.Result().StatusCode).To(Equal(http.StatusOK))
+			_, userID, id := templateStore.GetTemplateArgsForCall(0)
+			Expect(userID).To(Equal(234))
+			Expect(id).To(Equal(8))
+
+			_, plan := planStore.SaveArgsForCall(0)
+			Expect(plan.Slots).To(Equal([]models.Slot{
+				{Day: 0, Meal: models.Dinner, RecipeID: 3},
+				{Day: 1, Meal: models.Dinner, RecipeID: 4},
+				{Day: 2, Meal: models.Dinner, RecipeID: 6},
+			}))
+			Expect(recorder.Body.String()).To(ContainSubstring(`"slots":[` +
+				`{"day":0,"meal":"dinner","recipeId":3},` +
+				`{"day":1,"meal":"dinner","recipeId":4},` +
+				`{"day":2,"meal":"dinner","recipeId":6}]`))
+			Expect(recorder.Body.String()).To(ContainSubstring(`"conflicts":[{"day":2,"meal":"dinner","recipeId":5,"plannedRecipeId":6}]`))
+		})
+
+		It("returns the filled plan with its nutrition totals and cost, like GetPlan", func() {
+			_, _, ids := recipeStore.GetManyArgsForCall(0)
+			Expect(ids).To(Equal([]int{3, 4, 6}))
+			Expect(recorder.Body.String()).To(ContainSubstring(`"week":{"calories":1300,"protein":0.3,"fat":0,"carbohydrate":0}`))
+			Expect(recorder.Body.String()).To(ContainSubstring(`"cost":{"total":1300}`))
+		})
+
+		It("tells my other clients the plan and its shopping list changed", func() {
+			Expect(published()).To(Equal([]models.Event{
+				{Type: models.PlanChanged, Week: week},
+				{Type: models.ShoppingListChanged, Week: week},
+			}))
+		})
+
+		When("there is no template id", func() {
+			BeforeEach(func() {
+				body = `{}`
+			})
+
+			It("returns an unprocessable entity status", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusUnprocessableEntity))
+			})
+		})
+
+		When("the template isn't the user's", func() {
+			BeforeEach(func() {
+				templateStore.GetTemplateReturns(models.PlanTemplate{}, db.NotFoundErr())
+			})
+
+			It("returns a not found status", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusNotFound))
+				Expect(planStore.SaveCallCount()).To(Equal(0))
+			})
+		})
+
+		When("the store fails", func() {
+			BeforeEach(func() {
+				planStore.SaveReturns(errors.New("boom"))
+			})
+
+			It("returns an internal server error and tells no one", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusInternalServerError))
+				Expect(publisher.PublishCallCount()).To(BeZero())
+			})
+		})
+	})
+
+	Describe("ApplyRules", func() {
+		BeforeEach(func() {
+			templateStore.ListRulesReturns([]models.PlanRule{
+				{ID: 1, Slot: models.Slot{Day: 4, Meal: models.Dinner, RecipeID: 3}},
+				{ID: 2, Slot: models.Slot{Day: 6, Meal: models.Lunch, RecipeID: 4}},
+			}, nil)
+			planStore.GetReturns(models.Plan{UserID: 234, Week: week, Slots: []models.Slot{
+				{Day: 6, Meal: models.Lunch, RecipeID: 9},
+			}}, nil)
+		})
+
+		JustBeforeEach(func() {
+			send(http.MethodPost, "/plans/"+week+"/rules", map[string]string{"week": week}, httpHandlers.ApplyRules)
+		})
+
+		It("materialises the rules into the week", func() {
+			Expect(recorder.Result().StatusCode).To(Equal(http.StatusOK))
+			_, plan := planStore.SaveArgsForCall(0)
+			Expect(plan.Slots).To(Equal([]models.Slot{
+				{Day: 4, Meal: models.Dinner, RecipeID: 3},
+				{Day: 6, Meal: models.Lunch, RecipeID: 9},
+			}))
+			Expect(recorder.Body.String()).To(ContainSubstring(`"conflicts":[{"day":6,"meal":"lunch","recipeId":4,"plannedRecipeId":9}]`))
+		})
+
+		When("the week isn't a Monday", func() {
+			BeforeEach(func() {
+				week = "2026-10-21"
+			})
+
+			It("returns a bad request status", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusBadRequest))
+			})
+		})
+	})
+
+	Describe("NewPlanTemplate", func() {
+		BeforeEach(func() {
+			body = `{"name": " Usual ", "slots": [{"day": 4, "meal": "dinner", "recipeId": 3}]}`
+			templateStore.InsertTemplateStub = func(_ context.Context, t models.PlanTemplate) (models.PlanTemplate, error) {
+				t.ID = 8
+				return t, nil
+			}
+		})
+
+		JustBeforeEach(func() {
+			send(http.MethodPost, "/plan-templates", nil, httpHandlers.NewPlanTemplate)
+		})
+
+		When("I'm logged out", func() {
+			BeforeEach(func() {
+				sessionManager.GetReturns(&session.AuthInfo{IsLoggedIn: false}, nil)
+			})
+
+			It("returns a status not auth'ed", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusUnauthorized))
+			})
+		})
+
+		It("saves the template for the user", func() {
+			Expect(recorder.Result().StatusCode).To(Equal(http.StatusCreated))
+			_, template := templateStore.InsertTemplateArgsForCall(0)
+			Expect(template).To(Equal(models.PlanTemplate{UserID: 234, Name: "Usual", Slots: []models.Slot{{Day: 4, Meal: models.Dinner, RecipeID: 3}}}))
+			Expect(recorder.Body.String()).To(MatchJSON(`{"id": 8, "name": "Usual", "slots": [{"day": 4, "meal": "dinner", "recipeId": 3}]}`))
+			Expect(published()).To(Equal([]models.Event{{Type: models.PlanTemplatesChanged}}))
+		})
+
+		When("there is no name", func() {
+			BeforeEach(func() {
+				body = `{"name": "  "}`
+			})
+
+			It("returns an unprocessable entity status", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusUnprocessableEntity))
+			})
+		})
+
+		When("a slot is a leftover", func() {
+			BeforeEach(func() {
+				body = `{"name": "Usual", "slots": [{"day": 0, "meal": "dinner", "recipeId": 3}, {"day": 1, "meal": "lunch", "leftoverOf": {"day": 0, "meal": "dinner"}}]}`
+			})
+
+			It("returns an unprocessable entity status", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusUnprocessableEntity))
+				Expect(recorder.Body.String()).To(ContainSubstring(`"field":"slots[1].leftoverOf"`))
+			})
+		})
+
+		When("the user has a template with the name", func() {
+			BeforeEach(func() {
+				templateStore.InsertTemplateReturns(models.PlanTemplate{}, errors.New("taken"))
+				templateStore.InsertTemplateStub = nil
+				templateStore.IsDuplicateErrReturns(true)
+			})
+
+			It("returns a conflict status", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusConflict))
+				Expect(recorder.Body.String()).To(ContainSubstring(`duplicate-name`))
+			})
+		})
+	})
+
+	Describe("ListPlanTemplates", func() {
+		BeforeEach(func() {
+			templateStore.ListTemplatesReturns([]models.PlanTemplate{{ID: 8, Name: "Usual", Slots: []models.Slot{}}}, nil)
+		})
+
+		JustBeforeEach(func() {
+			send(http.MethodGet, "/plan-templates", nil, httpHandlers.ListPlanTemplates)
+		})
+
+		It("returns the user's templates", func() {
+			_, userID := templateStore.ListTemplatesArgsForCall(0)
+			Expect(userID).To(Equal(234))
+			Expect(recorder.Body.String()).To(MatchJSON(`[{"id": 8, "name": "Usual", "slots": []}]`))
+		})
+	})
+
+	Describe("DeletePlanTemplate", func() {
+		JustBeforeEach(func() {
+			send(http.MethodDelete, "/plan-templates/8", map[string]string{"id": "8"}, httpHandlers.DeletePlanTemplate)
+		})
+
+		It("deletes the user's template", func() {
+			Expect(recorder.Result().StatusCode).To(Equal(http.StatusNoContent))
+			_, userID, id := templateStore.DeleteTemplateArgsForCall(0)
+			Expect(userID).To(Equal(234))
+			Expect(id).To(Equal(8))
+			Expect(published()).To(Equal([]models.Event{{Type: models.PlanTemplatesChanged}}))
+		})
+
+		When("it isn't the user's", func() {
+			BeforeEach(func() {
+				templateStore.DeleteTemplateReturns(db.NotFoundErr())
+			})
+
+			It("returns a not found status", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusNotFound))
+				Expect(publisher.PublishCallCount()).To(BeZero())
+			})
+		})
+	})
+
+	Describe("SavePlanRule", func() {
+		BeforeEach(func() {
+			body = `{"day": 4, "meal": "dinner", "recipeId": 3}`
+			templateStore.SaveRuleStub = func(_ context.Context, rule models.PlanRule) (models.PlanRule, error) {
+				rule.ID = 2
+				return rule, nil
+			}
+		})
+
+		JustBeforeEach(func() {
+			send(http.MethodPut, "/plan-rules", nil, httpHandlers.SavePlanRule)
+		})
+
+		It("saves the rule for the user", func() {
+			Expect(recorder.Result().StatusCode).To(Equal(http.StatusOK))
+			_, rule := templateStore.SaveRuleArgsForCall(0)
+			Expect(rule).To(Equal(models.PlanRule{UserID: 234, Slot: models.Slot{Day: 4, Meal: models.Dinner, RecipeID: 3}}))
+			Expect(recorder.Body.String()).To(MatchJSON(`{"id": 2, "day": 4, "meal": "dinner", "recipeId": 3}`))
+			Expect(published()).To(Equal([]models.Event{{Type: models.PlanTemplatesChanged}}))
+		})
+
+		When("the recipe isn't the user's", func() {
+			BeforeEach(func() {
+				body = `{"day": 4, "meal": "dinner", "recipeId": 300}`
+			})
+
+			It("returns an unprocessable entity status", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusUnprocessableEntity))
+				Expect(recorder.Body.String()).To(ContainSubstring(`"recipeId"`))
+			})
+		})
+
+		When("the rule is for a leftover", func() {
+			BeforeEach(func() {
+				body = `{"day": 4, "meal": "lunch", "leftoverOf": {"day": 3, "meal": "dinner"}}`
+			})
+
+			It("returns an unprocessable entity status", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusUnprocessableEntity))
+				Expect(recorder.Body.String()).To(ContainSubstring(`"field":"leftoverOf"`))
+			})
+		})
+	})
+
+	Describe("DeletePlanRule", func() {
+		JustBeforeEach(func() {
+			send(http.MethodDelete, "/plan-rules/2", map[string]string{"id": "2"}, httpHandlers.DeletePlanRule)
+		})
+
+		It("deletes the user's rule", func() {
+			Expect(recorder.Result().StatusCode).To(Equal(http.StatusNoContent))
+			_, userID, id := templateStore.DeleteRuleArgsForCall(0)
+			Expect(userID).To(Equal(234))
+			Expect(id).To(Equal(2))
+			Expect(published()).To(Equal([]models.Event{{Type: models.PlanTemplatesChanged}}))
+		})
+
+		When("it isn't the user's", func() {
+			BeforeEach(func() {
+				templateStore.DeleteRuleReturns(db.NotFoundErr())
+			})
+
+			It("returns a not found status", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusNotFound))
+				Expect(publisher.PublishCallCount()).To(BeZero())
+			})
+		})
+	})
+})
@@ -0,0 +1,201 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/kieron-pivotal/menu-planner-app/models"
+	"github.com/kieron-pivotal/menu-planner-app/problem"
+)
+
+//counterfeiter:generate . PriceStore
+
+type PriceStore interface {
+	IsNotFoundErr(error) bool
+	List(ctx context.Context, userID int) ([]models.Price, error)
+	Save(ctx context.Context, price models.Price) (models.Price, error)
+	Delete(ctx context.Context, userID, id int) error
+}
+
+//counterfeiter:generate . BudgetStore
+
+type BudgetStore interface {
+	Get(ctx context.Context, userID int) (models.Budget, error)
+	Save(ctx context.Context, budget models.Budget) error
+}
+
+type PriceHandler struct {
+	sessionManager SessionManager
+	priceStore     PriceStore
+	budgetStore    BudgetStore
+}
+
+func NewPriceHandler(sessionManager SessionManager, priceStore PriceStore, budgetStore BudgetStore) *PriceHandler {
+	return &PriceHandler{
+		sessionManager: sessionManager,
+		priceStore:     priceStore,
+		budgetStore:    budgetStore,
+	}
+}
+
+func (h *PriceHandler) ListPrices(w http.ResponseWriter, r *http.Request) {
+	sess, err := h.sessionManager.Get(r.Context())
+	if err != nil || sess == nil || !sess.IsLoggedIn {
+		problem.Write(w, http.StatusUnauthorized, problem.Unauthorized, "")
+
+		return
+	}
+
+	prices, err := h.priceStore.List(r.Context(), sess.ID)
+	if err != nil {
+		log.Printf("price-list: %v\n", err)
+		problem.Write(w, http.StatusInternalServerError, problem.Internal, "")
+
+		return
+	}
+
+	w.Header().Add("Content-Type", "application/json")
+
+	if err = json.NewEncoder(w).Encode(prices); err != nil {
+		problem.Write(w, http.StatusInternalServerError, problem.Internal, "json encoding failure")
+
+		return
+	}
+}
+
+// SavePrice records what an ingredient costs at a store, replacing the
+// previous price. Prices without a quantity are for one item.
+func (h *PriceHandler) SavePrice(w http.ResponseWriter, r *http.Request) {
+	sess, err := h.sessionManager.Get(r.Context())
+	if err != nil || sess == nil || !sess.IsLoggedIn {
+		problem.Write(w, http.StatusUnauthorized, problem.Unauthorized, "")
+
+		return
+	}
+
+	price := models.Price{}
+	if !decodeJSON(w, r, &price, maxBodyBytes) {
+		return
+	}
+
+	price.Ingredient = strings.TrimSpace(price.Ingredient)
+	price.Store = strings.TrimSpace(price.Store)
+	if price.Quantity == 0 {
+		price.Quantity = 1
+	}
+	if errs := validatePrice(price); len(errs) > 0 {
+		problem.WriteInvalid(w, errs)
+
+		return
+	}
+
+	price.UserID = sess.ID
+
+	price, err = h.priceStore.Save(r.Context(), price)
+	if err != nil {
+		log.Printf("price-save: %v\n", err)
+		problem.Write(w, http.StatusBadRequest, problem.InvalidRequest, "price could not be saved")
+
+		return
+	}
+
+	w.Header().Add("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(price)
+}
+
+func (h *PriceHandler) DeletePrice(w http.ResponseWriter, r *http.Request) {
+	sess, err := h.sessionManager.Get(r.Context())
+	if err != nil || sess == nil || !sess.IsLoggedIn {
+		problem.Write(w, http.StatusUnauthorized, problem.Unauthorized, "")
+
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		problem.Write(w, http.StatusNotFound, problem.NotFound, "")
+
+		return
+	}
+
+	if err = h.priceStore.Delete(r.Context(), sess.ID, id); err != nil {
+		if h.priceStore.IsNotFoundErr(err) {
+			problem.Write(w, http.StatusNotFound, problem.NotFound, "")
+
+			return
+		}
+
+		log.Printf("price-delete: %v\n", err)
+		problem.Write(w, http.StatusInternalServerError, problem.Internal, "")
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetBudget returns what the user means to spend on food each week, which
+// is 0 if they haven't said
+func (h *PriceHandler) GetBudget(w http.ResponseWriter, r *http.Request) {
+	sess, err := h.sessionManager.Get(r.Context())
+	if err != nil || sess == nil || !sess.IsLoggedIn {
+		problem.Write(w, http.StatusUnauthorized, problem.Unauthorized, "")
+
+		return
+	}
+
+	budget, err := h.budgetStore.Get(r.Context(), sess.ID)
+	if err != nil {
+		log.Printf("budget-get: %v\n", err)
+		problem.Write(w, http.StatusInternalServerError, problem.Internal, "")
+
+		return
+	}
+
+	w.Header().Add("Content-Type", "application/json")
+
+	if err = json.NewEncoder(w).Encode(budget); err != nil {
+		problem.Write(w, http.StatusInternalServerError, problem.Internal, "json encoding failure")
+
+		return
+	}
+}
+
+// SaveBudget sets what the user means to spend on food each week. A budget
+// of 0 clears it.
+func (h *PriceHandler) SaveBudget(w http.ResponseWriter, r *http.Request) {
+	sess, err := h.sessionManager.Get(r.Context())
+	if err != nil || sess == nil || !sess.IsLoggedIn {
+		problem.Write(w, http.StatusUnauthorized, problem.Unauthorized, "")
+
+		return
+	}
+
+	budget := models.Budget{}
+	if !decodeJSON(w, r, &budget, maxBodyBytes) {
+		return
+	}
+
+	if errs := validateBudget(budget); len(errs) > 0 {
+		problem.WriteInvalid(w, errs)
+
+		return
+	}
+
+	budget.UserID = sess.ID
+
+	if err = h.budgetStore.Save(r.Context(), budget); err != nil {
+		log.Printf("budget-save: %v\n", err)
+		problem.Write(w, http.StatusInternalServerError, problem.Internal, "")
+
+		return
+	}
+
+	w.Header().Add("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(budget)
+}
@@ -0,0 +1,269 @@
+package handlers_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/kieron-pivotal/menu-planner-app/db"
+	"github.com/kieron-pivotal/menu-planner-app/handlers"
+	"github.com/kieron-pivotal/menu-planner-app/handlers/handlersfakes"
+	"github.com/kieron-pivotal/menu-planner-app/models"
+	"github.com/kieron-pivotal/menu-planner-app/session"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PriceHandler", func() {
+	var (
+		sessionManager *handlersfakes.FakeSessionManager
+		priceStore     *handlersfakes.FakePriceStore
+		budgetStore    *handlersfakes.FakeBudgetStore
+		recorder       *httptest.ResponseRecorder
+		req            *http.Request
+		httpHandlers   *handlers.PriceHandler
+	)
+
+	BeforeEach(func() {
+		sessionManager = new(handlersfakes.FakeSessionManager)
+		sessionManager.GetReturns(&session.AuthInfo{IsLoggedIn: true, Name: "forest", ID: 234}, nil)
+		priceStore = new(handlersfakes.FakePriceStore)
+		priceStore.IsNotFoundErrStub = func(err error) bool { return err == db.NotFoundErr() }
+		budgetStore = new(handlersfakes.FakeBudgetStore)
+		httpHandlers = handlers.NewPriceHandler(sessionManager, priceStore, budgetStore)
+		recorder = httptest.NewRecorder()
+	})
+
+	Describe("ListPrices", func() {
+		BeforeEach(func() {
+			priceStore.ListReturns([]models.Price{{ID: 1, Ingredient: "eggs", Store: "market", Price: 180, Quantity: 6}}, nil)
+		})
+
+		JustBeforeEach(func() {
+			var err error
+			req, err = http.NewRequest(http.MethodGet, "/prices", nil)
+			Expect(err).NotTo(HaveOccurred())
+			httpHandlers.ListPrices(recorder, req)
+		})
+
+		When("I'm logged out", func() {
+			BeforeEach(func() {
+				sessionManager.GetReturns(&session.AuthInfo{IsLoggedIn: false}, nil)
+			})
+
+			It("returns a status not auth'ed", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusUnauthorized))
+			})
+		})
+
+		It("returns the user's prices as JSON", func() {
+			_, userID := priceStore.ListArgsForCall(0)
+			Expect(userID).To(Equal(234))
+			Expect(recorder.Header().Get("Content-Type")).To(Equal("application/json"))
+			Expect(recorder.Body.String()).To(MatchJSON(`[{"id": 1, "ingredient": "eggs", "store": "market", "price": 180, "quantity": 6}]`))
+		})
+
+		When("the store fails", func() {
+			BeforeEach(func() {
+				priceStore.ListReturns(nil, errors.New("boom"))
+			})
+
+			It("returns an internal server error", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusInternalServerError))
+			})
+		})
+	})
+
+	Describe("SavePrice", func() {
+		var body string
+
+		BeforeEach(func() {
+			body = `{"ingredient": " eggs ", "store": "market", "price": 180, "quantity": 6}`
+			priceStore.SaveStub = func(_ context.Context, p models.Price) (models.Price, error) {
+				p.ID = 7
+				return p, nil
+			}
+		})
+
+		JustBeforeEach(func() {
+			var err error
+			req, err = http.NewRequest(http.MethodPut, "/prices", strings.NewReader(body))
+			Expect(err).NotTo(HaveOccurred())
+			httpHandlers.SavePrice(recorder, req)
+		})
+
+		When("I'm logged out", func() {
+			BeforeEach(func() {
+				sessionManager.GetReturns(&session.AuthInfo{IsLoggedIn: false}, nil)
+			})
+
+			It("returns a status not auth'ed", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusUnauthorized))
+			})
+		})
+
+		It("saves the price for the user", func() {
+			Expect(recorder.Result().StatusCode).To(Equal(http.StatusOK))
+			_, price := priceStore.SaveArgsForCall(0)
+			Expect(price).To(Equal(models.Price{UserID: 234, Ingredient: "eggs", Store: "market", Price: 180, Quantity: 6}))
+			Expect(recorder.Body.String()).To(ContainSubstring(`"id":7`))
+		})
+
+		When("there is no quantity", func() {
+			BeforeEach(func() {
+				body = `{"ingredient": "cabbage", "price": 90}`
+			})
+
+			It("prices one item", func() {
+				_, price := priceStore.SaveArgsForCall(0)
+				Expect(price.Quantity).To(Equal(1.0))
+			})
+		})
+
+		When("the price is invalid", func() {
+			It("returns an unprocessable entity status", func() {
+				for _, b := range []string{`{"price": 90}`, `{"ingredient": "cabbage", "price": -1}`, `{"ingredient": "cabbage", "pence": 90}`} {
+					recorder = httptest.NewRecorder()
+					req, _ = http.NewRequest(http.MethodPut, "/prices", strings.NewReader(b))
+					httpHandlers.SavePrice(recorder, req)
+					Expect(recorder.Result().StatusCode).To(Equal(http.StatusUnprocessableEntity), b)
+				}
+			})
+		})
+
+		When("the body isn't JSON", func() {
+			BeforeEach(func() {
+				body = `not json`
+			})
+
+			It("returns a bad request status", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusBadRequest))
+				Expect(priceStore.SaveCallCount()).To(BeZero())
+			})
+		})
+	})
+
+	Describe("DeletePrice", func() {
+		JustBeforeEach(func() {
+			var err error
+			req, err = http.NewRequest(http.MethodDelete, "/prices/7", nil)
+			Expect(err).NotTo(HaveOccurred())
+			req = mux.SetURLVars(req, map[string]string{"id": "7"})
+			httpHandlers.DeletePrice(recorder, req)
+		})
+
+		It("deletes the user's price", func() {
+			Expect(recorder.Result().StatusCode).To(Equal(http.StatusNoContent))
+			_, userID, id := priceStore.DeleteArgsForCall(0)
+			Expect(userID).To(Equal(234))
+			Expect(id).To(Equal(7))
+		})
+
+		When("the price doesn't exist", func() {
+			BeforeEach(func() {
+				priceStore.DeleteReturns(db.NotFoundErr())
+			})
+
+			It("returns not found", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusNotFound))
+			})
+		})
+	})
+
+	Describe("GetBudget", func() {
+		BeforeEach(func() {
+			budgetStore.GetReturns(models.Budget{UserID: 234, Weekly: 6000}, nil)
+		})
+
+		JustBeforeEach(func() {
+			var err error
+			req, err = http.NewRequest(http.MethodGet, "/profile/budget", nil)
+			Expect(err).NotTo(HaveOccurred())
+			httpHandlers.GetBudget(recorder, req)
+		})
+
+		When("I'm logged out", func() {
+			BeforeEach(func() {
+				sessionManager.GetReturns(&session.AuthInfo{IsLoggedIn: false}, nil)
+			})
+
+			It("returns a status not auth'ed", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusUnauthorized))
+			})
+		})
+
+		It("returns the user's weekly budget", func() {
+			_, userID := budgetStore.GetArgsForCall(0)
+			Expect(userID).To(Equal(234))
+			Expect(recorder.Header().Get("Content-Type")).To(Equal("application/json"))
+			Expect(recorder.Body.String()).To(MatchJSON(`{"weekly": 6000}`))
+		})
+
+		When("the store fails", func() {
+			BeforeEach(func() {
+				budgetStore.GetReturns(models.Budget{}, errors.New("boom"))
+			})
+
+			It("returns an internal server error", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusInternalServerError))
+			})
+		})
+	})
+
+	Describe("SaveBudget", func() {
+		var body string
+
+		BeforeEach(func() {
+			body = `{"weekly": 6000}`
+		})
+
+		JustBeforeEach(func() {
+			var err error
+			req, err = http.NewRequest(http.MethodPut, "/profile/budget", strings.NewReader(body))
+			Expect(err).NotTo(HaveOccurred())
+			httpHandlers.SaveBudget(recorder, req)
+		})
+
+		When("I'm logged out", func() {
+			BeforeEach(func() {
+				sessionManager.GetReturns(&session.AuthInfo{IsLoggedIn: false}, nil)
+			})
+
+			It("returns a status not auth'ed", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusUnauthorized))
+				Expect(budgetStore.SaveCallCount()).To(BeZero())
+			})
+		})
+
+		It("saves the budget for the user", func() {
+			Expect(recorder.Result().StatusCode).To(Equal(http.StatusOK))
+			_, budget := budgetStore.SaveArgsForCall(0)
+			Expect(budget).To(Equal(models.Budget{UserID: 234, Weekly: 6000}))
+			Expect(recorder.Body.String()).To(MatchJSON(`{"weekly": 6000}`))
+		})
+
+		When("the budget is negative", func() {
+			BeforeEach(func() {
+				body = `{"weekly": -1}`
+			})
+
+			It("returns an unprocessable entity status", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusUnprocessableEntity))
+				Expect(budgetStore.SaveCallCount()).To(BeZero())
+			})
+		})
+
+		When("the store fails", func() {
+			BeforeEach(func() {
+				budgetStore.SaveReturns(errors.New("boom"))
+			})
+
+			It("returns an internal server error", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusInternalServerError))
+			})
+		})
+	})
+})
@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/gorilla/mux"
+	"github.com/kieron-pivotal/menu-planner-app/models"
+	"github.com/kieron-pivotal/menu-planner-app/printout"
+	"github.com/kieron-pivotal/menu-planner-app/problem"
+)
+
+const defaultPlanTitle = "Meal plan"
+
+type PrintHandler struct {
+	sessionManager SessionManager
+	planStore      PlanStore
+	recipeStore    RecipeStore
+	layout         printout.Layout
+}
+
+func NewPrintHandler(sessionManager SessionManager, planStore PlanStore, recipeStore RecipeStore, layout printout.Layout) *PrintHandler {
+	return &PrintHandler{
+		sessionManager: sessionManager,
+		planStore:      planStore,
+		recipeStore:    recipeStore,
+		layout:         layout,
+	}
+}
+
+// RecipeCard serves a recipe as a PDF to print
+func (h *PrintHandler) RecipeCard(w http.ResponseWriter, r *http.Request) {
+	sess, err := h.sessionManager.Get(r.Context())
+	if err != nil || sess == nil || !sess.IsLoggedIn {
+		problem.Write(w, http.StatusUnauthorized, problem.Unauthorized, "")
+
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		problem.Write(w, http.StatusNotFound, problem.NotFound, "")
+
+		return
+	}
+
+	recipe, err := h.recipeStore.Get(r.Context(), sess.ID, id)
+	if err != nil {
+		if h.recipeStore.IsNotFoundErr(err) {
+			problem.Write(w, http.StatusNotFound, problem.NotFound, "")
+
+			return
+		}
+
+		log.Printf("recipe-get: %v\n", err)
+		problem.Write(w, http.StatusInternalServerError, problem.Internal, "")
+
+		return
+	}
+
+	buf := new(bytes.Buffer)
+	if err = printout.Recipe(buf, h.layout, recipe); err != nil {
+		log.Printf("recipe-print: %v\n", err)
+		problem.Write(w, http.StatusInternalServerError, problem.Internal, "")
+
+		return
+	}
+
+	writePDF(w, recipe.Name, buf)
+}
+
+// PlanPrintout serves the user's plan for the week as a PDF: a grid of
+// each day's meals, then a card for each recipe it cooks
+func (h *PrintHandler) PlanPrintout(w http.ResponseWriter, r *http.Request) {
+	sess, err := h.sessionManager.Get(r.Context())
+	if err != nil || sess == nil || !sess.IsLoggedIn {
+		problem.Write(w, http.StatusUnauthorized, problem.Unauthorized, "")
+
+		return
+	}
+
+	week, ok := planWeek(w, r)
+	if !ok {
+		return
+	}
+
+	plan, err := h.planStore.Get(r.Context(), sess.ID, week)
+	if err != nil {
+		log.Printf("plan-get: %v\n", err)
+		problem.Write(w, http.StatusInternalServerError, problem.Internal, "")
+
+		return
+	}
+
+	recipes := []models.Recipe{}
+	if len(plan.Slots) > 0 {
+		recipes, err = h.recipeStore.GetMany(r.Context(), sess.ID, slotRecipes(plan.Slots))
+		if err != nil {
+			log.Printf("recipe-get-many: %v\n", err)
+			problem.Write(w, http.StatusInternalServerError, problem.Internal, "")
+
+			return
+		}
+	}
+
+	date, _ := time.Parse(weekFormat, week)
+	title := defaultPlanTitle + ", week of " + date.Format("2 January 2006")
+
+	buf := new(bytes.Buffer)
+	if err = printout.Plan(buf, h.layout, title, plan, recipes); err != nil {
+		log.Printf("plan-print: %v\n", err)
+		problem.Write(w, http.StatusInternalServerError, problem.Internal, "")
+
+		return
+	}
+
+	writePDF(w, title, buf)
+}
+
+// RecipesPrintout serves the recipes listed in the recipes query parameter
+// as a PDF, with a first page listing them under the title parameter. It
+// prints meals chosen without a week's plan.
+func (h *PrintHandler) RecipesPrintout(w http.ResponseWriter, r *http.Request) {
+	sess, err := h.sessionManager.Get(r.Context())
+	if err != nil || sess == nil || !sess.IsLoggedIn {
+		problem.Write(w, http.StatusUnauthorized, problem.Unauthorized, "")
+
+		return
+	}
+
+	recipes, ok := queryRecipes(w, r, h.recipeStore, sess.ID)
+	if !ok {
+		return
+	}
+
+	title := strings.TrimSpace(r.URL.Query().Get("title"))
+	if title == "" {
+		title = defaultPlanTitle
+	}
+
+	buf := new(bytes.Buffer)
+	if err = printout.Recipes(buf, h.layout, title, recipes); err != nil {
+		log.Printf("recipes-print: %v\n", err)
+		problem.Write(w, http.StatusInternalServerError, problem.Internal, "")
+
+		return
+	}
+
+	writePDF(w, title, buf)
+}
+
+// writePDF writes a PDF for the browser to show, naming it after title if
+// it is saved
+func writePDF(w http.ResponseWriter, title string, pdf *bytes.Buffer) {
+	w.Header().Add("Content-Type", "application/pdf")
+	w.Header().Add("Content-Disposition", fmt.Sprintf(`inline; filename="%s.pdf"`, slug(title)))
+
+	if _, err := pdf.WriteTo(w); err != nil {
+		log.Printf("pdf-write: %v\n", err)
+	}
+}
+
+// slug lower-cases s and joins its letters and digits with hyphens, e.g.
+// "Fish & Chips" becomes "fish-chips"
+func slug(s string) string {
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return r > unicode.MaxASCII || !(unicode.IsLetter(r) || unicode.IsDigit(r))
+	})
+	if len(words) == 0 {
+		return "recipe"
+	}
+	return strings.Join(words, "-")
+}
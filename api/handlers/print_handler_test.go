@@ -0,0 +1,270 @@
+package handlers_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/gorilla/mux"
+	"github.com/kieron-pivotal/menu-planner-app/db"
+	"github.com/kieron-pivotal/menu-planner-app/handlers"
+	"github.com/kieron-pivotal/menu-planner-app/handlers/handlersfakes"
+	"github.com/kieron-pivotal/menu-planner-app/models"
+	"github.com/kieron-pivotal/menu-planner-app/printout"
+	"github.com/kieron-pivotal/menu-planner-app/session"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PrintHandler", func() {
+	var (
+		sessionManager *handlersfakes.FakeSessionManager
+		planStore      *handlersfakes.FakePlanStore
+		recipeStore    *handlersfakes.FakeRecipeStore
+		recorder       *httptest.ResponseRecorder
+		req            *http.Request
+		httpHandlers   *handlers.PrintHandler
+	)
+
+	BeforeEach(func() {
+		sessionManager = new(handlersfakes.FakeSessionManager)
+		sessionManager.GetReturns(&session.AuthInfo{IsLoggedIn: true, Name: "forest", ID: 234}, nil)
+		planStore = new(handlersfakes.FakePlanStore)
+		recipeStore = new(handlersfakes.FakeRecipeStore)
+		recipeStore.IsNotFoundErrStub = func(err error) bool { return err == db.NotFoundErr() }
+		recipeStore.GetStub = func(_ context.Context, _, id int) (models.Recipe, error) {
+			return models.Recipe{ID: id, Name: "Fish & Chips", Steps: []models.Step{{Instruction: "Fry"}}}, nil
+		}
+		recipeStore.GetManyStub = func(_ context.Context, _ int, ids []int) ([]models.Recipe, error) {
+			recipes := []models.Recipe{}
+			for _, id := range ids {
+				recipes = append(recipes, models.Recipe{ID: id, Name: "Fish & Chips", Steps: []models.Step{{Instruction: "Fry"}}})
+			}
+			return recipes, nil
+		}
+		httpHandlers = handlers.NewPrintHandler(sessionManager, planStore, recipeStore, printout.A4)
+		recorder = httptest.NewRecorder()
+	})
+
+	Describe("RecipeCard", func() {
+		var id string
+
+		BeforeEach(func() {
+			id = "345"
+		})
+
+		JustBeforeEach(func() {
+			var err error
+			req, err = http.NewRequest(http.MethodGet, "/recipes/"+id+"/card.pdf", nil)
+			Expect(err).NotTo(HaveOccurred())
+			req = mux.SetURLVars(req, map[string]string{"id": id})
+			httpHandlers.RecipeCard(recorder, req)
+		})
+
+		When("I'm logged out", func() {
+			BeforeEach(func() {
+				sessionManager.GetReturns(&session.AuthInfo{IsLoggedIn: false}, nil)
+			})
+
+			It("returns a status not auth'ed", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusUnauthorized))
+			})
+		})
+
+		It("prints the user's recipe", func() {
+			Expect(recorder.Result().StatusCode).To(Equal(http.StatusOK))
+			_, userID, recipeID := recipeStore.GetArgsForCall(0)
+			Expect(userID).To(Equal(234))
+			Expect(recipeID).To(Equal(345))
+
+			Expect(recorder.Result().Header.Get("Content-Type")).To(Equal("application/pdf"))
+			Expect(recorder.Result().Header.Get("Content-Disposition")).To(Equal(`inline; filename="fish-chips.pdf"`))
+			Expect(recorder.Body.String()).To(HavePrefix("%PDF-"))
+			Expect(recorder.Body.String()).To(ContainSubstring("(Fry) Tj"))
+		})
+
+		When("the id isn't a number", func() {
+			BeforeEach(func() {
+				id = "fish"
+			})
+
+			It("returns not found", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusNotFound))
+			})
+		})
+
+		When("the recipe isn't found", func() {
+			BeforeEach(func() {
+				recipeStore.GetStub = nil
+				recipeStore.GetReturns(models.Recipe{}, db.NotFoundErr())
+			})
+
+			It("returns not found", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusNotFound))
+			})
+		})
+
+		When("getting the recipe fails", func() {
+			BeforeEach(func() {
+				recipeStore.GetStub = nil
+				recipeStore.GetReturns(models.Recipe{}, errors.New("boom"))
+			})
+
+			It("returns an internal server error", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusInternalServerError))
+			})
+		})
+	})
+
+	Describe("PlanPrintout", func() {
+		var week string
+
+		BeforeEach(func() {
+			week = "2026-10-19"
+			planStore.GetReturns(models.Plan{UserID: 234, Week: week, Slots: []models.Slot{
+				{Day: 0, Meal: models.Dinner, RecipeID: 3, Portions: 2},
+				{Day: 1, Meal: models.Lunch, RecipeID: 3, LeftoverOf: &models.SlotRef{Day: 0, Meal: models.Dinner}},
+			}}, nil)
+		})
+
+		JustBeforeEach(func() {
+			var err error
+			req, err = http.NewRequest(http.MethodGet, "/plans/"+week+"/print.pdf", nil)
+			Expect(err).NotTo(HaveOccurred())
+			req = mux.SetURLVars(req, map[string]string{"week": week})
+			httpHandlers.PlanPrintout(recorder, req)
+		})
+
+		When("I'm logged out", func() {
+			BeforeEach(func() {
+				sessionManager.GetReturns(&session.AuthInfo{IsLoggedIn: false}, nil)
+			})
+
+			It("returns a status not auth'ed", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusUnauthorized))
+			})
+		})
+
+		It("prints the user's plan for the week with its leftovers marked", func() {
+			Expect(recorder.Result().StatusCode).To(Equal(http.StatusOK))
+			_, userID, w := planStore.GetArgsForCall(0)
+			Expect(userID).To(Equal(234))
+			Expect(w).To(Equal("2026-10-19"))
+			_, _, ids := recipeStore.GetManyArgsForCall(0)
+			Expect(ids).To(Equal([]int{3, 3}))
+
+			Expect(recorder.Result().Header.Get("Content-Type")).To(Equal("application/pdf"))
+			Expect(recorder.Result().Header.Get("Content-Disposition")).To(Equal(`inline; filename="meal-plan-week-of-19-october-2026.pdf"`))
+			Expect(recorder.Body.String()).To(ContainSubstring("(Meal plan, week of 19 October 2026) Tj"))
+			Expect(recorder.Body.String()).To(ContainSubstring("(leftovers, Mon dinner) Tj"))
+			Expect(recorder.Body.String()).To(ContainSubstring("(Fry) Tj"))
+		})
+
+		When("nothing is planned", func() {
+			BeforeEach(func() {
+				planStore.GetReturns(models.Plan{UserID: 234, Week: week, Slots: []models.Slot{}}, nil)
+			})
+
+			It("prints the empty week without looking up recipes", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusOK))
+				Expect(recipeStore.GetManyCallCount()).To(BeZero())
+			})
+		})
+
+		When("the week isn't a Monday", func() {
+			BeforeEach(func() {
+				week = "2026-10-20"
+			})
+
+			It("returns bad request", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusBadRequest))
+				Expect(planStore.GetCallCount()).To(BeZero())
+			})
+		})
+
+		When("getting the plan fails", func() {
+			BeforeEach(func() {
+				planStore.GetReturns(models.Plan{}, errors.New("boom"))
+			})
+
+			It("returns an internal server error", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusInternalServerError))
+			})
+		})
+
+		When("getting the recipes fails", func() {
+			BeforeEach(func() {
+				recipeStore.GetManyStub = nil
+				recipeStore.GetManyReturns(nil, errors.New("boom"))
+			})
+
+			It("returns an internal server error", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusInternalServerError))
+			})
+		})
+	})
+
+	Describe("RecipesPrintout", func() {
+		var url string
+
+		BeforeEach(func() {
+			url = "/plan.pdf?recipes=3,4&title=Week+23"
+		})
+
+		JustBeforeEach(func() {
+			var err error
+			req, err = http.NewRequest(http.MethodGet, url, nil)
+			Expect(err).NotTo(HaveOccurred())
+			httpHandlers.RecipesPrintout(recorder, req)
+		})
+
+		When("I'm logged out", func() {
+			BeforeEach(func() {
+				sessionManager.GetReturns(&session.AuthInfo{IsLoggedIn: false}, nil)
+			})
+
+			It("returns a status not auth'ed", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusUnauthorized))
+			})
+		})
+
+		It("prints the recipes with their title", func() {
+			Expect(recorder.Result().StatusCode).To(Equal(http.StatusOK))
+			Expect(recipeStore.GetManyCallCount()).To(Equal(1))
+			Expect(recorder.Result().Header.Get("Content-Disposition")).To(Equal(`inline; filename="week-23.pdf"`))
+			Expect(recorder.Body.String()).To(ContainSubstring("(Week 23) Tj"))
+		})
+
+		When("there is no title", func() {
+			BeforeEach(func() {
+				url = "/plan.pdf?recipes=3"
+			})
+
+			It("calls it a meal plan", func() {
+				Expect(recorder.Body.String()).To(ContainSubstring("(Meal plan) Tj"))
+			})
+		})
+
+		When("a recipe isn't found", func() {
+			BeforeEach(func() {
+				recipeStore.GetManyStub = nil
+				recipeStore.GetManyReturns([]models.Recipe{}, nil)
+			})
+
+			It("returns not found", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusNotFound))
+			})
+		})
+
+		When("a recipe id isn't a number", func() {
+			BeforeEach(func() {
+				url = "/plan.pdf?recipes=fish"
+			})
+
+			It("returns bad request", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusBadRequest))
+			})
+		})
+	})
+})
@@ -1,43 +1,109 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
-	"io/ioutil"
+	"errors"
+	"fmt"
+	"log"
 	"net/http"
+	"sort"
+	"strconv"
+	"strings"
 
+	"github.com/gorilla/mux"
 	"github.com/kieron-pivotal/menu-planner-app/models"
+	"github.com/kieron-pivotal/menu-planner-app/problem"
+	"github.com/kieron-pivotal/menu-planner-app/similar"
 )
 
 //counterfeiter:generate . RecipeStore
 
 type RecipeStore interface {
-	List(userID int) ([]models.Recipe, error)
-	Insert(recipe models.Recipe) (models.Recipe, error)
+	IsNotFoundErr(error) bool
+	IsDuplicateErr(error) bool
+	List(ctx context.Context, userID int) ([]models.Recipe, error)
+	Get(ctx context.Context, userID, id int) (models.Recipe, error)
+	GetMany(ctx context.Context, userID int, ids []int) ([]models.Recipe, error)
+	Insert(ctx context.Context, recipe models.Recipe) (models.Recipe, error)
+	Merge(ctx context.Context, survivorID, duplicateID int) error
+}
+
+// errConflict stops a unit of work when a new recipe clashes with ones the
+// user already has
+var errConflict = errors.New("recipe conflicts with existing recipes")
+
+//counterfeiter:generate . RatingStore
+
+type RatingStore interface {
+	Rate(ctx context.Context, rating models.Rating) (models.Rating, error)
+}
+
+//counterfeiter:generate . NutritionCalculator
+
+type NutritionCalculator interface {
+	ForRecipe(recipe models.Recipe) *models.Nutrition
+}
+
+//counterfeiter:generate . CostEstimator
+
+type CostEstimator interface {
+	ForRecipe(ctx context.Context, recipe models.Recipe) (*models.Cost, error)
+	ForRecipes(ctx context.Context, userID int, recipes []models.Recipe) (map[int]*models.Cost, error)
 }
 
 type RecipeHandler struct {
-	sessionManager SessionManager
-	recipeStore    RecipeStore
+	sessionManager      SessionManager
+	recipeStore         RecipeStore
+	transactor          Transactor
+	ratingStore         RatingStore
+	nutritionCalculator NutritionCalculator
+	costEstimator       CostEstimator
+	publisher           EventPublisher
 }
 
-func NewRecipeHandler(sessionManager SessionManager, recipeStore RecipeStore) *RecipeHandler {
+func NewRecipeHandler(
+	sessionManager SessionManager, recipeStore RecipeStore, transactor Transactor, ratingStore RatingStore,
+	nutritionCalculator NutritionCalculator, costEstimator CostEstimator, publisher EventPublisher) *RecipeHandler {
 	return &RecipeHandler{
-		sessionManager: sessionManager,
-		recipeStore:    recipeStore,
+		sessionManager:      sessionManager,
+		recipeStore:         recipeStore,
+		transactor:          transactor,
+		ratingStore:         ratingStore,
+		nutritionCalculator: nutritionCalculator,
+		costEstimator:       costEstimator,
+		publisher:           publisher,
 	}
 }
 
+// GetRecipes lists the user's recipes. With sort=lastCooked the most
+// recently cooked come first and those never cooked come last; with
+// sort=favourites the user's favourites come first, then the best rated.
+// minRating leaves out recipes anyone has rated lower.
 func (h *RecipeHandler) GetRecipes(w http.ResponseWriter, r *http.Request) {
 	sess, err := h.sessionManager.Get(r.Context())
 	if err != nil || sess == nil || !sess.IsLoggedIn {
-		http.Error(w, `{"error": "unauthorized"}`, http.StatusUnauthorized)
+		problem.Write(w, http.StatusUnauthorized, problem.Unauthorized, "")
 
 		return
 	}
 
-	recipes, err := h.recipeStore.List(sess.ID)
+	minRating := 0
+	if param := r.URL.Query().Get("minRating"); param != "" {
+		minRating, err = strconv.Atoi(param)
+		if err != nil {
+			problem.Write(w, http.StatusBadRequest, problem.InvalidParameter, "minRating must be a whole number")
+
+			return
+		}
+	}
+
+	recipes, err := h.recipeStore.List(r.Context(), sess.ID)
 	if err != nil {
-		// TODO: handle err
+		log.Printf("recipe-list: %v\n", err)
+		problem.Write(w, http.StatusInternalServerError, problem.Internal, "")
+
+		return
 	}
 
 	w.Header().Add("Content-Type", "application/json")
@@ -45,48 +111,321 @@ func (h *RecipeHandler) GetRecipes(w http.ResponseWriter, r *http.Request) {
 	list := []models.Recipe{}
 
 	for _, r := range recipes {
-		list = append(list, models.Recipe{Name: r.Name, ID: r.ID})
+		if r.LowestRating > 0 && r.LowestRating < minRating {
+			continue
+		}
+
+		list = append(list, models.Recipe{
+			Name: r.Name, ID: r.ID, LastCookedAt: r.LastCookedAt,
+			Score: r.Score, LowestRating: r.LowestRating, Favourite: r.Favourite,
+		})
+	}
+
+	switch r.URL.Query().Get("sort") {
+	case "lastCooked":
+		sort.SliceStable(list, func(i, j int) bool {
+			if list[i].LastCookedAt == nil {
+				return false
+			}
+			return list[j].LastCookedAt == nil || list[i].LastCookedAt.After(*list[j].LastCookedAt)
+		})
+	case "favourites":
+		sort.SliceStable(list, func(i, j int) bool {
+			if list[i].Favourite != list[j].Favourite {
+				return list[i].Favourite
+			}
+			return list[i].Score > list[j].Score
+		})
 	}
 
 	if err = json.NewEncoder(w).Encode(list); err != nil {
-		http.Error(w, "json encoding failure", http.StatusInternalServerError)
+		problem.Write(w, http.StatusInternalServerError, problem.Internal, "json encoding failure")
 
 		return
 	}
 }
 
-func (h *RecipeHandler) NewRecipe(w http.ResponseWriter, r *http.Request) {
+// GetRecipe returns a recipe with its ingredients, method and, if it has
+// ingredients, estimates of its nutrition and cost
+func (h *RecipeHandler) GetRecipe(w http.ResponseWriter, r *http.Request) {
 	sess, err := h.sessionManager.Get(r.Context())
 	if err != nil || sess == nil || !sess.IsLoggedIn {
-		http.Error(w, `{"error": "unauthorized"}`, http.StatusUnauthorized)
+		problem.Write(w, http.StatusUnauthorized, problem.Unauthorized, "")
 
 		return
 	}
 
-	body, err := ioutil.ReadAll(r.Body)
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
 	if err != nil {
-		http.Error(w, "", http.StatusBadRequest)
+		problem.Write(w, http.StatusNotFound, problem.NotFound, "")
+
+		return
+	}
+
+	recipe, err := h.recipeStore.Get(r.Context(), sess.ID, id)
+	if err != nil {
+		if h.recipeStore.IsNotFoundErr(err) {
+			problem.Write(w, http.StatusNotFound, problem.NotFound, "")
+
+			return
+		}
+
+		log.Printf("recipe-get: %v\n", err)
+		problem.Write(w, http.StatusInternalServerError, problem.Internal, "")
+
+		return
+	}
+
+	if len(recipe.Ingredients) > 0 {
+		recipe.Nutrition = h.nutritionCalculator.ForRecipe(recipe)
+
+		if recipe.Cost, err = h.costEstimator.ForRecipe(r.Context(), recipe); err != nil {
+			log.Printf("recipe-get: %v\n", err)
+			problem.Write(w, http.StatusInternalServerError, problem.Internal, "")
+
+			return
+		}
+	}
+
+	w.Header().Add("Content-Type", "application/json")
+
+	if err = json.NewEncoder(w).Encode(recipe); err != nil {
+		problem.Write(w, http.StatusInternalServerError, problem.Internal, "json encoding failure")
 
 		return
 	}
+}
+
+// NewRecipe saves a recipe. It is a 409 if the user has a recipe with the
+// same name, ignoring case, or, unless allowSimilar=true, with a name that
+// looks like the same dish, e.g. "Spag bol" for "Spaghetti Bolognese". The
+// problem lists those recipes so the user can pick one instead.
+func (h *RecipeHandler) NewRecipe(w http.ResponseWriter, r *http.Request) {
+	sess, err := h.sessionManager.Get(r.Context())
+	if err != nil || sess == nil || !sess.IsLoggedIn {
+		problem.Write(w, http.StatusUnauthorized, problem.Unauthorized, "")
+
+		return
+	}
+
+	allowSimilar := false
+	if param := r.URL.Query().Get("allowSimilar"); param != "" {
+		allowSimilar, err = strconv.ParseBool(param)
+		if err != nil {
+			problem.Write(w, http.StatusBadRequest, problem.InvalidParameter, "allowSimilar must be true or false")
+
+			return
+		}
+	}
 
 	recipe := models.Recipe{}
-	if err = json.Unmarshal(body, &recipe); err != nil {
-		http.Error(w, "", http.StatusBadRequest)
+	if !decodeJSON(w, r, &recipe, maxRecipeBytes) {
+		return
+	}
+
+	recipe.Name = strings.TrimSpace(recipe.Name)
+	if errs := validateRecipe(recipe); len(errs) > 0 {
+		problem.WriteInvalid(w, errs)
 
 		return
 	}
 
 	recipe.UserID = sess.ID
 
-	recipe, err = h.recipeStore.Insert(recipe)
+	code := problem.SimilarRecipes
+	var conflicts []models.Recipe
+
+	err = h.transactor.InTx(r.Context(), func(ctx context.Context) error {
+		existing, err := h.recipeStore.List(ctx, sess.ID)
+		if err != nil {
+			return err
+		}
+
+		for _, e := range existing {
+			if strings.EqualFold(e.Name, recipe.Name) {
+				code, conflicts = problem.DuplicateRecipe, []models.Recipe{e}
+				return errConflict
+			}
+		}
+
+		if !allowSimilar {
+			if conflicts = similar.Recipes(recipe.Name, existing); len(conflicts) > 0 {
+				return errConflict
+			}
+		}
+
+		recipe, err = h.recipeStore.Insert(ctx, recipe)
+		return err
+	})
+	if err == errConflict {
+		writeRecipeConflict(w, code, recipe.Name, conflicts)
+
+		return
+	}
+	if err != nil && h.recipeStore.IsDuplicateErr(err) {
+		writeRecipeConflict(w, problem.DuplicateRecipe, recipe.Name, nil)
+
+		return
+	}
 	if err != nil {
-		http.Error(w, "", http.StatusBadRequest)
+		log.Printf("recipe-insert: %v\n", err)
+		problem.Write(w, http.StatusInternalServerError, problem.Internal, "")
 
 		return
 	}
 
+	publish(r.Context(), h.publisher, sess.ID, models.Event{Type: models.RecipeCreated, RecipeID: recipe.ID})
+
 	w.Header().Add("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(recipe)
 }
+
+// writeRecipeConflict writes a 409 for a new recipe named name, listing
+// the recipes it clashed with
+func writeRecipeConflict(w http.ResponseWriter, code problem.Code, name string, recipes []models.Recipe) {
+	detail := fmt.Sprintf("you already have a recipe called %q", name)
+	if code == problem.SimilarRecipes {
+		detail = fmt.Sprintf("you have recipes like %q; save it anyway with allowSimilar=true", name)
+	}
+
+	conflicts := []problem.Conflict{}
+	for _, r := range recipes {
+		conflicts = append(conflicts, problem.Conflict{ID: r.ID, Name: r.Name})
+	}
+
+	problem.WriteConflict(w, code, detail, conflicts)
+}
+
+// MergeRecipes folds the recipes listed as duplicates into the one in the
+// path, which keeps its name, ingredients and method and gains their cook
+// log and ratings, and deletes them. It returns the merged recipe.
+func (h *RecipeHandler) MergeRecipes(w http.ResponseWriter, r *http.Request) {
+	sess, err := h.sessionManager.Get(r.Context())
+	if err != nil || sess == nil || !sess.IsLoggedIn {
+		problem.Write(w, http.StatusUnauthorized, problem.Unauthorized, "")
+
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		problem.Write(w, http.StatusNotFound, problem.NotFound, "")
+
+		return
+	}
+
+	var merge struct {
+		Duplicates []int `json:"duplicates"`
+	}
+	if !decodeJSON(w, r, &merge, maxBodyBytes) {
+		return
+	}
+
+	if errs := validateMerge(id, merge.Duplicates); len(errs) > 0 {
+		problem.WriteInvalid(w, errs)
+
+		return
+	}
+
+	var recipe models.Recipe
+	err = h.transactor.InTx(r.Context(), func(ctx context.Context) error {
+		for _, recipeID := range append([]int{id}, merge.Duplicates...) {
+			if _, err := h.recipeStore.Get(ctx, sess.ID, recipeID); err != nil {
+				return err
+			}
+		}
+
+		for _, duplicateID := range merge.Duplicates {
+			if err := h.recipeStore.Merge(ctx, id, duplicateID); err != nil {
+				return err
+			}
+		}
+
+		recipe, err = h.recipeStore.Get(ctx, sess.ID, id)
+		return err
+	})
+	if err != nil {
+		if h.recipeStore.IsNotFoundErr(err) {
+			problem.Write(w, http.StatusNotFound, problem.NotFound, "")
+
+			return
+		}
+
+		log.Printf("recipe-merge: %v\n", err)
+		problem.Write(w, http.StatusInternalServerError, problem.Internal, "")
+
+		return
+	}
+
+	changes := []models.Event{{Type: models.RecipeChanged, RecipeID: id}}
+	for _, duplicateID := range merge.Duplicates {
+		changes = append(changes, models.Event{Type: models.RecipeDeleted, RecipeID: duplicateID})
+	}
+	// the survivor replaces the duplicates in whichever weeks they were
+	// planned
+	changes = append(changes, planEvents("")...)
+	publish(r.Context(), h.publisher, sess.ID, changes...)
+
+	w.Header().Add("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(recipe)
+}
+
+// RateRecipe records the user's rating of a recipe and whether it is one
+// of their favourites. Users can only rate their own recipes until there
+// are households to share them with.
+func (h *RecipeHandler) RateRecipe(w http.ResponseWriter, r *http.Request) {
+	sess, err := h.sessionManager.Get(r.Context())
+	if err != nil || sess == nil || !sess.IsLoggedIn {
+		problem.Write(w, http.StatusUnauthorized, problem.Unauthorized, "")
+
+		return
+	}
+
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		problem.Write(w, http.StatusNotFound, problem.NotFound, "")
+
+		return
+	}
+
+	rating := models.Rating{}
+	if !decodeJSON(w, r, &rating, maxBodyBytes) {
+		return
+	}
+
+	if errs := validateRating(rating); len(errs) > 0 {
+		problem.WriteInvalid(w, errs)
+
+		return
+	}
+
+	if _, err = h.recipeStore.Get(r.Context(), sess.ID, id); err != nil {
+		if h.recipeStore.IsNotFoundErr(err) {
+			problem.Write(w, http.StatusNotFound, problem.NotFound, "")
+
+			return
+		}
+
+		log.Printf("recipe-get: %v\n", err)
+		problem.Write(w, http.StatusInternalServerError, problem.Internal, "")
+
+		return
+	}
+
+	rating.RecipeID = id
+	rating.UserID = sess.ID
+
+	rating, err = h.ratingStore.Rate(r.Context(), rating)
+	if err != nil {
+		log.Printf("recipe-rate: %v\n", err)
+		problem.Write(w, http.StatusInternalServerError, problem.Internal, "")
+
+		return
+	}
+
+	publish(r.Context(), h.publisher, sess.ID, models.Event{Type: models.RecipeChanged, RecipeID: id})
+
+	w.Header().Add("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rating)
+}
@@ -1,15 +1,20 @@
 package handlers_test
 
 import (
+	"context"
 	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"time"
 
+	"github.com/gorilla/mux"
+	"github.com/kieron-pivotal/menu-planner-app/db"
 	"github.com/kieron-pivotal/menu-planner-app/handlers"
 	"github.com/kieron-pivotal/menu-planner-app/handlers/handlersfakes"
 	"github.com/kieron-pivotal/menu-planner-app/models"
+	"github.com/kieron-pivotal/menu-planner-app/problem"
 	"github.com/kieron-pivotal/menu-planner-app/session"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -19,6 +24,11 @@ var _ = Describe("RecipeHandler", func() {
 	var (
 		sessionManager *handlersfakes.FakeSessionManager
 		recipeStore    *handlersfakes.FakeRecipeStore
+		transactor     *handlersfakes.FakeTransactor
+		ratingStore    *handlersfakes.FakeRatingStore
+		nutrition      *handlersfakes.FakeNutritionCalculator
+		costEstimator  *handlersfakes.FakeCostEstimator
+		publisher      *handlersfakes.FakeEventPublisher
 		recorder       *httptest.ResponseRecorder
 		req            *http.Request
 		httpHandlers   *handlers.RecipeHandler
@@ -30,20 +40,35 @@ var _ = Describe("RecipeHandler", func() {
 	BeforeEach(func() {
 		sessionManager = new(handlersfakes.FakeSessionManager)
 		recipeStore = new(handlersfakes.FakeRecipeStore)
-		httpHandlers = handlers.NewRecipeHandler(sessionManager, recipeStore)
+		recipeStore.IsNotFoundErrStub = func(err error) bool { return err == db.NotFoundErr() }
+		transactor = new(handlersfakes.FakeTransactor)
+		transactor.InTxStub = func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(context.WithValue(ctx, inTx{}, true))
+		}
+		ratingStore = new(handlersfakes.FakeRatingStore)
+		ratingStore.RateStub = func(_ context.Context, rating models.Rating) (models.Rating, error) {
+			return rating, nil
+		}
+		nutrition = new(handlersfakes.FakeNutritionCalculator)
+		costEstimator = new(handlersfakes.FakeCostEstimator)
+		publisher = new(handlersfakes.FakeEventPublisher)
+		httpHandlers = handlers.NewRecipeHandler(sessionManager, recipeStore, transactor, ratingStore, nutrition, costEstimator, publisher)
 		recorder = httptest.NewRecorder()
 		recipe1 = models.Recipe{Name: "Bob", ID: 345}
 		recipe2 = models.Recipe{Name: "Jim", ID: 456}
 	})
 
 	Describe("GetRecipes", func() {
+		var url string
+
 		BeforeEach(func() {
+			url = "/recipes"
 			hf = http.HandlerFunc(httpHandlers.GetRecipes)
 		})
 
 		JustBeforeEach(func() {
 			var err error
-			req, err = http.NewRequest(http.MethodGet, "application/json", nil)
+			req, err = http.NewRequest(http.MethodGet, url, nil)
 			Expect(err).NotTo(HaveOccurred())
 			hf.ServeHTTP(recorder, req)
 		})
@@ -55,6 +80,7 @@ var _ = Describe("RecipeHandler", func() {
 
 			It("returns a status not auth'ed", func() {
 				Expect(recorder.Result().StatusCode).To(Equal(http.StatusUnauthorized))
+				Expect(problemCode(recorder)).To(Equal(problem.Unauthorized))
 			})
 		})
 
@@ -68,6 +94,18 @@ var _ = Describe("RecipeHandler", func() {
 			})
 		})
 
+		When("the store fails", func() {
+			BeforeEach(func() {
+				sessionManager.GetReturns(&session.AuthInfo{IsLoggedIn: true, Name: "forest", ID: 234}, nil)
+				recipeStore.ListReturns(nil, errors.New("boom"))
+			})
+
+			It("returns an internal server error", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusInternalServerError))
+				Expect(problemCode(recorder)).To(Equal(problem.Internal))
+			})
+		})
+
 		When("I'm logged in", func() {
 			BeforeEach(func() {
 				sessionManager.GetReturns(&session.AuthInfo{IsLoggedIn: true, Name: "forest", ID: 234}, nil)
@@ -80,7 +118,7 @@ var _ = Describe("RecipeHandler", func() {
 
 			It("lists recipes using user ID", func() {
 				Expect(recipeStore.ListCallCount()).To(Equal(1))
-				userID := recipeStore.ListArgsForCall(0)
+				_, userID := recipeStore.ListArgsForCall(0)
 				Expect(userID).To(Equal(234))
 			})
 
@@ -88,20 +126,205 @@ var _ = Describe("RecipeHandler", func() {
 				Expect(recorder.Header().Get("Content-Type")).To(Equal("application/json"))
 				Expect(recorder.Body.String()).To(ContainSubstring(`[{"name":"Bob","id":345},{"name":"Jim","id":456}]`))
 			})
+
+			When("sorting by when they were last cooked", func() {
+				BeforeEach(func() {
+					url = "/recipes?sort=lastCooked"
+					lastWeek := time.Date(2020, 5, 1, 18, 0, 0, 0, time.UTC)
+					yesterday := time.Date(2020, 5, 7, 18, 0, 0, 0, time.UTC)
+					recipe2.LastCookedAt = &lastWeek
+					recipe3 := models.Recipe{Name: "Sue", ID: 567, LastCookedAt: &yesterday}
+					recipeStore.ListReturns([]models.Recipe{recipe1, recipe2, recipe3}, nil)
+				})
+
+				It("lists the most recently cooked first and those never cooked last", func() {
+					Expect(recorder.Body.String()).To(MatchJSON(`[
+						{"name": "Sue", "id": 567, "lastCookedAt": "2020-05-07T18:00:00Z"},
+						{"name": "Jim", "id": 456, "lastCookedAt": "2020-05-01T18:00:00Z"},
+						{"name": "Bob", "id": 345}
+					]`))
+				})
+			})
+
+			When("some recipes are rated", func() {
+				BeforeEach(func() {
+					recipe2.Score, recipe2.LowestRating = 4.5, 4
+					recipe3 := models.Recipe{Name: "Sue", ID: 567, Score: 3, LowestRating: 2, Favourite: true}
+					recipe4 := models.Recipe{Name: "Ann", ID: 678, Score: 5, LowestRating: 5}
+					recipeStore.ListReturns([]models.Recipe{recipe1, recipe2, recipe3, recipe4}, nil)
+				})
+
+				It("includes the scores", func() {
+					Expect(recorder.Body.String()).To(MatchJSON(`[
+						{"name": "Bob", "id": 345},
+						{"name": "Jim", "id": 456, "score": 4.5, "lowestRating": 4},
+						{"name": "Sue", "id": 567, "score": 3, "lowestRating": 2, "favourite": true},
+						{"name": "Ann", "id": 678, "score": 5, "lowestRating": 5}
+					]`))
+				})
+
+				When("sorting by favourites", func() {
+					BeforeEach(func() {
+						url = "/recipes?sort=favourites"
+					})
+
+					It("lists favourites first, then the best rated", func() {
+						Expect(names(recorder)).To(Equal([]string{"Sue", "Ann", "Jim", "Bob"}))
+					})
+				})
+
+				When("a minimum rating is given", func() {
+					BeforeEach(func() {
+						url = "/recipes?minRating=3"
+					})
+
+					It("leaves out recipes anyone rated lower", func() {
+						Expect(names(recorder)).To(Equal([]string{"Bob", "Jim", "Ann"}))
+					})
+				})
+
+				When("the minimum rating isn't a number", func() {
+					BeforeEach(func() {
+						url = "/recipes?minRating=good"
+					})
+
+					It("returns a bad request status", func() {
+						Expect(recorder.Result().StatusCode).To(Equal(http.StatusBadRequest))
+					})
+				})
+			})
+		})
+	})
+
+	Describe("GetRecipe", func() {
+		var id string
+
+		BeforeEach(func() {
+			id = "345"
+			hf = http.HandlerFunc(httpHandlers.GetRecipe)
+			sessionManager.GetReturns(&session.AuthInfo{IsLoggedIn: true, Name: "forest", ID: 234}, nil)
+			recipe1.Ingredients = []models.Ingredient{{Name: "eggs", Quantity: 2}}
+			recipe1.Steps = []models.Step{{Instruction: "Boil", DurationSeconds: 360, Ingredients: []int{0}}}
+			recipeStore.GetReturns(recipe1, nil)
+			nutrition.ForRecipeReturns(&models.Nutrition{
+				PerServing: models.Macros{Calories: 143, Protein: 13, Fat: 9.5, Carbohydrate: 0.7},
+			})
+			costEstimator.ForRecipeReturns(&models.Cost{Total: 60, PerServing: 30}, nil)
+		})
+
+		JustBeforeEach(func() {
+			var err error
+			req, err = http.NewRequest(http.MethodGet, "/recipes/"+id, nil)
+			Expect(err).NotTo(HaveOccurred())
+			req = mux.SetURLVars(req, map[string]string{"id": id})
+			hf.ServeHTTP(recorder, req)
+		})
+
+		When("I'm logged out", func() {
+			BeforeEach(func() {
+				sessionManager.GetReturns(&session.AuthInfo{IsLoggedIn: false}, nil)
+			})
+
+			It("returns a status not auth'ed", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusUnauthorized))
+			})
+		})
+
+		It("gets the user's recipe", func() {
+			Expect(recipeStore.GetCallCount()).To(Equal(1))
+			_, userID, recipeID := recipeStore.GetArgsForCall(0)
+			Expect(userID).To(Equal(234))
+			Expect(recipeID).To(Equal(345))
+		})
+
+		It("returns the recipe with its method, nutrition and cost as JSON", func() {
+			Expect(recorder.Result().StatusCode).To(Equal(http.StatusOK))
+			Expect(recorder.Header().Get("Content-Type")).To(Equal("application/json"))
+			Expect(recorder.Body.String()).To(MatchJSON(`{
+				"name": "Bob",
+				"id": 345,
+				"ingredients": [{"name": "eggs", "quantity": 2}],
+				"steps": [{"instruction": "Boil", "durationSeconds": 360, "ingredients": [0]}],
+				"nutrition": {"perServing": {"calories": 143, "protein": 13, "fat": 9.5, "carbohydrate": 0.7}},
+				"cost": {"total": 60, "perServing": 30}
+			}`))
+			Expect(nutrition.ForRecipeCallCount()).To(Equal(1))
+			Expect(nutrition.ForRecipeArgsForCall(0).Name).To(Equal("Bob"))
+			Expect(costEstimator.ForRecipeCallCount()).To(Equal(1))
+			_, costed := costEstimator.ForRecipeArgsForCall(0)
+			Expect(costed.Name).To(Equal("Bob"))
+		})
+
+		When("the cost can't be estimated", func() {
+			BeforeEach(func() {
+				costEstimator.ForRecipeReturns(nil, errors.New("boom"))
+			})
+
+			It("returns an internal server error", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusInternalServerError))
+			})
+		})
+
+		When("the recipe has no ingredients", func() {
+			BeforeEach(func() {
+				recipeStore.GetReturns(recipe2, nil)
+			})
+
+			It("leaves out the nutrition and cost", func() {
+				Expect(recorder.Body.String()).NotTo(ContainSubstring("nutrition"))
+				Expect(recorder.Body.String()).NotTo(ContainSubstring("cost"))
+				Expect(nutrition.ForRecipeCallCount()).To(BeZero())
+				Expect(costEstimator.ForRecipeCallCount()).To(BeZero())
+			})
+		})
+
+		When("the id isn't a number", func() {
+			BeforeEach(func() {
+				id = "soup"
+			})
+
+			It("returns not found", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusNotFound))
+				Expect(recipeStore.GetCallCount()).To(BeZero())
+			})
+		})
+
+		When("the recipe doesn't exist", func() {
+			BeforeEach(func() {
+				recipeStore.GetReturns(models.Recipe{}, db.NotFoundErr())
+			})
+
+			It("returns not found", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusNotFound))
+			})
+		})
+
+		When("the store fails", func() {
+			BeforeEach(func() {
+				recipeStore.GetReturns(models.Recipe{}, errors.New("boom"))
+			})
+
+			It("returns an internal server error", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusInternalServerError))
+			})
 		})
 	})
 
 	Context("NewRecipe", func() {
-		var body io.Reader
+		var (
+			url  string
+			body io.Reader
+		)
 
 		BeforeEach(func() {
+			url = "/recipes"
 			body = strings.NewReader("")
 			hf = http.HandlerFunc(httpHandlers.NewRecipe)
 		})
 
 		JustBeforeEach(func() {
 			var err error
-			req, err = http.NewRequest(http.MethodPost, "application/json", body)
+			req, err = http.NewRequest(http.MethodPost, url, body)
 			Expect(err).NotTo(HaveOccurred())
 			hf.ServeHTTP(recorder, req)
 		})
@@ -136,13 +359,479 @@ var _ = Describe("RecipeHandler", func() {
 			It("inserts the meal into the database", func() {
 				Expect(recorder.Result().StatusCode).To(Equal(http.StatusCreated))
 				Expect(recipeStore.InsertCallCount()).To(Equal(1))
-				recipe := recipeStore.InsertArgsForCall(0)
+				ctx, recipe := recipeStore.InsertArgsForCall(0)
+				Expect(ctx.Value(inTx{})).To(BeTrue())
 				Expect(recipe).To(Equal(models.Recipe{Name: "foo bar", ID: 0, UserID: 234}))
 				Expect(recorder.Body.String()).To(SatisfyAll(
 					ContainSubstring(`"name":"foo bar"`),
 					ContainSubstring(`"id":456`),
 				))
 			})
+
+			It("tells my other clients", func() {
+				Expect(publisher.PublishCallCount()).To(Equal(1))
+				_, userID, event := publisher.PublishArgsForCall(0)
+				Expect(userID).To(Equal(234))
+				Expect(event).To(Equal(models.Event{Type: models.RecipeCreated, RecipeID: 456}))
+			})
+
+			When("telling them fails", func() {
+				BeforeEach(func() {
+					publisher.PublishReturns(errors.New("boom"))
+				})
+
+				It("still saves the recipe", func() {
+					Expect(recorder.Result().StatusCode).To(Equal(http.StatusCreated))
+				})
+			})
+		})
+
+		When("the body isn't JSON", func() {
+			BeforeEach(func() {
+				sessionManager.GetReturns(&session.AuthInfo{IsLoggedIn: true, Name: "forest", ID: 234}, nil)
+				body = strings.NewReader(`{"name": "foo"`)
+			})
+
+			It("returns a bad request status", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusBadRequest))
+				Expect(problemCode(recorder)).To(Equal(problem.InvalidJSON))
+				Expect(recipeStore.InsertCallCount()).To(BeZero())
+			})
+		})
+
+		When("the body is more than one JSON value", func() {
+			BeforeEach(func() {
+				sessionManager.GetReturns(&session.AuthInfo{IsLoggedIn: true, Name: "forest", ID: 234}, nil)
+				body = strings.NewReader(`{"name": "foo"} {"name": "bar"}`)
+			})
+
+			It("returns a bad request status", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusBadRequest))
+				Expect(problemCode(recorder)).To(Equal(problem.InvalidJSON))
+			})
+		})
+
+		When("the body is too big", func() {
+			BeforeEach(func() {
+				sessionManager.GetReturns(&session.AuthInfo{IsLoggedIn: true, Name: "forest", ID: 234}, nil)
+				body = strings.NewReader(`{"name": "` + strings.Repeat("a", 300<<10) + `"}`)
+			})
+
+			It("returns a request too large status", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusRequestEntityTooLarge))
+				Expect(problemCode(recorder)).To(Equal(problem.BodyTooLarge))
+			})
+		})
+
+		When("a field has the wrong type", func() {
+			BeforeEach(func() {
+				sessionManager.GetReturns(&session.AuthInfo{IsLoggedIn: true, Name: "forest", ID: 234}, nil)
+				body = strings.NewReader(`{"name": 3}`)
+			})
+
+			It("says which", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusUnprocessableEntity))
+				Expect(fieldErrors(recorder)).To(Equal([]problem.FieldError{{Field: "name", Message: "must be a string"}}))
+			})
+		})
+
+		When("the body has a field recipes don't", func() {
+			BeforeEach(func() {
+				sessionManager.GetReturns(&session.AuthInfo{IsLoggedIn: true, Name: "forest", ID: 234}, nil)
+				body = strings.NewReader(`{"name": "foo", "colour": "red"}`)
+			})
+
+			It("rejects it", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusUnprocessableEntity))
+				Expect(fieldErrors(recorder)).To(Equal([]problem.FieldError{{Field: "colour", Message: "is not allowed"}}))
+				Expect(recipeStore.InsertCallCount()).To(BeZero())
+			})
+		})
+
+		When("fields are invalid", func() {
+			BeforeEach(func() {
+				sessionManager.GetReturns(&session.AuthInfo{IsLoggedIn: true, Name: "forest", ID: 234}, nil)
+				body = strings.NewReader(`{
+					"name": "  ",
+					"servings": -1,
+					"ingredients": [{"name": "eggs", "quantity": 2}, {"name": "", "quantity": -1, "unit": "` + strings.Repeat("g", 51) + `"}],
+					"steps": [{"instruction": "Boil", "durationSeconds": -5, "ingredients": [0, 2]}]
+				}`)
+			})
+
+			It("lists what is wrong with each", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusUnprocessableEntity))
+				Expect(problemCode(recorder)).To(Equal(problem.ValidationFailed))
+				Expect(fieldErrors(recorder)).To(Equal([]problem.FieldError{
+					{Field: "name", Message: "is required"},
+					{Field: "servings", Message: "must not be negative"},
+					{Field: "ingredients[1].name", Message: "is required"},
+					{Field: "ingredients[1].quantity", Message: "must not be negative"},
+					{Field: "ingredients[1].unit", Message: "must be at most 50 characters"},
+					{Field: "steps[0].durationSeconds", Message: "must not be negative"},
+					{Field: "steps[0].ingredients[1]", Message: "must be the index of one of the recipe's ingredients"},
+				}))
+				Expect(recipeStore.InsertCallCount()).To(BeZero())
+			})
+		})
+
+		When("the name is too long", func() {
+			BeforeEach(func() {
+				sessionManager.GetReturns(&session.AuthInfo{IsLoggedIn: true, Name: "forest", ID: 234}, nil)
+				body = strings.NewReader(`{"name": "` + strings.Repeat("é", 201) + `"}`)
+			})
+
+			It("says how long it may be", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusUnprocessableEntity))
+				Expect(fieldErrors(recorder)).To(Equal([]problem.FieldError{{Field: "name", Message: "must be at most 200 characters"}}))
+			})
+		})
+
+		When("the name has spaces round it", func() {
+			BeforeEach(func() {
+				sessionManager.GetReturns(&session.AuthInfo{IsLoggedIn: true, Name: "forest", ID: 234}, nil)
+				body = strings.NewReader(`{"name": " foo bar "}`)
+			})
+
+			It("trims them", func() {
+				_, recipe := recipeStore.InsertArgsForCall(0)
+				Expect(recipe.Name).To(Equal("foo bar"))
+			})
+		})
+
+		When("the recipe can't be saved", func() {
+			BeforeEach(func() {
+				sessionManager.GetReturns(&session.AuthInfo{IsLoggedIn: true, Name: "forest", ID: 234}, nil)
+				body = strings.NewReader(`{"name":"foo bar"}`)
+				recipeStore.InsertReturns(models.Recipe{}, errors.New("boom"))
+			})
+
+			It("returns an internal server error problem", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusInternalServerError))
+				Expect(problemCode(recorder)).To(Equal(problem.Internal))
+			})
+		})
+
+		When("the transaction can't be committed", func() {
+			BeforeEach(func() {
+				sessionManager.GetReturns(&session.AuthInfo{IsLoggedIn: true, Name: "forest", ID: 234}, nil)
+				body = strings.NewReader(`{"name":"foo bar"}`)
+				transactor.InTxStub = func(ctx context.Context, fn func(context.Context) error) error {
+					if err := fn(ctx); err != nil {
+						return err
+					}
+					return errors.New("commit failed")
+				}
+			})
+
+			It("returns an internal server error problem", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusInternalServerError))
+				Expect(problemCode(recorder)).To(Equal(problem.Internal))
+			})
+		})
+
+		When("I have recipes", func() {
+			BeforeEach(func() {
+				sessionManager.GetReturns(&session.AuthInfo{IsLoggedIn: true, Name: "forest", ID: 234}, nil)
+				recipeStore.ListReturns([]models.Recipe{
+					{ID: 3, Name: "Spaghetti Bolognese"},
+					{ID: 4, Name: "Fish pie"},
+				}, nil)
+				recipeStore.InsertReturns(models.Recipe{Name: "Fish curry", ID: 456}, nil)
+				body = strings.NewReader(`{"name":"Fish curry"}`)
+			})
+
+			It("checks the new one against them in the unit of work", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusCreated))
+				Expect(recipeStore.ListCallCount()).To(Equal(1))
+				ctx, userID := recipeStore.ListArgsForCall(0)
+				Expect(ctx.Value(inTx{})).To(BeTrue())
+				Expect(userID).To(Equal(234))
+			})
+
+			When("one has the same name in another case", func() {
+				BeforeEach(func() {
+					body = strings.NewReader(`{"name":"fish PIE"}`)
+				})
+
+				It("returns a conflict listing it", func() {
+					Expect(recorder.Result().StatusCode).To(Equal(http.StatusConflict))
+					Expect(problemCode(recorder)).To(Equal(problem.DuplicateRecipe))
+					Expect(conflicts(recorder)).To(Equal([]problem.Conflict{{ID: 4, Name: "Fish pie"}}))
+					Expect(recipeStore.InsertCallCount()).To(BeZero())
+				})
+
+				When("similar recipes are allowed", func() {
+					BeforeEach(func() {
+						url = "/recipes?allowSimilar=true"
+					})
+
+					It("still returns a conflict", func() {
+						Expect(recorder.Result().StatusCode).To(Equal(http.StatusConflict))
+						Expect(problemCode(recorder)).To(Equal(problem.DuplicateRecipe))
+					})
+				})
+			})
+
+			When("one looks like the same dish", func() {
+				BeforeEach(func() {
+					body = strings.NewReader(`{"name":"Spag bol"}`)
+				})
+
+				It("returns a conflict listing it", func() {
+					Expect(recorder.Result().StatusCode).To(Equal(http.StatusConflict))
+					Expect(problemCode(recorder)).To(Equal(problem.SimilarRecipes))
+					Expect(conflicts(recorder)).To(Equal([]problem.Conflict{{ID: 3, Name: "Spaghetti Bolognese"}}))
+					Expect(recipeStore.InsertCallCount()).To(BeZero())
+				})
+
+				When("similar recipes are allowed", func() {
+					BeforeEach(func() {
+						url = "/recipes?allowSimilar=true"
+					})
+
+					It("saves it", func() {
+						Expect(recorder.Result().StatusCode).To(Equal(http.StatusCreated))
+						Expect(recipeStore.InsertCallCount()).To(Equal(1))
+					})
+				})
+			})
+
+			When("allowSimilar isn't true or false", func() {
+				BeforeEach(func() {
+					url = "/recipes?allowSimilar=maybe"
+				})
+
+				It("returns a bad request status", func() {
+					Expect(recorder.Result().StatusCode).To(Equal(http.StatusBadRequest))
+					Expect(problemCode(recorder)).To(Equal(problem.InvalidParameter))
+				})
+			})
+
+			When("the store finds the name is taken", func() {
+				BeforeEach(func() {
+					recipeStore.InsertReturns(models.Recipe{}, errors.New("taken"))
+					recipeStore.IsDuplicateErrReturns(true)
+				})
+
+				It("returns a conflict", func() {
+					Expect(recorder.Result().StatusCode).To(Equal(http.StatusConflict))
+					Expect(problemCode(recorder)).To(Equal(problem.DuplicateRecipe))
+				})
+			})
+
+			When("they can't be listed", func() {
+				BeforeEach(func() {
+					recipeStore.ListReturns(nil, errors.New("boom"))
+				})
+
+				It("doesn't save it", func() {
+					Expect(recorder.Result().StatusCode).To(Equal(http.StatusInternalServerError))
+					Expect(recipeStore.InsertCallCount()).To(BeZero())
+				})
+			})
+		})
+	})
+
+	Describe("MergeRecipes", func() {
+		var (
+			id   string
+			body string
+		)
+
+		BeforeEach(func() {
+			id = "345"
+			body = `{"duplicates": [12, 13]}`
+			sessionManager.GetReturns(&session.AuthInfo{IsLoggedIn: true, Name: "forest", ID: 234}, nil)
+			recipeStore.GetReturns(recipe1, nil)
+		})
+
+		JustBeforeEach(func() {
+			var err error
+			req, err = http.NewRequest(http.MethodPost, "/recipes/"+id+"/merge", strings.NewReader(body))
+			Expect(err).NotTo(HaveOccurred())
+			req = mux.SetURLVars(req, map[string]string{"id": id})
+			httpHandlers.MergeRecipes(recorder, req)
+		})
+
+		When("I'm logged out", func() {
+			BeforeEach(func() {
+				sessionManager.GetReturns(&session.AuthInfo{IsLoggedIn: false}, nil)
+			})
+
+			It("returns a status not auth'ed", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusUnauthorized))
+			})
+		})
+
+		It("merges each duplicate into the recipe in the unit of work", func() {
+			Expect(recorder.Result().StatusCode).To(Equal(http.StatusOK))
+			Expect(recipeStore.MergeCallCount()).To(Equal(2))
+			ctx, survivorID, duplicateID := recipeStore.MergeArgsForCall(0)
+			Expect(ctx.Value(inTx{})).To(BeTrue())
+			Expect(survivorID).To(Equal(345))
+			Expect(duplicateID).To(Equal(12))
+			_, survivorID, duplicateID = recipeStore.MergeArgsForCall(1)
+			Expect(survivorID).To(Equal(345))
+			Expect(duplicateID).To(Equal(13))
+		})
+
+		It("checks I own them all first", func() {
+			Expect(recipeStore.GetCallCount()).To(Equal(4))
+			for i, recipeID := range []int{345, 12, 13} {
+				_, userID, gotID := recipeStore.GetArgsForCall(i)
+				Expect(userID).To(Equal(234))
+				Expect(gotID).To(Equal(recipeID))
+			}
+		})
+
+		It("returns the merged recipe", func() {
+			Expect(recorder.Body.String()).To(ContainSubstring(`"name":"Bob"`))
+		})
+
+		It("tells my other clients the recipe changed, the duplicates went and plans may have changed", func() {
+			events := []models.Event{}
+			for i := 0; i < publisher.PublishCallCount(); i++ {
+				_, userID, event := publisher.PublishArgsForCall(i)
+				Expect(userID).To(Equal(234))
+				events = append(events, event)
+			}
+			Expect(events).To(Equal([]models.Event{
+				{Type: models.RecipeChanged, RecipeID: 345},
+				{Type: models.RecipeDeleted, RecipeID: 12},
+				{Type: models.RecipeDeleted, RecipeID: 13},
+				{Type: models.PlanChanged},
+				{Type: models.ShoppingListChanged},
+			}))
+		})
+
+		When("a duplicate isn't mine", func() {
+			BeforeEach(func() {
+				recipeStore.GetReturnsOnCall(2, models.Recipe{}, db.NotFoundErr())
+			})
+
+			It("merges none of them", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusNotFound))
+				Expect(recipeStore.MergeCallCount()).To(BeZero())
+				Expect(publisher.PublishCallCount()).To(BeZero())
+			})
+		})
+
+		When("no duplicates are listed", func() {
+			BeforeEach(func() {
+				body = `{"duplicates": []}`
+			})
+
+			It("returns an unprocessable entity status", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusUnprocessableEntity))
+				Expect(fieldErrors(recorder)).To(Equal([]problem.FieldError{{Field: "duplicates", Message: "is required"}}))
+			})
+		})
+
+		When("the recipe is listed as its own duplicate", func() {
+			BeforeEach(func() {
+				body = `{"duplicates": [12, 345, 12]}`
+			})
+
+			It("says which are wrong", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusUnprocessableEntity))
+				Expect(fieldErrors(recorder)).To(Equal([]problem.FieldError{
+					{Field: "duplicates[1]", Message: "must not be the recipe merged into"},
+					{Field: "duplicates[2]", Message: "is listed twice"},
+				}))
+				Expect(recipeStore.MergeCallCount()).To(BeZero())
+			})
+		})
+
+		When("the store fails", func() {
+			BeforeEach(func() {
+				recipeStore.MergeReturns(errors.New("boom"))
+			})
+
+			It("returns an internal server error", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusInternalServerError))
+			})
+		})
+	})
+
+	Describe("RateRecipe", func() {
+		var (
+			id   string
+			body string
+		)
+
+		BeforeEach(func() {
+			id = "345"
+			body = `{"rating": 4, "favourite": true}`
+			sessionManager.GetReturns(&session.AuthInfo{IsLoggedIn: true, Name: "forest", ID: 234}, nil)
+			recipeStore.GetReturns(recipe1, nil)
+		})
+
+		JustBeforeEach(func() {
+			var err error
+			req, err = http.NewRequest(http.MethodPut, "/recipes/"+id+"/rating", strings.NewReader(body))
+			Expect(err).NotTo(HaveOccurred())
+			req = mux.SetURLVars(req, map[string]string{"id": id})
+			httpHandlers.RateRecipe(recorder, req)
+		})
+
+		When("I'm logged out", func() {
+			BeforeEach(func() {
+				sessionManager.GetReturns(&session.AuthInfo{IsLoggedIn: false}, nil)
+			})
+
+			It("returns a status not auth'ed", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusUnauthorized))
+			})
+		})
+
+		It("records the user's rating", func() {
+			Expect(recorder.Result().StatusCode).To(Equal(http.StatusOK))
+			_, userID, recipeID := recipeStore.GetArgsForCall(0)
+			Expect(userID).To(Equal(234))
+			Expect(recipeID).To(Equal(345))
+
+			Expect(ratingStore.RateCallCount()).To(Equal(1))
+			_, rating := ratingStore.RateArgsForCall(0)
+			Expect(rating).To(Equal(models.Rating{RecipeID: 345, UserID: 234, Rating: 4, Favourite: true}))
+			Expect(recorder.Body.String()).To(MatchJSON(`{"rating": 4, "favourite": true}`))
+
+			Expect(publisher.PublishCallCount()).To(Equal(1))
+			_, _, event := publisher.PublishArgsForCall(0)
+			Expect(event).To(Equal(models.Event{Type: models.RecipeChanged, RecipeID: 345}))
+		})
+
+		When("the rating is out of range", func() {
+			BeforeEach(func() {
+				body = `{"rating": -1}`
+			})
+
+			It("returns an unprocessable entity status", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusUnprocessableEntity))
+				Expect(problemCode(recorder)).To(Equal(problem.ValidationFailed))
+				Expect(ratingStore.RateCallCount()).To(BeZero())
+			})
+		})
+
+		When("the recipe isn't mine", func() {
+			BeforeEach(func() {
+				recipeStore.GetReturns(models.Recipe{}, db.NotFoundErr())
+			})
+
+			It("returns a not found status", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusNotFound))
+				Expect(ratingStore.RateCallCount()).To(BeZero())
+			})
+		})
+
+		When("the store fails", func() {
+			BeforeEach(func() {
+				ratingStore.RateReturns(models.Rating{}, errors.New("boom"))
+			})
+
+			It("returns an internal server error", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusInternalServerError))
+			})
 		})
 	})
 })
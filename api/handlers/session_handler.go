@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/kieron-pivotal/menu-planner-app/models"
+	"github.com/kieron-pivotal/menu-planner-app/problem"
+)
+
+//counterfeiter:generate . SessionRevoker
+
+type SessionRevoker interface {
+	IsNotFoundErr(error) bool
+	List(ctx context.Context, userID int) ([]models.Session, error)
+	Revoke(ctx context.Context, userID int, id string) error
+	RevokeAll(ctx context.Context, userID int) error
+}
+
+type SessionHandler struct {
+	sessionManager SessionManager
+	sessionRevoker SessionRevoker
+}
+
+func NewSessionHandler(sessionManager SessionManager, sessionRevoker SessionRevoker) *SessionHandler {
+	return &SessionHandler{
+		sessionManager: sessionManager,
+		sessionRevoker: sessionRevoker,
+	}
+}
+
+func (h *SessionHandler) ListSessions(w http.ResponseWriter, r *http.Request) {
+	sess, err := h.sessionManager.Get(r.Context())
+	if err != nil || sess == nil || !sess.IsLoggedIn {
+		problem.Write(w, http.StatusUnauthorized, problem.Unauthorized, "")
+
+		return
+	}
+
+	sessions, err := h.sessionRevoker.List(r.Context(), sess.ID)
+	if err != nil {
+		log.Printf("session-list: %v\n", err)
+		problem.Write(w, http.StatusInternalServerError, problem.Internal, "")
+
+		return
+	}
+
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID == sess.SessionID
+	}
+
+	w.Header().Add("Content-Type", "application/json")
+
+	if err = json.NewEncoder(w).Encode(sessions); err != nil {
+		problem.Write(w, http.StatusInternalServerError, problem.Internal, "json encoding failure")
+
+		return
+	}
+}
+
+func (h *SessionHandler) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	sess, err := h.sessionManager.Get(r.Context())
+	if err != nil || sess == nil || !sess.IsLoggedIn {
+		problem.Write(w, http.StatusUnauthorized, problem.Unauthorized, "")
+
+		return
+	}
+
+	if err = h.sessionRevoker.Revoke(r.Context(), sess.ID, mux.Vars(r)["id"]); err != nil {
+		if h.sessionRevoker.IsNotFoundErr(err) {
+			problem.Write(w, http.StatusNotFound, problem.NotFound, "")
+
+			return
+		}
+
+		log.Printf("session-revoke: %v\n", err)
+		problem.Write(w, http.StatusInternalServerError, problem.Internal, "")
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *SessionHandler) RevokeAllSessions(w http.ResponseWriter, r *http.Request) {
+	sess, err := h.sessionManager.Get(r.Context())
+	if err != nil || sess == nil || !sess.IsLoggedIn {
+		problem.Write(w, http.StatusUnauthorized, problem.Unauthorized, "")
+
+		return
+	}
+
+	if err = h.sessionRevoker.RevokeAll(r.Context(), sess.ID); err != nil {
+		log.Printf("session-revoke-all: %v\n", err)
+		problem.Write(w, http.StatusInternalServerError, problem.Internal, "")
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,171 @@
+package handlers_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/gorilla/mux"
+	"github.com/kieron-pivotal/menu-planner-app/db"
+	"github.com/kieron-pivotal/menu-planner-app/handlers"
+	"github.com/kieron-pivotal/menu-planner-app/handlers/handlersfakes"
+	"github.com/kieron-pivotal/menu-planner-app/models"
+	"github.com/kieron-pivotal/menu-planner-app/session"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SessionHandler", func() {
+	var (
+		sessionManager *handlersfakes.FakeSessionManager
+		sessionRevoker *handlersfakes.FakeSessionRevoker
+		recorder       *httptest.ResponseRecorder
+		req            *http.Request
+		httpHandlers   *handlers.SessionHandler
+		hf             http.HandlerFunc
+	)
+
+	BeforeEach(func() {
+		sessionManager = new(handlersfakes.FakeSessionManager)
+		sessionManager.GetReturns(&session.AuthInfo{IsLoggedIn: true, Name: "forest", ID: 234, SessionID: "current"}, nil)
+		sessionRevoker = new(handlersfakes.FakeSessionRevoker)
+		httpHandlers = handlers.NewSessionHandler(sessionManager, sessionRevoker)
+		recorder = httptest.NewRecorder()
+	})
+
+	Describe("ListSessions", func() {
+		BeforeEach(func() {
+			hf = http.HandlerFunc(httpHandlers.ListSessions)
+			sessionRevoker.ListReturns([]models.Session{
+				{ID: "current", UserAgent: "firefox", IP: "10.0.0.1"},
+				{ID: "other", UserAgent: "chrome", IP: "10.0.0.2"},
+			}, nil)
+		})
+
+		JustBeforeEach(func() {
+			var err error
+			req, err = http.NewRequest(http.MethodGet, "/sessions", nil)
+			Expect(err).NotTo(HaveOccurred())
+			hf.ServeHTTP(recorder, req)
+		})
+
+		When("I'm logged out", func() {
+			BeforeEach(func() {
+				sessionManager.GetReturns(&session.AuthInfo{IsLoggedIn: false}, nil)
+			})
+
+			It("returns a status not auth'ed", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusUnauthorized))
+			})
+		})
+
+		It("lists sessions using user ID", func() {
+			Expect(sessionRevoker.ListCallCount()).To(Equal(1))
+			_, userID := sessionRevoker.ListArgsForCall(0)
+			Expect(userID).To(Equal(234))
+		})
+
+		It("formats the sessions as JSON, flagging the current one", func() {
+			Expect(recorder.Result().StatusCode).To(Equal(http.StatusOK))
+			Expect(recorder.Header().Get("Content-Type")).To(Equal("application/json"))
+			Expect(recorder.Body.String()).To(SatisfyAll(
+				ContainSubstring(`"id":"current","userAgent":"firefox","ip":"10.0.0.1"`),
+				MatchRegexp(`"id":"current".*"current":true`),
+				MatchRegexp(`"id":"other".*"current":false`),
+			))
+		})
+
+		When("listing fails", func() {
+			BeforeEach(func() {
+				sessionRevoker.ListReturns(nil, errors.New("oops"))
+			})
+
+			It("fails with internal server error", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusInternalServerError))
+			})
+		})
+	})
+
+	Describe("RevokeSession", func() {
+		BeforeEach(func() {
+			hf = http.HandlerFunc(httpHandlers.RevokeSession)
+		})
+
+		JustBeforeEach(func() {
+			var err error
+			req, err = http.NewRequest(http.MethodDelete, "/sessions/other", nil)
+			Expect(err).NotTo(HaveOccurred())
+			req = mux.SetURLVars(req, map[string]string{"id": "other"})
+			hf.ServeHTTP(recorder, req)
+		})
+
+		When("I'm logged out", func() {
+			BeforeEach(func() {
+				sessionManager.GetReturns(nil, errors.New("no session"))
+			})
+
+			It("returns a status not auth'ed", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusUnauthorized))
+			})
+		})
+
+		It("revokes the session for the user", func() {
+			Expect(recorder.Result().StatusCode).To(Equal(http.StatusNoContent))
+			Expect(sessionRevoker.RevokeCallCount()).To(Equal(1))
+			_, userID, id := sessionRevoker.RevokeArgsForCall(0)
+			Expect(userID).To(Equal(234))
+			Expect(id).To(Equal("other"))
+		})
+
+		When("the session doesn't exist", func() {
+			BeforeEach(func() {
+				sessionRevoker.RevokeReturns(db.NotFoundErr())
+				sessionRevoker.IsNotFoundErrReturns(true)
+			})
+
+			It("returns not found", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusNotFound))
+			})
+		})
+
+		When("revoking fails", func() {
+			BeforeEach(func() {
+				sessionRevoker.RevokeReturns(errors.New("oops"))
+			})
+
+			It("fails with internal server error", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusInternalServerError))
+			})
+		})
+	})
+
+	Describe("RevokeAllSessions", func() {
+		BeforeEach(func() {
+			hf = http.HandlerFunc(httpHandlers.RevokeAllSessions)
+		})
+
+		JustBeforeEach(func() {
+			var err error
+			req, err = http.NewRequest(http.MethodDelete, "/sessions", nil)
+			Expect(err).NotTo(HaveOccurred())
+			hf.ServeHTTP(recorder, req)
+		})
+
+		It("logs the user out everywhere", func() {
+			Expect(recorder.Result().StatusCode).To(Equal(http.StatusNoContent))
+			Expect(sessionRevoker.RevokeAllCallCount()).To(Equal(1))
+			_, userID := sessionRevoker.RevokeAllArgsForCall(0)
+			Expect(userID).To(Equal(234))
+		})
+
+		When("revoking fails", func() {
+			BeforeEach(func() {
+				sessionRevoker.RevokeAllReturns(errors.New("oops"))
+			})
+
+			It("fails with internal server error", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusInternalServerError))
+			})
+		})
+	})
+})
@@ -0,0 +1,252 @@
+package handlers
+
+import (
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/kieron-pivotal/menu-planner-app/models"
+	"github.com/kieron-pivotal/menu-planner-app/problem"
+	"github.com/kieron-pivotal/menu-planner-app/shopping"
+)
+
+//counterfeiter:generate . ShoppingListBuilder
+
+type ShoppingListBuilder interface {
+	Build(recipes []models.Recipe) models.ShoppingList
+}
+
+// shoppingFormats are the names the format query parameter accepts
+var shoppingFormats = map[string]string{
+	"json":     shopping.JSON,
+	"text":     shopping.Text,
+	"txt":      shopping.Text,
+	"markdown": shopping.Markdown,
+	"md":       shopping.Markdown,
+	"csv":      shopping.CSV,
+	"html":     shopping.HTML,
+}
+
+// shoppingMediaTypes are the formats the Accept header can ask for
+var shoppingMediaTypes = map[string]string{
+	"application/json": shopping.JSON,
+	"text/plain":       shopping.Text,
+	"text/markdown":    shopping.Markdown,
+	"text/csv":         shopping.CSV,
+	"text/html":        shopping.HTML,
+}
+
+type ShoppingHandler struct {
+	sessionManager SessionManager
+	recipeStore    RecipeStore
+	planStore      PlanStore
+	builder        ShoppingListBuilder
+}
+
+func NewShoppingHandler(sessionManager SessionManager, recipeStore RecipeStore, planStore PlanStore, builder ShoppingListBuilder) *ShoppingHandler {
+	return &ShoppingHandler{
+		sessionManager: sessionManager,
+		recipeStore:    recipeStore,
+		planStore:      planStore,
+		builder:        builder,
+	}
+}
+
+// ShoppingList lists what to buy for the comma separated recipe ids in
+// the recipes query parameter. The format parameter, or failing that the
+// Accept header, chooses JSON, plain text, a Markdown checklist, CSV or
+// a printable HTML page.
+func (h *ShoppingHandler) ShoppingList(w http.ResponseWriter, r *http.Request) {
+	sess, err := h.sessionManager.Get(r.Context())
+	if err != nil || sess == nil || !sess.IsLoggedIn {
+		problem.Write(w, http.StatusUnauthorized, problem.Unauthorized, "")
+
+		return
+	}
+
+	format, ok := shoppingFormat(w, r)
+	if !ok {
+		return
+	}
+
+	recipes, ok := queryRecipes(w, r, h.recipeStore, sess.ID)
+	if !ok {
+		return
+	}
+
+	h.write(w, format, recipes)
+}
+
+// PlanShoppingList lists what to buy for the user's plan for a week, in
+// the formats ShoppingList can write. Each slot which cooks its recipe
+// adds it to the list, and leftover slots add nothing.
+func (h *ShoppingHandler) PlanShoppingList(w http.ResponseWriter, r *http.Request) {
+	sess, err := h.sessionManager.Get(r.Context())
+	if err != nil || sess == nil || !sess.IsLoggedIn {
+		problem.Write(w, http.StatusUnauthorized, problem.Unauthorized, "")
+
+		return
+	}
+
+	week, ok := planWeek(w, r)
+	if !ok {
+		return
+	}
+
+	format, ok := shoppingFormat(w, r)
+	if !ok {
+		return
+	}
+
+	plan, err := h.planStore.Get(r.Context(), sess.ID, week)
+	if err != nil {
+		log.Printf("plan-get: %v\n", err)
+		problem.Write(w, http.StatusInternalServerError, problem.Internal, "")
+
+		return
+	}
+
+	cooked := []models.Slot{}
+	for _, slot := range plan.Slots {
+		if slot.LeftoverOf == nil {
+			cooked = append(cooked, slot)
+		}
+	}
+
+	recipes := []models.Recipe{}
+	if len(cooked) > 0 {
+		found, err := h.recipeStore.GetMany(r.Context(), sess.ID, slotRecipes(cooked))
+		if err != nil {
+			log.Printf("recipe-get-many: %v\n", err)
+			problem.Write(w, http.StatusInternalServerError, problem.Internal, "")
+
+			return
+		}
+
+		byID := map[int]models.Recipe{}
+		for _, recipe := range found {
+			byID[recipe.ID] = recipe
+		}
+		for _, slot := range cooked {
+			if recipe, ok := byID[slot.RecipeID]; ok {
+				recipes = append(recipes, recipe)
+			}
+		}
+	}
+
+	h.write(w, format, recipes)
+}
+
+// shoppingFormat is the format the format query parameter, or failing
+// that the Accept header, asks for. It writes a 400 for a format
+// parameter it doesn't know.
+func shoppingFormat(w http.ResponseWriter, r *http.Request) (string, bool) {
+	// the same URL gives a different body for a different Accept header
+	w.Header().Add("Vary", "Accept")
+
+	param := r.URL.Query().Get("format")
+	if param == "" {
+		return acceptedFormat(r.Header.Get("Accept")), true
+	}
+
+	format, ok := shoppingFormats[strings.ToLower(param)]
+	if !ok {
+		problem.Write(w, http.StatusBadRequest, problem.InvalidParameter, "format must be one of json, text, markdown, csv or html")
+
+		return "", false
+	}
+
+	return format, true
+}
+
+// write writes the shopping list for recipes in format
+func (h *ShoppingHandler) write(w http.ResponseWriter, format string, recipes []models.Recipe) {
+	w.Header().Add("Content-Type", shopping.ContentType(format))
+
+	if err := shopping.Write(w, format, h.builder.Build(recipes)); err != nil {
+		log.Printf("shopping-list: %v\n", err)
+	}
+}
+
+// queryRecipes gets the user's recipes listed in the recipes query
+// parameter in one batch, writing a 400 if an id isn't a number and a 404
+// if a recipe isn't the user's. An id listed twice gives the recipe twice.
+func queryRecipes(w http.ResponseWriter, r *http.Request, recipeStore RecipeStore, userID int) ([]models.Recipe, bool) {
+	ids := []int{}
+	for _, param := range strings.Split(r.URL.Query().Get("recipes"), ",") {
+		if param = strings.TrimSpace(param); param == "" {
+			continue
+		}
+
+		id, err := strconv.Atoi(param)
+		if err != nil {
+			problem.Write(w, http.StatusBadRequest, problem.InvalidParameter, "recipes must be a comma separated list of recipe ids")
+
+			return nil, false
+		}
+		ids = append(ids, id)
+	}
+
+	recipes := []models.Recipe{}
+	if len(ids) == 0 {
+		return recipes, true
+	}
+
+	found, err := recipeStore.GetMany(r.Context(), userID, ids)
+	if err != nil {
+		log.Printf("recipe-get-many: %v\n", err)
+		problem.Write(w, http.StatusInternalServerError, problem.Internal, "")
+
+		return nil, false
+	}
+
+	byID := map[int]models.Recipe{}
+	for _, recipe := range found {
+		byID[recipe.ID] = recipe
+	}
+
+	for _, id := range ids {
+		recipe, ok := byID[id]
+		if !ok {
+			problem.Write(w, http.StatusNotFound, problem.NotFound, "")
+
+			return nil, false
+		}
+		recipes = append(recipes, recipe)
+	}
+
+	return recipes, true
+}
+
+// acceptedFormat is the shopping list format an Accept header weights
+// highest, the earliest winning a tie, or JSON if there is none. Formats
+// weighted q=0 are never chosen.
+func acceptedFormat(accept string) string {
+	best, bestQ := "", 0.0
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		format, ok := shoppingMediaTypes[mediaType]
+		if !ok {
+			continue
+		}
+
+		q := 1.0
+		if param, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(param, 64); err != nil {
+				continue
+			}
+		}
+		if q > bestQ {
+			best, bestQ = format, q
+		}
+	}
+	if best != "" {
+		return best
+	}
+	return shopping.JSON
+}
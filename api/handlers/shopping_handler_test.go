@@ -0,0 +1,246 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/gorilla/mux"
+	"github.com/kieron-pivotal/menu-planner-app/handlers"
+	"github.com/kieron-pivotal/menu-planner-app/handlers/handlersfakes"
+	"github.com/kieron-pivotal/menu-planner-app/models"
+	"github.com/kieron-pivotal/menu-planner-app/session"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ShoppingHandler", func() {
+	var (
+		sessionManager *handlersfakes.FakeSessionManager
+		recipeStore    *handlersfakes.FakeRecipeStore
+		planStore      *handlersfakes.FakePlanStore
+		builder        *handlersfakes.FakeShoppingListBuilder
+		recorder       *httptest.ResponseRecorder
+		req            *http.Request
+		httpHandlers   *handlers.ShoppingHandler
+		handle         http.HandlerFunc
+		url            string
+		vars           map[string]string
+		accept         string
+	)
+
+	BeforeEach(func() {
+		sessionManager = new(handlersfakes.FakeSessionManager)
+		sessionManager.GetReturns(&session.AuthInfo{IsLoggedIn: true, Name: "forest", ID: 234}, nil)
+		recipeStore = new(handlersfakes.FakeRecipeStore)
+		recipeStore.GetManyStub = func(_ context.Context, _ int, ids []int) ([]models.Recipe, error) {
+			recipes := []models.Recipe{}
+			for _, id := range ids {
+				recipes = append(recipes, models.Recipe{ID: id})
+			}
+			return recipes, nil
+		}
+		builder = new(handlersfakes.FakeShoppingListBuilder)
+		builder.BuildReturns(models.ShoppingList{Categories: []models.ShoppingCategory{
+			{Name: "Fruit & veg", Items: []models.ShoppingItem{{Name: "potatoes", Quantity: 1.5, Unit: "kg"}}},
+		}})
+		planStore = new(handlersfakes.FakePlanStore)
+		httpHandlers = handlers.NewShoppingHandler(sessionManager, recipeStore, planStore, builder)
+		recorder = httptest.NewRecorder()
+		handle = httpHandlers.ShoppingList
+		url = "/shopping-list?recipes=3,4,3"
+		vars = nil
+		accept = ""
+	})
+
+	JustBeforeEach(func() {
+		var err error
+		req, err = http.NewRequest(http.MethodGet, url, nil)
+		Expect(err).NotTo(HaveOccurred())
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		if vars != nil {
+			req = mux.SetURLVars(req, vars)
+		}
+		handle(recorder, req)
+	})
+
+	When("I'm logged out", func() {
+		BeforeEach(func() {
+			sessionManager.GetReturns(&session.AuthInfo{IsLoggedIn: false}, nil)
+		})
+
+		It("returns a status not auth'ed", func() {
+			Expect(recorder.Result().StatusCode).To(Equal(http.StatusUnauthorized))
+		})
+	})
+
+	It("builds the list from the user's recipes, counting repeats", func() {
+		Expect(recorder.Result().StatusCode).To(Equal(http.StatusOK))
+		Expect(recipeStore.GetManyCallCount()).To(Equal(1))
+		Expect(recipeStore.GetCallCount()).To(BeZero())
+		_, userID, asked := recipeStore.GetManyArgsForCall(0)
+		Expect(userID).To(Equal(234))
+		Expect(asked).To(Equal([]int{3, 4, 3}))
+
+		Expect(builder.BuildCallCount()).To(Equal(1))
+		ids := []int{}
+		for _, r := range builder.BuildArgsForCall(0) {
+			ids = append(ids, r.ID)
+		}
+		Expect(ids).To(Equal([]int{3, 4, 3}))
+	})
+
+	It("returns JSON by default", func() {
+		Expect(recorder.Result().Header.Get("Content-Type")).To(Equal("application/json"))
+		Expect(recorder.Result().Header.Get("Vary")).To(Equal("Accept"))
+		list := models.ShoppingList{}
+		Expect(json.Unmarshal(recorder.Body.Bytes(), &list)).To(Succeed())
+		Expect(list.Categories).To(HaveLen(1))
+		Expect(list.Categories[0].Items[0].Name).To(Equal("potatoes"))
+	})
+
+	When("a format is requested", func() {
+		BeforeEach(func() {
+			url += "&format=md"
+			accept = "text/csv"
+		})
+
+		It("prefers it to the Accept header", func() {
+			Expect(recorder.Result().Header.Get("Content-Type")).To(Equal("text/markdown; charset=utf-8"))
+			Expect(recorder.Body.String()).To(Equal("## Fruit & veg\n\n- [ ] 1.5 kg potatoes\n"))
+		})
+	})
+
+	When("the format is unknown", func() {
+		BeforeEach(func() {
+			url += "&format=pdf"
+		})
+
+		It("returns bad request", func() {
+			Expect(recorder.Result().StatusCode).To(Equal(http.StatusBadRequest))
+			Expect(builder.BuildCallCount()).To(Equal(0))
+		})
+	})
+
+	When("the Accept header asks for a format", func() {
+		BeforeEach(func() {
+			accept = "application/pdf, text/plain;q=0.9, */*;q=0.1"
+		})
+
+		It("uses the one it knows", func() {
+			Expect(recorder.Result().Header.Get("Content-Type")).To(Equal("text/plain; charset=utf-8"))
+			Expect(recorder.Result().Header.Get("Vary")).To(Equal("Accept"))
+			Expect(recorder.Body.String()).To(Equal("Fruit & veg\n- 1.5 kg potatoes\n"))
+		})
+
+		When("it refuses a format with q=0", func() {
+			BeforeEach(func() {
+				accept = "text/plain;q=0, application/json"
+			})
+
+			It("uses another", func() {
+				Expect(recorder.Result().Header.Get("Content-Type")).To(Equal("application/json"))
+			})
+		})
+
+		When("it weights the formats it asks for", func() {
+			BeforeEach(func() {
+				accept = "text/csv;q=0.1, application/json;q=0.9"
+			})
+
+			It("uses the one weighted highest", func() {
+				Expect(recorder.Result().Header.Get("Content-Type")).To(Equal("application/json"))
+			})
+		})
+	})
+
+	When("a recipe id isn't a number", func() {
+		BeforeEach(func() {
+			url = "/shopping-list?recipes=3,four"
+		})
+
+		It("returns bad request", func() {
+			Expect(recorder.Result().StatusCode).To(Equal(http.StatusBadRequest))
+		})
+	})
+
+	When("a recipe isn't found", func() {
+		BeforeEach(func() {
+			recipeStore.GetManyStub = nil
+			recipeStore.GetManyReturns([]models.Recipe{{ID: 3}}, nil)
+		})
+
+		It("returns not found", func() {
+			Expect(recorder.Result().StatusCode).To(Equal(http.StatusNotFound))
+			Expect(builder.BuildCallCount()).To(Equal(0))
+		})
+	})
+
+	When("getting a recipe fails", func() {
+		BeforeEach(func() {
+			recipeStore.GetManyStub = nil
+			recipeStore.GetManyReturns(nil, errors.New("boom"))
+		})
+
+		It("returns an internal server error", func() {
+			Expect(recorder.Result().StatusCode).To(Equal(http.StatusInternalServerError))
+		})
+	})
+
+	Describe("a week's plan", func() {
+		BeforeEach(func() {
+			week := "2026-10-19"
+			planStore.GetReturns(models.Plan{Week: week, Slots: []models.Slot{
+				{Day: 0, Meal: models.Dinner, RecipeID: 3, Portions: 4},
+				{Day: 1, Meal: models.Lunch, RecipeID: 3, LeftoverOf: &models.SlotRef{Day: 0, Meal: models.Dinner}},
+				{Day: 1, Meal: models.Dinner, RecipeID: 4},
+				{Day: 3, Meal: models.Dinner, RecipeID: 3},
+			}}, nil)
+			handle = httpHandlers.PlanShoppingList
+			url = "/plans/" + week + "/shopping-list"
+			vars = map[string]string{"week": week}
+		})
+
+		It("builds the list from the slots which cook, leaving out leftovers", func() {
+			Expect(recorder.Result().StatusCode).To(Equal(http.StatusOK))
+			_, userID, asked := planStore.GetArgsForCall(0)
+			Expect(userID).To(Equal(234))
+			Expect(asked).To(Equal("2026-10-19"))
+
+			Expect(recipeStore.GetManyCallCount()).To(Equal(1))
+			_, _, ids := recipeStore.GetManyArgsForCall(0)
+			Expect(ids).To(Equal([]int{3, 4, 3}))
+
+			built := []int{}
+			for _, r := range builder.BuildArgsForCall(0) {
+				built = append(built, r.ID)
+			}
+			Expect(built).To(Equal([]int{3, 4, 3}))
+			Expect(recorder.Result().Header.Get("Content-Type")).To(Equal("application/json"))
+		})
+
+		When("the week isn't a Monday", func() {
+			BeforeEach(func() {
+				vars["week"] = "2026-10-20"
+			})
+
+			It("returns bad request", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusBadRequest))
+			})
+		})
+
+		When("getting the plan fails", func() {
+			BeforeEach(func() {
+				planStore.GetReturns(models.Plan{}, errors.New("boom"))
+			})
+
+			It("returns an internal server error", func() {
+				Expect(recorder.Result().StatusCode).To(Equal(http.StatusInternalServerError))
+			})
+		})
+	})
+})
@@ -0,0 +1,303 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/kieron-pivotal/menu-planner-app/models"
+	"github.com/kieron-pivotal/menu-planner-app/problem"
+)
+
+const (
+	// maxBodyBytes limits request bodies, apart from recipes, which get
+	// maxRecipeBytes for their methods
+	maxBodyBytes   = 16 << 10
+	maxRecipeBytes = 256 << 10
+
+	// maxNameLen and maxUnitLen are the sizes of the VARCHAR columns
+	// names and units are stored in, and maxTextLen a limit on the TEXT
+	// columns for instructions and notes
+	maxNameLen = 200
+	maxUnitLen = 50
+	maxTextLen = 10000
+
+	maxRating = 5
+)
+
+const unknownFieldPrefix = "json: unknown field "
+
+// decodeJSON decodes a request body of at most limit bytes into v, which
+// is left alone if the body is empty. It writes a 413 if the body is too
+// big, a 400 if it isn't JSON, and a 422 if it has fields v doesn't or
+// values of the wrong type, and reports whether v can be used.
+func decodeJSON(w http.ResponseWriter, r *http.Request, v interface{}, limit int64) bool {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, limit))
+	dec.DisallowUnknownFields()
+
+	err := dec.Decode(v)
+	if err == io.EOF {
+		return true
+	}
+	if err == nil && dec.More() {
+		err = errors.New("body must be a single JSON value")
+	}
+	if err == nil {
+		return true
+	}
+
+	var tooLarge *http.MaxBytesError
+	var wrongType *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &tooLarge):
+		problem.Write(w, http.StatusRequestEntityTooLarge, problem.BodyTooLarge,
+			fmt.Sprintf("body must be at most %d bytes", tooLarge.Limit))
+	case errors.As(err, &wrongType):
+		problem.WriteInvalid(w, []problem.FieldError{{
+			Field:   wrongType.Field,
+			Message: "must be " + jsonType(wrongType.Type),
+		}})
+	case strings.HasPrefix(err.Error(), unknownFieldPrefix):
+		field, unquoteErr := strconv.Unquote(strings.TrimPrefix(err.Error(), unknownFieldPrefix))
+		if unquoteErr != nil {
+			field = strings.TrimPrefix(err.Error(), unknownFieldPrefix)
+		}
+		problem.WriteInvalid(w, []problem.FieldError{{Field: field, Message: "is not allowed"}})
+	default:
+		problem.Write(w, http.StatusBadRequest, problem.InvalidJSON, err.Error())
+	}
+	return false
+}
+
+// jsonType describes the JSON a Go type is decoded from, e.g. "a number"
+func jsonType(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "true or false"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	case reflect.Struct, reflect.Map:
+		return "an object"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "a whole number"
+	default:
+		return "a number"
+	}
+}
+
+// fieldErrors collects what is wrong with a request body
+type fieldErrors []problem.FieldError
+
+func (e *fieldErrors) add(field, message string) {
+	*e = append(*e, problem.FieldError{Field: field, Message: message})
+}
+
+// text checks a string isn't longer than max characters and, if it is
+// required, isn't blank
+func (e *fieldErrors) text(field, value string, required bool, max int) {
+	switch {
+	case required && strings.TrimSpace(value) == "":
+		e.add(field, "is required")
+	case utf8.RuneCountInString(value) > max:
+		e.add(field, fmt.Sprintf("must be at most %d characters", max))
+	}
+}
+
+func (e *fieldErrors) notNegative(field string, value float64) {
+	if value < 0 {
+		e.add(field, "must not be negative")
+	}
+}
+
+func (e *fieldErrors) rating(field string, value int) {
+	if value < 0 || value > maxRating {
+		e.add(field, fmt.Sprintf("must be between 0 and %d", maxRating))
+	}
+}
+
+func validateRecipe(recipe models.Recipe) []problem.FieldError {
+	e := fieldErrors{}
+	e.text("name", recipe.Name, true, maxNameLen)
+	e.notNegative("servings", float64(recipe.Servings))
+
+	for i, ingredient := range recipe.Ingredients {
+		field := fmt.Sprintf("ingredients[%d].", i)
+		e.text(field+"name", ingredient.Name, true, maxNameLen)
+		e.notNegative(field+"quantity", ingredient.Quantity)
+		e.text(field+"unit", ingredient.Unit, false, maxUnitLen)
+	}
+
+	for i, step := range recipe.Steps {
+		field := fmt.Sprintf("steps[%d].", i)
+		e.text(field+"instruction", step.Instruction, true, maxTextLen)
+		e.notNegative(field+"durationSeconds", float64(step.DurationSeconds))
+		for j, ref := range step.Ingredients {
+			if ref < 0 || ref >= len(recipe.Ingredients) {
+				e.add(fmt.Sprintf("%singredients[%d]", field, j), "must be the index of one of the recipe's ingredients")
+			}
+		}
+	}
+	return e
+}
+
+func validateRating(rating models.Rating) []problem.FieldError {
+	e := fieldErrors{}
+	e.rating("rating", rating.Rating)
+	return e
+}
+
+func validateCooked(cooked models.Cooked) []problem.FieldError {
+	e := fieldErrors{}
+	e.rating("rating", cooked.Rating)
+	e.text("notes", cooked.Notes, false, maxTextLen)
+	e.notNegative("leftovers", float64(cooked.Leftovers))
+	return e
+}
+
+func validatePrice(price models.Price) []problem.FieldError {
+	e := fieldErrors{}
+	e.text("ingredient", price.Ingredient, true, maxNameLen)
+	e.text("store", price.Store, false, maxNameLen)
+	e.notNegative("price", float64(price.Price))
+	e.notNegative("quantity", price.Quantity)
+	e.text("unit", price.Unit, false, maxUnitLen)
+	return e
+}
+
+func validateBudget(budget models.Budget) []problem.FieldError {
+	e := fieldErrors{}
+	e.notNegative("weekly", float64(budget.Weekly))
+	return e
+}
+
+// validateMerge checks the duplicates merged into recipe id
+func validateMerge(id int, duplicates []int) []problem.FieldError {
+	e := fieldErrors{}
+	if len(duplicates) == 0 {
+		e.add("duplicates", "is required")
+	}
+
+	seen := map[int]bool{}
+	for i, duplicate := range duplicates {
+		field := fmt.Sprintf("duplicates[%d]", i)
+		switch {
+		case duplicate == id:
+			e.add(field, "must not be the recipe merged into")
+		case seen[duplicate]:
+			e.add(field, "is listed twice")
+		}
+		seen[duplicate] = true
+	}
+	return e
+}
+
+// slot checks a slot, whose fields are named with the prefix field
+func (e *fieldErrors) slot(field string, slot models.Slot) {
+	if slot.Day < 0 || slot.Day > 6 {
+		e.add(field+"day", "must be between 0 (Monday) and 6 (Sunday)")
+	}
+	if !validMeal(slot.Meal) {
+		e.add(field+"meal", "must be breakfast, lunch or dinner")
+	}
+	if slot.RecipeID <= 0 && slot.LeftoverOf == nil {
+		e.add(field+"recipeId", "is required")
+	}
+	e.notNegative(field+"portions", float64(slot.Portions))
+}
+
+func (e *fieldErrors) slots(slots []models.Slot) {
+	seen := map[models.SlotRef]bool{}
+	for i, slot := range slots {
+		e.slot(fmt.Sprintf("slots[%d].", i), slot)
+
+		if seen[slot.Ref()] {
+			e.add(fmt.Sprintf("slots[%d]", i), "is for the same meal as an earlier slot")
+		}
+		seen[slot.Ref()] = true
+	}
+}
+
+// leftovers checks that each leftover slot follows a cooked slot of the
+// plan, and has its recipe if it names one
+func (e *fieldErrors) leftovers(slots []models.Slot) {
+	cooked := map[models.SlotRef]models.Slot{}
+	for _, slot := range slots {
+		if slot.LeftoverOf == nil {
+			cooked[slot.Ref()] = slot
+		}
+	}
+
+	for i, slot := range slots {
+		if slot.LeftoverOf == nil {
+			continue
+		}
+
+		field := fmt.Sprintf("slots[%d].", i)
+		source, ok := cooked[*slot.LeftoverOf]
+		switch {
+		case !ok:
+			e.add(field+"leftoverOf", "must be a cooked slot of the plan")
+		case !slot.LeftoverOf.Before(slot.Ref()):
+			e.add(field+"leftoverOf", "must be earlier in the week")
+		case slot.RecipeID != 0 && slot.RecipeID != source.RecipeID:
+			e.add(field+"recipeId", "must be the recipe of the slot it is a leftover of")
+		}
+	}
+}
+
+// noLeftover rejects a leftover slot where only a week's plan can have one
+func (e *fieldErrors) noLeftover(field string, slot models.Slot) {
+	if slot.LeftoverOf != nil {
+		e.add(field+"leftoverOf", "is only allowed in a week's plan")
+	}
+}
+
+func validMeal(meal models.Meal) bool {
+	for _, m := range models.Meals {
+		if m == meal {
+			return true
+		}
+	}
+	return false
+}
+
+func validatePlan(plan models.Plan) []problem.FieldError {
+	e := fieldErrors{}
+	e.slots(plan.Slots)
+	e.leftovers(plan.Slots)
+	return e
+}
+
+func validatePlanTemplate(template models.PlanTemplate) []problem.FieldError {
+	e := fieldErrors{}
+	e.text("name", template.Name, true, maxNameLen)
+	e.slots(template.Slots)
+	for i, slot := range template.Slots {
+		e.noLeftover(fmt.Sprintf("slots[%d].", i), slot)
+	}
+	return e
+}
+
+func validatePlanRule(rule models.PlanRule) []problem.FieldError {
+	e := fieldErrors{}
+	e.slot("", rule.Slot)
+	e.noLeftover("", rule.Slot)
+	return e
+}
+
+func validateGraphQL(query string) []problem.FieldError {
+	e := fieldErrors{}
+	if strings.TrimSpace(query) == "" {
+		e.add("query", "is required")
+	}
+	return e
+}
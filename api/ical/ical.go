@@ -0,0 +1,114 @@
+/* Package ical writes RFC 5545 iCalendar feeds */
+package ical
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"time"
+)
+
+// maxLineOctets is the longest a content line may be before it is folded
+const maxLineOctets = 75
+
+const (
+	timeFormat         = "20060102T150405Z"
+	floatingTimeFormat = "20060102T150405"
+)
+
+// Calendar is a named feed of events
+type Calendar struct {
+	Name string
+	// Stamp is when the feed was generated
+	Stamp  time.Time
+	Events []Event
+}
+
+// Event is something which happens between Start and End. UID must be
+// unique and stable, so calendar apps can tell updates from new events.
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	URL         string
+	Start       time.Time
+	End         time.Time
+	// Floating events happen at Start and End's clock times in whichever
+	// time zone the calendar is viewed, as dinner is at 18:30 wherever
+	// you are
+	Floating bool
+}
+
+// Write writes the calendar in iCalendar format
+func Write(w io.Writer, cal Calendar) error {
+	bw := bufio.NewWriter(w)
+
+	line := func(name, value string) {
+		writeFolded(bw, name+":"+value)
+	}
+
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", "-//menu-planner//menu-planner//EN")
+	line("CALSCALE", "GREGORIAN")
+	line("METHOD", "PUBLISH")
+	if cal.Name != "" {
+		line("X-WR-CALNAME", escape(cal.Name))
+	}
+
+	for _, e := range cal.Events {
+		line("BEGIN", "VEVENT")
+		line("UID", escape(e.UID))
+		line("DTSTAMP", cal.Stamp.UTC().Format(timeFormat))
+		if e.Floating {
+			line("DTSTART", e.Start.Format(floatingTimeFormat))
+			line("DTEND", e.End.Format(floatingTimeFormat))
+		} else {
+			line("DTSTART", e.Start.UTC().Format(timeFormat))
+			line("DTEND", e.End.UTC().Format(timeFormat))
+		}
+		line("SUMMARY", escape(e.Summary))
+		if e.Description != "" {
+			line("DESCRIPTION", escape(e.Description))
+		}
+		if e.URL != "" {
+			line("URL", e.URL)
+		}
+		line("END", "VEVENT")
+	}
+
+	line("END", "VCALENDAR")
+
+	return bw.Flush()
+}
+
+var escaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+// escape escapes a TEXT value
+func escape(s string) string {
+	return escaper.Replace(s)
+}
+
+// writeFolded writes a content line ending in CRLF, folding it onto
+// continuation lines so none is longer than 75 octets. Lines are never
+// split inside a UTF-8 character.
+func writeFolded(w *bufio.Writer, s string) {
+	limit := maxLineOctets
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !startsRune(s[cut]) {
+			cut--
+		}
+		w.WriteString(s[:cut])
+		w.WriteString("\r\n ")
+		s = s[cut:]
+		// continuation lines start with a space, which counts
+		limit = maxLineOctets - 1
+	}
+	w.WriteString(s)
+	w.WriteString("\r\n")
+}
+
+func startsRune(b byte) bool {
+	return b&0xC0 != 0x80
+}
@@ -0,0 +1,95 @@
+package ical_test
+
+import (
+	"bytes"
+	"strings"
+	"time"
+
+	"github.com/kieron-pivotal/menu-planner-app/ical"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Ical", func() {
+	var (
+		cal ical.Calendar
+		out string
+	)
+
+	BeforeEach(func() {
+		cal = ical.Calendar{
+			Name:  "Meals",
+			Stamp: time.Date(2020, 5, 8, 9, 0, 0, 0, time.UTC),
+			Events: []ical.Event{{
+				UID:         "cooked-7@menu-planner",
+				Summary:     "Fish, chips; peas",
+				Description: "Rated 4/5\nmore vinegar",
+				URL:         "https://menus.example.com/recipes/3",
+				Start:       time.Date(2020, 5, 7, 18, 15, 0, 0, time.FixedZone("BST", 3600)),
+				End:         time.Date(2020, 5, 7, 18, 0, 0, 0, time.UTC),
+			}},
+		}
+	})
+
+	JustBeforeEach(func() {
+		buf := new(bytes.Buffer)
+		Expect(ical.Write(buf, cal)).To(Succeed())
+		out = buf.String()
+	})
+
+	It("writes a calendar with an event in UTC", func() {
+		Expect(out).To(Equal(strings.Join([]string{
+			"BEGIN:VCALENDAR",
+			"VERSION:2.0",
+			"PRODID:-//menu-planner//menu-planner//EN",
+			"CALSCALE:GREGORIAN",
+			"METHOD:PUBLISH",
+			"X-WR-CALNAME:Meals",
+			"BEGIN:VEVENT",
+			"UID:cooked-7@menu-planner",
+			"DTSTAMP:20200508T090000Z",
+			"DTSTART:20200507T171500Z",
+			"DTEND:20200507T180000Z",
+			`SUMMARY:Fish\, chips\; peas`,
+			`DESCRIPTION:Rated 4/5\nmore vinegar`,
+			"URL:https://menus.example.com/recipes/3",
+			"END:VEVENT",
+			"END:VCALENDAR",
+			"",
+		}, "\r\n")))
+	})
+
+	When("an event is floating", func() {
+		BeforeEach(func() {
+			cal.Events[0].Floating = true
+		})
+
+		It("writes its clock times without a time zone", func() {
+			Expect(out).To(ContainSubstring("DTSTART:20200507T181500\r\nDTEND:20200507T180000\r\n"))
+		})
+	})
+
+	When("a line is longer than 75 octets", func() {
+		BeforeEach(func() {
+			cal.Events[0].Summary = strings.Repeat("é", 60)
+		})
+
+		It("folds it without splitting characters", func() {
+			for _, line := range strings.Split(out, "\r\n") {
+				Expect(len(line)).To(BeNumerically("<=", 75))
+			}
+			Expect(out).To(ContainSubstring("SUMMARY:" + strings.Repeat("é", 33) + "\r\n " + strings.Repeat("é", 27) + "\r\n"))
+		})
+	})
+
+	When("there are no events", func() {
+		BeforeEach(func() {
+			cal.Events = nil
+		})
+
+		It("writes an empty calendar", func() {
+			Expect(out).To(HavePrefix("BEGIN:VCALENDAR\r\n"))
+			Expect(out).To(HaveSuffix("X-WR-CALNAME:Meals\r\nEND:VCALENDAR\r\n"))
+		})
+	})
+})
@@ -1,18 +1,18 @@
 package integration_test
 
 import (
+	"context"
 	"database/sql"
-	"fmt"
 	"net/http/httptest"
-	"os"
 	"testing"
 
 	"github.com/gorilla/securecookie"
 	"github.com/kieron-pivotal/menu-planner-app/db"
+	"github.com/kieron-pivotal/menu-planner-app/db/dbtest"
+	"github.com/kieron-pivotal/menu-planner-app/events"
 	"github.com/kieron-pivotal/menu-planner-app/handlers/handlersfakes"
 	"github.com/kieron-pivotal/menu-planner-app/jwt"
 	"github.com/kieron-pivotal/menu-planner-app/session"
-	_ "github.com/lib/pq"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 )
@@ -24,9 +24,20 @@ var (
 	audience       string
 	userStore      *db.UserStore
 	recipeStore    *db.RecipeStore
+	sessionStore   *db.SessionStore
+	priceStore     *db.PriceStore
+	budgetStore    *db.BudgetStore
+	cookLogStore   *db.CookLogStore
+	ratingStore    *db.RatingStore
+	calendarStore  *db.CalendarTokenStore
+	planStore      *db.PlanStore
+	templateStore  *db.PlanTemplateStore
+	eventHub       *events.Hub
 	jwtDecoder     *jwt.JWT
 	sessionManager *session.Manager
-	pg             *sql.DB
+	sessionKeys    [][]byte
+	sqlDB          *sql.DB
+	closeDB        func()
 	tx             *sql.Tx
 )
 
@@ -37,36 +48,47 @@ func TestIntegration(t *testing.T) {
 
 var _ = BeforeSuite(func() {
 	audience = "my-web-app-id"
-	connStr := mustGetEnv("DB_CONN_STR")
 
 	var err error
-	pg, err = sql.Open("postgres", connStr)
+	sqlDB, _, closeDB, err = dbtest.Open()
 	Expect(err).NotTo(HaveOccurred())
 
 	jwtDecoder = jwt.NewJWT()
 
-	keys := [][]byte{securecookie.GenerateRandomKey(32), securecookie.GenerateRandomKey(32)}
-	sessionManager = session.NewManager(keys)
+	sessionKeys = [][]byte{securecookie.GenerateRandomKey(32), securecookie.GenerateRandomKey(32)}
+})
+
+var _ = AfterSuite(func() {
+	closeDB()
 })
 
 var _ = BeforeEach(func() {
 	var err error
-	tx, err = pg.Begin()
+	tx, err = sqlDB.Begin()
 	Expect(err).NotTo(HaveOccurred())
 
 	userStore = db.NewUserStore(tx)
 	recipeStore = db.NewRecipeStore(tx)
+	sessionStore = db.NewSessionStore(tx)
+	priceStore = db.NewPriceStore(tx)
+	budgetStore = db.NewBudgetStore(tx)
+	cookLogStore = db.NewCookLogStore(tx)
+	ratingStore = db.NewRatingStore(tx)
+	calendarStore = db.NewCalendarTokenStore(tx)
+	planStore = db.NewPlanStore(tx)
+	templateStore = db.NewPlanTemplateStore(tx)
+	eventHub = events.NewHub()
+	sessionManager = session.NewManager(sessionKeys, sessionStore)
 })
 
 var _ = AfterEach(func() {
 	Expect(tx.Rollback()).To(Succeed())
 })
 
-func mustGetEnv(v string) string {
-	s := os.Getenv(v)
-	if s != "" {
-		return s
-	}
-	Fail(fmt.Sprintf("expected env var %q", v))
-	return ""
+// suiteTransactor runs units of work directly, as every spec already runs in
+// a transaction which is rolled back afterwards
+type suiteTransactor struct{}
+
+func (suiteTransactor) InTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
 }
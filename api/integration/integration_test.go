@@ -1,20 +1,30 @@
 package integration_test
 
 import (
+	"bufio"
 	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"time"
 
+	"github.com/kieron-pivotal/menu-planner-app/client"
+	"github.com/kieron-pivotal/menu-planner-app/costing"
 	"github.com/kieron-pivotal/menu-planner-app/handlers"
 	"github.com/kieron-pivotal/menu-planner-app/handlers/handlersfakes"
 	"github.com/kieron-pivotal/menu-planner-app/models"
+	"github.com/kieron-pivotal/menu-planner-app/nutrition"
+	"github.com/kieron-pivotal/menu-planner-app/printout"
+	"github.com/kieron-pivotal/menu-planner-app/problem"
 	"github.com/kieron-pivotal/menu-planner-app/routing"
+	"github.com/kieron-pivotal/menu-planner-app/shopping"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 )
@@ -24,23 +34,48 @@ var _ = Describe("Integration", func() {
 		frontendURI = "https://my.frontend.com"
 		tokenVerifier = new(handlersfakes.FakeTokenVerifier)
 
-		authHandler := handlers.NewAuthHandler(audience, tokenVerifier, jwtDecoder, userStore, sessionManager)
-		recipeHandler := handlers.NewRecipeHandler(sessionManager, recipeStore)
-		r := routing.New(frontendURI, sessionManager, authHandler, recipeHandler)
+		authHandler := handlers.NewAuthHandler(audience, tokenVerifier, jwtDecoder, userStore, suiteTransactor{}, sessionManager)
+		recipeHandler := handlers.NewRecipeHandler(sessionManager, recipeStore, suiteTransactor{}, ratingStore, nutrition.Default(), costing.NewEstimator(priceStore), eventHub)
+		sessionHandler := handlers.NewSessionHandler(sessionManager, sessionManager)
+		priceHandler := handlers.NewPriceHandler(sessionManager, priceStore, budgetStore)
+		cookLogHandler := handlers.NewCookLogHandler(sessionManager, recipeStore, cookLogStore, suiteTransactor{}, eventHub)
+		calendarHandler := handlers.NewCalendarHandler(sessionManager, calendarStore, recipeStore, planStore, frontendURI)
+		shoppingHandler := handlers.NewShoppingHandler(sessionManager, recipeStore, planStore, shopping.Default())
+		printHandler := handlers.NewPrintHandler(sessionManager, planStore, recipeStore, printout.A4)
+		planHandler := handlers.NewPlanHandler(sessionManager, planStore, templateStore, recipeStore, suiteTransactor{}, nutrition.Default(), costing.NewEstimator(priceStore), budgetStore, eventHub)
+		eventsHandler := handlers.NewEventsHandler(sessionManager, eventHub)
+		graphQLHandler := handlers.NewGraphQLHandler(sessionManager, planStore, recipeStore, cookLogStore, shopping.Default())
+		r := routing.New(routing.NewCORSPolicy(frontendURI), sessionManager, authHandler, recipeHandler, sessionHandler, priceHandler, cookLogHandler, calendarHandler, shoppingHandler, printHandler, planHandler, eventsHandler, graphQLHandler)
 		mockServer = httptest.NewServer(r.SetupRoutes())
 	})
 
+	withCSRF := func(req *http.Request) {
+		resp, err := http.Get(mockServer.URL + "/api/v1/csrf")
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+
+		cookies := resp.Cookies()
+		Expect(cookies).To(HaveLen(1))
+		req.AddCookie(cookies[0])
+		req.Header.Set("X-CSRF-Token", cookies[0].Value)
+	}
+
 	login := func() (*http.Response, error) {
 		jstr := `{"email":"foo@bar.com", "name":"foo bar"}`
 		b64str := base64.StdEncoding.EncodeToString([]byte(jstr))
 		loginData := fmt.Sprintf(`{"idToken": "xxx.%s.zzz"}`, b64str)
 
-		return http.Post(mockServer.URL+"/authGoogle", "application/json", bytes.NewBufferString(loginData))
+		req, err := http.NewRequest(http.MethodPost, mockServer.URL+"/api/v1/authGoogle", bytes.NewBufferString(loginData))
+		Expect(err).NotTo(HaveOccurred())
+		req.Header.Set("Content-Type", "application/json")
+		withCSRF(req)
+
+		return http.DefaultClient.Do(req)
 	}
 
 	Context("auth", func() {
 		It("cannot access /whoami un-authed", func() {
-			resp, err := http.Get(mockServer.URL + "/whoami")
+			resp, err := http.Get(mockServer.URL + "/api/v1/whoami")
 			Expect(err).NotTo(HaveOccurred())
 			Expect(resp.StatusCode).To(Equal(http.StatusUnauthorized))
 		})
@@ -56,7 +91,7 @@ var _ = Describe("Integration", func() {
 				cookies := resp.Cookies()
 				Expect(cookies).To(HaveLen(1))
 
-				req, err := http.NewRequest(http.MethodGet, mockServer.URL+"/whoami", nil)
+				req, err := http.NewRequest(http.MethodGet, mockServer.URL+"/api/v1/whoami", nil)
 				Expect(err).NotTo(HaveOccurred())
 				req.AddCookie(cookies[0])
 
@@ -85,7 +120,7 @@ var _ = Describe("Integration", func() {
 					cookies = resp.Cookies()
 					Expect(cookies).To(HaveLen(1))
 
-					req, err := http.NewRequest(http.MethodGet, mockServer.URL+"/whoami", nil)
+					req, err := http.NewRequest(http.MethodGet, mockServer.URL+"/api/v1/whoami", nil)
 					Expect(err).NotTo(HaveOccurred())
 					req.AddCookie(cookies[0])
 
@@ -97,9 +132,10 @@ var _ = Describe("Integration", func() {
 					cookies = resp.Cookies()
 					Expect(cookies).To(HaveLen(1))
 
-					req, err = http.NewRequest(http.MethodPost, mockServer.URL+"/logout", nil)
+					req, err = http.NewRequest(http.MethodPost, mockServer.URL+"/api/v1/logout", nil)
 					Expect(err).NotTo(HaveOccurred())
 					req.AddCookie(cookies[0])
+					withCSRF(req)
 
 					resp, err = http.DefaultClient.Do(req)
 					Expect(err).NotTo(HaveOccurred())
@@ -109,7 +145,7 @@ var _ = Describe("Integration", func() {
 					cookies = resp.Cookies()
 					Expect(cookies).To(HaveLen(2))
 
-					req, err = http.NewRequest(http.MethodGet, mockServer.URL+"/whoami", nil)
+					req, err = http.NewRequest(http.MethodGet, mockServer.URL+"/api/v1/whoami", nil)
 					Expect(err).NotTo(HaveOccurred())
 					req.AddCookie(cookies[1])
 
@@ -120,6 +156,54 @@ var _ = Describe("Integration", func() {
 					Expect(resp.StatusCode).To(Equal(http.StatusUnauthorized))
 				})
 			})
+
+			When("an auth'ed session logs out everywhere", func() {
+				It("revokes the other sessions", func() {
+					resp, err := login()
+					Expect(err).NotTo(HaveOccurred())
+					defer resp.Body.Close()
+					Expect(resp.StatusCode).To(Equal(http.StatusOK))
+					first := resp.Cookies()[0]
+
+					resp, err = login()
+					Expect(err).NotTo(HaveOccurred())
+					defer resp.Body.Close()
+					Expect(resp.StatusCode).To(Equal(http.StatusOK))
+					second := resp.Cookies()[0]
+
+					req, err := http.NewRequest(http.MethodGet, mockServer.URL+"/api/v1/sessions", nil)
+					Expect(err).NotTo(HaveOccurred())
+					req.AddCookie(first)
+
+					resp, err = http.DefaultClient.Do(req)
+					Expect(err).NotTo(HaveOccurred())
+					defer resp.Body.Close()
+					Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+					var sessions []models.Session
+					Expect(json.NewDecoder(resp.Body).Decode(&sessions)).To(Succeed())
+					Expect(sessions).To(HaveLen(2))
+
+					req, err = http.NewRequest(http.MethodDelete, mockServer.URL+"/api/v1/sessions", nil)
+					Expect(err).NotTo(HaveOccurred())
+					req.AddCookie(first)
+					withCSRF(req)
+
+					resp, err = http.DefaultClient.Do(req)
+					Expect(err).NotTo(HaveOccurred())
+					defer resp.Body.Close()
+					Expect(resp.StatusCode).To(Equal(http.StatusNoContent))
+
+					req, err = http.NewRequest(http.MethodGet, mockServer.URL+"/api/v1/whoami", nil)
+					Expect(err).NotTo(HaveOccurred())
+					req.AddCookie(second)
+
+					resp, err = http.DefaultClient.Do(req)
+					Expect(err).NotTo(HaveOccurred())
+					defer resp.Body.Close()
+					Expect(resp.StatusCode).To(Equal(http.StatusUnauthorized))
+				})
+			})
 		})
 	})
 
@@ -137,7 +221,7 @@ var _ = Describe("Integration", func() {
 		Context("GET /recipes", func() {
 			JustBeforeEach(func() {
 				var err error
-				req, err = http.NewRequest(http.MethodGet, mockServer.URL+"/recipes", nil)
+				req, err = http.NewRequest(http.MethodGet, mockServer.URL+"/api/v1/recipes", nil)
 				Expect(err).NotTo(HaveOccurred())
 
 				if cookie != nil {
@@ -186,8 +270,9 @@ var _ = Describe("Integration", func() {
 
 			JustBeforeEach(func() {
 				var err error
-				req, err = http.NewRequest(http.MethodPost, mockServer.URL+"/recipes", body)
+				req, err = http.NewRequest(http.MethodPost, mockServer.URL+"/api/v1/recipes", body)
 				Expect(err).NotTo(HaveOccurred())
+				withCSRF(req)
 
 				if cookie != nil {
 					req.AddCookie(cookie)
@@ -203,6 +288,23 @@ var _ = Describe("Integration", func() {
 				})
 			})
 
+			When("the csrf token is missing", func() {
+				JustBeforeEach(func() {
+					resp.Body.Close()
+
+					var err error
+					req, err = http.NewRequest(http.MethodPost, mockServer.URL+"/api/v1/recipes", strings.NewReader(`{"name":"Roast Beef"}`))
+					Expect(err).NotTo(HaveOccurred())
+
+					resp, err = http.DefaultClient.Do(req)
+					Expect(err).NotTo(HaveOccurred())
+				})
+
+				It("returns a forbidden status", func() {
+					Expect(resp.StatusCode).To(Equal(http.StatusForbidden))
+				})
+			})
+
 			When("auth'ed", func() {
 				BeforeEach(func() {
 					r, err := login()
@@ -228,7 +330,361 @@ var _ = Describe("Integration", func() {
 					Expect(recipe.Name).To(Equal("Roast Beef"))
 					Expect(recipe.ID).To(BeNumerically(">", 0))
 				})
+
+				When("another of my clients is listening for changes", func() {
+					var stream *http.Response
+
+					BeforeEach(func() {
+						req, err := http.NewRequest(http.MethodGet, mockServer.URL+"/api/v1/events", nil)
+						Expect(err).NotTo(HaveOccurred())
+						req.AddCookie(cookie)
+
+						stream, err = http.DefaultClient.Do(req)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(stream.StatusCode).To(Equal(http.StatusOK))
+						Expect(stream.Header.Get("Content-Type")).To(Equal("text/event-stream"))
+					})
+
+					AfterEach(func() {
+						stream.Body.Close()
+					})
+
+					It("tells it about the new recipe", func() {
+						var recipe models.Recipe
+						Expect(json.NewDecoder(resp.Body).Decode(&recipe)).To(Succeed())
+						resp.Body.Close()
+
+						lines := bufio.NewReader(stream.Body)
+						event, err := lines.ReadString('\n')
+						Expect(err).NotTo(HaveOccurred())
+						Expect(event).To(Equal("event: recipe-created\n"))
+						data, err := lines.ReadString('\n')
+						Expect(err).NotTo(HaveOccurred())
+						Expect(data).To(Equal(fmt.Sprintf("data: {\"type\":\"recipe-created\",\"recipeId\":%d}\n", recipe.ID)))
+					})
+				})
+
+				When("the recipe has a method", func() {
+					BeforeEach(func() {
+						body = strings.NewReader(`{
+							"name": "Roast Beef",
+							"servings": 6,
+							"ingredients": [{"name": "beef", "quantity": 1.5, "unit": "kg"}, {"name": "salt"}],
+							"steps": [
+								{"instruction": "Season the beef", "ingredients": [1, 0]},
+								{"instruction": "Roast", "durationSeconds": 5400, "ingredients": [0]}
+							]
+						}`)
+					})
+
+					It("returns it from the recipe's own endpoint", func() {
+						Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+						var created models.Recipe
+						Expect(json.NewDecoder(resp.Body).Decode(&created)).To(Succeed())
+
+						req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v1/recipes/%d", mockServer.URL, created.ID), nil)
+						Expect(err).NotTo(HaveOccurred())
+						req.AddCookie(cookie)
+						detail, err := http.DefaultClient.Do(req)
+						Expect(err).NotTo(HaveOccurred())
+						defer detail.Body.Close()
+						Expect(detail.StatusCode).To(Equal(http.StatusOK))
+
+						var recipe models.Recipe
+						Expect(json.NewDecoder(detail.Body).Decode(&recipe)).To(Succeed())
+						Expect(recipe.Ingredients).To(Equal([]models.Ingredient{
+							{Name: "beef", Quantity: 1.5, Unit: "kg"},
+							{Name: "salt"},
+						}))
+						Expect(recipe.Steps).To(Equal([]models.Step{
+							{Instruction: "Season the beef", Ingredients: []int{0, 1}},
+							{Instruction: "Roast", DurationSeconds: 5400, Ingredients: []int{0}},
+						}))
+						Expect(recipe.Servings).To(Equal(6))
+						Expect(recipe.Nutrition.PerServing.Calories).To(Equal(625.0))
+						Expect(recipe.Cost).To(Equal(&models.Cost{Missing: []string{"beef"}}))
+					})
+
+					It("estimates the cost from the user's prices", func() {
+						var created models.Recipe
+						Expect(json.NewDecoder(resp.Body).Decode(&created)).To(Succeed())
+
+						req, err := http.NewRequest(http.MethodPut, mockServer.URL+"/api/v1/prices",
+							strings.NewReader(`{"ingredient": "beef", "store": "butcher", "price": 1500, "quantity": 1, "unit": "kg"}`))
+						Expect(err).NotTo(HaveOccurred())
+						withCSRF(req)
+						req.AddCookie(cookie)
+						saved, err := http.DefaultClient.Do(req)
+						Expect(err).NotTo(HaveOccurred())
+						saved.Body.Close()
+						Expect(saved.StatusCode).To(Equal(http.StatusOK))
+
+						req, err = http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v1/recipes/%d", mockServer.URL, created.ID), nil)
+						Expect(err).NotTo(HaveOccurred())
+						req.AddCookie(cookie)
+						detail, err := http.DefaultClient.Do(req)
+						Expect(err).NotTo(HaveOccurred())
+						defer detail.Body.Close()
+
+						var recipe models.Recipe
+						Expect(json.NewDecoder(detail.Body).Decode(&recipe)).To(Succeed())
+						Expect(recipe.Cost).To(Equal(&models.Cost{Total: 2250, PerServing: 375}))
+					})
+
+					It("lists what to buy to cook it twice", func() {
+						var created models.Recipe
+						Expect(json.NewDecoder(resp.Body).Decode(&created)).To(Succeed())
+
+						req, err := http.NewRequest(http.MethodGet,
+							fmt.Sprintf("%s/api/v1/shopping-list?recipes=%d,%d&format=markdown", mockServer.URL, created.ID, created.ID), nil)
+						Expect(err).NotTo(HaveOccurred())
+						req.AddCookie(cookie)
+						list, err := http.DefaultClient.Do(req)
+						Expect(err).NotTo(HaveOccurred())
+						defer list.Body.Close()
+						Expect(list.StatusCode).To(Equal(http.StatusOK))
+						Expect(list.Header.Get("Content-Type")).To(Equal("text/markdown; charset=utf-8"))
+
+						b, err := ioutil.ReadAll(list.Body)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(string(b)).To(Equal("## Meat & fish\n\n- [ ] 3 kg beef\n\n## Cupboard\n\n- [ ] salt\n"))
+					})
+
+					It("prints it as a PDF", func() {
+						var created models.Recipe
+						Expect(json.NewDecoder(resp.Body).Decode(&created)).To(Succeed())
+
+						req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v1/recipes/%d/card.pdf", mockServer.URL, created.ID), nil)
+						Expect(err).NotTo(HaveOccurred())
+						req.AddCookie(cookie)
+						card, err := http.DefaultClient.Do(req)
+						Expect(err).NotTo(HaveOccurred())
+						defer card.Body.Close()
+						Expect(card.StatusCode).To(Equal(http.StatusOK))
+						Expect(card.Header.Get("Content-Type")).To(Equal("application/pdf"))
+
+						b, err := ioutil.ReadAll(card.Body)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(string(b)).To(HavePrefix("%PDF-"))
+						Expect(string(b)).To(ContainSubstring("(Roast \\(1 h 30 min\\)) Tj"))
+					})
+				})
+
+				When("a step uses an ingredient the recipe doesn't have", func() {
+					BeforeEach(func() {
+						body = strings.NewReader(`{"name": "Roast Beef", "steps": [{"instruction": "Roast", "ingredients": [0]}]}`)
+					})
+
+					It("returns an unprocessable entity status", func() {
+						Expect(resp.StatusCode).To(Equal(http.StatusUnprocessableEntity))
+					})
+				})
+
+				It("logs when the recipe is cooked", func() {
+					var created models.Recipe
+					Expect(json.NewDecoder(resp.Body).Decode(&created)).To(Succeed())
+
+					req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/api/v1/recipes/%d/cooked", mockServer.URL, created.ID),
+						strings.NewReader(`{"cookedAt": "2020-05-07T18:30:00Z", "rating": 4, "notes": "more garlic"}`))
+					Expect(err).NotTo(HaveOccurred())
+					withCSRF(req)
+					req.AddCookie(cookie)
+					cooked, err := http.DefaultClient.Do(req)
+					Expect(err).NotTo(HaveOccurred())
+					cooked.Body.Close()
+					Expect(cooked.StatusCode).To(Equal(http.StatusCreated))
+
+					req, err = http.NewRequest(http.MethodGet, fmt.Sprintf("%s/api/v1/recipes/%d/history", mockServer.URL, created.ID), nil)
+					Expect(err).NotTo(HaveOccurred())
+					req.AddCookie(cookie)
+					history, err := http.DefaultClient.Do(req)
+					Expect(err).NotTo(HaveOccurred())
+					defer history.Body.Close()
+
+					var entries []models.Cooked
+					Expect(json.NewDecoder(history.Body).Decode(&entries)).To(Succeed())
+					Expect(entries).To(HaveLen(1))
+					Expect(entries[0].Rating).To(Equal(4))
+					Expect(entries[0].Notes).To(Equal("more garlic"))
+
+					req, err = http.NewRequest(http.MethodGet, mockServer.URL+"/api/v1/recipes/suggestions", nil)
+					Expect(err).NotTo(HaveOccurred())
+					req.AddCookie(cookie)
+					suggestions, err := http.DefaultClient.Do(req)
+					Expect(err).NotTo(HaveOccurred())
+					defer suggestions.Body.Close()
+
+					var recipes []models.Recipe
+					Expect(json.NewDecoder(suggestions.Body).Decode(&recipes)).To(Succeed())
+					Expect(recipes).To(HaveLen(1))
+					Expect(recipes[0].ID).To(Equal(created.ID))
+					Expect(*recipes[0].LastCookedAt).To(BeTemporally("==", time.Date(2020, 5, 7, 18, 30, 0, 0, time.UTC)))
+				})
+
+				It("serves this week's plan as a calendar feed", func() {
+					var created models.Recipe
+					Expect(json.NewDecoder(resp.Body).Decode(&created)).To(Succeed())
+
+					now := time.Now()
+					monday := now.AddDate(0, 0, -(int(now.Weekday())+6)%7).Format("2006-01-02")
+					body := fmt.Sprintf(`{"slots": [{"day": 2, "meal": "dinner", "recipeId": %d}]}`, created.ID)
+					req, err := http.NewRequest(http.MethodPut, mockServer.URL+"/api/v1/plans/"+monday, strings.NewReader(body))
+					Expect(err).NotTo(HaveOccurred())
+					withCSRF(req)
+					req.AddCookie(cookie)
+					planned, err := http.DefaultClient.Do(req)
+					Expect(err).NotTo(HaveOccurred())
+					planned.Body.Close()
+					Expect(planned.StatusCode).To(Equal(http.StatusOK))
+
+					req, err = http.NewRequest(http.MethodPost, mockServer.URL+"/api/v1/profile/calendar-token", nil)
+					Expect(err).NotTo(HaveOccurred())
+					withCSRF(req)
+					req.AddCookie(cookie)
+					issued, err := http.DefaultClient.Do(req)
+					Expect(err).NotTo(HaveOccurred())
+					defer issued.Body.Close()
+					Expect(issued.StatusCode).To(Equal(http.StatusCreated))
+
+					var token struct{ Path string }
+					Expect(json.NewDecoder(issued.Body).Decode(&token)).To(Succeed())
+
+					feed, err := http.Get(mockServer.URL + token.Path)
+					Expect(err).NotTo(HaveOccurred())
+					defer feed.Body.Close()
+					Expect(feed.StatusCode).To(Equal(http.StatusOK))
+					b, err := ioutil.ReadAll(feed.Body)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(string(b)).To(ContainSubstring("UID:plan-" + monday + "-2-dinner@menu-planner\r\n"))
+					Expect(string(b)).To(ContainSubstring("SUMMARY:Roast Beef\r\n"))
+
+					req, err = http.NewRequest(http.MethodDelete, mockServer.URL+"/api/v1/profile/calendar-token", nil)
+					Expect(err).NotTo(HaveOccurred())
+					withCSRF(req)
+					req.AddCookie(cookie)
+					revoked, err := http.DefaultClient.Do(req)
+					Expect(err).NotTo(HaveOccurred())
+					revoked.Body.Close()
+					Expect(revoked.StatusCode).To(Equal(http.StatusNoContent))
+
+					feed, err = http.Get(mockServer.URL + token.Path)
+					Expect(err).NotTo(HaveOccurred())
+					feed.Body.Close()
+					Expect(feed.StatusCode).To(Equal(http.StatusNotFound))
+				})
+
+				It("scores the recipe from its ratings", func() {
+					var created models.Recipe
+					Expect(json.NewDecoder(resp.Body).Decode(&created)).To(Succeed())
+
+					req, err := http.NewRequest(http.MethodPut, fmt.Sprintf("%s/api/v1/recipes/%d/rating", mockServer.URL, created.ID),
+						strings.NewReader(`{"rating": 4, "favourite": true}`))
+					Expect(err).NotTo(HaveOccurred())
+					withCSRF(req)
+					req.AddCookie(cookie)
+					rated, err := http.DefaultClient.Do(req)
+					Expect(err).NotTo(HaveOccurred())
+					rated.Body.Close()
+					Expect(rated.StatusCode).To(Equal(http.StatusOK))
+
+					req, err = http.NewRequest(http.MethodGet, mockServer.URL+"/api/v1/recipes?sort=favourites", nil)
+					Expect(err).NotTo(HaveOccurred())
+					req.AddCookie(cookie)
+					list, err := http.DefaultClient.Do(req)
+					Expect(err).NotTo(HaveOccurred())
+					defer list.Body.Close()
+
+					b, err := ioutil.ReadAll(list.Body)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(string(b)).To(MatchJSON(fmt.Sprintf(
+						`[{"name": "Roast Beef", "id": %d, "score": 4, "lowestRating": 4, "favourite": true}]`, created.ID)))
+				})
+
+				It("spots the recipe being added again and merges copies", func() {
+					var created models.Recipe
+					Expect(json.NewDecoder(resp.Body).Decode(&created)).To(Succeed())
+
+					post := func(url, body string) *http.Response {
+						req, err := http.NewRequest(http.MethodPost, mockServer.URL+"/api/v1"+url, strings.NewReader(body))
+						Expect(err).NotTo(HaveOccurred())
+						withCSRF(req)
+						req.AddCookie(cookie)
+						r, err := http.DefaultClient.Do(req)
+						Expect(err).NotTo(HaveOccurred())
+						return r
+					}
+
+					same := post("/recipes", `{"name":"roast beef"}`)
+					same.Body.Close()
+					Expect(same.StatusCode).To(Equal(http.StatusConflict))
+
+					similar := post("/recipes", `{"name":"Rost beef"}`)
+					defer similar.Body.Close()
+					Expect(similar.StatusCode).To(Equal(http.StatusConflict))
+					p := problem.Problem{}
+					Expect(json.NewDecoder(similar.Body).Decode(&p)).To(Succeed())
+					Expect(p.Code).To(Equal(problem.SimilarRecipes))
+					Expect(p.Conflicts).To(Equal([]problem.Conflict{{ID: created.ID, Name: "Roast Beef"}}))
+
+					anyway := post("/recipes?allowSimilar=true", `{"name":"Rost beef"}`)
+					defer anyway.Body.Close()
+					Expect(anyway.StatusCode).To(Equal(http.StatusCreated))
+					var copied models.Recipe
+					Expect(json.NewDecoder(anyway.Body).Decode(&copied)).To(Succeed())
+
+					merged := post(fmt.Sprintf("/recipes/%d/merge", created.ID), fmt.Sprintf(`{"duplicates": [%d]}`, copied.ID))
+					merged.Body.Close()
+					Expect(merged.StatusCode).To(Equal(http.StatusOK))
+
+					req, err := http.NewRequest(http.MethodGet, mockServer.URL+"/api/v1/recipes", nil)
+					Expect(err).NotTo(HaveOccurred())
+					req.AddCookie(cookie)
+					list, err := http.DefaultClient.Do(req)
+					Expect(err).NotTo(HaveOccurred())
+					defer list.Body.Close()
+
+					b, err := ioutil.ReadAll(list.Body)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(string(b)).To(MatchJSON(fmt.Sprintf(`[{"name": "Roast Beef", "id": %d}]`, created.ID)))
+				})
 			})
 		})
 	})
+
+	Context("Go client", func() {
+		It("calls the API as described", func() {
+			ctx := context.Background()
+			c, err := client.New(mockServer.URL, nil)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = c.WhoAmI(ctx)
+			var clientErr *client.Error
+			Expect(errors.As(err, &clientErr)).To(BeTrue())
+			Expect(clientErr.Code).To(Equal(problem.Unauthorized))
+
+			claims := base64.StdEncoding.EncodeToString([]byte(`{"email":"foo@bar.com", "name":"foo bar"}`))
+			me, err := c.AuthGoogle(ctx, client.GoogleLogin{IDToken: "xxx." + claims + ".zzz"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(me.Name).To(Equal("foo bar"))
+
+			recipe, err := c.NewRecipe(ctx, client.NewRecipeParams{}, models.Recipe{
+				Name:        "Porridge",
+				Servings:    2,
+				Ingredients: []models.Ingredient{{Name: "oats", Quantity: 100, Unit: "g"}},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			got, err := c.GetRecipe(ctx, recipe.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(got.Ingredients).To(Equal([]models.Ingredient{{Name: "oats", Quantity: 100, Unit: "g"}}))
+
+			list, err := c.GetShoppingListAs(ctx, client.GetShoppingListParams{Recipes: []int{recipe.ID, recipe.ID}}, "text/plain")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(list)).To(ContainSubstring("200 g oats"))
+
+			card, err := c.GetRecipeCard(ctx, recipe.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(card)).To(HavePrefix("%PDF-"))
+		})
+	})
 })
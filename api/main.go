@@ -1,61 +1,242 @@
 package main
 
 import (
-	"database/sql"
+	"context"
+	"flag"
 	"fmt"
+	"io/fs"
 	"log"
 	"net/http"
 	"os"
-	"strconv"
+	"text/tabwriter"
+	"time"
 
 	googleAuthIDTokenVerifier "github.com/futurenda/google-auth-id-token-verifier"
-	"github.com/gorilla/securecookie"
+	"github.com/kieron-pivotal/menu-planner-app/config"
+	"github.com/kieron-pivotal/menu-planner-app/costing"
 	"github.com/kieron-pivotal/menu-planner-app/db"
+	"github.com/kieron-pivotal/menu-planner-app/db/migrations"
+	"github.com/kieron-pivotal/menu-planner-app/demo"
+	"github.com/kieron-pivotal/menu-planner-app/events"
 	"github.com/kieron-pivotal/menu-planner-app/handlers"
 	"github.com/kieron-pivotal/menu-planner-app/jwt"
+	"github.com/kieron-pivotal/menu-planner-app/memstore"
+	"github.com/kieron-pivotal/menu-planner-app/nutrition"
+	"github.com/kieron-pivotal/menu-planner-app/printout"
 	"github.com/kieron-pivotal/menu-planner-app/routing"
 	"github.com/kieron-pivotal/menu-planner-app/session"
-	_ "github.com/lib/pq"
+	"github.com/kieron-pivotal/menu-planner-app/shopping"
 )
 
-// TODO: get all these from env
-const (
-	aud    = "176462381984-bfq3v9mc00v0ipvpebiaiide4l22dmoh.apps.googleusercontent.com"
-	webURI = "http://localhost:3000"
-	port   = 8080
-)
+func main() {
+	cfg, err := config.Load(os.Args[1:], os.Getenv)
+	if cfg.PrintConfig {
+		if printErr := cfg.Print(os.Stdout); printErr != nil {
+			log.Fatal(printErr)
+		}
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	if cfg.PrintConfig {
+		return
+	}
 
-var (
-	sign    = securecookie.GenerateRandomKey(32)
-	encrypt = securecookie.GenerateRandomKey(32)
-)
+	if cfg.Demo {
+		if len(cfg.Args) > 0 {
+			log.Fatalf("command %q needs a database and can't be run in demo mode", cfg.Args[0])
+		}
+		log.Println("demo mode: data is kept in memory and lost when the server stops")
 
-func main() {
-	googleVerifier := new(googleAuthIDTokenVerifier.Verifier)
-	jwtDecoder := jwt.NewJWT()
+		memDB := memstore.New()
+		recipeStore := memstore.NewRecipeStore(memDB)
+		serve(cfg, stores{
+			users:      demo.NewUserStore(memstore.NewUserStore(memDB), recipeStore),
+			recipes:    recipeStore,
+			sessions:   memstore.NewSessionStore(memDB),
+			prices:     memstore.NewPriceStore(memDB),
+			budgets:    memstore.NewBudgetStore(memDB),
+			cookLog:    memstore.NewCookLogStore(memDB),
+			ratings:    memstore.NewRatingStore(memDB),
+			calendar:   memstore.NewCalendarTokenStore(memDB),
+			plans:      memstore.NewPlanStore(memDB),
+			templates:  memstore.NewPlanTemplateStore(memDB),
+			transactor: memstore.NewTransactor(memDB),
+			events:     events.NewHub(),
+		})
+		return
+	}
 
-	connStr := mustGetEnv("DB_CONN_STR")
-	pg, err := sql.Open("postgres", connStr)
+	dialect := db.Dialect(cfg.DBDriver)
+	sqlDB, err := db.Open(dialect, cfg.DBConnStr)
 	if err != nil {
 		log.Fatal(err)
 	}
+	var schema fs.FS = migrations.Postgres
+	if dialect == db.SQLite {
+		schema = migrations.SQLite
+	}
+	migrator := db.NewMigrator(sqlDB, dialect, schema)
+
+	if len(cfg.Args) > 0 {
+		switch cfg.Args[0] {
+		case "migrate":
+			if err = migrate(migrator, cfg.Args[1:]); err != nil {
+				log.Fatal(err)
+			}
+		default:
+			log.Fatalf("unknown command %q, expected migrate", cfg.Args[0])
+		}
+		return
+	}
+
+	if cfg.MigrateOnStart {
+		applied, err := migrator.Migrate(context.Background(), false)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, m := range applied {
+			log.Printf("applied migration V%d %s\n", m.Version, m.Description)
+		}
+	}
+
+	// replicas share a Postgres database, so they share events through it
+	var eventBus bus = events.NewHub()
+	if dialect == db.Postgres {
+		if eventBus, err = db.NewEventBus(sqlDB, cfg.DBConnStr, events.NewHub()); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	serve(cfg, stores{
+		users:      db.NewUserStore(sqlDB),
+		recipes:    db.NewRecipeStore(sqlDB),
+		sessions:   db.NewSessionStore(sqlDB),
+		prices:     db.NewPriceStore(sqlDB),
+		budgets:    db.NewBudgetStore(sqlDB),
+		cookLog:    db.NewCookLogStore(sqlDB),
+		ratings:    db.NewRatingStore(sqlDB),
+		calendar:   db.NewCalendarTokenStore(sqlDB),
+		plans:      db.NewPlanStore(sqlDB),
+		templates:  db.NewPlanTemplateStore(sqlDB),
+		transactor: db.NewTransactor(sqlDB),
+		events:     eventBus,
+	})
+}
+
+// stores are the database or in-memory stores the server runs with
+type stores struct {
+	users      handlers.UserStore
+	recipes    handlers.RecipeStore
+	sessions   session.Tracker
+	prices     handlers.PriceStore
+	budgets    handlers.BudgetStore
+	cookLog    handlers.CookLogStore
+	ratings    handlers.RatingStore
+	calendar   handlers.CalendarTokenStore
+	plans      handlers.PlanStore
+	templates  handlers.PlanTemplateStore
+	transactor handlers.Transactor
+	events     bus
+}
+
+// bus passes changes to a user's data to each of their connected clients
+type bus interface {
+	handlers.EventPublisher
+	handlers.EventSubscriber
+}
 
-	userStore := db.NewUserStore(pg)
-	recipeStore := db.NewRecipeStore(pg)
+func serve(cfg config.Config, stores stores) {
+	googleVerifier := new(googleAuthIDTokenVerifier.Verifier)
+	jwtDecoder := jwt.NewJWT()
 
-	sessionManager := session.NewManager([][]byte{sign, encrypt})
-	authHandler := handlers.NewAuthHandler(aud, googleVerifier, jwtDecoder, userStore, sessionManager)
-	recipeHandler := handlers.NewRecipeHandler(sessionManager, recipeStore)
-	routes := routing.New(webURI, sessionManager, authHandler, recipeHandler)
+	nutrients := nutrition.Default()
+	if cfg.NutrientData != "" {
+		var err error
+		if nutrients, err = nutrition.LoadFile(cfg.NutrientData); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	sessionManager := session.NewManager(cfg.SessionKeys(), stores.sessions)
+	authHandler := handlers.NewAuthHandler(cfg.GoogleAudience, googleVerifier, jwtDecoder, stores.users, stores.transactor, sessionManager)
+	costEstimator := costing.NewEstimator(stores.prices)
+	recipeHandler := handlers.NewRecipeHandler(sessionManager, stores.recipes, stores.transactor, stores.ratings, nutrients, costEstimator, stores.events)
+	sessionHandler := handlers.NewSessionHandler(sessionManager, sessionManager)
+	priceHandler := handlers.NewPriceHandler(sessionManager, stores.prices, stores.budgets)
+	cookLogHandler := handlers.NewCookLogHandler(sessionManager, stores.recipes, stores.cookLog, stores.transactor, stores.events)
+	calendarHandler := handlers.NewCalendarHandler(sessionManager, stores.calendar, stores.recipes, stores.plans, cfg.WebURI)
+	shoppingHandler := handlers.NewShoppingHandler(sessionManager, stores.recipes, stores.plans, shopping.Default())
+	printHandler := handlers.NewPrintHandler(sessionManager, stores.plans, stores.recipes, printout.A4)
+	planHandler := handlers.NewPlanHandler(sessionManager, stores.plans, stores.templates, stores.recipes, stores.transactor, nutrients, costEstimator, stores.budgets, stores.events)
+	eventsHandler := handlers.NewEventsHandler(sessionManager, stores.events)
+	graphQLHandler := handlers.NewGraphQLHandler(sessionManager, stores.plans, stores.recipes, stores.cookLog, shopping.Default())
+	routes := routing.New(corsPolicy(cfg), sessionManager, authHandler, recipeHandler, sessionHandler, priceHandler, cookLogHandler, calendarHandler, shoppingHandler, printHandler, planHandler, eventsHandler, graphQLHandler)
 	r := routes.SetupRoutes()
 
-	log.Fatal(http.ListenAndServe("localhost:"+strconv.Itoa(port), r))
+	log.Fatal(http.ListenAndServe(cfg.Addr(), r))
+}
+
+// migrate runs the migrate subcommand:
+//
+//	migrate [--dry-run]  apply (or list) pending migrations
+//	migrate status       list every migration and whether it is applied
+func migrate(migrator *db.Migrator, args []string) error {
+	fs := flag.NewFlagSet("migrate", flag.ExitOnError)
+	dryRun := fs.Bool("dry-run", false, "list pending migrations without applying them")
+	fs.Parse(args)
+
+	ctx := context.Background()
+
+	if fs.Arg(0) == "status" {
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tDESCRIPTION\tSTATUS")
+		for _, s := range statuses {
+			status := "pending"
+			switch {
+			case s.Missing:
+				status = "applied, file missing"
+			case s.Modified:
+				status = "applied, file modified"
+			case s.Applied:
+				status = "applied " + s.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(w, "V%d\t%s\t%s\n", s.Version, s.Description, status)
+		}
+		return w.Flush()
+	}
+
+	migrations, err := migrator.Migrate(ctx, *dryRun)
+	for _, m := range migrations {
+		if *dryRun {
+			fmt.Printf("would apply V%d %s\n", m.Version, m.Description)
+		} else {
+			fmt.Printf("applied V%d %s\n", m.Version, m.Description)
+		}
+	}
+	return err
 }
 
-func mustGetEnv(v string) string {
-	s := os.Getenv(v)
-	if s != "" {
-		return s
+// corsPolicy allows credentialed requests from the web app and the configured
+// allowed origins, and credential-less requests from the public origins, with
+// the configured methods and headers
+func corsPolicy(cfg config.Config) routing.CORSPolicy {
+	policy := routing.NewCORSPolicy(cfg.WebURI)
+	policy.AllowedMethods = cfg.CORSAllowedMethods
+	policy.AllowedHeaders = cfg.CORSAllowedHeaders
+	policy.MaxAge = cfg.CORSMaxAge
+
+	for _, o := range cfg.CORSAllowedOrigins {
+		policy.Origins = append(policy.Origins, routing.CORSOrigin{Origin: o, AllowCredentials: true})
 	}
-	panic(fmt.Sprintf("env var %q not set", v))
+	for _, o := range cfg.CORSPublicOrigins {
+		policy.Origins = append(policy.Origins, routing.CORSOrigin{Origin: o})
+	}
+
+	return policy
 }
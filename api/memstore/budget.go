@@ -0,0 +1,43 @@
+package memstore
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kieron-pivotal/menu-planner-app/models"
+)
+
+type BudgetStore struct {
+	db *DB
+}
+
+func NewBudgetStore(db *DB) *BudgetStore {
+	return &BudgetStore{
+		db: db,
+	}
+}
+
+// Get returns the user's weekly budget, which is 0 if they haven't set one
+func (s *BudgetStore) Get(ctx context.Context, userID int) (models.Budget, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	return models.Budget{UserID: userID, Weekly: s.db.data.budgets[userID]}, nil
+}
+
+// Save sets the user's weekly budget, replacing any they had
+func (s *BudgetStore) Save(ctx context.Context, budget models.Budget) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	if !s.db.data.userExists(budget.UserID) {
+		return fmt.Errorf("save-budget failed %w", errNoUser)
+	}
+	if budget.Weekly < 0 {
+		return fmt.Errorf("save-budget failed %w", errNegative)
+	}
+
+	s.db.data.budgets[budget.UserID] = budget.Weekly
+
+	return nil
+}
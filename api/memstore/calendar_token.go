@@ -0,0 +1,65 @@
+package memstore
+
+import (
+	"context"
+	"fmt"
+)
+
+type CalendarTokenStore struct {
+	db *DB
+}
+
+func NewCalendarTokenStore(db *DB) *CalendarTokenStore {
+	return &CalendarTokenStore{
+		db: db,
+	}
+}
+
+func (s *CalendarTokenStore) IsNotFoundErr(err error) bool {
+	return err == errNotFound
+}
+
+// Save gives the user a calendar token, replacing any they had
+func (s *CalendarTokenStore) Save(ctx context.Context, userID int, tokenHash string) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	if !s.db.data.userExists(userID) {
+		return fmt.Errorf("save-calendar-token failed %w", errNoUser)
+	}
+	for id, hash := range s.db.data.calendarTokens {
+		if hash == tokenHash && id != userID {
+			return fmt.Errorf("save-calendar-token failed %w", errDuplicate)
+		}
+	}
+
+	s.db.data.calendarTokens[userID] = tokenHash
+
+	return nil
+}
+
+func (s *CalendarTokenStore) Delete(ctx context.Context, userID int) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	if _, ok := s.db.data.calendarTokens[userID]; !ok {
+		return errNotFound
+	}
+	delete(s.db.data.calendarTokens, userID)
+
+	return nil
+}
+
+// FindUser returns the id of the user whose token has the given hash
+func (s *CalendarTokenStore) FindUser(ctx context.Context, tokenHash string) (int, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	for id, hash := range s.db.data.calendarTokens {
+		if hash == tokenHash {
+			return id, nil
+		}
+	}
+
+	return 0, errNotFound
+}
@@ -0,0 +1,126 @@
+package memstore
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/kieron-pivotal/menu-planner-app/models"
+)
+
+type CookLogStore struct {
+	db *DB
+}
+
+func NewCookLogStore(db *DB) *CookLogStore {
+	return &CookLogStore{
+		db: db,
+	}
+}
+
+func (s *CookLogStore) IsNotFoundErr(err error) bool {
+	return err == errNotFound
+}
+
+// Add records that a recipe was cooked and updates when it was last cooked
+func (s *CookLogStore) Add(ctx context.Context, cooked models.Cooked) (models.Cooked, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	recipe := -1
+	for i, r := range s.db.data.recipes {
+		if r.ID == cooked.RecipeID {
+			recipe = i
+		}
+	}
+	if recipe < 0 {
+		return models.Cooked{}, fmt.Errorf("add-cooked failed %w", errNoRecipe)
+	}
+
+	cooked.CookedAt = cooked.CookedAt.Round(0)
+	s.db.data.lastCookedID++
+	cooked.ID = s.db.data.lastCookedID
+	s.db.data.cooked = append(s.db.data.cooked, cooked)
+
+	r := &s.db.data.recipes[recipe]
+	if r.LastCookedAt == nil || r.LastCookedAt.Before(cooked.CookedAt) {
+		cookedAt := cooked.CookedAt
+		r.LastCookedAt = &cookedAt
+	}
+
+	return cooked, nil
+}
+
+// History lists the times a recipe was cooked, most recent first
+func (s *CookLogStore) History(ctx context.Context, recipeID int) ([]models.Cooked, error) {
+	return s.Histories(ctx, []int{recipeID})
+}
+
+// Histories lists the times any of the recipes were cooked, most recent
+// first
+func (s *CookLogStore) Histories(ctx context.Context, recipeIDs []int) ([]models.Cooked, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	wanted := map[int]bool{}
+	for _, id := range recipeIDs {
+		wanted[id] = true
+	}
+
+	res := []models.Cooked{}
+	for _, c := range s.db.data.cooked {
+		if wanted[c.RecipeID] {
+			res = append(res, c)
+		}
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		if !res[i].CookedAt.Equal(res[j].CookedAt) {
+			return res[i].CookedAt.After(res[j].CookedAt)
+		}
+		return res[i].ID > res[j].ID
+	})
+
+	return res, nil
+}
+
+// Leftovers lists the user's cooks which still have portions left, oldest
+// first
+func (s *CookLogStore) Leftovers(ctx context.Context, userID int) ([]models.Cooked, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	res := []models.Cooked{}
+	for _, c := range s.db.data.cooked {
+		if c.Leftovers > 0 && s.db.data.ownsRecipe(userID, c.RecipeID) {
+			res = append(res, c)
+		}
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		if !res[i].CookedAt.Equal(res[j].CookedAt) {
+			return res[i].CookedAt.Before(res[j].CookedAt)
+		}
+		return res[i].ID < res[j].ID
+	})
+
+	return res, nil
+}
+
+// EatLeftovers takes portions from a cook's leftovers. Eating more than
+// is left uses them all up.
+func (s *CookLogStore) EatLeftovers(ctx context.Context, userID, id, portions int) (models.Cooked, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	for i, c := range s.db.data.cooked {
+		if c.ID == id && s.db.data.ownsRecipe(userID, c.RecipeID) {
+			c.Leftovers -= portions
+			if c.Leftovers < 0 {
+				c.Leftovers = 0
+			}
+			s.db.data.cooked[i] = c
+			return c, nil
+		}
+	}
+
+	return models.Cooked{}, errNotFound
+}
@@ -0,0 +1,166 @@
+/* Package memstore implements the stores in memory, for tests and demo mode */
+package memstore
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/kieron-pivotal/menu-planner-app/models"
+)
+
+// maxNameLen and maxUnitLen match the VARCHAR columns of the database
+const (
+	maxNameLen = 200
+	maxUnitLen = 50
+)
+
+var (
+	errNotFound  = errors.New("no matching record found")
+	errTooLong   = errors.New("value too long")
+	errDuplicate = errors.New("duplicate key")
+	errNoUser    = errors.New("user does not exist")
+	errNoRecipe  = errors.New("recipe does not exist")
+	errNameTaken = errors.New("recipe name already used")
+	errNegative  = errors.New("value is negative")
+
+	errTemplateNameTaken = errors.New("plan template name already used")
+)
+
+func NotFoundErr() error {
+	return errNotFound
+}
+
+// DB holds the data shared by the stores. It is safe for concurrent use.
+type DB struct {
+	mu   sync.Mutex
+	data data
+
+	// txMu serialises units of work, so a rollback can't undo another's
+	// changes
+	txMu sync.Mutex
+}
+
+type data struct {
+	users        []User
+	recipes      []models.Recipe
+	sessions     []models.Session
+	prices       []models.Price
+	cooked       []models.Cooked
+	ratings      []models.Rating
+	plans        []models.Plan
+	templates    []models.PlanTemplate
+	rules        []models.PlanRule
+	lastUserID   int
+	lastRecipeID int
+	lastPriceID  int
+	lastCookedID int
+
+	lastTemplateID int
+	lastRuleID     int
+
+	// calendarTokens are token hashes by user id
+	calendarTokens map[int]string
+	// budgets are weekly budgets by user id
+	budgets map[int]int
+}
+
+func New() *DB {
+	return &DB{data: data{calendarTokens: map[int]string{}, budgets: map[int]int{}}}
+}
+
+func (d data) clone() data {
+	c := d
+	c.users = append([]User(nil), d.users...)
+	c.recipes = append([]models.Recipe(nil), d.recipes...)
+	c.sessions = append([]models.Session(nil), d.sessions...)
+	c.prices = append([]models.Price(nil), d.prices...)
+	c.cooked = append([]models.Cooked(nil), d.cooked...)
+	c.ratings = append([]models.Rating(nil), d.ratings...)
+	c.plans = []models.Plan{}
+	for _, p := range d.plans {
+		p.Slots = cloneSlots(p.Slots)
+		c.plans = append(c.plans, p)
+	}
+	c.templates = []models.PlanTemplate{}
+	for _, t := range d.templates {
+		t.Slots = append([]models.Slot{}, t.Slots...)
+		c.templates = append(c.templates, t)
+	}
+	c.rules = append([]models.PlanRule(nil), d.rules...)
+	c.calendarTokens = map[int]string{}
+	for id, hash := range d.calendarTokens {
+		c.calendarTokens[id] = hash
+	}
+	c.budgets = map[int]int{}
+	for id, amount := range d.budgets {
+		c.budgets[id] = amount
+	}
+	return c
+}
+
+func (d *data) userExists(id int) bool {
+	for _, u := range d.users {
+		if u.id == id {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *data) recipeExists(id int) bool {
+	for _, r := range d.recipes {
+		if r.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *data) ownsRecipe(userID, recipeID int) bool {
+	for _, r := range d.recipes {
+		if r.ID == recipeID {
+			return r.UserID == userID
+		}
+	}
+	return false
+}
+
+type txKey struct{}
+
+// Transactor runs units of work against a DB, undoing their changes if they
+// fail. Store calls made outside a unit of work are not isolated from it.
+type Transactor struct {
+	db *DB
+}
+
+func NewTransactor(db *DB) *Transactor {
+	return &Transactor{
+		db: db,
+	}
+}
+
+// InTx keeps fn's changes if it succeeds and restores the data as it was
+// before if it returns an error. Calls nested inside another unit of work
+// join the outer one.
+func (t *Transactor) InTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if ctx.Value(txKey{}) != nil {
+		return fn(ctx)
+	}
+
+	t.db.txMu.Lock()
+	defer t.db.txMu.Unlock()
+
+	t.db.mu.Lock()
+	before := t.db.data.clone()
+	t.db.mu.Unlock()
+
+	if err := fn(context.WithValue(ctx, txKey{}, true)); err != nil {
+		t.db.mu.Lock()
+		t.db.data = before
+		t.db.mu.Unlock()
+		return err
+	}
+
+	return nil
+}
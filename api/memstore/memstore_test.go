@@ -0,0 +1,90 @@
+package memstore_test
+
+import (
+	"context"
+	"errors"
+
+	"github.com/kieron-pivotal/menu-planner-app/memstore"
+	"github.com/kieron-pivotal/menu-planner-app/models"
+	"github.com/kieron-pivotal/menu-planner-app/storetest"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = storetest.DescribeStores("In-memory stores", func() storetest.Stores {
+	memDB := memstore.New()
+	return storetest.Stores{
+		Users:     memstore.NewUserStore(memDB),
+		Recipes:   memstore.NewRecipeStore(memDB),
+		Sessions:  memstore.NewSessionStore(memDB),
+		Prices:    memstore.NewPriceStore(memDB),
+		Budgets:   memstore.NewBudgetStore(memDB),
+		CookLog:   memstore.NewCookLogStore(memDB),
+		Ratings:   memstore.NewRatingStore(memDB),
+		Calendar:  memstore.NewCalendarTokenStore(memDB),
+		Plans:     memstore.NewPlanStore(memDB),
+		Templates: memstore.NewPlanTemplateStore(memDB),
+	}
+})
+
+var _ = Describe("Transactor", func() {
+	var (
+		memDB       *memstore.DB
+		transactor  *memstore.Transactor
+		userStore   *memstore.UserStore
+		recipeStore *memstore.RecipeStore
+		ctx         context.Context
+	)
+
+	BeforeEach(func() {
+		memDB = memstore.New()
+		transactor = memstore.NewTransactor(memDB)
+		userStore = memstore.NewUserStore(memDB)
+		recipeStore = memstore.NewRecipeStore(memDB)
+		ctx = context.Background()
+	})
+
+	It("keeps the changes of a successful unit of work", func() {
+		err := transactor.InTx(ctx, func(ctx context.Context) error {
+			_, err := userStore.Create(ctx, "kept@example.com", "Kept")
+			return err
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = userStore.FindByEmail(ctx, "kept@example.com")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("undoes every change of a failed unit of work", func() {
+		err := transactor.InTx(ctx, func(ctx context.Context) error {
+			user, err := userStore.Create(ctx, "undone@example.com", "Undone")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = recipeStore.Insert(ctx, models.Recipe{Name: "soup", UserID: user.ID()})
+			Expect(err).NotTo(HaveOccurred())
+
+			return errors.New("boom")
+		})
+		Expect(err).To(MatchError("boom"))
+
+		_, err = userStore.FindByEmail(ctx, "undone@example.com")
+		Expect(userStore.IsNotFoundErr(err)).To(BeTrue())
+		recipes, err := recipeStore.List(ctx, 1)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(recipes).To(BeEmpty())
+	})
+
+	It("joins nested units of work to the outer one", func() {
+		err := transactor.InTx(ctx, func(ctx context.Context) error {
+			Expect(transactor.InTx(ctx, func(ctx context.Context) error {
+				_, err := userStore.Create(ctx, "nested@example.com", "Nested")
+				return err
+			})).To(Succeed())
+
+			return errors.New("boom")
+		})
+		Expect(err).To(MatchError("boom"))
+
+		_, err = userStore.FindByEmail(ctx, "nested@example.com")
+		Expect(userStore.IsNotFoundErr(err)).To(BeTrue())
+	})
+})
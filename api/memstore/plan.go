@@ -0,0 +1,288 @@
+package memstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/kieron-pivotal/menu-planner-app/models"
+)
+
+type PlanStore struct {
+	db *DB
+}
+
+func NewPlanStore(db *DB) *PlanStore {
+	return &PlanStore{
+		db: db,
+	}
+}
+
+// Get returns the user's plan for the week, which has no slots if nothing
+// is planned
+func (s *PlanStore) Get(ctx context.Context, userID int, week string) (models.Plan, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	plan := models.Plan{UserID: userID, Week: week, Slots: []models.Slot{}}
+	for _, p := range s.db.data.plans {
+		if p.UserID == userID && p.Week == week {
+			plan.Slots = cloneSlots(p.Slots)
+		}
+	}
+	models.SortSlots(plan.Slots)
+
+	return plan, nil
+}
+
+// Weeks returns the user's plans for the weeks from one Monday to
+// another, inclusive, in order. Weeks with nothing planned are left out.
+func (s *PlanStore) Weeks(ctx context.Context, userID int, from, to string) ([]models.Plan, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	plans := []models.Plan{}
+	for _, p := range s.db.data.plans {
+		if p.UserID == userID && p.Week >= from && p.Week <= to && len(p.Slots) > 0 {
+			p.Slots = cloneSlots(p.Slots)
+			models.SortSlots(p.Slots)
+			plans = append(plans, p)
+		}
+	}
+	sort.Slice(plans, func(i, j int) bool { return plans[i].Week < plans[j].Week })
+
+	return plans, nil
+}
+
+// Save replaces the user's plan for the week
+func (s *PlanStore) Save(ctx context.Context, plan models.Plan) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	d := &s.db.data
+	if !d.userExists(plan.UserID) {
+		return fmt.Errorf("save-plan failed %w", errNoUser)
+	}
+	if err := d.checkSlots(plan.Slots); err != nil {
+		return fmt.Errorf("save-plan failed %w", err)
+	}
+
+	plans := []models.Plan{}
+	for _, p := range d.plans {
+		if p.UserID != plan.UserID || p.Week != plan.Week {
+			plans = append(plans, p)
+		}
+	}
+	plan.Slots = cloneSlots(plan.Slots)
+	d.plans = append(plans, plan)
+
+	return nil
+}
+
+// PlanTemplateStore keeps the week layouts a user reuses: named templates
+// and the rules which plan a recipe for a slot every week
+type PlanTemplateStore struct {
+	db *DB
+}
+
+func NewPlanTemplateStore(db *DB) *PlanTemplateStore {
+	return &PlanTemplateStore{
+		db: db,
+	}
+}
+
+func (s *PlanTemplateStore) IsNotFoundErr(err error) bool {
+	return err == errNotFound
+}
+
+func (s *PlanTemplateStore) IsDuplicateErr(err error) bool {
+	return errors.Is(err, errTemplateNameTaken)
+}
+
+// ListTemplates returns the user's templates with their slots, by name
+func (s *PlanTemplateStore) ListTemplates(ctx context.Context, userID int) ([]models.PlanTemplate, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	res := []models.PlanTemplate{}
+	for _, t := range s.db.data.templates {
+		if t.UserID == userID {
+			t.Slots = append([]models.Slot{}, t.Slots...)
+			models.SortSlots(t.Slots)
+			res = append(res, t)
+		}
+	}
+	sort.SliceStable(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+
+	return res, nil
+}
+
+// GetTemplate returns one of the user's templates with its slots
+func (s *PlanTemplateStore) GetTemplate(ctx context.Context, userID, id int) (models.PlanTemplate, error) {
+	templates, err := s.ListTemplates(ctx, userID)
+	if err != nil {
+		return models.PlanTemplate{}, err
+	}
+
+	for _, template := range templates {
+		if template.ID == id {
+			return template, nil
+		}
+	}
+
+	return models.PlanTemplate{}, errNotFound
+}
+
+func (s *PlanTemplateStore) InsertTemplate(ctx context.Context, template models.PlanTemplate) (models.PlanTemplate, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	d := &s.db.data
+	if len(template.Name) > maxNameLen {
+		return models.PlanTemplate{}, fmt.Errorf("insert-plan-template failed %w", errTooLong)
+	}
+	if !d.userExists(template.UserID) {
+		return models.PlanTemplate{}, fmt.Errorf("insert-plan-template failed %w", errNoUser)
+	}
+	for _, t := range d.templates {
+		if t.UserID == template.UserID && t.Name == template.Name {
+			return models.PlanTemplate{}, fmt.Errorf("insert-plan-template failed %w", errTemplateNameTaken)
+		}
+	}
+	if err := d.checkSlots(template.Slots); err != nil {
+		return models.PlanTemplate{}, fmt.Errorf("insert-plan-template failed %w", err)
+	}
+
+	d.lastTemplateID++
+	template.ID = d.lastTemplateID
+	template.Slots = append([]models.Slot{}, template.Slots...)
+	d.templates = append(d.templates, template)
+
+	return template, nil
+}
+
+func (s *PlanTemplateStore) DeleteTemplate(ctx context.Context, userID, id int) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	for i, t := range s.db.data.templates {
+		if t.UserID == userID && t.ID == id {
+			s.db.data.templates = append(s.db.data.templates[:i:i], s.db.data.templates[i+1:]...)
+			return nil
+		}
+	}
+
+	return errNotFound
+}
+
+// ListRules returns the user's rules in slot order
+func (s *PlanTemplateStore) ListRules(ctx context.Context, userID int) ([]models.PlanRule, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	slots := []models.Slot{}
+	ids := map[models.Slot]int{}
+	for _, r := range s.db.data.rules {
+		if r.UserID == userID {
+			slots = append(slots, r.Slot)
+			ids[r.Slot] = r.ID
+		}
+	}
+	models.SortSlots(slots)
+
+	res := []models.PlanRule{}
+	for _, slot := range slots {
+		res = append(res, models.PlanRule{ID: ids[slot], UserID: userID, Slot: slot})
+	}
+
+	return res, nil
+}
+
+// SaveRule plans a recipe for a slot every week, replacing any rule the
+// user has for the slot
+func (s *PlanTemplateStore) SaveRule(ctx context.Context, rule models.PlanRule) (models.PlanRule, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	d := &s.db.data
+	if !d.userExists(rule.UserID) {
+		return models.PlanRule{}, fmt.Errorf("save-plan-rule failed %w", errNoUser)
+	}
+	if err := d.checkSlots([]models.Slot{rule.Slot}); err != nil {
+		return models.PlanRule{}, fmt.Errorf("save-plan-rule failed %w", err)
+	}
+
+	for i, r := range d.rules {
+		if r.UserID == rule.UserID && r.Day == rule.Day && r.Meal == rule.Meal {
+			rule.ID = r.ID
+			d.rules[i] = rule
+			return rule, nil
+		}
+	}
+
+	d.lastRuleID++
+	rule.ID = d.lastRuleID
+	d.rules = append(d.rules, rule)
+
+	return rule, nil
+}
+
+func (s *PlanTemplateStore) DeleteRule(ctx context.Context, userID, id int) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	for i, r := range s.db.data.rules {
+		if r.UserID == userID && r.ID == id {
+			s.db.data.rules = append(s.db.data.rules[:i:i], s.db.data.rules[i+1:]...)
+			return nil
+		}
+	}
+
+	return errNotFound
+}
+
+// checkSlots rejects slots the database would: those for recipes which
+// don't exist, or two for the same meal
+func (d *data) checkSlots(slots []models.Slot) error {
+	seen := map[models.Slot]bool{}
+	for _, slot := range slots {
+		if !d.recipeExists(slot.RecipeID) {
+			return errNoRecipe
+		}
+
+		key := models.Slot{Day: slot.Day, Meal: slot.Meal}
+		if seen[key] {
+			return errDuplicate
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
+// cloneSlots copies slots, so the copies' leftover references can't be
+// changed through the originals
+func cloneSlots(slots []models.Slot) []models.Slot {
+	res := []models.Slot{}
+	for _, slot := range slots {
+		if slot.LeftoverOf != nil {
+			ref := *slot.LeftoverOf
+			slot.LeftoverOf = &ref
+		}
+		res = append(res, slot)
+	}
+	return res
+}
+
+// slotLists are the slots of every plan and template, which can be
+// changed in place
+func (d *data) slotLists() [][]models.Slot {
+	res := [][]models.Slot{}
+	for _, p := range d.plans {
+		res = append(res, p.Slots)
+	}
+	for _, t := range d.templates {
+		res = append(res, t.Slots)
+	}
+	return res
+}
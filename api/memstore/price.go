@@ -0,0 +1,85 @@
+package memstore
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/kieron-pivotal/menu-planner-app/models"
+)
+
+type PriceStore struct {
+	db *DB
+}
+
+func NewPriceStore(db *DB) *PriceStore {
+	return &PriceStore{
+		db: db,
+	}
+}
+
+func (s *PriceStore) IsNotFoundErr(err error) bool {
+	return err == errNotFound
+}
+
+func (s *PriceStore) List(ctx context.Context, userID int) ([]models.Price, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	res := []models.Price{}
+	for _, p := range s.db.data.prices {
+		if p.UserID == userID {
+			res = append(res, p)
+		}
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		if res[i].Ingredient != res[j].Ingredient {
+			return res[i].Ingredient < res[j].Ingredient
+		}
+		return res[i].Store < res[j].Store
+	})
+
+	return res, nil
+}
+
+// Save records a price, replacing any the user has for the same ingredient
+// at the same store
+func (s *PriceStore) Save(ctx context.Context, price models.Price) (models.Price, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	if len(price.Ingredient) > maxNameLen || len(price.Store) > maxNameLen || len(price.Unit) > maxUnitLen {
+		return models.Price{}, fmt.Errorf("save-price failed %w", errTooLong)
+	}
+	if !s.db.data.userExists(price.UserID) {
+		return models.Price{}, fmt.Errorf("save-price failed %w", errNoUser)
+	}
+
+	for i, p := range s.db.data.prices {
+		if p.UserID == price.UserID && p.Ingredient == price.Ingredient && p.Store == price.Store {
+			price.ID = p.ID
+			s.db.data.prices[i] = price
+			return price, nil
+		}
+	}
+
+	s.db.data.lastPriceID++
+	price.ID = s.db.data.lastPriceID
+	s.db.data.prices = append(s.db.data.prices, price)
+
+	return price, nil
+}
+
+func (s *PriceStore) Delete(ctx context.Context, userID, id int) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	for i, p := range s.db.data.prices {
+		if p.UserID == userID && p.ID == id {
+			s.db.data.prices = append(s.db.data.prices[:i:i], s.db.data.prices[i+1:]...)
+			return nil
+		}
+	}
+
+	return errNotFound
+}
@@ -0,0 +1,67 @@
+package memstore
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kieron-pivotal/menu-planner-app/models"
+)
+
+type RatingStore struct {
+	db *DB
+}
+
+func NewRatingStore(db *DB) *RatingStore {
+	return &RatingStore{
+		db: db,
+	}
+}
+
+// Rate records what a user thinks of a recipe, replacing their previous
+// rating
+func (s *RatingStore) Rate(ctx context.Context, rating models.Rating) (models.Rating, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	if !s.db.data.userExists(rating.UserID) {
+		return models.Rating{}, fmt.Errorf("rate-recipe failed %w", errNoUser)
+	}
+	if !s.db.data.recipeExists(rating.RecipeID) {
+		return models.Rating{}, fmt.Errorf("rate-recipe failed %w", errNoRecipe)
+	}
+
+	for i, r := range s.db.data.ratings {
+		if r.RecipeID == rating.RecipeID && r.UserID == rating.UserID {
+			s.db.data.ratings[i] = rating
+			return rating, nil
+		}
+	}
+	s.db.data.ratings = append(s.db.data.ratings, rating)
+
+	return rating, nil
+}
+
+// rate sets the recipe's score, lowest rating and whether userID has
+// marked it as a favourite
+func (d *data) rate(recipe *models.Recipe, userID int) {
+	total, count := 0, 0
+	for _, r := range d.ratings {
+		if r.RecipeID != recipe.ID {
+			continue
+		}
+		if r.UserID == userID && r.Favourite {
+			recipe.Favourite = true
+		}
+		if r.Rating == 0 {
+			continue
+		}
+		if count == 0 || r.Rating < recipe.LowestRating {
+			recipe.LowestRating = r.Rating
+		}
+		total += r.Rating
+		count++
+	}
+	if count > 0 {
+		recipe.Score = float64(total) / float64(count)
+	}
+}
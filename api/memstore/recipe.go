@@ -0,0 +1,220 @@
+package memstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kieron-pivotal/menu-planner-app/models"
+)
+
+type RecipeStore struct {
+	db *DB
+}
+
+func NewRecipeStore(db *DB) *RecipeStore {
+	return &RecipeStore{
+		db: db,
+	}
+}
+
+func (s *RecipeStore) IsNotFoundErr(err error) bool {
+	return err == errNotFound
+}
+
+func (s *RecipeStore) IsDuplicateErr(err error) bool {
+	return errors.Is(err, errNameTaken)
+}
+
+func (s *RecipeStore) List(ctx context.Context, userID int) ([]models.Recipe, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	res := []models.Recipe{}
+	for _, r := range s.db.data.recipes {
+		if r.UserID == userID {
+			recipe := models.Recipe{ID: r.ID, Name: r.Name, UserID: r.UserID, LastCookedAt: r.LastCookedAt}
+			s.db.data.rate(&recipe, userID)
+			res = append(res, recipe)
+		}
+	}
+
+	return res, nil
+}
+
+// Get returns a user's recipe with its ingredients and steps
+func (s *RecipeStore) Get(ctx context.Context, userID, id int) (models.Recipe, error) {
+	recipes, err := s.GetMany(ctx, userID, []int{id})
+	if err != nil {
+		return models.Recipe{}, err
+	}
+	if len(recipes) == 0 {
+		return models.Recipe{}, errNotFound
+	}
+
+	return recipes[0], nil
+}
+
+// GetMany returns the user's recipes with the given ids, with their
+// ingredients and steps, in id order. Ids which aren't the user's recipes
+// are left out.
+func (s *RecipeStore) GetMany(ctx context.Context, userID int, ids []int) ([]models.Recipe, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	wanted := map[int]bool{}
+	for _, id := range ids {
+		wanted[id] = true
+	}
+
+	res := []models.Recipe{}
+	for _, r := range s.db.data.recipes {
+		if wanted[r.ID] && r.UserID == userID {
+			recipe := copyRecipe(r)
+			s.db.data.rate(&recipe, userID)
+			res = append(res, recipe)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].ID < res[j].ID })
+
+	return res, nil
+}
+
+func (s *RecipeStore) Insert(ctx context.Context, recipe models.Recipe) (models.Recipe, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	if len(recipe.Name) > maxNameLen {
+		return models.Recipe{}, fmt.Errorf("insert failed: %w", errTooLong)
+	}
+	if !s.db.data.userExists(recipe.UserID) {
+		return models.Recipe{}, fmt.Errorf("insert failed: %w", errNoUser)
+	}
+	for _, r := range s.db.data.recipes {
+		if r.UserID == recipe.UserID && strings.EqualFold(r.Name, recipe.Name) {
+			return models.Recipe{}, fmt.Errorf("insert failed: %w", errNameTaken)
+		}
+	}
+	for _, ing := range recipe.Ingredients {
+		if len(ing.Name) > maxNameLen || len(ing.Unit) > maxUnitLen {
+			return models.Recipe{}, fmt.Errorf("insert failed: %w", errTooLong)
+		}
+	}
+	for i, step := range recipe.Steps {
+		used := map[int]bool{}
+		for _, ref := range step.Ingredients {
+			if ref < 0 || ref >= len(recipe.Ingredients) {
+				return models.Recipe{}, fmt.Errorf("insert failed: step %d uses unknown ingredient %d", i, ref)
+			}
+			if used[ref] {
+				return models.Recipe{}, fmt.Errorf("insert failed: %w", errDuplicate)
+			}
+			used[ref] = true
+		}
+	}
+
+	s.db.data.lastRecipeID++
+	recipe.ID = s.db.data.lastRecipeID
+	recipe.LastCookedAt = nil
+	s.db.data.recipes = append(s.db.data.recipes, copyRecipe(recipe))
+
+	return recipe, nil
+}
+
+// Merge folds a duplicate recipe into the survivor, moving its cook log,
+// places in plans and any ratings the survivor lacks, and deletes the
+// duplicate
+func (s *RecipeStore) Merge(ctx context.Context, survivorID, duplicateID int) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	d := &s.db.data
+	duplicate, survivor := -1, -1
+	for i, r := range d.recipes {
+		switch r.ID {
+		case duplicateID:
+			duplicate = i
+		case survivorID:
+			survivor = i
+		}
+	}
+	if duplicate < 0 {
+		return errNotFound
+	}
+	if survivor < 0 {
+		return fmt.Errorf("merge-recipes failed %w", errNoRecipe)
+	}
+
+	var lastCookedAt *time.Time
+	for i, c := range d.cooked {
+		if c.RecipeID == duplicateID {
+			d.cooked[i].RecipeID = survivorID
+		}
+		if d.cooked[i].RecipeID == survivorID && (lastCookedAt == nil || lastCookedAt.Before(c.CookedAt)) {
+			cookedAt := c.CookedAt
+			lastCookedAt = &cookedAt
+		}
+	}
+	d.recipes[survivor].LastCookedAt = lastCookedAt
+
+	ratings := []models.Rating{}
+	for _, r := range d.ratings {
+		if r.RecipeID != duplicateID {
+			ratings = append(ratings, r)
+		}
+	}
+	for _, dup := range d.ratings {
+		if dup.RecipeID != duplicateID {
+			continue
+		}
+		merged := false
+		for i, r := range ratings {
+			if r.RecipeID == survivorID && r.UserID == dup.UserID {
+				if r.Rating == 0 {
+					ratings[i].Rating = dup.Rating
+				}
+				ratings[i].Favourite = r.Favourite || dup.Favourite
+				merged = true
+			}
+		}
+		if !merged {
+			dup.RecipeID = survivorID
+			ratings = append(ratings, dup)
+		}
+	}
+	d.ratings = ratings
+
+	for _, slots := range d.slotLists() {
+		for i := range slots {
+			if slots[i].RecipeID == duplicateID {
+				slots[i].RecipeID = survivorID
+			}
+		}
+	}
+	for i := range d.rules {
+		if d.rules[i].RecipeID == duplicateID {
+			d.rules[i].RecipeID = survivorID
+		}
+	}
+
+	d.recipes = append(d.recipes[:duplicate:duplicate], d.recipes[duplicate+1:]...)
+
+	return nil
+}
+
+// copyRecipe copies a recipe's slices, so callers can't change stored
+// recipes. Step ingredients are sorted as the database returns them.
+func copyRecipe(r models.Recipe) models.Recipe {
+	c := r
+	c.Ingredients = append([]models.Ingredient{}, r.Ingredients...)
+	c.Steps = []models.Step{}
+	for _, step := range r.Steps {
+		step.Ingredients = append([]int(nil), step.Ingredients...)
+		sort.Ints(step.Ingredients)
+		c.Steps = append(c.Steps, step)
+	}
+	return c
+}
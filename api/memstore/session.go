@@ -0,0 +1,130 @@
+package memstore
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/kieron-pivotal/menu-planner-app/models"
+)
+
+type SessionStore struct {
+	db *DB
+}
+
+func NewSessionStore(db *DB) *SessionStore {
+	return &SessionStore{
+		db: db,
+	}
+}
+
+func (s *SessionStore) IsNotFoundErr(err error) bool {
+	return err == errNotFound
+}
+
+func (s *SessionStore) Create(ctx context.Context, sess models.Session) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	if !s.db.data.userExists(sess.UserID) {
+		return fmt.Errorf("create-session failed %w", errNoUser)
+	}
+	for _, existing := range s.db.data.sessions {
+		if existing.ID == sess.ID {
+			return fmt.Errorf("create-session failed %w", errDuplicate)
+		}
+	}
+
+	sess.CreatedAt = sess.CreatedAt.Round(0)
+	sess.LastSeenAt = sess.LastSeenAt.Round(0)
+	sess.Current = false
+	s.db.data.sessions = append(s.db.data.sessions, sess)
+
+	return nil
+}
+
+// Touch records activity on a session at lastSeen, reporting false if the
+// session no longer exists or hasn't been seen since seenSince. Activity is
+// only written if the session was last seen before writeBefore.
+func (s *SessionStore) Touch(ctx context.Context, id string, lastSeen, seenSince, writeBefore time.Time) (bool, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	for i := range s.db.data.sessions {
+		sess := &s.db.data.sessions[i]
+		if sess.ID != id {
+			continue
+		}
+		if !sess.LastSeenAt.After(seenSince) {
+			return false, nil
+		}
+		if sess.LastSeenAt.Before(writeBefore) {
+			sess.LastSeenAt = lastSeen.Round(0)
+		}
+		return true, nil
+	}
+
+	return false, nil
+}
+
+func (s *SessionStore) List(ctx context.Context, userID int, seenSince time.Time) ([]models.Session, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	res := []models.Session{}
+	for _, sess := range s.db.data.sessions {
+		if sess.UserID == userID && sess.LastSeenAt.After(seenSince) {
+			res = append(res, sess)
+		}
+	}
+	sort.SliceStable(res, func(i, j int) bool { return res[i].LastSeenAt.After(res[j].LastSeenAt) })
+
+	return res, nil
+}
+
+func (s *SessionStore) Delete(ctx context.Context, userID int, id string) error {
+	found := false
+	s.deleteWhere(func(sess models.Session) bool {
+		if sess.UserID == userID && sess.ID == id {
+			found = true
+			return true
+		}
+		return false
+	})
+
+	if !found {
+		return errNotFound
+	}
+
+	return nil
+}
+
+func (s *SessionStore) DeleteIdle(ctx context.Context, userID int, lastSeenBefore time.Time) error {
+	s.deleteWhere(func(sess models.Session) bool {
+		return sess.UserID == userID && !sess.LastSeenAt.After(lastSeenBefore)
+	})
+
+	return nil
+}
+
+func (s *SessionStore) DeleteAll(ctx context.Context, userID int) error {
+	s.deleteWhere(func(sess models.Session) bool {
+		return sess.UserID == userID
+	})
+
+	return nil
+}
+
+func (s *SessionStore) deleteWhere(match func(models.Session) bool) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	kept := []models.Session{}
+	for _, sess := range s.db.data.sessions {
+		if !match(sess) {
+			kept = append(kept, sess)
+		}
+	}
+	s.db.data.sessions = kept
+}
@@ -0,0 +1,77 @@
+package memstore
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kieron-pivotal/menu-planner-app/models"
+)
+
+type UserStore struct {
+	db *DB
+}
+
+func NewUserStore(db *DB) *UserStore {
+	return &UserStore{
+		db: db,
+	}
+}
+
+type User struct {
+	id    int
+	email string
+	name  string
+}
+
+func (u User) Email() string {
+	return u.email
+}
+
+func (u User) Name() string {
+	return u.name
+}
+
+func (u User) ID() int {
+	return u.id
+}
+
+func (s *UserStore) IsNotFoundErr(err error) bool {
+	return err == errNotFound
+}
+
+func (s *UserStore) FindByEmail(ctx context.Context, email string) (models.User, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	for _, u := range s.db.data.users {
+		if u.email == email {
+			return u, nil
+		}
+	}
+
+	return User{}, errNotFound
+}
+
+func (s *UserStore) Create(ctx context.Context, email, name string) (models.User, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	if len(email) > maxNameLen || len(name) > maxNameLen {
+		return User{}, fmt.Errorf("create-user failed %w", errTooLong)
+	}
+	for _, u := range s.db.data.users {
+		if u.email == email {
+			return User{}, fmt.Errorf("create-user failed %w", errDuplicate)
+		}
+	}
+
+	s.db.data.lastUserID++
+	u := User{
+		id:    s.db.data.lastUserID,
+		email: email,
+		name:  name,
+	}
+	s.db.data.users = append(s.db.data.users, u)
+
+	return u, nil
+}
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// Cooked records a time a recipe was made
+type Cooked struct {
+	ID       int       `json:"id"`
+	RecipeID int       `json:"recipeId"`
+	CookedAt time.Time `json:"cookedAt"`
+	// Rating is from 1 to 5, or zero if not rated
+	Rating int    `json:"rating,omitempty"`
+	Notes  string `json:"notes,omitempty"`
+	// Leftovers is how many portions are left for later meals
+	Leftovers int `json:"leftovers,omitempty"`
+}
@@ -0,0 +1,35 @@
+package models
+
+// EventType says what changed
+type EventType string
+
+const (
+	RecipeCreated EventType = "recipe-created"
+	// RecipeChanged is a recipe's rating, favourite or merged duplicates
+	// changing
+	RecipeChanged    EventType = "recipe-changed"
+	RecipeDeleted    EventType = "recipe-deleted"
+	RecipeCooked     EventType = "recipe-cooked"
+	LeftoversChanged EventType = "leftovers-changed"
+	// PlanChanged is a week's plan being saved or filled from a template
+	// or the rules, and ShoppingListChanged the plan's shopping list
+	// changing with it
+	PlanChanged         EventType = "plan-changed"
+	ShoppingListChanged EventType = "shopping-list-changed"
+	// PlanTemplatesChanged is a plan template or rule being saved or
+	// deleted
+	PlanTemplatesChanged EventType = "plan-templates-changed"
+	// Resync means events may have been missed, so clients should fetch
+	// what they show again
+	Resync EventType = "resync"
+)
+
+// Event tells a user's other clients that their data changed, so that
+// plans and shopping lists showing it can be refreshed
+type Event struct {
+	Type     EventType `json:"type"`
+	RecipeID int       `json:"recipeId,omitempty"`
+	// Week is the Monday of the plan changed, or empty if any week's plan
+	// may have
+	Week string `json:"week,omitempty"`
+}
@@ -0,0 +1,48 @@
+package models
+
+import "math"
+
+// Macros are an amount of energy, in kcal, and of macronutrients, in grams
+type Macros struct {
+	Calories     float64 `json:"calories"`
+	Protein      float64 `json:"protein"`
+	Fat          float64 `json:"fat"`
+	Carbohydrate float64 `json:"carbohydrate"`
+}
+
+func (m Macros) Add(o Macros) Macros {
+	return Macros{
+		Calories:     m.Calories + o.Calories,
+		Protein:      m.Protein + o.Protein,
+		Fat:          m.Fat + o.Fat,
+		Carbohydrate: m.Carbohydrate + o.Carbohydrate,
+	}
+}
+
+func (m Macros) Scale(f float64) Macros {
+	return Macros{
+		Calories:     m.Calories * f,
+		Protein:      m.Protein * f,
+		Fat:          m.Fat * f,
+		Carbohydrate: m.Carbohydrate * f,
+	}
+}
+
+// Round rounds each amount to one decimal place
+func (m Macros) Round() Macros {
+	r := func(v float64) float64 { return math.Round(v*10) / 10 }
+	return Macros{
+		Calories:     r(m.Calories),
+		Protein:      r(m.Protein),
+		Fat:          r(m.Fat),
+		Carbohydrate: r(m.Carbohydrate),
+	}
+}
+
+// Nutrition is an estimate of a recipe's nutrients
+type Nutrition struct {
+	PerServing Macros `json:"perServing"`
+	// Missing are the ingredients left out of the estimate because their
+	// nutrients aren't known
+	Missing []string `json:"missing,omitempty"`
+}
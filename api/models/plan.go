@@ -0,0 +1,156 @@
+package models
+
+import "sort"
+
+// Meal is a time of day a plan has a slot for
+type Meal string
+
+const (
+	Breakfast Meal = "breakfast"
+	Lunch     Meal = "lunch"
+	Dinner    Meal = "dinner"
+)
+
+// Meals are the meals of a day, in the order they are eaten
+var Meals = []Meal{Breakfast, Lunch, Dinner}
+
+// Slot is a meal on a day of the week, 0 for Monday to 6 for Sunday, and
+// the recipe planned for it
+type Slot struct {
+	Day      int  `json:"day"`
+	Meal     Meal `json:"meal"`
+	RecipeID int  `json:"recipeId"`
+	// Portions is how many portions are eaten at the slot, 1 if it isn't
+	// set
+	Portions int `json:"portions,omitempty"`
+	// LeftoverOf is the earlier slot of the week whose leftovers are eaten
+	// at this one, which is nil if the slot is cooked
+	LeftoverOf *SlotRef `json:"leftoverOf,omitempty"`
+}
+
+// SlotRef names a slot of a plan by its day and meal
+type SlotRef struct {
+	Day  int  `json:"day"`
+	Meal Meal `json:"meal"`
+}
+
+// Ref names the slot
+func (s Slot) Ref() SlotRef {
+	return SlotRef{Day: s.Day, Meal: s.Meal}
+}
+
+// Eats is how many portions are eaten at the slot
+func (s Slot) Eats() int {
+	if s.Portions < 1 {
+		return 1
+	}
+	return s.Portions
+}
+
+// Before is whether the slot comes earlier in the week than o
+func (s SlotRef) Before(o SlotRef) bool {
+	if s.Day != o.Day {
+		return s.Day < o.Day
+	}
+	return mealOrder(s.Meal) < mealOrder(o.Meal)
+}
+
+// Plan is what a user will eat in the week starting on the Monday Week,
+// e.g. "2026-10-19". Meals with nothing planned have no slot.
+type Plan struct {
+	UserID int    `json:"-"`
+	Week   string `json:"week"`
+	Slots  []Slot `json:"slots"`
+	// Nutrition is worked out from the recipes when a plan is read or
+	// saved, and isn't stored
+	Nutrition *PlanNutrition `json:"nutrition,omitempty"`
+	Cost      *PlanCost      `json:"cost,omitempty"`
+	// Leftovers are worked out like Nutrition
+	Leftovers []Batch `json:"leftovers,omitempty"`
+}
+
+// Batch is a slot which cooks more portions than are eaten at it, and how
+// many of them are left after the plan's leftover slots
+type Batch struct {
+	SlotRef
+	RecipeID  int `json:"recipeId"`
+	Cooked    int `json:"cooked"`
+	Remaining int `json:"remaining"`
+}
+
+// PlanNutrition totals a plan's estimated nutrients for one person having
+// a serving at each slot
+type PlanNutrition struct {
+	// Days are Monday to Sunday
+	Days []Macros `json:"days"`
+	Week Macros   `json:"week"`
+	// Missing are the ingredients left out because their nutrients aren't
+	// known
+	Missing []string `json:"missing,omitempty"`
+}
+
+// PlanCost is an estimate of what a plan's meals cost, in minor units.
+// Each slot, leftover slots included, costs the share of its recipe's
+// servings eaten at it.
+type PlanCost struct {
+	Total int `json:"total"`
+	// Missing are the ingredients left out because they have no price
+	Missing []string `json:"missing,omitempty"`
+	// Budget is only set if the user has a weekly budget
+	Budget *BudgetReport `json:"budget,omitempty"`
+}
+
+// BudgetReport compares a plan's cost with the user's weekly budget
+type BudgetReport struct {
+	Weekly int `json:"weekly"`
+	// Remaining is what is left of the budget, negative if the plan is
+	// over it
+	Remaining int  `json:"remaining"`
+	Over      bool `json:"over"`
+}
+
+// PlanTemplate is a named week layout which can be applied to any week
+type PlanTemplate struct {
+	ID     int    `json:"id"`
+	UserID int    `json:"-"`
+	Name   string `json:"name"`
+	Slots  []Slot `json:"slots"`
+}
+
+// PlanRule plans a recipe for the same slot every week, e.g. fish and
+// chips every Friday dinner
+type PlanRule struct {
+	ID     int `json:"id"`
+	UserID int `json:"-"`
+	Slot
+}
+
+// SlotConflict is a slot a template or rule would have filled, but which
+// already had another recipe planned
+type SlotConflict struct {
+	Slot
+	PlannedRecipeID int `json:"plannedRecipeId"`
+}
+
+// PlanChange is a plan after a template or rules were applied to it, with
+// the slots they couldn't fill
+type PlanChange struct {
+	Plan      Plan           `json:"plan"`
+	Conflicts []SlotConflict `json:"conflicts"`
+}
+
+// SortSlots orders slots by day, then meal
+func SortSlots(slots []Slot) {
+	sort.SliceStable(slots, func(i, j int) bool {
+		return slots[i].Ref().Before(slots[j].Ref())
+	})
+}
+
+func mealOrder(meal Meal) int {
+	for i, m := range Meals {
+		if m == meal {
+			return i
+		}
+	}
+	return len(Meals)
+}
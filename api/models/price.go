@@ -0,0 +1,30 @@
+package models
+
+// Price is what a user pays for an ingredient at a store, e.g. 120 for
+// 500 g of pasta
+type Price struct {
+	ID         int    `json:"id"`
+	UserID     int    `json:"-"`
+	Ingredient string `json:"ingredient"`
+	Store      string `json:"store,omitempty"`
+	// Price is in minor units, e.g. pence, for Quantity of Unit
+	Price    int     `json:"price"`
+	Quantity float64 `json:"quantity"`
+	Unit     string  `json:"unit,omitempty"`
+}
+
+// Cost is an estimate of what a recipe costs, in minor units
+type Cost struct {
+	Total      int `json:"total"`
+	PerServing int `json:"perServing"`
+	// Missing are the ingredients left out of the estimate because they
+	// have no price
+	Missing []string `json:"missing,omitempty"`
+}
+
+// Budget is what a user means to spend on food each week, in minor units.
+// A budget of 0 means none is set.
+type Budget struct {
+	UserID int `json:"-"`
+	Weekly int `json:"weekly"`
+}
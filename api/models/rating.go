@@ -0,0 +1,10 @@
+package models
+
+// Rating is what one user thinks of a recipe
+type Rating struct {
+	RecipeID int `json:"-"`
+	UserID   int `json:"-"`
+	// Rating is from 1 to 5, or zero if not rated
+	Rating    int  `json:"rating,omitempty"`
+	Favourite bool `json:"favourite"`
+}
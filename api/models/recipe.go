@@ -1,7 +1,45 @@
 package models
 
+import "time"
+
 type Recipe struct {
 	Name   string `json:"name"`
 	ID     int    `json:"id"`
 	UserID int    `json:"-"`
+	// Servings is how many people the recipe feeds, or zero if unknown
+	Servings int `json:"servings,omitempty"`
+	// LastCookedAt is nil if the recipe has never been cooked
+	LastCookedAt *time.Time `json:"lastCookedAt,omitempty"`
+
+	// Score is the average of everyone's ratings and LowestRating the
+	// worst of them, both zero if nobody has rated the recipe. Only a
+	// recipe's owner can rate it until there are households, so for now
+	// both are the owner's rating. Favourite is whether the user listing
+	// recipes has marked it as one.
+	Score        float64 `json:"score,omitempty"`
+	LowestRating int     `json:"lowestRating,omitempty"`
+	Favourite    bool    `json:"favourite,omitempty"`
+
+	// Ingredients and Steps are only loaded for a single recipe
+	Ingredients []Ingredient `json:"ingredients,omitempty"`
+	Steps       []Step       `json:"steps,omitempty"`
+	Nutrition   *Nutrition   `json:"nutrition,omitempty"`
+	Cost        *Cost        `json:"cost,omitempty"`
+}
+
+type Ingredient struct {
+	Name string `json:"name"`
+	// Quantity is zero for ingredients without one, e.g. "salt to taste"
+	Quantity float64 `json:"quantity,omitempty"`
+	Unit     string  `json:"unit,omitempty"`
+}
+
+// Step is one instruction in a recipe's method
+type Step struct {
+	Instruction string `json:"instruction"`
+	// DurationSeconds is how long the step's timer runs, or zero if it
+	// has no timer
+	DurationSeconds int `json:"durationSeconds,omitempty"`
+	// Ingredients are the indexes of the recipe ingredients used in the step
+	Ingredients []int `json:"ingredients,omitempty"`
 }
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+type Session struct {
+	ID         string    `json:"id"`
+	UserID     int       `json:"-"`
+	UserAgent  string    `json:"userAgent"`
+	IP         string    `json:"ip"`
+	CreatedAt  time.Time `json:"createdAt"`
+	LastSeenAt time.Time `json:"lastSeenAt"`
+	Current    bool      `json:"current"`
+}
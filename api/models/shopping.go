@@ -0,0 +1,19 @@
+package models
+
+// ShoppingList is what to buy for some recipes, grouped by category
+type ShoppingList struct {
+	Categories []ShoppingCategory `json:"categories"`
+}
+
+type ShoppingCategory struct {
+	Name  string         `json:"name"`
+	Items []ShoppingItem `json:"items"`
+}
+
+// ShoppingItem is an ingredient to buy. Items without a quantity, like
+// salt to taste, are only needed if there is none in the cupboard.
+type ShoppingItem struct {
+	Name     string  `json:"name"`
+	Quantity float64 `json:"quantity,omitempty"`
+	Unit     string  `json:"unit,omitempty"`
+}
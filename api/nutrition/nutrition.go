@@ -0,0 +1,194 @@
+/*
+Package nutrition estimates the calories and macronutrients of recipes from
+a table of nutrient values per 100 g of each food, such as an export of the
+USDA food composition tables.
+*/
+package nutrition
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/kieron-pivotal/menu-planner-app/models"
+	"github.com/kieron-pivotal/menu-planner-app/units"
+)
+
+//go:embed nutrients.csv
+var defaultCSV []byte
+
+// columns are the CSV headers Load needs. Other columns are ignored.
+var columns = []string{"name", "kcal", "protein", "fat", "carbohydrate"}
+
+type food struct {
+	per100g models.Macros
+	// eachG is the weight of one item, for quantities without a unit
+	eachG float64
+}
+
+// Table maps food names to their nutrients
+type Table struct {
+	foods map[string]food
+}
+
+// Default returns the small built-in table of common ingredients
+func Default() *Table {
+	t, err := Load(bytes.NewReader(defaultCSV))
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// LoadFile loads a table from a CSV file, see Load
+func LoadFile(path string) (*Table, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("nutrition: %w", err)
+	}
+	defer f.Close()
+
+	return Load(f)
+}
+
+// Load reads a CSV table with a header row. It needs name, kcal, protein,
+// fat and carbohydrate columns, with values per 100 g. Optional columns are
+// aliases, semicolon separated other names for the food, and each_g, the
+// weight of one item.
+func Load(r io.Reader) (*Table, error) {
+	rows, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("nutrition: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("nutrition: no header row")
+	}
+
+	index := map[string]int{}
+	for i, h := range rows[0] {
+		index[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+	for _, c := range columns {
+		if _, ok := index[c]; !ok {
+			return nil, fmt.Errorf("nutrition: missing %s column", c)
+		}
+	}
+
+	t := &Table{foods: map[string]food{}}
+	for n, row := range rows[1:] {
+		line := n + 2
+		number := func(column string) (float64, error) {
+			i, ok := index[column]
+			if !ok || strings.TrimSpace(row[i]) == "" {
+				return 0, nil
+			}
+			v, err := strconv.ParseFloat(strings.TrimSpace(row[i]), 64)
+			if err != nil {
+				return 0, fmt.Errorf("nutrition: line %d: invalid %s %q", line, column, row[i])
+			}
+			return v, nil
+		}
+
+		f := food{}
+		for _, v := range []struct {
+			column string
+			value  *float64
+		}{
+			{"kcal", &f.per100g.Calories},
+			{"protein", &f.per100g.Protein},
+			{"fat", &f.per100g.Fat},
+			{"carbohydrate", &f.per100g.Carbohydrate},
+			{"each_g", &f.eachG},
+		} {
+			if *v.value, err = number(v.column); err != nil {
+				return nil, err
+			}
+		}
+
+		names := []string{row[index["name"]]}
+		if i, ok := index["aliases"]; ok {
+			names = append(names, strings.Split(row[i], ";")...)
+		}
+		for _, name := range names {
+			if name = normalize(name); name != "" {
+				t.foods[name] = f
+			}
+		}
+	}
+
+	return t, nil
+}
+
+// ForRecipe estimates a recipe's nutrients per serving. Recipes without a
+// number of servings count as one serving. Ingredients without a quantity,
+// like "salt to taste", are assumed to be negligible. Ingredients which
+// aren't in the table, or whose quantity can't be converted to grams, are
+// left out and listed as missing.
+func (t *Table) ForRecipe(recipe models.Recipe) *models.Nutrition {
+	total := models.Macros{}
+	res := &models.Nutrition{}
+
+	for _, ing := range recipe.Ingredients {
+		if ing.Quantity == 0 {
+			continue
+		}
+
+		f, ok := t.lookup(ing.Name)
+		if !ok {
+			res.Missing = append(res.Missing, ing.Name)
+			continue
+		}
+
+		g, ok := units.Grams(ing.Quantity, ing.Unit, f.eachG)
+		if !ok {
+			res.Missing = append(res.Missing, ing.Name)
+			continue
+		}
+
+		total = total.Add(f.per100g.Scale(g / 100))
+	}
+
+	servings := recipe.Servings
+	if servings < 1 {
+		servings = 1
+	}
+	res.PerServing = total.Scale(1 / float64(servings)).Round()
+
+	return res
+}
+
+// lookup finds a food by name, its singular, or failing those the longest
+// food name contained in it, so "free range eggs" finds "eggs"
+func (t *Table) lookup(name string) (food, bool) {
+	name = normalize(name)
+	if f, ok := t.foods[name]; ok {
+		return f, true
+	}
+	for _, suffix := range []string{"es", "s"} {
+		if f, ok := t.foods[strings.TrimSuffix(name, suffix)]; ok && strings.HasSuffix(name, suffix) {
+			return f, true
+		}
+	}
+
+	best := ""
+	padded := " " + name + " "
+	for n := range t.foods {
+		if len(n) > len(best) && strings.Contains(padded, " "+n+" ") {
+			best = n
+		}
+	}
+	if best != "" {
+		return t.foods[best], true
+	}
+
+	return food{}, false
+}
+
+func normalize(name string) string {
+	return strings.Join(strings.Fields(strings.ToLower(name)), " ")
+}
@@ -0,0 +1,13 @@
+package nutrition_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestNutrition(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Nutrition Suite")
+}
@@ -0,0 +1,90 @@
+package nutrition_test
+
+import (
+	"strings"
+
+	"github.com/kieron-pivotal/menu-planner-app/models"
+	"github.com/kieron-pivotal/menu-planner-app/nutrition"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Nutrition", func() {
+	var table *nutrition.Table
+
+	BeforeEach(func() {
+		var err error
+		table, err = nutrition.Load(strings.NewReader(`Name,Aliases,kcal,protein,fat,carbohydrate,each_g,fibre
+egg,eggs;hen's egg,143,13,9.5,0.7,50,0
+butter,,717,0.9,81,0.1,,0
+chopped tomatoes,tinned tomatoes,21,1,0.2,3.9,,1
+tomato,,18,0.9,0.2,3.9,120,1
+`))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("estimates the nutrients per serving", func() {
+		n := table.ForRecipe(models.Recipe{
+			Servings: 2,
+			Ingredients: []models.Ingredient{
+				{Name: "eggs", Quantity: 4},
+				{Name: "butter", Quantity: 20, Unit: "g"},
+			},
+		})
+
+		Expect(n.PerServing).To(Equal(models.Macros{Calories: 214.7, Protein: 13.1, Fat: 17.6, Carbohydrate: 0.7}))
+		Expect(n.Missing).To(BeEmpty())
+	})
+
+	It("counts recipes without servings as one serving", func() {
+		n := table.ForRecipe(models.Recipe{Ingredients: []models.Ingredient{{Name: "butter", Quantity: 1, Unit: "tbsp"}}})
+		Expect(n.PerServing.Calories).To(Equal(107.6))
+	})
+
+	It("matches names ignoring case, plurals and extra words", func() {
+		for _, name := range []string{"Eggs", "  EGG ", "free range eggs", "tomatoes", "tinned  tomatoes", "cherry tomato"} {
+			n := table.ForRecipe(models.Recipe{Ingredients: []models.Ingredient{{Name: name, Quantity: 100, Unit: "g"}}})
+			Expect(n.Missing).To(BeEmpty(), name)
+		}
+	})
+
+	It("prefers the longest matching name", func() {
+		n := table.ForRecipe(models.Recipe{Ingredients: []models.Ingredient{{Name: "chopped tomatoes", Quantity: 100, Unit: "g"}}})
+		Expect(n.PerServing.Calories).To(Equal(21.0))
+	})
+
+	It("lists ingredients it can't estimate", func() {
+		n := table.ForRecipe(models.Recipe{Ingredients: []models.Ingredient{
+			{Name: "unobtainium", Quantity: 1, Unit: "kg"},
+			{Name: "butter", Quantity: 1},
+			{Name: "eggs", Quantity: 1, Unit: "handful"},
+			{Name: "egg", Quantity: 1},
+		}})
+
+		Expect(n.Missing).To(Equal([]string{"unobtainium", "butter", "eggs"}))
+		Expect(n.PerServing.Calories).To(Equal(71.5))
+	})
+
+	It("ignores ingredients without a quantity", func() {
+		n := table.ForRecipe(models.Recipe{Ingredients: []models.Ingredient{{Name: "pepper"}}})
+		Expect(n.Missing).To(BeEmpty())
+		Expect(n.PerServing).To(BeZero())
+	})
+
+	It("has a built-in table", func() {
+		n := nutrition.Default().ForRecipe(models.Recipe{Ingredients: []models.Ingredient{{Name: "spaghetti", Quantity: 100, Unit: "g"}}})
+		Expect(n.PerServing.Calories).To(Equal(371.0))
+	})
+
+	Describe("loading", func() {
+		It("needs the nutrient columns", func() {
+			_, err := nutrition.Load(strings.NewReader("name,kcal,protein,fat\negg,143,13,9.5\n"))
+			Expect(err).To(MatchError("nutrition: missing carbohydrate column"))
+		})
+
+		It("rejects values which aren't numbers", func() {
+			_, err := nutrition.Load(strings.NewReader("name,kcal,protein,fat,carbohydrate\negg,lots,13,9.5,0.7\n"))
+			Expect(err).To(MatchError(`nutrition: line 2: invalid kcal "lots"`))
+		})
+	})
+})
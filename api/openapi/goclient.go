@@ -0,0 +1,482 @@
+package openapi
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// goImports are the packages a generated client may use, by name
+var goImports = map[string]string{
+	"context": "context",
+	"models":  "github.com/kieron-pivotal/menu-planner-app/models",
+	"problem": "github.com/kieron-pivotal/menu-planner-app/problem",
+	"strconv": "strconv",
+	"strings": "strings",
+	"time":    "time",
+	"url":     "net/url",
+}
+
+// goMethods orders the operations on a path in the generated code
+var goMethods = []string{"get", "put", "post", "delete", "patch"}
+
+// GoClient generates the operations of a Go client in package pkg, and a
+// basePath constant the paths are relative to. Each operation is a method of
+// a Client type, written by hand in the same package, which must have a
+// method
+//
+//	do(ctx context.Context, method, path string, query url.Values, accept string, body, out interface{}) error
+//
+// where out is nil, a *[]byte for the raw body, or a value to decode JSON
+// into. Operations answering with JSON return the decoded body; those
+// also answering in other media types get a <Name>As method returning the
+// raw body of the media type asked for. Operations with x-go-skip are left
+// out. Schemas without an x-go-type are generated as structs.
+func (d *Document) GoClient(pkg string) ([]byte, error) {
+	g := &goGen{doc: d, imports: map[string]bool{"context": true, "url": true}}
+
+	paths := make([]string, 0, len(d.Paths))
+	for path := range d.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		for _, method := range goMethods {
+			if op := d.Paths[path][method]; op != nil && !op.GoSkip {
+				if err := g.operation(strings.ToUpper(method), path, op); err != nil {
+					return nil, fmt.Errorf("openapi: %s %s: %w", strings.ToUpper(method), path, err)
+				}
+			}
+		}
+	}
+
+	names := make([]string, 0, len(d.Components.Schemas))
+	for name := range d.Components.Schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	types := &bytes.Buffer{}
+	for _, name := range names {
+		schema := d.Components.Schemas[name]
+		if schema.GoType != "" {
+			continue
+		}
+		if err := g.structType(types, name, schema); err != nil {
+			return nil, fmt.Errorf("openapi: schema %s: %w", name, err)
+		}
+	}
+
+	out := &bytes.Buffer{}
+	fmt.Fprintf(out, "// Code generated from the OpenAPI description by openapi.GoClient; DO NOT EDIT.\n\n")
+	fmt.Fprintf(out, "package %s\n\nimport (\n", pkg)
+	std, local := []string{}, []string{}
+	for name := range g.imports {
+		if path := goImports[name]; strings.Contains(path, ".") {
+			local = append(local, path)
+		} else {
+			std = append(std, path)
+		}
+	}
+	sort.Strings(std)
+	sort.Strings(local)
+	for _, path := range std {
+		fmt.Fprintf(out, "\t%q\n", path)
+	}
+	if len(local) > 0 {
+		fmt.Fprintf(out, "\n")
+	}
+	for _, path := range local {
+		fmt.Fprintf(out, "\t%q\n", path)
+	}
+	fmt.Fprintf(out, ")\n\n")
+	fmt.Fprintf(out, "// basePath is the prefix of every operation's path\n")
+	fmt.Fprintf(out, "const basePath = %q\n", d.BasePath())
+	out.Write(types.Bytes())
+	out.Write(g.out.Bytes())
+
+	src, err := format.Source(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("openapi: generated client doesn't compile: %w", err)
+	}
+	return src, nil
+}
+
+type goGen struct {
+	doc     *Document
+	imports map[string]bool
+	out     bytes.Buffer
+}
+
+func (g *goGen) structType(w *bytes.Buffer, name string, schema *Schema) error {
+	if schema.Type != "object" {
+		return fmt.Errorf("only object schemas can be generated, not %q", schema.Type)
+	}
+
+	fmt.Fprintf(w, "\n// %s is the %s schema", name, name)
+	if schema.Description != "" {
+		fmt.Fprintf(w, ": %s", clause(schema.Description))
+	}
+	fmt.Fprintf(w, "\ntype %s struct {\n", name)
+
+	props := make([]string, 0, len(schema.Properties))
+	for prop := range schema.Properties {
+		props = append(props, prop)
+	}
+	sort.Strings(props)
+
+	required := map[string]bool{}
+	for _, prop := range schema.Required {
+		required[prop] = true
+	}
+
+	for _, prop := range props {
+		t, err := g.goType(schema.Properties[prop])
+		if err != nil {
+			return fmt.Errorf("%s: %w", prop, err)
+		}
+		tag := prop
+		if !required[prop] {
+			tag += ",omitempty"
+		}
+		if desc := schema.Properties[prop].Description; desc != "" {
+			fmt.Fprintf(w, "\t// %s\n", desc)
+		}
+		fmt.Fprintf(w, "\t%s %s `json:%q`\n", exported(prop), t, tag)
+	}
+	fmt.Fprintf(w, "}\n")
+	return nil
+}
+
+// goType is the Go type of values of a schema
+func (g *goGen) goType(s *Schema) (string, error) {
+	if s.Ref != "" {
+		name := RefName(s.Ref)
+		target, ok := g.doc.Components.Schemas[name]
+		if !ok {
+			return "", fmt.Errorf("unknown schema %s", s.Ref)
+		}
+		if target.GoType == "" {
+			return name, nil
+		}
+		if pkg := strings.SplitN(target.GoType, ".", 2); len(pkg) == 2 {
+			if _, ok := goImports[pkg[0]]; !ok {
+				return "", fmt.Errorf("x-go-type %s is in an unknown package", target.GoType)
+			}
+			g.imports[pkg[0]] = true
+		}
+		return target.GoType, nil
+	}
+
+	switch s.Type {
+	case "array":
+		if s.Items == nil {
+			return "", fmt.Errorf("array without items")
+		}
+		t, err := g.goType(s.Items)
+		return "[]" + t, err
+	case "integer":
+		return "int", nil
+	case "number":
+		return "float64", nil
+	case "boolean":
+		return "bool", nil
+	case "string":
+		if s.Format == "date-time" {
+			g.imports["time"] = true
+			return "time.Time", nil
+		}
+		return "string", nil
+	case "object":
+		if len(s.Properties) == 0 {
+			return "map[string]interface{}", nil
+		}
+	}
+	return "", fmt.Errorf("can't generate a Go type for %q", s.Type)
+}
+
+func (g *goGen) operation(method, path string, op *Operation) error {
+	if op.OperationID == "" {
+		return fmt.Errorf("no operationId")
+	}
+	name := exported(op.OperationID)
+
+	args := []string{"ctx context.Context"}
+	pathExpr, err := g.pathExpr(path, op, &args)
+	if err != nil {
+		return err
+	}
+
+	query := []Parameter{}
+	for _, p := range op.Parameters {
+		if p.In == "query" && !p.GoSkip {
+			query = append(query, p)
+		}
+	}
+	if len(query) > 0 {
+		if err = g.paramsType(name, query); err != nil {
+			return err
+		}
+		args = append(args, "params "+name+"Params")
+	}
+
+	body := "nil"
+	if op.RequestBody != nil {
+		media, ok := op.RequestBody.Content["application/json"]
+		if !ok || media.Schema == nil {
+			return fmt.Errorf("request bodies must be JSON")
+		}
+		t, err := g.goType(media.Schema)
+		if err != nil {
+			return err
+		}
+		args = append(args, "body "+t)
+		body = "body"
+	}
+
+	res, err := success(op)
+	if err != nil {
+		return err
+	}
+
+	call := func(accept, out string) string {
+		return fmt.Sprintf("c.do(ctx, %q, %s, %s, %s, %s, %s)", method, pathExpr, g.queryExpr(query), accept, body, out)
+	}
+
+	doc := comment(name, fmt.Sprintf("calls %s %s: %s", method, path, clause(op.Summary)))
+	argList := strings.Join(args, ", ")
+
+	others := []string{}
+	for media := range res.Content {
+		if media != "application/json" {
+			others = append(others, media)
+		}
+	}
+	sort.Strings(others)
+
+	json, hasJSON := res.Content["application/json"]
+	switch {
+	case len(res.Content) == 0:
+		fmt.Fprintf(&g.out, "\n%sfunc (c *Client) %s(%s) error {\n", doc, name, argList)
+		fmt.Fprintf(&g.out, "\treturn %s\n}\n", call(`""`, "nil"))
+	case hasJSON:
+		t, err := g.goType(json.Schema)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&g.out, "\n%sfunc (c *Client) %s(%s) (%s, error) {\n", doc, name, argList, t)
+		fmt.Fprintf(&g.out, "\tvar out %s\n\terr := %s\n\treturn out, err\n}\n", t, call(`"application/json"`, "&out"))
+
+		if len(others) > 0 {
+			doc = comment(name+"As", fmt.Sprintf("is %s returning the body as accept, one of %s", name, strings.Join(others, ", ")))
+			fmt.Fprintf(&g.out, "\n%sfunc (c *Client) %sAs(%s, accept string) ([]byte, error) {\n", doc, name, argList)
+			fmt.Fprintf(&g.out, "\tvar out []byte\n\terr := %s\n\treturn out, err\n}\n", call("accept", "&out"))
+		}
+	default:
+		fmt.Fprintf(&g.out, "\n%sfunc (c *Client) %s(%s) ([]byte, error) {\n", doc, name, argList)
+		fmt.Fprintf(&g.out, "\tvar out []byte\n\terr := %s\n\treturn out, err\n}\n",
+			call(fmt.Sprintf("%q", strings.Join(others, ", ")), "&out"))
+	}
+	return nil
+}
+
+// success returns the operation's first 2xx response
+func success(op *Operation) (Response, error) {
+	codes := []string{}
+	for code := range op.Responses {
+		if strings.HasPrefix(code, "2") {
+			codes = append(codes, code)
+		}
+	}
+	if len(codes) == 0 {
+		return Response{}, fmt.Errorf("no 2xx response")
+	}
+	sort.Strings(codes)
+	return op.Responses[codes[0]], nil
+}
+
+// pathExpr returns a Go expression building the path, adding its
+// parameters to args
+func (g *goGen) pathExpr(path string, op *Operation, args *[]string) (string, error) {
+	parts := []string{}
+	rest := path
+	for {
+		start := strings.Index(rest, "{")
+		if start < 0 {
+			break
+		}
+		end := strings.Index(rest, "}")
+		if end < start {
+			return "", fmt.Errorf("unbalanced braces in path")
+		}
+		parts = append(parts, fmt.Sprintf("%q", rest[:start]))
+
+		name := rest[start+1 : end]
+		var param *Parameter
+		for i, p := range op.Parameters {
+			if p.In == "path" && p.Name == name {
+				param = &op.Parameters[i]
+			}
+		}
+		if param == nil || param.Schema == nil {
+			return "", fmt.Errorf("path parameter %s isn't described", name)
+		}
+
+		arg := unexported(name)
+		switch param.Schema.Type {
+		case "integer":
+			*args = append(*args, arg+" int")
+			parts = append(parts, "strconv.Itoa("+arg+")")
+			g.imports["strconv"] = true
+		case "string":
+			*args = append(*args, arg+" string")
+			parts = append(parts, "url.PathEscape("+arg+")")
+		default:
+			return "", fmt.Errorf("path parameter %s must be an integer or string", name)
+		}
+		rest = rest[end+1:]
+	}
+	if rest != "" || len(parts) == 0 {
+		parts = append(parts, fmt.Sprintf("%q", rest))
+	}
+	return strings.Join(parts, " + "), nil
+}
+
+func (g *goGen) paramsType(name string, query []Parameter) error {
+	fmt.Fprintf(&g.out, "\n// %sParams are the query parameters of %s. Zero values are left out.\n", name, name)
+	fmt.Fprintf(&g.out, "type %sParams struct {\n", name)
+	for _, p := range query {
+		if p.Schema == nil {
+			return fmt.Errorf("query parameter %s has no schema", p.Name)
+		}
+		t, err := g.goType(p.Schema)
+		if err != nil {
+			return fmt.Errorf("query parameter %s: %w", p.Name, err)
+		}
+		if p.Description != "" {
+			fmt.Fprintf(&g.out, "\t// %s\n", p.Description)
+		}
+		fmt.Fprintf(&g.out, "\t%s %s\n", exported(p.Name), t)
+	}
+	fmt.Fprintf(&g.out, "}\n")
+
+	g.valuesMethod(name, query)
+	return nil
+}
+
+// queryExpr returns a Go expression for the url.Values of an operation's
+// query parameters
+func (g *goGen) queryExpr(query []Parameter) string {
+	if len(query) == 0 {
+		return "nil"
+	}
+	return "params.values()"
+}
+
+// valuesMethod writes a values method turning an operation's parameters
+// into a query
+func (g *goGen) valuesMethod(name string, query []Parameter) {
+	w := &g.out
+	fmt.Fprintf(w, "\nfunc (p %sParams) values() url.Values {\n\tq := url.Values{}\n", name)
+	for _, param := range query {
+		field := "p." + exported(param.Name)
+		switch param.Schema.Type {
+		case "integer":
+			fmt.Fprintf(w, "\tif %s != 0 {\n\t\tq.Set(%q, strconv.Itoa(%s))\n\t}\n", field, param.Name, field)
+			g.imports["strconv"] = true
+		case "boolean":
+			fmt.Fprintf(w, "\tif %s {\n\t\tq.Set(%q, \"true\")\n\t}\n", field, param.Name)
+		case "array":
+			fmt.Fprintf(w, "\tif len(%s) > 0 {\n\t\tvalues := []string{}\n", field)
+			fmt.Fprintf(w, "\t\tfor _, v := range %s {\n", field)
+			if param.Schema.Items != nil && param.Schema.Items.Type == "integer" {
+				fmt.Fprintf(w, "\t\t\tvalues = append(values, strconv.Itoa(v))\n")
+				g.imports["strconv"] = true
+			} else {
+				fmt.Fprintf(w, "\t\t\tvalues = append(values, v)\n")
+			}
+			fmt.Fprintf(w, "\t\t}\n\t\tq.Set(%q, strings.Join(values, \",\"))\n\t}\n", param.Name)
+			g.imports["strings"] = true
+		default:
+			fmt.Fprintf(w, "\tif %s != \"\" {\n\t\tq.Set(%q, %s)\n\t}\n", field, param.Name, field)
+		}
+	}
+	fmt.Fprintf(w, "\treturn q\n}\n")
+}
+
+// comment is a doc comment for name
+func comment(name, text string) string {
+	return "// " + name + " " + text + "\n"
+}
+
+// clause turns a sentence into a clause to follow a colon, e.g. "Who is
+// logged in." into "who is logged in"
+func clause(s string) string {
+	s = strings.TrimSuffix(s, ".")
+	runes := []rune(s)
+	if len(runes) > 1 && unicode.IsUpper(runes[0]) && unicode.IsLower(runes[1]) {
+		runes[0] = unicode.ToLower(runes[0])
+	}
+	return string(runes)
+}
+
+// initialisms are written in capitals in Go names
+var initialisms = map[string]bool{"CSRF": true, "ID": true, "IP": true, "JSON": true, "PDF": true, "URL": true}
+
+// exported turns a JSON or operation name, e.g. getCSRFToken or idToken,
+// into an exported Go name, e.g. GetCSRFToken or IDToken
+func exported(name string) string {
+	b := &strings.Builder{}
+	for _, word := range words(name) {
+		if upper := strings.ToUpper(word); initialisms[upper] {
+			b.WriteString(upper)
+			continue
+		}
+		b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+	return b.String()
+}
+
+// words splits a name at punctuation and changes of case, keeping runs
+// of capitals together, e.g. "getCSRFToken" into "get", "CSRF", "Token"
+func words(name string) []string {
+	res := []string{}
+	runes := []rune(name)
+	start := -1
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if start >= 0 {
+				res = append(res, string(runes[start:i]))
+			}
+			start = -1
+			continue
+		}
+		if start >= 0 && unicode.IsUpper(r) && (unicode.IsLower(runes[i-1]) ||
+			(i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))) {
+			res = append(res, string(runes[start:i]))
+			start = -1
+		}
+		if start < 0 {
+			start = i
+		}
+	}
+	if start >= 0 {
+		res = append(res, string(runes[start:]))
+	}
+	return res
+}
+
+// unexported turns a name into an unexported Go name, e.g. for arguments
+func unexported(name string) string {
+	for _, r := range name {
+		if !unicode.IsLower(r) && !unicode.IsDigit(r) {
+			s := exported(name)
+			return strings.ToLower(s[:1]) + s[1:]
+		}
+	}
+	return name
+}
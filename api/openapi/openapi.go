@@ -0,0 +1,132 @@
+/*
+Package openapi describes the API in OpenAPI 3. The description is kept by
+hand in openapi.json, served at /openapi.json and /api/v1/openapi.json, and
+the client package is generated from it, so it must change whenever a route
+does.
+*/
+package openapi
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+)
+
+//go:embed openapi.json
+var spec []byte
+
+// Document is the part of an OpenAPI document the API uses
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Servers    []Server            `json:"servers"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components struct {
+		Schemas map[string]*Schema `json:"schemas"`
+	} `json:"components"`
+}
+
+// Server is where the paths are served, relative to the API's host
+type Server struct {
+	URL         string `json:"url"`
+	Description string `json:"description"`
+}
+
+// BasePath returns the prefix of every path, from the first server
+func (d *Document) BasePath() string {
+	if len(d.Servers) == 0 {
+		return ""
+	}
+	return d.Servers[0].URL
+}
+
+// PathItem maps lower case HTTP methods to the operations on a path
+type PathItem map[string]*Operation
+
+type Operation struct {
+	OperationID string              `json:"operationId"`
+	Summary     string              `json:"summary"`
+	Description string              `json:"description"`
+	Parameters  []Parameter         `json:"parameters"`
+	RequestBody *RequestBody        `json:"requestBody"`
+	Responses   map[string]Response `json:"responses"`
+	// GoSkip leaves the operation out of the generated client, e.g. a
+	// stream it can't read whole
+	GoSkip bool `json:"x-go-skip"`
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description"`
+	Required    bool    `json:"required"`
+	Schema      *Schema `json:"schema"`
+	// GoSkip leaves the parameter out of the generated client, e.g. when
+	// the client sets it another way
+	GoSkip bool `json:"x-go-skip"`
+}
+
+type RequestBody struct {
+	Description string               `json:"description"`
+	Required    bool                 `json:"required"`
+	Content     map[string]MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Schema struct {
+	Ref         string             `json:"$ref"`
+	Type        string             `json:"type"`
+	Format      string             `json:"format"`
+	Description string             `json:"description"`
+	Items       *Schema            `json:"items"`
+	Properties  map[string]*Schema `json:"properties"`
+	Required    []string           `json:"required"`
+	ReadOnly    bool               `json:"readOnly"`
+	// GoType is the Go type a schema is decoded into, e.g.
+	// "models.Recipe". The client generates types for schemas without one.
+	GoType string `json:"x-go-type"`
+}
+
+// JSON returns the description as served
+func JSON() []byte {
+	return spec
+}
+
+// Load parses the description
+func Load() (*Document, error) {
+	doc := &Document{}
+	if err := json.Unmarshal(spec, doc); err != nil {
+		return nil, fmt.Errorf("openapi: %w", err)
+	}
+	return doc, nil
+}
+
+// Operation returns the operation for an HTTP method on a path template,
+// e.g. "/recipes/{id}", or nil if there isn't one
+func (d *Document) Operation(method, path string) *Operation {
+	return d.Paths[path][strings.ToLower(method)]
+}
+
+// RefName returns the name of the component schema a $ref points to
+func RefName(ref string) string {
+	return strings.TrimPrefix(ref, "#/components/schemas/")
+}
+
+// ServeSpec serves the description
+func ServeSpec(w http.ResponseWriter, r *http.Request) {
+	w.Header().Add("Content-Type", "application/json")
+
+	if _, err := w.Write(spec); err != nil {
+		log.Printf("openapi-serve: %v\n", err)
+	}
+}
@@ -0,0 +1,129 @@
+package openapi_test
+
+import (
+	"strings"
+
+	"github.com/kieron-pivotal/menu-planner-app/openapi"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("OpenAPI", func() {
+	var doc *openapi.Document
+
+	BeforeEach(func() {
+		var err error
+		doc, err = openapi.Load()
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("is OpenAPI 3", func() {
+		Expect(doc.OpenAPI).To(HavePrefix("3."))
+	})
+
+	It("serves the paths under /api/v1", func() {
+		Expect(doc.BasePath()).To(Equal("/api/v1"))
+	})
+
+	It("finds operations by method and path template", func() {
+		op := doc.Operation("GET", "/recipes/{id}")
+		Expect(op).NotTo(BeNil())
+		Expect(op.OperationID).To(Equal("getRecipe"))
+		Expect(doc.Operation("PATCH", "/recipes/{id}")).To(BeNil())
+		Expect(doc.Operation("GET", "/nowhere")).To(BeNil())
+	})
+
+	It("names every operation differently", func() {
+		seen := map[string]string{}
+		for path, item := range doc.Paths {
+			for method, op := range item {
+				route := strings.ToUpper(method) + " " + path
+				Expect(op.OperationID).NotTo(BeEmpty(), route)
+				Expect(seen).NotTo(HaveKey(op.OperationID), route)
+				seen[op.OperationID] = route
+			}
+		}
+	})
+
+	It("describes every path parameter", func() {
+		for path, item := range doc.Paths {
+			for _, op := range item {
+				for _, segment := range strings.Split(path, "{")[1:] {
+					name := strings.SplitN(segment, "}", 2)[0]
+					found := false
+					for _, p := range op.Parameters {
+						found = found || (p.In == "path" && p.Name == name && p.Required)
+					}
+					Expect(found).To(BeTrue(), "%s %s doesn't describe {%s}", op.OperationID, path, name)
+				}
+			}
+		}
+	})
+
+	It("only refers to schemas it has", func() {
+		var check func(where string, s *openapi.Schema)
+		check = func(where string, s *openapi.Schema) {
+			if s == nil {
+				return
+			}
+			if s.Ref != "" {
+				Expect(doc.Components.Schemas).To(HaveKey(openapi.RefName(s.Ref)), where)
+			}
+			check(where, s.Items)
+			for name, prop := range s.Properties {
+				check(where+"."+name, prop)
+			}
+		}
+
+		for name, s := range doc.Components.Schemas {
+			check(name, s)
+		}
+		for _, item := range doc.Paths {
+			for _, op := range item {
+				for _, p := range op.Parameters {
+					check(op.OperationID+" "+p.Name, p.Schema)
+				}
+				if op.RequestBody != nil {
+					for _, media := range op.RequestBody.Content {
+						check(op.OperationID+" body", media.Schema)
+					}
+				}
+				for code, res := range op.Responses {
+					for _, media := range res.Content {
+						check(op.OperationID+" "+code, media.Schema)
+					}
+				}
+			}
+		}
+	})
+
+	Describe("GoClient", func() {
+		It("generates a method for each operation", func() {
+			src, err := doc.GoClient("client")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(src)).To(SatisfyAll(
+				HavePrefix("// Code generated"),
+				ContainSubstring("\npackage client\n"),
+				ContainSubstring("\nconst basePath = \"/api/v1\"\n"),
+				ContainSubstring("func (c *Client) GetRecipe(ctx context.Context, id int) (models.Recipe, error) {"),
+				ContainSubstring("func (c *Client) RevokeSession(ctx context.Context, id string) error {"),
+				ContainSubstring("func (c *Client) GetRecipeCard(ctx context.Context, id int) ([]byte, error) {"),
+				ContainSubstring("func (c *Client) GetShoppingListAs(ctx context.Context, params GetShoppingListParams, accept string) ([]byte, error) {"),
+				ContainSubstring("func (c *Client) GetCSRFToken(ctx context.Context) (CSRFToken, error) {"),
+				ContainSubstring("\tIDToken string `json:\"idToken\"`\n"),
+			))
+		})
+
+		It("leaves out parameters the client sets another way", func() {
+			src, err := doc.GoClient("client")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(src)).NotTo(ContainSubstring(`q.Set("format"`))
+		})
+
+		It("leaves out operations the client can't call", func() {
+			src, err := doc.GoClient("client")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(src)).NotTo(ContainSubstring("StreamEvents"))
+		})
+	})
+})
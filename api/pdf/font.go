@@ -0,0 +1,84 @@
+package pdf
+
+// winAnsi are the characters of Windows-1252 outside Latin-1, which the
+// fonts are encoded in
+var winAnsi = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87,
+	'ˆ': 0x88, '‰': 0x89, 'Š': 0x8a, '‹': 0x8b, 'Œ': 0x8c, 'Ž': 0x8e,
+	'‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97,
+	'˜': 0x98, '™': 0x99, 'š': 0x9a, '›': 0x9b, 'œ': 0x9c, 'ž': 0x9e, 'Ÿ': 0x9f,
+}
+
+// encode converts text to Windows-1252, replacing characters the fonts
+// can't show with a question mark
+func encode(s string) []byte {
+	b := make([]byte, 0, len(s))
+	for _, r := range s {
+		switch {
+		case r < 0x80 || (r >= 0xa0 && r <= 0xff):
+			b = append(b, byte(r))
+		case winAnsi[r] != 0:
+			b = append(b, winAnsi[r])
+		default:
+			b = append(b, '?')
+		}
+	}
+	return b
+}
+
+// Width is how many points wide text is when drawn in font at size
+func Width(font Font, size float64, s string) float64 {
+	units := 0
+	for _, c := range encode(s) {
+		units += charWidth(font, c)
+	}
+	return float64(units) * size / 1000
+}
+
+// charWidth is the width of a character in thousandths of the font size.
+// Widths outside ASCII are only known for common punctuation and are
+// otherwise taken to be those of a lower case letter, which is close
+// enough for wrapping lines.
+func charWidth(font Font, c byte) int {
+	if c >= 32 && c <= 126 {
+		if font == Bold {
+			return boldWidths[c-32]
+		}
+		return regularWidths[c-32]
+	}
+	if w, ok := extraWidths[font][c]; ok {
+		return w
+	}
+	return 556
+}
+
+// regularWidths and boldWidths are the Helvetica metrics for ASCII
+// space to tilde
+var regularWidths = [...]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+var boldWidths = [...]int{
+	278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+	975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+	333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+	611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+}
+
+var extraWidths = map[Font]map[byte]int{
+	Regular: {
+		0x91: 222, 0x92: 222, 0x93: 333, 0x94: 333, 0x95: 350, 0x96: 556, 0x97: 1000,
+		0xa0: 278, 0xb0: 400, 0xb7: 278, 0xbc: 834, 0xbd: 834, 0xbe: 834, 0xd7: 584,
+	},
+	Bold: {
+		0x91: 278, 0x92: 278, 0x93: 500, 0x94: 500, 0x95: 350, 0x96: 556, 0x97: 1000,
+		0xa0: 278, 0xb0: 400, 0xb7: 278, 0xbc: 834, 0xbd: 834, 0xbe: 834, 0xd7: 584,
+	},
+}
@@ -0,0 +1,157 @@
+/* Package pdf writes simple PDF documents of text and lines */
+package pdf
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+)
+
+// A4 page size in points
+const (
+	A4Width  = 595.28
+	A4Height = 841.89
+)
+
+// Font is one of the standard Helvetica faces, which every PDF reader
+// has, so no font needs embedding
+type Font int
+
+const (
+	Regular Font = iota
+	Bold
+)
+
+var baseFonts = []string{"Helvetica", "Helvetica-Bold"}
+
+// Document is a PDF being drawn page by page. Positions are in points
+// from the top left corner of the page. Output depends only on what was
+// drawn, so documents can be compared byte for byte.
+type Document struct {
+	Title  string
+	width  float64
+	height float64
+	pages  []*bytes.Buffer
+}
+
+func New(width, height float64) *Document {
+	return &Document{width: width, height: height}
+}
+
+// AddPage starts a new page, which later drawing goes on
+func (d *Document) AddPage() {
+	d.pages = append(d.pages, new(bytes.Buffer))
+}
+
+// PageCount is how many pages have been added
+func (d *Document) PageCount() int {
+	return len(d.pages)
+}
+
+// Text draws a single line of text with its baseline at y
+func (d *Document) Text(x, y float64, font Font, size float64, s string) {
+	fmt.Fprintf(d.page(), "BT /F%d %s Tf %s %s Td (%s) Tj ET\n",
+		font+1, num(size), num(x), num(d.height-y), escape(encode(s)))
+}
+
+// Line draws a straight line lineWidth points thick
+func (d *Document) Line(x1, y1, x2, y2, lineWidth float64) {
+	fmt.Fprintf(d.page(), "%s w %s %s m %s %s l S\n",
+		num(lineWidth), num(x1), num(d.height-y1), num(x2), num(d.height-y2))
+}
+
+// Write writes the document, with an empty first page if nothing was
+// drawn
+func (d *Document) Write(w io.Writer) error {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+
+	bw := bufio.NewWriter(w)
+	offset := 0
+	offsets := []int{}
+	out := func(format string, args ...interface{}) {
+		n, _ := fmt.Fprintf(bw, format, args...)
+		offset += n
+	}
+	object := func(format string, args ...interface{}) {
+		offsets = append(offsets, offset)
+		out("%d 0 obj\n", len(offsets))
+		out(format, args...)
+		out("\nendobj\n")
+	}
+
+	// objects are the catalog, page tree, info, fonts and then a page and
+	// its contents for each page
+	const firstPage = 4
+	pageObject := func(i int) int {
+		return firstPage + len(baseFonts) + 2*i
+	}
+
+	out("%%PDF-1.4\n%%\xe2\xe3\xcf\xd3\n")
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+
+	kids := new(bytes.Buffer)
+	for i := range d.pages {
+		if i > 0 {
+			kids.WriteString(" ")
+		}
+		fmt.Fprintf(kids, "%d 0 R", pageObject(i))
+	}
+	object("<< /Type /Pages /Kids [%s] /Count %d >>", kids, len(d.pages))
+	object("<< /Title (%s) /Producer (menu-planner) >>", escape(encode(d.Title)))
+
+	fonts := new(bytes.Buffer)
+	for i, name := range baseFonts {
+		object("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", name)
+		fmt.Fprintf(fonts, "/F%d %d 0 R ", i+1, firstPage+i)
+	}
+
+	for i, page := range d.pages {
+		object("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources << /Font << %s>> >> /Contents %d 0 R >>",
+			num(d.width), num(d.height), fonts, pageObject(i)+1)
+		object("<< /Length %d >>\nstream\n%sendstream", page.Len(), page)
+	}
+
+	xref := offset
+	out("xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, o := range offsets {
+		out("%010d 00000 n \n", o)
+	}
+	out("trailer\n<< /Size %d /Root 1 0 R /Info 3 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return bw.Flush()
+}
+
+func (d *Document) page() *bytes.Buffer {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+	return d.pages[len(d.pages)-1]
+}
+
+// num formats a coordinate to two decimal places, without trailing zeros
+func num(f float64) string {
+	return strconv.FormatFloat(math.Round(f*100)/100, 'f', -1, 64)
+}
+
+// escape escapes a PDF string, writing bytes outside printable ASCII as
+// octal so the file stays readable
+func escape(b []byte) string {
+	s := new(bytes.Buffer)
+	for _, c := range b {
+		switch {
+		case c == '\\' || c == '(' || c == ')':
+			s.WriteByte('\\')
+			s.WriteByte(c)
+		case c < 32 || c > 126:
+			fmt.Fprintf(s, "\\%03o", c)
+		default:
+			s.WriteByte(c)
+		}
+	}
+	return s.String()
+}
@@ -0,0 +1,16 @@
+package pdf_test
+
+import (
+	"flag"
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+func TestPdf(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Pdf Suite")
+}
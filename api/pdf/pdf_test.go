@@ -0,0 +1,92 @@
+package pdf_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strconv"
+
+	"github.com/kieron-pivotal/menu-planner-app/pdf"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Pdf", func() {
+	var (
+		doc *pdf.Document
+		out []byte
+	)
+
+	BeforeEach(func() {
+		doc = pdf.New(pdf.A4Width, pdf.A4Height)
+		doc.Title = "Fish (and chips)"
+	})
+
+	JustBeforeEach(func() {
+		buf := new(bytes.Buffer)
+		Expect(doc.Write(buf)).To(Succeed())
+		out = buf.Bytes()
+	})
+
+	When("text and lines are drawn", func() {
+		BeforeEach(func() {
+			doc.Text(50, 72, pdf.Bold, 22, "Fish & chips")
+			doc.Line(50, 80, 545.28, 80, 0.5)
+			doc.Text(50, 100, pdf.Regular, 11, `• 500 g cod – £4½ \o/`)
+			doc.AddPage()
+			doc.Text(50, 72, pdf.Regular, 11, "Serve with peas ☺")
+		})
+
+		It("matches the golden file", func() {
+			golden := filepath.Join("testdata", "fish.pdf")
+			if *update {
+				Expect(ioutil.WriteFile(golden, out, 0644)).To(Succeed())
+			}
+			expected, err := ioutil.ReadFile(golden)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(out)).To(Equal(string(expected)))
+		})
+
+		It("has a page for each one added", func() {
+			Expect(doc.PageCount()).To(Equal(2))
+			Expect(string(out)).To(ContainSubstring("/Count 2"))
+		})
+
+		It("measures objects from the start of the file", func() {
+			xref := regexp.MustCompile(`startxref\n(\d+)`).FindSubmatch(out)
+			Expect(xref).NotTo(BeNil())
+			offset, err := strconv.Atoi(string(xref[1]))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(out[offset:])).To(HavePrefix("xref\n0 10\n"))
+
+			for _, entry := range regexp.MustCompile(`(\d{10}) 00000 n`).FindAllSubmatch(out, -1) {
+				offset, err := strconv.Atoi(string(entry[1]))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(out[offset:])).To(MatchRegexp(`^\d+ 0 obj\n`))
+			}
+		})
+
+		It("escapes text and writes it in Windows-1252", func() {
+			Expect(string(out)).To(ContainSubstring(`(\225 500 g cod \226 \2434\275 \\o/) Tj`))
+			Expect(string(out)).To(ContainSubstring(`(Serve with peas ?) Tj`))
+			Expect(string(out)).To(ContainSubstring(`/Title (Fish \(and chips\))`))
+		})
+	})
+
+	When("nothing is drawn", func() {
+		It("writes a blank page", func() {
+			Expect(doc.PageCount()).To(Equal(1))
+			Expect(string(out)).To(HavePrefix("%PDF-1.4\n"))
+			Expect(string(out)).To(HaveSuffix("%%EOF\n"))
+		})
+	})
+
+	Describe("Width", func() {
+		It("uses the font metrics", func() {
+			Expect(pdf.Width(pdf.Regular, 10, "Hi")).To(BeNumerically("~", 9.44, 0.001))
+			Expect(pdf.Width(pdf.Bold, 10, "Hi")).To(BeNumerically("~", 10, 0.001))
+			Expect(pdf.Width(pdf.Regular, 10, "½")).To(BeNumerically("~", 8.34, 0.001))
+		})
+	})
+})
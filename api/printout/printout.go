@@ -0,0 +1,345 @@
+/* Package printout lays recipes out as PDFs for printing */
+package printout
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kieron-pivotal/menu-planner-app/models"
+	"github.com/kieron-pivotal/menu-planner-app/pdf"
+	"github.com/kieron-pivotal/menu-planner-app/units"
+)
+
+// Layout is the page size, margins and type sizes of a printout, all in
+// points
+type Layout struct {
+	PageWidth   float64
+	PageHeight  float64
+	Margin      float64
+	TitleSize   float64
+	HeadingSize float64
+	BodySize    float64
+	// Leading is the distance between lines as a multiple of the type size
+	Leading float64
+}
+
+// A4 is the default layout
+var A4 = Layout{
+	PageWidth:   pdf.A4Width,
+	PageHeight:  pdf.A4Height,
+	Margin:      56,
+	TitleSize:   22,
+	HeadingSize: 14,
+	BodySize:    11,
+	Leading:     1.4,
+}
+
+// Recipe writes a recipe card, continuing onto further pages if the
+// method is long
+func Recipe(w io.Writer, layout Layout, recipe models.Recipe) error {
+	p := newPrinter(layout, recipe.Name)
+	p.recipe(recipe)
+	return p.doc.Write(w)
+}
+
+// Recipes writes a page listing recipes, e.g. those chosen for the meals
+// ahead, followed by each recipe starting on a new page
+func Recipes(w io.Writer, layout Layout, title string, recipes []models.Recipe) error {
+	p := newPrinter(layout, title)
+	p.text(pdf.Bold, layout.TitleSize, "", title)
+	p.rule()
+	for i, recipe := range recipes {
+		line := recipe.Name
+		if s := summary(recipe); s != "" {
+			line += " · " + s
+		}
+		p.text(pdf.Regular, layout.HeadingSize, fmt.Sprintf("%d.", i+1), line)
+	}
+
+	for _, recipe := range recipes {
+		p.newPage()
+		p.recipe(recipe)
+	}
+	return p.doc.Write(w)
+}
+
+// Plan writes a week's plan as a grid of each day's meals, for the fridge
+// door, marking the slots which eat leftovers. A card follows for each
+// recipe the plan cooks, starting on a new page, in the order they are
+// first cooked.
+func Plan(w io.Writer, layout Layout, title string, plan models.Plan, recipes []models.Recipe) error {
+	byID := map[int]models.Recipe{}
+	for _, recipe := range recipes {
+		byID[recipe.ID] = recipe
+	}
+
+	p := newPrinter(layout, title)
+	p.text(pdf.Bold, layout.TitleSize, "", title)
+	p.rule()
+	p.grid(plan.Slots, byID)
+
+	slots := append([]models.Slot{}, plan.Slots...)
+	sort.Slice(slots, func(i, j int) bool { return slots[i].Ref().Before(slots[j].Ref()) })
+
+	printed := map[int]bool{}
+	for _, slot := range slots {
+		recipe, ok := byID[slot.RecipeID]
+		if !ok || slot.LeftoverOf != nil || printed[recipe.ID] {
+			continue
+		}
+		printed[recipe.ID] = true
+
+		p.newPage()
+		p.recipe(recipe)
+	}
+	return p.doc.Write(w)
+}
+
+// cell is the text in one cell of the plan's grid
+type cell struct {
+	font  pdf.Font
+	lines []string
+}
+
+// grid draws a header of the meals, then a row for each day of the week
+// with the recipe planned for each meal
+func (p *printer) grid(slots []models.Slot, recipes map[int]models.Recipe) {
+	l := p.layout
+	dayWidth := 6 * l.BodySize
+	mealWidth := (l.PageWidth - 2*l.Margin - dayWidth) / float64(len(models.Meals))
+	pad := l.BodySize / 2
+
+	planned := map[models.SlotRef]models.Slot{}
+	for _, slot := range slots {
+		planned[slot.Ref()] = slot
+	}
+
+	header := []cell{{}}
+	for _, meal := range models.Meals {
+		header = append(header, cell{pdf.Bold, []string{mealName(meal)}})
+	}
+	p.gap(l.BodySize)
+	p.row(dayWidth, mealWidth, pad, header)
+
+	for day := 0; day < 7; day++ {
+		cells := []cell{{pdf.Bold, []string{dayName(day)}}}
+		for _, meal := range models.Meals {
+			c := cell{font: pdf.Regular}
+			if slot, ok := planned[models.SlotRef{Day: day, Meal: meal}]; ok {
+				c.lines = wrap(pdf.Regular, l.BodySize, mealWidth-2*pad, recipes[slot.RecipeID].Name)
+				if slot.LeftoverOf != nil {
+					leftovers := fmt.Sprintf("leftovers, %s %s", dayName(slot.LeftoverOf.Day)[:3], slot.LeftoverOf.Meal)
+					c.lines = append(c.lines, wrap(pdf.Regular, l.BodySize, mealWidth-2*pad, leftovers)...)
+				}
+			}
+			cells = append(cells, c)
+		}
+		p.row(dayWidth, mealWidth, pad, cells)
+	}
+}
+
+// row draws a row of the plan's grid as tall as its tallest cell, on a new
+// page if it doesn't fit, with a line under it and between its cells
+func (p *printer) row(dayWidth, mealWidth, pad float64, cells []cell) {
+	l := p.layout
+	lineHeight := l.BodySize * l.Leading
+
+	lines := 1
+	for _, c := range cells {
+		if len(c.lines) > lines {
+			lines = len(c.lines)
+		}
+	}
+	height := float64(lines)*lineHeight + pad
+	if p.y+height > l.PageHeight-l.Margin {
+		p.newPage()
+	}
+
+	top := p.y
+	x := l.Margin
+	for i, c := range cells {
+		if i > 0 {
+			p.doc.Line(x, top, x, top+height, 0.5)
+		}
+		for j, line := range c.lines {
+			p.doc.Text(x+pad, top+float64(j+1)*lineHeight, c.font, l.BodySize, line)
+		}
+		if i == 0 {
+			x += dayWidth
+		} else {
+			x += mealWidth
+		}
+	}
+
+	p.y = top + height
+	p.doc.Line(l.Margin, p.y, l.PageWidth-l.Margin, p.y, 0.5)
+}
+
+// dayName is the name of a day of a plan, 0 for Monday
+func dayName(day int) string {
+	return time.Weekday((day + 1) % 7).String()
+}
+
+// mealName is a meal's name starting with a capital
+func mealName(meal models.Meal) string {
+	return strings.ToUpper(string(meal[:1])) + string(meal[1:])
+}
+
+// printer draws lines of text down the page, starting a new page when
+// one fills up
+type printer struct {
+	doc    *pdf.Document
+	layout Layout
+	// y is the distance from the top of the page to the bottom of the
+	// last line drawn
+	y float64
+}
+
+func newPrinter(layout Layout, title string) *printer {
+	p := &printer{doc: pdf.New(layout.PageWidth, layout.PageHeight), layout: layout}
+	p.doc.Title = title
+	p.newPage()
+	return p
+}
+
+func (p *printer) newPage() {
+	p.doc.AddPage()
+	p.y = p.layout.Margin
+}
+
+func (p *printer) recipe(recipe models.Recipe) {
+	l := p.layout
+
+	p.text(pdf.Bold, l.TitleSize, "", recipe.Name)
+	if s := summary(recipe); s != "" {
+		p.text(pdf.Regular, l.BodySize, "", strings.ToUpper(s[:1])+s[1:])
+	}
+	p.rule()
+
+	if len(recipe.Ingredients) > 0 {
+		p.heading("Ingredients")
+		for _, ingredient := range recipe.Ingredients {
+			p.text(pdf.Regular, l.BodySize, "•", describe(ingredient))
+		}
+	}
+
+	if len(recipe.Steps) > 0 {
+		p.heading("Method")
+		for i, step := range recipe.Steps {
+			instruction := step.Instruction
+			if step.DurationSeconds > 0 {
+				instruction += " (" + duration(step.DurationSeconds) + ")"
+			}
+			p.text(pdf.Regular, l.BodySize, fmt.Sprintf("%d.", i+1), instruction)
+		}
+	}
+}
+
+func (p *printer) heading(s string) {
+	p.gap(p.layout.BodySize)
+	p.text(pdf.Bold, p.layout.HeadingSize, "", s)
+}
+
+// text draws s wrapped to the width of the page. A label, e.g. a bullet
+// or step number, hangs to the left of the first line.
+func (p *printer) text(font pdf.Font, size float64, label, s string) {
+	l := p.layout
+	x := l.Margin
+	if label != "" {
+		x += 2 * size
+	}
+	width := l.PageWidth - l.Margin - x
+
+	for i, line := range wrap(font, size, width, s) {
+		p.gap(size * l.Leading)
+		if i == 0 && label != "" {
+			p.doc.Text(l.Margin, p.y, font, size, label)
+		}
+		p.doc.Text(x, p.y, font, size, line)
+	}
+}
+
+// rule draws a line across the page under the last line of text
+func (p *printer) rule() {
+	p.gap(p.layout.BodySize / 2)
+	p.doc.Line(p.layout.Margin, p.y, p.layout.PageWidth-p.layout.Margin, p.y, 0.5)
+}
+
+// gap moves down the page, onto a new one if there's no room
+func (p *printer) gap(height float64) {
+	if p.y+height > p.layout.PageHeight-p.layout.Margin {
+		p.newPage()
+	}
+	p.y += height
+}
+
+// wrap splits text into lines which fit in width, breaking between words
+// where it can
+func wrap(font pdf.Font, size, width float64, s string) []string {
+	lines := []string{}
+	line := ""
+	for _, word := range strings.Fields(s) {
+		if line != "" && pdf.Width(font, size, line+" "+word) <= width {
+			line += " " + word
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+		for pdf.Width(font, size, word) > width {
+			cut := len([]rune(word)) - 1
+			for cut > 1 && pdf.Width(font, size, string([]rune(word)[:cut])) > width {
+				cut--
+			}
+			lines = append(lines, string([]rune(word)[:cut]))
+			word = string([]rune(word)[cut:])
+		}
+		line = word
+	}
+	if line != "" || len(lines) == 0 {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// summary is how many the recipe serves and how long it takes, or the
+// empty string if neither is known
+func summary(recipe models.Recipe) string {
+	parts := []string{}
+	if recipe.Servings > 0 {
+		parts = append(parts, fmt.Sprintf("serves %d", recipe.Servings))
+	}
+	seconds := 0
+	for _, step := range recipe.Steps {
+		seconds += step.DurationSeconds
+	}
+	if seconds > 0 {
+		parts = append(parts, duration(seconds))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func describe(ingredient models.Ingredient) string {
+	if ingredient.Quantity == 0 {
+		return ingredient.Name
+	}
+	return units.Format(ingredient.Quantity, ingredient.Unit) + " " + ingredient.Name
+}
+
+// duration is a number of seconds in hours and minutes, rounded up to
+// the minute
+func duration(seconds int) string {
+	d := (time.Duration(seconds)*time.Second + time.Minute - 1).Truncate(time.Minute)
+	h, m := int(d.Hours()), int(d.Minutes())%60
+	switch {
+	case h == 0:
+		return fmt.Sprintf("%d min", m)
+	case m == 0:
+		return fmt.Sprintf("%d h", h)
+	default:
+		return fmt.Sprintf("%d h %d min", h, m)
+	}
+}
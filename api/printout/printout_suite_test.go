@@ -0,0 +1,30 @@
+package printout_test
+
+import (
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+func TestPrintout(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Printout Suite")
+}
+
+// expectGolden compares out with testdata/name, first rewriting the file
+// if the update flag is set
+func expectGolden(name string, out []byte) {
+	golden := filepath.Join("testdata", name)
+	if *update {
+		Expect(ioutil.WriteFile(golden, out, 0644)).To(Succeed())
+	}
+	expected, err := ioutil.ReadFile(golden)
+	Expect(err).NotTo(HaveOccurred())
+	ExpectWithOffset(1, string(out)).To(Equal(string(expected)))
+}
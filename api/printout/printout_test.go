@@ -0,0 +1,147 @@
+package printout_test
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/kieron-pivotal/menu-planner-app/models"
+	"github.com/kieron-pivotal/menu-planner-app/printout"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Printout", func() {
+	var (
+		roast models.Recipe
+		salad models.Recipe
+		out   []byte
+	)
+
+	BeforeEach(func() {
+		roast = models.Recipe{
+			Name:     "Roast Beef",
+			Servings: 6,
+			Ingredients: []models.Ingredient{
+				{Name: "beef", Quantity: 1500, Unit: "g"},
+				{Name: "potatoes", Quantity: 1, Unit: "kg"},
+				{Name: "salt"},
+			},
+			Steps: []models.Step{
+				{Instruction: "Season the beef all over with plenty of salt and leave it to come up to room temperature while the oven heats"},
+				{Instruction: "Roast", DurationSeconds: 5400},
+				{Instruction: "Rest", DurationSeconds: 1230},
+			},
+		}
+		salad = models.Recipe{
+			Name:        "Salade niçoise",
+			Ingredients: []models.Ingredient{{Name: "eggs", Quantity: 2}},
+			Steps:       []models.Step{{Instruction: "Boil the eggs", DurationSeconds: 480}},
+		}
+	})
+
+	Describe("Recipe", func() {
+		JustBeforeEach(func() {
+			buf := new(bytes.Buffer)
+			Expect(printout.Recipe(buf, printout.A4, roast)).To(Succeed())
+			out = buf.Bytes()
+		})
+
+		It("matches the golden file", func() {
+			expectGolden("recipe.pdf", out)
+		})
+
+		It("lists the ingredients and method", func() {
+			Expect(string(out)).To(ContainSubstring("(Serves 6, 1 h 51 min) Tj"))
+			Expect(string(out)).To(ContainSubstring("(1.5 kg beef) Tj"))
+			Expect(string(out)).To(ContainSubstring("(salt) Tj"))
+			Expect(string(out)).To(ContainSubstring("(Roast \\(1 h 30 min\\)) Tj"))
+		})
+
+		It("wraps long steps", func() {
+			Expect(string(out)).To(ContainSubstring("(Season the beef all over"))
+			Expect(string(out)).To(ContainSubstring("heats) Tj"))
+			Expect(string(out)).NotTo(ContainSubstring("salt and leave it to come up to room temperature while the oven heats"))
+		})
+
+		When("the method is too long for a page", func() {
+			BeforeEach(func() {
+				for i := 0; i < 60; i++ {
+					roast.Steps = append(roast.Steps, models.Step{Instruction: "Baste"})
+				}
+			})
+
+			It("continues on another page", func() {
+				Expect(strings.Count(string(out), "/Type /Page ")).To(Equal(2))
+				Expect(string(out)).To(ContainSubstring("(63.) Tj"))
+			})
+		})
+	})
+
+	Describe("Recipes", func() {
+		JustBeforeEach(func() {
+			buf := new(bytes.Buffer)
+			Expect(printout.Recipes(buf, printout.A4, "This week", []models.Recipe{roast, salad})).To(Succeed())
+			out = buf.Bytes()
+		})
+
+		It("matches the golden file", func() {
+			expectGolden("recipes.pdf", out)
+		})
+
+		It("lists the meals and then gives each recipe a page", func() {
+			Expect(strings.Count(string(out), "/Type /Page ")).To(Equal(3))
+			Expect(string(out)).To(ContainSubstring("/Title (This week)"))
+			Expect(string(out)).To(ContainSubstring("(Roast Beef \\267 serves 6, 1 h 51 min) Tj"))
+			Expect(string(out)).To(ContainSubstring("(Salade ni\\347oise \\267 8 min) Tj"))
+		})
+	})
+
+	Describe("Plan", func() {
+		var plan models.Plan
+
+		BeforeEach(func() {
+			roast.ID, salad.ID = 3, 4
+			plan = models.Plan{Week: "2026-10-19", Slots: []models.Slot{
+				{Day: 4, Meal: models.Dinner, RecipeID: 3},
+				{Day: 1, Meal: models.Lunch, RecipeID: 3, LeftoverOf: &models.SlotRef{Day: 0, Meal: models.Dinner}},
+				{Day: 0, Meal: models.Dinner, RecipeID: 3, Portions: 2},
+				{Day: 2, Meal: models.Breakfast, RecipeID: 4},
+			}}
+		})
+
+		JustBeforeEach(func() {
+			buf := new(bytes.Buffer)
+			Expect(printout.Plan(buf, printout.A4, "Week of 19 October", plan, []models.Recipe{salad, roast})).To(Succeed())
+			out = buf.Bytes()
+		})
+
+		It("matches the golden file", func() {
+			expectGolden("plan.pdf", out)
+		})
+
+		It("lays out each day's meals and marks the leftovers", func() {
+			Expect(string(out)).To(ContainSubstring("/Title (Week of 19 October)"))
+			for _, heading := range []string{"Breakfast", "Lunch", "Dinner", "Monday", "Sunday"} {
+				Expect(string(out)).To(ContainSubstring("(" + heading + ") Tj"))
+			}
+			Expect(string(out)).To(ContainSubstring("(leftovers, Mon dinner) Tj"))
+		})
+
+		It("gives each recipe cooked a page, once, in the order of the week", func() {
+			Expect(strings.Count(string(out), "/Type /Page ")).To(Equal(3))
+			Expect(strings.Count(string(out), "(Roast \\(1 h 30 min\\)) Tj")).To(Equal(1))
+			Expect(strings.Index(string(out), "(Roast \\(1 h 30 min\\)) Tj")).To(BeNumerically("<", strings.Index(string(out), "(Boil the eggs")))
+		})
+
+		When("nothing is planned", func() {
+			BeforeEach(func() {
+				plan.Slots = nil
+			})
+
+			It("prints an empty grid", func() {
+				Expect(strings.Count(string(out), "/Type /Page ")).To(Equal(1))
+				Expect(string(out)).To(ContainSubstring("(Monday) Tj"))
+			})
+		})
+	})
+})
@@ -0,0 +1,99 @@
+/* Package problem writes errors as RFC 7807 problem details, so every endpoint fails in the same shape */
+package problem
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+)
+
+const ContentType = "application/problem+json"
+
+// Code tells clients what went wrong, so they can act on it without
+// parsing the detail, e.g. by sending the user to log in again
+type Code string
+
+const (
+	Unauthorized     Code = "unauthorized"
+	InvalidIDToken   Code = "invalid-id-token"
+	InvalidSession   Code = "invalid-session"
+	CrossSite        Code = "cross-site-request"
+	InvalidCSRFToken Code = "invalid-csrf-token"
+	OriginNotAllowed Code = "origin-not-allowed"
+	NotFound         Code = "not-found"
+	InvalidJSON      Code = "invalid-json"
+	InvalidParameter Code = "invalid-parameter"
+	InvalidRequest   Code = "invalid-request"
+	ValidationFailed Code = "validation-failed"
+	BodyTooLarge     Code = "body-too-large"
+	DuplicateRecipe  Code = "duplicate-recipe"
+	SimilarRecipes   Code = "similar-recipes"
+	DuplicateName    Code = "duplicate-name"
+	Internal         Code = "internal-error"
+)
+
+// Problem is the body of an error response. Type is a URI naming the
+// code, as RFC 7807 asks for, and Title the status text.
+type Problem struct {
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	Detail string `json:"detail,omitempty"`
+	Code   Code   `json:"code"`
+	// Errors says what is wrong with each invalid field of a request body
+	Errors []FieldError `json:"errors,omitempty"`
+	// Conflicts are the existing resources a request clashed with
+	Conflicts []Conflict `json:"conflicts,omitempty"`
+}
+
+// FieldError is a problem with one field. Field is its path in the JSON
+// body, e.g. "ingredients[2].name".
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Write writes a problem with the status. Detail explains this
+// occurrence to a person and may be empty.
+func Write(w http.ResponseWriter, status int, code Code, detail string) {
+	write(w, Problem{Status: status, Code: code, Detail: detail})
+}
+
+// WriteInvalid writes a 422 listing the invalid fields
+func WriteInvalid(w http.ResponseWriter, errs []FieldError) {
+	write(w, Problem{
+		Status: http.StatusUnprocessableEntity,
+		Code:   ValidationFailed,
+		Detail: "some fields are invalid",
+		Errors: errs,
+	})
+}
+
+// Conflict is an existing resource a request clashed with
+type Conflict struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// WriteConflict writes a 409 listing what the request clashed with
+func WriteConflict(w http.ResponseWriter, code Code, detail string, conflicts []Conflict) {
+	write(w, Problem{
+		Status:    http.StatusConflict,
+		Code:      code,
+		Detail:    detail,
+		Conflicts: conflicts,
+	})
+}
+
+func write(w http.ResponseWriter, p Problem) {
+	p.Type = "urn:menu-planner:problem:" + string(p.Code)
+	p.Title = http.StatusText(p.Status)
+
+	w.Header().Set("Content-Type", ContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(p.Status)
+
+	if err := json.NewEncoder(w).Encode(p); err != nil {
+		log.Printf("problem-write: %v\n", err)
+	}
+}
@@ -0,0 +1,73 @@
+package problem_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/kieron-pivotal/menu-planner-app/problem"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Problem", func() {
+	var recorder *httptest.ResponseRecorder
+
+	BeforeEach(func() {
+		recorder = httptest.NewRecorder()
+		recorder.Header().Set("Content-Type", "application/json")
+	})
+
+	It("writes problem details with the status and code", func() {
+		problem.Write(recorder, http.StatusNotFound, problem.NotFound, "no such recipe")
+
+		Expect(recorder.Code).To(Equal(http.StatusNotFound))
+		Expect(recorder.Header().Get("Content-Type")).To(Equal("application/problem+json"))
+		Expect(recorder.Body.String()).To(MatchJSON(`{
+			"type": "urn:menu-planner:problem:not-found",
+			"title": "Not Found",
+			"status": 404,
+			"detail": "no such recipe",
+			"code": "not-found"
+		}`))
+	})
+
+	It("leaves out an empty detail", func() {
+		problem.Write(recorder, http.StatusUnauthorized, problem.Unauthorized, "")
+
+		Expect(recorder.Body.String()).To(MatchJSON(`{
+			"type": "urn:menu-planner:problem:unauthorized",
+			"title": "Unauthorized",
+			"status": 401,
+			"code": "unauthorized"
+		}`))
+	})
+
+	It("lists invalid fields", func() {
+		problem.WriteInvalid(recorder, []problem.FieldError{{Field: "name", Message: "is required"}})
+
+		Expect(recorder.Code).To(Equal(http.StatusUnprocessableEntity))
+		Expect(recorder.Body.String()).To(MatchJSON(`{
+			"type": "urn:menu-planner:problem:validation-failed",
+			"title": "Unprocessable Entity",
+			"status": 422,
+			"detail": "some fields are invalid",
+			"code": "validation-failed",
+			"errors": [{"field": "name", "message": "is required"}]
+		}`))
+	})
+
+	It("lists conflicting resources", func() {
+		problem.WriteConflict(recorder, problem.SimilarRecipes, "you have similar recipes",
+			[]problem.Conflict{{ID: 3, Name: "Spaghetti Bolognese"}})
+
+		Expect(recorder.Code).To(Equal(http.StatusConflict))
+		Expect(recorder.Body.String()).To(MatchJSON(`{
+			"type": "urn:menu-planner:problem:similar-recipes",
+			"title": "Conflict",
+			"status": 409,
+			"detail": "you have similar recipes",
+			"code": "similar-recipes",
+			"conflicts": [{"id": 3, "name": "Spaghetti Bolognese"}]
+		}`))
+	})
+})
@@ -0,0 +1,130 @@
+package routing
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kieron-pivotal/menu-planner-app/problem"
+)
+
+// CORSOrigin is an origin allowed to make cross-origin requests. Origin is
+// either exact, e.g. https://menu.example.com, or a wildcard matching any
+// subdomain, e.g. https://*.example.com.
+type CORSOrigin struct {
+	Origin           string
+	AllowCredentials bool
+}
+
+// CORSPolicy decides which cross-origin requests browsers may make
+type CORSPolicy struct {
+	Origins        []CORSOrigin
+	AllowedHeaders []string
+	// AllowedMethods overrides the methods advertised on preflight requests.
+	// When empty, each route's own methods are advertised.
+	AllowedMethods []string
+	// ExposedHeaders are response headers scripts on allowed origins may read
+	ExposedHeaders []string
+	MaxAge         time.Duration
+}
+
+// NewCORSPolicy returns a policy allowing credentialed requests from the
+// given origins, with the headers the web app needs
+func NewCORSPolicy(origins ...string) CORSPolicy {
+	policy := CORSPolicy{
+		AllowedHeaders: []string{"Content-Type", csrfHeaderName},
+		ExposedHeaders: []string{"Deprecation", "Sunset", "Link"},
+	}
+	for _, o := range origins {
+		policy.Origins = append(policy.Origins, CORSOrigin{Origin: o, AllowCredentials: true})
+	}
+	return policy
+}
+
+// Middleware applies the policy. It answers every OPTIONS request itself,
+// refusing preflights from origins not in the policy.
+func (p CORSPolicy) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Add("Vary", "Origin")
+
+		origin := req.Header.Get("Origin")
+		allowed, found := p.match(origin)
+		if found {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			if allowed.AllowCredentials {
+				w.Header().Set("Access-Control-Allow-Credentials", "true")
+			}
+		}
+
+		if req.Method != http.MethodOptions {
+			if found && len(p.ExposedHeaders) > 0 {
+				w.Header().Set("Access-Control-Expose-Headers", strings.Join(p.ExposedHeaders, ", "))
+			}
+			next.ServeHTTP(w, req)
+			return
+		}
+
+		if origin != "" && !found {
+			w.Header().Del("Access-Control-Allow-Methods")
+			problem.Write(w, http.StatusForbidden, problem.OriginNotAllowed, "")
+			return
+		}
+
+		if origin != "" {
+			if len(p.AllowedMethods) > 0 {
+				w.Header().Set("Access-Control-Allow-Methods", strings.Join(p.AllowedMethods, ","))
+			}
+			if len(p.AllowedHeaders) > 0 {
+				w.Header().Set("Access-Control-Allow-Headers", strings.Join(p.AllowedHeaders, ", "))
+			}
+			if p.MaxAge > 0 {
+				w.Header().Set("Access-Control-Max-Age", strconv.Itoa(int(p.MaxAge.Seconds())))
+			}
+		}
+	})
+}
+
+// Allows reports whether the origin is in the policy
+func (p CORSPolicy) Allows(origin string) bool {
+	_, found := p.match(origin)
+	return found
+}
+
+func (p CORSPolicy) match(origin string) (CORSOrigin, bool) {
+	if origin == "" {
+		return CORSOrigin{}, false
+	}
+
+	for _, o := range p.Origins {
+		if originMatches(o.Origin, origin) {
+			return o, true
+		}
+	}
+	return CORSOrigin{}, false
+}
+
+func originMatches(pattern, origin string) bool {
+	pattern = strings.ToLower(strings.TrimSuffix(pattern, "/"))
+	origin = strings.ToLower(origin)
+
+	if !strings.Contains(pattern, "*") {
+		return pattern == origin
+	}
+
+	p, err := url.Parse(strings.Replace(pattern, "*", "wildcard", 1))
+	if err != nil || !strings.HasPrefix(p.Host, "wildcard.") {
+		return false
+	}
+
+	o, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+
+	suffix := strings.TrimPrefix(p.Host, "wildcard")
+	return o.Scheme == p.Scheme &&
+		strings.HasSuffix(o.Host, suffix) &&
+		len(o.Host) > len(suffix)
+}
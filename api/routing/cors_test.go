@@ -0,0 +1,145 @@
+package routing_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/kieron-pivotal/menu-planner-app/routing"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CORSPolicy", func() {
+	var (
+		policy   routing.CORSPolicy
+		called   bool
+		req      *http.Request
+		recorder *httptest.ResponseRecorder
+	)
+
+	BeforeEach(func() {
+		called = false
+		policy = routing.CORSPolicy{
+			Origins: []routing.CORSOrigin{
+				{Origin: "https://app.example.com", AllowCredentials: true},
+				{Origin: "https://*.partner.com"},
+			},
+			AllowedHeaders: []string{"Content-Type", "X-CSRF-Token"},
+			ExposedHeaders: []string{"Deprecation", "Sunset"},
+			MaxAge:         10 * time.Minute,
+		}
+		recorder = httptest.NewRecorder()
+
+		var err error
+		req, err = http.NewRequest(http.MethodGet, "/recipes", nil)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	JustBeforeEach(func() {
+		next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called = true
+		})
+		policy.Middleware(next).ServeHTTP(recorder, req)
+	})
+
+	It("always varies on origin", func() {
+		Expect(recorder.Header().Values("Vary")).To(ContainElement("Origin"))
+	})
+
+	When("the origin is allowed exactly", func() {
+		BeforeEach(func() {
+			req.Header.Set("Origin", "https://app.example.com")
+		})
+
+		It("allows the origin with credentials", func() {
+			Expect(called).To(BeTrue())
+			Expect(recorder.Header().Get("Access-Control-Allow-Origin")).To(Equal("https://app.example.com"))
+			Expect(recorder.Header().Get("Access-Control-Allow-Credentials")).To(Equal("true"))
+		})
+
+		It("exposes the policy's response headers", func() {
+			Expect(recorder.Header().Get("Access-Control-Expose-Headers")).To(Equal("Deprecation, Sunset"))
+		})
+	})
+
+	When("the origin matches a wildcard subdomain", func() {
+		BeforeEach(func() {
+			req.Header.Set("Origin", "https://shop.partner.com")
+		})
+
+		It("allows the origin without credentials", func() {
+			Expect(called).To(BeTrue())
+			Expect(recorder.Header().Get("Access-Control-Allow-Origin")).To(Equal("https://shop.partner.com"))
+			Expect(recorder.Header().Get("Access-Control-Allow-Credentials")).To(BeEmpty())
+		})
+	})
+
+	It("doesn't match other origins", func() {
+		for _, origin := range []string{
+			"https://evil.com",
+			"https://partner.com",
+			"https://shop.evilpartner.com",
+			"http://shop.partner.com",
+			"https://app.example.com:8443",
+		} {
+			Expect(policy.Allows(origin)).To(BeFalse(), origin)
+		}
+	})
+
+	When("the origin is not allowed", func() {
+		BeforeEach(func() {
+			req.Header.Set("Origin", "https://evil.com")
+		})
+
+		It("serves the request without CORS headers", func() {
+			Expect(called).To(BeTrue())
+			Expect(recorder.Header().Get("Access-Control-Allow-Origin")).To(BeEmpty())
+			Expect(recorder.Header().Get("Access-Control-Allow-Credentials")).To(BeEmpty())
+			Expect(recorder.Header().Get("Access-Control-Expose-Headers")).To(BeEmpty())
+		})
+	})
+
+	Context("preflight requests", func() {
+		BeforeEach(func() {
+			var err error
+			req, err = http.NewRequest(http.MethodOptions, "/recipes", nil)
+			Expect(err).NotTo(HaveOccurred())
+			req.Header.Set("Origin", "https://app.example.com")
+			req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+		})
+
+		It("answers them without calling the handler", func() {
+			Expect(called).To(BeFalse())
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+		})
+
+		It("advertises headers and max age", func() {
+			Expect(recorder.Header().Get("Access-Control-Allow-Headers")).To(Equal("Content-Type, X-CSRF-Token"))
+			Expect(recorder.Header().Get("Access-Control-Max-Age")).To(Equal("600"))
+		})
+
+		When("methods are configured", func() {
+			BeforeEach(func() {
+				policy.AllowedMethods = []string{"GET", "POST"}
+			})
+
+			It("advertises them", func() {
+				Expect(recorder.Header().Get("Access-Control-Allow-Methods")).To(Equal("GET,POST"))
+			})
+		})
+
+		When("the origin is not allowed", func() {
+			BeforeEach(func() {
+				req.Header.Set("Origin", "https://evil.com")
+			})
+
+			It("refuses them", func() {
+				Expect(called).To(BeFalse())
+				Expect(recorder.Code).To(Equal(http.StatusForbidden))
+				Expect(recorder.Header().Get("Access-Control-Allow-Origin")).To(BeEmpty())
+				Expect(recorder.Header().Get("Access-Control-Allow-Headers")).To(BeEmpty())
+			})
+		})
+	})
+})
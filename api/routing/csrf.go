@@ -0,0 +1,105 @@
+package routing
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/url"
+
+	"github.com/kieron-pivotal/menu-planner-app/problem"
+)
+
+const (
+	csrfCookieName = "_csrf"
+	csrfHeaderName = "X-CSRF-Token"
+)
+
+// CSRFMiddleware protects state-changing requests from cross-site request
+// forgery. Browsers must send a recognised Origin, and every request must
+// echo the CSRF cookie's token in the X-CSRF-Token header (double-submit).
+func (r Routes) CSRFMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if isSafeMethod(req.Method) {
+			next.ServeHTTP(w, req)
+			return
+		}
+
+		if !r.isTrustedOrigin(req) {
+			log.Printf("csrf: rejected request from origin %q, fetch site %q\n",
+				req.Header.Get("Origin"), req.Header.Get("Sec-Fetch-Site"))
+			problem.Write(w, http.StatusForbidden, problem.CrossSite, "")
+			return
+		}
+
+		cookie, err := req.Cookie(csrfCookieName)
+		if err != nil || cookie.Value == "" ||
+			subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(req.Header.Get(csrfHeaderName))) != 1 {
+			problem.Write(w, http.StatusForbidden, problem.InvalidCSRFToken, "")
+			return
+		}
+
+		next.ServeHTTP(w, req)
+	})
+}
+
+// CSRFToken hands out the token which the web app must send in the
+// X-CSRF-Token header, setting the matching cookie if there isn't one already
+func (r Routes) CSRFToken(w http.ResponseWriter, req *http.Request) {
+	token := ""
+	if cookie, err := req.Cookie(csrfCookieName); err == nil {
+		token = cookie.Value
+	}
+
+	if token == "" {
+		b := make([]byte, 32)
+		if _, err := rand.Read(b); err != nil {
+			log.Printf("csrf-token: %v\n", err)
+			problem.Write(w, http.StatusInternalServerError, problem.Internal, "")
+			return
+		}
+		token = base64.RawURLEncoding.EncodeToString(b)
+
+		http.SetCookie(w, &http.Cookie{
+			Name:     csrfCookieName,
+			Value:    token,
+			Path:     "/",
+			HttpOnly: true,
+		})
+	}
+
+	w.Header().Add("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(struct {
+		Token string `json:"token"`
+	}{token})
+}
+
+// isTrustedOrigin accepts requests from origins in the CORS policy or the
+// API's own origin. Clients which send neither Origin nor Sec-Fetch-Site (i.e. not
+// browsers) are left to the token check.
+func (r Routes) isTrustedOrigin(req *http.Request) bool {
+	origin := req.Header.Get("Origin")
+	if origin == "" {
+		return req.Header.Get("Sec-Fetch-Site") != "cross-site"
+	}
+
+	if r.corsPolicy.Allows(origin) {
+		return true
+	}
+
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	return u.Host == req.Host
+}
+
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
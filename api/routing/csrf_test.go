@@ -0,0 +1,177 @@
+package routing_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/kieron-pivotal/menu-planner-app/problem"
+	"github.com/kieron-pivotal/menu-planner-app/routing"
+	"github.com/kieron-pivotal/menu-planner-app/routing/routingfakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CSRF", func() {
+	var (
+		mockServer    *httptest.Server
+		recipeHandler *routingfakes.FakeRecipeHandler
+		frontendURI   = "https://foo.com"
+		req           *http.Request
+		resp          *http.Response
+	)
+
+	BeforeEach(func() {
+		recipeHandler = new(routingfakes.FakeRecipeHandler)
+		sessionManager := new(routingfakes.FakeSessionManager)
+		sessionManager.SessionMiddlewareStub = func(next http.Handler) http.Handler {
+			return next
+		}
+		router := routing.New(routing.NewCORSPolicy(frontendURI), sessionManager, new(routingfakes.FakeAuthHandler),
+			recipeHandler, new(routingfakes.FakeSessionHandler), new(routingfakes.FakePriceHandler),
+			new(routingfakes.FakeCookLogHandler), new(routingfakes.FakeCalendarHandler),
+			new(routingfakes.FakeShoppingHandler),
+			new(routingfakes.FakePrintHandler), new(routingfakes.FakePlanHandler), new(routingfakes.FakeEventsHandler),
+			new(routingfakes.FakeGraphQLHandler))
+		mockServer = httptest.NewServer(router.SetupRoutes())
+
+		var err error
+		req, err = http.NewRequest(http.MethodPost, mockServer.URL+"/api/v1/recipes", nil)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		mockServer.Close()
+	})
+
+	JustBeforeEach(func() {
+		var err error
+		resp, err = http.DefaultClient.Do(req)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	When("a state-changing request has a matching token", func() {
+		BeforeEach(func() {
+			withCSRF(req)
+			req.Header.Set("Origin", frontendURI)
+		})
+
+		It("is passed to the handler", func() {
+			Expect(recipeHandler.NewRecipeCallCount()).To(Equal(1))
+		})
+	})
+
+	When("the token header is missing", func() {
+		BeforeEach(func() {
+			req.AddCookie(&http.Cookie{Name: "_csrf", Value: "csrf-token"})
+		})
+
+		It("is rejected", func() {
+			Expect(resp.StatusCode).To(Equal(http.StatusForbidden))
+			Expect(recipeHandler.NewRecipeCallCount()).To(BeZero())
+
+			p := problem.Problem{}
+			Expect(json.NewDecoder(resp.Body).Decode(&p)).To(Succeed())
+			Expect(p.Code).To(Equal(problem.InvalidCSRFToken))
+		})
+	})
+
+	When("the token header doesn't match the cookie", func() {
+		BeforeEach(func() {
+			req.AddCookie(&http.Cookie{Name: "_csrf", Value: "csrf-token"})
+			req.Header.Set("X-CSRF-Token", "guessed-token")
+		})
+
+		It("is rejected", func() {
+			Expect(resp.StatusCode).To(Equal(http.StatusForbidden))
+			Expect(recipeHandler.NewRecipeCallCount()).To(BeZero())
+		})
+	})
+
+	When("the request comes from another origin", func() {
+		BeforeEach(func() {
+			withCSRF(req)
+			req.Header.Set("Origin", "https://evil.com")
+		})
+
+		It("is rejected", func() {
+			Expect(resp.StatusCode).To(Equal(http.StatusForbidden))
+			Expect(recipeHandler.NewRecipeCallCount()).To(BeZero())
+		})
+	})
+
+	When("the browser reports a cross-site request without an origin", func() {
+		BeforeEach(func() {
+			withCSRF(req)
+			req.Header.Set("Sec-Fetch-Site", "cross-site")
+		})
+
+		It("is rejected", func() {
+			Expect(resp.StatusCode).To(Equal(http.StatusForbidden))
+			Expect(recipeHandler.NewRecipeCallCount()).To(BeZero())
+		})
+	})
+
+	When("the request is same-origin", func() {
+		BeforeEach(func() {
+			withCSRF(req)
+			req.Header.Set("Origin", mockServer.URL)
+		})
+
+		It("is passed to the handler", func() {
+			Expect(recipeHandler.NewRecipeCallCount()).To(Equal(1))
+		})
+	})
+
+	When("the request is safe", func() {
+		BeforeEach(func() {
+			var err error
+			req, err = http.NewRequest(http.MethodGet, mockServer.URL+"/api/v1/recipes", nil)
+			Expect(err).NotTo(HaveOccurred())
+			req.Header.Set("Origin", "https://evil.com")
+		})
+
+		It("needs no token", func() {
+			Expect(recipeHandler.GetRecipesCallCount()).To(Equal(1))
+		})
+	})
+
+	Describe("the token endpoint", func() {
+		BeforeEach(func() {
+			var err error
+			req, err = http.NewRequest(http.MethodGet, mockServer.URL+"/api/v1/csrf", nil)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("returns a token matching a new cookie", func() {
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+			var body struct {
+				Token string `json:"token"`
+			}
+			Expect(json.NewDecoder(resp.Body).Decode(&body)).To(Succeed())
+			Expect(body.Token).NotTo(BeEmpty())
+
+			cookies := resp.Cookies()
+			Expect(cookies).To(HaveLen(1))
+			Expect(cookies[0].Name).To(Equal("_csrf"))
+			Expect(cookies[0].Value).To(Equal(body.Token))
+			Expect(cookies[0].HttpOnly).To(BeTrue())
+		})
+
+		When("there is already a token cookie", func() {
+			BeforeEach(func() {
+				req.AddCookie(&http.Cookie{Name: "_csrf", Value: "existing-token"})
+			})
+
+			It("returns the existing token", func() {
+				var body struct {
+					Token string `json:"token"`
+				}
+				Expect(json.NewDecoder(resp.Body).Decode(&body)).To(Succeed())
+				Expect(body.Token).To(Equal("existing-token"))
+				Expect(resp.Cookies()).To(BeEmpty())
+			})
+		})
+	})
+})
@@ -0,0 +1,27 @@
+package routing
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+var (
+	// legacyDeprecated is when the unversioned paths were deprecated in
+	// favour of /api/v1
+	legacyDeprecated = time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+	// legacySunset is when the unversioned paths may stop working
+	legacySunset = time.Date(2027, time.April, 30, 0, 0, 0, 0, time.UTC)
+)
+
+// deprecated marks responses from a deprecated path with Deprecation
+// (RFC 9745) and Sunset (RFC 8594) headers, and links to the same path under
+// the successor prefix
+func deprecated(successor string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Deprecation", fmt.Sprintf("@%d", legacyDeprecated.Unix()))
+		w.Header().Set("Sunset", legacySunset.Format(http.TimeFormat))
+		w.Header().Add("Link", fmt.Sprintf("<%s%s>; rel=\"successor-version\"", successor, r.URL.RequestURI()))
+		next.ServeHTTP(w, r)
+	})
+}
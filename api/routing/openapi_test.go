@@ -0,0 +1,112 @@
+package routing_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/kieron-pivotal/menu-planner-app/openapi"
+	"github.com/kieron-pivotal/menu-planner-app/routing"
+	"github.com/kieron-pivotal/menu-planner-app/routing/routingfakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("OpenAPI description", func() {
+	var (
+		router *mux.Router
+		doc    *openapi.Document
+	)
+
+	BeforeEach(func() {
+		sessionManager := new(routingfakes.FakeSessionManager)
+		sessionManager.SessionMiddlewareStub = func(next http.Handler) http.Handler { return next }
+		router = routing.New(routing.NewCORSPolicy("https://foo.com"), sessionManager,
+			new(routingfakes.FakeAuthHandler), new(routingfakes.FakeRecipeHandler),
+			new(routingfakes.FakeSessionHandler), new(routingfakes.FakePriceHandler),
+			new(routingfakes.FakeCookLogHandler), new(routingfakes.FakeCalendarHandler),
+			new(routingfakes.FakeShoppingHandler), new(routingfakes.FakePrintHandler),
+			new(routingfakes.FakePlanHandler), new(routingfakes.FakeEventsHandler),
+			new(routingfakes.FakeGraphQLHandler)).SetupRoutes()
+
+		var err error
+		doc, err = openapi.Load()
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	// routes lists the registered routes as "METHOD /path/{template}"
+	routes := func() map[string]bool {
+		res := map[string]bool{}
+		Expect(router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+			path, err := route.GetPathTemplate()
+			Expect(err).NotTo(HaveOccurred())
+			methods, err := route.GetMethods()
+			Expect(err).NotTo(HaveOccurred())
+
+			for _, method := range methods {
+				if method != http.MethodOptions {
+					res[method+" "+path] = true
+				}
+			}
+			return nil
+		})).To(Succeed())
+		return res
+	}
+
+	It("describes every versioned route", func() {
+		for route := range routes() {
+			methodPath := strings.SplitN(route, " ", 2)
+			path := strings.TrimPrefix(methodPath[1], doc.BasePath())
+			if path == methodPath[1] {
+				continue
+			}
+			Expect(doc.Operation(methodPath[0], path)).NotTo(BeNil(), "%s isn't in openapi.json", route)
+		}
+	})
+
+	It("serves every unversioned route under the base path too", func() {
+		registered := routes()
+		for route := range registered {
+			methodPath := strings.SplitN(route, " ", 2)
+			// GraphQL is described by its own schema
+			if methodPath[1] == "/graphql" {
+				continue
+			}
+			if !strings.HasPrefix(methodPath[1], doc.BasePath()) {
+				Expect(registered).To(HaveKey(methodPath[0]+" "+doc.BasePath()+methodPath[1]), "%s has no versioned route", route)
+			}
+		}
+	})
+
+	It("only describes routes which exist", func() {
+		registered := routes()
+		for path, item := range doc.Paths {
+			for method := range item {
+				route := strings.ToUpper(method) + " " + doc.BasePath() + path
+				Expect(registered).To(HaveKey(route), "openapi.json describes %s, which has no route", route)
+			}
+		}
+	})
+
+	It("serves the description", func() {
+		recorder := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/openapi.json", nil)
+		router.ServeHTTP(recorder, req)
+
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+		Expect(recorder.Header().Get("Content-Type")).To(Equal("application/json"))
+		Expect(recorder.Body.Bytes()).To(MatchJSON(openapi.JSON()))
+	})
+
+	It("serves the description at the root without deprecating it", func() {
+		recorder := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/openapi.json", nil)
+		router.ServeHTTP(recorder, req)
+
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+		Expect(recorder.Header().Get("Deprecation")).To(BeEmpty())
+		Expect(recorder.Header().Get("Sunset")).To(BeEmpty())
+		Expect(recorder.Body.Bytes()).To(MatchJSON(openapi.JSON()))
+	})
+})
@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/gorilla/mux"
+	"github.com/kieron-pivotal/menu-planner-app/openapi"
 )
 
 //go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -generate
@@ -20,7 +21,88 @@ type AuthHandler interface {
 
 type RecipeHandler interface {
 	GetRecipes(w http.ResponseWriter, r *http.Request)
+	GetRecipe(w http.ResponseWriter, r *http.Request)
 	NewRecipe(w http.ResponseWriter, r *http.Request)
+	RateRecipe(w http.ResponseWriter, r *http.Request)
+	MergeRecipes(w http.ResponseWriter, r *http.Request)
+}
+
+//counterfeiter:generate . SessionHandler
+
+type SessionHandler interface {
+	ListSessions(w http.ResponseWriter, r *http.Request)
+	RevokeSession(w http.ResponseWriter, r *http.Request)
+	RevokeAllSessions(w http.ResponseWriter, r *http.Request)
+}
+
+//counterfeiter:generate . PriceHandler
+
+type PriceHandler interface {
+	ListPrices(w http.ResponseWriter, r *http.Request)
+	SavePrice(w http.ResponseWriter, r *http.Request)
+	DeletePrice(w http.ResponseWriter, r *http.Request)
+	GetBudget(w http.ResponseWriter, r *http.Request)
+	SaveBudget(w http.ResponseWriter, r *http.Request)
+}
+
+//counterfeiter:generate . CookLogHandler
+
+type CookLogHandler interface {
+	RecordCooked(w http.ResponseWriter, r *http.Request)
+	CookHistory(w http.ResponseWriter, r *http.Request)
+	Suggestions(w http.ResponseWriter, r *http.Request)
+	ListLeftovers(w http.ResponseWriter, r *http.Request)
+	EatLeftovers(w http.ResponseWriter, r *http.Request)
+}
+
+//counterfeiter:generate . CalendarHandler
+
+type CalendarHandler interface {
+	IssueCalendarToken(w http.ResponseWriter, r *http.Request)
+	RevokeCalendarToken(w http.ResponseWriter, r *http.Request)
+	CalendarFeed(w http.ResponseWriter, r *http.Request)
+}
+
+//counterfeiter:generate . ShoppingHandler
+
+type ShoppingHandler interface {
+	ShoppingList(w http.ResponseWriter, r *http.Request)
+	PlanShoppingList(w http.ResponseWriter, r *http.Request)
+}
+
+//counterfeiter:generate . PrintHandler
+
+type PrintHandler interface {
+	RecipeCard(w http.ResponseWriter, r *http.Request)
+	PlanPrintout(w http.ResponseWriter, r *http.Request)
+	RecipesPrintout(w http.ResponseWriter, r *http.Request)
+}
+
+//counterfeiter:generate . PlanHandler
+
+type PlanHandler interface {
+	GetPlan(w http.ResponseWriter, r *http.Request)
+	SavePlan(w http.ResponseWriter, r *http.Request)
+	ApplyTemplate(w http.ResponseWriter, r *http.Request)
+	ApplyRules(w http.ResponseWriter, r *http.Request)
+	ListPlanTemplates(w http.ResponseWriter, r *http.Request)
+	NewPlanTemplate(w http.ResponseWriter, r *http.Request)
+	DeletePlanTemplate(w http.ResponseWriter, r *http.Request)
+	ListPlanRules(w http.ResponseWriter, r *http.Request)
+	SavePlanRule(w http.ResponseWriter, r *http.Request)
+	DeletePlanRule(w http.ResponseWriter, r *http.Request)
+}
+
+//counterfeiter:generate . EventsHandler
+
+type EventsHandler interface {
+	StreamEvents(w http.ResponseWriter, r *http.Request)
+}
+
+//counterfeiter:generate . GraphQLHandler
+
+type GraphQLHandler interface {
+	Query(w http.ResponseWriter, r *http.Request)
 }
 
 //counterfeiter:generate . SessionManager
@@ -30,45 +112,136 @@ type SessionManager interface {
 }
 
 type Routes struct {
-	frontendURI    string
-	sessionManager SessionManager
-	authHandler    AuthHandler
-	recipeHandler  RecipeHandler
+	corsPolicy      CORSPolicy
+	sessionManager  SessionManager
+	authHandler     AuthHandler
+	recipeHandler   RecipeHandler
+	sessionHandler  SessionHandler
+	priceHandler    PriceHandler
+	cookLogHandler  CookLogHandler
+	calendarHandler CalendarHandler
+	shoppingHandler ShoppingHandler
+	printHandler    PrintHandler
+	planHandler     PlanHandler
+	eventsHandler   EventsHandler
+	graphQLHandler  GraphQLHandler
 }
 
 func New(
-	frontendURI string, sessionManager SessionManager,
-	authHandler AuthHandler, recipeHandler RecipeHandler) Routes {
+	corsPolicy CORSPolicy, sessionManager SessionManager,
+	authHandler AuthHandler, recipeHandler RecipeHandler,
+	sessionHandler SessionHandler, priceHandler PriceHandler,
+	cookLogHandler CookLogHandler, calendarHandler CalendarHandler,
+	shoppingHandler ShoppingHandler, printHandler PrintHandler,
+	planHandler PlanHandler, eventsHandler EventsHandler, graphQLHandler GraphQLHandler) Routes {
 	return Routes{
-		frontendURI:    frontendURI,
-		sessionManager: sessionManager,
-		authHandler:    authHandler,
-		recipeHandler:  recipeHandler,
+		corsPolicy:      corsPolicy,
+		sessionManager:  sessionManager,
+		authHandler:     authHandler,
+		recipeHandler:   recipeHandler,
+		sessionHandler:  sessionHandler,
+		priceHandler:    priceHandler,
+		cookLogHandler:  cookLogHandler,
+		calendarHandler: calendarHandler,
+		shoppingHandler: shoppingHandler,
+		printHandler:    printHandler,
+		planHandler:     planHandler,
+		eventsHandler:   eventsHandler,
+		graphQLHandler:  graphQLHandler,
+	}
+}
+
+// handleFunc registers a handler for a method on a path relative to the
+// version's prefix
+type handleFunc func(path string, f http.HandlerFunc, method string)
+
+// apiVersion is a version of the API served under /api/<name>. Versions are
+// served side by side, so a model can change in a new version without
+// breaking clients of an old one.
+type apiVersion struct {
+	name   string
+	routes func(handle handleFunc)
+}
+
+func (r Routes) versions() []apiVersion {
+	return []apiVersion{
+		{name: "v1", routes: r.v1},
 	}
 }
 
 func (r Routes) SetupRoutes() *mux.Router {
 	m := mux.NewRouter()
 
-	m.HandleFunc("/authGoogle", r.authHandler.AuthGoogle).Methods("POST", "OPTIONS")
-	m.HandleFunc("/whoami", r.authHandler.WhoAmI).Methods("GET", "OPTIONS")
-	m.HandleFunc("/logout", r.authHandler.Logout).Methods("POST", "OPTIONS")
-	m.HandleFunc("/recipes", r.recipeHandler.GetRecipes).Methods("GET", "OPTIONS")
-	m.HandleFunc("/recipes", r.recipeHandler.NewRecipe).Methods("POST", "OPTIONS")
+	for _, v := range r.versions() {
+		prefix := "/api/" + v.name
+		v.routes(func(path string, f http.HandlerFunc, method string) {
+			m.HandleFunc(prefix+path, f).Methods(method, "OPTIONS")
+		})
+	}
+
+	// The description is found at the root, like GraphQL, so it is served
+	// there undeprecated. It is registered first to take the place of v1's
+	// deprecated alias.
+	m.HandleFunc("/openapi.json", openapi.ServeSpec).Methods("GET", "OPTIONS")
+
+	// v1 was first served at the root. Those paths still work until the
+	// sunset, but say where they have moved to.
+	r.v1(func(path string, f http.HandlerFunc, method string) {
+		m.Handle(path, deprecated("/api/v1", f)).Methods(method, "OPTIONS")
+	})
+
+	// GraphQL isn't versioned: its schema grows new fields instead
+	m.HandleFunc("/graphql", r.graphQLHandler.Query).Methods("POST", "OPTIONS")
+
 	m.Use(mux.CORSMethodMiddleware(m))
-	m.Use(r.CORSOriginMiddleware)
+	m.Use(r.corsPolicy.Middleware)
+	m.Use(r.CSRFMiddleware)
 	m.Use(r.sessionManager.SessionMiddleware)
 
 	return m
 }
 
-func (r Routes) CORSOriginMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
-		w.Header().Set("Access-Control-Allow-Origin", r.frontendURI)
-		w.Header().Set("Access-Control-Allow-Credentials", "true")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
-		if req.Method != http.MethodOptions {
-			next.ServeHTTP(w, req)
-		}
-	})
+func (r Routes) v1(handle handleFunc) {
+	handle("/openapi.json", openapi.ServeSpec, "GET")
+	handle("/csrf", r.CSRFToken, "GET")
+	handle("/authGoogle", r.authHandler.AuthGoogle, "POST")
+	handle("/whoami", r.authHandler.WhoAmI, "GET")
+	handle("/logout", r.authHandler.Logout, "POST")
+	handle("/recipes", r.recipeHandler.GetRecipes, "GET")
+	handle("/recipes", r.recipeHandler.NewRecipe, "POST")
+	handle("/recipes/suggestions", r.cookLogHandler.Suggestions, "GET")
+	handle("/recipes/{id}", r.recipeHandler.GetRecipe, "GET")
+	handle("/recipes/{id}/rating", r.recipeHandler.RateRecipe, "PUT")
+	handle("/recipes/{id}/merge", r.recipeHandler.MergeRecipes, "POST")
+	handle("/recipes/{id}/cooked", r.cookLogHandler.RecordCooked, "POST")
+	handle("/recipes/{id}/history", r.cookLogHandler.CookHistory, "GET")
+	handle("/recipes/{id}/card.pdf", r.printHandler.RecipeCard, "GET")
+	handle("/plan.pdf", r.printHandler.RecipesPrintout, "GET")
+	handle("/shopping-list", r.shoppingHandler.ShoppingList, "GET")
+	handle("/plans/{week}", r.planHandler.GetPlan, "GET")
+	handle("/plans/{week}", r.planHandler.SavePlan, "PUT")
+	handle("/plans/{week}/template", r.planHandler.ApplyTemplate, "POST")
+	handle("/plans/{week}/rules", r.planHandler.ApplyRules, "POST")
+	handle("/plans/{week}/shopping-list", r.shoppingHandler.PlanShoppingList, "GET")
+	handle("/plans/{week}/print.pdf", r.printHandler.PlanPrintout, "GET")
+	handle("/plan-templates", r.planHandler.ListPlanTemplates, "GET")
+	handle("/plan-templates", r.planHandler.NewPlanTemplate, "POST")
+	handle("/plan-templates/{id}", r.planHandler.DeletePlanTemplate, "DELETE")
+	handle("/plan-rules", r.planHandler.ListPlanRules, "GET")
+	handle("/plan-rules", r.planHandler.SavePlanRule, "PUT")
+	handle("/plan-rules/{id}", r.planHandler.DeletePlanRule, "DELETE")
+	handle("/leftovers", r.cookLogHandler.ListLeftovers, "GET")
+	handle("/leftovers/{id}/eaten", r.cookLogHandler.EatLeftovers, "POST")
+	handle("/events", r.eventsHandler.StreamEvents, "GET")
+	handle("/profile/calendar-token", r.calendarHandler.IssueCalendarToken, "POST")
+	handle("/profile/calendar-token", r.calendarHandler.RevokeCalendarToken, "DELETE")
+	handle("/calendar/{token}.ics", r.calendarHandler.CalendarFeed, "GET")
+	handle("/sessions", r.sessionHandler.ListSessions, "GET")
+	handle("/sessions", r.sessionHandler.RevokeAllSessions, "DELETE")
+	handle("/sessions/{id}", r.sessionHandler.RevokeSession, "DELETE")
+	handle("/prices", r.priceHandler.ListPrices, "GET")
+	handle("/prices", r.priceHandler.SavePrice, "PUT")
+	handle("/prices/{id}", r.priceHandler.DeletePrice, "DELETE")
+	handle("/profile/budget", r.priceHandler.GetBudget, "GET")
+	handle("/profile/budget", r.priceHandler.SaveBudget, "PUT")
 }
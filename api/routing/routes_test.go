@@ -5,25 +5,49 @@ import (
 	"net/http/httptest"
 	"strings"
 
+	"github.com/gorilla/mux"
 	"github.com/kieron-pivotal/menu-planner-app/routing"
 	"github.com/kieron-pivotal/menu-planner-app/routing/routingfakes"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 )
 
+func withCSRF(req *http.Request) {
+	req.AddCookie(&http.Cookie{Name: "_csrf", Value: "csrf-token"})
+	req.Header.Set("X-CSRF-Token", "csrf-token")
+}
+
 var _ = Describe("Routes", func() {
 	Context("routing", func() {
 		var (
-			mockServer     *httptest.Server
-			authHandler    *routingfakes.FakeAuthHandler
-			recipeHandler  *routingfakes.FakeRecipeHandler
-			frontendURI    = "https://foo.com"
-			sessionManager *routingfakes.FakeSessionManager
+			mockServer      *httptest.Server
+			authHandler     *routingfakes.FakeAuthHandler
+			recipeHandler   *routingfakes.FakeRecipeHandler
+			sessionHandler  *routingfakes.FakeSessionHandler
+			priceHandler    *routingfakes.FakePriceHandler
+			cookLogHandler  *routingfakes.FakeCookLogHandler
+			calendarHandler *routingfakes.FakeCalendarHandler
+			shoppingHandler *routingfakes.FakeShoppingHandler
+			printHandler    *routingfakes.FakePrintHandler
+			planHandler     *routingfakes.FakePlanHandler
+			eventsHandler   *routingfakes.FakeEventsHandler
+			graphQLHandler  *routingfakes.FakeGraphQLHandler
+			frontendURI     = "https://foo.com"
+			sessionManager  *routingfakes.FakeSessionManager
 		)
 
 		BeforeEach(func() {
 			authHandler = new(routingfakes.FakeAuthHandler)
 			recipeHandler = new(routingfakes.FakeRecipeHandler)
+			sessionHandler = new(routingfakes.FakeSessionHandler)
+			priceHandler = new(routingfakes.FakePriceHandler)
+			cookLogHandler = new(routingfakes.FakeCookLogHandler)
+			calendarHandler = new(routingfakes.FakeCalendarHandler)
+			shoppingHandler = new(routingfakes.FakeShoppingHandler)
+			printHandler = new(routingfakes.FakePrintHandler)
+			planHandler = new(routingfakes.FakePlanHandler)
+			eventsHandler = new(routingfakes.FakeEventsHandler)
+			graphQLHandler = new(routingfakes.FakeGraphQLHandler)
 			sessionManager = new(routingfakes.FakeSessionManager)
 			// noop middleware
 			sessionManager.SessionMiddlewareStub = func(next http.Handler) http.Handler {
@@ -31,7 +55,7 @@ var _ = Describe("Routes", func() {
 					next.ServeHTTP(w, r)
 				})
 			}
-			router := routing.New(frontendURI, sessionManager, authHandler, recipeHandler)
+			router := routing.New(routing.NewCORSPolicy(frontendURI), sessionManager, authHandler, recipeHandler, sessionHandler, priceHandler, cookLogHandler, calendarHandler, shoppingHandler, printHandler, planHandler, eventsHandler, graphQLHandler)
 			mockServer = httptest.NewServer(router.SetupRoutes())
 		})
 
@@ -42,8 +66,9 @@ var _ = Describe("Routes", func() {
 			)
 
 			JustBeforeEach(func() {
-				req, err = http.NewRequest(http.MethodPost, mockServer.URL+"/authGoogle", nil)
+				req, err = http.NewRequest(http.MethodPost, mockServer.URL+"/api/v1/authGoogle", nil)
 				Expect(err).NotTo(HaveOccurred())
+				withCSRF(req)
 				_, err = http.DefaultClient.Do(req)
 				Expect(err).NotTo(HaveOccurred())
 			})
@@ -55,8 +80,10 @@ var _ = Describe("Routes", func() {
 
 		Context("authGoogle", func() {
 			It("gets CORS right", func() {
-				req, err := http.NewRequest(http.MethodOptions, mockServer.URL+"/authGoogle", nil)
+				req, err := http.NewRequest(http.MethodOptions, mockServer.URL+"/api/v1/authGoogle", nil)
 				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Origin", frontendURI)
+				req.Header.Set("Access-Control-Request-Method", http.MethodPost)
 
 				resp, err := http.DefaultClient.Do(req)
 				Expect(err).NotTo(HaveOccurred())
@@ -70,11 +97,14 @@ var _ = Describe("Routes", func() {
 				Expect(allowedMethods).To(Equal("POST,OPTIONS"))
 
 				allowedHeaders := resp.Header.Get("Access-Control-Allow-Headers")
-				Expect(allowedHeaders).To(Equal("Content-Type"))
+				Expect(allowedHeaders).To(Equal("Content-Type, X-CSRF-Token"))
 			})
 
 			It("calls authGoogle handler on POST", func() {
-				_, err := http.Post(mockServer.URL+"/authGoogle", "application/json", nil)
+				req, err := http.NewRequest(http.MethodPost, mockServer.URL+"/api/v1/authGoogle", nil)
+				Expect(err).NotTo(HaveOccurred())
+				withCSRF(req)
+				_, err = http.DefaultClient.Do(req)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(authHandler.AuthGoogleCallCount()).To(Equal(1))
 			})
@@ -82,17 +112,312 @@ var _ = Describe("Routes", func() {
 
 		Context("recipes", func() {
 			It("calls getRecipes handler on GET /recipes", func() {
-				_, err := http.Get(mockServer.URL + "/recipes")
+				_, err := http.Get(mockServer.URL + "/api/v1/recipes")
 				Expect(err).NotTo(HaveOccurred())
 				Expect(recipeHandler.GetRecipesCallCount()).To(Equal(1))
 			})
 
+			It("calls getRecipe handler on GET /recipes/{id}", func() {
+				_, err := http.Get(mockServer.URL + "/api/v1/recipes/12")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(recipeHandler.GetRecipeCallCount()).To(Equal(1))
+			})
+
 			It("calls newRecipe handler on POST /recipes", func() {
 				body := strings.NewReader("")
-				_, err := http.Post(mockServer.URL+"/recipes", "application/json", body)
+				req, err := http.NewRequest(http.MethodPost, mockServer.URL+"/api/v1/recipes", body)
+				Expect(err).NotTo(HaveOccurred())
+				withCSRF(req)
+				_, err = http.DefaultClient.Do(req)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(recipeHandler.NewRecipeCallCount()).To(Equal(1))
 			})
+
+			It("calls rateRecipe handler on PUT /recipes/{id}/rating", func() {
+				req, err := http.NewRequest(http.MethodPut, mockServer.URL+"/api/v1/recipes/12/rating", strings.NewReader(""))
+				Expect(err).NotTo(HaveOccurred())
+				withCSRF(req)
+				_, err = http.DefaultClient.Do(req)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(recipeHandler.RateRecipeCallCount()).To(Equal(1))
+			})
+
+			It("calls mergeRecipes handler on POST /recipes/{id}/merge", func() {
+				req, err := http.NewRequest(http.MethodPost, mockServer.URL+"/api/v1/recipes/12/merge", strings.NewReader(""))
+				Expect(err).NotTo(HaveOccurred())
+				withCSRF(req)
+				_, err = http.DefaultClient.Do(req)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(recipeHandler.MergeRecipesCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("cook log", func() {
+			It("calls recordCooked handler on POST /recipes/{id}/cooked", func() {
+				req, err := http.NewRequest(http.MethodPost, mockServer.URL+"/api/v1/recipes/12/cooked", strings.NewReader(""))
+				Expect(err).NotTo(HaveOccurred())
+				withCSRF(req)
+				_, err = http.DefaultClient.Do(req)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cookLogHandler.RecordCookedCallCount()).To(Equal(1))
+			})
+
+			It("calls cookHistory handler on GET /recipes/{id}/history", func() {
+				_, err := http.Get(mockServer.URL + "/api/v1/recipes/12/history")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cookLogHandler.CookHistoryCallCount()).To(Equal(1))
+			})
+
+			It("calls suggestions handler on GET /recipes/suggestions", func() {
+				_, err := http.Get(mockServer.URL + "/api/v1/recipes/suggestions")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cookLogHandler.SuggestionsCallCount()).To(Equal(1))
+				Expect(recipeHandler.GetRecipeCallCount()).To(BeZero())
+			})
+
+			It("calls listLeftovers handler on GET /leftovers", func() {
+				_, err := http.Get(mockServer.URL + "/api/v1/leftovers")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cookLogHandler.ListLeftoversCallCount()).To(Equal(1))
+			})
+
+			It("calls eatLeftovers handler on POST /leftovers/{id}/eaten", func() {
+				req, err := http.NewRequest(http.MethodPost, mockServer.URL+"/api/v1/leftovers/4/eaten", strings.NewReader(""))
+				Expect(err).NotTo(HaveOccurred())
+				withCSRF(req)
+				_, err = http.DefaultClient.Do(req)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cookLogHandler.EatLeftoversCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("plans", func() {
+			send := func(method, path string) {
+				req, err := http.NewRequest(method, mockServer.URL+"/api/v1"+path, strings.NewReader(""))
+				Expect(err).NotTo(HaveOccurred())
+				withCSRF(req)
+				_, err = http.DefaultClient.Do(req)
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			It("calls getPlan and savePlan handlers on GET and PUT /plans/{week}", func() {
+				send(http.MethodGet, "/plans/2026-10-19")
+				send(http.MethodPut, "/plans/2026-10-19")
+				Expect(planHandler.GetPlanCallCount()).To(Equal(1))
+				Expect(planHandler.SavePlanCallCount()).To(Equal(1))
+			})
+
+			It("calls applyTemplate handler on POST /plans/{week}/template", func() {
+				send(http.MethodPost, "/plans/2026-10-19/template")
+				Expect(planHandler.ApplyTemplateCallCount()).To(Equal(1))
+			})
+
+			It("calls applyRules handler on POST /plans/{week}/rules", func() {
+				send(http.MethodPost, "/plans/2026-10-19/rules")
+				Expect(planHandler.ApplyRulesCallCount()).To(Equal(1))
+			})
+
+			It("calls the plan template handlers on /plan-templates", func() {
+				send(http.MethodGet, "/plan-templates")
+				send(http.MethodPost, "/plan-templates")
+				send(http.MethodDelete, "/plan-templates/3")
+				Expect(planHandler.ListPlanTemplatesCallCount()).To(Equal(1))
+				Expect(planHandler.NewPlanTemplateCallCount()).To(Equal(1))
+				Expect(planHandler.DeletePlanTemplateCallCount()).To(Equal(1))
+			})
+
+			It("calls the plan rule handlers on /plan-rules", func() {
+				send(http.MethodGet, "/plan-rules")
+				send(http.MethodPut, "/plan-rules")
+				send(http.MethodDelete, "/plan-rules/3")
+				Expect(planHandler.ListPlanRulesCallCount()).To(Equal(1))
+				Expect(planHandler.SavePlanRuleCallCount()).To(Equal(1))
+				Expect(planHandler.DeletePlanRuleCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("prices", func() {
+			It("calls listPrices handler on GET /prices", func() {
+				_, err := http.Get(mockServer.URL + "/api/v1/prices")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(priceHandler.ListPricesCallCount()).To(Equal(1))
+			})
+
+			It("calls savePrice handler on PUT /prices", func() {
+				req, err := http.NewRequest(http.MethodPut, mockServer.URL+"/api/v1/prices", strings.NewReader(""))
+				Expect(err).NotTo(HaveOccurred())
+				withCSRF(req)
+				_, err = http.DefaultClient.Do(req)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(priceHandler.SavePriceCallCount()).To(Equal(1))
+			})
+
+			It("calls deletePrice handler on DELETE /prices/{id}", func() {
+				req, err := http.NewRequest(http.MethodDelete, mockServer.URL+"/api/v1/prices/3", nil)
+				Expect(err).NotTo(HaveOccurred())
+				withCSRF(req)
+				_, err = http.DefaultClient.Do(req)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(priceHandler.DeletePriceCallCount()).To(Equal(1))
+			})
+
+			It("calls getBudget handler on GET /profile/budget", func() {
+				_, err := http.Get(mockServer.URL + "/api/v1/profile/budget")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(priceHandler.GetBudgetCallCount()).To(Equal(1))
+			})
+
+			It("calls saveBudget handler on PUT /profile/budget", func() {
+				req, err := http.NewRequest(http.MethodPut, mockServer.URL+"/api/v1/profile/budget", strings.NewReader(""))
+				Expect(err).NotTo(HaveOccurred())
+				withCSRF(req)
+				_, err = http.DefaultClient.Do(req)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(priceHandler.SaveBudgetCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("printouts", func() {
+			It("calls recipeCard handler on GET /recipes/{id}/card.pdf", func() {
+				_, err := http.Get(mockServer.URL + "/api/v1/recipes/3/card.pdf")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(printHandler.RecipeCardCallCount()).To(Equal(1))
+				Expect(recipeHandler.GetRecipeCallCount()).To(Equal(0))
+			})
+
+			It("calls planPrintout handler on GET /plans/{week}/print.pdf", func() {
+				_, err := http.Get(mockServer.URL + "/api/v1/plans/2026-10-19/print.pdf")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(printHandler.PlanPrintoutCallCount()).To(Equal(1))
+			})
+
+			It("calls recipesPrintout handler on GET /plan.pdf", func() {
+				_, err := http.Get(mockServer.URL + "/api/v1/plan.pdf?recipes=1,2")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(printHandler.RecipesPrintoutCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("shopping list", func() {
+			It("calls shoppingList handler on GET /shopping-list", func() {
+				_, err := http.Get(mockServer.URL + "/api/v1/shopping-list?recipes=1,2")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(shoppingHandler.ShoppingListCallCount()).To(Equal(1))
+			})
+
+			It("calls planShoppingList handler on GET /plans/{week}/shopping-list", func() {
+				_, err := http.Get(mockServer.URL + "/api/v1/plans/2026-10-19/shopping-list")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(shoppingHandler.PlanShoppingListCallCount()).To(Equal(1))
+				Expect(planHandler.GetPlanCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("calendar", func() {
+			It("calls issueCalendarToken handler on POST /profile/calendar-token", func() {
+				req, err := http.NewRequest(http.MethodPost, mockServer.URL+"/api/v1/profile/calendar-token", nil)
+				Expect(err).NotTo(HaveOccurred())
+				withCSRF(req)
+				_, err = http.DefaultClient.Do(req)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(calendarHandler.IssueCalendarTokenCallCount()).To(Equal(1))
+			})
+
+			It("calls revokeCalendarToken handler on DELETE /profile/calendar-token", func() {
+				req, err := http.NewRequest(http.MethodDelete, mockServer.URL+"/api/v1/profile/calendar-token", nil)
+				Expect(err).NotTo(HaveOccurred())
+				withCSRF(req)
+				_, err = http.DefaultClient.Do(req)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(calendarHandler.RevokeCalendarTokenCallCount()).To(Equal(1))
+			})
+
+			It("calls calendarFeed handler on GET /calendar/{token}.ics", func() {
+				_, err := http.Get(mockServer.URL + "/api/v1/calendar/abc123.ics")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(calendarHandler.CalendarFeedCallCount()).To(Equal(1))
+				_, req := calendarHandler.CalendarFeedArgsForCall(0)
+				Expect(mux.Vars(req)["token"]).To(Equal("abc123"))
+			})
+		})
+
+		Context("sessions", func() {
+			It("calls listSessions handler on GET /sessions", func() {
+				_, err := http.Get(mockServer.URL + "/api/v1/sessions")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(sessionHandler.ListSessionsCallCount()).To(Equal(1))
+			})
+
+			It("calls revokeAllSessions handler on DELETE /sessions", func() {
+				req, err := http.NewRequest(http.MethodDelete, mockServer.URL+"/api/v1/sessions", nil)
+				Expect(err).NotTo(HaveOccurred())
+				withCSRF(req)
+				_, err = http.DefaultClient.Do(req)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(sessionHandler.RevokeAllSessionsCallCount()).To(Equal(1))
+			})
+
+			It("calls revokeSession handler on DELETE /sessions/{id}", func() {
+				req, err := http.NewRequest(http.MethodDelete, mockServer.URL+"/api/v1/sessions/abc", nil)
+				Expect(err).NotTo(HaveOccurred())
+				withCSRF(req)
+				_, err = http.DefaultClient.Do(req)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(sessionHandler.RevokeSessionCallCount()).To(Equal(1))
+				_, r := sessionHandler.RevokeSessionArgsForCall(0)
+				Expect(mux.Vars(r)).To(HaveKeyWithValue("id", "abc"))
+			})
+		})
+
+		Context("events", func() {
+			It("calls the events handler on GET /events", func() {
+				_, err := http.Get(mockServer.URL + "/api/v1/events")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(eventsHandler.StreamEventsCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("graphql", func() {
+			It("calls the graphQL handler on POST /graphql, without deprecating it", func() {
+				req, err := http.NewRequest(http.MethodPost, mockServer.URL+"/graphql", nil)
+				Expect(err).NotTo(HaveOccurred())
+				withCSRF(req)
+				resp, err := http.DefaultClient.Do(req)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(graphQLHandler.QueryCallCount()).To(Equal(1))
+				Expect(resp.Header.Get("Deprecation")).To(BeEmpty())
+			})
+		})
+
+		Context("unversioned paths", func() {
+			It("still serve v1, deprecated", func() {
+				resp, err := http.Get(mockServer.URL + "/recipes/12?x=1")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(recipeHandler.GetRecipeCallCount()).To(Equal(1))
+				_, r := recipeHandler.GetRecipeArgsForCall(0)
+				Expect(mux.Vars(r)).To(HaveKeyWithValue("id", "12"))
+
+				Expect(resp.Header.Get("Deprecation")).To(Equal("@1792368000"))
+				Expect(resp.Header.Get("Sunset")).To(Equal("Fri, 30 Apr 2027 00:00:00 GMT"))
+				Expect(resp.Header.Get("Link")).To(Equal(`</api/v1/recipes/12?x=1>; rel="successor-version"`))
+			})
+
+			It("gets CORS right", func() {
+				req, err := http.NewRequest(http.MethodOptions, mockServer.URL+"/authGoogle", nil)
+				Expect(err).NotTo(HaveOccurred())
+				req.Header.Set("Origin", frontendURI)
+				req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+				resp, err := http.DefaultClient.Do(req)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp.Header.Get("Access-Control-Allow-Methods")).To(Equal("POST,OPTIONS"))
+			})
+
+			It("aren't deprecated under /api/v1", func() {
+				resp, err := http.Get(mockServer.URL + "/api/v1/recipes/12")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(resp.Header.Get("Deprecation")).To(BeEmpty())
+				Expect(resp.Header.Get("Sunset")).To(BeEmpty())
+			})
 		})
 	})
 })
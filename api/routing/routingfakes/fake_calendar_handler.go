@@ -0,0 +1,158 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package routingfakes
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/kieron-pivotal/menu-planner-app/routing"
+)
+
+type FakeCalendarHandler struct {
+	CalendarFeedStub        func(http.ResponseWriter, *http.Request)
+	calendarFeedMutex       sync.RWMutex
+	calendarFeedArgsForCall []struct {
+		arg1 http.ResponseWriter
+		arg2 *http.Request
+	}
+	IssueCalendarTokenStub        func(http.ResponseWriter, *http.Request)
+	issueCalendarTokenMutex       sync.RWMutex
+	issueCalendarTokenArgsForCall []struct {
+		arg1 http.ResponseWriter
+		arg2 *http.Request
+	}
+	RevokeCalendarTokenStub        func(http.ResponseWriter, *http.Request)
+	revokeCalendarTokenMutex       sync.RWMutex
+	revokeCalendarTokenArgsForCall []struct {
+		arg1 http.ResponseWriter
+		arg2 *http.Request
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeCalendarHandler) CalendarFeed(arg1 http.ResponseWriter, arg2 *http.Request) {
+	fake.calendarFeedMutex.Lock()
+	fake.calendarFeedArgsForCall = append(fake.calendarFeedArgsForCall, struct {
+		arg1 http.ResponseWriter
+		arg2 *http.Request
+	}{arg1, arg2})
+	fake.recordInvocation("CalendarFeed", []interface{}{arg1, arg2})
+	fake.calendarFeedMutex.Unlock()
+	if fake.CalendarFeedStub != nil {
+		fake.CalendarFeedStub(arg1, arg2)
+	}
+}
+
+func (fake *FakeCalendarHandler) CalendarFeedCallCount() int {
+	fake.calendarFeedMutex.RLock()
+	defer fake.calendarFeedMutex.RUnlock()
+	return len(fake.calendarFeedArgsForCall)
+}
+
+func (fake *FakeCalendarHandler) CalendarFeedCalls(stub func(http.ResponseWriter, *http.Request)) {
+	fake.calendarFeedMutex.Lock()
+	defer fake.calendarFeedMutex.Unlock()
+	fake.CalendarFeedStub = stub
+}
+
+func (fake *FakeCalendarHandler) CalendarFeedArgsForCall(i int) (http.ResponseWriter, *http.Request) {
+	fake.calendarFeedMutex.RLock()
+	defer fake.calendarFeedMutex.RUnlock()
+	argsForCall := fake.calendarFeedArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeCalendarHandler) IssueCalendarToken(arg1 http.ResponseWriter, arg2 *http.Request) {
+	fake.issueCalendarTokenMutex.Lock()
+	fake.issueCalendarTokenArgsForCall = append(fake.issueCalendarTokenArgsForCall, struct {
+		arg1 http.ResponseWriter
+		arg2 *http.Request
+	}{arg1, arg2})
+	fake.recordInvocation("IssueCalendarToken", []interface{}{arg1, arg2})
+	fake.issueCalendarTokenMutex.Unlock()
+	if fake.IssueCalendarTokenStub != nil {
+		fake.IssueCalendarTokenStub(arg1, arg2)
+	}
+}
+
+func (fake *FakeCalendarHandler) IssueCalendarTokenCallCount() int {
+	fake.issueCalendarTokenMutex.RLock()
+	defer fake.issueCalendarTokenMutex.RUnlock()
+	return len(fake.issueCalendarTokenArgsForCall)
+}
+
+func (fake *FakeCalendarHandler) IssueCalendarTokenCalls(stub func(http.ResponseWriter, *http.Request)) {
+	fake.issueCalendarTokenMutex.Lock()
+	defer fake.issueCalendarTokenMutex.Unlock()
+	fake.IssueCalendarTokenStub = stub
+}
+
+func (fake *FakeCalendarHandler) IssueCalendarTokenArgsForCall(i int) (http.ResponseWriter, *http.Request) {
+	fake.issueCalendarTokenMutex.RLock()
+	defer fake.issueCalendarTokenMutex.RUnlock()
+	argsForCall := fake.issueCalendarTokenArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeCalendarHandler) RevokeCalendarToken(arg1 http.ResponseWriter, arg2 *http.Request) {
+	fake.revokeCalendarTokenMutex.Lock()
+	fake.revokeCalendarTokenArgsForCall = append(fake.revokeCalendarTokenArgsForCall, struct {
+		arg1 http.ResponseWriter
+		arg2 *http.Request
+	}{arg1, arg2})
+	fake.recordInvocation("RevokeCalendarToken", []interface{}{arg1, arg2})
+	fake.revokeCalendarTokenMutex.Unlock()
+	if fake.RevokeCalendarTokenStub != nil {
+		fake.RevokeCalendarTokenStub(arg1, arg2)
+	}
+}
+
+func (fake *FakeCalendarHandler) RevokeCalendarTokenCallCount() int {
+	fake.revokeCalendarTokenMutex.RLock()
+	defer fake.revokeCalendarTokenMutex.RUnlock()
+	return len(fake.revokeCalendarTokenArgsForCall)
+}
+
+func (fake *FakeCalendarHandler) RevokeCalendarTokenCalls(stub func(http.ResponseWriter, *http.Request)) {
+	fake.revokeCalendarTokenMutex.Lock()
+	defer fake.revokeCalendarTokenMutex.Unlock()
+	fake.RevokeCalendarTokenStub = stub
+}
+
+func (fake *FakeCalendarHandler) RevokeCalendarTokenArgsForCall(i int) (http.ResponseWriter, *http.Request) {
+	fake.revokeCalendarTokenMutex.RLock()
+	defer fake.revokeCalendarTokenMutex.RUnlock()
+	argsForCall := fake.revokeCalendarTokenArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeCalendarHandler) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.calendarFeedMutex.RLock()
+	defer fake.calendarFeedMutex.RUnlock()
+	fake.issueCalendarTokenMutex.RLock()
+	defer fake.issueCalendarTokenMutex.RUnlock()
+	fake.revokeCalendarTokenMutex.RLock()
+	defer fake.revokeCalendarTokenMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeCalendarHandler) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ routing.CalendarHandler = new(FakeCalendarHandler)
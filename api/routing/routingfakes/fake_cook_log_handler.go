@@ -0,0 +1,238 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package routingfakes
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/kieron-pivotal/menu-planner-app/routing"
+)
+
+type FakeCookLogHandler struct {
+	CookHistoryStub        func(http.ResponseWriter, *http.Request)
+	cookHistoryMutex       sync.RWMutex
+	cookHistoryArgsForCall []struct {
+		arg1 http.ResponseWriter
+		arg2 *http.Request
+	}
+	EatLeftoversStub        func(http.ResponseWriter, *http.Request)
+	eatLeftoversMutex       sync.RWMutex
+	eatLeftoversArgsForCall []struct {
+		arg1 http.ResponseWriter
+		arg2 *http.Request
+	}
+	ListLeftoversStub        func(http.ResponseWriter, *http.Request)
+	listLeftoversMutex       sync.RWMutex
+	listLeftoversArgsForCall []struct {
+		arg1 http.ResponseWriter
+		arg2 *http.Request
+	}
+	RecordCookedStub        func(http.ResponseWriter, *http.Request)
+	recordCookedMutex       sync.RWMutex
+	recordCookedArgsForCall []struct {
+		arg1 http.ResponseWriter
+		arg2 *http.Request
+	}
+	SuggestionsStub        func(http.ResponseWriter, *http.Request)
+	suggestionsMutex       sync.RWMutex
+	suggestionsArgsForCall []struct {
+		arg1 http.ResponseWriter
+		arg2 *http.Request
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeCookLogHandler) CookHistory(arg1 http.ResponseWriter, arg2 *http.Request) {
+	fake.cookHistoryMutex.Lock()
+	fake.cookHistoryArgsForCall = append(fake.cookHistoryArgsForCall, struct {
+		arg1 http.ResponseWriter
+		arg2 *http.Request
+	}{arg1, arg2})
+	fake.recordInvocation("CookHistory", []interface{}{arg1, arg2})
+	fake.cookHistoryMutex.Unlock()
+	if fake.CookHistoryStub != nil {
+		fake.CookHistoryStub(arg1, arg2)
+	}
+}
+
+func (fake *FakeCookLogHandler) CookHistoryCallCount() int {
+	fake.cookHistoryMutex.RLock()
+	defer fake.cookHistoryMutex.RUnlock()
+	return len(fake.cookHistoryArgsForCall)
+}
+
+func (fake *FakeCookLogHandler) CookHistoryCalls(stub func(http.ResponseWriter, *http.Request)) {
+	fake.cookHistoryMutex.Lock()
+	defer fake.cookHistoryMutex.Unlock()
+	fake.CookHistoryStub = stub
+}
+
+func (fake *FakeCookLogHandler) CookHistoryArgsForCall(i int) (http.ResponseWriter, *http.Request) {
+	fake.cookHistoryMutex.RLock()
+	defer fake.cookHistoryMutex.RUnlock()
+	argsForCall := fake.cookHistoryArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeCookLogHandler) EatLeftovers(arg1 http.ResponseWriter, arg2 *http.Request) {
+	fake.eatLeftoversMutex.Lock()
+	fake.eatLeftoversArgsForCall = append(fake.eatLeftoversArgsForCall, struct {
+		arg1 http.ResponseWriter
+		arg2 *http.Request
+	}{arg1, arg2})
+	fake.recordInvocation("EatLeftovers", []interface{}{arg1, arg2})
+	fake.eatLeftoversMutex.Unlock()
+	if fake.EatLeftoversStub != nil {
+		fake.EatLeftoversStub(arg1, arg2)
+	}
+}
+
+func (fake *FakeCookLogHandler) EatLeftoversCallCount() int {
+	fake.eatLeftoversMutex.RLock()
+	defer fake.eatLeftoversMutex.RUnlock()
+	return len(fake.eatLeftoversArgsForCall)
+}
+
+func (fake *FakeCookLogHandler) EatLeftoversCalls(stub func(http.ResponseWriter, *http.Request)) {
+	fake.eatLeftoversMutex.Lock()
+	defer fake.eatLeftoversMutex.Unlock()
+	fake.EatLeftoversStub = stub
+}
+
+func (fake *FakeCookLogHandler) EatLeftoversArgsForCall(i int) (http.ResponseWriter, *http.Request) {
+	fake.eatLeftoversMutex.RLock()
+	defer fake.eatLeftoversMutex.RUnlock()
+	argsForCall := fake.eatLeftoversArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeCookLogHandler) ListLeftovers(arg1 http.ResponseWriter, arg2 *http.Request) {
+	fake.listLeftoversMutex.Lock()
+	fake.listLeftoversArgsForCall = append(fake.listLeftoversArgsForCall, struct {
+		arg1 http.ResponseWriter
+		arg2 *http.Request
+	}{arg1, arg2})
+	fake.recordInvocation("ListLeftovers", []interface{}{arg1, arg2})
+	fake.listLeftoversMutex.Unlock()
+	if fake.ListLeftoversStub != nil {
+		fake.ListLeftoversStub(arg1, arg2)
+	}
+}
+
+func (fake *FakeCookLogHandler) ListLeftoversCallCount() int {
+	fake.listLeftoversMutex.RLock()
+	defer fake.listLeftoversMutex.RUnlock()
+	return len(fake.listLeftoversArgsForCall)
+}
+
+func (fake *FakeCookLogHandler) ListLeftoversCalls(stub func(http.ResponseWriter, *http.Request)) {
+	fake.listLeftoversMutex.Lock()
+	defer fake.listLeftoversMutex.Unlock()
+	fake.ListLeftoversStub = stub
+}
+
+func (fake *FakeCookLogHandler) ListLeftoversArgsForCall(i int) (http.ResponseWriter, *http.Request) {
+	fake.listLeftoversMutex.RLock()
+	defer fake.listLeftoversMutex.RUnlock()
+	argsForCall := fake.listLeftoversArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeCookLogHandler) RecordCooked(arg1 http.ResponseWriter, arg2 *http.Request) {
+	fake.recordCookedMutex.Lock()
+	fake.recordCookedArgsForCall = append(fake.recordCookedArgsForCall, struct {
+		arg1 http.ResponseWriter
+		arg2 *http.Request
+	}{arg1, arg2})
+	fake.recordInvocation("RecordCooked", []interface{}{arg1, arg2})
+	fake.recordCookedMutex.Unlock()
+	if fake.RecordCookedStub != nil {
+		fake.RecordCookedStub(arg1, arg2)
+	}
+}
+
+func (fake *FakeCookLogHandler) RecordCookedCallCount() int {
+	fake.recordCookedMutex.RLock()
+	defer fake.recordCookedMutex.RUnlock()
+	return len(fake.recordCookedArgsForCall)
+}
+
+func (fake *FakeCookLogHandler) RecordCookedCalls(stub func(http.ResponseWriter, *http.Request)) {
+	fake.recordCookedMutex.Lock()
+	defer fake.recordCookedMutex.Unlock()
+	fake.RecordCookedStub = stub
+}
+
+func (fake *FakeCookLogHandler) RecordCookedArgsForCall(i int) (http.ResponseWriter, *http.Request) {
+	fake.recordCookedMutex.RLock()
+	defer fake.recordCookedMutex.RUnlock()
+	argsForCall := fake.recordCookedArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeCookLogHandler) Suggestions(arg1 http.ResponseWriter, arg2 *http.Request) {
+	fake.suggestionsMutex.Lock()
+	fake.suggestionsArgsForCall = append(fake.suggestionsArgsForCall, struct {
+		arg1 http.ResponseWriter
+		arg2 *http.Request
+	}{arg1, arg2})
+	fake.recordInvocation("Suggestions", []interface{}{arg1, arg2})
+	fake.suggestionsMutex.Unlock()
+	if fake.SuggestionsStub != nil {
+		fake.SuggestionsStub(arg1, arg2)
+	}
+}
+
+func (fake *FakeCookLogHandler) SuggestionsCallCount() int {
+	fake.suggestionsMutex.RLock()
+	defer fake.suggestionsMutex.RUnlock()
+	return len(fake.suggestionsArgsForCall)
+}
+
+func (fake *FakeCookLogHandler) SuggestionsCalls(stub func(http.ResponseWriter, *http.Request)) {
+	fake.suggestionsMutex.Lock()
+	defer fake.suggestionsMutex.Unlock()
+	fake.SuggestionsStub = stub
+}
+
+func (fake *FakeCookLogHandler) SuggestionsArgsForCall(i int) (http.ResponseWriter, *http.Request) {
+	fake.suggestionsMutex.RLock()
+	defer fake.suggestionsMutex.RUnlock()
+	argsForCall := fake.suggestionsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeCookLogHandler) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.cookHistoryMutex.RLock()
+	defer fake.cookHistoryMutex.RUnlock()
+	fake.eatLeftoversMutex.RLock()
+	defer fake.eatLeftoversMutex.RUnlock()
+	fake.listLeftoversMutex.RLock()
+	defer fake.listLeftoversMutex.RUnlock()
+	fake.recordCookedMutex.RLock()
+	defer fake.recordCookedMutex.RUnlock()
+	fake.suggestionsMutex.RLock()
+	defer fake.suggestionsMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeCookLogHandler) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ routing.CookLogHandler = new(FakeCookLogHandler)
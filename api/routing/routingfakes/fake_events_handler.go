@@ -0,0 +1,78 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package routingfakes
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/kieron-pivotal/menu-planner-app/routing"
+)
+
+type FakeEventsHandler struct {
+	StreamEventsStub        func(http.ResponseWriter, *http.Request)
+	streamEventsMutex       sync.RWMutex
+	streamEventsArgsForCall []struct {
+		arg1 http.ResponseWriter
+		arg2 *http.Request
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeEventsHandler) StreamEvents(arg1 http.ResponseWriter, arg2 *http.Request) {
+	fake.streamEventsMutex.Lock()
+	fake.streamEventsArgsForCall = append(fake.streamEventsArgsForCall, struct {
+		arg1 http.ResponseWriter
+		arg2 *http.Request
+	}{arg1, arg2})
+	fake.recordInvocation("StreamEvents", []interface{}{arg1, arg2})
+	fake.streamEventsMutex.Unlock()
+	if fake.StreamEventsStub != nil {
+		fake.StreamEventsStub(arg1, arg2)
+	}
+}
+
+func (fake *FakeEventsHandler) StreamEventsCallCount() int {
+	fake.streamEventsMutex.RLock()
+	defer fake.streamEventsMutex.RUnlock()
+	return len(fake.streamEventsArgsForCall)
+}
+
+func (fake *FakeEventsHandler) StreamEventsCalls(stub func(http.ResponseWriter, *http.Request)) {
+	fake.streamEventsMutex.Lock()
+	defer fake.streamEventsMutex.Unlock()
+	fake.StreamEventsStub = stub
+}
+
+func (fake *FakeEventsHandler) StreamEventsArgsForCall(i int) (http.ResponseWriter, *http.Request) {
+	fake.streamEventsMutex.RLock()
+	defer fake.streamEventsMutex.RUnlock()
+	argsForCall := fake.streamEventsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeEventsHandler) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.streamEventsMutex.RLock()
+	defer fake.streamEventsMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeEventsHandler) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ routing.EventsHandler = new(FakeEventsHandler)
@@ -0,0 +1,78 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package routingfakes
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/kieron-pivotal/menu-planner-app/routing"
+)
+
+type FakeGraphQLHandler struct {
+	QueryStub        func(http.ResponseWriter, *http.Request)
+	queryMutex       sync.RWMutex
+	queryArgsForCall []struct {
+		arg1 http.ResponseWriter
+		arg2 *http.Request
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeGraphQLHandler) Query(arg1 http.ResponseWriter, arg2 *http.Request) {
+	fake.queryMutex.Lock()
+	fake.queryArgsForCall = append(fake.queryArgsForCall, struct {
+		arg1 http.ResponseWriter
+		arg2 *http.Request
+	}{arg1, arg2})
+	fake.recordInvocation("Query", []interface{}{arg1, arg2})
+	fake.queryMutex.Unlock()
+	if fake.QueryStub != nil {
+		fake.QueryStub(arg1, arg2)
+	}
+}
+
+func (fake *FakeGraphQLHandler) QueryCallCount() int {
+	fake.queryMutex.RLock()
+	defer fake.queryMutex.RUnlock()
+	return len(fake.queryArgsForCall)
+}
+
+func (fake *FakeGraphQLHandler) QueryCalls(stub func(http.ResponseWriter, *http.Request)) {
+	fake.queryMutex.Lock()
+	defer fake.queryMutex.Unlock()
+	fake.QueryStub = stub
+}
+
+func (fake *FakeGraphQLHandler) QueryArgsForCall(i int) (http.ResponseWriter, *http.Request) {
+	fake.queryMutex.RLock()
+	defer fake.queryMutex.RUnlock()
+	argsForCall := fake.queryArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeGraphQLHandler) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.queryMutex.RLock()
+	defer fake.queryMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeGraphQLHandler) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ routing.GraphQLHandler = new(FakeGraphQLHandler)
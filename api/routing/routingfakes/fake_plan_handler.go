@@ -0,0 +1,438 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package routingfakes
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/kieron-pivotal/menu-planner-app/routing"
+)
+
+type FakePlanHandler struct {
+	ApplyRulesStub        func(http.ResponseWriter, *http.Request)
+	applyRulesMutex       sync.RWMutex
+	applyRulesArgsForCall []struct {
+		arg1 http.ResponseWriter
+		arg2 *http.Request
+	}
+	ApplyTemplateStub        func(http.ResponseWriter, *http.Request)
+	applyTemplateMutex       sync.RWMutex
+	applyTemplateArgsForCall []struct {
+		arg1 http.ResponseWriter
+		arg2 *http.Request
+	}
+	DeletePlanRuleStub        func(http.ResponseWriter, *http.Request)
+	deletePlanRuleMutex       sync.RWMutex
+	deletePlanRuleArgsForCall []struct {
+		arg1 http.ResponseWriter
+		arg2 *http.Request
+	}
+	DeletePlanTemplateStub        func(http.ResponseWriter, *http.Request)
+	deletePlanTemplateMutex       sync.RWMutex
+	deletePlanTemplateArgsForCall []struct {
+		arg1 http.ResponseWriter
+		arg2 *http.Request
+	}
+	GetPlanStub        func(http.ResponseWriter, *http.Request)
+	getPlanMutex       sync.RWMutex
+	getPlanArgsForCall []struct {
+		arg1 http.ResponseWriter
+		arg2 *http.Request
+	}
+	ListPlanRulesStub        func(http.ResponseWriter, *http.Request)
+	listPlanRulesMutex       sync.RWMutex
+	listPlanRulesArgsForCall []struct {
+		arg1 http.ResponseWriter
+		arg2 *http.Request
+	}
+	ListPlanTemplatesStub        func(http.ResponseWriter, *http.Request)
+	listPlanTemplatesMutex       sync.RWMutex
+	listPlanTemplatesArgsForCall []struct {
+		arg1 http.ResponseWriter
+		arg2 *http.Request
+	}
+	NewPlanTemplateStub        func(http.ResponseWriter, *http.Request)
+	newPlanTemplateMutex       sync.RWMutex
+	newPlanTemplateArgsForCall []struct {
+		arg1 http.ResponseWriter
+		arg2 *http.Request
+	}
+	SavePlanStub        func(http.ResponseWriter, *http.Request)
+	savePlanMutex       sync.RWMutex
+	savePlanArgsForCall []struct {
+		arg1 http.ResponseWriter
+		arg2 *http.Request
+	}
+	SavePlanRuleStub        func(http.ResponseWriter, *http.Request)
+	savePlanRuleMutex       sync.RWMutex
+	savePlanRuleArgsForCall []struct {
+		arg1 http.ResponseWriter
+		arg2 *http.Request
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakePlanHandler) ApplyRules(arg1 http.ResponseWriter, arg2 *http.Request) {
+	fake.applyRulesMutex.Lock()
+	fake.applyRulesArgsForCall = append(fake.applyRulesArgsForCall, struct {
+		arg1 http.ResponseWriter
+		arg2 *http.Request
+	}{arg1, arg2})
+	fake.recordInvocation("ApplyRules", []interface{}{arg1, arg2})
+	fake.applyRulesMutex.Unlock()
+	if fake.ApplyRulesStub != nil {
+		fake.ApplyRulesStub(arg1, arg2)
+	}
+}
+
+func (fake *FakePlanHandler) ApplyRulesCallCount() int {
+	fake.applyRulesMutex.RLock()
+	defer fake.applyRulesMutex.RUnlock()
+	return len(fake.applyRulesArgsForCall)
+}
+
+func (fake *FakePlanHandler) ApplyRulesCalls(stub func(http.ResponseWriter, *http.Request)) {
+	fake.applyRulesMutex.Lock()
+	defer fake.applyRulesMutex.Unlock()
+	fake.ApplyRulesStub = stub
+}
+
+func (fake *FakePlanHandler) ApplyRulesArgsForCall(i int) (http.ResponseWriter, *http.Request) {
+	fake.applyRulesMutex.RLock()
+	defer fake.applyRulesMutex.RUnlock()
+	argsForCall := fake.applyRulesArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakePlanHandler) ApplyTemplate(arg1 http.ResponseWriter, arg2 *http.Request) {
+	fake.applyTemplateMutex.Lock()
+	fake.applyTemplateArgsForCall = append(fake.applyTemplateArgsForCall, struct {
+		arg1 http.ResponseWriter
+		arg2 *http.Request
+	}{arg1, arg2})
+	fake.recordInvocation("ApplyTemplate", []interface{}{arg1, arg2})
+	fake.applyTemplateMutex.Unlock()
+	if fake.ApplyTemplateStub != nil {
+		fake.ApplyTemplateStub(arg1, arg2)
+	}
+}
+
+func (fake *FakePlanHandler) ApplyTemplateCallCount() int {
+	fake.applyTemplateMutex.RLock()
+	defer fake.applyTemplateMutex.RUnlock()
+	return len(fake.applyTemplateArgsForCall)
+}
+
+func (fake *FakePlanHandler) ApplyTemplateCalls(stub func(http.ResponseWriter, *http.Request)) {
+	fake.applyTemplateMutex.Lock()
+	defer fake.applyTemplateMutex.Unlock()
+	fake.ApplyTemplateStub = stub
+}
+
+func (fake *FakePlanHandler) ApplyTemplateArgsForCall(i int) (http.ResponseWriter, *http.Request) {
+	fake.applyTemplateMutex.RLock()
+	defer fake.applyTemplateMutex.RUnlock()
+	argsForCall := fake.applyTemplateArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakePlanHandler) DeletePlanRule(arg1 http.ResponseWriter, arg2 *http.Request) {
+	fake.deletePlanRuleMutex.Lock()
+	fake.deletePlanRuleArgsForCall = append(fake.deletePlanRuleArgsForCall, struct {
+		arg1 http.ResponseWriter
+		arg2 *http.Request
+	}{arg1, arg2})
+	fake.recordInvocation("DeletePlanRule", []interface{}{arg1, arg2})
+	fake.deletePlanRuleMutex.Unlock()
+	if fake.DeletePlanRuleStub != nil {
+		fake.DeletePlanRuleStub(arg1, arg2)
+	}
+}
+
+func (fake *FakePlanHandler) DeletePlanRuleCallCount() int {
+	fake.deletePlanRuleMutex.RLock()
+	defer fake.deletePlanRuleMutex.RUnlock()
+	return len(fake.deletePlanRuleArgsForCall)
+}
+
+func (fake *FakePlanHandler) DeletePlanRuleCalls(stub func(http.ResponseWriter, *http.Request)) {
+	fake.deletePlanRuleMutex.Lock()
+	defer fake.deletePlanRuleMutex.Unlock()
+	fake.DeletePlanRuleStub = stub
+}
+
+func (fake *FakePlanHandler) DeletePlanRuleArgsForCall(i int) (http.ResponseWriter, *http.Request) {
+	fake.deletePlanRuleMutex.RLock()
+	defer fake.deletePlanRuleMutex.RUnlock()
+	argsForCall := fake.deletePlanRuleArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakePlanHandler) DeletePlanTemplate(arg1 http.ResponseWriter, arg2 *http.Request) {
+	fake.deletePlanTemplateMutex.Lock()
+	fake.deletePlanTemplateArgsForCall = append(fake.deletePlanTemplateArgsForCall, struct {
+		arg1 http.ResponseWriter
+		arg2 *http.Request
+	}{arg1, arg2})
+	fake.recordInvocation("DeletePlanTemplate", []interface{}{arg1, arg2})
+	fake.deletePlanTemplateMutex.Unlock()
+	if fake.DeletePlanTemplateStub != nil {
+		fake.DeletePlanTemplateStub(arg1, arg2)
+	}
+}
+
+func (fake *FakePlanHandler) DeletePlanTemplateCallCount() int {
+	fake.deletePlanTemplateMutex.RLock()
+	defer fake.deletePlanTemplateMutex.RUnlock()
+	return len(fake.deletePlanTemplateArgsForCall)
+}
+
+func (fake *FakePlanHandler) DeletePlanTemplateCalls(stub func(http.ResponseWriter, *http.Request)) {
+	fake.deletePlanTemplateMutex.Lock()
+	defer fake.deletePlanTemplateMutex.Unlock()
+	fake.DeletePlanTemplateStub = stub
+}
+
+func (fake *FakePlanHandler) DeletePlanTemplateArgsForCall(i int) (http.ResponseWriter, *http.Request) {
+	fake.deletePlanTemplateMutex.RLock()
+	defer fake.deletePlanTemplateMutex.RUnlock()
+	argsForCall := fake.deletePlanTemplateArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakePlanHandler) GetPlan(arg1 http.ResponseWriter, arg2 *http.Request) {
+	fake.getPlanMutex.Lock()
+	fake.getPlanArgsForCall = append(fake.getPlanArgsForCall, struct {
+		arg1 http.ResponseWriter
+		arg2 *http.Request
+	}{arg1, arg2})
+	fake.recordInvocation("GetPlan", []interface{}{arg1, arg2})
+	fake.getPlanMutex.Unlock()
+	if fake.GetPlanStub != nil {
+		fake.GetPlanStub(arg1, arg2)
+	}
+}
+
+func (fake *FakePlanHandler) GetPlanCallCount() int {
+	fake.getPlanMutex.RLock()
+	defer fake.getPlanMutex.RUnlock()
+	return len(fake.getPlanArgsForCall)
+}
+
+func (fake *FakePlanHandler) GetPlanCalls(stub func(http.ResponseWriter, *http.Request)) {
+	fake.getPlanMutex.Lock()
+	defer fake.getPlanMutex.Unlock()
+	fake.GetPlanStub = stub
+}
+
+func (fake *FakePlanHandler) GetPlanArgsForCall(i int) (http.ResponseWriter, *http.Request) {
+	fake.getPlanMutex.RLock()
+	defer fake.getPlanMutex.RUnlock()
+	argsForCall := fake.getPlanArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakePlanHandler) ListPlanRules(arg1 http.ResponseWriter, arg2 *http.Request) {
+	fake.listPlanRulesMutex.Lock()
+	fake.listPlanRulesArgsForCall = append(fake.listPlanRulesArgsForCall, struct {
+		arg1 http.ResponseWriter
+		arg2 *http.Request
+	}{arg1, arg2})
+	fake.recordInvocation("ListPlanRules", []interface{}{arg1, arg2})
+	fake.listPlanRulesMutex.Unlock()
+	if fake.ListPlanRulesStub != nil {
+		fake.ListPlanRulesStub(arg1, arg2)
+	}
+}
+
+func (fake *FakePlanHandler) ListPlanRulesCallCount() int {
+	fake.listPlanRulesMutex.RLock()
+	defer fake.listPlanRulesMutex.RUnlock()
+	return len(fake.listPlanRulesArgsForCall)
+}
+
+func (fake *FakePlanHandler) ListPlanRulesCalls(stub func(http.ResponseWriter, *http.Request)) {
+	fake.listPlanRulesMutex.Lock()
+	defer fake.listPlanRulesMutex.Unlock()
+	fake.ListPlanRulesStub = stub
+}
+
+func (fake *FakePlanHandler) ListPlanRulesArgsForCall(i int) (http.ResponseWriter, *http.Request) {
+	fake.listPlanRulesMutex.RLock()
+	defer fake.listPlanRulesMutex.RUnlock()
+	argsForCall := fake.listPlanRulesArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakePlanHandler) ListPlanTemplates(arg1 http.ResponseWriter, arg2 *http.Request) {
+	fake.listPlanTemplatesMutex.Lock()
+	fake.listPlanTemplatesArgsForCall = append(fake.listPlanTemplatesArgsForCall, struct {
+		arg1 http.ResponseWriter
+		arg2 *http.Request
+	}{arg1, arg2})
+	fake.recordInvocation("ListPlanTemplates", []interface{}{arg1, arg2})
+	fake.listPlanTemplatesMutex.Unlock()
+	if fake.ListPlanTemplatesStub != nil {
+		fake.ListPlanTemplatesStub(arg1, arg2)
+	}
+}
+
+func (fake *FakePlanHandler) ListPlanTemplatesCallCount() int {
+	fake.listPlanTemplatesMutex.RLock()
+	defer fake.listPlanTemplatesMutex.RUnlock()
+	return len(fake.listPlanTemplatesArgsForCall)
+}
+
+func (fake *FakePlanHandler) ListPlanTemplatesCalls(stub func(http.ResponseWriter, *http.Request)) {
+	fake.listPlanTemplatesMutex.Lock()
+	defer fake.listPlanTemplatesMutex.Unlock()
+	fake.ListPlanTemplatesStub = stub
+}
+
+func (fake *FakePlanHandler) ListPlanTemplatesArgsForCall(i int) (http.ResponseWriter, *http.Request) {
+	fake.listPlanTemplatesMutex.RLock()
+	defer fake.listPlanTemplatesMutex.RUnlock()
+	argsForCall := fake.listPlanTemplatesArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakePlanHandler) NewPlanTemplate(arg1 http.ResponseWriter, arg2 *http.Request) {
+	fake.newPlanTemplateMutex.Lock()
+	fake.newPlanTemplateArgsForCall = append(fake.newPlanTemplateArgsForCall, struct {
+		arg1 http.ResponseWriter
+		arg2 *http.Request
+	}{arg1, arg2})
+	fake.recordInvocation("NewPlanTemplate", []interface{}{arg1, arg2})
+	fake.newPlanTemplateMutex.Unlock()
+	if fake.NewPlanTemplateStub != nil {
+		fake.NewPlanTemplateStub(arg1, arg2)
+	}
+}
+
+func (fake *FakePlanHandler) NewPlanTemplateCallCount() int {
+	fake.newPlanTemplateMutex.RLock()
+	defer fake.newPlanTemplateMutex.RUnlock()
+	return len(fake.newPlanTemplateArgsForCall)
+}
+
+func (fake *FakePlanHandler) NewPlanTemplateCalls(stub func(http.ResponseWriter, *http.Request)) {
+	fake.newPlanTemplateMutex.Lock()
+	defer fake.newPlanTemplateMutex.Unlock()
+	fake.NewPlanTemplateStub = stub
+}
+
+func (fake *FakePlanHandler) NewPlanTemplateArgsForCall(i int) (http.ResponseWriter, *http.Request) {
+	fake.newPlanTemplateMutex.RLock()
+	defer fake.newPlanTemplateMutex.RUnlock()
+	argsForCall := fake.newPlanTemplateArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakePlanHandler) SavePlan(arg1 http.ResponseWriter, arg2 *http.Request) {
+	fake.savePlanMutex.Lock()
+	fake.savePlanArgsForCall = append(fake.savePlanArgsForCall, struct {
+		arg1 http.ResponseWriter
+		arg2 *http.Request
+	}{arg1, arg2})
+	fake.recordInvocation("SavePlan", []interface{}{arg1, arg2})
+	fake.savePlanMutex.Unlock()
+	if fake.SavePlanStub != nil {
+		fake.SavePlanStub(arg1, arg2)
+	}
+}
+
+func (fake *FakePlanHandler) SavePlanCallCount() int {
+	fake.savePlanMutex.RLock()
+	defer fake.savePlanMutex.RUnlock()
+	return len(fake.savePlanArgsForCall)
+}
+
+func (fake *FakePlanHandler) SavePlanCalls(stub func(http.ResponseWriter, *http.Request)) {
+	fake.savePlanMutex.Lock()
+	defer fake.savePlanMutex.Unlock()
+	fake.SavePlanStub = stub
+}
+
+func (fake *FakePlanHandler) SavePlanArgsForCall(i int) (http.ResponseWriter, *http.Request) {
+	fake.savePlanMutex.RLock()
+	defer fake.savePlanMutex.RUnlock()
+	argsForCall := fake.savePlanArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakePlanHandler) SavePlanRule(arg1 http.ResponseWriter, arg2 *http.Request) {
+	fake.savePlanRuleMutex.Lock()
+	fake.savePlanRuleArgsForCall = append(fake.savePlanRuleArgsForCall, struct {
+		arg1 http.ResponseWriter
+		arg2 *http.Request
+	}{arg1, arg2})
+	fake.recordInvocation("SavePlanRule", []interface{}{arg1, arg2})
+	fake.savePlanRuleMutex.Unlock()
+	if fake.SavePlanRuleStub != nil {
+		fake.SavePlanRuleStub(arg1, arg2)
+	}
+}
+
+func (fake *FakePlanHandler) SavePlanRuleCallCount() int {
+	fake.savePlanRuleMutex.RLock()
+	defer fake.savePlanRuleMutex.RUnlock()
+	return len(fake.savePlanRuleArgsForCall)
+}
+
+func (fake *FakePlanHandler) SavePlanRuleCalls(stub func(http.ResponseWriter, *http.Request)) {
+	fake.savePlanRuleMutex.Lock()
+	defer fake.savePlanRuleMutex.Unlock()
+	fake.SavePlanRuleStub = stub
+}
+
+func (fake *FakePlanHandler) SavePlanRuleArgsForCall(i int) (http.ResponseWriter, *http.Request) {
+	fake.savePlanRuleMutex.RLock()
+	defer fake.savePlanRuleMutex.RUnlock()
+	argsForCall := fake.savePlanRuleArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakePlanHandler) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.applyRulesMutex.RLock()
+	defer fake.applyRulesMutex.RUnlock()
+	fake.applyTemplateMutex.RLock()
+	defer fake.applyTemplateMutex.RUnlock()
+	fake.deletePlanRuleMutex.RLock()
+	defer fake.deletePlanRuleMutex.RUnlock()
+	fake.deletePlanTemplateMutex.RLock()
+	defer fake.deletePlanTemplateMutex.RUnlock()
+	fake.getPlanMutex.RLock()
+	defer fake.getPlanMutex.RUnlock()
+	fake.listPlanRulesMutex.RLock()
+	defer fake.listPlanRulesMutex.RUnlock()
+	fake.listPlanTemplatesMutex.RLock()
+	defer fake.listPlanTemplatesMutex.RUnlock()
+	fake.newPlanTemplateMutex.RLock()
+	defer fake.newPlanTemplateMutex.RUnlock()
+	fake.savePlanMutex.RLock()
+	defer fake.savePlanMutex.RUnlock()
+	fake.savePlanRuleMutex.RLock()
+	defer fake.savePlanRuleMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakePlanHandler) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ routing.PlanHandler = new(FakePlanHandler)
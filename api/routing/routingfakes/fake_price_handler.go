@@ -0,0 +1,238 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package routingfakes
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/kieron-pivotal/menu-planner-app/routing"
+)
+
+type FakePriceHandler struct {
+	DeletePriceStub        func(http.ResponseWriter, *http.Request)
+	deletePriceMutex       sync.RWMutex
+	deletePriceArgsForCall []struct {
+		arg1 http.ResponseWriter
+		arg2 *http.Request
+	}
+	GetBudgetStub        func(http.ResponseWriter, *http.Request)
+	getBudgetMutex       sync.RWMutex
+	getBudgetArgsForCall []struct {
+		arg1 http.ResponseWriter
+		arg2 *http.Request
+	}
+	ListPricesStub        func(http.ResponseWriter, *http.Request)
+	listPricesMutex       sync.RWMutex
+	listPricesArgsForCall []struct {
+		arg1 http.ResponseWriter
+		arg2 *http.Request
+	}
+	SaveBudgetStub        func(http.ResponseWriter, *http.Request)
+	saveBudgetMutex       sync.RWMutex
+	saveBudgetArgsForCall []struct {
+		arg1 http.ResponseWriter
+		arg2 *http.Request
+	}
+	SavePriceStub        func(http.ResponseWriter, *http.Request)
+	savePriceMutex       sync.RWMutex
+	savePriceArgsForCall []struct {
+		arg1 http.ResponseWriter
+		arg2 *http.Request
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakePriceHandler) DeletePrice(arg1 http.ResponseWriter, arg2 *http.Request) {
+	fake.deletePriceMutex.Lock()
+	fake.deletePriceArgsForCall = append(fake.deletePriceArgsForCall, struct {
+		arg1 http.ResponseWriter
+		arg2 *http.Request
+	}{arg1, arg2})
+	fake.recordInvocation("DeletePrice", []interface{}{arg1, arg2})
+	fake.deletePriceMutex.Unlock()
+	if fake.DeletePriceStub != nil {
+		fake.DeletePriceStub(arg1, arg2)
+	}
+}
+
+func (fake *FakePriceHandler) DeletePriceCallCount() int {
+	fake.deletePriceMutex.RLock()
+	defer fake.deletePriceMutex.RUnlock()
+	return len(fake.deletePriceArgsForCall)
+}
+
+func (fake *FakePriceHandler) DeletePriceCalls(stub func(http.ResponseWriter, *http.Request)) {
+	fake.deletePriceMutex.Lock()
+	defer fake.deletePriceMutex.Unlock()
+	fake.DeletePriceStub = stub
+}
+
+func (fake *FakePriceHandler) DeletePriceArgsForCall(i int) (http.ResponseWriter, *http.Request) {
+	fake.deletePriceMutex.RLock()
+	defer fake.deletePriceMutex.RUnlock()
+	argsForCall := fake.deletePriceArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakePriceHandler) GetBudget(arg1 http.ResponseWriter, arg2 *http.Request) {
+	fake.getBudgetMutex.Lock()
+	fake.getBudgetArgsForCall = append(fake.getBudgetArgsForCall, struct {
+		arg1 http.ResponseWriter
+		arg2 *http.Request
+	}{arg1, arg2})
+	fake.recordInvocation("GetBudget", []interface{}{arg1, arg2})
+	fake.getBudgetMutex.Unlock()
+	if fake.GetBudgetStub != nil {
+		fake.GetBudgetStub(arg1, arg2)
+	}
+}
+
+func (fake *FakePriceHandler) GetBudgetCallCount() int {
+	fake.getBudgetMutex.RLock()
+	defer fake.getBudgetMutex.RUnlock()
+	return len(fake.getBudgetArgsForCall)
+}
+
+func (fake *FakePriceHandler) GetBudgetCalls(stub func(http.ResponseWriter, *http.Request)) {
+	fake.getBudgetMutex.Lock()
+	defer fake.getBudgetMutex.Unlock()
+	fake.GetBudgetStub = stub
+}
+
+func (fake *FakePriceHandler) GetBudgetArgsForCall(i int) (http.ResponseWriter, *http.Request) {
+	fake.getBudgetMutex.RLock()
+	defer fake.getBudgetMutex.RUnlock()
+	argsForCall := fake.getBudgetArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakePriceHandler) ListPrices(arg1 http.ResponseWriter, arg2 *http.Request) {
+	fake.listPricesMutex.Lock()
+	fake.listPricesArgsForCall = append(fake.listPricesArgsForCall, struct {
+		arg1 http.ResponseWriter
+		arg2 *http.Request
+	}{arg1, arg2})
+	fake.recordInvocation("ListPrices", []interface{}{arg1, arg2})
+	fake.listPricesMutex.Unlock()
+	if fake.ListPricesStub != nil {
+		fake.ListPricesStub(arg1, arg2)
+	}
+}
+
+func (fake *FakePriceHandler) ListPricesCallCount() int {
+	fake.listPricesMutex.RLock()
+	defer fake.listPricesMutex.RUnlock()
+	return len(fake.listPricesArgsForCall)
+}
+
+func (fake *FakePriceHandler) ListPricesCalls(stub func(http.ResponseWriter, *http.Request)) {
+	fake.listPricesMutex.Lock()
+	defer fake.listPricesMutex.Unlock()
+	fake.ListPricesStub = stub
+}
+
+func (fake *FakePriceHandler) ListPricesArgsForCall(i int) (http.ResponseWriter, *http.Request) {
+	fake.listPricesMutex.RLock()
+	defer fake.listPricesMutex.RUnlock()
+	argsForCall := fake.listPricesArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakePriceHandler) SaveBudget(arg1 http.ResponseWriter, arg2 *http.Request) {
+	fake.saveBudgetMutex.Lock()
+	fake.saveBudgetArgsForCall = append(fake.saveBudgetArgsForCall, struct {
+		arg1 http.ResponseWriter
+		arg2 *http.Request
+	}{arg1, arg2})
+	fake.recordInvocation("SaveBudget", []interface{}{arg1, arg2})
+	fake.saveBudgetMutex.Unlock()
+	if fake.SaveBudgetStub != nil {
+		fake.SaveBudgetStub(arg1, arg2)
+	}
+}
+
+func (fake *FakePriceHandler) SaveBudgetCallCount() int {
+	fake.saveBudgetMutex.RLock()
+	defer fake.saveBudgetMutex.RUnlock()
+	return len(fake.saveBudgetArgsForCall)
+}
+
+func (fake *FakePriceHandler) SaveBudgetCalls(stub func(http.ResponseWriter, *http.Request)) {
+	fake.saveBudgetMutex.Lock()
+	defer fake.saveBudgetMutex.Unlock()
+	fake.SaveBudgetStub = stub
+}
+
+func (fake *FakePriceHandler) SaveBudgetArgsForCall(i int) (http.ResponseWriter, *http.Request) {
+	fake.saveBudgetMutex.RLock()
+	defer fake.saveBudgetMutex.RUnlock()
+	argsForCall := fake.saveBudgetArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakePriceHandler) SavePrice(arg1 http.ResponseWriter, arg2 *http.Request) {
+	fake.savePriceMutex.Lock()
+	fake.savePriceArgsForCall = append(fake.savePriceArgsForCall, struct {
+		arg1 http.ResponseWriter
+		arg2 *http.Request
+	}{arg1, arg2})
+	fake.recordInvocation("SavePrice", []interface{}{arg1, arg2})
+	fake.savePriceMutex.Unlock()
+	if fake.SavePriceStub != nil {
+		fake.SavePriceStub(arg1, arg2)
+	}
+}
+
+func (fake *FakePriceHandler) SavePriceCallCount() int {
+	fake.savePriceMutex.RLock()
+	defer fake.savePriceMutex.RUnlock()
+	return len(fake.savePriceArgsForCall)
+}
+
+func (fake *FakePriceHandler) SavePriceCalls(stub func(http.ResponseWriter, *http.Request)) {
+	fake.savePriceMutex.Lock()
+	defer fake.savePriceMutex.Unlock()
+	fake.SavePriceStub = stub
+}
+
+func (fake *FakePriceHandler) SavePriceArgsForCall(i int) (http.ResponseWriter, *http.Request) {
+	fake.savePriceMutex.RLock()
+	defer fake.savePriceMutex.RUnlock()
+	argsForCall := fake.savePriceArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakePriceHandler) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.deletePriceMutex.RLock()
+	defer fake.deletePriceMutex.RUnlock()
+	fake.getBudgetMutex.RLock()
+	defer fake.getBudgetMutex.RUnlock()
+	fake.listPricesMutex.RLock()
+	defer fake.listPricesMutex.RUnlock()
+	fake.saveBudgetMutex.RLock()
+	defer fake.saveBudgetMutex.RUnlock()
+	fake.savePriceMutex.RLock()
+	defer fake.savePriceMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakePriceHandler) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ routing.PriceHandler = new(FakePriceHandler)
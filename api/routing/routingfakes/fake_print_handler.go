@@ -0,0 +1,158 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package routingfakes
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/kieron-pivotal/menu-planner-app/routing"
+)
+
+type FakePrintHandler struct {
+	PlanPrintoutStub        func(http.ResponseWriter, *http.Request)
+	planPrintoutMutex       sync.RWMutex
+	planPrintoutArgsForCall []struct {
+		arg1 http.ResponseWriter
+		arg2 *http.Request
+	}
+	RecipeCardStub        func(http.ResponseWriter, *http.Request)
+	recipeCardMutex       sync.RWMutex
+	recipeCardArgsForCall []struct {
+		arg1 http.ResponseWriter
+		arg2 *http.Request
+	}
+	RecipesPrintoutStub        func(http.ResponseWriter, *http.Request)
+	recipesPrintoutMutex       sync.RWMutex
+	recipesPrintoutArgsForCall []struct {
+		arg1 http.ResponseWriter
+		arg2 *http.Request
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakePrintHandler) PlanPrintout(arg1 http.ResponseWriter, arg2 *http.Request) {
+	fake.planPrintoutMutex.Lock()
+	fake.planPrintoutArgsForCall = append(fake.planPrintoutArgsForCall, struct {
+		arg1 http.ResponseWriter
+		arg2 *http.Request
+	}{arg1, arg2})
+	fake.recordInvocation("PlanPrintout", []interface{}{arg1, arg2})
+	fake.planPrintoutMutex.Unlock()
+	if fake.PlanPrintoutStub != nil {
+		fake.PlanPrintoutStub(arg1, arg2)
+	}
+}
+
+func (fake *FakePrintHandler) PlanPrintoutCallCount() int {
+	fake.planPrintoutMutex.RLock()
+	defer fake.planPrintoutMutex.RUnlock()
+	return len(fake.planPrintoutArgsForCall)
+}
+
+func (fake *FakePrintHandler) PlanPrintoutCalls(stub func(http.ResponseWriter, *http.Request)) {
+	fake.planPrintoutMutex.Lock()
+	defer fake.planPrintoutMutex.Unlock()
+	fake.PlanPrintoutStub = stub
+}
+
+func (fake *FakePrintHandler) PlanPrintoutArgsForCall(i int) (http.ResponseWriter, *http.Request) {
+	fake.planPrintoutMutex.RLock()
+	defer fake.planPrintoutMutex.RUnlock()
+	argsForCall := fake.planPrintoutArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakePrintHandler) RecipeCard(arg1 http.ResponseWriter, arg2 *http.Request) {
+	fake.recipeCardMutex.Lock()
+	fake.recipeCardArgsForCall = append(fake.recipeCardArgsForCall, struct {
+		arg1 http.ResponseWriter
+		arg2 *http.Request
+	}{arg1, arg2})
+	fake.recordInvocation("RecipeCard", []interface{}{arg1, arg2})
+	fake.recipeCardMutex.Unlock()
+	if fake.RecipeCardStub != nil {
+		fake.RecipeCardStub(arg1, arg2)
+	}
+}
+
+func (fake *FakePrintHandler) RecipeCardCallCount() int {
+	fake.recipeCardMutex.RLock()
+	defer fake.recipeCardMutex.RUnlock()
+	return len(fake.recipeCardArgsForCall)
+}
+
+func (fake *FakePrintHandler) RecipeCardCalls(stub func(http.ResponseWriter, *http.Request)) {
+	fake.recipeCardMutex.Lock()
+	defer fake.recipeCardMutex.Unlock()
+	fake.RecipeCardStub = stub
+}
+
+func (fake *FakePrintHandler) RecipeCardArgsForCall(i int) (http.ResponseWriter, *http.Request) {
+	fake.recipeCardMutex.RLock()
+	defer fake.recipeCardMutex.RUnlock()
+	argsForCall := fake.recipeCardArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakePrintHandler) RecipesPrintout(arg1 http.ResponseWriter, arg2 *http.Request) {
+	fake.recipesPrintoutMutex.Lock()
+	fake.recipesPrintoutArgsForCall = append(fake.recipesPrintoutArgsForCall, struct {
+		arg1 http.ResponseWriter
+		arg2 *http.Request
+	}{arg1, arg2})
+	fake.recordInvocation("RecipesPrintout", []interface{}{arg1, arg2})
+	fake.recipesPrintoutMutex.Unlock()
+	if fake.RecipesPrintoutStub != nil {
+		fake.RecipesPrintoutStub(arg1, arg2)
+	}
+}
+
+func (fake *FakePrintHandler) RecipesPrintoutCallCount() int {
+	fake.recipesPrintoutMutex.RLock()
+	defer fake.recipesPrintoutMutex.RUnlock()
+	return len(fake.recipesPrintoutArgsForCall)
+}
+
+func (fake *FakePrintHandler) RecipesPrintoutCalls(stub func(http.ResponseWriter, *http.Request)) {
+	fake.recipesPrintoutMutex.Lock()
+	defer fake.recipesPrintoutMutex.Unlock()
+	fake.RecipesPrintoutStub = stub
+}
+
+func (fake *FakePrintHandler) RecipesPrintoutArgsForCall(i int) (http.ResponseWriter, *http.Request) {
+	fake.recipesPrintoutMutex.RLock()
+	defer fake.recipesPrintoutMutex.RUnlock()
+	argsForCall := fake.recipesPrintoutArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakePrintHandler) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.planPrintoutMutex.RLock()
+	defer fake.planPrintoutMutex.RUnlock()
+	fake.recipeCardMutex.RLock()
+	defer fake.recipeCardMutex.RUnlock()
+	fake.recipesPrintoutMutex.RLock()
+	defer fake.recipesPrintoutMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakePrintHandler) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ routing.PrintHandler = new(FakePrintHandler)
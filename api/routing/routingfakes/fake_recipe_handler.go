@@ -9,22 +9,72 @@ import (
 )
 
 type FakeRecipeHandler struct {
+	GetRecipeStub        func(http.ResponseWriter, *http.Request)
+	getRecipeMutex       sync.RWMutex
+	getRecipeArgsForCall []struct {
+		arg1 http.ResponseWriter
+		arg2 *http.Request
+	}
 	GetRecipesStub        func(http.ResponseWriter, *http.Request)
 	getRecipesMutex       sync.RWMutex
 	getRecipesArgsForCall []struct {
 		arg1 http.ResponseWriter
 		arg2 *http.Request
 	}
+	MergeRecipesStub        func(http.ResponseWriter, *http.Request)
+	mergeRecipesMutex       sync.RWMutex
+	mergeRecipesArgsForCall []struct {
+		arg1 http.ResponseWriter
+		arg2 *http.Request
+	}
 	NewRecipeStub        func(http.ResponseWriter, *http.Request)
 	newRecipeMutex       sync.RWMutex
 	newRecipeArgsForCall []struct {
 		arg1 http.ResponseWriter
 		arg2 *http.Request
 	}
+	RateRecipeStub        func(http.ResponseWriter, *http.Request)
+	rateRecipeMutex       sync.RWMutex
+	rateRecipeArgsForCall []struct {
+		arg1 http.ResponseWriter
+		arg2 *http.Request
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *FakeRecipeHandler) GetRecipe(arg1 http.ResponseWriter, arg2 *http.Request) {
+	fake.getRecipeMutex.Lock()
+	fake.getRecipeArgsForCall = append(fake.getRecipeArgsForCall, struct {
+		arg1 http.ResponseWriter
+		arg2 *http.Request
+	}{arg1, arg2})
+	fake.recordInvocation("GetRecipe", []interface{}{arg1, arg2})
+	fake.getRecipeMutex.Unlock()
+	if fake.GetRecipeStub != nil {
+		fake.GetRecipeStub(arg1, arg2)
+	}
+}
+
+func (fake *FakeRecipeHandler) GetRecipeCallCount() int {
+	fake.getRecipeMutex.RLock()
+	defer fake.getRecipeMutex.RUnlock()
+	return len(fake.getRecipeArgsForCall)
+}
+
+func (fake *FakeRecipeHandler) GetRecipeCalls(stub func(http.ResponseWriter, *http.Request)) {
+	fake.getRecipeMutex.Lock()
+	defer fake.getRecipeMutex.Unlock()
+	fake.GetRecipeStub = stub
+}
+
+func (fake *FakeRecipeHandler) GetRecipeArgsForCall(i int) (http.ResponseWriter, *http.Request) {
+	fake.getRecipeMutex.RLock()
+	defer fake.getRecipeMutex.RUnlock()
+	argsForCall := fake.getRecipeArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
 func (fake *FakeRecipeHandler) GetRecipes(arg1 http.ResponseWriter, arg2 *http.Request) {
 	fake.getRecipesMutex.Lock()
 	fake.getRecipesArgsForCall = append(fake.getRecipesArgsForCall, struct {
@@ -57,6 +107,38 @@ func (fake *FakeRecipeHandler) GetRecipesArgsForCall(i int) (http.ResponseWriter
 	return argsForCall.arg1, argsForCall.arg2
 }
 
+func (fake *FakeRecipeHandler) MergeRecipes(arg1 http.ResponseWriter, arg2 *http.Request) {
+	fake.mergeRecipesMutex.Lock()
+	fake.mergeRecipesArgsForCall = append(fake.mergeRecipesArgsForCall, struct {
+		arg1 http.ResponseWriter
+		arg2 *http.Request
+	}{arg1, arg2})
+	fake.recordInvocation("MergeRecipes", []interface{}{arg1, arg2})
+	fake.mergeRecipesMutex.Unlock()
+	if fake.MergeRecipesStub != nil {
+		fake.MergeRecipesStub(arg1, arg2)
+	}
+}
+
+func (fake *FakeRecipeHandler) MergeRecipesCallCount() int {
+	fake.mergeRecipesMutex.RLock()
+	defer fake.mergeRecipesMutex.RUnlock()
+	return len(fake.mergeRecipesArgsForCall)
+}
+
+func (fake *FakeRecipeHandler) MergeRecipesCalls(stub func(http.ResponseWriter, *http.Request)) {
+	fake.mergeRecipesMutex.Lock()
+	defer fake.mergeRecipesMutex.Unlock()
+	fake.MergeRecipesStub = stub
+}
+
+func (fake *FakeRecipeHandler) MergeRecipesArgsForCall(i int) (http.ResponseWriter, *http.Request) {
+	fake.mergeRecipesMutex.RLock()
+	defer fake.mergeRecipesMutex.RUnlock()
+	argsForCall := fake.mergeRecipesArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
 func (fake *FakeRecipeHandler) NewRecipe(arg1 http.ResponseWriter, arg2 *http.Request) {
 	fake.newRecipeMutex.Lock()
 	fake.newRecipeArgsForCall = append(fake.newRecipeArgsForCall, struct {
@@ -89,13 +171,51 @@ func (fake *FakeRecipeHandler) NewRecipeArgsForCall(i int) (http.ResponseWriter,
 	return argsForCall.arg1, argsForCall.arg2
 }
 
+func (fake *FakeRecipeHandler) RateRecipe(arg1 http.ResponseWriter, arg2 *http.Request) {
+	fake.rateRecipeMutex.Lock()
+	fake.rateRecipeArgsForCall = append(fake.rateRecipeArgsForCall, struct {
+		arg1 http.ResponseWriter
+		arg2 *http.Request
+	}{arg1, arg2})
+	fake.recordInvocation("RateRecipe", []interface{}{arg1, arg2})
+	fake.rateRecipeMutex.Unlock()
+	if fake.RateRecipeStub != nil {
+		fake.RateRecipeStub(arg1, arg2)
+	}
+}
+
+func (fake *FakeRecipeHandler) RateRecipeCallCount() int {
+	fake.rateRecipeMutex.RLock()
+	defer fake.rateRecipeMutex.RUnlock()
+	return len(fake.rateRecipeArgsForCall)
+}
+
+func (fake *FakeRecipeHandler) RateRecipeCalls(stub func(http.ResponseWriter, *http.Request)) {
+	fake.rateRecipeMutex.Lock()
+	defer fake.rateRecipeMutex.Unlock()
+	fake.RateRecipeStub = stub
+}
+
+func (fake *FakeRecipeHandler) RateRecipeArgsForCall(i int) (http.ResponseWriter, *http.Request) {
+	fake.rateRecipeMutex.RLock()
+	defer fake.rateRecipeMutex.RUnlock()
+	argsForCall := fake.rateRecipeArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
 func (fake *FakeRecipeHandler) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.getRecipeMutex.RLock()
+	defer fake.getRecipeMutex.RUnlock()
 	fake.getRecipesMutex.RLock()
 	defer fake.getRecipesMutex.RUnlock()
+	fake.mergeRecipesMutex.RLock()
+	defer fake.mergeRecipesMutex.RUnlock()
 	fake.newRecipeMutex.RLock()
 	defer fake.newRecipeMutex.RUnlock()
+	fake.rateRecipeMutex.RLock()
+	defer fake.rateRecipeMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
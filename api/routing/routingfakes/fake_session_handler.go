@@ -0,0 +1,158 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package routingfakes
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/kieron-pivotal/menu-planner-app/routing"
+)
+
+type FakeSessionHandler struct {
+	ListSessionsStub        func(http.ResponseWriter, *http.Request)
+	listSessionsMutex       sync.RWMutex
+	listSessionsArgsForCall []struct {
+		arg1 http.ResponseWriter
+		arg2 *http.Request
+	}
+	RevokeAllSessionsStub        func(http.ResponseWriter, *http.Request)
+	revokeAllSessionsMutex       sync.RWMutex
+	revokeAllSessionsArgsForCall []struct {
+		arg1 http.ResponseWriter
+		arg2 *http.Request
+	}
+	RevokeSessionStub        func(http.ResponseWriter, *http.Request)
+	revokeSessionMutex       sync.RWMutex
+	revokeSessionArgsForCall []struct {
+		arg1 http.ResponseWriter
+		arg2 *http.Request
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeSessionHandler) ListSessions(arg1 http.ResponseWriter, arg2 *http.Request) {
+	fake.listSessionsMutex.Lock()
+	fake.listSessionsArgsForCall = append(fake.listSessionsArgsForCall, struct {
+		arg1 http.ResponseWriter
+		arg2 *http.Request
+	}{arg1, arg2})
+	fake.recordInvocation("ListSessions", []interface{}{arg1, arg2})
+	fake.listSessionsMutex.Unlock()
+	if fake.ListSessionsStub != nil {
+		fake.ListSessionsStub(arg1, arg2)
+	}
+}
+
+func (fake *FakeSessionHandler) ListSessionsCallCount() int {
+	fake.listSessionsMutex.RLock()
+	defer fake.listSessionsMutex.RUnlock()
+	return len(fake.listSessionsArgsForCall)
+}
+
+func (fake *FakeSessionHandler) ListSessionsCalls(stub func(http.ResponseWriter, *http.Request)) {
+	fake.listSessionsMutex.Lock()
+	defer fake.listSessionsMutex.Unlock()
+	fake.ListSessionsStub = stub
+}
+
+func (fake *FakeSessionHandler) ListSessionsArgsForCall(i int) (http.ResponseWriter, *http.Request) {
+	fake.listSessionsMutex.RLock()
+	defer fake.listSessionsMutex.RUnlock()
+	argsForCall := fake.listSessionsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeSessionHandler) RevokeAllSessions(arg1 http.ResponseWriter, arg2 *http.Request) {
+	fake.revokeAllSessionsMutex.Lock()
+	fake.revokeAllSessionsArgsForCall = append(fake.revokeAllSessionsArgsForCall, struct {
+		arg1 http.ResponseWriter
+		arg2 *http.Request
+	}{arg1, arg2})
+	fake.recordInvocation("RevokeAllSessions", []interface{}{arg1, arg2})
+	fake.revokeAllSessionsMutex.Unlock()
+	if fake.RevokeAllSessionsStub != nil {
+		fake.RevokeAllSessionsStub(arg1, arg2)
+	}
+}
+
+func (fake *FakeSessionHandler) RevokeAllSessionsCallCount() int {
+	fake.revokeAllSessionsMutex.RLock()
+	defer fake.revokeAllSessionsMutex.RUnlock()
+	return len(fake.revokeAllSessionsArgsForCall)
+}
+
+func (fake *FakeSessionHandler) RevokeAllSessionsCalls(stub func(http.ResponseWriter, *http.Request)) {
+	fake.revokeAllSessionsMutex.Lock()
+	defer fake.revokeAllSessionsMutex.Unlock()
+	fake.RevokeAllSessionsStub = stub
+}
+
+func (fake *FakeSessionHandler) RevokeAllSessionsArgsForCall(i int) (http.ResponseWriter, *http.Request) {
+	fake.revokeAllSessionsMutex.RLock()
+	defer fake.revokeAllSessionsMutex.RUnlock()
+	argsForCall := fake.revokeAllSessionsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeSessionHandler) RevokeSession(arg1 http.ResponseWriter, arg2 *http.Request) {
+	fake.revokeSessionMutex.Lock()
+	fake.revokeSessionArgsForCall = append(fake.revokeSessionArgsForCall, struct {
+		arg1 http.ResponseWriter
+		arg2 *http.Request
+	}{arg1, arg2})
+	fake.recordInvocation("RevokeSession", []interface{}{arg1, arg2})
+	fake.revokeSessionMutex.Unlock()
+	if fake.RevokeSessionStub != nil {
+		fake.RevokeSessionStub(arg1, arg2)
+	}
+}
+
+func (fake *FakeSessionHandler) RevokeSessionCallCount() int {
+	fake.revokeSessionMutex.RLock()
+	defer fake.revokeSessionMutex.RUnlock()
+	return len(fake.revokeSessionArgsForCall)
+}
+
+func (fake *FakeSessionHandler) RevokeSessionCalls(stub func(http.ResponseWriter, *http.Request)) {
+	fake.revokeSessionMutex.Lock()
+	defer fake.revokeSessionMutex.Unlock()
+	fake.RevokeSessionStub = stub
+}
+
+func (fake *FakeSessionHandler) RevokeSessionArgsForCall(i int) (http.ResponseWriter, *http.Request) {
+	fake.revokeSessionMutex.RLock()
+	defer fake.revokeSessionMutex.RUnlock()
+	argsForCall := fake.revokeSessionArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeSessionHandler) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.listSessionsMutex.RLock()
+	defer fake.listSessionsMutex.RUnlock()
+	fake.revokeAllSessionsMutex.RLock()
+	defer fake.revokeAllSessionsMutex.RUnlock()
+	fake.revokeSessionMutex.RLock()
+	defer fake.revokeSessionMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeSessionHandler) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ routing.SessionHandler = new(FakeSessionHandler)
@@ -0,0 +1,118 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package routingfakes
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/kieron-pivotal/menu-planner-app/routing"
+)
+
+type FakeShoppingHandler struct {
+	PlanShoppingListStub        func(http.ResponseWriter, *http.Request)
+	planShoppingListMutex       sync.RWMutex
+	planShoppingListArgsForCall []struct {
+		arg1 http.ResponseWriter
+		arg2 *http.Request
+	}
+	ShoppingListStub        func(http.ResponseWriter, *http.Request)
+	shoppingListMutex       sync.RWMutex
+	shoppingListArgsForCall []struct {
+		arg1 http.ResponseWriter
+		arg2 *http.Request
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeShoppingHandler) PlanShoppingList(arg1 http.ResponseWriter, arg2 *http.Request) {
+	fake.planShoppingListMutex.Lock()
+	fake.planShoppingListArgsForCall = append(fake.planShoppingListArgsForCall, struct {
+		arg1 http.ResponseWriter
+		arg2 *http.Request
+	}{arg1, arg2})
+	fake.recordInvocation("PlanShoppingList", []interface{}{arg1, arg2})
+	fake.planShoppingListMutex.Unlock()
+	if fake.PlanShoppingListStub != nil {
+		fake.PlanShoppingListStub(arg1, arg2)
+	}
+}
+
+func (fake *FakeShoppingHandler) PlanShoppingListCallCount() int {
+	fake.planShoppingListMutex.RLock()
+	defer fake.planShoppingListMutex.RUnlock()
+	return len(fake.planShoppingListArgsForCall)
+}
+
+func (fake *FakeShoppingHandler) PlanShoppingListCalls(stub func(http.ResponseWriter, *http.Request)) {
+	fake.planShoppingListMutex.Lock()
+	defer fake.planShoppingListMutex.Unlock()
+	fake.PlanShoppingListStub = stub
+}
+
+func (fake *FakeShoppingHandler) PlanShoppingListArgsForCall(i int) (http.ResponseWriter, *http.Request) {
+	fake.planShoppingListMutex.RLock()
+	defer fake.planShoppingListMutex.RUnlock()
+	argsForCall := fake.planShoppingListArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeShoppingHandler) ShoppingList(arg1 http.ResponseWriter, arg2 *http.Request) {
+	fake.shoppingListMutex.Lock()
+	fake.shoppingListArgsForCall = append(fake.shoppingListArgsForCall, struct {
+		arg1 http.ResponseWriter
+		arg2 *http.Request
+	}{arg1, arg2})
+	fake.recordInvocation("ShoppingList", []interface{}{arg1, arg2})
+	fake.shoppingListMutex.Unlock()
+	if fake.ShoppingListStub != nil {
+		fake.ShoppingListStub(arg1, arg2)
+	}
+}
+
+func (fake *FakeShoppingHandler) ShoppingListCallCount() int {
+	fake.shoppingListMutex.RLock()
+	defer fake.shoppingListMutex.RUnlock()
+	return len(fake.shoppingListArgsForCall)
+}
+
+func (fake *FakeShoppingHandler) ShoppingListCalls(stub func(http.ResponseWriter, *http.Request)) {
+	fake.shoppingListMutex.Lock()
+	defer fake.shoppingListMutex.Unlock()
+	fake.ShoppingListStub = stub
+}
+
+func (fake *FakeShoppingHandler) ShoppingListArgsForCall(i int) (http.ResponseWriter, *http.Request) {
+	fake.shoppingListMutex.RLock()
+	defer fake.shoppingListMutex.RUnlock()
+	argsForCall := fake.shoppingListArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeShoppingHandler) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.planShoppingListMutex.RLock()
+	defer fake.planShoppingListMutex.RUnlock()
+	fake.shoppingListMutex.RLock()
+	defer fake.shoppingListMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeShoppingHandler) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ routing.ShoppingHandler = new(FakeShoppingHandler)
@@ -9,8 +9,8 @@ if ! psql "$DB_INTEGRATION_CONN_STR" -l &>/dev/null; then
     "$DB_SCRIPTS_DIR/create-test-db.sh" "$DB_INTEGRATION_NAME" "$DB_INTEGRATION_USER" "$DB_INTEGRATION_PASSWORD"
 fi
 
-flyway -user="$DB_INTEGRATION_USER" -password="$DB_INTEGRATION_PASSWORD" -url="jdbc:postgresql://localhost/$DB_INTEGRATION_NAME" -locations="filesystem:$DB_SCRIPTS_DIR/../migrations" migrate
-
 export DB_CONN_STR="$DB_INTEGRATION_CONN_STR"
+# the web app's google client id, unless another is given
+export GOOGLE_AUDIENCE="${GOOGLE_AUDIENCE:-176462381984-bfq3v9mc00v0ipvpebiaiide4l22dmoh.apps.googleusercontent.com}"
 
-go run $DIR/../main.go
+go run "$DIR/.."
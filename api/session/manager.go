@@ -3,40 +3,69 @@ package session
 
 import (
 	"context"
+	"crypto/rand"
 	"encoding/gob"
+	"encoding/hex"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
+	"time"
 
 	"github.com/gorilla/sessions"
+	"github.com/kieron-pivotal/menu-planner-app/models"
+	"github.com/kieron-pivotal/menu-planner-app/problem"
 )
 
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -generate
+
 type sessionKey int
 
 const (
 	ctxSessionKey     sessionKey = 0
 	sessionCookieName            = "_id"
 	authInfoKey                  = "authInfo"
+	maxAge                       = 15 * time.Minute
+	// touchInterval is how stale a session's last seen time may get before
+	// a request records it again
+	touchInterval = time.Minute
 )
 
+//counterfeiter:generate . Tracker
+
+// Tracker keeps a server-side record of every logged-in session, so that
+// sessions can be listed and revoked independently of their cookies
+type Tracker interface {
+	IsNotFoundErr(error) bool
+	Create(ctx context.Context, sess models.Session) error
+	Touch(ctx context.Context, id string, lastSeen, seenSince, writeBefore time.Time) (bool, error)
+	List(ctx context.Context, userID int, seenSince time.Time) ([]models.Session, error)
+	Delete(ctx context.Context, userID int, id string) error
+	DeleteIdle(ctx context.Context, userID int, lastSeenBefore time.Time) error
+	DeleteAll(ctx context.Context, userID int) error
+}
+
 type Manager struct {
 	sessionStore sessions.Store
+	tracker      Tracker
 }
 
 type AuthInfo struct {
 	Name       string
 	ID         int
 	IsLoggedIn bool
+	SessionID  string
 }
 
 func init() {
 	gob.Register(&AuthInfo{})
 }
 
-func NewManager(sessionStoreKeys [][]byte) *Manager {
+func NewManager(sessionStoreKeys [][]byte, tracker Tracker) *Manager {
 	store := createSessionStore(sessionStoreKeys)
 	return &Manager{
 		sessionStore: store,
+		tracker:      tracker,
 	}
 }
 
@@ -49,7 +78,7 @@ func (m *Manager) SessionMiddleware(next http.Handler) http.Handler {
 				MaxAge: -1,
 			}
 			http.SetCookie(w, &delCookie)
-			w.WriteHeader(http.StatusBadRequest)
+			problem.Write(w, http.StatusBadRequest, problem.InvalidSession, "")
 			return
 		}
 
@@ -59,9 +88,23 @@ func (m *Manager) SessionMiddleware(next http.Handler) http.Handler {
 			var ok bool
 			if ourSession, ok = val.(*AuthInfo); !ok {
 				log.Printf("not a *AuthInfo, actually a %T", val)
-				w.WriteHeader(http.StatusBadRequest)
+				problem.Write(w, http.StatusBadRequest, problem.InvalidSession, "")
 				return
 			}
+
+			if ourSession.IsLoggedIn {
+				active, err := m.IsActive(r.Context(), ourSession)
+				if err != nil {
+					log.Printf("session-middleware: %v\n", err)
+					problem.Write(w, http.StatusInternalServerError, problem.Internal, "")
+					return
+				}
+
+				if !active {
+					ourSession = &AuthInfo{}
+					session.Values[authInfoKey] = ourSession
+				}
+			}
 			r = r.Clone(context.WithValue(r.Context(), ctxSessionKey, ourSession))
 
 			session.Save(r, w)
@@ -75,6 +118,11 @@ func (m *Manager) Set(r *http.Request, w http.ResponseWriter, authInfo *AuthInfo
 	if err != nil {
 		return fmt.Errorf("session-set: failed to get session %w", err)
 	}
+
+	previous, _ := session.Values[authInfoKey].(*AuthInfo)
+	if err = m.track(r, previous, authInfo); err != nil {
+		return fmt.Errorf("session-set: failed to track session %w", err)
+	}
 	session.Values[authInfoKey] = authInfo
 
 	if err = session.Save(r, w); err != nil {
@@ -97,10 +145,112 @@ func (m *Manager) Get(ctx context.Context) (*AuthInfo, error) {
 	return authInfo, nil
 }
 
+// List returns the user's sessions which have not yet expired
+func (m *Manager) List(ctx context.Context, userID int) ([]models.Session, error) {
+	return m.tracker.List(ctx, userID, time.Now().Add(-maxAge))
+}
+
+// Revoke logs out a single session of the user. The session's cookie is
+// rejected from its next request onwards.
+func (m *Manager) Revoke(ctx context.Context, userID int, id string) error {
+	return m.tracker.Delete(ctx, userID, id)
+}
+
+// RevokeAll logs the user out everywhere, including the current session
+func (m *Manager) RevokeAll(ctx context.Context, userID int) error {
+	return m.tracker.DeleteAll(ctx, userID)
+}
+
+func (m *Manager) IsNotFoundErr(err error) bool {
+	return m.tracker.IsNotFoundErr(err)
+}
+
+// track keeps the server-side record of a session in step with the auth
+// info being saved over previous, the session's auth info so far, if any.
+// Logging in again replaces the session's record with a new one.
+func (m *Manager) track(r *http.Request, previous, authInfo *AuthInfo) error {
+	switch {
+	case authInfo.IsLoggedIn && authInfo.SessionID == "":
+		id, err := newSessionID()
+		if err != nil {
+			return err
+		}
+
+		if previous != nil && previous.SessionID != "" {
+			if err = m.untrack(r.Context(), previous); err != nil {
+				return err
+			}
+		}
+
+		now := time.Now()
+		if err = m.tracker.DeleteIdle(r.Context(), authInfo.ID, now.Add(-maxAge)); err != nil {
+			return err
+		}
+
+		err = m.tracker.Create(r.Context(), models.Session{
+			ID:         id,
+			UserID:     authInfo.ID,
+			UserAgent:  r.UserAgent(),
+			IP:         clientIP(r),
+			CreatedAt:  now,
+			LastSeenAt: now,
+		})
+		if err != nil {
+			return err
+		}
+		authInfo.SessionID = id
+
+	case !authInfo.IsLoggedIn && authInfo.SessionID != "":
+		if err := m.untrack(r.Context(), authInfo); err != nil {
+			return err
+		}
+		authInfo.SessionID = ""
+	}
+
+	return nil
+}
+
+// untrack deletes the record of a session, which may already be gone
+func (m *Manager) untrack(ctx context.Context, authInfo *AuthInfo) error {
+	err := m.tracker.Delete(ctx, authInfo.ID, authInfo.SessionID)
+	if err != nil && !m.tracker.IsNotFoundErr(err) {
+		return err
+	}
+	return nil
+}
+
+// IsActive reports whether a logged-in session is still known server-side
+// and hasn't expired, recording the activity if it is. Sessions created
+// before tracking existed have no ID and are treated as revoked.
+func (m *Manager) IsActive(ctx context.Context, authInfo *AuthInfo) (bool, error) {
+	if authInfo.SessionID == "" {
+		return false, nil
+	}
+
+	now := time.Now()
+	return m.tracker.Touch(ctx, authInfo.SessionID, now, now.Add(-maxAge), now.Add(-touchInterval))
+}
+
+func newSessionID() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate session id %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
 func createSessionStore(sessionStoreKeys [][]byte) sessions.Store {
 	store := sessions.NewFilesystemStore("", sessionStoreKeys...)
 	store.Options.Path = "/"
 	store.Options.HttpOnly = true
-	store.Options.MaxAge = 60 * 15
+	store.Options.MaxAge = int(maxAge.Seconds())
 	return store
 }
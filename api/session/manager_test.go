@@ -1,11 +1,16 @@
 package session_test
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"time"
 
 	"github.com/gorilla/securecookie"
+	"github.com/kieron-pivotal/menu-planner-app/models"
 	"github.com/kieron-pivotal/menu-planner-app/session"
+	"github.com/kieron-pivotal/menu-planner-app/session/sessionfakes"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 )
@@ -13,6 +18,7 @@ import (
 var _ = Describe("Session", func() {
 	var (
 		sessionManager    *session.Manager
+		tracker           *sessionfakes.FakeTracker
 		sessionKeys       [][]byte
 		middleware        http.Handler
 		next              http.Handler
@@ -26,7 +32,9 @@ var _ = Describe("Session", func() {
 	BeforeEach(func() {
 		lambda = func(w http.ResponseWriter, r *http.Request) {}
 		sessionKeys = [][]byte{securecookie.GenerateRandomKey(64), securecookie.GenerateRandomKey(32)}
-		sessionManager = session.NewManager(sessionKeys)
+		tracker = new(sessionfakes.FakeTracker)
+		tracker.TouchReturns(true, nil)
+		sessionManager = session.NewManager(sessionKeys, tracker)
 		req, err = http.NewRequest(http.MethodGet, "", nil)
 		Expect(err).NotTo(HaveOccurred())
 		resp = httptest.NewRecorder()
@@ -135,6 +143,158 @@ var _ = Describe("Session", func() {
 				Expect(sess.IsLoggedIn).To(BeTrue())
 				Expect(sess.ID).To(Equal(10))
 			})
+
+			It("records the activity against the tracked session", func() {
+				cookies := resp.Result().Cookies()
+				req, err = http.NewRequest(http.MethodGet, "", nil)
+				Expect(err).NotTo(HaveOccurred())
+				req.AddCookie(cookies[0])
+
+				middleware = sessionManager.SessionMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+				middleware.ServeHTTP(httptest.NewRecorder(), req)
+
+				Expect(tracker.TouchCallCount()).To(Equal(1))
+				_, id, lastSeen, seenSince, writeBefore := tracker.TouchArgsForCall(0)
+				Expect(id).To(Equal(ourSession.SessionID))
+				Expect(lastSeen).To(BeTemporally("~", time.Now(), time.Second))
+				Expect(seenSince).To(BeTemporally("~", time.Now().Add(-15*time.Minute), time.Second))
+				Expect(writeBefore).To(BeTemporally("~", time.Now().Add(-time.Minute), time.Second))
+			})
+
+			When("the session has been revoked", func() {
+				BeforeEach(func() {
+					tracker.TouchReturns(false, nil)
+				})
+
+				It("treats the request as logged out", func() {
+					cookies := resp.Result().Cookies()
+					req, err = http.NewRequest(http.MethodGet, "", nil)
+					Expect(err).NotTo(HaveOccurred())
+					req.AddCookie(cookies[0])
+
+					var sess *session.AuthInfo
+					middleware = sessionManager.SessionMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+						sess, err = sessionManager.Get(r.Context())
+						Expect(err).NotTo(HaveOccurred())
+					}))
+					middleware.ServeHTTP(httptest.NewRecorder(), req)
+
+					Expect(sess.IsLoggedIn).To(BeFalse())
+				})
+			})
+
+			When("checking the tracked session fails", func() {
+				BeforeEach(func() {
+					tracker.TouchReturns(false, errors.New("oops"))
+				})
+
+				It("fails with internal server error", func() {
+					cookies := resp.Result().Cookies()
+					req, err = http.NewRequest(http.MethodGet, "", nil)
+					Expect(err).NotTo(HaveOccurred())
+					req.AddCookie(cookies[0])
+
+					resp = httptest.NewRecorder()
+					middleware = sessionManager.SessionMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+					middleware.ServeHTTP(resp, req)
+
+					Expect(resp.Result().StatusCode).To(Equal(http.StatusInternalServerError))
+				})
+			})
+		})
+
+		It("tracks the new logged-in session", func() {
+			Expect(tracker.CreateCallCount()).To(Equal(1))
+			_, tracked := tracker.CreateArgsForCall(0)
+			Expect(tracked.ID).To(HaveLen(64))
+			Expect(tracked.UserID).To(Equal(10))
+			Expect(ourSession.SessionID).To(Equal(tracked.ID))
+		})
+
+		It("prunes the user's idle sessions", func() {
+			Expect(tracker.DeleteIdleCallCount()).To(Equal(1))
+			_, userID, _ := tracker.DeleteIdleArgsForCall(0)
+			Expect(userID).To(Equal(10))
+		})
+
+		When("tracking the session fails", func() {
+			BeforeEach(func() {
+				tracker.CreateReturns(errors.New("oops"))
+			})
+
+			It("returns an error", func() {
+				Expect(setErr).To(MatchError(ContainSubstring("failed to track session")))
+			})
+		})
+
+		When("a session which is already logged in logs in again", func() {
+			It("stops tracking the old session and tracks a new one", func() {
+				firstID := ourSession.SessionID
+				cookies := resp.Result().Cookies()
+				req, err = http.NewRequest(http.MethodGet, "", nil)
+				Expect(err).NotTo(HaveOccurred())
+				req.AddCookie(cookies[0])
+
+				again := &session.AuthInfo{Name: "bob", ID: 10, IsLoggedIn: true}
+				middleware = sessionManager.SessionMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					Expect(sessionManager.Set(r, w, again)).To(Succeed())
+				}))
+				middleware.ServeHTTP(httptest.NewRecorder(), req)
+
+				Expect(tracker.DeleteCallCount()).To(Equal(1))
+				_, userID, id := tracker.DeleteArgsForCall(0)
+				Expect(userID).To(Equal(10))
+				Expect(id).To(Equal(firstID))
+
+				Expect(tracker.CreateCallCount()).To(Equal(2))
+				Expect(again.SessionID).NotTo(Equal(firstID))
+			})
+		})
+
+		When("a tracked session is logged out", func() {
+			BeforeEach(func() {
+				ourSession = &session.AuthInfo{
+					Name:      "bob",
+					ID:        10,
+					SessionID: "some-session-id",
+				}
+			})
+
+			It("stops tracking it", func() {
+				Expect(setErr).NotTo(HaveOccurred())
+				Expect(tracker.DeleteCallCount()).To(Equal(1))
+				_, userID, id := tracker.DeleteArgsForCall(0)
+				Expect(userID).To(Equal(10))
+				Expect(id).To(Equal("some-session-id"))
+				Expect(ourSession.SessionID).To(BeEmpty())
+			})
+		})
+	})
+
+	Context("listing and revoking", func() {
+		It("lists only sessions seen within the session lifetime", func() {
+			tracker.ListReturns([]models.Session{{ID: "abc"}}, nil)
+
+			sessions, err := sessionManager.List(context.Background(), 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sessions).To(ConsistOf(models.Session{ID: "abc"}))
+
+			_, userID, since := tracker.ListArgsForCall(0)
+			Expect(userID).To(Equal(10))
+			Expect(since).To(BeTemporally("~", time.Now().Add(-15*time.Minute), time.Second))
+		})
+
+		It("revokes a single session", func() {
+			Expect(sessionManager.Revoke(context.Background(), 10, "abc")).To(Succeed())
+			_, userID, id := tracker.DeleteArgsForCall(0)
+			Expect(userID).To(Equal(10))
+			Expect(id).To(Equal("abc"))
+		})
+
+		It("revokes all sessions", func() {
+			Expect(sessionManager.RevokeAll(context.Background(), 10)).To(Succeed())
+			_, userID := tracker.DeleteAllArgsForCall(0)
+			Expect(userID).To(Equal(10))
 		})
 	})
 })
@@ -0,0 +1,585 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package sessionfakes
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/kieron-pivotal/menu-planner-app/models"
+	"github.com/kieron-pivotal/menu-planner-app/session"
+)
+
+type FakeTracker struct {
+	CreateStub        func(context.Context, models.Session) error
+	createMutex       sync.RWMutex
+	createArgsForCall []struct {
+		arg1 context.Context
+		arg2 models.Session
+	}
+	createReturns struct {
+		result1 error
+	}
+	createReturnsOnCall map[int]struct {
+		result1 error
+	}
+	DeleteStub        func(context.Context, int, string) error
+	deleteMutex       sync.RWMutex
+	deleteArgsForCall []struct {
+		arg1 context.Context
+		arg2 int
+		arg3 string
+	}
+	deleteReturns struct {
+		result1 error
+	}
+	deleteReturnsOnCall map[int]struct {
+		result1 error
+	}
+	DeleteAllStub        func(context.Context, int) error
+	deleteAllMutex       sync.RWMutex
+	deleteAllArgsForCall []struct {
+		arg1 context.Context
+		arg2 int
+	}
+	deleteAllReturns struct {
+		result1 error
+	}
+	deleteAllReturnsOnCall map[int]struct {
+		result1 error
+	}
+	DeleteIdleStub        func(context.Context, int, time.Time) error
+	deleteIdleMutex       sync.RWMutex
+	deleteIdleArgsForCall []struct {
+		arg1 context.Context
+		arg2 int
+		arg3 time.Time
+	}
+	deleteIdleReturns struct {
+		result1 error
+	}
+	deleteIdleReturnsOnCall map[int]struct {
+		result1 error
+	}
+	IsNotFoundErrStub        func(error) bool
+	isNotFoundErrMutex       sync.RWMutex
+	isNotFoundErrArgsForCall []struct {
+		arg1 error
+	}
+	isNotFoundErrReturns struct {
+		result1 bool
+	}
+	isNotFoundErrReturnsOnCall map[int]struct {
+		result1 bool
+	}
+	ListStub        func(context.Context, int, time.Time) ([]models.Session, error)
+	listMutex       sync.RWMutex
+	listArgsForCall []struct {
+		arg1 context.Context
+		arg2 int
+		arg3 time.Time
+	}
+	listReturns struct {
+		result1 []models.Session
+		result2 error
+	}
+	listReturnsOnCall map[int]struct {
+		result1 []models.Session
+		result2 error
+	}
+	TouchStub        func(context.Context, string, time.Time, time.Time, time.Time) (bool, error)
+	touchMutex       sync.RWMutex
+	touchArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 time.Time
+		arg4 time.Time
+		arg5 time.Time
+	}
+	touchReturns struct {
+		result1 bool
+		result2 error
+	}
+	touchReturnsOnCall map[int]struct {
+		result1 bool
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeTracker) Create(arg1 context.Context, arg2 models.Session) error {
+	fake.createMutex.Lock()
+	ret, specificReturn := fake.createReturnsOnCall[len(fake.createArgsForCall)]
+	fake.createArgsForCall = append(fake.createArgsForCall, struct {
+		arg1 context.Context
+		arg2 models.Session
+	}{arg1, arg2})
+	fake.recordInvocation("Create", []interface{}{arg1, arg2})
+	fake.createMutex.Unlock()
+	if fake.CreateStub != nil {
+		return fake.CreateStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.createReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeTracker) CreateCallCount() int {
+	fake.createMutex.RLock()
+	defer fake.createMutex.RUnlock()
+	return len(fake.createArgsForCall)
+}
+
+func (fake *FakeTracker) CreateCalls(stub func(context.Context, models.Session) error) {
+	fake.createMutex.Lock()
+	defer fake.createMutex.Unlock()
+	fake.CreateStub = stub
+}
+
+func (fake *FakeTracker) CreateArgsForCall(i int) (context.Context, models.Session) {
+	fake.createMutex.RLock()
+	defer fake.createMutex.RUnlock()
+	argsForCall := fake.createArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeTracker) CreateReturns(result1 error) {
+	fake.createMutex.Lock()
+	defer fake.createMutex.Unlock()
+	fake.CreateStub = nil
+	fake.createReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeTracker) CreateReturnsOnCall(i int, result1 error) {
+	fake.createMutex.Lock()
+	defer fake.createMutex.Unlock()
+	fake.CreateStub = nil
+	if fake.createReturnsOnCall == nil {
+		fake.createReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.createReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeTracker) Delete(arg1 context.Context, arg2 int, arg3 string) error {
+	fake.deleteMutex.Lock()
+	ret, specificReturn := fake.deleteReturnsOnCall[len(fake.deleteArgsForCall)]
+	fake.deleteArgsForCall = append(fake.deleteArgsForCall, struct {
+		arg1 context.Context
+		arg2 int
+		arg3 string
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("Delete", []interface{}{arg1, arg2, arg3})
+	fake.deleteMutex.Unlock()
+	if fake.DeleteStub != nil {
+		return fake.DeleteStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.deleteReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeTracker) DeleteCallCount() int {
+	fake.deleteMutex.RLock()
+	defer fake.deleteMutex.RUnlock()
+	return len(fake.deleteArgsForCall)
+}
+
+func (fake *FakeTracker) DeleteCalls(stub func(context.Context, int, string) error) {
+	fake.deleteMutex.Lock()
+	defer fake.deleteMutex.Unlock()
+	fake.DeleteStub = stub
+}
+
+func (fake *FakeTracker) DeleteArgsForCall(i int) (context.Context, int, string) {
+	fake.deleteMutex.RLock()
+	defer fake.deleteMutex.RUnlock()
+	argsForCall := fake.deleteArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeTracker) DeleteReturns(result1 error) {
+	fake.deleteMutex.Lock()
+	defer fake.deleteMutex.Unlock()
+	fake.DeleteStub = nil
+	fake.deleteReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeTracker) DeleteReturnsOnCall(i int, result1 error) {
+	fake.deleteMutex.Lock()
+	defer fake.deleteMutex.Unlock()
+	fake.DeleteStub = nil
+	if fake.deleteReturnsOnCall == nil {
+		fake.deleteReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeTracker) DeleteAll(arg1 context.Context, arg2 int) error {
+	fake.deleteAllMutex.Lock()
+	ret, specificReturn := fake.deleteAllReturnsOnCall[len(fake.deleteAllArgsForCall)]
+	fake.deleteAllArgsForCall = append(fake.deleteAllArgsForCall, struct {
+		arg1 context.Context
+		arg2 int
+	}{arg1, arg2})
+	fake.recordInvocation("DeleteAll", []interface{}{arg1, arg2})
+	fake.deleteAllMutex.Unlock()
+	if fake.DeleteAllStub != nil {
+		return fake.DeleteAllStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.deleteAllReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeTracker) DeleteAllCallCount() int {
+	fake.deleteAllMutex.RLock()
+	defer fake.deleteAllMutex.RUnlock()
+	return len(fake.deleteAllArgsForCall)
+}
+
+func (fake *FakeTracker) DeleteAllCalls(stub func(context.Context, int) error) {
+	fake.deleteAllMutex.Lock()
+	defer fake.deleteAllMutex.Unlock()
+	fake.DeleteAllStub = stub
+}
+
+func (fake *FakeTracker) DeleteAllArgsForCall(i int) (context.Context, int) {
+	fake.deleteAllMutex.RLock()
+	defer fake.deleteAllMutex.RUnlock()
+	argsForCall := fake.deleteAllArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeTracker) DeleteAllReturns(result1 error) {
+	fake.deleteAllMutex.Lock()
+	defer fake.deleteAllMutex.Unlock()
+	fake.DeleteAllStub = nil
+	fake.deleteAllReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeTracker) DeleteAllReturnsOnCall(i int, result1 error) {
+	fake.deleteAllMutex.Lock()
+	defer fake.deleteAllMutex.Unlock()
+	fake.DeleteAllStub = nil
+	if fake.deleteAllReturnsOnCall == nil {
+		fake.deleteAllReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteAllReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeTracker) DeleteIdle(arg1 context.Context, arg2 int, arg3 time.Time) error {
+	fake.deleteIdleMutex.Lock()
+	ret, specificReturn := fake.deleteIdleReturnsOnCall[len(fake.deleteIdleArgsForCall)]
+	fake.deleteIdleArgsForCall = append(fake.deleteIdleArgsForCall, struct {
+		arg1 context.Context
+		arg2 int
+		arg3 time.Time
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("DeleteIdle", []interface{}{arg1, arg2, arg3})
+	fake.deleteIdleMutex.Unlock()
+	if fake.DeleteIdleStub != nil {
+		return fake.DeleteIdleStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.deleteIdleReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeTracker) DeleteIdleCallCount() int {
+	fake.deleteIdleMutex.RLock()
+	defer fake.deleteIdleMutex.RUnlock()
+	return len(fake.deleteIdleArgsForCall)
+}
+
+func (fake *FakeTracker) DeleteIdleCalls(stub func(context.Context, int, time.Time) error) {
+	fake.deleteIdleMutex.Lock()
+	defer fake.deleteIdleMutex.Unlock()
+	fake.DeleteIdleStub = stub
+}
+
+func (fake *FakeTracker) DeleteIdleArgsForCall(i int) (context.Context, int, time.Time) {
+	fake.deleteIdleMutex.RLock()
+	defer fake.deleteIdleMutex.RUnlock()
+	argsForCall := fake.deleteIdleArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeTracker) DeleteIdleReturns(result1 error) {
+	fake.deleteIdleMutex.Lock()
+	defer fake.deleteIdleMutex.Unlock()
+	fake.DeleteIdleStub = nil
+	fake.deleteIdleReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeTracker) DeleteIdleReturnsOnCall(i int, result1 error) {
+	fake.deleteIdleMutex.Lock()
+	defer fake.deleteIdleMutex.Unlock()
+	fake.DeleteIdleStub = nil
+	if fake.deleteIdleReturnsOnCall == nil {
+		fake.deleteIdleReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteIdleReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeTracker) IsNotFoundErr(arg1 error) bool {
+	fake.isNotFoundErrMutex.Lock()
+	ret, specificReturn := fake.isNotFoundErrReturnsOnCall[len(fake.isNotFoundErrArgsForCall)]
+	fake.isNotFoundErrArgsForCall = append(fake.isNotFoundErrArgsForCall, struct {
+		arg1 error
+	}{arg1})
+	fake.recordInvocation("IsNotFoundErr", []interface{}{arg1})
+	fake.isNotFoundErrMutex.Unlock()
+	if fake.IsNotFoundErrStub != nil {
+		return fake.IsNotFoundErrStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.isNotFoundErrReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeTracker) IsNotFoundErrCallCount() int {
+	fake.isNotFoundErrMutex.RLock()
+	defer fake.isNotFoundErrMutex.RUnlock()
+	return len(fake.isNotFoundErrArgsForCall)
+}
+
+func (fake *FakeTracker) IsNotFoundErrCalls(stub func(error) bool) {
+	fake.isNotFoundErrMutex.Lock()
+	defer fake.isNotFoundErrMutex.Unlock()
+	fake.IsNotFoundErrStub = stub
+}
+
+func (fake *FakeTracker) IsNotFoundErrArgsForCall(i int) error {
+	fake.isNotFoundErrMutex.RLock()
+	defer fake.isNotFoundErrMutex.RUnlock()
+	argsForCall := fake.isNotFoundErrArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeTracker) IsNotFoundErrReturns(result1 bool) {
+	fake.isNotFoundErrMutex.Lock()
+	defer fake.isNotFoundErrMutex.Unlock()
+	fake.IsNotFoundErrStub = nil
+	fake.isNotFoundErrReturns = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *FakeTracker) IsNotFoundErrReturnsOnCall(i int, result1 bool) {
+	fake.isNotFoundErrMutex.Lock()
+	defer fake.isNotFoundErrMutex.Unlock()
+	fake.IsNotFoundErrStub = nil
+	if fake.isNotFoundErrReturnsOnCall == nil {
+		fake.isNotFoundErrReturnsOnCall = make(map[int]struct {
+			result1 bool
+		})
+	}
+	fake.isNotFoundErrReturnsOnCall[i] = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *FakeTracker) List(arg1 context.Context, arg2 int, arg3 time.Time) ([]models.Session, error) {
+	fake.listMutex.Lock()
+	ret, specificReturn := fake.listReturnsOnCall[len(fake.listArgsForCall)]
+	fake.listArgsForCall = append(fake.listArgsForCall, struct {
+		arg1 context.Context
+		arg2 int
+		arg3 time.Time
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("List", []interface{}{arg1, arg2, arg3})
+	fake.listMutex.Unlock()
+	if fake.ListStub != nil {
+		return fake.ListStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.listReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeTracker) ListCallCount() int {
+	fake.listMutex.RLock()
+	defer fake.listMutex.RUnlock()
+	return len(fake.listArgsForCall)
+}
+
+func (fake *FakeTracker) ListCalls(stub func(context.Context, int, time.Time) ([]models.Session, error)) {
+	fake.listMutex.Lock()
+	defer fake.listMutex.Unlock()
+	fake.ListStub = stub
+}
+
+func (fake *FakeTracker) ListArgsForCall(i int) (context.Context, int, time.Time) {
+	fake.listMutex.RLock()
+	defer fake.listMutex.RUnlock()
+	argsForCall := fake.listArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeTracker) ListReturns(result1 []models.Session, result2 error) {
+	fake.listMutex.Lock()
+	defer fake.listMutex.Unlock()
+	fake.ListStub = nil
+	fake.listReturns = struct {
+		result1 []models.Session
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeTracker) ListReturnsOnCall(i int, result1 []models.Session, result2 error) {
+	fake.listMutex.Lock()
+	defer fake.listMutex.Unlock()
+	fake.ListStub = nil
+	if fake.listReturnsOnCall == nil {
+		fake.listReturnsOnCall = make(map[int]struct {
+			result1 []models.Session
+			result2 error
+		})
+	}
+	fake.listReturnsOnCall[i] = struct {
+		result1 []models.Session
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeTracker) Touch(arg1 context.Context, arg2 string, arg3 time.Time, arg4 time.Time, arg5 time.Time) (bool, error) {
+	fake.touchMutex.Lock()
+	ret, specificReturn := fake.touchReturnsOnCall[len(fake.touchArgsForCall)]
+	fake.touchArgsForCall = append(fake.touchArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 time.Time
+		arg4 time.Time
+		arg5 time.Time
+	}{arg1, arg2, arg3, arg4, arg5})
+	fake.recordInvocation("Touch", []interface{}{arg1, arg2, arg3, arg4, arg5})
+	fake.touchMutex.Unlock()
+	if fake.TouchStub != nil {
+		return fake.TouchStub(arg1, arg2, arg3, arg4, arg5)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.touchReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeTracker) TouchCallCount() int {
+	fake.touchMutex.RLock()
+	defer fake.touchMutex.RUnlock()
+	return len(fake.touchArgsForCall)
+}
+
+func (fake *FakeTracker) TouchCalls(stub func(context.Context, string, time.Time, time.Time, time.Time) (bool, error)) {
+	fake.touchMutex.Lock()
+	defer fake.touchMutex.Unlock()
+	fake.TouchStub = stub
+}
+
+func (fake *FakeTracker) TouchArgsForCall(i int) (context.Context, string, time.Time, time.Time, time.Time) {
+	fake.touchMutex.RLock()
+	defer fake.touchMutex.RUnlock()
+	argsForCall := fake.touchArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
+}
+
+func (fake *FakeTracker) TouchReturns(result1 bool, result2 error) {
+	fake.touchMutex.Lock()
+	defer fake.touchMutex.Unlock()
+	fake.TouchStub = nil
+	fake.touchReturns = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeTracker) TouchReturnsOnCall(i int, result1 bool, result2 error) {
+	fake.touchMutex.Lock()
+	defer fake.touchMutex.Unlock()
+	fake.TouchStub = nil
+	if fake.touchReturnsOnCall == nil {
+		fake.touchReturnsOnCall = make(map[int]struct {
+			result1 bool
+			result2 error
+		})
+	}
+	fake.touchReturnsOnCall[i] = struct {
+		result1 bool
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeTracker) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.createMutex.RLock()
+	defer fake.createMutex.RUnlock()
+	fake.deleteMutex.RLock()
+	defer fake.deleteMutex.RUnlock()
+	fake.deleteAllMutex.RLock()
+	defer fake.deleteAllMutex.RUnlock()
+	fake.deleteIdleMutex.RLock()
+	defer fake.deleteIdleMutex.RUnlock()
+	fake.isNotFoundErrMutex.RLock()
+	defer fake.isNotFoundErrMutex.RUnlock()
+	fake.listMutex.RLock()
+	defer fake.listMutex.RUnlock()
+	fake.touchMutex.RLock()
+	defer fake.touchMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeTracker) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ session.Tracker = new(FakeTracker)
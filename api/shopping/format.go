@@ -0,0 +1,124 @@
+package shopping
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/kieron-pivotal/menu-planner-app/models"
+	"github.com/kieron-pivotal/menu-planner-app/units"
+)
+
+// The formats a list can be written in
+const (
+	JSON     = "json"
+	Text     = "text"
+	Markdown = "markdown"
+	CSV      = "csv"
+	HTML     = "html"
+)
+
+var contentTypes = map[string]string{
+	JSON:     "application/json",
+	Text:     "text/plain; charset=utf-8",
+	Markdown: "text/markdown; charset=utf-8",
+	CSV:      "text/csv; charset=utf-8",
+	HTML:     "text/html; charset=utf-8",
+}
+
+// ContentType is the media type of a format, or empty if the format is
+// unknown
+func ContentType(format string) string {
+	return contentTypes[format]
+}
+
+// Write writes the list in the given format
+func Write(w io.Writer, format string, list models.ShoppingList) error {
+	switch format {
+	case JSON:
+		return json.NewEncoder(w).Encode(list)
+	case Text:
+		return writeLines(w, list, "%s\n", "- %s\n")
+	case Markdown:
+		return writeLines(w, list, "## %s\n\n", "- [ ] %s\n")
+	case CSV:
+		return writeCSV(w, list)
+	case HTML:
+		return page.Execute(w, list)
+	}
+	return fmt.Errorf("shopping: unknown format %q", format)
+}
+
+// describe writes an item as it would be written on a list, e.g. "1.5 kg
+// potatoes"
+func describe(item models.ShoppingItem) string {
+	if item.Quantity == 0 {
+		return item.Name
+	}
+	return units.Format(item.Quantity, item.Unit) + " " + item.Name
+}
+
+func writeLines(w io.Writer, list models.ShoppingList, heading, line string) error {
+	bw := bufio.NewWriter(w)
+	for i, category := range list.Categories {
+		if i > 0 {
+			bw.WriteString("\n")
+		}
+		fmt.Fprintf(bw, heading, category.Name)
+		for _, item := range category.Items {
+			fmt.Fprintf(bw, line, describe(item))
+		}
+	}
+	return bw.Flush()
+}
+
+func writeCSV(w io.Writer, list models.ShoppingList) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"category", "item", "quantity", "unit"})
+	for _, category := range list.Categories {
+		for _, item := range category.Items {
+			quantity := ""
+			unit := strings.TrimSpace(item.Unit)
+			if item.Quantity != 0 {
+				var q float64
+				q, unit = units.Readable(item.Quantity, item.Unit)
+				quantity = strconv.FormatFloat(q, 'f', -1, 64)
+			}
+			cw.Write([]string{category.Name, item.Name, quantity, unit})
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+var page = template.Must(template.New("list").Funcs(template.FuncMap{"describe": describe}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Shopping list</title>
+<style>
+body { font-family: sans-serif; max-width: 40em; margin: 2em auto; }
+h2 { font-size: 1.1em; border-bottom: 1px solid #999; }
+ul { list-style: none; padding-left: 0; columns: 2; }
+li::before { content: "\2610\00a0"; }
+@media print { body { margin: 0; } }
+</style>
+</head>
+<body>
+<h1>Shopping list</h1>
+{{- range .Categories}}
+<h2>{{.Name}}</h2>
+<ul>
+{{- range .Items}}
+<li>{{describe .}}</li>
+{{- end}}
+</ul>
+{{- end}}
+</body>
+</html>
+`))
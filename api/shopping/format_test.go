@@ -0,0 +1,93 @@
+package shopping_test
+
+import (
+	"bytes"
+
+	"github.com/kieron-pivotal/menu-planner-app/models"
+	"github.com/kieron-pivotal/menu-planner-app/shopping"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Format", func() {
+	var list models.ShoppingList
+
+	BeforeEach(func() {
+		list = models.ShoppingList{Categories: []models.ShoppingCategory{
+			{Name: "Fruit & veg", Items: []models.ShoppingItem{
+				{Name: "onions", Quantity: 3},
+				{Name: "potatoes", Quantity: 1500, Unit: "g"},
+			}},
+			{Name: "Cupboard", Items: []models.ShoppingItem{
+				{Name: "olive oil", Quantity: 45, Unit: "ml"},
+				{Name: "salt"},
+			}},
+		}}
+	})
+
+	write := func(format string) string {
+		buf := new(bytes.Buffer)
+		Expect(shopping.Write(buf, format, list)).To(Succeed())
+		return buf.String()
+	}
+
+	It("writes plain text", func() {
+		Expect(write(shopping.Text)).To(Equal(`Fruit & veg
+- 3 onions
+- 1.5 kg potatoes
+
+Cupboard
+- 45 ml olive oil
+- salt
+`))
+	})
+
+	It("writes a Markdown checklist", func() {
+		Expect(write(shopping.Markdown)).To(Equal(`## Fruit & veg
+
+- [ ] 3 onions
+- [ ] 1.5 kg potatoes
+
+## Cupboard
+
+- [ ] 45 ml olive oil
+- [ ] salt
+`))
+	})
+
+	It("writes CSV", func() {
+		Expect(write(shopping.CSV)).To(Equal(`category,item,quantity,unit
+Fruit & veg,onions,3,
+Fruit & veg,potatoes,1.5,kg
+Cupboard,olive oil,45,ml
+Cupboard,salt,,
+`))
+	})
+
+	It("writes a printable HTML page, escaping names", func() {
+		list.Categories[1].Items[1].Name = "salt & <pepper>"
+
+		out := write(shopping.HTML)
+		Expect(out).To(HavePrefix("<!DOCTYPE html>"))
+		Expect(out).To(ContainSubstring("<h2>Fruit &amp; veg</h2>\n<ul>\n<li>3 onions</li>\n<li>1.5 kg potatoes</li>\n</ul>"))
+		Expect(out).To(ContainSubstring("<li>salt &amp; &lt;pepper&gt;</li>"))
+		Expect(out).To(ContainSubstring("@media print"))
+	})
+
+	It("writes JSON", func() {
+		Expect(write(shopping.JSON)).To(MatchJSON(`{"categories": [
+			{"name": "Fruit & veg", "items": [{"name": "onions", "quantity": 3}, {"name": "potatoes", "quantity": 1500, "unit": "g"}]},
+			{"name": "Cupboard", "items": [{"name": "olive oil", "quantity": 45, "unit": "ml"}, {"name": "salt"}]}
+		]}`))
+	})
+
+	It("knows the content type of each format", func() {
+		Expect(shopping.ContentType(shopping.Markdown)).To(Equal("text/markdown; charset=utf-8"))
+		Expect(shopping.ContentType(shopping.CSV)).To(Equal("text/csv; charset=utf-8"))
+		Expect(shopping.ContentType("pdf")).To(BeEmpty())
+	})
+
+	It("refuses unknown formats", func() {
+		Expect(shopping.Write(new(bytes.Buffer), "pdf", list)).To(MatchError(ContainSubstring("unknown format")))
+	})
+})
@@ -0,0 +1,190 @@
+/*
+Package shopping works out what to buy for a set of recipes, adding up
+ingredients they share and grouping them into categories such as the
+aisles of a supermarket.
+*/
+package shopping
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/kieron-pivotal/menu-planner-app/models"
+	"github.com/kieron-pivotal/menu-planner-app/units"
+)
+
+//go:embed categories.csv
+var defaultCSV []byte
+
+// Other is the category of ingredients which aren't in any other
+const Other = "Other"
+
+// Categories maps ingredient names to the category they are bought in
+type Categories struct {
+	order      []string
+	categories map[string]string
+}
+
+// Default returns the built-in categories of common ingredients
+func Default() *Categories {
+	c, err := Load(bytes.NewReader(defaultCSV))
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// Load reads categories from a CSV with a header row, one category per
+// row, followed by its semicolon separated ingredient names. Categories
+// are listed in the order they are read.
+func Load(r io.Reader) (*Categories, error) {
+	rows, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("shopping: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("shopping: no header row")
+	}
+
+	c := &Categories{categories: map[string]string{}}
+	for n, row := range rows[1:] {
+		if len(row) < 2 {
+			return nil, fmt.Errorf("shopping: line %d: expected category and ingredients", n+2)
+		}
+		category := strings.TrimSpace(row[0])
+		c.order = append(c.order, category)
+		for _, name := range strings.Split(row[1], ";") {
+			if name = normalize(name); name != "" {
+				c.categories[name] = category
+			}
+		}
+	}
+
+	return c, nil
+}
+
+// Build adds up the ingredients of the recipes. The same ingredient in
+// different weights or volumes is added up in grams or millilitres; in
+// other units it is listed once per unit. A recipe listed twice is
+// bought for twice.
+func (c *Categories) Build(recipes []models.Recipe) models.ShoppingList {
+	items := []models.ShoppingItem{}
+
+	for _, recipe := range recipes {
+		for _, ing := range recipe.Ingredients {
+			name := strings.TrimSpace(ing.Name)
+			if name == "" {
+				continue
+			}
+
+			quantity, unit, ok := units.Base(ing.Quantity, ing.Unit)
+			if !ok {
+				unit = strings.TrimSpace(ing.Unit)
+			}
+
+			found := false
+			for i, item := range items {
+				if sameName(item.Name, name) && sameUnit(item.Unit, unit) {
+					items[i].Quantity += quantity
+					found = true
+					break
+				}
+			}
+			if !found {
+				items = append(items, models.ShoppingItem{Name: name, Quantity: quantity, Unit: unit})
+			}
+		}
+	}
+
+	byCategory := map[string][]models.ShoppingItem{}
+	for _, item := range items {
+		if item.Quantity == 0 && hasQuantity(items, item.Name) {
+			continue
+		}
+		category := c.lookup(item.Name)
+		byCategory[category] = append(byCategory[category], item)
+	}
+
+	list := models.ShoppingList{Categories: []models.ShoppingCategory{}}
+	for _, name := range append(c.order, Other) {
+		if len(byCategory[name]) == 0 {
+			continue
+		}
+		category := models.ShoppingCategory{Name: name, Items: byCategory[name]}
+		sort.SliceStable(category.Items, func(i, j int) bool {
+			return strings.ToLower(category.Items[i].Name) < strings.ToLower(category.Items[j].Name)
+		})
+		list.Categories = append(list.Categories, category)
+	}
+
+	return list
+}
+
+// lookup finds an ingredient's category by name, its singular, or
+// failing those the longest name contained in it, so "red onions" are
+// with "onion"
+func (c *Categories) lookup(name string) string {
+	name = normalize(name)
+	for _, n := range forms(name) {
+		if category, ok := c.categories[n]; ok {
+			return category
+		}
+	}
+
+	best := ""
+	for n := range c.categories {
+		for _, f := range forms(name) {
+			if len(n) > len(best) && strings.Contains(" "+f+" ", " "+n+" ") {
+				best = n
+			}
+		}
+	}
+	if best != "" {
+		return c.categories[best]
+	}
+
+	return Other
+}
+
+// hasQuantity reports whether an item with the name is needed in some
+// quantity, making an entry without one redundant
+func hasQuantity(items []models.ShoppingItem, name string) bool {
+	for _, item := range items {
+		if item.Quantity > 0 && sameName(item.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func sameName(a, b string) bool {
+	for _, x := range forms(normalize(a)) {
+		for _, y := range forms(normalize(b)) {
+			if x == y {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// sameUnit matches units by name, so "tins" and "tin" are added up
+func sameUnit(a, b string) bool {
+	if a == "" || b == "" {
+		return a == b
+	}
+	return sameName(units.Normalize(a), units.Normalize(b))
+}
+
+func forms(name string) []string {
+	return []string{name, strings.TrimSuffix(name, "s"), strings.TrimSuffix(name, "es")}
+}
+
+func normalize(name string) string {
+	return strings.Join(strings.Fields(strings.ToLower(name)), " ")
+}
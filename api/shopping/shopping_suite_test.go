@@ -0,0 +1,13 @@
+package shopping_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestShopping(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Shopping Suite")
+}
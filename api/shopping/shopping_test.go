@@ -0,0 +1,110 @@
+package shopping_test
+
+import (
+	"strings"
+
+	"github.com/kieron-pivotal/menu-planner-app/models"
+	"github.com/kieron-pivotal/menu-planner-app/shopping"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Shopping", func() {
+	var (
+		categories *shopping.Categories
+		bolognese  models.Recipe
+		chilli     models.Recipe
+	)
+
+	BeforeEach(func() {
+		categories = shopping.Default()
+		bolognese = models.Recipe{Name: "Bolognese", Ingredients: []models.Ingredient{
+			{Name: "beef mince", Quantity: 500, Unit: "g"},
+			{Name: "onion", Quantity: 1},
+			{Name: "chopped tomatoes", Quantity: 2, Unit: "tins"},
+			{Name: "olive oil", Quantity: 1, Unit: "tbsp"},
+			{Name: "salt"},
+			{Name: "spaghetti", Quantity: 400, Unit: "g"},
+		}}
+		chilli = models.Recipe{Name: "Chilli", Ingredients: []models.Ingredient{
+			{Name: "Beef Mince", Quantity: 0.75, Unit: "kg"},
+			{Name: "onions", Quantity: 2},
+			{Name: "chopped tomatoes", Quantity: 1, Unit: "tin"},
+			{Name: "olive oil", Quantity: 30, Unit: "ml"},
+			{Name: "salt", Quantity: 1, Unit: "tsp"},
+			{Name: "kidney beans", Quantity: 400, Unit: "g"},
+			{Name: "Dragon fruit", Quantity: 1},
+			{Name: "garlic", Quantity: 2, Unit: "cloves"},
+		}}
+	})
+
+	It("adds up the ingredients and groups them by category", func() {
+		list := categories.Build([]models.Recipe{bolognese, chilli})
+
+		Expect(list).To(Equal(models.ShoppingList{Categories: []models.ShoppingCategory{
+			{Name: "Fruit & veg", Items: []models.ShoppingItem{
+				{Name: "garlic", Quantity: 2, Unit: "cloves"},
+				{Name: "onion", Quantity: 3},
+			}},
+			{Name: "Meat & fish", Items: []models.ShoppingItem{
+				{Name: "beef mince", Quantity: 1250, Unit: "g"},
+			}},
+			{Name: "Cupboard", Items: []models.ShoppingItem{
+				{Name: "chopped tomatoes", Quantity: 3, Unit: "tins"},
+				{Name: "kidney beans", Quantity: 400, Unit: "g"},
+				{Name: "olive oil", Quantity: 45, Unit: "ml"},
+				{Name: "salt", Quantity: 5, Unit: "ml"},
+				{Name: "spaghetti", Quantity: 400, Unit: "g"},
+			}},
+			{Name: "Other", Items: []models.ShoppingItem{
+				{Name: "Dragon fruit", Quantity: 1},
+			}},
+		}}))
+	})
+
+	It("buys for a recipe twice if it is listed twice", func() {
+		list := categories.Build([]models.Recipe{bolognese, bolognese})
+		Expect(list.Categories[1].Items).To(Equal([]models.ShoppingItem{{Name: "beef mince", Quantity: 1000, Unit: "g"}}))
+	})
+
+	It("keeps ingredients without a quantity if nothing else needs them", func() {
+		list := categories.Build([]models.Recipe{bolognese})
+		Expect(list.Categories[2].Items).To(ContainElement(models.ShoppingItem{Name: "salt"}))
+	})
+
+	It("finds the category of ingredients by a name they contain", func() {
+		list := categories.Build([]models.Recipe{{Ingredients: []models.Ingredient{
+			{Name: "red onions", Quantity: 2},
+			{Name: "free range eggs", Quantity: 6},
+			{Name: "cherry tomatoes", Quantity: 250, Unit: "g"},
+		}}})
+
+		Expect(list.Categories).To(HaveLen(2))
+		Expect(list.Categories[0].Name).To(Equal("Fruit & veg"))
+		Expect(list.Categories[0].Items).To(HaveLen(2))
+		Expect(list.Categories[1].Name).To(Equal("Dairy & eggs"))
+	})
+
+	It("returns an empty list for no recipes", func() {
+		list := categories.Build(nil)
+		Expect(list.Categories).NotTo(BeNil())
+		Expect(list.Categories).To(BeEmpty())
+	})
+
+	It("loads categories in the order they are listed", func() {
+		custom, err := shopping.Load(strings.NewReader("category,ingredients\nTins,chopped tomatoes;kidney beans\nVeg,onion\n"))
+		Expect(err).NotTo(HaveOccurred())
+
+		list := custom.Build([]models.Recipe{chilli})
+		names := []string{}
+		for _, c := range list.Categories {
+			names = append(names, c.Name)
+		}
+		Expect(names).To(Equal([]string{"Tins", "Veg", "Other"}))
+	})
+
+	It("rejects rows without ingredients", func() {
+		_, err := shopping.Load(strings.NewReader("category,ingredients\nTins\n"))
+		Expect(err).To(HaveOccurred())
+	})
+})
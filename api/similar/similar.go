@@ -0,0 +1,140 @@
+/* Package similar finds recipes whose names probably mean the same dish */
+package similar
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/kieron-pivotal/menu-planner-app/models"
+)
+
+const (
+	// minPrefix is the shortest abbreviation matched, e.g. "bol" for
+	// "bolognese"
+	minPrefix = 3
+
+	// minTypoLen is the shortest name allowed to differ by a typo, so
+	// short names such as "pea soup" and "pear soup" stay apart
+	minTypoLen = 10
+)
+
+// ignored are words which don't change the dish
+var ignored = map[string]bool{
+	"a": true, "an": true, "the": true, "and": true, "with": true, "of": true,
+	"my": true, "mum's": true, "mums": true,
+}
+
+// Recipes returns the recipes with names like name, in their original
+// order. Names match when they are the same ignoring case, punctuation
+// and filler words; when every word of one is a word or abbreviation of a
+// word of the other, as in "Spag bol" and "Spaghetti Bolognese"; or when
+// they differ by a typo or two.
+func Recipes(name string, recipes []models.Recipe) []models.Recipe {
+	words := normalize(name)
+	res := []models.Recipe{}
+	if len(words) == 0 {
+		return res
+	}
+
+	for _, r := range recipes {
+		if alike(words, normalize(r.Name)) {
+			res = append(res, r)
+		}
+	}
+	return res
+}
+
+// alike reports whether two normalized names are the same dish
+func alike(a, b []string) bool {
+	if len(a) == 0 || len(b) == 0 {
+		return false
+	}
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+
+	// allow one typo in every five letters of the whole name
+	joinedA, joinedB := strings.Join(a, " "), strings.Join(b, " ")
+	if n := len([]rune(joinedB)); n >= minTypoLen && distance(joinedA, joinedB) <= n/5 {
+		return true
+	}
+
+	if len(a) != len(b) {
+		return false
+	}
+
+	used := make([]bool, len(b))
+	for _, word := range a {
+		found := false
+		for i, other := range b {
+			if !used[i] && sameWord(word, other) {
+				used[i], found = true, true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// sameWord reports whether the words are equal, one abbreviates the other
+// by at least two letters, or they differ by a typo
+func sameWord(a, b string) bool {
+	if a == b {
+		return true
+	}
+	if len([]rune(a)) > len([]rune(b)) {
+		a, b = b, a
+	}
+	na, nb := len([]rune(a)), len([]rune(b))
+	if na >= minPrefix && nb-na >= 2 && strings.HasPrefix(b, a) {
+		return true
+	}
+	return na > minPrefix && distance(a, b) <= 1
+}
+
+// normalize lower-cases a name and splits it into words, leaving out
+// punctuation, filler words and plural endings
+func normalize(name string) []string {
+	words := []string{}
+	for _, word := range strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\''
+	}) {
+		word = strings.Trim(word, "'")
+		if word == "" || ignored[word] {
+			continue
+		}
+		if len(word) > minPrefix && strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss") {
+			word = strings.TrimSuffix(word, "s")
+		}
+		words = append(words, word)
+	}
+	return words
+}
+
+// distance is the Levenshtein distance between two strings: the fewest
+// single character insertions, deletions and substitutions turning one
+// into the other
+func distance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,13 @@
+package similar_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestSimilar(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Similar Suite")
+}
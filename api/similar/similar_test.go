@@ -0,0 +1,55 @@
+package similar_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/kieron-pivotal/menu-planner-app/models"
+	"github.com/kieron-pivotal/menu-planner-app/similar"
+)
+
+var _ = Describe("Recipes", func() {
+	names := func(recipes []models.Recipe) []string {
+		res := []string{}
+		for _, r := range recipes {
+			res = append(res, r.Name)
+		}
+		return res
+	}
+
+	recipes := []models.Recipe{
+		{ID: 1, Name: "Spaghetti Bolognese"},
+		{ID: 2, Name: "Fish pie"},
+		{ID: 3, Name: "Chicken curry"},
+		{ID: 4, Name: "Chicken and leek pie"},
+		{ID: 5, Name: "Pea soup"},
+	}
+
+	It("matches names which mean the same dish", func() {
+		for name, expected := range map[string][]string{
+			"spaghetti bolognese":     {"Spaghetti Bolognese"},
+			"Spaghetti  Bolognese!":   {"Spaghetti Bolognese"},
+			"Spag bol":                {"Spaghetti Bolognese"},
+			"bolognese spaghetti":     {"Spaghetti Bolognese"},
+			"Spagetti bolognaise":     {"Spaghetti Bolognese"},
+			"The fish pie":            {"Fish pie"},
+			"fish pies":               {"Fish pie"},
+			"Chicken curry with rice": {},
+			"Chicken pie":             {},
+			"Chicken & leek pie":      {"Chicken and leek pie"},
+			"Pear soup":               {},
+			"Beef stew":               {},
+			"":                        {},
+			"the":                     {},
+		} {
+			Expect(names(similar.Recipes(name, recipes))).To(Equal(expected), name)
+		}
+	})
+
+	It("keeps the recipes in order", func() {
+		Expect(names(similar.Recipes("chicken curry", []models.Recipe{
+			{ID: 7, Name: "Chicken Curry"},
+			{ID: 2, Name: "Chicken curry"},
+		}))).To(Equal([]string{"Chicken Curry", "Chicken curry"}))
+	})
+})
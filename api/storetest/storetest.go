@@ -0,0 +1,990 @@
+/*
+Package storetest is a conformance suite for store implementations. Every
+implementation runs the same specs, so tests and demo mode can rely on the
+in-memory stores behaving like the database ones.
+*/
+package storetest
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/kieron-pivotal/menu-planner-app/handlers"
+	"github.com/kieron-pivotal/menu-planner-app/models"
+	"github.com/kieron-pivotal/menu-planner-app/session"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// Stores are the implementations under test
+type Stores struct {
+	Users     handlers.UserStore
+	Recipes   handlers.RecipeStore
+	Sessions  session.Tracker
+	Prices    handlers.PriceStore
+	Budgets   handlers.BudgetStore
+	CookLog   handlers.CookLogStore
+	Ratings   handlers.RatingStore
+	Calendar  handlers.CalendarTokenStore
+	Plans     handlers.PlanStore
+	Templates handlers.PlanTemplateStore
+}
+
+// DescribeStores defines the conformance specs. newStores is called before every
+// spec and must return stores whose changes don't outlive the spec, e.g.
+// by running in a transaction which is rolled back afterwards.
+func DescribeStores(name string, newStores func() Stores) bool {
+	return Context(name+" conformance", func() {
+		var (
+			stores Stores
+			ctx    context.Context
+		)
+
+		BeforeEach(func() {
+			stores = newStores()
+			ctx = context.Background()
+		})
+
+		createUser := func(email string) models.User {
+			user, err := stores.Users.Create(ctx, email, "Some One")
+			Expect(err).NotTo(HaveOccurred())
+			return user
+		}
+
+		Describe("users", func() {
+			It("finds created users by email", func() {
+				created := createUser("conformance@example.com")
+				Expect(created.ID()).NotTo(BeZero())
+				Expect(created.Email()).To(Equal("conformance@example.com"))
+				Expect(created.Name()).To(Equal("Some One"))
+
+				found, err := stores.Users.FindByEmail(ctx, "conformance@example.com")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found.ID()).To(Equal(created.ID()))
+				Expect(found.Name()).To(Equal("Some One"))
+			})
+
+			It("gives each user a different id", func() {
+				Expect(createUser("one@example.com").ID()).NotTo(Equal(createUser("two@example.com").ID()))
+			})
+
+			It("reports unknown emails as not found", func() {
+				_, err := stores.Users.FindByEmail(ctx, "nobody@example.com")
+				Expect(stores.Users.IsNotFoundErr(err)).To(BeTrue())
+			})
+
+			It("rejects duplicate emails", func() {
+				createUser("conformance@example.com")
+				_, err := stores.Users.Create(ctx, "conformance@example.com", "Another")
+				Expect(err).To(MatchError(ContainSubstring("create-user failed")))
+			})
+
+			It("rejects names longer than 200 characters", func() {
+				_, err := stores.Users.Create(ctx, "conformance@example.com", strings.Repeat("A", 201))
+				Expect(err).To(MatchError(ContainSubstring("create-user failed")))
+			})
+		})
+
+		Describe("recipes", func() {
+			var userID int
+
+			BeforeEach(func() {
+				userID = createUser("cook@example.com").ID()
+			})
+
+			It("lists nothing for a user without recipes", func() {
+				recipes, err := stores.Recipes.List(ctx, userID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(recipes).NotTo(BeNil())
+				Expect(recipes).To(BeEmpty())
+			})
+
+			It("lists only the user's recipes, in the order they were added", func() {
+				otherID := createUser("other@example.com").ID()
+				for _, r := range []models.Recipe{
+					{Name: "soup", UserID: userID},
+					{Name: "stew", UserID: otherID},
+					{Name: "bread", UserID: userID},
+				} {
+					inserted, err := stores.Recipes.Insert(ctx, r)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(inserted.ID).NotTo(BeZero())
+				}
+
+				recipes, err := stores.Recipes.List(ctx, userID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(recipes).To(HaveLen(2))
+				Expect(recipes[0].Name).To(Equal("soup"))
+				Expect(recipes[1].Name).To(Equal("bread"))
+				Expect(recipes[0].UserID).To(Equal(userID))
+			})
+
+			It("gets a recipe with its ingredients and steps in order", func() {
+				inserted, err := stores.Recipes.Insert(ctx, models.Recipe{
+					Name:     "omelette",
+					UserID:   userID,
+					Servings: 2,
+					Ingredients: []models.Ingredient{
+						{Name: "eggs", Quantity: 3},
+						{Name: "butter", Quantity: 10, Unit: "g"},
+						{Name: "salt"},
+					},
+					Steps: []models.Step{
+						{Instruction: "Beat the eggs with salt", Ingredients: []int{2, 0}},
+						{Instruction: "Melt the butter", DurationSeconds: 60, Ingredients: []int{1}},
+						{Instruction: "Cook", DurationSeconds: 120},
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				recipe, err := stores.Recipes.Get(ctx, userID, inserted.ID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(recipe.ID).To(Equal(inserted.ID))
+				Expect(recipe.Name).To(Equal("omelette"))
+				Expect(recipe.Servings).To(Equal(2))
+				Expect(recipe.Ingredients).To(Equal([]models.Ingredient{
+					{Name: "eggs", Quantity: 3},
+					{Name: "butter", Quantity: 10, Unit: "g"},
+					{Name: "salt"},
+				}))
+				Expect(recipe.Steps).To(Equal([]models.Step{
+					{Instruction: "Beat the eggs with salt", Ingredients: []int{0, 2}},
+					{Instruction: "Melt the butter", DurationSeconds: 60, Ingredients: []int{1}},
+					{Instruction: "Cook", DurationSeconds: 120},
+				}))
+
+				recipes, err := stores.Recipes.List(ctx, userID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(recipes[0].Steps).To(BeEmpty())
+			})
+
+			It("gets recipes without a method", func() {
+				inserted, err := stores.Recipes.Insert(ctx, models.Recipe{Name: "toast", UserID: userID})
+				Expect(err).NotTo(HaveOccurred())
+
+				recipe, err := stores.Recipes.Get(ctx, userID, inserted.ID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(recipe.Ingredients).To(BeEmpty())
+				Expect(recipe.Steps).To(BeEmpty())
+			})
+
+			It("gets several of the user's recipes at once, in id order", func() {
+				otherID := createUser("other@example.com").ID()
+				soup, err := stores.Recipes.Insert(ctx, models.Recipe{Name: "soup", UserID: userID,
+					Ingredients: []models.Ingredient{{Name: "leeks", Quantity: 2}},
+					Steps:       []models.Step{{Instruction: "Simmer", Ingredients: []int{0}}}})
+				Expect(err).NotTo(HaveOccurred())
+				stew, err := stores.Recipes.Insert(ctx, models.Recipe{Name: "stew", UserID: otherID})
+				Expect(err).NotTo(HaveOccurred())
+				bread, err := stores.Recipes.Insert(ctx, models.Recipe{Name: "bread", UserID: userID,
+					Ingredients: []models.Ingredient{{Name: "flour", Quantity: 500, Unit: "g"}}})
+				Expect(err).NotTo(HaveOccurred())
+				_, err = stores.Ratings.Rate(ctx, models.Rating{UserID: userID, RecipeID: bread.ID, Rating: 4, Favourite: true})
+				Expect(err).NotTo(HaveOccurred())
+
+				recipes, err := stores.Recipes.GetMany(ctx, userID, []int{bread.ID, stew.ID, soup.ID, bread.ID, 9999})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(recipes).To(HaveLen(2))
+				Expect(recipes[0].Name).To(Equal("soup"))
+				Expect(recipes[0].Ingredients).To(Equal([]models.Ingredient{{Name: "leeks", Quantity: 2}}))
+				Expect(recipes[0].Steps).To(Equal([]models.Step{{Instruction: "Simmer", Ingredients: []int{0}}}))
+				Expect(recipes[1].Name).To(Equal("bread"))
+				Expect(recipes[1].Ingredients).To(Equal([]models.Ingredient{{Name: "flour", Quantity: 500, Unit: "g"}}))
+				Expect(recipes[1].Steps).To(BeEmpty())
+				Expect(recipes[1].Score).To(Equal(4.0))
+				Expect(recipes[1].Favourite).To(BeTrue())
+
+				recipes, err = stores.Recipes.GetMany(ctx, userID, nil)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(recipes).NotTo(BeNil())
+				Expect(recipes).To(BeEmpty())
+			})
+
+			It("reports other users' recipes as not found", func() {
+				otherID := createUser("other@example.com").ID()
+				inserted, err := stores.Recipes.Insert(ctx, models.Recipe{Name: "soup", UserID: otherID})
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = stores.Recipes.Get(ctx, userID, inserted.ID)
+				Expect(stores.Recipes.IsNotFoundErr(err)).To(BeTrue())
+			})
+
+			It("rejects steps using ingredients the recipe doesn't have", func() {
+				_, err := stores.Recipes.Insert(ctx, models.Recipe{
+					Name:        "soup",
+					UserID:      userID,
+					Ingredients: []models.Ingredient{{Name: "water"}},
+					Steps:       []models.Step{{Instruction: "Boil", Ingredients: []int{1}}},
+				})
+				Expect(err).To(MatchError(ContainSubstring("insert failed")))
+			})
+
+			It("rejects recipes for unknown users", func() {
+				_, err := stores.Recipes.Insert(ctx, models.Recipe{Name: "soup", UserID: userID + 1000})
+				Expect(err).To(MatchError(ContainSubstring("insert failed")))
+			})
+
+			It("rejects names longer than 200 characters", func() {
+				_, err := stores.Recipes.Insert(ctx, models.Recipe{Name: strings.Repeat("A", 201), UserID: userID})
+				Expect(err).To(MatchError(ContainSubstring("insert failed")))
+			})
+
+			It("rejects names the user already has, ignoring case", func() {
+				_, err := stores.Recipes.Insert(ctx, models.Recipe{Name: "Fish Pie", UserID: userID})
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = stores.Recipes.Insert(ctx, models.Recipe{Name: "fish pie", UserID: userID})
+				Expect(err).To(MatchError(ContainSubstring("insert failed")))
+				Expect(stores.Recipes.IsDuplicateErr(err)).To(BeTrue())
+			})
+
+			It("lets different users have recipes with the same name", func() {
+				_, err := stores.Recipes.Insert(ctx, models.Recipe{Name: "Fish Pie", UserID: userID})
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = stores.Recipes.Insert(ctx, models.Recipe{Name: "Fish Pie", UserID: createUser("other@example.com").ID()})
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+
+		Describe("prices", func() {
+			var userID int
+
+			BeforeEach(func() {
+				userID = createUser("shopper@example.com").ID()
+			})
+
+			It("lists the user's prices by ingredient and store", func() {
+				otherID := createUser("other@example.com").ID()
+				for _, p := range []models.Price{
+					{UserID: userID, Ingredient: "rice", Store: "market", Price: 150, Quantity: 1, Unit: "kg"},
+					{UserID: userID, Ingredient: "eggs", Store: "market", Price: 180, Quantity: 6},
+					{UserID: otherID, Ingredient: "eggs", Price: 200, Quantity: 6},
+					{UserID: userID, Ingredient: "eggs", Store: "corner shop", Price: 220, Quantity: 6},
+				} {
+					saved, err := stores.Prices.Save(ctx, p)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(saved.ID).NotTo(BeZero())
+				}
+
+				prices, err := stores.Prices.List(ctx, userID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(prices).To(HaveLen(3))
+				Expect(prices[0].Store).To(Equal("corner shop"))
+				Expect(prices[1].Store).To(Equal("market"))
+				Expect(prices[1].Ingredient).To(Equal("eggs"))
+				Expect(prices[2]).To(Equal(models.Price{
+					ID: prices[2].ID, UserID: userID, Ingredient: "rice", Store: "market", Price: 150, Quantity: 1, Unit: "kg",
+				}))
+			})
+
+			It("replaces the price of an ingredient at the same store", func() {
+				first, err := stores.Prices.Save(ctx, models.Price{UserID: userID, Ingredient: "rice", Store: "market", Price: 150, Quantity: 1, Unit: "kg"})
+				Expect(err).NotTo(HaveOccurred())
+				second, err := stores.Prices.Save(ctx, models.Price{UserID: userID, Ingredient: "rice", Store: "market", Price: 90, Quantity: 500, Unit: "g"})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(second.ID).To(Equal(first.ID))
+
+				prices, err := stores.Prices.List(ctx, userID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(prices).To(HaveLen(1))
+				Expect(prices[0].Price).To(Equal(90))
+				Expect(prices[0].Unit).To(Equal("g"))
+			})
+
+			It("deletes only the user's own prices", func() {
+				otherID := createUser("other@example.com").ID()
+				mine, err := stores.Prices.Save(ctx, models.Price{UserID: userID, Ingredient: "rice", Price: 150, Quantity: 1})
+				Expect(err).NotTo(HaveOccurred())
+				theirs, err := stores.Prices.Save(ctx, models.Price{UserID: otherID, Ingredient: "rice", Price: 150, Quantity: 1})
+				Expect(err).NotTo(HaveOccurred())
+
+				err = stores.Prices.Delete(ctx, userID, theirs.ID)
+				Expect(stores.Prices.IsNotFoundErr(err)).To(BeTrue())
+
+				Expect(stores.Prices.Delete(ctx, userID, mine.ID)).To(Succeed())
+				prices, err := stores.Prices.List(ctx, userID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(prices).To(BeEmpty())
+			})
+		})
+
+		Describe("budgets", func() {
+			var userID int
+
+			BeforeEach(func() {
+				userID = createUser("saver@example.com").ID()
+			})
+
+			It("has no budget until one is set", func() {
+				budget, err := stores.Budgets.Get(ctx, userID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(budget).To(Equal(models.Budget{UserID: userID}))
+			})
+
+			It("replaces the user's budget", func() {
+				Expect(stores.Budgets.Save(ctx, models.Budget{UserID: userID, Weekly: 6000})).To(Succeed())
+				Expect(stores.Budgets.Save(ctx, models.Budget{UserID: userID, Weekly: 5500})).To(Succeed())
+
+				budget, err := stores.Budgets.Get(ctx, userID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(budget.Weekly).To(Equal(5500))
+			})
+
+			It("rejects a negative budget", func() {
+				err := stores.Budgets.Save(ctx, models.Budget{UserID: userID, Weekly: -1})
+				Expect(err).To(MatchError(ContainSubstring("save-budget failed")))
+			})
+
+			It("rejects a budget for an unknown user", func() {
+				err := stores.Budgets.Save(ctx, models.Budget{UserID: userID + 1000, Weekly: 6000})
+				Expect(err).To(MatchError(ContainSubstring("save-budget failed")))
+			})
+		})
+
+		Describe("cook log", func() {
+			var (
+				recipe    models.Recipe
+				yesterday time.Time
+				lastWeek  time.Time
+			)
+
+			BeforeEach(func() {
+				var err error
+				recipe, err = stores.Recipes.Insert(ctx, models.Recipe{Name: "pancakes", UserID: createUser("cook@example.com").ID()})
+				Expect(err).NotTo(HaveOccurred())
+
+				yesterday = time.Now().Add(-24 * time.Hour).Truncate(time.Second)
+				lastWeek = yesterday.Add(-6 * 24 * time.Hour)
+			})
+
+			It("lists a recipe's history, most recent first", func() {
+				first, err := stores.CookLog.Add(ctx, models.Cooked{RecipeID: recipe.ID, CookedAt: lastWeek, Rating: 3})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(first.ID).NotTo(BeZero())
+				_, err = stores.CookLog.Add(ctx, models.Cooked{RecipeID: recipe.ID, CookedAt: yesterday, Rating: 5, Notes: "extra lemon"})
+				Expect(err).NotTo(HaveOccurred())
+
+				history, err := stores.CookLog.History(ctx, recipe.ID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(history).To(HaveLen(2))
+				Expect(history[0].CookedAt).To(BeTemporally("==", yesterday))
+				Expect(history[0].Rating).To(Equal(5))
+				Expect(history[0].Notes).To(Equal("extra lemon"))
+				Expect(history[1].ID).To(Equal(first.ID))
+				Expect(history[1].RecipeID).To(Equal(recipe.ID))
+			})
+
+			It("lists nothing for a recipe never cooked", func() {
+				history, err := stores.CookLog.History(ctx, recipe.ID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(history).NotTo(BeNil())
+				Expect(history).To(BeEmpty())
+			})
+
+			It("lists several recipes' histories at once, most recent first", func() {
+				other, err := stores.Recipes.Insert(ctx, models.Recipe{Name: "waffles", UserID: recipe.UserID})
+				Expect(err).NotTo(HaveOccurred())
+				_, err = stores.CookLog.Add(ctx, models.Cooked{RecipeID: recipe.ID, CookedAt: lastWeek})
+				Expect(err).NotTo(HaveOccurred())
+				_, err = stores.CookLog.Add(ctx, models.Cooked{RecipeID: other.ID, CookedAt: yesterday})
+				Expect(err).NotTo(HaveOccurred())
+
+				history, err := stores.CookLog.Histories(ctx, []int{recipe.ID, other.ID})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(history).To(HaveLen(2))
+				Expect(history[0].RecipeID).To(Equal(other.ID))
+				Expect(history[1].RecipeID).To(Equal(recipe.ID))
+
+				history, err = stores.CookLog.Histories(ctx, nil)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(history).NotTo(BeNil())
+				Expect(history).To(BeEmpty())
+			})
+
+			It("keeps the latest time the recipe was cooked", func() {
+				got, err := stores.Recipes.Get(ctx, recipe.UserID, recipe.ID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(got.LastCookedAt).To(BeNil())
+
+				_, err = stores.CookLog.Add(ctx, models.Cooked{RecipeID: recipe.ID, CookedAt: yesterday})
+				Expect(err).NotTo(HaveOccurred())
+				_, err = stores.CookLog.Add(ctx, models.Cooked{RecipeID: recipe.ID, CookedAt: lastWeek})
+				Expect(err).NotTo(HaveOccurred())
+
+				got, err = stores.Recipes.Get(ctx, recipe.UserID, recipe.ID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(got.LastCookedAt).NotTo(BeNil())
+				Expect(*got.LastCookedAt).To(BeTemporally("==", yesterday))
+
+				recipes, err := stores.Recipes.List(ctx, recipe.UserID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(recipes).To(HaveLen(1))
+				Expect(recipes[0].LastCookedAt).NotTo(BeNil())
+				Expect(*recipes[0].LastCookedAt).To(BeTemporally("==", yesterday))
+			})
+
+			It("refuses to log a recipe which doesn't exist", func() {
+				_, err := stores.CookLog.Add(ctx, models.Cooked{RecipeID: recipe.ID + 1000, CookedAt: yesterday})
+				Expect(err).To(MatchError(ContainSubstring("add-cooked failed")))
+			})
+
+			It("lists the user's leftovers, oldest first", func() {
+				otherRecipe, err := stores.Recipes.Insert(ctx, models.Recipe{Name: "chilli", UserID: createUser("other@example.com").ID()})
+				Expect(err).NotTo(HaveOccurred())
+				for _, c := range []models.Cooked{
+					{RecipeID: recipe.ID, CookedAt: yesterday, Leftovers: 2},
+					{RecipeID: recipe.ID, CookedAt: lastWeek, Leftovers: 1},
+					{RecipeID: recipe.ID, CookedAt: lastWeek},
+					{RecipeID: otherRecipe.ID, CookedAt: lastWeek, Leftovers: 3},
+				} {
+					_, err = stores.CookLog.Add(ctx, c)
+					Expect(err).NotTo(HaveOccurred())
+				}
+
+				leftovers, err := stores.CookLog.Leftovers(ctx, recipe.UserID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(leftovers).To(HaveLen(2))
+				Expect(leftovers[0].Leftovers).To(Equal(1))
+				Expect(leftovers[1].Leftovers).To(Equal(2))
+				Expect(leftovers[1].CookedAt).To(BeTemporally("==", yesterday))
+			})
+
+			It("eats leftovers until there are none", func() {
+				cooked, err := stores.CookLog.Add(ctx, models.Cooked{RecipeID: recipe.ID, CookedAt: yesterday, Leftovers: 3})
+				Expect(err).NotTo(HaveOccurred())
+
+				eaten, err := stores.CookLog.EatLeftovers(ctx, recipe.UserID, cooked.ID, 2)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(eaten.Leftovers).To(Equal(1))
+				Expect(eaten.RecipeID).To(Equal(recipe.ID))
+
+				eaten, err = stores.CookLog.EatLeftovers(ctx, recipe.UserID, cooked.ID, 2)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(eaten.Leftovers).To(BeZero())
+
+				leftovers, err := stores.CookLog.Leftovers(ctx, recipe.UserID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(leftovers).To(BeEmpty())
+			})
+
+			It("only lets users eat their own leftovers", func() {
+				cooked, err := stores.CookLog.Add(ctx, models.Cooked{RecipeID: recipe.ID, CookedAt: yesterday, Leftovers: 3})
+				Expect(err).NotTo(HaveOccurred())
+
+				otherID := createUser("other@example.com").ID()
+				_, err = stores.CookLog.EatLeftovers(ctx, otherID, cooked.ID, 1)
+				Expect(stores.CookLog.IsNotFoundErr(err)).To(BeTrue())
+			})
+		})
+
+		Describe("ratings", func() {
+			var (
+				userID, otherID int
+				recipe          models.Recipe
+			)
+
+			BeforeEach(func() {
+				userID = createUser("cook@example.com").ID()
+				otherID = createUser("guest@example.com").ID()
+
+				var err error
+				recipe, err = stores.Recipes.Insert(ctx, models.Recipe{Name: "curry", UserID: userID})
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("has no score for a recipe nobody has rated", func() {
+				got, err := stores.Recipes.Get(ctx, userID, recipe.ID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(got.Score).To(BeZero())
+				Expect(got.LowestRating).To(BeZero())
+				Expect(got.Favourite).To(BeFalse())
+			})
+
+			It("aggregates everyone's ratings, ignoring unrated favourites", func() {
+				for _, r := range []models.Rating{
+					{RecipeID: recipe.ID, UserID: userID, Rating: 5, Favourite: true},
+					{RecipeID: recipe.ID, UserID: otherID, Rating: 2},
+				} {
+					_, err := stores.Ratings.Rate(ctx, r)
+					Expect(err).NotTo(HaveOccurred())
+				}
+				thirdID := createUser("fan@example.com").ID()
+				_, err := stores.Ratings.Rate(ctx, models.Rating{RecipeID: recipe.ID, UserID: thirdID, Favourite: true})
+				Expect(err).NotTo(HaveOccurred())
+
+				got, err := stores.Recipes.Get(ctx, userID, recipe.ID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(got.Score).To(BeNumerically("~", 3.5))
+				Expect(got.LowestRating).To(Equal(2))
+				Expect(got.Favourite).To(BeTrue())
+
+				recipes, err := stores.Recipes.List(ctx, userID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(recipes).To(HaveLen(1))
+				Expect(recipes[0].Score).To(BeNumerically("~", 3.5))
+				Expect(recipes[0].LowestRating).To(Equal(2))
+				Expect(recipes[0].Favourite).To(BeTrue())
+			})
+
+			It("only counts the listing user's favourites", func() {
+				_, err := stores.Ratings.Rate(ctx, models.Rating{RecipeID: recipe.ID, UserID: otherID, Favourite: true})
+				Expect(err).NotTo(HaveOccurred())
+
+				recipes, err := stores.Recipes.List(ctx, userID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(recipes[0].Favourite).To(BeFalse())
+			})
+
+			It("replaces a user's previous rating", func() {
+				_, err := stores.Ratings.Rate(ctx, models.Rating{RecipeID: recipe.ID, UserID: userID, Rating: 1, Favourite: true})
+				Expect(err).NotTo(HaveOccurred())
+				_, err = stores.Ratings.Rate(ctx, models.Rating{RecipeID: recipe.ID, UserID: userID, Rating: 4})
+				Expect(err).NotTo(HaveOccurred())
+
+				got, err := stores.Recipes.Get(ctx, userID, recipe.ID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(got.Score).To(BeNumerically("~", 4))
+				Expect(got.LowestRating).To(Equal(4))
+				Expect(got.Favourite).To(BeFalse())
+			})
+
+			It("refuses to rate a recipe which doesn't exist", func() {
+				_, err := stores.Ratings.Rate(ctx, models.Rating{RecipeID: recipe.ID + 1000, UserID: userID, Rating: 3})
+				Expect(err).To(MatchError(ContainSubstring("rate-recipe failed")))
+			})
+		})
+
+		Describe("merging recipes", func() {
+			var (
+				userID, otherID     int
+				survivor, duplicate models.Recipe
+				yesterday, lastWeek time.Time
+			)
+
+			BeforeEach(func() {
+				userID = createUser("cook@example.com").ID()
+				otherID = createUser("guest@example.com").ID()
+
+				var err error
+				survivor, err = stores.Recipes.Insert(ctx, models.Recipe{Name: "Spaghetti Bolognese", UserID: userID})
+				Expect(err).NotTo(HaveOccurred())
+				duplicate, err = stores.Recipes.Insert(ctx, models.Recipe{Name: "Spag bol", UserID: userID})
+				Expect(err).NotTo(HaveOccurred())
+
+				yesterday = time.Now().Add(-24 * time.Hour).Truncate(time.Second)
+				lastWeek = yesterday.Add(-6 * 24 * time.Hour)
+			})
+
+			It("moves the duplicate's cook log to the survivor", func() {
+				_, err := stores.CookLog.Add(ctx, models.Cooked{RecipeID: survivor.ID, CookedAt: lastWeek})
+				Expect(err).NotTo(HaveOccurred())
+				_, err = stores.CookLog.Add(ctx, models.Cooked{RecipeID: duplicate.ID, CookedAt: yesterday, Notes: "extra garlic"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(stores.Recipes.Merge(ctx, survivor.ID, duplicate.ID)).To(Succeed())
+
+				history, err := stores.CookLog.History(ctx, survivor.ID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(history).To(HaveLen(2))
+				Expect(history[0].Notes).To(Equal("extra garlic"))
+				Expect(history[0].RecipeID).To(Equal(survivor.ID))
+
+				got, err := stores.Recipes.Get(ctx, userID, survivor.ID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(got.Name).To(Equal("Spaghetti Bolognese"))
+				Expect(got.LastCookedAt).NotTo(BeNil())
+				Expect(*got.LastCookedAt).To(BeTemporally("==", yesterday))
+			})
+
+			It("moves ratings, keeping the survivor's where a user rated both", func() {
+				for _, r := range []models.Rating{
+					{RecipeID: survivor.ID, UserID: userID, Rating: 4},
+					{RecipeID: duplicate.ID, UserID: userID, Rating: 2, Favourite: true},
+					{RecipeID: duplicate.ID, UserID: otherID, Rating: 5},
+				} {
+					_, err := stores.Ratings.Rate(ctx, r)
+					Expect(err).NotTo(HaveOccurred())
+				}
+
+				Expect(stores.Recipes.Merge(ctx, survivor.ID, duplicate.ID)).To(Succeed())
+
+				got, err := stores.Recipes.Get(ctx, userID, survivor.ID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(got.Score).To(BeNumerically("~", 4.5))
+				Expect(got.LowestRating).To(Equal(4))
+				Expect(got.Favourite).To(BeTrue())
+			})
+
+			It("deletes the duplicate", func() {
+				Expect(stores.Recipes.Merge(ctx, survivor.ID, duplicate.ID)).To(Succeed())
+
+				_, err := stores.Recipes.Get(ctx, userID, duplicate.ID)
+				Expect(stores.Recipes.IsNotFoundErr(err)).To(BeTrue())
+
+				recipes, err := stores.Recipes.List(ctx, userID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(recipes).To(HaveLen(1))
+				Expect(recipes[0].ID).To(Equal(survivor.ID))
+			})
+
+			It("reports an unknown duplicate as not found", func() {
+				err := stores.Recipes.Merge(ctx, survivor.ID, duplicate.ID+1000)
+				Expect(stores.Recipes.IsNotFoundErr(err)).To(BeTrue())
+			})
+
+			It("plans the survivor wherever the duplicate was planned", func() {
+				slot := models.Slot{Day: 4, Meal: models.Dinner, RecipeID: duplicate.ID}
+				Expect(stores.Plans.Save(ctx, models.Plan{UserID: userID, Week: "2026-10-19", Slots: []models.Slot{slot}})).To(Succeed())
+				template, err := stores.Templates.InsertTemplate(ctx, models.PlanTemplate{UserID: userID, Name: "Usual", Slots: []models.Slot{slot}})
+				Expect(err).NotTo(HaveOccurred())
+				_, err = stores.Templates.SaveRule(ctx, models.PlanRule{UserID: userID, Slot: slot})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(stores.Recipes.Merge(ctx, survivor.ID, duplicate.ID)).To(Succeed())
+
+				plan, err := stores.Plans.Get(ctx, userID, "2026-10-19")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(plan.Slots).To(ConsistOf(models.Slot{Day: 4, Meal: models.Dinner, RecipeID: survivor.ID}))
+				template, err = stores.Templates.GetTemplate(ctx, userID, template.ID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(template.Slots[0].RecipeID).To(Equal(survivor.ID))
+				rules, err := stores.Templates.ListRules(ctx, userID)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(rules[0].RecipeID).To(Equal(survivor.ID))
+			})
+		})
+
+		Describe("plans", func() {
+			var (
+				userID, otherID int
+				soup, stew      int
+			)
+
+			BeforeEach(func() {
+				userID = createUser("planner@example.com").ID()
+				otherID = createUser("other@example.com").ID()
+
+				recipe, err := stores.Recipes.Insert(ctx, models.Recipe{Name: "Soup", UserID: userID})
+				Expect(err).NotTo(HaveOccurred())
+				soup = recipe.ID
+				recipe, err = stores.Recipes.Insert(ctx, models.Recipe{Name: "Stew", UserID: userID})
+				Expect(err).NotTo(HaveOccurred())
+				stew = recipe.ID
+			})
+
+			It("has no slots for a week with nothing planned", func() {
+				plan, err := stores.Plans.Get(ctx, userID, "2026-10-19")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(plan.Week).To(Equal("2026-10-19"))
+				Expect(plan.Slots).To(BeEmpty())
+				Expect(plan.Slots).NotTo(BeNil())
+			})
+
+			It("replaces a week's slots, returning them by day then meal", func() {
+				Expect(stores.Plans.Save(ctx, models.Plan{UserID: userID, Week: "2026-10-19", Slots: []models.Slot{
+					{Day: 2, Meal: models.Lunch, RecipeID: soup},
+				}})).To(Succeed())
+				Expect(stores.Plans.Save(ctx, models.Plan{UserID: userID, Week: "2026-10-19", Slots: []models.Slot{
+					{Day: 1, Meal: models.Dinner, RecipeID: stew},
+					{Day: 1, Meal: models.Breakfast, RecipeID: soup},
+					{Day: 0, Meal: models.Dinner, RecipeID: soup},
+				}})).To(Succeed())
+
+				plan, err := stores.Plans.Get(ctx, userID, "2026-10-19")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(plan.Slots).To(Equal([]models.Slot{
+					{Day: 0, Meal: models.Dinner, RecipeID: soup},
+					{Day: 1, Meal: models.Breakfast, RecipeID: soup},
+					{Day: 1, Meal: models.Dinner, RecipeID: stew},
+				}))
+			})
+
+			It("keeps the portions of each slot and the slots leftovers are of", func() {
+				slots := []models.Slot{
+					{Day: 0, Meal: models.Dinner, RecipeID: stew, Portions: 2},
+					{Day: 1, Meal: models.Lunch, RecipeID: stew, LeftoverOf: &models.SlotRef{Day: 0, Meal: models.Dinner}},
+				}
+				Expect(stores.Plans.Save(ctx, models.Plan{UserID: userID, Week: "2026-10-19", Slots: slots})).To(Succeed())
+
+				plan, err := stores.Plans.Get(ctx, userID, "2026-10-19")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(plan.Slots).To(Equal(slots))
+			})
+
+			It("keeps weeks and users apart", func() {
+				slots := []models.Slot{{Day: 0, Meal: models.Dinner, RecipeID: soup}}
+				Expect(stores.Plans.Save(ctx, models.Plan{UserID: userID, Week: "2026-10-19", Slots: slots})).To(Succeed())
+
+				plan, err := stores.Plans.Get(ctx, userID, "2026-10-26")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(plan.Slots).To(BeEmpty())
+				plan, err = stores.Plans.Get(ctx, otherID, "2026-10-19")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(plan.Slots).To(BeEmpty())
+			})
+
+			It("lists the weeks planned between two Mondays in order", func() {
+				for _, week := range []string{"2026-11-02", "2026-10-12", "2026-10-19", "2026-11-09"} {
+					Expect(stores.Plans.Save(ctx, models.Plan{UserID: userID, Week: week, Slots: []models.Slot{
+						{Day: 1, Meal: models.Dinner, RecipeID: stew},
+						{Day: 0, Meal: models.Dinner, RecipeID: soup},
+					}})).To(Succeed())
+				}
+				Expect(stores.Plans.Save(ctx, models.Plan{UserID: otherID, Week: "2026-10-26", Slots: []models.Slot{
+					{Day: 0, Meal: models.Dinner, RecipeID: soup},
+				}})).To(Succeed())
+
+				plans, err := stores.Plans.Weeks(ctx, userID, "2026-10-19", "2026-11-02")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(plans).To(HaveLen(2))
+				Expect(plans[0].Week).To(Equal("2026-10-19"))
+				Expect(plans[1].Week).To(Equal("2026-11-02"))
+				Expect(plans[1].UserID).To(Equal(userID))
+				Expect(plans[1].Slots).To(Equal([]models.Slot{
+					{Day: 0, Meal: models.Dinner, RecipeID: soup},
+					{Day: 1, Meal: models.Dinner, RecipeID: stew},
+				}))
+			})
+
+			It("rejects two slots for the same meal", func() {
+				err := stores.Plans.Save(ctx, models.Plan{UserID: userID, Week: "2026-10-19", Slots: []models.Slot{
+					{Day: 0, Meal: models.Dinner, RecipeID: soup},
+					{Day: 0, Meal: models.Dinner, RecipeID: stew},
+				}})
+				Expect(err).To(MatchError(ContainSubstring("save-plan failed")))
+			})
+
+			Describe("templates", func() {
+				It("lists the user's templates by name with their slots", func() {
+					_, err := stores.Templates.InsertTemplate(ctx, models.PlanTemplate{UserID: userID, Name: "Weekday", Slots: []models.Slot{
+						{Day: 1, Meal: models.Dinner, RecipeID: stew},
+						{Day: 0, Meal: models.Dinner, RecipeID: soup},
+					}})
+					Expect(err).NotTo(HaveOccurred())
+					empty, err := stores.Templates.InsertTemplate(ctx, models.PlanTemplate{UserID: userID, Name: "Away"})
+					Expect(err).NotTo(HaveOccurred())
+					_, err = stores.Templates.InsertTemplate(ctx, models.PlanTemplate{UserID: otherID, Name: "Theirs"})
+					Expect(err).NotTo(HaveOccurred())
+
+					templates, err := stores.Templates.ListTemplates(ctx, userID)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(templates).To(HaveLen(2))
+					Expect(templates[0].ID).To(Equal(empty.ID))
+					Expect(templates[0].Slots).To(BeEmpty())
+					Expect(templates[1].Name).To(Equal("Weekday"))
+					Expect(templates[1].Slots).To(Equal([]models.Slot{
+						{Day: 0, Meal: models.Dinner, RecipeID: soup},
+						{Day: 1, Meal: models.Dinner, RecipeID: stew},
+					}))
+				})
+
+				It("rejects a name the user already has", func() {
+					_, err := stores.Templates.InsertTemplate(ctx, models.PlanTemplate{UserID: userID, Name: "Usual"})
+					Expect(err).NotTo(HaveOccurred())
+					_, err = stores.Templates.InsertTemplate(ctx, models.PlanTemplate{UserID: otherID, Name: "Usual"})
+					Expect(err).NotTo(HaveOccurred())
+
+					_, err = stores.Templates.InsertTemplate(ctx, models.PlanTemplate{UserID: userID, Name: "Usual"})
+					Expect(stores.Templates.IsDuplicateErr(err)).To(BeTrue())
+				})
+
+				It("only finds and deletes the user's own templates", func() {
+					template, err := stores.Templates.InsertTemplate(ctx, models.PlanTemplate{UserID: userID, Name: "Usual"})
+					Expect(err).NotTo(HaveOccurred())
+
+					_, err = stores.Templates.GetTemplate(ctx, otherID, template.ID)
+					Expect(stores.Templates.IsNotFoundErr(err)).To(BeTrue())
+					err = stores.Templates.DeleteTemplate(ctx, otherID, template.ID)
+					Expect(stores.Templates.IsNotFoundErr(err)).To(BeTrue())
+
+					Expect(stores.Templates.DeleteTemplate(ctx, userID, template.ID)).To(Succeed())
+					_, err = stores.Templates.GetTemplate(ctx, userID, template.ID)
+					Expect(stores.Templates.IsNotFoundErr(err)).To(BeTrue())
+				})
+			})
+
+			Describe("rules", func() {
+				It("replaces the rule for a slot", func() {
+					first, err := stores.Templates.SaveRule(ctx, models.PlanRule{UserID: userID, Slot: models.Slot{Day: 4, Meal: models.Dinner, RecipeID: soup}})
+					Expect(err).NotTo(HaveOccurred())
+					_, err = stores.Templates.SaveRule(ctx, models.PlanRule{UserID: userID, Slot: models.Slot{Day: 0, Meal: models.Lunch, RecipeID: soup}})
+					Expect(err).NotTo(HaveOccurred())
+					second, err := stores.Templates.SaveRule(ctx, models.PlanRule{UserID: userID, Slot: models.Slot{Day: 4, Meal: models.Dinner, RecipeID: stew}})
+					Expect(err).NotTo(HaveOccurred())
+					Expect(second.ID).To(Equal(first.ID))
+
+					rules, err := stores.Templates.ListRules(ctx, userID)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(rules).To(HaveLen(2))
+					Expect(rules[0].Slot).To(Equal(models.Slot{Day: 0, Meal: models.Lunch, RecipeID: soup}))
+					Expect(rules[1].Slot).To(Equal(models.Slot{Day: 4, Meal: models.Dinner, RecipeID: stew}))
+
+					rules, err = stores.Templates.ListRules(ctx, otherID)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(rules).To(BeEmpty())
+				})
+
+				It("only deletes the user's own rules", func() {
+					rule, err := stores.Templates.SaveRule(ctx, models.PlanRule{UserID: userID, Slot: models.Slot{Day: 4, Meal: models.Dinner, RecipeID: soup}})
+					Expect(err).NotTo(HaveOccurred())
+
+					err = stores.Templates.DeleteRule(ctx, otherID, rule.ID)
+					Expect(stores.Templates.IsNotFoundErr(err)).To(BeTrue())
+					Expect(stores.Templates.DeleteRule(ctx, userID, rule.ID)).To(Succeed())
+					err = stores.Templates.DeleteRule(ctx, userID, rule.ID)
+					Expect(stores.Templates.IsNotFoundErr(err)).To(BeTrue())
+				})
+			})
+		})
+
+		Describe("calendar tokens", func() {
+			var userID int
+
+			BeforeEach(func() {
+				userID = createUser("subscriber@example.com").ID()
+			})
+
+			It("finds the user by their token's hash", func() {
+				Expect(stores.Calendar.Save(ctx, userID, "hash-1")).To(Succeed())
+
+				found, err := stores.Calendar.FindUser(ctx, "hash-1")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(Equal(userID))
+
+				_, err = stores.Calendar.FindUser(ctx, "hash-2")
+				Expect(stores.Calendar.IsNotFoundErr(err)).To(BeTrue())
+			})
+
+			It("replaces the user's previous token", func() {
+				Expect(stores.Calendar.Save(ctx, userID, "hash-1")).To(Succeed())
+				Expect(stores.Calendar.Save(ctx, userID, "hash-2")).To(Succeed())
+
+				_, err := stores.Calendar.FindUser(ctx, "hash-1")
+				Expect(stores.Calendar.IsNotFoundErr(err)).To(BeTrue())
+				found, err := stores.Calendar.FindUser(ctx, "hash-2")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(found).To(Equal(userID))
+			})
+
+			It("revokes the token", func() {
+				Expect(stores.Calendar.Save(ctx, userID, "hash-1")).To(Succeed())
+				Expect(stores.Calendar.Delete(ctx, userID)).To(Succeed())
+
+				_, err := stores.Calendar.FindUser(ctx, "hash-1")
+				Expect(stores.Calendar.IsNotFoundErr(err)).To(BeTrue())
+
+				err = stores.Calendar.Delete(ctx, userID)
+				Expect(stores.Calendar.IsNotFoundErr(err)).To(BeTrue())
+			})
+		})
+
+		Describe("sessions", func() {
+			var (
+				userID, otherID int
+				now             time.Time
+			)
+
+			BeforeEach(func() {
+				userID = createUser("sessions@example.com").ID()
+				otherID = createUser("other@example.com").ID()
+				now = time.Now().Truncate(time.Second)
+
+				for _, s := range []models.Session{
+					{ID: "conformance-a", UserID: userID, UserAgent: "firefox", IP: "10.0.0.1", CreatedAt: now.Add(-time.Hour), LastSeenAt: now.Add(-time.Minute)},
+					{ID: "conformance-b", UserID: userID, UserAgent: "chrome", IP: "10.0.0.2", CreatedAt: now.Add(-time.Hour), LastSeenAt: now.Add(-time.Hour)},
+					{ID: "conformance-c", UserID: otherID, UserAgent: "safari", IP: "10.0.0.3", CreatedAt: now, LastSeenAt: now},
+				} {
+					Expect(stores.Sessions.Create(ctx, s)).To(Succeed())
+				}
+			})
+
+			It("lists the user's sessions seen since a time, most recent first", func() {
+				sessions, err := stores.Sessions.List(ctx, userID, now.Add(-2*time.Hour))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(sessions).To(HaveLen(2))
+				Expect(sessions[0].ID).To(Equal("conformance-a"))
+				Expect(sessions[0].UserAgent).To(Equal("firefox"))
+				Expect(sessions[0].IP).To(Equal("10.0.0.1"))
+				Expect(sessions[0].CreatedAt).To(BeTemporally("==", now.Add(-time.Hour)))
+				Expect(sessions[0].LastSeenAt).To(BeTemporally("==", now.Add(-time.Minute)))
+				Expect(sessions[1].ID).To(Equal("conformance-b"))
+
+				sessions, err = stores.Sessions.List(ctx, userID, now.Add(-time.Minute))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(sessions).To(BeEmpty())
+			})
+
+			It("rejects duplicate ids", func() {
+				err := stores.Sessions.Create(ctx, models.Session{ID: "conformance-a", UserID: userID, CreatedAt: now, LastSeenAt: now})
+				Expect(err).To(MatchError(ContainSubstring("create-session failed")))
+			})
+
+			It("touches existing sessions only", func() {
+				active, err := stores.Sessions.Touch(ctx, "conformance-a", now, now.Add(-15*time.Minute), now)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(active).To(BeTrue())
+
+				sessions, err := stores.Sessions.List(ctx, userID, now.Add(-time.Second))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(sessions).To(HaveLen(1))
+				Expect(sessions[0].ID).To(Equal("conformance-a"))
+
+				active, err = stores.Sessions.Touch(ctx, "nope", now, now.Add(-15*time.Minute), now)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(active).To(BeFalse())
+			})
+
+			It("reports expired sessions without touching them", func() {
+				active, err := stores.Sessions.Touch(ctx, "conformance-b", now, now.Add(-15*time.Minute), now)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(active).To(BeFalse())
+
+				sessions, err := stores.Sessions.List(ctx, userID, now.Add(-time.Second))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(sessions).To(BeEmpty())
+			})
+
+			It("only writes activity when last seen is stale", func() {
+				active, err := stores.Sessions.Touch(ctx, "conformance-a", now, now.Add(-15*time.Minute), now.Add(-2*time.Minute))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(active).To(BeTrue())
+
+				sessions, err := stores.Sessions.List(ctx, userID, now.Add(-time.Second))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(sessions).To(BeEmpty())
+			})
+
+			It("deletes only the user's own sessions", func() {
+				Expect(stores.Sessions.Delete(ctx, userID, "conformance-a")).To(Succeed())
+				err := stores.Sessions.Delete(ctx, userID, "conformance-a")
+				Expect(stores.Sessions.IsNotFoundErr(err)).To(BeTrue())
+
+				err = stores.Sessions.Delete(ctx, userID, "conformance-c")
+				Expect(stores.Sessions.IsNotFoundErr(err)).To(BeTrue())
+			})
+
+			It("deletes idle sessions", func() {
+				Expect(stores.Sessions.DeleteIdle(ctx, userID, now.Add(-time.Hour))).To(Succeed())
+
+				sessions, err := stores.Sessions.List(ctx, userID, now.Add(-2*time.Hour))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(sessions).To(HaveLen(1))
+				Expect(sessions[0].ID).To(Equal("conformance-a"))
+			})
+
+			It("deletes all of a user's sessions", func() {
+				Expect(stores.Sessions.DeleteAll(ctx, userID)).To(Succeed())
+
+				sessions, err := stores.Sessions.List(ctx, userID, now.Add(-2*time.Hour))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(sessions).To(BeEmpty())
+
+				sessions, err = stores.Sessions.List(ctx, otherID, now.Add(-2*time.Hour))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(sessions).To(HaveLen(1))
+			})
+		})
+	})
+}
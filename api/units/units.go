@@ -0,0 +1,110 @@
+/* Package units converts ingredient quantities to a common measure */
+package units
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// grams per unit. Volumes assume the density of water, which is close
+// enough for estimates.
+var grams = map[string]float64{
+	"g":          1,
+	"gram":       1,
+	"kg":         1000,
+	"kilogram":   1000,
+	"mg":         0.001,
+	"oz":         28.35,
+	"ounce":      28.35,
+	"lb":         453.6,
+	"pound":      453.6,
+	"ml":         1,
+	"millilitre": 1,
+	"milliliter": 1,
+	"l":          1000,
+	"litre":      1000,
+	"liter":      1000,
+	"tsp":        5,
+	"teaspoon":   5,
+	"tbsp":       15,
+	"tablespoon": 15,
+	"cup":        240,
+}
+
+// volumes are the units in grams which measure volume rather than weight
+var volumes = map[string]bool{
+	"ml": true, "millilitre": true, "milliliter": true,
+	"l": true, "litre": true, "liter": true,
+	"tsp": true, "teaspoon": true,
+	"tbsp": true, "tablespoon": true,
+	"cup": true,
+}
+
+// Normalize lower-cases a unit and removes full stops and plurals, so that
+// "Tbsps." and "tbsp" are the same
+func Normalize(unit string) string {
+	u := strings.TrimSuffix(strings.ToLower(strings.TrimSpace(unit)), ".")
+	if _, ok := grams[u]; !ok && strings.HasSuffix(u, "s") {
+		if _, ok := grams[strings.TrimSuffix(u, "s")]; ok {
+			return strings.TrimSuffix(u, "s")
+		}
+	}
+	return u
+}
+
+// Grams converts a quantity to grams. Quantities without a unit are counts
+// of items weighing eachGrams, e.g. 2 onions. It reports false if the unit
+// is unknown, or there is no unit and eachGrams is zero.
+func Grams(quantity float64, unit string, eachGrams float64) (float64, bool) {
+	u := Normalize(unit)
+	if u == "" {
+		return quantity * eachGrams, eachGrams > 0
+	}
+
+	g, ok := grams[u]
+	return quantity * g, ok
+}
+
+// Base converts a weight to grams or a volume to millilitres, so that
+// quantities in different units can be added up. It reports false for
+// other units, e.g. cloves or tins, and for counts of items.
+func Base(quantity float64, unit string) (float64, string, bool) {
+	u := Normalize(unit)
+	g, ok := grams[u]
+	if !ok {
+		return quantity, u, false
+	}
+	if volumes[u] {
+		return quantity * g, "ml", true
+	}
+	return quantity * g, "g", true
+}
+
+// Readable rounds a quantity to at most two decimal places, giving grams
+// and millilitres of 1000 or more in kilograms and litres
+func Readable(quantity float64, unit string) (float64, string) {
+	u := strings.TrimSpace(unit)
+	switch Normalize(u) {
+	case "g":
+		if quantity >= 1000 {
+			quantity, u = quantity/1000, "kg"
+		}
+	case "ml":
+		if quantity >= 1000 {
+			quantity, u = quantity/1000, "l"
+		}
+	}
+
+	return math.Round(quantity*100) / 100, u
+}
+
+// Format writes a quantity for people to read, see Readable
+func Format(quantity float64, unit string) string {
+	q, u := Readable(quantity, unit)
+	n := strconv.FormatFloat(q, 'f', -1, 64)
+	if u == "" {
+		return n
+	}
+	return n + " " + u
+}
@@ -0,0 +1,88 @@
+package units_test
+
+import (
+	"github.com/kieron-pivotal/menu-planner-app/units"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Units", func() {
+	It("converts weights and volumes to grams", func() {
+		for unit, want := range map[string]float64{
+			"g":        2,
+			"kg":       2000,
+			"Tbsps.":   30,
+			"ml":       2,
+			"litres":   2000,
+			"cups":     480,
+			" Lb ":     907.2,
+			"ounces":   56.7,
+			"tsp":      10,
+			"grams":    2,
+			"TSP.":     10,
+			"kilogram": 2000,
+		} {
+			got, ok := units.Grams(2, unit, 0)
+			Expect(ok).To(BeTrue(), unit)
+			Expect(got).To(BeNumerically("~", want, 0.001), unit)
+		}
+	})
+
+	It("converts counts using the weight of each item", func() {
+		got, ok := units.Grams(3, "", 50)
+		Expect(ok).To(BeTrue())
+		Expect(got).To(Equal(150.0))
+	})
+
+	It("can't convert counts of items without a weight", func() {
+		_, ok := units.Grams(3, "", 0)
+		Expect(ok).To(BeFalse())
+	})
+
+	It("can't convert unknown units", func() {
+		_, ok := units.Grams(1, "handful", 50)
+		Expect(ok).To(BeFalse())
+	})
+
+	It("converts weights to grams and volumes to millilitres", func() {
+		for _, c := range []struct {
+			quantity float64
+			unit     string
+			want     float64
+			base     string
+		}{
+			{2, "kg", 2000, "g"},
+			{8, "oz", 226.8, "g"},
+			{2, "tbsp", 30, "ml"},
+			{1.5, "Litres", 1500, "ml"},
+		} {
+			got, base, ok := units.Base(c.quantity, c.unit)
+			Expect(ok).To(BeTrue(), c.unit)
+			Expect(got).To(BeNumerically("~", c.want, 0.001), c.unit)
+			Expect(base).To(Equal(c.base), c.unit)
+		}
+	})
+
+	It("can't convert counts or other units to a base unit", func() {
+		_, _, ok := units.Base(3, "")
+		Expect(ok).To(BeFalse())
+		_, unit, ok := units.Base(2, "Tins")
+		Expect(ok).To(BeFalse())
+		Expect(unit).To(Equal("tins"))
+	})
+
+	It("formats quantities for people to read", func() {
+		for want, got := range map[string]string{
+			"3":          units.Format(3, ""),
+			"1.5 kg":     units.Format(1500, "g"),
+			"750 g":      units.Format(750, "g"),
+			"2 l":        units.Format(2000, "ml"),
+			"0.33 cup":   units.Format(1.0/3, "cup"),
+			"2.5 tbsp":   units.Format(2.5, "tbsp"),
+			"1.25 tins":  units.Format(1.25, " tins "),
+			"1000.5 mgs": units.Format(1000.5, "mgs"),
+		} {
+			Expect(got).To(Equal(want))
+		}
+	})
+})
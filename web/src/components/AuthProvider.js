@@ -1,4 +1,6 @@
 import React, { createContext, useState, useContext, useCallback } from "react";
+import { csrfHeaders } from "../csrf";
+import { checkResponse } from "../problem";
 
 const initAuth = {
     isAuthed: false,
@@ -22,31 +24,30 @@ export default function AuthProvider({ children }) {
     }, []);
 
     const logout = useCallback(() => {
-        fetch(process.env.REACT_APP_API_URI + "/logout", {
-            credentials: "include",
-            method: "POST",
-        })
-            .then((resp) => {
-                if (!resp.ok) throw new Error(resp.statusText);
-                return resp;
-            })
+        csrfHeaders()
+            .then((headers) =>
+                fetch(process.env.REACT_APP_API_URI + "/logout", {
+                    credentials: "include",
+                    method: "POST",
+                    headers,
+                })
+            )
+            .then(checkResponse)
             .then(setUnauthenticated)
             .catch(console.error);
     }, [setUnauthenticated]);
 
     const authGoogle = (token) => {
-        fetch(process.env.REACT_APP_API_URI + "/authGoogle", {
-            credentials: "include",
-            method: "POST",
-            body: JSON.stringify({ idToken: token }),
-            headers: {
-                "Content-Type": "application/json",
-            },
-        })
-            .then((resp) => {
-                if (!resp.ok) throw new Error(resp.statusText);
-                return resp;
-            })
+        csrfHeaders({ "Content-Type": "application/json" })
+            .then((headers) =>
+                fetch(process.env.REACT_APP_API_URI + "/authGoogle", {
+                    credentials: "include",
+                    method: "POST",
+                    body: JSON.stringify({ idToken: token }),
+                    headers,
+                })
+            )
+            .then(checkResponse)
             .then((data) => data.json())
             .then((data) => {
                 setAuthenticated(data.name);
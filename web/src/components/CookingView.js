@@ -0,0 +1,199 @@
+import React, { useState, useEffect } from "react";
+import {
+    Button,
+    Header,
+    Input,
+    List,
+    Message,
+    Rating,
+    Segment,
+} from "semantic-ui-react";
+import { csrfHeaders } from "../csrf";
+import { checkResponse } from "../problem";
+
+const formatTime = (seconds) => {
+    const m = Math.floor(seconds / 60);
+    const s = seconds % 60;
+    return `${m}:${s < 10 ? "0" : ""}${s}`;
+};
+
+function Timer({ seconds }) {
+    const [remaining, setRemaining] = useState(seconds);
+    const [running, setRunning] = useState(false);
+
+    useEffect(() => {
+        setRemaining(seconds);
+        setRunning(false);
+    }, [seconds]);
+
+    useEffect(() => {
+        if (!running || remaining <= 0) return;
+        const tick = setTimeout(() => setRemaining(remaining - 1), 1000);
+        return () => clearTimeout(tick);
+    }, [running, remaining]);
+
+    if (remaining <= 0) {
+        return <Message positive>Time's up!</Message>;
+    }
+
+    return (
+        <Button
+            icon={running ? "pause" : "clock"}
+            content={formatTime(remaining)}
+            onClick={() => setRunning(!running)}
+        />
+    );
+}
+
+function CookedIt({ recipeID, lastCookedAt }) {
+    const [rating, setRating] = useState(0);
+    const [leftovers, setLeftovers] = useState(0);
+    const [cookedAt, setCookedAt] = useState(lastCookedAt);
+
+    const recordCooked = () => {
+        csrfHeaders({ "Content-Type": "application/json" })
+            .then((headers) =>
+                fetch(
+                    process.env.REACT_APP_API_URI +
+                        "/recipes/" +
+                        recipeID +
+                        "/cooked",
+                    {
+                        credentials: "include",
+                        method: "POST",
+                        body: JSON.stringify({ rating, leftovers }),
+                        headers,
+                    }
+                )
+            )
+            .then(checkResponse)
+            .then((r) => r.json())
+            .then((c) => setCookedAt(c.cookedAt))
+            .catch(console.error);
+    };
+
+    return (
+        <Segment>
+            {cookedAt && (
+                <p>Last cooked {new Date(cookedAt).toLocaleDateString()}</p>
+            )}
+            <Rating
+                icon="star"
+                maxRating={5}
+                rating={rating}
+                onRate={(e, { rating }) => setRating(rating)}
+            />{" "}
+            <Input
+                type="number"
+                min="0"
+                label="portions left over"
+                value={leftovers}
+                onChange={(e, { value }) =>
+                    setLeftovers(Math.max(0, parseInt(value, 10) || 0))
+                }
+            />{" "}
+            <Button content="Cooked it" onClick={recordCooked} />
+        </Segment>
+    );
+}
+
+export default function CookingView({ recipeID }) {
+    const [recipe, setRecipe] = useState(null);
+    const [current, setCurrent] = useState(0);
+
+    useEffect(() => {
+        fetch(process.env.REACT_APP_API_URI + "/recipes/" + recipeID, {
+            credentials: "include",
+            method: "GET",
+        })
+            .then(checkResponse)
+            .then((r) => r.json())
+            .then(setRecipe)
+            .catch(console.error);
+    }, [recipeID]);
+
+    if (!recipe) return null;
+
+    const ingredients = recipe.ingredients || [];
+    const steps = recipe.steps || [];
+    const step = steps[current];
+
+    const describe = (i) =>
+        [i.quantity, i.unit, i.name].filter((part) => part).join(" ");
+
+    return (
+        <>
+            <Header>{recipe.name}</Header>
+            <a
+                href={
+                    process.env.REACT_APP_API_URI +
+                    "/recipes/" +
+                    recipeID +
+                    "/card.pdf"
+                }
+                target="_blank"
+                rel="noopener noreferrer"
+            >
+                Print recipe card
+            </a>
+            {recipe.nutrition && (
+                <p>
+                    Per serving: {recipe.nutrition.perServing.calories} kcal,{" "}
+                    {recipe.nutrition.perServing.protein} g protein,{" "}
+                    {recipe.nutrition.perServing.fat} g fat,{" "}
+                    {recipe.nutrition.perServing.carbohydrate} g carbohydrate
+                    {recipe.nutrition.missing &&
+                        ` (not counting ${recipe.nutrition.missing.join(", ")})`}
+                </p>
+            )}
+            {recipe.cost && recipe.cost.total > 0 && (
+                <p>
+                    Costs about {(recipe.cost.perServing / 100).toFixed(2)} per
+                    serving
+                    {recipe.cost.missing &&
+                        ` (not counting ${recipe.cost.missing.join(", ")})`}
+                </p>
+            )}
+            <List bulleted>
+                {ingredients.map((i, n) => (
+                    <List.Item key={n}>{describe(i)}</List.Item>
+                ))}
+            </List>
+
+            {step ? (
+                <Segment>
+                    <Header size="small">
+                        Step {current + 1} of {steps.length}
+                    </Header>
+                    <p>{step.instruction}</p>
+                    {(step.ingredients || []).length > 0 && (
+                        <p>
+                            Uses:{" "}
+                            {step.ingredients
+                                .map((n) => ingredients[n].name)
+                                .join(", ")}
+                        </p>
+                    )}
+                    {step.durationSeconds > 0 && (
+                        <Timer seconds={step.durationSeconds} />
+                    )}
+                    <Button.Group floated="right">
+                        <Button
+                            icon="left arrow"
+                            disabled={current === 0}
+                            onClick={() => setCurrent(current - 1)}
+                        />
+                        <Button
+                            icon="right arrow"
+                            disabled={current === steps.length - 1}
+                            onClick={() => setCurrent(current + 1)}
+                        />
+                    </Button.Group>
+                </Segment>
+            ) : (
+                <Message>This recipe has no method yet.</Message>
+            )}
+            <CookedIt recipeID={recipeID} lastCookedAt={recipe.lastCookedAt} />
+        </>
+    );
+}
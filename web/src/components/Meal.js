@@ -1,6 +1,7 @@
 import React from "react";
-import { Card, Button } from "semantic-ui-react";
+import { Card, Button, Modal } from "semantic-ui-react";
 import { useRecipes } from "./RecipeProvider";
+import CookingView from "./CookingView";
 
 export default function Meal({ id, recipeIDs, removeMeal }) {
     const { recipes } = useRecipes();
@@ -19,7 +20,18 @@ export default function Meal({ id, recipeIDs, removeMeal }) {
                 />
                 <Card.Header>{mainRecipe.text}</Card.Header>
                 {recipeIDs.map((id) => (
-                    <Card.Description>{recipes.get(id).text}</Card.Description>
+                    <Modal
+                        trigger={
+                            <Card.Description as="a">
+                                {recipes.get(id).text}
+                            </Card.Description>
+                        }
+                        closeIcon
+                    >
+                        <Modal.Content>
+                            <CookingView recipeID={id} />
+                        </Modal.Content>
+                    </Modal>
                 ))}
             </Card.Content>
         </Card>
@@ -14,9 +14,13 @@ export default function Meals() {
     };
 
     const handleAddition = (_, { value }) => {
-        addRecipe(value).then((r) =>
-            setMealRecipes(mealRecipes.concat(r.value))
-        );
+        addRecipe(value)
+            .then((r) =>
+                setMealRecipes(
+                    mealRecipes.filter((id) => id !== r.value).concat(r.value)
+                )
+            )
+            .catch(console.error);
     };
 
     const addMeal = (e) => {
@@ -31,6 +35,8 @@ export default function Meals() {
         setMeals(newMeals);
     };
 
+    const planIDs = meals.flatMap((m) => m.recipes).join(",");
+
     return (
         <>
             <Form onSubmit={addMeal} style={{ marginBottom: "5ex" }}>
@@ -52,6 +58,22 @@ export default function Meals() {
                 </Form.Group>
             </Form>
 
+            {meals.length > 0 && (
+                <p>
+                    <a
+                        href={
+                            process.env.REACT_APP_API_URI +
+                            "/plan.pdf?recipes=" +
+                            planIDs
+                        }
+                        target="_blank"
+                        rel="noopener noreferrer"
+                    >
+                        Print plan
+                    </a>
+                </p>
+            )}
+
             <Card.Group>
                 {meals.map((m) => (
                     <Meal
@@ -1,65 +1,116 @@
 import React, { createContext, useContext, useState, useEffect } from "react";
+import { csrfHeaders } from "../csrf";
+import { checkResponse } from "../problem";
 
 const RecipeContext = createContext();
 export const useRecipes = () => useContext(RecipeContext);
 
+const toOption = (r) => ({
+    key: r.name,
+    text: r.name,
+    value: r.id,
+});
+
+// fetchRecipes resolves with the user's recipes as dropdown options, by id
+const fetchRecipes = () =>
+    fetch(process.env.REACT_APP_API_URI + "/recipes", {
+        credentials: "include",
+        method: "GET",
+    })
+        .then(checkResponse)
+        .then((r) => r.json())
+        .then((json) => {
+            const recipeMap = new Map();
+            json.forEach((r) => {
+                recipeMap.set(r.id, toOption(r));
+            });
+            return recipeMap;
+        });
+
+// recipeListEvents are the server-sent events after which the list is
+// fetched again: the user's other clients adding or merging away recipes,
+// or events having been missed
+const recipeListEvents = ["recipe-created", "recipe-deleted", "resync"];
+
 export default function RecipeProvider({ children }) {
     const [recipes, setRecipes] = useState([]);
 
     useEffect(() => {
-        fetch(process.env.REACT_APP_API_URI + "/recipes", {
-            credentials: "include",
-            method: "GET",
-        })
-            .then((resp) => {
-                if (!resp.ok) throw new Error(resp.statusText);
-                return resp;
-            })
-            .then((r) => r.json())
-            .then((json) => {
-                const recipeMap = new Map();
-                json.forEach((r) => {
-                    recipeMap.set(r.id, {
-                        key: r.name,
-                        text: r.name,
-                        value: r.id,
-                    });
-                });
-                setRecipes(recipeMap);
-            })
-            .catch(console.error);
+        const load = () => fetchRecipes().then(setRecipes).catch(console.error);
+        load();
+
+        if (!window.EventSource) {
+            return undefined;
+        }
+
+        const events = new EventSource(
+            process.env.REACT_APP_API_URI + "/events",
+            { withCredentials: true }
+        );
+        recipeListEvents.forEach((type) => events.addEventListener(type, load));
+
+        // the stream reconnecting means events may have been missed
+        let connected = false;
+        events.onopen = () => {
+            if (connected) {
+                load();
+            }
+            connected = true;
+        };
+
+        return () => events.close();
     }, []);
 
-    const addRecipe = (name) => {
-        return new Promise((resolve, reject) => {
-            fetch(process.env.REACT_APP_API_URI + "/recipes", {
-                credentials: "include",
-                method: "POST",
-                body: JSON.stringify({ name }),
-            })
-                .then((resp) => {
-                    if (!resp.ok) {
-                        reject(resp.statusText);
-                        throw new Error(resp.statusText);
+    const postRecipe = (name, allowSimilar) =>
+        csrfHeaders()
+            .then((headers) =>
+                fetch(
+                    process.env.REACT_APP_API_URI +
+                        "/recipes" +
+                        (allowSimilar ? "?allowSimilar=true" : ""),
+                    {
+                        credentials: "include",
+                        method: "POST",
+                        body: JSON.stringify({ name }),
+                        headers,
                     }
+                )
+            )
+            .then(checkResponse)
+            .then((r) => r.json());
 
-                    return resp;
-                })
-                .then((r) => r.json())
-                .then((r) => {
-                    const newRecipes = new Map(recipes);
-                    const newRecipe = {
-                        key: r.name,
-                        text: r.name,
-                        value: r.id,
-                    };
-                    newRecipes.set(newRecipe.value, newRecipe);
-                    setRecipes(newRecipes);
+    // addRecipe saves a new recipe and resolves with its dropdown option.
+    // If the user already has it, or one like it they choose instead, that
+    // one is resolved with.
+    const addRecipe = (name) =>
+        postRecipe(name, false)
+            .catch((err) => {
+                const existing = (err.conflicts || [])[0];
+                if (err.code === "duplicate-recipe" && existing) {
+                    return existing;
+                }
+                if (err.code !== "similar-recipes" || !existing) {
+                    throw err;
+                }
 
-                    resolve(newRecipe);
-                });
-        });
-    };
+                const names = err.conflicts.map((c) => c.name).join(", ");
+                if (
+                    window.confirm(
+                        `You already have ${names}. Add "${name}" anyway?`
+                    )
+                ) {
+                    return postRecipe(name, true);
+                }
+                return existing;
+            })
+            .then((r) => {
+                const newRecipe = toOption(r);
+                const newRecipes = new Map(recipes);
+                newRecipes.set(newRecipe.value, newRecipe);
+                setRecipes(newRecipes);
+
+                return newRecipe;
+            });
 
     return (
         <RecipeContext.Provider value={{ recipes, addRecipe }}>
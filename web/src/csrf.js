@@ -0,0 +1,28 @@
+let token;
+
+// resetCSRFToken forgets the token, e.g. after the API rejects it
+export const resetCSRFToken = () => {
+    token = undefined;
+};
+
+// csrfHeaders fetches the API's CSRF token once and returns the given headers
+// with it added, for use on any state-changing request.
+export const csrfHeaders = (headers = {}) => {
+    const tokenPromise = token
+        ? Promise.resolve(token)
+        : fetch(process.env.REACT_APP_API_URI + "/csrf", {
+              credentials: "include",
+              method: "GET",
+          })
+              .then((resp) => {
+                  if (!resp.ok) throw new Error(resp.statusText);
+                  return resp;
+              })
+              .then((r) => r.json())
+              .then((data) => {
+                  token = data.token;
+                  return token;
+              });
+
+    return tokenPromise.then((t) => ({ ...headers, "X-CSRF-Token": t }));
+};
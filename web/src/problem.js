@@ -0,0 +1,30 @@
+import { resetCSRFToken } from "./csrf";
+
+// ProblemError is a failed API response. code is the API's error code,
+// e.g. "unauthorized", or undefined if the response had no problem details.
+// conflicts lists what a 409 clashed with, as {id, name}.
+export class ProblemError extends Error {
+    constructor(status, problem = {}) {
+        super(problem.detail || problem.title || `request failed (${status})`);
+        this.status = status;
+        this.code = problem.code;
+        this.conflicts = problem.conflicts || [];
+    }
+}
+
+// checkResponse passes on a successful response and rejects a failed one
+// with a ProblemError. A rejected CSRF token is forgotten, so the next
+// request fetches a new one.
+export const checkResponse = (resp) => {
+    if (resp.ok) return resp;
+
+    const type = resp.headers.get("Content-Type") || "";
+    const problem = type.startsWith("application/problem+json")
+        ? resp.json().catch(() => ({}))
+        : Promise.resolve({});
+
+    return problem.then((p) => {
+        if (p.code === "invalid-csrf-token") resetCSRFToken();
+        throw new ProblemError(resp.status, p);
+    });
+};